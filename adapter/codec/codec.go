@@ -35,6 +35,8 @@ type Envelope struct {
 
 // MessageData represents the serialized form of a Message.
 type MessageData struct {
+	ID        string                 `json:"id,omitempty"`
+	ParentID  string                 `json:"parent_id,omitempty"`
 	Role      string                 `json:"role"`
 	Content   string                 `json:"content"`
 	Metadata  map[string]interface{} `json:"metadata"`
@@ -52,6 +54,8 @@ type ToolResultData struct {
 // EncodeMessage converts a Message to its serializable form.
 func EncodeMessage(msg *agenkit.Message) MessageData {
 	return MessageData{
+		ID:        msg.ID,
+		ParentID:  msg.ParentID,
 		Role:      msg.Role,
 		Content:   msg.ContentString(),
 		Metadata:  msg.Metadata,
@@ -67,6 +71,8 @@ func DecodeMessage(data MessageData) (*agenkit.Message, error) {
 	}
 
 	return &agenkit.Message{
+		ID:        data.ID,
+		ParentID:  data.ParentID,
 		Role:      data.Role,
 		Content:   data.Content,
 		Metadata:  data.Metadata,
@@ -44,6 +44,9 @@ func TestEncodeDecodeMessage(t *testing.T) {
 	if decoded.Metadata["key1"] != msg.Metadata["key1"] {
 		t.Errorf("Metadata mismatch")
 	}
+	if decoded.ID != msg.ID {
+		t.Errorf("Expected ID '%s', got '%s'", msg.ID, decoded.ID)
+	}
 }
 
 func TestEncodeDecodeToolResult(t *testing.T) {
@@ -140,6 +140,10 @@ type anthropicResponse struct {
 type anthropicContentBlock struct {
 	Type string `json:"type"`
 	Text string `json:"text"`
+	// ID, Name and Input are set on tool_use blocks
+	ID    string                 `json:"id,omitempty"`
+	Name  string                 `json:"name,omitempty"`
+	Input map[string]interface{} `json:"input,omitempty"`
 }
 
 // anthropicUsage contains token usage information.
@@ -245,7 +249,7 @@ func (a *AnthropicLLM) Complete(ctx context.Context, messages []*agenkit.Message
 	}
 
 	// Convert to Agenkit Message
-	response := agenkit.NewMessage("agent", content)
+	response := agenkit.NewMessage(agenkit.RoleAssistant, content)
 	response.Metadata["model"] = a.model
 	response.Metadata["usage"] = map[string]interface{}{
 		"input_tokens":  anthropicResp.Usage.InputTokens,
@@ -254,6 +258,17 @@ func (a *AnthropicLLM) Complete(ctx context.Context, messages []*agenkit.Message
 	response.Metadata["stop_reason"] = anthropicResp.StopReason
 	response.Metadata["id"] = anthropicResp.ID
 
+	// Pass tool_use blocks through as structured tool calls
+	for _, block := range anthropicResp.Content {
+		if block.Type == "tool_use" {
+			arguments := block.Input
+			if arguments == nil {
+				arguments = map[string]interface{}{}
+			}
+			response.WithToolCalls(agenkit.ToolCall{ID: block.ID, Name: block.Name, Arguments: arguments})
+		}
+	}
+
 	// Store content_blocks for multimodal consumers when multiple blocks present
 	if len(anthropicResp.Content) > 1 {
 		blocks := make([]interface{}, len(anthropicResp.Content))
@@ -353,7 +368,7 @@ func (a *AnthropicLLM) Stream(ctx context.Context, messages []*agenkit.Message,
 
 			// Handle content_block_delta events
 			if event.Type == "content_block_delta" && event.Delta != nil && event.Delta.Text != "" {
-				chunk := agenkit.NewMessage("agent", event.Delta.Text)
+				chunk := agenkit.NewMessage(agenkit.RoleAssistant, event.Delta.Text)
 				chunk.Metadata["streaming"] = true
 				chunk.Metadata["model"] = a.model
 				messageChan <- chunk
@@ -362,7 +377,7 @@ func (a *AnthropicLLM) Stream(ctx context.Context, messages []*agenkit.Message,
 
 		if err := scanner.Err(); err != nil {
 			// Send error message
-			errorMsg := agenkit.NewMessage("agent", "")
+			errorMsg := agenkit.NewMessage(agenkit.RoleAssistant, "")
 			errorMsg.Metadata["error"] = err.Error()
 			errorMsg.Metadata["streaming"] = true
 			messageChan <- errorMsg
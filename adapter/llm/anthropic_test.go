@@ -0,0 +1,45 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestAnthropicLLM_ToolUseBecomesToolCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "msg_1",
+			"type": "message",
+			"role": "assistant",
+			"content": [
+				{"type": "text", "text": "Checking the weather."},
+				{"type": "tool_use", "id": "toolu_1", "name": "weather", "input": {"city": "Paris"}}
+			],
+			"stop_reason": "tool_use",
+			"usage": {"input_tokens": 10, "output_tokens": 5}
+		}`))
+	}))
+	defer server.Close()
+
+	llm := NewAnthropicLLM("key", "", WithBaseURL(server.URL))
+	response, err := llm.Complete(context.Background(), []*agenkit.Message{agenkit.NewMessage("user", "Weather in Paris?")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if response.ContentString() != "Checking the weather." {
+		t.Errorf("unexpected content: %q", response.ContentString())
+	}
+	if len(response.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(response.ToolCalls))
+	}
+	call := response.ToolCalls[0]
+	if call.ID != "toolu_1" || call.Name != "weather" || call.Arguments["city"] != "Paris" {
+		t.Errorf("unexpected tool call: %+v", call)
+	}
+}
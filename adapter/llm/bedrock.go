@@ -244,7 +244,7 @@ func (b *BedrockLLM) Complete(ctx context.Context, messages []*agenkit.Message,
 	}
 
 	// Build response message
-	response := agenkit.NewMessage("agent", content)
+	response := agenkit.NewMessage(agenkit.RoleAssistant, content)
 	response.Metadata["model"] = b.modelID
 
 	// Add usage if available
@@ -361,7 +361,7 @@ func (b *BedrockLLM) Stream(ctx context.Context, messages []*agenkit.Message, op
 				// Extract text from content block delta
 				if e.Value.Delta != nil {
 					if textDelta, ok := e.Value.Delta.(*types.ContentBlockDeltaMemberText); ok {
-						chunk := agenkit.NewMessage("agent", textDelta.Value)
+						chunk := agenkit.NewMessage(agenkit.RoleAssistant, textDelta.Value)
 						chunk.Metadata["streaming"] = true
 						chunk.Metadata["model"] = b.modelID
 						messageChan <- chunk
@@ -372,7 +372,7 @@ func (b *BedrockLLM) Stream(ctx context.Context, messages []*agenkit.Message, op
 
 		// Check for stream errors
 		if err := stream.Err(); err != nil {
-			errorMsg := agenkit.NewMessage("agent", "")
+			errorMsg := agenkit.NewMessage(agenkit.RoleAssistant, "")
 			errorMsg.Metadata["error"] = err.Error()
 			errorMsg.Metadata["streaming"] = true
 			messageChan <- errorMsg
@@ -150,7 +150,7 @@ func (g *GeminiLLM) Complete(ctx context.Context, messages []*agenkit.Message, o
 	content := g.extractContent(resp)
 
 	// Build response message
-	response := agenkit.NewMessage("agent", content)
+	response := agenkit.NewMessage(agenkit.RoleAssistant, content)
 	response.Metadata["model"] = g.model
 
 	// Add usage metadata if available
@@ -227,7 +227,7 @@ func (g *GeminiLLM) Stream(ctx context.Context, messages []*agenkit.Message, opt
 			}
 			if err != nil {
 				// On error, send an error message and return
-				errorMsg := agenkit.NewMessage("agent", "")
+				errorMsg := agenkit.NewMessage(agenkit.RoleAssistant, "")
 				errorMsg.Metadata["error"] = err.Error()
 				errorMsg.Metadata["streaming"] = true
 				messageChan <- errorMsg
@@ -237,7 +237,7 @@ func (g *GeminiLLM) Stream(ctx context.Context, messages []*agenkit.Message, opt
 			// Extract content from chunk
 			content := g.extractContent(resp)
 			if content != "" {
-				chunk := agenkit.NewMessage("agent", content)
+				chunk := agenkit.NewMessage(agenkit.RoleAssistant, content)
 				chunk.Metadata["streaming"] = true
 				chunk.Metadata["model"] = g.model
 				messageChan <- chunk
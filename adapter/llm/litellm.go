@@ -253,7 +253,7 @@ func (l *LiteLLMLLM) Complete(ctx context.Context, messages []*agenkit.Message,
 	}
 
 	// Convert to Agenkit Message
-	response := agenkit.NewMessage("agent", litellmResp.Choices[0].Message.Content)
+	response := agenkit.NewMessage(agenkit.RoleAssistant, litellmResp.Choices[0].Message.Content)
 	response.Metadata["model"] = litellmResp.Model
 	response.Metadata["usage"] = map[string]interface{}{
 		"prompt_tokens":     litellmResp.Usage.PromptTokens,
@@ -353,7 +353,7 @@ func (l *LiteLLMLLM) Stream(ctx context.Context, messages []*agenkit.Message, op
 
 			// Extract content from delta
 			if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
-				msg := agenkit.NewMessage("agent", chunk.Choices[0].Delta.Content)
+				msg := agenkit.NewMessage(agenkit.RoleAssistant, chunk.Choices[0].Delta.Content)
 				msg.Metadata["streaming"] = true
 				msg.Metadata["model"] = l.model
 				messageChan <- msg
@@ -362,7 +362,7 @@ func (l *LiteLLMLLM) Stream(ctx context.Context, messages []*agenkit.Message, op
 
 		if err := scanner.Err(); err != nil {
 			// Send error message
-			errorMsg := agenkit.NewMessage("agent", "")
+			errorMsg := agenkit.NewMessage(agenkit.RoleAssistant, "")
 			errorMsg.Metadata["error"] = err.Error()
 			errorMsg.Metadata["streaming"] = true
 			messageChan <- errorMsg
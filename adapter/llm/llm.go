@@ -70,7 +70,7 @@ type LLM interface {
 	//
 	// Returns:
 	//   - Response from the LLM as an Agenkit Message with:
-	//     * Role: "agent"
+	//     * Role: "assistant"
 	//     * Content: The generated text
 	//     * Metadata: Provider-specific data (usage stats, model name, etc.)
 	//
@@ -103,7 +103,7 @@ type LLM interface {
 	//
 	// Returns:
 	//   - Channel of Message chunks as they arrive from the LLM. Each chunk contains:
-	//     * Role: "agent"
+	//     * Role: "assistant"
 	//     * Content: Partial text (may be a single token or character)
 	//     * Metadata: {"streaming": true, ...}
 	//   - The channel will be closed when streaming completes or on error
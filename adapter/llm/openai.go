@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -156,10 +157,11 @@ func (o *OpenAILLM) Complete(ctx context.Context, messages []*agenkit.Message, o
 
 	// Convert response to Agenkit Message.
 	// Content field holds the text for backward compatibility.
-	// When tool_calls are present (multi-block response), they are stored in
-	// Metadata["content_blocks"] for consumers that need the full structured response.
+	// When tool_calls are present (multi-block response), they are set as the
+	// message's ToolCalls and also stored in Metadata["content_blocks"] for
+	// consumers that need the full structured response.
 	msg := resp.Choices[0].Message
-	response := agenkit.NewMessage("agent", msg.Content)
+	response := agenkit.NewMessage(agenkit.RoleAssistant, msg.Content)
 	response.Metadata["model"] = resp.Model
 	response.Metadata["usage"] = map[string]interface{}{
 		"prompt_tokens":     resp.Usage.PromptTokens,
@@ -185,6 +187,13 @@ func (o *OpenAILLM) Complete(ctx context.Context, messages []*agenkit.Message, o
 				"name":  tc.Function.Name,
 				"input": tc.Function.Arguments,
 			})
+
+			// Arguments arrive as a JSON string; malformed ones become empty
+			var arguments map[string]interface{}
+			if err := json.Unmarshal([]byte(tc.Function.Arguments), &arguments); err != nil || arguments == nil {
+				arguments = map[string]interface{}{}
+			}
+			response.WithToolCalls(agenkit.ToolCall{ID: tc.ID, Name: tc.Function.Name, Arguments: arguments})
 		}
 		response.Metadata["content_blocks"] = blocks
 	}
@@ -264,7 +273,7 @@ func (o *OpenAILLM) Stream(ctx context.Context, messages []*agenkit.Message, opt
 			}
 			if err != nil {
 				// On error, send an error message and return
-				errorMsg := agenkit.NewMessage("agent", "")
+				errorMsg := agenkit.NewMessage(agenkit.RoleAssistant, "")
 				errorMsg.Metadata["error"] = err.Error()
 				errorMsg.Metadata["streaming"] = true
 				messageChan <- errorMsg
@@ -275,7 +284,7 @@ func (o *OpenAILLM) Stream(ctx context.Context, messages []*agenkit.Message, opt
 			if len(response.Choices) > 0 {
 				delta := response.Choices[0].Delta
 				if delta.Content != "" {
-					chunk := agenkit.NewMessage("agent", delta.Content)
+					chunk := agenkit.NewMessage(agenkit.RoleAssistant, delta.Content)
 					chunk.Metadata["streaming"] = true
 					chunk.Metadata["model"] = o.model
 					messageChan <- chunk
@@ -213,7 +213,7 @@ func (o *OpenAICompatibleLLM) Complete(ctx context.Context, messages []*agenkit.
 	}
 
 	// Convert response to Agenkit Message with provider metadata
-	response := agenkit.NewMessage("agent", resp.Choices[0].Message.Content)
+	response := agenkit.NewMessage(agenkit.RoleAssistant, resp.Choices[0].Message.Content)
 	response.Metadata["model"] = resp.Model
 	response.Metadata["usage"] = map[string]interface{}{
 		"prompt_tokens":     resp.Usage.PromptTokens,
@@ -321,7 +321,7 @@ func (o *OpenAICompatibleLLM) Stream(ctx context.Context, messages []*agenkit.Me
 			}
 			if err != nil {
 				// On error, send an error message and return
-				errorMsg := agenkit.NewMessage("agent", "")
+				errorMsg := agenkit.NewMessage(agenkit.RoleAssistant, "")
 				errorMsg.Metadata["error"] = err.Error()
 				errorMsg.Metadata["streaming"] = true
 				messageChan <- errorMsg
@@ -332,7 +332,7 @@ func (o *OpenAICompatibleLLM) Stream(ctx context.Context, messages []*agenkit.Me
 			if len(response.Choices) > 0 {
 				delta := response.Choices[0].Delta
 				if delta.Content != "" {
-					chunk := agenkit.NewMessage("agent", delta.Content)
+					chunk := agenkit.NewMessage(agenkit.RoleAssistant, delta.Content)
 					chunk.Metadata["streaming"] = true
 					chunk.Metadata["model"] = o.model
 					if o.provider != "" {
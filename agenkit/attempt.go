@@ -0,0 +1,51 @@
+package agenkit
+
+import (
+	"context"
+	"time"
+)
+
+// attemptKey is the private context key for the current attempt number.
+type attemptKey struct{}
+
+// retryObserverKey is the private context key for a RetryObserver.
+type retryObserverKey struct{}
+
+// RetryObserver is notified of each failed attempt that a retry layer is
+// about to retry. attempt is 1-based and latency is how long the attempt
+// took. Session recorders use it to capture attempts that the final
+// response would otherwise hide.
+type RetryObserver func(attempt int, err error, latency time.Duration)
+
+// WithAttempt returns a copy of ctx marking the call as the given 1-based
+// attempt. Retry layers set it on the context passed to each attempt.
+func WithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// AttemptFromContext returns the attempt number set by the nearest retry
+// layer. Returns (attempt, true) if one is present, (0, false) otherwise.
+func AttemptFromContext(ctx context.Context) (int, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	attempt, ok := ctx.Value(attemptKey{}).(int)
+	return attempt, ok
+}
+
+// WithRetryObserver returns a copy of ctx carrying observer, which retry
+// layers further down the call tree notify through NotifyRetry.
+func WithRetryObserver(ctx context.Context, observer RetryObserver) context.Context {
+	return context.WithValue(ctx, retryObserverKey{}, observer)
+}
+
+// NotifyRetry reports a failed attempt that is about to be retried to the
+// RetryObserver in ctx, if any.
+func NotifyRetry(ctx context.Context, attempt int, err error, latency time.Duration) {
+	if ctx == nil {
+		return
+	}
+	if observer, ok := ctx.Value(retryObserverKey{}).(RetryObserver); ok && observer != nil {
+		observer(attempt, err, latency)
+	}
+}
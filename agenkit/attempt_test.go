@@ -0,0 +1,33 @@
+package agenkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAttemptFromContext(t *testing.T) {
+	if _, ok := AttemptFromContext(context.Background()); ok {
+		t.Error("expected no attempt on a bare context")
+	}
+	attempt, ok := AttemptFromContext(WithAttempt(context.Background(), 2))
+	if !ok || attempt != 2 {
+		t.Errorf("expected attempt 2, got %d (%v)", attempt, ok)
+	}
+}
+
+func TestNotifyRetry(t *testing.T) {
+	// No observer is a no-op
+	NotifyRetry(context.Background(), 1, errors.New("x"), time.Millisecond)
+
+	var got []int
+	ctx := WithRetryObserver(context.Background(), func(attempt int, err error, latency time.Duration) {
+		got = append(got, attempt)
+	})
+	NotifyRetry(ctx, 1, errors.New("x"), time.Millisecond)
+	NotifyRetry(ctx, 2, errors.New("y"), time.Millisecond)
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("expected attempts [1 2], got %v", got)
+	}
+}
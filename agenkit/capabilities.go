@@ -0,0 +1,43 @@
+package agenkit
+
+// CapabilityScorer is implemented by agents that advertise how proficient
+// they are at each capability, not just whether they have it.
+//
+// Scores are relative weights, conventionally in (0, 1] with 1.0 meaning
+// expert. A capability with a score of 0 or less is treated as absent.
+// Routers use the scores to prefer the strongest specialist when several
+// agents list the same capability.
+//
+// Example:
+//
+//	func (a *ReviewAgent) CapabilityScores() map[string]float64 {
+//	    return map[string]float64{"code-review": 1.0, "coding": 0.6}
+//	}
+type CapabilityScorer interface {
+	CapabilityScores() map[string]float64
+}
+
+// CapabilityScores returns agent's proficiency per capability.
+//
+// Agents implementing CapabilityScorer report their own scores; for all
+// others every capability listed by Capabilities scores 1.0. The returned
+// map is a copy and may be modified by the caller.
+func CapabilityScores(agent Agent) map[string]float64 {
+	if scorer, ok := agent.(CapabilityScorer); ok {
+		scores := make(map[string]float64)
+		for capability, score := range scorer.CapabilityScores() {
+			scores[capability] = score
+		}
+		return scores
+	}
+	return UniformCapabilityScores(agent.Capabilities())
+}
+
+// UniformCapabilityScores scores every capability in capabilities as 1.0.
+func UniformCapabilityScores(capabilities []string) map[string]float64 {
+	scores := make(map[string]float64, len(capabilities))
+	for _, capability := range capabilities {
+		scores[capability] = 1.0
+	}
+	return scores
+}
@@ -0,0 +1,52 @@
+package agenkit
+
+import (
+	"context"
+	"testing"
+)
+
+// scoredAgent advertises proficiency scores.
+type scoredAgent struct {
+	scores map[string]float64
+}
+
+func (a *scoredAgent) Name() string           { return "scored" }
+func (a *scoredAgent) Capabilities() []string { return []string{"ignored"} }
+func (a *scoredAgent) Introspect() *IntrospectionResult {
+	return DefaultIntrospectionResult(a)
+}
+func (a *scoredAgent) Process(ctx context.Context, m *Message) (*Message, error) {
+	return m, nil
+}
+func (a *scoredAgent) CapabilityScores() map[string]float64 { return a.scores }
+
+// listingAgent lists capabilities without scores.
+type listingAgent struct{ plainAgent }
+
+func (a *listingAgent) Capabilities() []string { return []string{"coding", "sql"} }
+
+func TestCapabilityScores_UsesScorer(t *testing.T) {
+	agent := &scoredAgent{scores: map[string]float64{"coding": 0.9, "sql": 0.3}}
+
+	scores := CapabilityScores(agent)
+	if len(scores) != 2 || scores["coding"] != 0.9 || scores["sql"] != 0.3 {
+		t.Errorf("unexpected scores: %v", scores)
+	}
+
+	// The result is a copy
+	scores["coding"] = 0
+	if agent.scores["coding"] != 0.9 {
+		t.Error("expected agent scores to be unchanged")
+	}
+}
+
+func TestCapabilityScores_DefaultsListedToOne(t *testing.T) {
+	scores := CapabilityScores(&listingAgent{})
+	if len(scores) != 2 || scores["coding"] != 1.0 || scores["sql"] != 1.0 {
+		t.Errorf("expected listed capabilities at 1.0, got %v", scores)
+	}
+
+	if scores := CapabilityScores(&plainAgent{}); len(scores) != 0 {
+		t.Errorf("expected no scores, got %v", scores)
+	}
+}
@@ -0,0 +1,85 @@
+// Package agenkit provides request-scoped context propagation helpers.
+//
+// RequestContext carries ambient per-request data (tenant, trace, locale)
+// on the context.Context every Process and Execute call receives, instead
+// of in message metadata that is lost when patterns create new messages.
+package agenkit
+
+import "context"
+
+// requestContextKey is the private context key for RequestContext values.
+// Using an unexported struct type guarantees no collisions with keys defined
+// in other packages.
+type requestContextKey struct{}
+
+// RequestContext carries request-scoped data through the agent call tree.
+//
+// Patterns and compositions must pass the ctx they receive, or a context
+// derived from it, to every child agent, tool and LLM call. Substituting
+// context.Background() drops cancellation, deadlines and these values.
+//
+// Example:
+//
+//	ctx = agenkit.WithRequestContext(ctx, agenkit.RequestContext{TenantID: "acme", Locale: "en-GB"})
+//	response, err := agent.Process(ctx, message)
+//
+//	// Inside any agent or tool further down the call tree:
+//	if rc, ok := agenkit.RequestContextFromContext(ctx); ok {
+//	    log.Printf("tenant=%s", rc.TenantID)
+//	}
+type RequestContext struct {
+	// TenantID identifies the tenant the request belongs to
+	TenantID string `json:"tenant_id,omitempty"`
+
+	// TraceID correlates the request across agents and services
+	TraceID string `json:"trace_id,omitempty"`
+
+	// UserID identifies the end user on whose behalf the request runs
+	UserID string `json:"user_id,omitempty"`
+
+	// SessionID identifies the conversation or session the request is part of
+	SessionID string `json:"session_id,omitempty"`
+
+	// Locale is the caller's preferred locale (e.g. "en-US")
+	Locale string `json:"locale,omitempty"`
+
+	// Values holds additional application-defined request data
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+// Value returns the extension value stored under key.
+func (r RequestContext) Value(key string) (interface{}, bool) {
+	if r.Values == nil {
+		return nil, false
+	}
+	v, ok := r.Values[key]
+	return v, ok
+}
+
+// WithValue returns a copy of the request context with key set to value.
+// The receiver's Values map is not modified, so a RequestContext already
+// attached to a context.Context is never mutated.
+func (r RequestContext) WithValue(key string, value interface{}) RequestContext {
+	values := make(map[string]interface{}, len(r.Values)+1)
+	for k, v := range r.Values {
+		values[k] = v
+	}
+	values[key] = value
+	r.Values = values
+	return r
+}
+
+// WithRequestContext returns a copy of ctx carrying rc.
+func WithRequestContext(ctx context.Context, rc RequestContext) context.Context {
+	return context.WithValue(ctx, requestContextKey{}, rc)
+}
+
+// RequestContextFromContext retrieves the RequestContext stored in ctx.
+// Returns (context, true) if one is present, (zero, false) otherwise.
+func RequestContextFromContext(ctx context.Context) (RequestContext, bool) {
+	if ctx == nil {
+		return RequestContext{}, false
+	}
+	rc, ok := ctx.Value(requestContextKey{}).(RequestContext)
+	return rc, ok
+}
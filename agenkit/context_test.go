@@ -0,0 +1,58 @@
+package agenkit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRequestContext_RoundTrip(t *testing.T) {
+	rc := RequestContext{
+		TenantID: "acme",
+		TraceID:  "trace-123",
+		UserID:   "user-1",
+		Locale:   "en-GB",
+	}
+
+	ctx := WithRequestContext(context.Background(), rc)
+
+	got, ok := RequestContextFromContext(ctx)
+	if !ok {
+		t.Fatal("expected request context to be present")
+	}
+	if got.TenantID != "acme" || got.TraceID != "trace-123" || got.UserID != "user-1" || got.Locale != "en-GB" {
+		t.Errorf("unexpected request context: %+v", got)
+	}
+}
+
+func TestRequestContext_Missing(t *testing.T) {
+	if _, ok := RequestContextFromContext(context.Background()); ok {
+		t.Error("expected no request context on background context")
+	}
+}
+
+func TestRequestContext_SurvivesDerivedContexts(t *testing.T) {
+	ctx := WithRequestContext(context.Background(), RequestContext{TenantID: "acme"})
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	got, ok := RequestContextFromContext(ctx)
+	if !ok || got.TenantID != "acme" {
+		t.Errorf("expected request context to survive derived context, got %+v", got)
+	}
+}
+
+func TestRequestContext_WithValueDoesNotMutate(t *testing.T) {
+	base := RequestContext{}.WithValue("region", "eu-west-1")
+	extended := base.WithValue("feature", "beta")
+
+	if _, ok := base.Value("feature"); ok {
+		t.Error("expected WithValue not to mutate the original request context")
+	}
+
+	region, ok := extended.Value("region")
+	if !ok || region != "eu-west-1" {
+		t.Errorf("expected extended context to keep region, got %v", region)
+	}
+}
@@ -0,0 +1,87 @@
+package agenkit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Dead letter reasons recorded by the framework's patterns.
+const (
+	// DeadLetterUnroutable marks a message no route could accept
+	DeadLetterUnroutable = "unroutable"
+	// DeadLetterAllFailed marks a message every fallback agent failed on
+	DeadLetterAllFailed = "all_failed"
+	// DeadLetterItemFailed marks a batch item the agent failed on
+	DeadLetterItemFailed = "item_failed"
+)
+
+// DeadLetter is a message a pattern gave up on, with why, so it can be
+// inspected and reprocessed later instead of being lost.
+type DeadLetter struct {
+	// Message is the message that could not be handled
+	Message *Message
+	// Source names the pattern or helper that gave up on it
+	Source string
+	// Reason classifies the failure, e.g. DeadLetterUnroutable
+	Reason string
+	// Err is the error returned to the caller
+	Err error
+	// Timestamp is when the message was dead-lettered
+	Timestamp time.Time
+	// Metadata holds source-specific details, such as the category a
+	// router could not match or a batch item's key
+	Metadata map[string]interface{}
+}
+
+// DeadLetterSink receives messages that patterns could not handle.
+//
+// Patterns write to the sink in addition to returning their error, so the
+// caller's error handling is unchanged. A sink error is logged by the
+// pattern, not returned. Implementations must be safe for concurrent use.
+type DeadLetterSink interface {
+	WriteDeadLetter(ctx context.Context, letter DeadLetter) error
+}
+
+// MemoryDeadLetterSink keeps dead letters in memory, up to a limit.
+// Suitable for tests and for triaging a single process; production
+// systems usually write to a queue or table instead.
+type MemoryDeadLetterSink struct {
+	mu         sync.Mutex
+	letters    []DeadLetter
+	maxLetters int
+}
+
+// NewMemoryDeadLetterSink creates a sink keeping the most recent
+// maxLetters dead letters (0 = no limit).
+func NewMemoryDeadLetterSink(maxLetters int) *MemoryDeadLetterSink {
+	return &MemoryDeadLetterSink{maxLetters: maxLetters}
+}
+
+// WriteDeadLetter stores letter, evicting the oldest if the sink is full.
+func (s *MemoryDeadLetterSink) WriteDeadLetter(ctx context.Context, letter DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.letters = append(s.letters, letter)
+	if s.maxLetters > 0 && len(s.letters) > s.maxLetters {
+		s.letters = s.letters[len(s.letters)-s.maxLetters:]
+	}
+	return nil
+}
+
+// Letters returns the stored dead letters, oldest first.
+func (s *MemoryDeadLetterSink) Letters() []DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DeadLetter(nil), s.letters...)
+}
+
+// Drain returns the stored dead letters, oldest first, and removes them,
+// e.g. to reprocess them.
+func (s *MemoryDeadLetterSink) Drain() []DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	letters := s.letters
+	s.letters = nil
+	return letters
+}
@@ -0,0 +1,30 @@
+package agenkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMemoryDeadLetterSink(t *testing.T) {
+	sink := NewMemoryDeadLetterSink(2)
+	for i := 0; i < 3; i++ {
+		letter := DeadLetter{Message: NewMessage("user", fmt.Sprintf("m%d", i)), Reason: DeadLetterUnroutable, Err: errors.New("no route")}
+		if err := sink.WriteDeadLetter(context.Background(), letter); err != nil {
+			t.Fatalf("WriteDeadLetter: %v", err)
+		}
+	}
+
+	letters := sink.Letters()
+	if len(letters) != 2 || letters[0].Message.ContentString() != "m1" || letters[1].Message.ContentString() != "m2" {
+		t.Fatalf("expected the 2 most recent letters, got %+v", letters)
+	}
+
+	if drained := sink.Drain(); len(drained) != 2 {
+		t.Errorf("expected to drain 2 letters, got %d", len(drained))
+	}
+	if len(sink.Letters()) != 0 {
+		t.Error("expected the sink to be empty after Drain")
+	}
+}
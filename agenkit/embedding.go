@@ -0,0 +1,203 @@
+package agenkit
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// EmbeddingFunc embeds a batch of texts, returning one vector per text in
+// the same order.
+type EmbeddingFunc func(ctx context.Context, texts []string) ([][]float64, error)
+
+// EmbeddingCache stores embeddings by key.
+type EmbeddingCache interface {
+	// Get returns the embedding for key, and whether it was found.
+	Get(key string) ([]float64, bool)
+	// Set stores an embedding under key.
+	Set(key string, embedding []float64)
+	// Len returns the number of cached embeddings.
+	Len() int
+}
+
+// LRUEmbeddingCache is an in-process EmbeddingCache that evicts the least
+// recently used embedding once it holds capacity entries. It is safe for
+// concurrent use.
+type LRUEmbeddingCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// lruEmbedding is an LRUEmbeddingCache list entry.
+type lruEmbedding struct {
+	key       string
+	embedding []float64
+}
+
+// NewLRUEmbeddingCache creates an LRU cache holding up to capacity
+// embeddings (default 1000 if capacity <= 0).
+func NewLRUEmbeddingCache(capacity int) *LRUEmbeddingCache {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &LRUEmbeddingCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the embedding for key and marks it recently used.
+func (c *LRUEmbeddingCache) Get(key string) ([]float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEmbedding).embedding, true
+}
+
+// Set stores embedding under key, evicting the least recently used entry
+// if the cache is full.
+func (c *LRUEmbeddingCache) Set(key string, embedding []float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEmbedding).embedding = embedding
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEmbedding).key)
+	}
+	c.entries[key] = c.order.PushFront(&lruEmbedding{key: key, embedding: embedding})
+}
+
+// Len returns the number of cached embeddings.
+func (c *LRUEmbeddingCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// EmbeddingCacheStats counts how a CachingEmbedder served texts.
+type EmbeddingCacheStats struct {
+	// Hits were served from the cache
+	Hits int64
+	// Misses were sent to the underlying EmbeddingFunc
+	Misses int64
+}
+
+// CachingEmbedder memoizes an EmbeddingFunc by content hash, so stored
+// memories, classifier examples and other repeated texts are embedded
+// once.
+//
+// Batches are deduplicated: each distinct uncached text is sent to the
+// underlying function once, in a single call, and duplicates within the
+// batch count as hits. Errors are not cached.
+//
+// Example:
+//
+//	embedder := agenkit.NewCachingEmbedder(provider.EmbedBatch, agenkit.NewLRUEmbeddingCache(5000))
+//	vectors, err := embedder.EmbedBatch(ctx, texts)
+type CachingEmbedder struct {
+	underlying EmbeddingFunc
+	cache      EmbeddingCache
+
+	mu    sync.Mutex
+	stats EmbeddingCacheStats
+}
+
+// NewCachingEmbedder wraps underlying with cache (default: an
+// LRUEmbeddingCache with default capacity).
+func NewCachingEmbedder(underlying EmbeddingFunc, cache EmbeddingCache) *CachingEmbedder {
+	if cache == nil {
+		cache = NewLRUEmbeddingCache(0)
+	}
+	return &CachingEmbedder{underlying: underlying, cache: cache}
+}
+
+// Embed returns the embedding for a single text.
+func (e *CachingEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	vectors, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+// EmbedBatch returns one embedding per text, in order, calling the
+// underlying function only for distinct texts missing from the cache.
+//
+// Returns an error if the underlying function fails or returns the wrong
+// number of embeddings.
+func (e *CachingEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	results := make([][]float64, len(texts))
+	keys := make([]string, len(texts))
+	var missing []string
+	pending := make(map[string][]int)
+	hits := int64(0)
+
+	for i, text := range texts {
+		keys[i] = embeddingKey(text)
+		if vector, ok := e.cache.Get(keys[i]); ok {
+			results[i] = vector
+			hits++
+			continue
+		}
+		if _, seen := pending[keys[i]]; seen {
+			hits++
+		} else {
+			missing = append(missing, text)
+		}
+		pending[keys[i]] = append(pending[keys[i]], i)
+	}
+
+	e.mu.Lock()
+	e.stats.Hits += hits
+	e.stats.Misses += int64(len(missing))
+	e.mu.Unlock()
+
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	vectors, err := e.underlying(ctx, missing)
+	if err != nil {
+		return nil, fmt.Errorf("embedding failed: %w", err)
+	}
+	if len(vectors) != len(missing) {
+		return nil, fmt.Errorf("embedding returned %d vectors for %d texts", len(vectors), len(missing))
+	}
+
+	for i, text := range missing {
+		key := embeddingKey(text)
+		e.cache.Set(key, vectors[i])
+		for _, index := range pending[key] {
+			results[index] = vectors[i]
+		}
+	}
+	return results, nil
+}
+
+// Stats returns the hit and miss counts so far.
+func (e *CachingEmbedder) Stats() EmbeddingCacheStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.stats
+}
+
+// embeddingKey returns the cache key for text: its SHA-256 hex digest.
+func embeddingKey(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
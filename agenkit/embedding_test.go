@@ -0,0 +1,98 @@
+package agenkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// countingEmbedder embeds each text as its length and records every batch.
+type countingEmbedder struct {
+	batches [][]string
+	err     error
+}
+
+func (c *countingEmbedder) embed(ctx context.Context, texts []string) ([][]float64, error) {
+	c.batches = append(c.batches, texts)
+	if c.err != nil {
+		return nil, c.err
+	}
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float64{float64(len(text))}
+	}
+	return vectors, nil
+}
+
+func TestCachingEmbedder_DedupesBatchAndCaches(t *testing.T) {
+	underlying := &countingEmbedder{}
+	embedder := NewCachingEmbedder(underlying.embed, nil)
+	ctx := context.Background()
+
+	vectors, err := embedder.EmbedBatch(ctx, []string{"a", "bb", "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vectors) != 3 || vectors[0][0] != 1 || vectors[1][0] != 2 || vectors[2][0] != 1 {
+		t.Errorf("unexpected vectors %v", vectors)
+	}
+	if len(underlying.batches) != 1 || len(underlying.batches[0]) != 2 {
+		t.Errorf("expected one deduped batch, got %v", underlying.batches)
+	}
+
+	vectors, err = embedder.EmbedBatch(ctx, []string{"bb", "ccc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vectors[0][0] != 2 || vectors[1][0] != 3 {
+		t.Errorf("unexpected vectors %v", vectors)
+	}
+	if last := underlying.batches[len(underlying.batches)-1]; len(last) != 1 || last[0] != "ccc" {
+		t.Errorf("expected only the miss to be sent, got %v", last)
+	}
+
+	if stats := embedder.Stats(); stats.Hits != 2 || stats.Misses != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	if _, err := embedder.Embed(ctx, "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(underlying.batches) != 2 {
+		t.Errorf("expected cached single embed, got %d batches", len(underlying.batches))
+	}
+}
+
+func TestCachingEmbedder_Errors(t *testing.T) {
+	underlying := &countingEmbedder{err: errors.New("rate limited")}
+	embedder := NewCachingEmbedder(underlying.embed, nil)
+	if _, err := embedder.Embed(context.Background(), "a"); !errors.Is(err, underlying.err) {
+		t.Errorf("expected underlying error, got %v", err)
+	}
+
+	short := func(ctx context.Context, texts []string) ([][]float64, error) {
+		return [][]float64{{1}}, nil
+	}
+	embedder = NewCachingEmbedder(short, nil)
+	if _, err := embedder.EmbedBatch(context.Background(), []string{"a", "b"}); err == nil {
+		t.Error("expected error for mismatched vector count")
+	}
+}
+
+func TestLRUEmbeddingCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLRUEmbeddingCache(2)
+	cache.Set("a", []float64{1})
+	cache.Set("b", []float64{2})
+	cache.Get("a")
+	cache.Set("c", []float64{3})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("expected recently used entry to be kept")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", cache.Len())
+	}
+}
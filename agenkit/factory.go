@@ -0,0 +1,103 @@
+package agenkit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownAgentType is returned when creating an agent of a type that
+// hasn't been registered.
+var ErrUnknownAgentType = errors.New("unknown agent type")
+
+// AgentConstructor builds an agent named name from free-form parameters,
+// typically decoded from a JSON or YAML configuration file.
+type AgentConstructor func(name string, params map[string]interface{}) (Agent, error)
+
+// AgentFactory is a registry of agent constructors keyed by type name, so
+// agents can be instantiated from configuration rather than wired in code.
+// Safe for concurrent use.
+//
+// Example:
+//
+//	factory := agenkit.NewAgentFactory()
+//	factory.Register("llm", func(name string, params map[string]interface{}) (agenkit.Agent, error) {
+//	    model, _ := params["model"].(string)
+//	    return NewLLMAgent(name, model), nil
+//	})
+//	agent, err := factory.Create("llm", "writer", map[string]interface{}{"model": "gpt-4o"})
+type AgentFactory struct {
+	mu           sync.RWMutex
+	constructors map[string]AgentConstructor
+}
+
+// NewAgentFactory creates an empty agent factory.
+func NewAgentFactory() *AgentFactory {
+	return &AgentFactory{
+		constructors: make(map[string]AgentConstructor),
+	}
+}
+
+// Register adds a constructor for agentType.
+//
+// Returns an error if agentType is empty, constructor is nil, or the type
+// is already registered.
+func (f *AgentFactory) Register(agentType string, constructor AgentConstructor) error {
+	if agentType == "" {
+		return fmt.Errorf("agent type cannot be empty")
+	}
+	if constructor == nil {
+		return fmt.Errorf("constructor for agent type '%s' cannot be nil", agentType)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, exists := f.constructors[agentType]; exists {
+		return fmt.Errorf("agent type '%s' is already registered", agentType)
+	}
+	f.constructors[agentType] = constructor
+	return nil
+}
+
+// Has reports whether agentType is registered.
+func (f *AgentFactory) Has(agentType string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	_, exists := f.constructors[agentType]
+	return exists
+}
+
+// Types returns the registered agent types, sorted.
+func (f *AgentFactory) Types() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	types := make([]string, 0, len(f.constructors))
+	for agentType := range f.constructors {
+		types = append(types, agentType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Create builds an agent of agentType.
+//
+// Returns an error wrapping ErrUnknownAgentType if the type isn't
+// registered, or the constructor's error.
+func (f *AgentFactory) Create(agentType, name string, params map[string]interface{}) (Agent, error) {
+	f.mu.RLock()
+	constructor, exists := f.constructors[agentType]
+	f.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownAgentType, agentType)
+	}
+
+	agent, err := constructor(name, params)
+	if err != nil {
+		return nil, fmt.Errorf("create %s agent '%s': %w", agentType, name, err)
+	}
+	if agent == nil {
+		return nil, fmt.Errorf("create %s agent '%s': constructor returned nil", agentType, name)
+	}
+	return agent, nil
+}
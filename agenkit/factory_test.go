@@ -0,0 +1,78 @@
+package agenkit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAgentFactory_RegisterAndCreate(t *testing.T) {
+	factory := NewAgentFactory()
+	var gotName string
+	var gotParams map[string]interface{}
+	err := factory.Register("plain", func(name string, params map[string]interface{}) (Agent, error) {
+		gotName, gotParams = name, params
+		return &plainAgent{}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	agent, err := factory.Create("plain", "worker", map[string]interface{}{"model": "small"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if agent.Name() != "plain" || gotName != "worker" || gotParams["model"] != "small" {
+		t.Errorf("unexpected construction: %s, %s, %v", agent.Name(), gotName, gotParams)
+	}
+	if !factory.Has("plain") || factory.Has("other") {
+		t.Error("unexpected Has result")
+	}
+}
+
+func TestAgentFactory_RegisterValidation(t *testing.T) {
+	factory := NewAgentFactory()
+	constructor := func(name string, params map[string]interface{}) (Agent, error) { return &plainAgent{}, nil }
+
+	if err := factory.Register("", constructor); err == nil {
+		t.Error("expected error for empty type")
+	}
+	if err := factory.Register("plain", nil); err == nil {
+		t.Error("expected error for nil constructor")
+	}
+	if err := factory.Register("plain", constructor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := factory.Register("plain", constructor); err == nil {
+		t.Error("expected error for duplicate type")
+	}
+	if err := factory.Register("another", constructor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	types := factory.Types()
+	if len(types) != 2 || types[0] != "another" || types[1] != "plain" {
+		t.Errorf("expected sorted types, got %v", types)
+	}
+}
+
+func TestAgentFactory_CreateErrors(t *testing.T) {
+	factory := NewAgentFactory()
+	if _, err := factory.Create("missing", "x", nil); !errors.Is(err, ErrUnknownAgentType) {
+		t.Errorf("expected ErrUnknownAgentType, got %v", err)
+	}
+
+	_ = factory.Register("broken", func(name string, params map[string]interface{}) (Agent, error) {
+		return nil, errors.New("missing api key")
+	})
+	_ = factory.Register("empty", func(name string, params map[string]interface{}) (Agent, error) {
+		return nil, nil
+	})
+
+	if _, err := factory.Create("broken", "x", nil); err == nil || !strings.Contains(err.Error(), "missing api key") {
+		t.Errorf("expected constructor error, got %v", err)
+	}
+	if _, err := factory.Create("empty", "x", nil); err == nil {
+		t.Error("expected error for nil agent")
+	}
+}
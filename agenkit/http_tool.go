@@ -0,0 +1,331 @@
+package agenkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ToolResultRetryableKey is the ToolResult metadata key a tool sets to true
+// when its failure is transient and the call may succeed if retried.
+const ToolResultRetryableKey = "retryable"
+
+// Retryable reports whether a failed result is marked as transient with
+// ToolResultRetryableKey.
+func (t *ToolResult) Retryable() bool {
+	if t == nil || t.Success {
+		return false
+	}
+	retryable, _ := t.Metadata[ToolResultRetryableKey].(bool)
+	return retryable
+}
+
+// HTTPToolConfig configures an HTTPTool.
+//
+// URL, RequestTemplate and header values are text/template templates
+// executed with the tool's parameters map, so "{{.city}}" inserts the
+// "city" parameter. Templates can use the built-in urlquery function to
+// escape query values and json to encode a value as JSON. A parameter the
+// template references but the call doesn't provide is an error.
+type HTTPToolConfig struct {
+	// Name identifies the tool (required)
+	Name string
+	// Description tells the agent what the tool does and which parameters
+	// it takes (required)
+	Description string
+	// URL is the endpoint template (required), e.g.
+	// "https://api.example.com/weather?q={{urlquery .city}}"
+	URL string
+	// Method is the HTTP method (default: GET)
+	Method string
+	// RequestTemplate renders the request body (optional). If empty, POST,
+	// PUT and PATCH requests send the parameters as a JSON object and
+	// other methods send no body.
+	RequestTemplate string
+	// ContentType is the body's content type (default: application/json)
+	ContentType string
+	// Headers are added to each request; values are templates
+	Headers map[string]string
+	// ResponsePath selects the result from a JSON response with a dotted
+	// path of object keys and array indexes, e.g. "data.items.0.name"
+	// (optional; default: the whole decoded response). A response that
+	// isn't JSON is returned as a string when ResponsePath is empty.
+	ResponsePath string
+	// MaxResponseBytes caps how much of the response body is read
+	// (default: 1 MiB)
+	MaxResponseBytes int64
+	// Client sends the requests (default: a client with a 30s timeout)
+	Client *http.Client
+}
+
+// HTTPTool is a Tool that calls an HTTP API, giving agents access to a
+// REST endpoint without a hand-written Tool per endpoint.
+//
+// Failures are reported as failed ToolResults so the agent sees them as
+// observations: network errors, 429 and 5xx responses are marked retryable
+// (see ToolResult.Retryable), while other non-2xx responses, bad
+// parameters and missing response paths are not. Every result records
+// metadata "status_code" once a response arrives. Execute returns an error
+// only if ctx is done.
+//
+// Example:
+//
+//	weather, err := agenkit.NewHTTPTool(agenkit.HTTPToolConfig{
+//	    Name:         "weather",
+//	    Description:  "Current temperature for a city. Parameters: city",
+//	    URL:          "https://api.example.com/weather?q={{urlquery .city}}",
+//	    ResponsePath: "current.temp_c",
+//	})
+type HTTPTool struct {
+	name             string
+	description      string
+	method           string
+	url              *template.Template
+	body             *template.Template
+	contentType      string
+	headers          map[string]*template.Template
+	responsePath     []string
+	maxResponseBytes int64
+	client           *http.Client
+}
+
+// NewHTTPTool creates an HTTP tool.
+//
+// Returns an error if Name, Description or URL is empty or a template
+// doesn't parse.
+func NewHTTPTool(config HTTPToolConfig) (*HTTPTool, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("tool name is required")
+	}
+	if config.Description == "" {
+		return nil, fmt.Errorf("tool description is required")
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("URL is required")
+	}
+
+	urlTemplate, err := parseHTTPToolTemplate("url", config.URL)
+	if err != nil {
+		return nil, err
+	}
+	var bodyTemplate *template.Template
+	if config.RequestTemplate != "" {
+		if bodyTemplate, err = parseHTTPToolTemplate("request", config.RequestTemplate); err != nil {
+			return nil, err
+		}
+	}
+	headers := make(map[string]*template.Template, len(config.Headers))
+	for key, value := range config.Headers {
+		if headers[key], err = parseHTTPToolTemplate("header "+key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	method := strings.ToUpper(config.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	contentType := config.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	maxResponseBytes := config.MaxResponseBytes
+	if maxResponseBytes <= 0 {
+		maxResponseBytes = 1 << 20
+	}
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	var responsePath []string
+	if config.ResponsePath != "" {
+		responsePath = strings.Split(config.ResponsePath, ".")
+	}
+
+	return &HTTPTool{
+		name:             config.Name,
+		description:      config.Description,
+		method:           method,
+		url:              urlTemplate,
+		body:             bodyTemplate,
+		contentType:      contentType,
+		headers:          headers,
+		responsePath:     responsePath,
+		maxResponseBytes: maxResponseBytes,
+		client:           client,
+	}, nil
+}
+
+// parseHTTPToolTemplate parses one of an HTTPTool's templates.
+func parseHTTPToolTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			encoded, err := json.Marshal(v)
+			return string(encoded), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// Name returns the tool name.
+func (t *HTTPTool) Name() string {
+	return t.name
+}
+
+// Description returns the tool description.
+func (t *HTTPTool) Description() string {
+	return t.description
+}
+
+// Execute renders the request from params, sends it under ctx and extracts
+// the result into ToolResult.Data.
+func (t *HTTPTool) Execute(ctx context.Context, params map[string]any) (*ToolResult, error) {
+	if params == nil {
+		params = map[string]any{}
+	}
+	req, err := t.buildRequest(ctx, params)
+	if err != nil {
+		return NewToolError(err.Error()), nil
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return NewToolError(fmt.Sprintf("request failed: %v", err)).
+			WithMetadata(ToolResultRetryableKey, true), nil
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.maxResponseBytes))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return NewToolError(fmt.Sprintf("reading response failed: %v", err)).
+			WithMetadata(ToolResultRetryableKey, true).
+			WithMetadata("status_code", resp.StatusCode), nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		message := fmt.Sprintf("HTTP %d", resp.StatusCode)
+		if text := strings.TrimSpace(string(body)); text != "" {
+			message += ": " + text
+		}
+		return NewToolError(message).
+			WithMetadata(ToolResultRetryableKey, retryable).
+			WithMetadata("status_code", resp.StatusCode), nil
+	}
+
+	data, err := t.extract(body)
+	if err != nil {
+		return NewToolError(err.Error()).WithMetadata("status_code", resp.StatusCode), nil
+	}
+	return NewToolResult(data).WithMetadata("status_code", resp.StatusCode), nil
+}
+
+// buildRequest renders the URL, body and headers from params.
+func (t *HTTPTool) buildRequest(ctx context.Context, params map[string]any) (*http.Request, error) {
+	url, err := renderHTTPToolTemplate(t.url, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	switch {
+	case t.body != nil:
+		rendered, err := renderHTTPToolTemplate(t.body, params)
+		if err != nil {
+			return nil, err
+		}
+		body = strings.NewReader(rendered)
+	case t.method == http.MethodPost || t.method == http.MethodPut || t.method == http.MethodPatch:
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("encoding parameters failed: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, t.method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", t.contentType)
+	}
+	for key, tmpl := range t.headers {
+		value, err := renderHTTPToolTemplate(tmpl, params)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
+
+// renderHTTPToolTemplate executes tmpl with params.
+func renderHTTPToolTemplate(tmpl *template.Template, params map[string]any) (string, error) {
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, params); err != nil {
+		return "", fmt.Errorf("invalid parameters for %s: %w", tmpl.Name(), err)
+	}
+	return rendered.String(), nil
+}
+
+// extract decodes body and selects the configured response path.
+func (t *HTTPTool) extract(body []byte) (interface{}, error) {
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		if len(t.responsePath) == 0 {
+			return string(body), nil
+		}
+		return nil, fmt.Errorf("response is not JSON: %w", err)
+	}
+
+	current := decoded
+	for i, key := range t.responsePath {
+		next, err := httpToolPathStep(current, key)
+		if err != nil {
+			return nil, fmt.Errorf("response path %q: %w", strings.Join(t.responsePath[:i+1], "."), err)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// errPathNotFound is returned when a response path segment doesn't exist.
+var errPathNotFound = errors.New("not found")
+
+// httpToolPathStep selects key from an object or index key from an array.
+func httpToolPathStep(value interface{}, key string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		next, ok := v[key]
+		if !ok {
+			return nil, errPathNotFound
+		}
+		return next, nil
+	case []interface{}:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(v) {
+			return nil, errPathNotFound
+		}
+		return v[index], nil
+	default:
+		return nil, errPathNotFound
+	}
+}
@@ -0,0 +1,164 @@
+package agenkit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPTool_GetWithResponsePath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "New York" || r.Header.Get("X-Key") != "secret-abc" {
+			t.Errorf("unexpected request: %s %v", r.URL, r.Header)
+		}
+		_, _ = w.Write([]byte(`{"current": {"temp_c": 21.5, "tags": ["sunny", "calm"]}}`))
+	}))
+	defer server.Close()
+
+	tool, err := NewHTTPTool(HTTPToolConfig{
+		Name:         "weather",
+		Description:  "Weather for a city",
+		URL:          server.URL + "/weather?q={{urlquery .city}}",
+		Headers:      map[string]string{"X-Key": "secret-{{.key}}"},
+		ResponsePath: "current.temp_c",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := tool.Execute(context.Background(), map[string]any{"city": "New York", "key": "abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success || result.Data != 21.5 || result.Metadata["status_code"] != 200 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	tool.responsePath = []string{"current", "tags", "1"}
+	if result, _ := tool.Execute(context.Background(), map[string]any{"city": "New York", "key": "abc"}); result.Data != "calm" {
+		t.Errorf("expected array index path, got %+v", result)
+	}
+}
+
+func TestHTTPTool_PostBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+		_, _ = w.Write([]byte("created"))
+	}))
+	defer server.Close()
+
+	templated, _ := NewHTTPTool(HTTPToolConfig{
+		Name:            "create",
+		Description:     "Create an item",
+		URL:             server.URL,
+		Method:          "post",
+		RequestTemplate: `{"title": {{json .title}}}`,
+	})
+	result, err := templated.Execute(context.Background(), map[string]any{"title": `say "hi"`})
+	if err != nil || !result.Success || result.Data != "created" {
+		t.Fatalf("unexpected result: %+v, %v", result, err)
+	}
+
+	plain, _ := NewHTTPTool(HTTPToolConfig{Name: "create", Description: "Create", URL: server.URL, Method: http.MethodPut})
+	if _, err := plain.Execute(context.Background(), map[string]any{"n": 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(bodies[0]), &decoded); err != nil || decoded["title"] != `say "hi"` {
+		t.Errorf("expected templated JSON body, got %s", bodies[0])
+	}
+	if bodies[1] != `{"n":1}` {
+		t.Errorf("expected parameters as JSON body, got %s", bodies[1])
+	}
+}
+
+func TestHTTPTool_Failures(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("try later"))
+	}))
+	defer server.Close()
+
+	tool, _ := NewHTTPTool(HTTPToolConfig{Name: "api", Description: "API", URL: server.URL + "/{{.id}}"})
+
+	tests := []struct {
+		name      string
+		status    int
+		params    map[string]any
+		retryable bool
+		contains  string
+	}{
+		{"server error", http.StatusServiceUnavailable, map[string]any{"id": 1}, true, "HTTP 503: try later"},
+		{"rate limited", http.StatusTooManyRequests, map[string]any{"id": 1}, true, "HTTP 429"},
+		{"not found", http.StatusNotFound, map[string]any{"id": 1}, false, "HTTP 404"},
+		{"missing parameter", http.StatusOK, map[string]any{}, false, "invalid parameters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status = tt.status
+			result, err := tool.Execute(context.Background(), tt.params)
+			if err != nil {
+				t.Fatalf("expected a failed result, got error %v", err)
+			}
+			if result.Success || result.Retryable() != tt.retryable || !strings.Contains(result.Error, tt.contains) {
+				t.Errorf("unexpected result: %+v", result)
+			}
+		})
+	}
+}
+
+func TestHTTPTool_NetworkErrorAndCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	tool, _ := NewHTTPTool(HTTPToolConfig{Name: "api", Description: "API", URL: url})
+	result, err := tool.Execute(context.Background(), nil)
+	if err != nil || !result.Retryable() {
+		t.Errorf("expected a retryable failed result, got %+v, %v", result, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := tool.Execute(ctx, nil); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestHTTPTool_ResponsePathMissing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {}}`))
+	}))
+	defer server.Close()
+
+	tool, _ := NewHTTPTool(HTTPToolConfig{Name: "api", Description: "API", URL: server.URL, ResponsePath: "data.items.0"})
+	result, _ := tool.Execute(context.Background(), nil)
+	if result.Success || result.Retryable() || !strings.Contains(result.Error, `"data.items"`) {
+		t.Errorf("expected a missing path failure, got %+v", result)
+	}
+}
+
+func TestNewHTTPTool_Validation(t *testing.T) {
+	configs := []HTTPToolConfig{
+		{Description: "d", URL: "http://x"},
+		{Name: "n", URL: "http://x"},
+		{Name: "n", Description: "d"},
+		{Name: "n", Description: "d", URL: "http://x/{{.id"},
+	}
+	for i, config := range configs {
+		if _, err := NewHTTPTool(config); err == nil {
+			t.Errorf("config %d: expected error", i)
+		}
+	}
+}
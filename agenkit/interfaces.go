@@ -3,16 +3,31 @@ package agenkit
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // Message represents a message exchanged between agents or tools.
+//
+// ID identifies the message and ParentID links it to the message it was
+// produced from, so the causal graph of a pipeline or multi-agent run can
+// be reconstructed. NewMessage assigns an ID; for messages built as struct
+// literals, EnsureID assigns one on demand.
 type Message struct {
+	ID        string                 `json:"id,omitempty"`
+	ParentID  string                 `json:"parent_id,omitempty"`
 	Role      string                 `json:"role"`
 	Content   any                    `json:"content"`
 	Metadata  map[string]interface{} `json:"metadata"`
 	Timestamp time.Time              `json:"timestamp"`
+	// ToolCalls are structured tool calls requested by the message, as
+	// returned by function-calling LLM APIs (optional)
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 }
 
 // ContentString returns the message content as a string.
@@ -57,6 +72,7 @@ func (m *Message) ContentBlocks() []interface{} {
 // consider using NewValidatedMessage or calling Validate() explicitly.
 func NewMessage(role, content string) *Message {
 	return &Message{
+		ID:        uuid.NewString(),
 		Role:      role,
 		Content:   content,
 		Metadata:  make(map[string]interface{}),
@@ -81,7 +97,31 @@ func (m *Message) WithMetadata(key string, value interface{}) *Message {
 	return m
 }
 
+// EnsureID assigns a new UUID to the message if it has no ID, and returns the ID.
+func (m *Message) EnsureID() string {
+	if m.ID == "" {
+		m.ID = uuid.NewString()
+	}
+	return m.ID
+}
+
+// WithParent links the message to parent, the message it was produced
+// from, and returns the message for chaining. A parent without an ID is
+// assigned one. A nil parent leaves the message unchanged.
+//
+// Example:
+//
+//	response := agenkit.NewMessage("assistant", answer).WithParent(request)
+func (m *Message) WithParent(parent *Message) *Message {
+	if parent != nil {
+		m.ParentID = parent.EnsureID()
+	}
+	return m
+}
+
 // Validate validates the message according to security constraints.
+// The role must be canonical (see Roles), or the legacy "agent" unless
+// strict mode is enabled (see SetStrictRoles).
 func (m *Message) Validate() error {
 	// Role validation
 	if m.Role == "" {
@@ -91,16 +131,14 @@ func (m *Message) Validate() error {
 		return fmt.Errorf("message role exceeds maximum length of 20 characters (got %d)", len(m.Role))
 	}
 
-	// Validate role is one of the allowed values
-	allowedRoles := map[string]bool{
-		"user":      true,
-		"assistant": true,
-		"system":    true,
-		"tool":      true,
-		"agent":     true,
-	}
-	if !allowedRoles[m.Role] {
-		return fmt.Errorf("invalid message role: %s. Must be one of: user, assistant, system, tool, agent", m.Role)
+	// Validate role is one of the allowed values; the legacy "agent" only
+	// outside strict mode
+	switch {
+	case IsCanonicalRole(m.Role):
+	case m.Role == RoleAgent && StrictRoles():
+		return fmt.Errorf("legacy message role %q is not allowed in strict mode, use %q", m.Role, RoleAssistant)
+	case m.Role != RoleAgent:
+		return fmt.Errorf("invalid message role: %s. Must be one of: %s", m.Role, strings.Join(Roles, ", "))
 	}
 
 	// Content validation - max 16MB (aligned with other languages)
@@ -158,6 +196,11 @@ func min(a, b int) int {
 }
 
 // ToolResult represents the result of a tool execution.
+//
+// Data may hold any JSON-encodable value: a string, a number, or structured
+// data such as a map or a list of rows. Use DataString or DataJSON to render
+// it for an LLM, and DataMap, DataSlice, DataFloat or DecodeData to read it
+// without flattening it to a string.
 type ToolResult struct {
 	Success  bool                   `json:"success"`
 	Data     interface{}            `json:"data,omitempty"`
@@ -189,6 +232,121 @@ func (t *ToolResult) WithMetadata(key string, value interface{}) *ToolResult {
 	return t
 }
 
+// DataString renders Data as text for prompts and observations.
+// Strings, byte slices, errors and fmt.Stringers render as themselves; nil
+// renders as ""; numbers and booleans use their natural formatting; maps,
+// slices and structs render as JSON so their structure survives.
+func (t *ToolResult) DataString() string {
+	switch v := t.Data.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	switch reflect.Indirect(reflect.ValueOf(t.Data)).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		if data, err := t.DataJSON(); err == nil {
+			return data
+		}
+	}
+	return fmt.Sprintf("%v", t.Data)
+}
+
+// DataJSON returns Data encoded as JSON, for passing structured results
+// to an LLM. Nil data encodes as "null".
+func (t *ToolResult) DataJSON() (string, error) {
+	data, err := json.Marshal(t.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tool result data: %w", err)
+	}
+	return string(data), nil
+}
+
+// DataMap returns Data as a map if it is a map with string keys.
+func (t *ToolResult) DataMap() (map[string]interface{}, bool) {
+	if m, ok := t.Data.(map[string]interface{}); ok {
+		return m, true
+	}
+	v := reflect.ValueOf(t.Data)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	m := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		m[iter.Key().String()] = iter.Value().Interface()
+	}
+	return m, true
+}
+
+// DataSlice returns Data as a slice if it is a slice or array (other than
+// []byte), such as a list of rows.
+func (t *ToolResult) DataSlice() ([]interface{}, bool) {
+	if s, ok := t.Data.([]interface{}); ok {
+		return s, true
+	}
+	if _, ok := t.Data.([]byte); ok {
+		return nil, false
+	}
+	v := reflect.ValueOf(t.Data)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	s := make([]interface{}, v.Len())
+	for i := range s {
+		s[i] = v.Index(i).Interface()
+	}
+	return s, true
+}
+
+// DataFloat returns Data as a float64 if it is a number of any Go numeric
+// type or a json.Number.
+func (t *ToolResult) DataFloat() (float64, bool) {
+	if n, ok := t.Data.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	v := reflect.ValueOf(t.Data)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// DecodeData decodes Data into target (a pointer) via JSON, converting
+// generic maps, such as results received over a transport, into typed
+// structs.
+//
+// Example:
+//
+//	var rows []SalesRow
+//	if err := result.DecodeData(&rows); err != nil {
+//	    return err
+//	}
+func (t *ToolResult) DecodeData(target interface{}) error {
+	data, err := json.Marshal(t.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode tool result data: %w", err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed to decode tool result data: %w", err)
+	}
+	return nil
+}
+
 // Agent is the core interface that all agents must implement.
 // Agents process messages and optionally support streaming responses.
 type Agent interface {
@@ -0,0 +1,154 @@
+package agenkit
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewMessage_AssignsID(t *testing.T) {
+	a := NewMessage("user", "a")
+	b := NewMessage("user", "b")
+
+	if a.ID == "" || a.ID == b.ID {
+		t.Errorf("expected distinct IDs, got %q and %q", a.ID, b.ID)
+	}
+	if a.ParentID != "" {
+		t.Errorf("expected no parent, got %q", a.ParentID)
+	}
+}
+
+func TestMessage_EnsureID(t *testing.T) {
+	msg := &Message{Role: "user", Content: "hi"}
+
+	id := msg.EnsureID()
+	if id == "" || msg.ID != id {
+		t.Fatalf("expected ID to be assigned, got %q", msg.ID)
+	}
+	if msg.EnsureID() != id {
+		t.Error("expected existing ID to be kept")
+	}
+}
+
+func TestMessage_WithParent(t *testing.T) {
+	parent := &Message{Role: "user", Content: "question"}
+	child := NewMessage("assistant", "answer").WithParent(parent)
+
+	if parent.ID == "" || child.ParentID != parent.ID {
+		t.Errorf("expected child linked to parent %q, got %q", parent.ID, child.ParentID)
+	}
+
+	if NewMessage("assistant", "x").WithParent(nil).ParentID != "" {
+		t.Error("expected nil parent to leave message unlinked")
+	}
+}
+
+func TestMessage_JSONLineage(t *testing.T) {
+	msg := NewMessage("assistant", "answer").WithParent(NewMessage("user", "q"))
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var decoded Message
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if decoded.ID != msg.ID || decoded.ParentID != msg.ParentID {
+		t.Errorf("expected lineage to round-trip, got %q/%q", decoded.ID, decoded.ParentID)
+	}
+
+	// Messages without lineage serialize as before
+	data, _ = json.Marshal(&Message{Role: "user", Content: "x"})
+	var fields map[string]interface{}
+	_ = json.Unmarshal(data, &fields)
+	if _, ok := fields["id"]; ok {
+		t.Error("expected empty ID to be omitted")
+	}
+}
+
+type salesRow struct {
+	Region string  `json:"region"`
+	Total  float64 `json:"total"`
+}
+
+func TestToolResult_DataString(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+		want string
+	}{
+		{"nil", nil, ""},
+		{"string", "42 rows", "42 rows"},
+		{"int", 42, "42"},
+		{"float", 3.5, "3.5"},
+		{"bool", true, "true"},
+		{"map", map[string]interface{}{"count": 2, "ok": true}, `{"count":2,"ok":true}`},
+		{"rows", []salesRow{{"EU", 10}, {"US", 12.5}}, `[{"region":"EU","total":10},{"region":"US","total":12.5}]`},
+		{"struct pointer", &salesRow{"EU", 10}, `{"region":"EU","total":10}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewToolResult(tt.data).DataString(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestToolResult_DataJSON(t *testing.T) {
+	data, err := NewToolResult(nil).DataJSON()
+	if err != nil || data != "null" {
+		t.Errorf("expected null, got %q, %v", data, err)
+	}
+
+	if _, err := NewToolResult(make(chan int)).DataJSON(); err == nil {
+		t.Error("expected error for unencodable data")
+	}
+}
+
+func TestToolResult_TypedAccessors(t *testing.T) {
+	m, ok := NewToolResult(map[string]int{"a": 1}).DataMap()
+	if !ok || m["a"] != 1 {
+		t.Errorf("expected map, got %v, %v", m, ok)
+	}
+	if _, ok := NewToolResult("text").DataMap(); ok {
+		t.Error("expected string not to be a map")
+	}
+
+	s, ok := NewToolResult([]salesRow{{"EU", 10}}).DataSlice()
+	if !ok || len(s) != 1 || s[0].(salesRow).Region != "EU" {
+		t.Errorf("expected slice, got %v, %v", s, ok)
+	}
+	if _, ok := NewToolResult([]byte("raw")).DataSlice(); ok {
+		t.Error("expected []byte not to be a slice")
+	}
+
+	for _, data := range []interface{}{7, int64(7), uint8(7), float32(7), 7.0, json.Number("7")} {
+		if f, ok := NewToolResult(data).DataFloat(); !ok || f != 7 {
+			t.Errorf("expected 7 from %T, got %v, %v", data, f, ok)
+		}
+	}
+	if _, ok := NewToolResult("7").DataFloat(); ok {
+		t.Error("expected string not to be a number")
+	}
+}
+
+func TestToolResult_DecodeData(t *testing.T) {
+	// Generic data, as received over a transport
+	result := NewToolResult([]interface{}{
+		map[string]interface{}{"region": "EU", "total": 10.0},
+	})
+
+	var rows []salesRow
+	if err := result.DecodeData(&rows); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0] != (salesRow{"EU", 10}) {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+
+	if err := NewToolResult("text").DecodeData(&rows); err == nil {
+		t.Error("expected error decoding string into rows")
+	}
+}
@@ -0,0 +1,72 @@
+package agenkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Initializable is implemented by agents that need to acquire resources
+// (connection pools, model weights, caches) before their first Process call.
+//
+// Init should be idempotent: composite patterns forward Init to their
+// sub-agents, so an agent shared by several patterns may be initialized
+// more than once.
+type Initializable interface {
+	Init(ctx context.Context) error
+}
+
+// Closer is implemented by agents that hold resources which must be
+// released on shutdown. It matches io.Closer.
+//
+// Close should be idempotent for the same reason as Init.
+type Closer interface {
+	Close() error
+}
+
+// InitAll initializes every agent that implements Initializable, in order.
+//
+// If an agent fails to initialize, the agents already initialized are
+// closed (in reverse order) and the error is returned. Agents that do not
+// implement Initializable are skipped.
+//
+// Example:
+//
+//	if err := agenkit.InitAll(ctx, retriever, model, reranker); err != nil {
+//	    return err
+//	}
+//	defer agenkit.CloseAll(retriever, model, reranker)
+func InitAll(ctx context.Context, agents ...Agent) error {
+	for i, agent := range agents {
+		initializable, ok := agent.(Initializable)
+		if !ok {
+			continue
+		}
+		if err := initializable.Init(ctx); err != nil {
+			initErr := fmt.Errorf("init agent %s: %w", agent.Name(), err)
+			if closeErr := CloseAll(agents[:i]...); closeErr != nil {
+				return errors.Join(initErr, closeErr)
+			}
+			return initErr
+		}
+	}
+	return nil
+}
+
+// CloseAll closes every agent that implements Closer, in reverse order.
+//
+// All agents are closed even if some fail; the errors are joined. Agents
+// that do not implement Closer are skipped.
+func CloseAll(agents ...Agent) error {
+	var errs []error
+	for i := len(agents) - 1; i >= 0; i-- {
+		closer, ok := agents[i].(Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close agent %s: %w", agents[i].Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+package agenkit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// lifecycleAgent records Init and Close calls into a shared log.
+type lifecycleAgent struct {
+	name     string
+	log      *[]string
+	initErr  error
+	closeErr error
+}
+
+func (a *lifecycleAgent) Name() string           { return a.name }
+func (a *lifecycleAgent) Capabilities() []string { return nil }
+func (a *lifecycleAgent) Introspect() *IntrospectionResult {
+	return DefaultIntrospectionResult(a)
+}
+func (a *lifecycleAgent) Process(ctx context.Context, m *Message) (*Message, error) {
+	return m, nil
+}
+
+func (a *lifecycleAgent) Init(ctx context.Context) error {
+	*a.log = append(*a.log, "init "+a.name)
+	return a.initErr
+}
+
+func (a *lifecycleAgent) Close() error {
+	*a.log = append(*a.log, "close "+a.name)
+	return a.closeErr
+}
+
+// plainAgent implements neither lifecycle interface.
+type plainAgent struct{}
+
+func (a *plainAgent) Name() string           { return "plain" }
+func (a *plainAgent) Capabilities() []string { return nil }
+func (a *plainAgent) Introspect() *IntrospectionResult {
+	return DefaultIntrospectionResult(a)
+}
+func (a *plainAgent) Process(ctx context.Context, m *Message) (*Message, error) {
+	return m, nil
+}
+
+func TestInitAll_CloseAll_Order(t *testing.T) {
+	var log []string
+	a := &lifecycleAgent{name: "a", log: &log}
+	b := &lifecycleAgent{name: "b", log: &log}
+
+	if err := InitAll(context.Background(), a, &plainAgent{}, b); err != nil {
+		t.Fatalf("InitAll failed: %v", err)
+	}
+	if err := CloseAll(a, &plainAgent{}, b); err != nil {
+		t.Fatalf("CloseAll failed: %v", err)
+	}
+
+	want := []string{"init a", "init b", "close b", "close a"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("expected %v, got %v", want, log)
+	}
+}
+
+func TestInitAll_RollsBackOnFailure(t *testing.T) {
+	var log []string
+	boom := errors.New("boom")
+	a := &lifecycleAgent{name: "a", log: &log}
+	b := &lifecycleAgent{name: "b", log: &log, initErr: boom}
+	c := &lifecycleAgent{name: "c", log: &log}
+
+	err := InitAll(context.Background(), a, b, c)
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected init error, got %v", err)
+	}
+
+	want := []string{"init a", "init b", "close a"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("expected %v, got %v", want, log)
+	}
+}
+
+func TestCloseAll_ClosesEveryAgent(t *testing.T) {
+	var log []string
+	errA := errors.New("a failed")
+	errB := errors.New("b failed")
+	a := &lifecycleAgent{name: "a", log: &log, closeErr: errA}
+	b := &lifecycleAgent{name: "b", log: &log, closeErr: errB}
+
+	err := CloseAll(a, b)
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("expected both close errors, got %v", err)
+	}
+	if len(log) != 2 {
+		t.Errorf("expected both agents closed, got %v", log)
+	}
+}
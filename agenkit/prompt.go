@@ -0,0 +1,89 @@
+package agenkit
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// PromptTemplate is a named, pre-parsed text/template used to build the
+// prompts that LLM-driven patterns send to their models.
+//
+// Patterns export their default templates so callers can read them, copy
+// them and pass a tuned variant through the pattern's config instead of
+// forking the pattern. Templates are rendered with missingkey=error, so a
+// reference to a field the pattern does not provide fails loudly.
+//
+// In addition to the text/template builtins, templates may call:
+//
+//	join  - strings.Join, e.g. {{join .Categories ", "}}
+//	upper - strings.ToUpper
+//	lower - strings.ToLower
+//	trim  - strings.TrimSpace
+//
+// Example:
+//
+//	tmpl := agenkit.MustPromptTemplate("terse-classifier",
+//	    `Pick one of {{join .Categories "|"}}. Answer with the label only.
+//	{{.Message}}`)
+type PromptTemplate struct {
+	name string
+	text string
+	tmpl *template.Template
+}
+
+// promptFuncs are the helper functions available to every PromptTemplate.
+var promptFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+}
+
+// NewPromptTemplate parses text as a prompt template.
+//
+// Returns an error if the name is empty or the template does not parse.
+func NewPromptTemplate(name, text string) (*PromptTemplate, error) {
+	if name == "" {
+		return nil, fmt.Errorf("prompt template name is required")
+	}
+
+	tmpl, err := template.New(name).
+		Funcs(promptFuncs).
+		Option("missingkey=error").
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt template %s: %w", name, err)
+	}
+
+	return &PromptTemplate{name: name, text: text, tmpl: tmpl}, nil
+}
+
+// MustPromptTemplate is like NewPromptTemplate but panics on error. It is
+// intended for package-level template variables.
+func MustPromptTemplate(name, text string) *PromptTemplate {
+	t, err := NewPromptTemplate(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Name returns the template's name.
+func (t *PromptTemplate) Name() string {
+	return t.name
+}
+
+// Text returns the unparsed template source.
+func (t *PromptTemplate) Text() string {
+	return t.text
+}
+
+// Render executes the template against data and returns the prompt.
+func (t *PromptTemplate) Render(data any) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render prompt template %s: %w", t.name, err)
+	}
+	return b.String(), nil
+}
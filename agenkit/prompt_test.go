@@ -0,0 +1,56 @@
+package agenkit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPromptTemplate_Render(t *testing.T) {
+	tmpl, err := NewPromptTemplate("greet", `Hello {{.Name}}, pick one of {{join .Options ", "}}.`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := tmpl.Render(map[string]interface{}{
+		"Name":    "Ada",
+		"Options": []string{"a", "b"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "Hello Ada, pick one of a, b." {
+		t.Errorf("unexpected render: %q", got)
+	}
+	if tmpl.Name() != "greet" {
+		t.Errorf("expected name greet, got %s", tmpl.Name())
+	}
+	if !strings.HasPrefix(tmpl.Text(), "Hello {{.Name}}") {
+		t.Errorf("expected Text to return source, got %q", tmpl.Text())
+	}
+}
+
+func TestPromptTemplate_MissingKey(t *testing.T) {
+	tmpl := MustPromptTemplate("missing", "{{.Absent}}")
+
+	if _, err := tmpl.Render(map[string]interface{}{}); err == nil {
+		t.Fatal("expected error for missing key")
+	}
+}
+
+func TestNewPromptTemplate_Errors(t *testing.T) {
+	if _, err := NewPromptTemplate("", "text"); err == nil {
+		t.Error("expected error for empty name")
+	}
+	if _, err := NewPromptTemplate("bad", "{{.Unclosed"); err == nil {
+		t.Error("expected error for unparsable template")
+	}
+}
+
+func TestMustPromptTemplate_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	MustPromptTemplate("bad", "{{if}}")
+}
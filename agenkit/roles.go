@@ -0,0 +1,78 @@
+package agenkit
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// Canonical message roles, matching the roles of the major LLM chat APIs.
+const (
+	// RoleUser marks input from the end user
+	RoleUser = "user"
+	// RoleAssistant marks output from an agent or LLM
+	RoleAssistant = "assistant"
+	// RoleSystem marks instructions that frame the conversation
+	RoleSystem = "system"
+	// RoleTool marks the result of a tool call
+	RoleTool = "tool"
+	// RoleDeveloper marks instructions from the application developer,
+	// which newer APIs rank between system and user instructions
+	RoleDeveloper = "developer"
+)
+
+// RoleAgent is the legacy name for RoleAssistant, still accepted by
+// Validate outside strict mode. NormalizeRole maps it to RoleAssistant.
+//
+// Deprecated: Use RoleAssistant.
+const RoleAgent = "agent"
+
+// Roles lists the canonical message roles.
+var Roles = []string{RoleUser, RoleAssistant, RoleSystem, RoleTool, RoleDeveloper}
+
+// strictRoles enables strict role validation, see SetStrictRoles.
+var strictRoles atomic.Bool
+
+// SetStrictRoles enables or disables strict role validation for the
+// process. In strict mode Message.Validate accepts only the canonical
+// roles, rejecting the legacy RoleAgent, so role mix-ups surface as errors
+// instead of silently breaking role-based context handling. Disabled by
+// default.
+func SetStrictRoles(enabled bool) {
+	strictRoles.Store(enabled)
+}
+
+// StrictRoles reports whether strict role validation is enabled.
+func StrictRoles() bool {
+	return strictRoles.Load()
+}
+
+// IsCanonicalRole reports whether role is one of the canonical roles.
+func IsCanonicalRole(role string) bool {
+	switch role {
+	case RoleUser, RoleAssistant, RoleSystem, RoleTool, RoleDeveloper:
+		return true
+	}
+	return false
+}
+
+// NormalizeRole returns the canonical form of role: lower-cased, trimmed,
+// and with the legacy "agent" mapped to "assistant". Unknown roles are
+// returned lower-cased and trimmed, for Validate to reject.
+//
+// Example:
+//
+//	agenkit.NormalizeRole("agent") // "assistant"
+func NormalizeRole(role string) string {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if role == RoleAgent {
+		return RoleAssistant
+	}
+	return role
+}
+
+// NormalizeRole sets the message's role to its canonical form (see
+// NormalizeRole) and returns the message for chaining.
+func (m *Message) NormalizeRole() *Message {
+	m.Role = NormalizeRole(m.Role)
+	return m
+}
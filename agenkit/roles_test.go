@@ -0,0 +1,56 @@
+package agenkit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMessage_ValidateRoles(t *testing.T) {
+	for _, role := range Roles {
+		if err := NewMessage(role, "hi").Validate(); err != nil {
+			t.Errorf("role %q: unexpected error %v", role, err)
+		}
+	}
+	if err := NewMessage("moderator", "hi").Validate(); err == nil || !strings.Contains(err.Error(), "developer") {
+		t.Errorf("expected an error listing the canonical roles, got %v", err)
+	}
+
+	// The legacy role is only rejected in strict mode
+	legacy := NewMessage(RoleAgent, "hi")
+	if err := legacy.Validate(); err != nil {
+		t.Errorf("unexpected error for legacy role: %v", err)
+	}
+	SetStrictRoles(true)
+	defer SetStrictRoles(false)
+	if !StrictRoles() {
+		t.Fatal("expected strict mode enabled")
+	}
+	if err := legacy.Validate(); err == nil || !strings.Contains(err.Error(), "strict mode") {
+		t.Errorf("expected strict mode to reject the legacy role, got %v", err)
+	}
+	if err := legacy.NormalizeRole().Validate(); err != nil {
+		t.Errorf("unexpected error after normalizing: %v", err)
+	}
+	if legacy.Role != RoleAssistant {
+		t.Errorf("expected role %q, got %q", RoleAssistant, legacy.Role)
+	}
+}
+
+func TestNormalizeRole(t *testing.T) {
+	tests := map[string]string{
+		"agent":     RoleAssistant,
+		" Agent ":   RoleAssistant,
+		"assistant": RoleAssistant,
+		"USER":      RoleUser,
+		"developer": RoleDeveloper,
+		"moderator": "moderator",
+	}
+	for role, want := range tests {
+		if got := NormalizeRole(role); got != want {
+			t.Errorf("NormalizeRole(%q) = %q, want %q", role, got, want)
+		}
+	}
+	if IsCanonicalRole(RoleAgent) || !IsCanonicalRole(RoleTool) {
+		t.Error("unexpected IsCanonicalRole result")
+	}
+}
@@ -0,0 +1,48 @@
+package agenkit
+
+// ToolCall is a structured request to call a tool, as returned by
+// function-calling LLM APIs (OpenAI tool_calls, Anthropic tool_use
+// blocks). Adapters set Message.ToolCalls so reasoning patterns can
+// execute calls directly instead of parsing them out of text.
+type ToolCall struct {
+	// ID identifies the call; its ToolCallResult carries it back so the
+	// LLM can match results to calls
+	ID string `json:"id"`
+	// Name is the tool to call
+	Name string `json:"name"`
+	// Arguments are the tool's parameters
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// ToolCallResult is the result of executing a ToolCall.
+type ToolCallResult struct {
+	// ID is the ToolCall's ID
+	ID string `json:"id"`
+	// Result is the tool's result
+	Result *ToolResult `json:"result"`
+}
+
+// NewToolCallResult pairs result with the call that produced it.
+func NewToolCallResult(call ToolCall, result *ToolResult) ToolCallResult {
+	return ToolCallResult{ID: call.ID, Result: result}
+}
+
+// WithToolCalls appends structured tool calls to the message and returns
+// the message for chaining.
+//
+// Example:
+//
+//	response := agenkit.NewMessage("assistant", "Let me check.").WithToolCalls(agenkit.ToolCall{
+//	    ID:        "call_1",
+//	    Name:      "weather",
+//	    Arguments: map[string]interface{}{"city": "Paris"},
+//	})
+func (m *Message) WithToolCalls(calls ...ToolCall) *Message {
+	m.ToolCalls = append(m.ToolCalls, calls...)
+	return m
+}
+
+// HasToolCalls reports whether the message carries structured tool calls.
+func (m *Message) HasToolCalls() bool {
+	return m != nil && len(m.ToolCalls) > 0
+}
@@ -0,0 +1,55 @@
+package agenkit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMessage_WithToolCalls(t *testing.T) {
+	msg := NewMessage("assistant", "Let me check.")
+	if msg.HasToolCalls() {
+		t.Error("expected no tool calls on a new message")
+	}
+
+	msg.WithToolCalls(
+		ToolCall{ID: "call_1", Name: "weather", Arguments: map[string]interface{}{"city": "Paris"}},
+		ToolCall{ID: "call_2", Name: "time", Arguments: map[string]interface{}{}},
+	)
+	if !msg.HasToolCalls() || len(msg.ToolCalls) != 2 || msg.ToolCalls[1].Name != "time" {
+		t.Errorf("unexpected tool calls: %+v", msg.ToolCalls)
+	}
+
+	var nilMsg *Message
+	if nilMsg.HasToolCalls() {
+		t.Error("expected a nil message to have no tool calls")
+	}
+}
+
+func TestMessage_ToolCallsJSON(t *testing.T) {
+	plain, _ := json.Marshal(NewMessage("user", "hi"))
+	if strings.Contains(string(plain), "tool_calls") {
+		t.Errorf("expected tool_calls omitted when empty: %s", plain)
+	}
+
+	msg := NewMessage("assistant", "").WithToolCalls(ToolCall{ID: "call_1", Name: "weather", Arguments: map[string]interface{}{"city": "Paris"}})
+	encoded, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var decoded Message
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if len(decoded.ToolCalls) != 1 || decoded.ToolCalls[0].Arguments["city"] != "Paris" {
+		t.Errorf("tool calls did not round-trip: %s", encoded)
+	}
+}
+
+func TestNewToolCallResult(t *testing.T) {
+	call := ToolCall{ID: "call_1", Name: "weather"}
+	result := NewToolCallResult(call, NewToolResult("sunny"))
+	if result.ID != "call_1" || result.Result.Data != "sunny" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,467 @@
+package checkpointing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// BatchConfig configures RunBatch and ResumeBatch.
+type BatchConfig struct {
+	// BatchID identifies the batch; its checkpoint is stored under this
+	// session ID (required when checkpointing)
+	BatchID string
+	// CheckpointEvery is how many completed items trigger a checkpoint
+	// (default: 10). A final checkpoint is always written.
+	CheckpointEvery int
+	// Concurrency is how many items are processed at once (default: 1)
+	Concurrency int
+	// KeyFunc returns an item's stable key, used to dedupe items and to
+	// recognize completed items on resume (default: a hash of the
+	// message's role and content)
+	KeyFunc func(index int, message *agenkit.Message) string
+	// DeadLetters receives items the agent failed on, with their key and
+	// index in the metadata (optional)
+	DeadLetters agenkit.DeadLetterSink
+	// WorkGroup runs each item as a unit of work (optional), so shutting
+	// the group down drains the batch: items it rejects or cancels are
+	// left for ResumeBatch
+	WorkGroup WorkGroup
+}
+
+// WorkGroup runs units of work that can be drained on shutdown.
+// *patterns.WorkGroup implements it.
+type WorkGroup interface {
+	// Go runs fn in a new goroutine, or returns an error without running
+	// it if the group no longer accepts work
+	Go(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// BatchItemResult is the outcome of one batch item.
+type BatchItemResult struct {
+	// Index is the item's position in the batch
+	Index int
+	// Key is the item's stable key
+	Key string
+	// Result is the agent's response, if the item succeeded
+	Result *agenkit.Message
+	// Err is the agent's error, if the item failed
+	Err error
+	// Resumed is true if the result was restored from a checkpoint
+	Resumed bool
+	// Duplicate is true if the result was shared with an earlier item with
+	// the same key
+	Duplicate bool
+}
+
+// BatchResult contains the outcome of a batch run.
+type BatchResult struct {
+	// BatchID identifies the batch
+	BatchID string
+	// Items holds every item's outcome, in batch order. Items not reached
+	// or cancelled when the run was interrupted have neither Result nor Err.
+	Items []BatchItemResult
+	// Completed is the number of items processed successfully in this run
+	Completed int
+	// Failed is the number of items that failed in this run
+	Failed int
+	// Resumed is the number of items restored from a checkpoint
+	Resumed int
+	// Checkpoints is the number of checkpoints written in this run
+	Checkpoints int
+}
+
+// batchCheckpointID returns the ID of batchID's checkpoint. Every
+// checkpoint of a batch overwrites the previous one.
+func batchCheckpointID(batchID string) string {
+	return "batch-" + batchID
+}
+
+// DefaultBatchKey is the default BatchConfig.KeyFunc: a SHA-256 hash of the
+// message's role and content.
+func DefaultBatchKey(index int, message *agenkit.Message) string {
+	hash := sha256.Sum256([]byte(message.Role + "\x00" + message.ContentString()))
+	return hex.EncodeToString(hash[:])
+}
+
+// RunBatch processes messages with agent from the start, checkpointing
+// completed results to storage so the batch can be resumed with
+// ResumeBatch after an interruption.
+//
+// Args:
+//
+//	ctx: Context; cancelling it stops the batch and the items in flight,
+//	  which are left for ResumeBatch
+//	storage: Where checkpoints are written (nil disables checkpointing)
+//	agent: Agent processing each item
+//	messages: The batch items
+//	config: Batch configuration
+//
+// Returns:
+//
+//	BatchResult with every item's outcome, and the context error if the
+//	batch was interrupted, or an error if a checkpoint couldn't be written
+//
+// Items with the same key are processed once. Failed items are not
+// checkpointed, so ResumeBatch retries them; set config.DeadLetters to
+// capture them for triage as well.
+//
+// Example:
+//
+//	storage, _ := checkpointing.NewLocalStorage("./checkpoints")
+//	config := checkpointing.BatchConfig{BatchID: "eval-2024-06", Concurrency: 4}
+//	result, err := checkpointing.RunBatch(ctx, storage, agent, messages, config)
+//	// After a crash, pick up where it left off:
+//	result, err = checkpointing.ResumeBatch(ctx, storage, agent, messages, config)
+func RunBatch(ctx context.Context, storage CheckpointStorage, agent agenkit.Agent, messages []*agenkit.Message, config BatchConfig) (*BatchResult, error) {
+	return runBatch(ctx, storage, agent, messages, config, nil)
+}
+
+// ResumeBatch continues a batch started with RunBatch, skipping items whose
+// results are in the batch's checkpoint. Without a checkpoint it runs the
+// whole batch.
+//
+// Args:
+//
+//	ctx: Context; cancelling it stops the batch and the items in flight,
+//	  which are left for ResumeBatch
+//	storage: Where the batch's checkpoint is read from and written to
+//	agent: Agent processing each remaining item
+//	messages: The batch items (completed items are matched by key, so the
+//	  order may change between runs)
+//	config: Batch configuration, with the same BatchID and KeyFunc as the
+//	  interrupted run
+//
+// Returns:
+//
+//	BatchResult with every item's outcome, including restored ones
+func ResumeBatch(ctx context.Context, storage CheckpointStorage, agent agenkit.Agent, messages []*agenkit.Message, config BatchConfig) (*BatchResult, error) {
+	if storage == nil {
+		return nil, fmt.Errorf("storage is required to resume a batch")
+	}
+	if config.BatchID == "" {
+		return nil, fmt.Errorf("batch ID is required to resume a batch")
+	}
+
+	checkpoint, err := storage.Load(ctx, batchCheckpointID(config.BatchID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load batch checkpoint: %w", err)
+	}
+	completed := make(map[string]*agenkit.Message)
+	if checkpoint != nil {
+		if completed, err = decodeBatchResults(checkpoint.State["completed"]); err != nil {
+			return nil, fmt.Errorf("failed to decode batch checkpoint: %w", err)
+		}
+	}
+	return runBatch(ctx, storage, agent, messages, config, completed)
+}
+
+// batchRun is the state of one RunBatch or ResumeBatch call.
+type batchRun struct {
+	ctx       context.Context
+	storage   CheckpointStorage
+	agent     agenkit.Agent
+	config    BatchConfig
+	keys      []string
+	result    *BatchResult
+	mu        sync.Mutex
+	completed map[string]*agenkit.Message
+	sinceSave int
+	// saveErr is the first checkpoint error, and interrupted the first
+	// cancellation of an item by the work group
+	saveErr     error
+	interrupted error
+	// saveMu orders checkpoint writes, so an older snapshot never
+	// overwrites a newer one
+	saveMu sync.Mutex
+}
+
+func runBatch(ctx context.Context, storage CheckpointStorage, agent agenkit.Agent, messages []*agenkit.Message, config BatchConfig, completed map[string]*agenkit.Message) (*BatchResult, error) {
+	if agent == nil {
+		return nil, fmt.Errorf("agent is required")
+	}
+	if storage != nil && config.BatchID == "" {
+		return nil, fmt.Errorf("batch ID is required for checkpointing")
+	}
+	if config.CheckpointEvery <= 0 {
+		config.CheckpointEvery = 10
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = DefaultBatchKey
+	}
+	if completed == nil {
+		completed = make(map[string]*agenkit.Message)
+	}
+
+	run := &batchRun{
+		ctx:       ctx,
+		storage:   storage,
+		agent:     agent,
+		config:    config,
+		keys:      make([]string, len(messages)),
+		result:    &BatchResult{BatchID: config.BatchID, Items: make([]BatchItemResult, len(messages))},
+		completed: completed,
+	}
+
+	// Pick the first item of each key that still needs processing
+	pending := make([]int, 0, len(messages))
+	first := make(map[string]int)
+	for i, message := range messages {
+		key := config.KeyFunc(i, message)
+		run.keys[i] = key
+		run.result.Items[i] = BatchItemResult{Index: i, Key: key}
+		if _, done := completed[key]; done {
+			continue
+		}
+		if _, seen := first[key]; !seen {
+			first[key] = i
+			pending = append(pending, i)
+		}
+	}
+
+	// Each item is a unit of work; sem bounds how many run at once
+	sem := make(chan struct{}, config.Concurrency)
+	var wg sync.WaitGroup
+	var rejected error
+dispatch:
+	for _, i := range pending {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+		wg.Add(1)
+		index := i
+		unit := func(unitCtx context.Context) error {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			return run.process(unitCtx, index, messages[index])
+		}
+		if config.WorkGroup == nil {
+			go func() { _ = unit(ctx) }()
+			continue
+		}
+		if err := config.WorkGroup.Go(ctx, unit); err != nil {
+			<-sem
+			wg.Done()
+			rejected = err
+			break
+		}
+	}
+	wg.Wait()
+	saveErr := run.saveErr
+
+	// Write the final checkpoint even if ctx was cancelled, so the work
+	// done so far survives the interruption
+	if err := run.save(context.WithoutCancel(ctx)); err != nil && saveErr == nil {
+		saveErr = err
+	}
+
+	// Fill in restored and duplicate items
+	for i, key := range run.keys {
+		item := &run.result.Items[i]
+		if item.Result != nil || item.Err != nil {
+			continue
+		}
+		if origin, ok := first[key]; ok && origin != i {
+			item.Result = run.result.Items[origin].Result
+			item.Err = run.result.Items[origin].Err
+			item.Duplicate = item.Result != nil || item.Err != nil
+			continue
+		}
+		if result, ok := completed[key]; ok {
+			item.Result = result
+			item.Resumed = true
+			run.result.Resumed++
+		}
+	}
+
+	if saveErr != nil {
+		return run.result, saveErr
+	}
+	if err := ctx.Err(); err != nil {
+		return run.result, fmt.Errorf("batch interrupted: %w", err)
+	}
+	if rejected != nil {
+		return run.result, fmt.Errorf("batch interrupted: %w", rejected)
+	}
+	if run.interrupted != nil {
+		return run.result, fmt.Errorf("batch interrupted: %w", run.interrupted)
+	}
+	return run.result, nil
+}
+
+// process runs item i on ctx, the context of its unit of work, and
+// checkpoints every CheckpointEvery completions. It returns the agent's
+// error. An item that fails because it was cancelled is left for
+// ResumeBatch rather than recorded as failed.
+func (r *batchRun) process(ctx context.Context, i int, message *agenkit.Message) error {
+	var response *agenkit.Message
+	err := ctx.Err()
+	if err == nil {
+		response, err = r.agent.Process(ctx, message)
+	}
+	if err != nil && ctx.Err() != nil {
+		r.mu.Lock()
+		if r.interrupted == nil {
+			r.interrupted = ctx.Err()
+		}
+		r.mu.Unlock()
+		return err
+	}
+
+	r.mu.Lock()
+	item := &r.result.Items[i]
+	if err != nil {
+		item.Err = err
+		r.result.Failed++
+		r.mu.Unlock()
+		r.deadLetter(i, message, err)
+		return err
+	}
+	item.Result = response
+	r.result.Completed++
+	r.completed[r.keys[i]] = response
+	r.sinceSave++
+	due := r.sinceSave >= r.config.CheckpointEvery
+	r.mu.Unlock()
+
+	if due {
+		if err := r.save(r.ctx); err != nil {
+			r.mu.Lock()
+			if r.saveErr == nil {
+				r.saveErr = err
+			}
+			r.mu.Unlock()
+		}
+	}
+	return nil
+}
+
+// deadLetter writes failed item i to the configured sink.
+func (r *batchRun) deadLetter(i int, message *agenkit.Message, err error) {
+	if r.config.DeadLetters == nil {
+		return
+	}
+
+	letter := agenkit.DeadLetter{
+		Message:   message,
+		Source:    "batch",
+		Reason:    agenkit.DeadLetterItemFailed,
+		Err:       err,
+		Timestamp: time.Now().UTC(),
+		Metadata: map[string]interface{}{
+			"batch_id": r.config.BatchID,
+			"index":    i,
+			"key":      r.keys[i],
+		},
+	}
+	if sinkErr := r.config.DeadLetters.WriteDeadLetter(r.ctx, letter); sinkErr != nil {
+		log.Printf("WARNING: Failed to dead-letter batch item %d: %v", i, sinkErr)
+	}
+}
+
+// save writes the batch checkpoint with every completed result, if
+// anything completed since the last one. The results are snapshotted under
+// mu and written without it, so workers aren't blocked on storage.
+func (r *batchRun) save(ctx context.Context) error {
+	if r.storage == nil {
+		return nil
+	}
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+
+	r.mu.Lock()
+	saved := r.sinceSave
+	if saved == 0 {
+		r.mu.Unlock()
+		return nil
+	}
+	snapshot := make(map[string]*agenkit.Message, len(r.completed))
+	for key, response := range r.completed {
+		snapshot[key] = response
+	}
+	cursor := r.cursorLocked()
+	r.mu.Unlock()
+
+	completed, err := encodeBatchResults(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode batch results: %w", err)
+	}
+	checkpoint := &Checkpoint{
+		CheckpointID: batchCheckpointID(r.config.BatchID),
+		SessionID:    r.config.BatchID,
+		AgentName:    r.agent.Name(),
+		Timestamp:    time.Now().UTC(),
+		StepNumber:   len(snapshot),
+		State: map[string]interface{}{
+			"cursor":    cursor,
+			"total":     len(r.keys),
+			"completed": completed,
+		},
+		Messages: make([]agenkit.Message, 0),
+		Metadata: map[string]interface{}{"batch": true},
+	}
+	if err := r.storage.Save(ctx, checkpoint); err != nil {
+		return fmt.Errorf("failed to save batch checkpoint: %w", err)
+	}
+
+	r.mu.Lock()
+	r.sinceSave -= saved
+	r.result.Checkpoints++
+	r.mu.Unlock()
+	return nil
+}
+
+// cursorLocked returns the index of the first item not yet completed.
+// Caller must hold mu.
+func (r *batchRun) cursorLocked() int {
+	for i, key := range r.keys {
+		if _, ok := r.completed[key]; !ok {
+			return i
+		}
+	}
+	return len(r.keys)
+}
+
+// encodeBatchResults converts results to plain JSON values so they
+// survive any storage backend's serialization.
+func encodeBatchResults(results map[string]*agenkit.Message) (map[string]interface{}, error) {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	var encoded map[string]interface{}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+	return encoded, nil
+}
+
+// decodeBatchResults reverses encodeBatchResults.
+func decodeBatchResults(value interface{}) (map[string]*agenkit.Message, error) {
+	results := make(map[string]*agenkit.Message)
+	if value == nil {
+		return results, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,260 @@
+package checkpointing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// countingAgent upper-cases its input, counting calls per input. It
+// cancels cancel after stopAfter calls if set, and fails inputs in fail.
+type countingAgent struct {
+	mu        sync.Mutex
+	calls     map[string]int
+	total     int
+	stopAfter int
+	cancel    context.CancelFunc
+	fail      map[string]bool
+}
+
+func newCountingAgent() *countingAgent {
+	return &countingAgent{calls: make(map[string]int), fail: make(map[string]bool)}
+}
+
+func (a *countingAgent) Name() string           { return "counting" }
+func (a *countingAgent) Capabilities() []string { return nil }
+func (a *countingAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+
+func (a *countingAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls[message.ContentString()]++
+	a.total++
+	if a.stopAfter > 0 && a.total == a.stopAfter {
+		a.cancel()
+	}
+	if a.fail[message.ContentString()] {
+		return nil, errors.New("boom")
+	}
+	return agenkit.NewMessage("assistant", strings.ToUpper(message.ContentString())), nil
+}
+
+func batchMessages(n int) []*agenkit.Message {
+	messages := make([]*agenkit.Message, n)
+	for i := range messages {
+		messages[i] = agenkit.NewMessage("user", fmt.Sprintf("item %d", i))
+	}
+	return messages
+}
+
+func TestResumeBatch_SkipsCompletedItems(t *testing.T) {
+	storage, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	messages := batchMessages(10)
+	config := BatchConfig{BatchID: "job", CheckpointEvery: 2}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupted := newCountingAgent()
+	interrupted.stopAfter, interrupted.cancel = 4, cancel
+	result, err := RunBatch(ctx, storage, interrupted, messages, config)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected interruption, got %v", err)
+	}
+	if result.Completed != 4 || result.Checkpoints == 0 {
+		t.Fatalf("completed %d with %d checkpoints, want 4 and some", result.Completed, result.Checkpoints)
+	}
+
+	resumed := newCountingAgent()
+	result, err = ResumeBatch(context.Background(), storage, resumed, messages, config)
+	if err != nil {
+		t.Fatalf("ResumeBatch: %v", err)
+	}
+	if resumed.total != 6 {
+		t.Errorf("resume processed %d items, want 6", resumed.total)
+	}
+	if result.Resumed != 4 || result.Completed != 6 {
+		t.Errorf("resumed %d and completed %d, want 4 and 6", result.Resumed, result.Completed)
+	}
+	for i, item := range result.Items {
+		if want := strings.ToUpper(messages[i].ContentString()); item.Result == nil || item.Result.ContentString() != want {
+			t.Errorf("item %d = %+v, want %q", i, item.Result, want)
+		}
+	}
+
+	checkpoint, _ := storage.Load(context.Background(), batchCheckpointID("job"))
+	if checkpoint == nil || checkpoint.StepNumber != 10 {
+		t.Fatalf("final checkpoint = %+v, want 10 completed items", checkpoint)
+	}
+	if cursor, _ := checkpoint.State["cursor"].(float64); cursor != 10 {
+		t.Errorf("cursor = %v, want 10", checkpoint.State["cursor"])
+	}
+}
+
+// interruptingAgent cancels the batch on its first call and returns the
+// context error, like an agent interrupted mid-call.
+type interruptingAgent struct {
+	cancel context.CancelFunc
+}
+
+func (a *interruptingAgent) Name() string           { return "interrupting" }
+func (a *interruptingAgent) Capabilities() []string { return nil }
+func (a *interruptingAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+
+func (a *interruptingAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	a.cancel()
+	return nil, ctx.Err()
+}
+
+func TestRunBatch_InterruptedItemsNotFailed(t *testing.T) {
+	storage := NewMemoryStorage()
+	sink := agenkit.NewMemoryDeadLetterSink(0)
+	messages := batchMessages(3)
+	config := BatchConfig{BatchID: "interrupted", DeadLetters: sink}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result, err := RunBatch(ctx, storage, &interruptingAgent{cancel: cancel}, messages, config)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected interruption, got %v", err)
+	}
+	if result.Failed != 0 || result.Items[0].Err != nil {
+		t.Errorf("failed %d, item 0 err %v; want the interrupted item left unprocessed", result.Failed, result.Items[0].Err)
+	}
+	if letters := sink.Letters(); len(letters) != 0 {
+		t.Errorf("expected no dead letters, got %d", len(letters))
+	}
+
+	agent := newCountingAgent()
+	if _, err := ResumeBatch(context.Background(), storage, agent, messages, config); err != nil {
+		t.Fatalf("ResumeBatch: %v", err)
+	}
+	if agent.total != 3 {
+		t.Errorf("resume processed %d items, want 3", agent.total)
+	}
+}
+
+// closingWorkGroup runs units in goroutines and rejects them after accept
+// units, like a work group that began shutting down.
+type closingWorkGroup struct {
+	mu     sync.Mutex
+	accept int
+}
+
+func (g *closingWorkGroup) Go(ctx context.Context, fn func(ctx context.Context) error) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.accept == 0 {
+		return errors.New("work group is shutting down")
+	}
+	g.accept--
+	go func() { _ = fn(ctx) }()
+	return nil
+}
+
+func TestRunBatch_WorkGroupShutdownInterruptsBatch(t *testing.T) {
+	storage := NewMemoryStorage()
+	messages := batchMessages(5)
+	config := BatchConfig{BatchID: "drained", WorkGroup: &closingWorkGroup{accept: 2}}
+
+	agent := newCountingAgent()
+	result, err := RunBatch(context.Background(), storage, agent, messages, config)
+	if err == nil || !strings.Contains(err.Error(), "batch interrupted") {
+		t.Fatalf("expected interruption, got %v", err)
+	}
+	if result.Completed != 2 || agent.total != 2 {
+		t.Fatalf("completed %d with %d calls, want 2", result.Completed, agent.total)
+	}
+
+	config.WorkGroup = nil
+	resumed := newCountingAgent()
+	if _, err := ResumeBatch(context.Background(), storage, resumed, messages, config); err != nil {
+		t.Fatalf("ResumeBatch: %v", err)
+	}
+	if resumed.total != 3 {
+		t.Errorf("resume processed %d items, want 3", resumed.total)
+	}
+}
+
+func TestRunBatch_DedupesByKey(t *testing.T) {
+	messages := []*agenkit.Message{
+		agenkit.NewMessage("user", "same"),
+		agenkit.NewMessage("user", "other"),
+		agenkit.NewMessage("user", "same"),
+	}
+	agent := newCountingAgent()
+	result, err := RunBatch(context.Background(), NewMemoryStorage(), agent, messages, BatchConfig{BatchID: "dedupe", Concurrency: 2})
+	if err != nil {
+		t.Fatalf("RunBatch: %v", err)
+	}
+	if agent.calls["same"] != 1 {
+		t.Errorf("duplicate item processed %d times, want 1", agent.calls["same"])
+	}
+	if !result.Items[2].Duplicate || result.Items[2].Result.ContentString() != "SAME" {
+		t.Errorf("item 2 = %+v, want a duplicate of item 0", result.Items[2])
+	}
+}
+
+func TestResumeBatch_RetriesFailedItems(t *testing.T) {
+	storage := NewMemoryStorage()
+	messages := batchMessages(3)
+	config := BatchConfig{BatchID: "retry"}
+
+	flaky := newCountingAgent()
+	flaky.fail["item 1"] = true
+	result, err := RunBatch(context.Background(), storage, flaky, messages, config)
+	if err != nil {
+		t.Fatalf("RunBatch: %v", err)
+	}
+	if result.Failed != 1 || result.Items[1].Err == nil {
+		t.Fatalf("failed %d, item 1 err %v; want item 1 to fail", result.Failed, result.Items[1].Err)
+	}
+
+	healthy := newCountingAgent()
+	result, err = ResumeBatch(context.Background(), storage, healthy, messages, config)
+	if err != nil {
+		t.Fatalf("ResumeBatch: %v", err)
+	}
+	if healthy.total != 1 || healthy.calls["item 1"] != 1 {
+		t.Errorf("resume calls = %v, want only item 1", healthy.calls)
+	}
+	if result.Failed != 0 || result.Items[1].Result == nil {
+		t.Errorf("item 1 = %+v, want it to succeed on resume", result.Items[1])
+	}
+}
+
+func TestRunBatch_DeadLettersFailedItems(t *testing.T) {
+	sink := agenkit.NewMemoryDeadLetterSink(0)
+	messages := batchMessages(3)
+
+	flaky := newCountingAgent()
+	flaky.fail["item 2"] = true
+	if _, err := RunBatch(context.Background(), nil, flaky, messages, BatchConfig{BatchID: "triage", DeadLetters: sink}); err != nil {
+		t.Fatalf("RunBatch: %v", err)
+	}
+
+	letters := sink.Letters()
+	if len(letters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(letters))
+	}
+	letter := letters[0]
+	if letter.Message != messages[2] || letter.Reason != agenkit.DeadLetterItemFailed || letter.Err == nil ||
+		letter.Metadata["index"] != 2 || letter.Metadata["batch_id"] != "triage" {
+		t.Errorf("unexpected dead letter %+v", letter)
+	}
+}
+
+func TestRunBatch_RequiresBatchIDForCheckpointing(t *testing.T) {
+	if _, err := RunBatch(context.Background(), NewMemoryStorage(), newCountingAgent(), batchMessages(1), BatchConfig{}); err == nil {
+		t.Error("expected error without a batch ID")
+	}
+}
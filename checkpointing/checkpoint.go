@@ -12,6 +12,7 @@
 //   - FileStorage: File-based persistent storage
 //   - CheckpointManager: High-level checkpoint management
 //   - DurableAgent: Agent wrapper with automatic checkpointing
+//   - RunBatch/ResumeBatch: Resumable batch processing with checkpointed results
 package checkpointing
 
 import (
@@ -0,0 +1,199 @@
+// Package codec provides pluggable serialization formats for recordings
+// and message history.
+//
+// A Codec encodes plain data: the nil, bool, float64, string, []interface{}
+// and map[string]interface{} values encoding/json produces, which is what
+// recordings (SessionRecording.ToDict) and messages (MessageToValue)
+// convert to. Every codec decodes to those same types, with every number
+// as a float64, so data can move between formats without changing shape:
+// a recording saved as JSON can be loaded and re-saved as MessagePack.
+//
+// Components:
+//   - Codec: Interface for serialization formats
+//   - JSON: The default, human-readable format
+//   - codec/msgpack: Compact binary MessagePack format
+//   - codec/protobuf: Protocol Buffers, as google.protobuf.Value
+//   - MarshalMessages/UnmarshalMessages: Encode message history
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// Codec serializes plain data values.
+//
+// Implementations must accept any value encoding/json can marshal, and
+// may convert values they don't support natively with Normalize.
+type Codec interface {
+	// Name identifies the format, e.g. "json"
+	Name() string
+	// Extension is the file extension for the format, including the dot
+	Extension() string
+	// Marshal encodes value
+	Marshal(value interface{}) ([]byte, error)
+	// Unmarshal decodes data into plain data values
+	Unmarshal(data []byte) (interface{}, error)
+}
+
+// JSONCodec encodes values as JSON.
+type JSONCodec struct {
+	// Indent pretty-prints the output with two-space indentation
+	Indent bool
+}
+
+// JSON is the default codec: indented JSON, for human-readable debugging.
+var JSON Codec = JSONCodec{Indent: true}
+
+// Name returns "json".
+func (c JSONCodec) Name() string {
+	return "json"
+}
+
+// Extension returns ".json".
+func (c JSONCodec) Extension() string {
+	return ".json"
+}
+
+// Marshal encodes value as JSON.
+func (c JSONCodec) Marshal(value interface{}) ([]byte, error) {
+	if c.Indent {
+		return json.MarshalIndent(value, "", "  ")
+	}
+	return json.Marshal(value)
+}
+
+// Unmarshal decodes JSON into plain data values.
+func (c JSONCodec) Unmarshal(data []byte) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Normalize converts value to the plain data values encoding/json would
+// decode it to, e.g. structs to maps and time.Time to strings. Binary
+// codecs use it for values they can't encode natively.
+func Normalize(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// MessageToValue converts message to plain data with the same keys as its
+// JSON encoding.
+func MessageToValue(message *agenkit.Message) (map[string]interface{}, error) {
+	metadata := message.Metadata
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	value := map[string]interface{}{
+		"role":      message.Role,
+		"content":   message.Content,
+		"metadata":  metadata,
+		"timestamp": message.Timestamp.Format(time.RFC3339Nano),
+	}
+	if message.ID != "" {
+		value["id"] = message.ID
+	}
+	if message.ParentID != "" {
+		value["parent_id"] = message.ParentID
+	}
+	if len(message.ToolCalls) > 0 {
+		toolCalls, err := Normalize(message.ToolCalls)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode tool calls: %w", err)
+		}
+		value["tool_calls"] = toolCalls
+	}
+	return value, nil
+}
+
+// MessageFromValue reverses MessageToValue.
+func MessageFromValue(value interface{}) (*agenkit.Message, error) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("message must be a map, got %T", value)
+	}
+
+	message := &agenkit.Message{Content: fields["content"], Metadata: make(map[string]interface{})}
+	message.ID, _ = fields["id"].(string)
+	message.ParentID, _ = fields["parent_id"].(string)
+	message.Role, _ = fields["role"].(string)
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		message.Metadata = metadata
+	}
+	if timestamp, ok := fields["timestamp"].(string); ok {
+		parsed, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message timestamp: %w", err)
+		}
+		message.Timestamp = parsed
+	}
+	if toolCalls, ok := fields["tool_calls"]; ok {
+		data, err := json.Marshal(toolCalls)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tool calls: %w", err)
+		}
+		if err := json.Unmarshal(data, &message.ToolCalls); err != nil {
+			return nil, fmt.Errorf("invalid tool calls: %w", err)
+		}
+	}
+	return message, nil
+}
+
+// MarshalMessages encodes a message history with c (nil uses JSON).
+//
+// Example:
+//
+//	data, err := codec.MarshalMessages(msgpack.Codec, history)
+func MarshalMessages(c Codec, messages []*agenkit.Message) ([]byte, error) {
+	if c == nil {
+		c = JSON
+	}
+	values := make([]interface{}, len(messages))
+	for i, message := range messages {
+		value, err := MessageToValue(message)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		values[i] = value
+	}
+	return c.Marshal(values)
+}
+
+// UnmarshalMessages decodes a message history encoded by MarshalMessages
+// with the same codec (nil uses JSON).
+func UnmarshalMessages(c Codec, data []byte) ([]*agenkit.Message, error) {
+	if c == nil {
+		c = JSON
+	}
+	value, err := c.Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s messages: %w", c.Name(), err)
+	}
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("messages must be a list, got %T", value)
+	}
+
+	messages := make([]*agenkit.Message, len(values))
+	for i, value := range values {
+		message, err := MessageFromValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		messages[i] = message
+	}
+	return messages, nil
+}
@@ -0,0 +1,109 @@
+package codec_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+	"github.com/scttfrdmn/agenkit-go/codec"
+	"github.com/scttfrdmn/agenkit-go/codec/msgpack"
+	"github.com/scttfrdmn/agenkit-go/codec/protobuf"
+)
+
+var codecs = []codec.Codec{codec.JSON, codec.JSONCodec{}, msgpack.Codec, protobuf.Codec}
+
+func sampleMessages() []*agenkit.Message {
+	user := agenkit.NewMessage("user", "What's the weather in Paris?")
+	user.ID = "m1"
+	user.Timestamp = time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	user.Metadata = map[string]interface{}{"tokens": 7.0, "tags": []interface{}{"a", "b"}}
+
+	reply := agenkit.NewMessage("assistant", "Sunny, 21°C")
+	reply.ParentID = "m1"
+	reply.Timestamp = user.Timestamp.Add(time.Second)
+	reply.ToolCalls = []agenkit.ToolCall{{ID: "call1", Name: "weather", Arguments: map[string]interface{}{"city": "Paris"}}}
+	return []*agenkit.Message{user, reply}
+}
+
+func TestMessagesRoundTrip(t *testing.T) {
+	messages := sampleMessages()
+	for _, c := range codecs {
+		t.Run(c.Name(), func(t *testing.T) {
+			data, err := codec.MarshalMessages(c, messages)
+			if err != nil {
+				t.Fatalf("MarshalMessages failed: %v", err)
+			}
+			decoded, err := codec.UnmarshalMessages(c, data)
+			if err != nil {
+				t.Fatalf("UnmarshalMessages failed: %v", err)
+			}
+			if len(decoded) != 2 {
+				t.Fatalf("expected 2 messages, got %d", len(decoded))
+			}
+			for i, message := range decoded {
+				want := messages[i]
+				if message.Role != want.Role || message.Content != want.Content || message.ID != want.ID ||
+					message.ParentID != want.ParentID || !message.Timestamp.Equal(want.Timestamp) {
+					t.Errorf("message %d: got %+v, want %+v", i, message, want)
+				}
+			}
+			if !reflect.DeepEqual(decoded[0].Metadata, messages[0].Metadata) {
+				t.Errorf("metadata changed: %v", decoded[0].Metadata)
+			}
+			if !reflect.DeepEqual(decoded[1].ToolCalls, messages[1].ToolCalls) {
+				t.Errorf("tool calls changed: %+v", decoded[1].ToolCalls)
+			}
+		})
+	}
+}
+
+func TestCrossCodecRoundTrip(t *testing.T) {
+	// Decoded values are the same in every format, so data decoded with
+	// one codec re-encodes with another unchanged
+	value := map[string]interface{}{
+		"name":  "session",
+		"count": 3,
+		"score": 0.25,
+		"nil":   nil,
+		"items": []map[string]interface{}{{"ok": true}, {"ok": false}},
+		"when":  time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	want, err := codec.Normalize(value)
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+
+	for _, from := range codecs {
+		for _, to := range codecs {
+			data, err := from.Marshal(value)
+			if err != nil {
+				t.Fatalf("%s Marshal failed: %v", from.Name(), err)
+			}
+			decoded, err := from.Unmarshal(data)
+			if err != nil {
+				t.Fatalf("%s Unmarshal failed: %v", from.Name(), err)
+			}
+			data, err = to.Marshal(decoded)
+			if err != nil {
+				t.Fatalf("%s Marshal failed: %v", to.Name(), err)
+			}
+			got, err := to.Unmarshal(data)
+			if err != nil {
+				t.Fatalf("%s Unmarshal failed: %v", to.Name(), err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s -> %s: got %v, want %v", from.Name(), to.Name(), got, want)
+			}
+		}
+	}
+}
+
+func TestUnmarshalMessages_Invalid(t *testing.T) {
+	if _, err := codec.UnmarshalMessages(nil, []byte(`{"role": "user"}`)); err == nil {
+		t.Error("expected error for a non-list history")
+	}
+	if _, err := codec.UnmarshalMessages(nil, []byte(`[{"timestamp": "yesterday"}]`)); err == nil {
+		t.Error("expected error for an invalid timestamp")
+	}
+}
@@ -0,0 +1,384 @@
+// Package msgpack provides a MessagePack codec for recordings and message
+// history.
+//
+// MessagePack is a compact binary encoding of the same data model as
+// JSON, typically a third smaller and faster to encode and decode, so it
+// suits high-volume recording. Values are decoded as encoding/json would
+// decode the equivalent JSON (every number as a float64), so recordings
+// can move between the two formats unchanged.
+//
+// Example:
+//
+//	storage := evaluation.NewLocalRecordingStorage("./recordings")
+//	storage.SetCodec(msgpack.Codec)
+package msgpack
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/scttfrdmn/agenkit-go/codec"
+)
+
+// Codec is the MessagePack codec.
+var Codec codec.Codec = msgpackCodec{}
+
+// msgpackCodec implements codec.Codec.
+type msgpackCodec struct{}
+
+// Name returns "msgpack".
+func (msgpackCodec) Name() string {
+	return "msgpack"
+}
+
+// Extension returns ".msgpack".
+func (msgpackCodec) Extension() string {
+	return ".msgpack"
+}
+
+// Marshal encodes value as MessagePack. Map keys are written in sorted
+// order, so equal values encode to equal bytes.
+func (msgpackCodec) Marshal(value interface{}) ([]byte, error) {
+	return appendValue(nil, value)
+}
+
+// Unmarshal decodes MessagePack into plain data values.
+func (msgpackCodec) Unmarshal(data []byte) (interface{}, error) {
+	d := &decoder{data: data}
+	value, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("msgpack: %d trailing bytes", len(d.data)-d.pos)
+	}
+	return value, nil
+}
+
+// appendValue appends the encoding of value to buf.
+func appendValue(buf []byte, value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, 0xc0), nil
+	case bool:
+		if v {
+			return append(buf, 0xc3), nil
+		}
+		return append(buf, 0xc2), nil
+	case string:
+		return appendString(buf, v), nil
+	case float64:
+		return appendFloat(buf, v)
+	case float32:
+		return appendFloat(buf, float64(v))
+	case int:
+		return appendInt(buf, int64(v)), nil
+	case int8:
+		return appendInt(buf, int64(v)), nil
+	case int16:
+		return appendInt(buf, int64(v)), nil
+	case int32:
+		return appendInt(buf, int64(v)), nil
+	case int64:
+		return appendInt(buf, v), nil
+	case uint8:
+		return appendUint(buf, uint64(v)), nil
+	case uint16:
+		return appendUint(buf, uint64(v)), nil
+	case uint32:
+		return appendUint(buf, uint64(v)), nil
+	case uint64:
+		return appendUint(buf, v), nil
+	case uint:
+		return appendUint(buf, uint64(v)), nil
+	case []interface{}:
+		buf = appendLength(buf, len(v), 0x90, 0xdc, 0xdd)
+		for _, item := range v {
+			var err error
+			if buf, err = appendValue(buf, item); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case []string:
+		buf = appendLength(buf, len(v), 0x90, 0xdc, 0xdd)
+		for _, item := range v {
+			buf = appendString(buf, item)
+		}
+		return buf, nil
+	case []map[string]interface{}:
+		buf = appendLength(buf, len(v), 0x90, 0xdc, 0xdd)
+		for _, item := range v {
+			var err error
+			if buf, err = appendValue(buf, item); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf = appendLength(buf, len(v), 0x80, 0xde, 0xdf)
+		for _, key := range keys {
+			buf = appendString(appendString(buf, key), v[key])
+		}
+		return buf, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf = appendLength(buf, len(v), 0x80, 0xde, 0xdf)
+		for _, key := range keys {
+			buf = appendString(buf, key)
+			var err error
+			if buf, err = appendValue(buf, v[key]); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	default:
+		// Structs, typed maps and slices, time.Time, []byte: encode as
+		// the JSON data model would represent them
+		normalized, err := codec.Normalize(v)
+		if err != nil {
+			return nil, fmt.Errorf("msgpack: cannot encode %T: %w", v, err)
+		}
+		return appendValue(buf, normalized)
+	}
+}
+
+// appendString appends a str-format string.
+func appendString(buf []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, 0xda), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xdb), uint32(n))
+	}
+	return append(buf, s...)
+}
+
+// appendLength appends an array or map header: fix is the fixarray or
+// fixmap prefix, and code16 and code32 the 16 and 32-bit formats.
+func appendLength(buf []byte, n int, fix, code16, code32 byte) []byte {
+	switch {
+	case n < 16:
+		return append(buf, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(buf, code32), uint32(n))
+	}
+}
+
+// appendFloat appends f, as an integer when it is one, which is both
+// smaller and what JSON would have written.
+func appendFloat(buf []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("msgpack: unsupported value %v", f)
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return appendInt(buf, int64(f)), nil
+	}
+	return binary.BigEndian.AppendUint64(append(buf, 0xcb), math.Float64bits(f)), nil
+}
+
+// appendInt appends i in the smallest integer format.
+func appendInt(buf []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendUint(buf, uint64(i))
+	case i >= -32:
+		return append(buf, byte(i))
+	case i >= math.MinInt8:
+		return append(buf, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(i))
+	}
+}
+
+// appendUint appends u in the smallest unsigned integer format.
+func appendUint(buf []byte, u uint64) []byte {
+	switch {
+	case u <= math.MaxInt8:
+		return append(buf, byte(u))
+	case u <= math.MaxUint8:
+		return append(buf, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xce), uint32(u))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcf), u)
+	}
+}
+
+// errTruncated is returned when data ends mid-value.
+var errTruncated = errors.New("msgpack: unexpected end of data")
+
+// decoder reads values from data.
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+// take returns the next n bytes.
+func (d *decoder) take(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, errTruncated
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// uint reads an n-byte big-endian unsigned integer.
+func (d *decoder) uint(n int) (uint64, error) {
+	b, err := d.take(n)
+	if err != nil {
+		return 0, err
+	}
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+// value decodes the next value.
+func (d *decoder) value() (interface{}, error) {
+	b, err := d.take(1)
+	if err != nil {
+		return nil, err
+	}
+	code := b[0]
+
+	switch {
+	case code <= 0x7f:
+		return float64(code), nil
+	case code >= 0xe0:
+		return float64(int8(code)), nil
+	case code&0xe0 == 0xa0:
+		return d.str(int(code & 0x1f))
+	case code&0xf0 == 0x90:
+		return d.array(int(code & 0x0f))
+	case code&0xf0 == 0x80:
+		return d.fields(int(code & 0x0f))
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uint(1 << (code - 0xcc))
+		return float64(u), err
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (code - 0xd0)
+		u, err := d.uint(size)
+		// Sign-extend from the encoded width
+		shift := 64 - 8*size
+		return float64(int64(u<<shift) >> shift), err
+	case 0xca:
+		u, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := d.uint(8)
+		return math.Float64frombits(u), err
+	case 0xd9, 0xda, 0xdb, 0xc4, 0xc5, 0xc6:
+		// str8/16/32, and bin8/16/32 read as strings
+		var size int
+		if code >= 0xd9 {
+			size = 1 << (code - 0xd9)
+		} else {
+			size = 1 << (code - 0xc4)
+		}
+		n, err := d.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		return d.str(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (code - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(int(n))
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (code - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.fields(int(n))
+	default:
+		return nil, fmt.Errorf("msgpack: unsupported type code 0x%02x", code)
+	}
+}
+
+// str reads an n-byte string.
+func (d *decoder) str(n int) (interface{}, error) {
+	b, err := d.take(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// array reads n values.
+func (d *decoder) array(n int) (interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errTruncated // every value takes at least a byte
+	}
+	items := make([]interface{}, n)
+	for i := range items {
+		item, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// fields reads n key-value pairs with string keys.
+func (d *decoder) fields(n int) (interface{}, error) {
+	if 2*n > len(d.data)-d.pos {
+		return nil, errTruncated
+	}
+	fields := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("msgpack: map key must be a string, got %T", key)
+		}
+		if fields[name], err = d.value(); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
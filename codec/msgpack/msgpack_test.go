@@ -0,0 +1,85 @@
+package msgpack
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshal_Formats(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{5, []byte{0x05}},
+		{-1, []byte{0xff}},
+		{200, []byte{0xcc, 0xc8}},
+		{-200, []byte{0xd1, 0xff, 0x38}},
+		{2.0, []byte{0x02}},
+		{"hi", []byte{0xa2, 'h', 'i'}},
+		{[]interface{}{1, "a"}, []byte{0x92, 0x01, 0xa1, 'a'}},
+		{map[string]interface{}{"b": 2, "a": 1}, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x02}},
+	}
+	for _, tt := range tests {
+		got, err := Codec.Marshal(tt.value)
+		if err != nil {
+			t.Fatalf("Marshal(%v) failed: %v", tt.value, err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("Marshal(%v) = % x, want % x", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	long := strings.Repeat("x", 70000)
+	items := make([]interface{}, 20)
+	for i := range items {
+		items[i] = float64(i * 1000)
+	}
+	value := map[string]interface{}{
+		"float":    1.5,
+		"negative": -1e10,
+		"big":      float64(math.MaxUint32 + 1),
+		"long":     long,
+		"items":    items,
+		"nested":   map[string]interface{}{"ok": false, "none": nil},
+	}
+
+	data, err := Codec.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	got, err := Codec.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, value) {
+		t.Errorf("round trip changed value")
+	}
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"empty":     {},
+		"truncated": {0xa5, 'a'},
+		"trailing":  {0x01, 0x02},
+		"int key":   {0x81, 0x01, 0x01},
+		"ext type":  {0xd4, 0x01, 0x01},
+		"huge len":  {0xdd, 0xff, 0xff, 0xff, 0xff},
+	} {
+		if _, err := Codec.Unmarshal(data); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestMarshal_NaN(t *testing.T) {
+	if _, err := Codec.Marshal(math.NaN()); err == nil {
+		t.Error("expected error for NaN")
+	}
+}
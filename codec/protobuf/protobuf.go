@@ -0,0 +1,104 @@
+// Package protobuf provides a Protocol Buffers codec for recordings and
+// message history.
+//
+// Values are encoded as a google.protobuf.Value, the well-known type for
+// JSON-like data, so any protobuf runtime can read them without a
+// generated schema. Like JSON, every number is decoded as a float64.
+//
+// Example:
+//
+//	data, err := codec.MarshalMessages(protobuf.Codec, history)
+package protobuf
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/scttfrdmn/agenkit-go/codec"
+)
+
+// Codec is the Protocol Buffers codec.
+var Codec codec.Codec = protobufCodec{}
+
+// protobufCodec implements codec.Codec.
+type protobufCodec struct{}
+
+// Name returns "protobuf".
+func (protobufCodec) Name() string {
+	return "protobuf"
+}
+
+// Extension returns ".pb".
+func (protobufCodec) Extension() string {
+	return ".pb"
+}
+
+// Marshal encodes value as a serialized google.protobuf.Value.
+func (protobufCodec) Marshal(value interface{}) ([]byte, error) {
+	pbValue, err := toValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(pbValue)
+}
+
+// toValue converts value to a google.protobuf.Value.
+func toValue(value interface{}) (*structpb.Value, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		return listValue(len(v), func(i int) interface{} { return v[i] })
+	case []map[string]interface{}:
+		return listValue(len(v), func(i int) interface{} { return v[i] })
+	case []string:
+		return listValue(len(v), func(i int) interface{} { return v[i] })
+	case map[string]interface{}:
+		fields := make(map[string]*structpb.Value, len(v))
+		for key, item := range v {
+			field, err := toValue(item)
+			if err != nil {
+				return nil, err
+			}
+			fields[key] = field
+		}
+		return structpb.NewStructValue(&structpb.Struct{Fields: fields}), nil
+	}
+
+	if pbValue, err := structpb.NewValue(value); err == nil {
+		return pbValue, nil
+	}
+	// Structs, typed maps, time.Time: convert to the JSON data model first
+	normalized, err := codec.Normalize(value)
+	if err != nil {
+		return nil, fmt.Errorf("protobuf: cannot encode %T: %w", value, err)
+	}
+	pbValue, err := structpb.NewValue(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("protobuf: cannot encode %T: %w", value, err)
+	}
+	return pbValue, nil
+}
+
+// listValue converts n items, read with item, to a list value.
+func listValue(n int, item func(int) interface{}) (*structpb.Value, error) {
+	values := make([]*structpb.Value, n)
+	for i := range values {
+		value, err := toValue(item(i))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return structpb.NewListValue(&structpb.ListValue{Values: values}), nil
+}
+
+// Unmarshal decodes a serialized google.protobuf.Value into plain data
+// values.
+func (protobufCodec) Unmarshal(data []byte) (interface{}, error) {
+	var pbValue structpb.Value
+	if err := proto.Unmarshal(data, &pbValue); err != nil {
+		return nil, err
+	}
+	return pbValue.AsInterface(), nil
+}
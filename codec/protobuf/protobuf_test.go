@@ -0,0 +1,64 @@
+package protobuf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestRoundTrip(t *testing.T) {
+	value := map[string]interface{}{
+		"name":  "session",
+		"count": 3,
+		"items": []map[string]interface{}{{"ok": true}},
+		"tags":  []string{"a", "b"},
+		"none":  nil,
+	}
+	data, err := Codec.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	got, err := Codec.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	want := map[string]interface{}{
+		"name":  "session",
+		"count": 3.0,
+		"items": []interface{}{map[string]interface{}{"ok": true}},
+		"tags":  []interface{}{"a", "b"},
+		"none":  nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Encoding is deterministic
+	again, _ := Codec.Marshal(value)
+	if !bytes.Equal(data, again) {
+		t.Error("expected equal values to encode to equal bytes")
+	}
+}
+
+func TestMarshal_IsGoogleProtobufValue(t *testing.T) {
+	data, err := Codec.Marshal(map[string]interface{}{"role": "user"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var pbValue structpb.Value
+	if err := proto.Unmarshal(data, &pbValue); err != nil {
+		t.Fatalf("not a google.protobuf.Value: %v", err)
+	}
+	if pbValue.GetStructValue().GetFields()["role"].GetStringValue() != "user" {
+		t.Errorf("unexpected value %v", &pbValue)
+	}
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	if _, err := Codec.Unmarshal([]byte{0xff, 0xff}); err == nil {
+		t.Error("expected error for invalid data")
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"go.uber.org/goleak"
+
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
 
@@ -233,6 +235,30 @@ func TestParallelAgentConcurrency(t *testing.T) {
 	}
 }
 
+func TestParallelAgentCancellationNoLeak(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	agent1 := &TestAgent{name: "agent1", response: "r1", delay: time.Hour}
+	agent2 := &TestAgent{name: "agent2", response: "r2", delay: time.Hour}
+
+	parallel, err := NewParallelAgent("parallel", agent1, agent2)
+	if err != nil {
+		t.Fatalf("Failed to create parallel agent: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err = parallel.Process(ctx, agenkit.NewMessage("user", "test"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected prompt return after cancellation, took %v", elapsed)
+	}
+}
+
 // Fallback Agent Tests
 
 func TestFallbackAgentFirstSucceeds(t *testing.T) {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
@@ -81,15 +80,21 @@ type AgentResult struct {
 
 // Process executes all agents in parallel and combines their results.
 func (p *ParallelAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	// Cancel in-flight agents if we return early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered so workers never block after Process returns
 	results := make(chan *AgentResult, len(p.agents))
-	var wg sync.WaitGroup
 
-	// Start all agents concurrently
+	// Start agents concurrently, stopping if the context is cancelled
+	launched := 0
 	for _, agent := range p.agents {
-		wg.Add(1)
+		if ctx.Err() != nil {
+			break
+		}
+		launched++
 		go func(a agenkit.Agent) {
-			defer wg.Done()
-
 			result, err := a.Process(ctx, message)
 			results <- &AgentResult{
 				AgentName: a.Name(),
@@ -99,16 +104,19 @@ func (p *ParallelAgent) Process(ctx context.Context, message *agenkit.Message) (
 		}(agent)
 	}
 
-	// Wait for all agents to complete
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect results
+	// Collect results until all launched agents report or ctx is cancelled
 	var responses []*AgentResult
-	for result := range results {
-		responses = append(responses, result)
+	for received := 0; received < launched; received++ {
+		select {
+		case result := <-results:
+			responses = append(responses, result)
+		case <-ctx.Done():
+			return nil, fmt.Errorf("parallel execution cancelled: %w", ctx.Err())
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("parallel execution cancelled: %w", ctx.Err())
 	}
 
 	// Check for errors
@@ -145,7 +153,7 @@ func (p *ParallelAgent) combineResponses(results []*AgentResult) *agenkit.Messag
 		}
 	}
 
-	response := agenkit.NewMessage("agent", strings.Join(contentParts, "\n"))
+	response := agenkit.NewMessage(agenkit.RoleAssistant, strings.Join(contentParts, "\n"))
 	response.Metadata = combinedMetadata
 	return response
 }
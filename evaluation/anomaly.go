@@ -0,0 +1,364 @@
+// Package evaluation provides anomaly detection for live agent metrics.
+//
+// Flags latency spikes, error bursts and quality drops against adaptive
+// baselines as session results arrive.
+package evaluation
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Anomaly metric names observed by AnomalyDetector.
+const (
+	// AnomalyMetricLatency is per-session latency in milliseconds
+	AnomalyMetricLatency = "latency_ms"
+	// AnomalyMetricErrorRate is the error rate over the recent session window
+	AnomalyMetricErrorRate = "error_rate"
+	// AnomalyMetricQuality is the mean quality score of a session
+	AnomalyMetricQuality = "quality"
+)
+
+// AnomalyDirection describes which way an anomalous value deviated.
+type AnomalyDirection string
+
+const (
+	// AnomalyDirectionSpike indicates the value jumped above its baseline
+	AnomalyDirectionSpike AnomalyDirection = "spike"
+	// AnomalyDirectionDrop indicates the value fell below its baseline
+	AnomalyDirectionDrop AnomalyDirection = "drop"
+)
+
+// AnomalyEvent describes a statistical outlier detected in the result stream.
+type AnomalyEvent struct {
+	MetricName string
+	Value      float64
+	Expected   float64
+	StdDev     float64
+	ZScore     float64
+	Direction  AnomalyDirection
+	SessionID  string
+	Timestamp  time.Time
+}
+
+// ToDict converts the anomaly event to dictionary.
+func (e *AnomalyEvent) ToDict() map[string]interface{} {
+	return map[string]interface{}{
+		"metric_name": e.MetricName,
+		"value":       e.Value,
+		"expected":    e.Expected,
+		"std_dev":     e.StdDev,
+		"z_score":     e.ZScore,
+		"direction":   string(e.Direction),
+		"session_id":  e.SessionID,
+		"timestamp":   e.Timestamp.Format(time.RFC3339),
+	}
+}
+
+// AnomalyCallback is invoked for every detected anomaly.
+type AnomalyCallback func(event *AnomalyEvent)
+
+// AnomalyDetectorConfig configures an AnomalyDetector.
+type AnomalyDetectorConfig struct {
+	// Alpha is the EWMA smoothing factor in (0, 1] (default: 0.1).
+	// Higher values adapt the baseline faster.
+	Alpha float64
+	// Threshold is the number of standard deviations that counts as
+	// anomalous (default: 3.0)
+	Threshold float64
+	// WarmupSamples is the number of observations per metric before
+	// anomalies are reported (default: 10)
+	WarmupSamples int
+	// ErrorWindow is the number of recent sessions used to compute the
+	// error rate (default: 10)
+	ErrorWindow int
+	// MinDeviation is the minimum absolute deviation per metric before a
+	// value can be flagged, guarding against near-zero variance baselines
+	// (defaults: error_rate 0.2, quality 0.05, latency_ms 0)
+	MinDeviation map[string]float64
+	// OnAnomaly is called for each detected anomaly (optional)
+	OnAnomaly AnomalyCallback
+}
+
+// ewmaBaseline tracks an exponentially weighted mean and variance.
+type ewmaBaseline struct {
+	mean     float64
+	variance float64
+	count    int
+}
+
+// update folds value into the baseline using smoothing factor alpha.
+func (b *ewmaBaseline) update(value, alpha float64) {
+	if b.count == 0 {
+		b.mean = value
+		b.variance = 0
+		b.count = 1
+		return
+	}
+	diff := value - b.mean
+	b.mean += alpha * diff
+	b.variance = (1 - alpha) * (b.variance + alpha*diff*diff)
+	b.count++
+}
+
+// AnomalyDetector flags latency spikes, error bursts, and quality drops in a
+// stream of session results.
+//
+// Each metric keeps an EWMA baseline (mean and variance) that adapts as new
+// results arrive, so the detector follows gradual drift while still flagging
+// sudden deviations beyond Threshold standard deviations. This complements
+// RegressionDetector, which compares against a fixed per-deployment baseline.
+//
+// Example:
+//
+//	detector := NewAnomalyDetector(&AnomalyDetectorConfig{
+//	    OnAnomaly: func(e *AnomalyEvent) {
+//	        log.Printf("%s %s: %.2f (expected %.2f)", e.MetricName, e.Direction, e.Value, e.Expected)
+//	    },
+//	})
+//
+//	for result := range results {
+//	    detector.Observe(result)
+//	}
+type AnomalyDetector struct {
+	mu            sync.Mutex
+	alpha         float64
+	threshold     float64
+	warmupSamples int
+	errorWindow   int
+	minDeviation  map[string]float64
+	onAnomaly     AnomalyCallback
+	baselines     map[string]*ewmaBaseline
+	recentErrors  []bool
+	eventCount    int
+}
+
+// NewAnomalyDetector creates a new anomaly detector.
+//
+// Args:
+//
+//	config: Detector configuration (nil for defaults)
+func NewAnomalyDetector(config *AnomalyDetectorConfig) *AnomalyDetector {
+	if config == nil {
+		config = &AnomalyDetectorConfig{}
+	}
+
+	d := &AnomalyDetector{
+		alpha:         config.Alpha,
+		threshold:     config.Threshold,
+		warmupSamples: config.WarmupSamples,
+		errorWindow:   config.ErrorWindow,
+		onAnomaly:     config.OnAnomaly,
+		minDeviation: map[string]float64{
+			AnomalyMetricLatency:   0,
+			AnomalyMetricErrorRate: 0.2,
+			AnomalyMetricQuality:   0.05,
+		},
+		baselines:    make(map[string]*ewmaBaseline),
+		recentErrors: make([]bool, 0),
+	}
+
+	if d.alpha <= 0 || d.alpha > 1 {
+		d.alpha = 0.1
+	}
+	if d.threshold <= 0 {
+		d.threshold = 3.0
+	}
+	if d.warmupSamples <= 0 {
+		d.warmupSamples = 10
+	}
+	if d.errorWindow <= 0 {
+		d.errorWindow = 10
+	}
+	for metric, deviation := range config.MinDeviation {
+		d.minDeviation[metric] = deviation
+	}
+
+	return d
+}
+
+// Observe feeds a session result into the detector.
+//
+// Returns the anomalies detected for this result (empty if none). The
+// OnAnomaly callback, if configured, is invoked for each one.
+func (d *AnomalyDetector) Observe(result *SessionResult) []*AnomalyEvent {
+	d.mu.Lock()
+
+	events := make([]*AnomalyEvent, 0)
+	now := time.Now().UTC()
+
+	if latency, ok := sessionLatencyMs(result); ok {
+		if event := d.checkLocked(AnomalyMetricLatency, latency, true, false); event != nil {
+			events = append(events, event)
+		}
+	}
+
+	d.recentErrors = append(d.recentErrors, sessionHasErrors(result))
+	if len(d.recentErrors) > d.errorWindow {
+		d.recentErrors = d.recentErrors[len(d.recentErrors)-d.errorWindow:]
+	}
+	errorCount := 0
+	for _, failed := range d.recentErrors {
+		if failed {
+			errorCount++
+		}
+	}
+	errorRate := float64(errorCount) / float64(len(d.recentErrors))
+	if event := d.checkLocked(AnomalyMetricErrorRate, errorRate, true, false); event != nil {
+		events = append(events, event)
+	}
+
+	if quality, ok := sessionQuality(result); ok {
+		if event := d.checkLocked(AnomalyMetricQuality, quality, false, true); event != nil {
+			events = append(events, event)
+		}
+	}
+
+	for _, event := range events {
+		event.SessionID = result.SessionID
+		event.Timestamp = now
+	}
+	d.eventCount += len(events)
+	callback := d.onAnomaly
+
+	d.mu.Unlock()
+
+	if callback != nil {
+		for _, event := range events {
+			callback(event)
+		}
+	}
+
+	return events
+}
+
+// checkLocked compares value with the metric baseline, then folds it in.
+// Caller must hold the lock.
+func (d *AnomalyDetector) checkLocked(metric string, value float64, flagHigh, flagLow bool) *AnomalyEvent {
+	baseline, ok := d.baselines[metric]
+	if !ok {
+		baseline = &ewmaBaseline{}
+		d.baselines[metric] = baseline
+	}
+
+	var event *AnomalyEvent
+	if baseline.count >= d.warmupSamples {
+		stdDev := math.Sqrt(baseline.variance)
+		deviation := value - baseline.mean
+		limit := math.Max(d.threshold*stdDev, d.minDeviation[metric])
+
+		if math.Abs(deviation) > limit && ((deviation > 0 && flagHigh) || (deviation < 0 && flagLow)) {
+			zScore := math.Inf(1)
+			if stdDev > 0 {
+				zScore = deviation / stdDev
+			} else if deviation < 0 {
+				zScore = math.Inf(-1)
+			}
+
+			direction := AnomalyDirectionSpike
+			if deviation < 0 {
+				direction = AnomalyDirectionDrop
+			}
+
+			event = &AnomalyEvent{
+				MetricName: metric,
+				Value:      value,
+				Expected:   baseline.mean,
+				StdDev:     stdDev,
+				ZScore:     zScore,
+				Direction:  direction,
+			}
+		}
+	}
+
+	// Always adapt so sustained shifts become the new normal
+	baseline.update(value, d.alpha)
+
+	return event
+}
+
+// Baseline returns the current baseline mean, standard deviation, and sample
+// count for a metric.
+func (d *AnomalyDetector) Baseline(metricName string) (mean, stdDev float64, count int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	baseline, ok := d.baselines[metricName]
+	if !ok {
+		return 0, 0, 0
+	}
+	return baseline.mean, math.Sqrt(baseline.variance), baseline.count
+}
+
+// GetSummary returns summary information about the detector state.
+func (d *AnomalyDetector) GetSummary() map[string]interface{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	baselines := make(map[string]interface{}, len(d.baselines))
+	for metric, baseline := range d.baselines {
+		baselines[metric] = map[string]interface{}{
+			"mean":    baseline.mean,
+			"std_dev": math.Sqrt(baseline.variance),
+			"count":   baseline.count,
+		}
+	}
+
+	return map[string]interface{}{
+		"alpha":          d.alpha,
+		"threshold":      d.threshold,
+		"warmup_samples": d.warmupSamples,
+		"anomaly_count":  d.eventCount,
+		"baselines":      baselines,
+	}
+}
+
+// Reset clears all learned baselines.
+func (d *AnomalyDetector) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.baselines = make(map[string]*ewmaBaseline)
+	d.recentErrors = make([]bool, 0)
+	d.eventCount = 0
+}
+
+// sessionLatencyMs extracts session latency in milliseconds.
+//
+// Prefers a "latency_ms" measurement, then a duration-typed measurement
+// (seconds), then the session start/end times.
+func sessionLatencyMs(result *SessionResult) (float64, bool) {
+	if m := result.GetMetric(AnomalyMetricLatency); m != nil {
+		return m.Value, true
+	}
+	if durations := result.GetMetricsByType(MetricTypeDuration); len(durations) > 0 {
+		return durations[0].Value * 1000, true
+	}
+	if seconds := result.DurationSeconds(); seconds != nil {
+		return *seconds * 1000, true
+	}
+	return 0, false
+}
+
+// sessionHasErrors reports whether a session failed or recorded errors.
+func sessionHasErrors(result *SessionResult) bool {
+	switch result.Status {
+	case SessionStatusFailed, SessionStatusTimeout:
+		return true
+	}
+	return len(result.Errors) > 0
+}
+
+// sessionQuality returns the mean quality score measurement of a session.
+func sessionQuality(result *SessionResult) (float64, bool) {
+	scores := result.GetMetricsByType(MetricTypeQualityScore)
+	if len(scores) == 0 {
+		return 0, false
+	}
+	total := 0.0
+	for _, score := range scores {
+		total += score.Value
+	}
+	return total / float64(len(scores)), true
+}
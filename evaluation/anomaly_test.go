@@ -0,0 +1,157 @@
+package evaluation
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// latencySession builds a completed session with the given latency and quality.
+func latencySession(id int, latencyMs, quality float64) *SessionResult {
+	result := NewSessionResult(fmt.Sprintf("session-%d", id), "agent")
+	result.AddMetricMeasurement(NewMetricMeasurement(AnomalyMetricLatency, latencyMs, MetricTypeDuration))
+	result.AddMetricMeasurement(NewMetricMeasurement("response_quality", quality, MetricTypeQualityScore))
+	result.SetStatus(SessionStatusCompleted)
+	return result
+}
+
+func TestAnomalyDetector_Defaults(t *testing.T) {
+	detector := NewAnomalyDetector(nil)
+
+	summary := detector.GetSummary()
+	if summary["alpha"] != 0.1 {
+		t.Errorf("Expected default alpha 0.1, got %v", summary["alpha"])
+	}
+	if summary["threshold"] != 3.0 {
+		t.Errorf("Expected default threshold 3.0, got %v", summary["threshold"])
+	}
+	if summary["warmup_samples"] != 10 {
+		t.Errorf("Expected default warmup 10, got %v", summary["warmup_samples"])
+	}
+}
+
+func TestAnomalyDetector_DetectsLatencySpike(t *testing.T) {
+	var received []*AnomalyEvent
+	detector := NewAnomalyDetector(&AnomalyDetectorConfig{
+		OnAnomaly: func(e *AnomalyEvent) { received = append(received, e) },
+	})
+
+	for i := 0; i < 20; i++ {
+		latency := 100.0 + float64(i%3)*5
+		if events := detector.Observe(latencySession(i, latency, 0.9)); len(events) != 0 {
+			t.Fatalf("Expected no anomalies during steady state, got %v", events[0].ToDict())
+		}
+	}
+
+	events := detector.Observe(latencySession(99, 1000, 0.9))
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 anomaly, got %d", len(events))
+	}
+
+	event := events[0]
+	if event.MetricName != AnomalyMetricLatency {
+		t.Errorf("Expected latency anomaly, got %q", event.MetricName)
+	}
+	if event.Direction != AnomalyDirectionSpike {
+		t.Errorf("Expected spike, got %q", event.Direction)
+	}
+	if event.SessionID != "session-99" {
+		t.Errorf("Expected session-99, got %q", event.SessionID)
+	}
+	if event.ZScore <= 3 {
+		t.Errorf("Expected z-score above threshold, got %.2f", event.ZScore)
+	}
+	if len(received) != 1 {
+		t.Errorf("Expected callback to fire once, got %d", len(received))
+	}
+}
+
+func TestAnomalyDetector_DetectsQualityDrop(t *testing.T) {
+	detector := NewAnomalyDetector(&AnomalyDetectorConfig{WarmupSamples: 5})
+
+	for i := 0; i < 10; i++ {
+		detector.Observe(latencySession(i, 100, 0.9))
+	}
+
+	events := detector.Observe(latencySession(10, 100, 0.4))
+	if len(events) != 1 || events[0].MetricName != AnomalyMetricQuality {
+		t.Fatalf("Expected a single quality anomaly, got %d events", len(events))
+	}
+	if events[0].Direction != AnomalyDirectionDrop {
+		t.Errorf("Expected drop, got %q", events[0].Direction)
+	}
+	if !math.IsInf(events[0].ZScore, -1) {
+		t.Errorf("Expected -Inf z-score for zero-variance baseline, got %v", events[0].ZScore)
+	}
+
+	// Quality improvements are not anomalies
+	detector.Reset()
+	for i := 0; i < 10; i++ {
+		detector.Observe(latencySession(i, 100, 0.5))
+	}
+	if events := detector.Observe(latencySession(10, 100, 1.0)); len(events) != 0 {
+		t.Errorf("Expected quality increase not to be flagged, got %d events", len(events))
+	}
+}
+
+func TestAnomalyDetector_DetectsErrorBurst(t *testing.T) {
+	detector := NewAnomalyDetector(&AnomalyDetectorConfig{WarmupSamples: 5, ErrorWindow: 5})
+
+	for i := 0; i < 10; i++ {
+		detector.Observe(latencySession(i, 100, 0.9))
+	}
+
+	var flagged bool
+	for i := 10; i < 13; i++ {
+		result := NewSessionResult(fmt.Sprintf("session-%d", i), "agent")
+		result.AddMetricMeasurement(NewMetricMeasurement(AnomalyMetricLatency, 100, MetricTypeDuration))
+		result.AddError("timeout", "upstream timed out", nil)
+		result.SetStatus(SessionStatusFailed)
+
+		for _, event := range detector.Observe(result) {
+			if event.MetricName == AnomalyMetricErrorRate {
+				flagged = true
+			}
+		}
+	}
+
+	if !flagged {
+		t.Error("Expected error burst to be flagged")
+	}
+}
+
+func TestAnomalyDetector_AdaptsBaseline(t *testing.T) {
+	detector := NewAnomalyDetector(&AnomalyDetectorConfig{Alpha: 0.5, WarmupSamples: 3})
+
+	for i := 0; i < 5; i++ {
+		detector.Observe(latencySession(i, 100, 0.9))
+	}
+	for i := 5; i < 30; i++ {
+		detector.Observe(latencySession(i, 200, 0.9))
+	}
+
+	mean, _, count := detector.Baseline(AnomalyMetricLatency)
+	if math.Abs(mean-200) > 1 {
+		t.Errorf("Expected baseline to adapt towards 200, got %.2f", mean)
+	}
+	if count != 30 {
+		t.Errorf("Expected 30 samples, got %d", count)
+	}
+
+	if events := detector.Observe(latencySession(30, 200, 0.9)); len(events) != 0 {
+		t.Errorf("Expected new normal not to be flagged, got %d events", len(events))
+	}
+}
+
+func TestAnomalyDetector_NoAnomaliesDuringWarmup(t *testing.T) {
+	detector := NewAnomalyDetector(nil)
+
+	detector.Observe(latencySession(0, 100, 0.9))
+	if events := detector.Observe(latencySession(1, 10000, 0.1)); len(events) != 0 {
+		t.Errorf("Expected no anomalies during warmup, got %d", len(events))
+	}
+
+	if _, _, count := detector.Baseline("unknown"); count != 0 {
+		t.Errorf("Expected empty baseline for unknown metric, got count %d", count)
+	}
+}
@@ -5,12 +5,24 @@ package evaluation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
 	"time"
 )
 
+// ErrConstraintsUnsatisfied is returned when no sampled configuration
+// satisfies a search space's constraints.
+var ErrConstraintsUnsatisfied = errors.New("no sampled configuration satisfies the search space constraints")
+
+// maxSampleAttempts bounds how many samples TrySample draws while looking
+// for one that satisfies the constraints.
+const maxSampleAttempts = 1000
+
 // AcquisitionFunction specifies the acquisition function type for Bayesian optimization.
 type AcquisitionFunction string
 
@@ -23,6 +35,17 @@ const (
 	AcquisitionPI AcquisitionFunction = "pi"
 )
 
+// EvalAggregation specifies how repeated evaluations of one configuration
+// are combined into its score.
+type EvalAggregation string
+
+const (
+	// EvalAggregationMean averages the evaluations
+	EvalAggregationMean EvalAggregation = "mean"
+	// EvalAggregationMedian takes the median, which resists outlier runs
+	EvalAggregationMedian EvalAggregation = "median"
+)
+
 // ParameterType specifies the type of a hyperparameter.
 type ParameterType string
 
@@ -46,8 +69,13 @@ type ParameterSpec struct {
 }
 
 // SearchSpace defines the hyperparameter search space.
+//
+// Constraints added with AddConstraint express dependencies between
+// parameters; sampling rejects configurations that violate them, so
+// optimizers never spend evaluations on invalid combinations.
 type SearchSpace struct {
-	Parameters map[string]ParameterSpec
+	Parameters  map[string]ParameterSpec
+	constraints []func(config map[string]interface{}) bool
 }
 
 // NewSearchSpace creates a new search space.
@@ -95,8 +123,136 @@ func (s *SearchSpace) AddCategorical(name string, values []string) {
 	}
 }
 
-// Sample generates a random configuration from the search space.
+// AddConstraint adds a predicate every sampled configuration must satisfy.
+//
+// Example:
+//
+//	space.AddCategorical("optimizer", []string{"sgd", "adam"})
+//	space.AddContinuous("momentum", 0.0, 0.99)
+//	// Only explore non-zero momentum with SGD
+//	space.AddConstraint(func(config map[string]interface{}) bool {
+//	    return config["optimizer"] == "sgd" || config["momentum"].(float64) < 0.01
+//	})
+func (s *SearchSpace) AddConstraint(constraint func(config map[string]interface{}) bool) {
+	s.constraints = append(s.constraints, constraint)
+}
+
+// Satisfies reports whether config satisfies every constraint.
+func (s *SearchSpace) Satisfies(config map[string]interface{}) bool {
+	for _, constraint := range s.constraints {
+		if !constraint(config) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks an externally supplied configuration against the search
+// space: every parameter must be present with a value of the right type
+// inside its range or value set, no unknown parameters are allowed, and
+// every constraint must hold.
+func (s *SearchSpace) Validate(config map[string]interface{}) error {
+	names := make([]string, 0, len(s.Parameters))
+	for name := range s.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, ok := config[name]
+		if !ok {
+			return fmt.Errorf("missing parameter %q", name)
+		}
+		if err := s.Parameters[name].validate(value); err != nil {
+			return fmt.Errorf("parameter %q: %w", name, err)
+		}
+	}
+	for name := range config {
+		if _, ok := s.Parameters[name]; !ok {
+			return fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+	for i, constraint := range s.constraints {
+		if !constraint(config) {
+			return fmt.Errorf("configuration violates constraint %d", i)
+		}
+	}
+	return nil
+}
+
+// validate checks a single parameter value against the spec.
+func (p ParameterSpec) validate(value interface{}) error {
+	switch p.Type {
+	case ParamTypeContinuous:
+		v, ok := numericValue(value)
+		if !ok {
+			return fmt.Errorf("expected a number, got %T", value)
+		}
+		if v < p.Low || v > p.High {
+			return fmt.Errorf("%v outside [%v, %v]", v, p.Low, p.High)
+		}
+	case ParamTypeInteger:
+		v, ok := numericValue(value)
+		if !ok || v != math.Trunc(v) {
+			return fmt.Errorf("expected an integer, got %v (%T)", value, value)
+		}
+		if v < p.Low || v > p.High {
+			return fmt.Errorf("%v outside [%v, %v]", v, p.Low, p.High)
+		}
+	case ParamTypeDiscrete, ParamTypeCategorical:
+		for _, allowed := range p.Values {
+			if reflect.DeepEqual(value, allowed) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v is not one of %v", value, p.Values)
+	}
+	return nil
+}
+
+// numericValue converts Go numeric types to float64.
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// TrySample generates a random configuration satisfying every constraint,
+// drawing up to 1000 samples. It returns ErrConstraintsUnsatisfied if none
+// of them do.
+func (s *SearchSpace) TrySample() (map[string]interface{}, error) {
+	for attempt := 0; attempt < maxSampleAttempts; attempt++ {
+		if config := s.sampleUnconstrained(); s.Satisfies(config) {
+			return config, nil
+		}
+	}
+	return nil, ErrConstraintsUnsatisfied
+}
+
+// Sample generates a random configuration from the search space that
+// satisfies every constraint. It returns nil if the constraints could not
+// be satisfied; use TrySample to get the error instead.
 func (s *SearchSpace) Sample() map[string]interface{} {
+	config, err := s.TrySample()
+	if err != nil {
+		return nil
+	}
+	return config
+}
+
+// sampleUnconstrained draws each parameter independently.
+func (s *SearchSpace) sampleUnconstrained() map[string]interface{} {
 	config := make(map[string]interface{})
 	for name, spec := range s.Parameters {
 		switch spec.Type {
@@ -120,12 +276,23 @@ type OptimizationResult struct {
 	StartTime   time.Time
 	EndTime     time.Time
 	Metadata    map[string]interface{}
+	// StoppedEarly is true if the run halted before NIterations because the
+	// best score plateaued
+	StoppedEarly bool
+	// StoppedAt is the number of iterations actually run when StoppedEarly
+	StoppedAt int
 }
 
 // OptimizationStep represents a single evaluation in the optimization.
 type OptimizationStep struct {
 	Config map[string]interface{}
-	Score  float64
+	// Score is the configuration's score, aggregated over NEvals runs
+	Score float64
+	// NEvals is the number of evaluations behind Score
+	NEvals int
+	// Variance is the sample variance of the evaluations (0 for a single
+	// evaluation)
+	Variance float64
 }
 
 // Duration returns the total optimization duration.
@@ -156,7 +323,26 @@ type ObjectiveFunc func(ctx context.Context, config map[string]interface{}) (flo
 //  3. Use acquisition function to select next config
 //  4. Evaluate new config
 //  5. Update statistics and repeat
+//
+// Besides the blocking Optimize loop, the optimizer supports an ask/tell
+// interface for asynchronous or distributed evaluation: call Suggest to get
+// the next configuration to try and Observe to report its score whenever the
+// evaluation completes. Observations accumulate across calls (including
+// calls to Optimize), so the surrogate keeps improving. Suggest and Observe
+// are safe for concurrent use.
+//
+// Example:
+//
+//	optimizer, _ := evaluation.NewBayesianOptimizer(evaluation.BayesianOptimizerConfig{
+//	    SearchSpace: space,
+//	    Maximize:    true,
+//	})
+//	config := optimizer.Suggest()
+//	jobID := submitEvalJob(config)
+//	// ... later, when the job finishes
+//	optimizer.Observe(config, results[jobID])
 type BayesianOptimizer struct {
+	mu          sync.Mutex
 	searchSpace *SearchSpace
 	objective   ObjectiveFunc
 	maximize    bool
@@ -164,6 +350,10 @@ type BayesianOptimizer struct {
 	nInitial    int
 	xi          float64 // Exploration parameter for EI/PI
 	kappa       float64 // Exploration parameter for UCB
+	patience    int
+	minDelta    float64
+	nEvals      int
+	aggregation EvalAggregation
 	history     []OptimizationStep
 	bestConfig  map[string]interface{}
 	bestScore   float64
@@ -172,12 +362,24 @@ type BayesianOptimizer struct {
 // BayesianOptimizerConfig contains configuration for BayesianOptimizer.
 type BayesianOptimizerConfig struct {
 	SearchSpace *SearchSpace
-	Objective   ObjectiveFunc
+	Objective   ObjectiveFunc // Required by Optimize; optional for Suggest/Observe
 	Maximize    bool
 	Acquisition AcquisitionFunction
 	NInitial    int
 	Xi          float64 // Exploration parameter for EI and PI (default: 0.01)
 	Kappa       float64 // Exploration parameter for UCB (default: 2.576)
+	// Patience stops Optimize early after this many consecutive iterations
+	// without the best score improving by more than MinDelta (0 = disabled).
+	// Early stopping never triggers during the initial random phase.
+	Patience int
+	MinDelta float64 // Minimum change that counts as an improvement (default: 0)
+	// NEvalsPerConfig evaluates each configuration this many times in
+	// Optimize to average out objective noise, such as LLM-judged scores
+	// (default: 1). The observed variance is kept in the history and
+	// makes the surrogate trust noisy observations less.
+	NEvalsPerConfig int
+	// Aggregation combines repeated evaluations (default: EvalAggregationMean)
+	Aggregation EvalAggregation
 }
 
 // NewBayesianOptimizer creates a new Bayesian optimizer.
@@ -185,9 +387,6 @@ func NewBayesianOptimizer(config BayesianOptimizerConfig) (*BayesianOptimizer, e
 	if config.SearchSpace == nil {
 		return nil, fmt.Errorf("search space is required")
 	}
-	if config.Objective == nil {
-		return nil, fmt.Errorf("objective function is required")
-	}
 
 	// Set defaults
 	if config.NInitial == 0 {
@@ -202,6 +401,19 @@ func NewBayesianOptimizer(config BayesianOptimizerConfig) (*BayesianOptimizer, e
 	if config.Acquisition == "" {
 		config.Acquisition = AcquisitionEI
 	}
+	if config.NEvalsPerConfig < 0 {
+		return nil, fmt.Errorf("NEvalsPerConfig must be non-negative, got %d", config.NEvalsPerConfig)
+	}
+	if config.NEvalsPerConfig == 0 {
+		config.NEvalsPerConfig = 1
+	}
+	switch config.Aggregation {
+	case "":
+		config.Aggregation = EvalAggregationMean
+	case EvalAggregationMean, EvalAggregationMedian:
+	default:
+		return nil, fmt.Errorf("unknown aggregation: %s", config.Aggregation)
+	}
 
 	return &BayesianOptimizer{
 		searchSpace: config.SearchSpace,
@@ -211,46 +423,76 @@ func NewBayesianOptimizer(config BayesianOptimizerConfig) (*BayesianOptimizer, e
 		nInitial:    config.NInitial,
 		xi:          config.Xi,
 		kappa:       config.Kappa,
+		patience:    config.Patience,
+		minDelta:    config.MinDelta,
+		nEvals:      config.NEvalsPerConfig,
+		aggregation: config.Aggregation,
 		history:     make([]OptimizationStep, 0),
 		bestScore:   math.Inf(-1),
 	}, nil
 }
 
 // Optimize runs the Bayesian optimization process.
+//
+// It evaluates nIterations configurations chosen by Suggest, starting from
+// any observations already recorded. Each configuration is evaluated
+// NEvalsPerConfig times. The returned history includes those earlier
+// observations. If Patience is set, the run stops early once the best
+// score has plateaued and the result reports StoppedEarly.
 func (b *BayesianOptimizer) Optimize(ctx context.Context, nIterations int) (*OptimizationResult, error) {
+	if b.objective == nil {
+		return nil, fmt.Errorf("objective function is required")
+	}
+
 	startTime := time.Now()
 
-	// Phase 1: Random initialization
-	for i := 0; i < min(b.nInitial, nIterations); i++ {
-		config := b.searchSpace.Sample()
-		score, err := b.objective(ctx, config)
-		if err != nil {
-			return nil, fmt.Errorf("evaluation failed at iteration %d: %w", i, err)
+	_, reference := b.Best()
+	hasReference := b.observations() > 0
+	sinceImprovement := 0
+	stoppedAt := 0
+
+	for i := 0; i < nIterations; i++ {
+		// Random configurations until nInitial are observed, then the
+		// acquisition function takes over
+		config := b.Suggest()
+		if config == nil {
+			return nil, fmt.Errorf("iteration %d: %w", i, ErrConstraintsUnsatisfied)
 		}
 
-		b.addObservation(config, score)
-	}
+		scores := make([]float64, b.nEvals)
+		for j := range scores {
+			score, err := b.objective(ctx, config)
+			if err != nil {
+				return nil, fmt.Errorf("evaluation failed at iteration %d: %w", i, err)
+			}
+			scores[j] = score
+		}
 
-	// Phase 2: Bayesian optimization with acquisition function
-	for i := b.nInitial; i < nIterations; i++ {
-		// Propose next configuration using acquisition function
-		config := b.proposeNext()
+		score := b.ObserveScores(config, scores)
 
-		// Evaluate
-		score, err := b.objective(ctx, config)
-		if err != nil {
-			return nil, fmt.Errorf("evaluation failed at iteration %d: %w", i, err)
+		// Early stopping: track iterations since a significant improvement
+		if !hasReference || b.improves(score, reference) {
+			reference = score
+			hasReference = true
+			sinceImprovement = 0
+		} else {
+			sinceImprovement++
+		}
+		if b.patience > 0 && sinceImprovement >= b.patience && b.observations() >= b.nInitial {
+			stoppedAt = i + 1
+			break
 		}
-
-		b.addObservation(config, score)
 	}
 
 	endTime := time.Now()
 
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	return &OptimizationResult{
 		BestConfig:  b.bestConfig,
 		BestScore:   b.bestScore,
-		History:     b.history,
+		History:     append([]OptimizationStep(nil), b.history...),
 		NIterations: nIterations,
 		StartTime:   startTime,
 		EndTime:     endTime,
@@ -259,34 +501,130 @@ func (b *BayesianOptimizer) Optimize(ctx context.Context, nIterations int) (*Opt
 			"acquisition": string(b.acquisition),
 			"n_initial":   b.nInitial,
 			"maximize":    b.maximize,
+			"n_evals":     b.nEvals,
+			"aggregation": string(b.aggregation),
 		},
+		StoppedEarly: stoppedAt > 0,
+		StoppedAt:    stoppedAt,
 	}, nil
 }
 
-// addObservation adds a new observation to the history.
-func (b *BayesianOptimizer) addObservation(config map[string]interface{}, score float64) {
+// observations returns the number of recorded observations.
+func (b *BayesianOptimizer) observations() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.history)
+}
+
+// improves reports whether score beats reference by more than minDelta in
+// the optimization direction.
+func (b *BayesianOptimizer) improves(score, reference float64) bool {
+	if b.maximize {
+		return score > reference+b.minDelta
+	}
+	return score < reference-b.minDelta
+}
+
+// Suggest returns the next configuration to evaluate.
+//
+// Until nInitial observations have been recorded, configurations are sampled
+// at random; afterwards the acquisition function selects them. Suggest does
+// not record anything, so configurations handed out but not yet observed do
+// not influence later suggestions. Suggestions always satisfy the search
+// space's constraints; Suggest returns nil if none can be found.
+func (b *BayesianOptimizer) Suggest() map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.history) < b.nInitial {
+		return b.searchSpace.Sample()
+	}
+	return b.proposeNext()
+}
+
+// Observe records the score of an evaluated configuration.
+//
+// The configuration need not have come from Suggest; known results (for
+// example from earlier experiments) can be injected to warm-start the
+// optimizer.
+func (b *BayesianOptimizer) Observe(config map[string]interface{}, score float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.addObservation(OptimizationStep{Config: copyConfig(config), Score: score, NEvals: 1})
+}
+
+// ObserveScores records repeated evaluations of one configuration and
+// returns the aggregated score. Their variance is kept with the
+// observation so the surrogate can discount noisy results. Empty scores
+// are ignored and return NaN.
+func (b *BayesianOptimizer) ObserveScores(config map[string]interface{}, scores []float64) float64 {
+	if len(scores) == 0 {
+		return math.NaN()
+	}
+
 	step := OptimizationStep{
-		Config: config,
-		Score:  score,
+		Config: copyConfig(config),
+		Score:  aggregateScores(scores, b.aggregation),
+		NEvals: len(scores),
 	}
+	if len(scores) > 1 {
+		sd := stddev(scores, mean(scores))
+		step.Variance = sd * sd
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.addObservation(step)
+	return step.Score
+}
+
+// Best returns the best configuration and score observed so far. The
+// configuration is nil if nothing has been observed.
+func (b *BayesianOptimizer) Best() (map[string]interface{}, float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.bestConfig == nil {
+		return nil, b.bestScore
+	}
+	return copyConfig(b.bestConfig), b.bestScore
+}
+
+// History returns a copy of all observations in the order recorded.
+func (b *BayesianOptimizer) History() []OptimizationStep {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]OptimizationStep(nil), b.history...)
+}
+
+// addObservation adds a new observation to the history.
+func (b *BayesianOptimizer) addObservation(step OptimizationStep) {
 	b.history = append(b.history, step)
 
 	// Update best
+	score := step.Score
 	if len(b.history) == 1 || (b.maximize && score > b.bestScore) || (!b.maximize && score < b.bestScore) {
 		b.bestScore = score
-		b.bestConfig = copyConfig(config)
+		b.bestConfig = copyConfig(step.Config)
 	}
 }
 
 // proposeNext proposes the next configuration to evaluate using the acquisition function.
 func (b *BayesianOptimizer) proposeNext() map[string]interface{} {
 	nCandidates := 1000
-	bestCandidate := b.searchSpace.Sample()
+	var bestCandidate map[string]interface{}
 	bestAcqValue := math.Inf(-1)
 
-	// Generate and evaluate random candidates
+	// Generate and evaluate random candidates, skipping invalid ones
 	for i := 0; i < nCandidates; i++ {
-		candidate := b.searchSpace.Sample()
+		candidate := b.searchSpace.sampleUnconstrained()
+		if !b.searchSpace.Satisfies(candidate) {
+			continue
+		}
 		acqValue := b.evaluateAcquisition(candidate)
 
 		if acqValue > bestAcqValue {
@@ -295,6 +633,10 @@ func (b *BayesianOptimizer) proposeNext() map[string]interface{} {
 		}
 	}
 
+	// Tight constraints may reject every candidate; search harder for one
+	if bestCandidate == nil {
+		return b.searchSpace.Sample()
+	}
 	return bestCandidate
 }
 
@@ -317,29 +659,54 @@ func (b *BayesianOptimizer) evaluateAcquisition(config map[string]interface{}) f
 
 // estimatePerformance estimates the mean and std of a configuration.
 // This is a simplified version using local neighborhood statistics.
+//
+// Observations are weighted by the inverse of their noise (the variance of
+// their aggregated score), and the average noise is added to the spread, so
+// noisy regions are trusted less and explored more.
 func (b *BayesianOptimizer) estimatePerformance(config map[string]interface{}) (float64, float64) {
 	if len(b.history) == 0 {
 		return 0.0, 1.0
 	}
 
 	// Find similar configurations in history
-	var scores []float64
+	var neighbors []OptimizationStep
 	for _, step := range b.history {
 		similarity := b.configSimilarity(config, step.Config)
 		if similarity > 0.5 { // Threshold for "similar"
-			scores = append(scores, step.Score)
+			neighbors = append(neighbors, step)
 		}
 	}
 
 	// If no similar configs, use global statistics
-	if len(scores) == 0 {
-		for _, step := range b.history {
-			scores = append(scores, step.Score)
+	if len(neighbors) == 0 {
+		neighbors = b.history
+	}
+
+	scores := make([]float64, len(neighbors))
+	noise := make([]float64, len(neighbors))
+	for i, step := range neighbors {
+		scores[i] = step.Score
+		if step.NEvals > 0 {
+			noise[i] = step.Variance / float64(step.NEvals)
 		}
 	}
+	avgNoise := mean(noise)
 
 	mu := mean(scores)
+	if avgNoise > 0 {
+		// Inverse-noise weights, floored at the average noise so exact
+		// observations don't drown out the rest
+		var weightedSum, totalWeight float64
+		for i := range scores {
+			weight := 1.0 / (noise[i] + avgNoise)
+			weightedSum += weight * scores[i]
+			totalWeight += weight
+		}
+		mu = weightedSum / totalWeight
+	}
+
 	sigma := stddev(scores, mu)
+	sigma = math.Sqrt(sigma*sigma + avgNoise)
 
 	// Ensure non-zero sigma
 	if sigma < 1e-6 {
@@ -484,6 +851,20 @@ func stddev(values []float64, mean float64) float64 {
 	return math.Sqrt(variance / float64(len(values)-1))
 }
 
+// aggregateScores combines repeated evaluations into one score.
+func aggregateScores(scores []float64, aggregation EvalAggregation) float64 {
+	if aggregation != EvalAggregationMedian {
+		return mean(scores)
+	}
+	sorted := append([]float64(nil), scores...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
 func normCDF(x float64) float64 {
 	// Approximation of standard normal CDF
 	return 0.5 * (1.0 + math.Erf(x/math.Sqrt(2.0)))
@@ -2,8 +2,10 @@ package evaluation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -138,6 +140,124 @@ func TestSearchSpaceSample(t *testing.T) {
 	}
 }
 
+// constrainedSpace allows momentum only with SGD.
+func constrainedSpace() *SearchSpace {
+	space := NewSearchSpace()
+	space.AddCategorical("optimizer", []string{"sgd", "adam"})
+	space.AddContinuous("momentum", 0.0, 0.9)
+	space.AddInteger("layers", 1, 4)
+	space.AddConstraint(func(config map[string]interface{}) bool {
+		return config["optimizer"] == "sgd" || config["momentum"].(float64) < 0.1
+	})
+	return space
+}
+
+// TestSearchSpaceConstraintsSample tests that samples satisfy constraints
+func TestSearchSpaceConstraintsSample(t *testing.T) {
+	space := constrainedSpace()
+
+	for i := 0; i < 200; i++ {
+		config := space.Sample()
+		if config["optimizer"] == "adam" && config["momentum"].(float64) >= 0.1 {
+			t.Fatalf("sample violates constraint: %v", config)
+		}
+		if err := space.Validate(config); err != nil {
+			t.Fatalf("sample failed validation: %v", err)
+		}
+	}
+
+	space.AddConstraint(func(map[string]interface{}) bool { return false })
+	if _, err := space.TrySample(); !errors.Is(err, ErrConstraintsUnsatisfied) {
+		t.Errorf("expected ErrConstraintsUnsatisfied, got %v", err)
+	}
+	if space.Sample() != nil {
+		t.Error("expected nil sample for unsatisfiable constraints")
+	}
+}
+
+// TestSearchSpaceValidate tests validation of external configurations
+func TestSearchSpaceValidate(t *testing.T) {
+	space := constrainedSpace()
+
+	valid := map[string]interface{}{"optimizer": "sgd", "momentum": 0.8, "layers": 2}
+	if err := space.Validate(valid); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+	// Integral floats (e.g. decoded from JSON) are accepted for integers
+	if err := space.Validate(map[string]interface{}{"optimizer": "adam", "momentum": 0.0, "layers": 3.0}); err != nil {
+		t.Errorf("expected integral float to be accepted, got %v", err)
+	}
+
+	invalid := []struct {
+		config map[string]interface{}
+		want   string
+	}{
+		{map[string]interface{}{"optimizer": "sgd", "layers": 2}, "missing parameter \"momentum\""},
+		{map[string]interface{}{"optimizer": "rmsprop", "momentum": 0.5, "layers": 2}, "not one of"},
+		{map[string]interface{}{"optimizer": "sgd", "momentum": 1.5, "layers": 2}, "outside"},
+		{map[string]interface{}{"optimizer": "sgd", "momentum": "high", "layers": 2}, "expected a number"},
+		{map[string]interface{}{"optimizer": "sgd", "momentum": 0.5, "layers": 2.5}, "expected an integer"},
+		{map[string]interface{}{"optimizer": "sgd", "momentum": 0.5, "layers": 2, "dropout": 0.1}, "unknown parameter"},
+		{map[string]interface{}{"optimizer": "adam", "momentum": 0.5, "layers": 2}, "violates constraint 0"},
+	}
+	for _, tc := range invalid {
+		err := space.Validate(tc.config)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Validate(%v): expected error containing %q, got %v", tc.config, tc.want, err)
+		}
+	}
+}
+
+// TestBayesianOptimizerRespectsConstraints tests that every evaluated config is valid
+func TestBayesianOptimizerRespectsConstraints(t *testing.T) {
+	space := constrainedSpace()
+
+	objective := func(ctx context.Context, config map[string]interface{}) (float64, error) {
+		if err := space.Validate(config); err != nil {
+			return 0, err
+		}
+		// Rewards high momentum, so the optimizer is pulled toward the constraint
+		return config["momentum"].(float64), nil
+	}
+
+	opt, err := NewBayesianOptimizer(BayesianOptimizerConfig{
+		SearchSpace: space,
+		Objective:   objective,
+		Maximize:    true,
+		NInitial:    3,
+	})
+	if err != nil {
+		t.Fatalf("failed to create optimizer: %v", err)
+	}
+	if _, err := opt.Optimize(context.Background(), 15); err != nil {
+		t.Fatalf("optimization evaluated an invalid config: %v", err)
+	}
+
+	random := NewRandomSearchOptimizer(objective, space, true)
+	if _, err := random.Optimize(context.Background(), 15); err != nil {
+		t.Fatalf("random search evaluated an invalid config: %v", err)
+	}
+}
+
+// TestBayesianOptimizerUnsatisfiableConstraints tests the error for impossible spaces
+func TestBayesianOptimizerUnsatisfiableConstraints(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddContinuous("x", 0, 1)
+	space.AddConstraint(func(config map[string]interface{}) bool {
+		return config["x"].(float64) > 2
+	})
+
+	opt, _ := NewBayesianOptimizer(BayesianOptimizerConfig{
+		SearchSpace: space,
+		Objective: func(ctx context.Context, config map[string]interface{}) (float64, error) {
+			return 0, nil
+		},
+	})
+	if _, err := opt.Optimize(context.Background(), 3); !errors.Is(err, ErrConstraintsUnsatisfied) {
+		t.Errorf("expected ErrConstraintsUnsatisfied, got %v", err)
+	}
+}
+
 // TestNewBayesianOptimizer tests optimizer creation
 func TestNewBayesianOptimizer(t *testing.T) {
 	tests := []struct {
@@ -170,12 +290,11 @@ func TestNewBayesianOptimizer(t *testing.T) {
 			errorMsg:    "search space is required",
 		},
 		{
-			name: "nil objective",
+			name: "nil objective allowed for ask/tell",
 			config: BayesianOptimizerConfig{
 				SearchSpace: NewSearchSpace(),
 			},
-			expectError: true,
-			errorMsg:    "objective function is required",
+			expectError: false,
 		},
 		{
 			name: "default values applied",
@@ -282,6 +401,181 @@ func TestBayesianOptimizerOptimizeBasic(t *testing.T) {
 	}
 }
 
+// TestBayesianOptimizerAskTell tests the Suggest/Observe interface
+func TestBayesianOptimizerAskTell(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddContinuous("x", -5.0, 5.0)
+
+	opt, err := NewBayesianOptimizer(BayesianOptimizerConfig{
+		SearchSpace: space,
+		Maximize:    true,
+		NInitial:    2,
+	})
+	if err != nil {
+		t.Fatalf("failed to create optimizer: %v", err)
+	}
+
+	if config, _ := opt.Best(); config != nil {
+		t.Errorf("expected no best config before observations, got %v", config)
+	}
+
+	// Inject a known result, then run the ask/tell loop
+	opt.Observe(map[string]interface{}{"x": 2.0}, 0.0)
+	for i := 0; i < 5; i++ {
+		config := opt.Suggest()
+		x, ok := config["x"].(float64)
+		if !ok || x < -5.0 || x > 5.0 {
+			t.Fatalf("suggestion out of range: %v", config)
+		}
+		opt.Observe(config, -(x-2.0)*(x-2.0))
+	}
+
+	if len(opt.History()) != 6 {
+		t.Errorf("expected 6 observations, got %d", len(opt.History()))
+	}
+
+	best, score := opt.Best()
+	if best["x"] != 2.0 || score != 0.0 {
+		t.Errorf("expected injected optimum to remain best, got %v (%v)", best, score)
+	}
+
+	// Optimize continues from the recorded state
+	opt.objective = func(ctx context.Context, config map[string]interface{}) (float64, error) {
+		x := config["x"].(float64)
+		return -(x - 2.0) * (x - 2.0), nil
+	}
+	result, err := opt.Optimize(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("optimization failed: %v", err)
+	}
+	if len(result.History) != 9 {
+		t.Errorf("expected 9 history entries, got %d", len(result.History))
+	}
+}
+
+// TestBayesianOptimizerObserveCopiesConfig tests that Observe does not alias
+func TestBayesianOptimizerObserveCopiesConfig(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddContinuous("x", 0.0, 1.0)
+
+	opt, err := NewBayesianOptimizer(BayesianOptimizerConfig{SearchSpace: space, Maximize: true})
+	if err != nil {
+		t.Fatalf("failed to create optimizer: %v", err)
+	}
+
+	config := map[string]interface{}{"x": 0.5}
+	opt.Observe(config, 1.0)
+	config["x"] = 0.9
+
+	if got := opt.History()[0].Config["x"]; got != 0.5 {
+		t.Errorf("expected recorded config to be unaffected, got %v", got)
+	}
+}
+
+// TestBayesianOptimizerOptimizeRequiresObjective tests Optimize without an objective
+func TestBayesianOptimizerOptimizeRequiresObjective(t *testing.T) {
+	opt, err := NewBayesianOptimizer(BayesianOptimizerConfig{SearchSpace: NewSearchSpace()})
+	if err != nil {
+		t.Fatalf("failed to create optimizer: %v", err)
+	}
+
+	if _, err := opt.Optimize(context.Background(), 1); err == nil {
+		t.Fatal("expected error without objective")
+	}
+}
+
+// TestBayesianOptimizerEarlyStopping tests Patience-based early stopping
+func TestBayesianOptimizerEarlyStopping(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddContinuous("x", 0.0, 1.0)
+
+	evaluations := 0
+	opt, err := NewBayesianOptimizer(BayesianOptimizerConfig{
+		SearchSpace: space,
+		Objective: func(ctx context.Context, config map[string]interface{}) (float64, error) {
+			evaluations++
+			return 1.0, nil // Flat objective never improves
+		},
+		Maximize: true,
+		NInitial: 3,
+		Patience: 4,
+	})
+	if err != nil {
+		t.Fatalf("failed to create optimizer: %v", err)
+	}
+
+	result, err := opt.Optimize(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("optimization failed: %v", err)
+	}
+
+	if !result.StoppedEarly {
+		t.Fatal("expected early stop on flat objective")
+	}
+	// First observation sets the reference, then 4 without improvement
+	if result.StoppedAt != 5 || evaluations != 5 {
+		t.Errorf("expected stop after 5 iterations, got StoppedAt=%d evaluations=%d", result.StoppedAt, evaluations)
+	}
+	if result.NIterations != 50 {
+		t.Errorf("expected requested iterations to be preserved, got %d", result.NIterations)
+	}
+}
+
+// TestBayesianOptimizerEarlyStoppingMinDelta tests that tiny gains don't reset patience
+func TestBayesianOptimizerEarlyStoppingMinDelta(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddContinuous("x", 0.0, 1.0)
+
+	score := 0.0
+	opt, err := NewBayesianOptimizer(BayesianOptimizerConfig{
+		SearchSpace: space,
+		Objective: func(ctx context.Context, config map[string]interface{}) (float64, error) {
+			score -= 0.001 // Minimizing: steady but negligible improvement
+			return score, nil
+		},
+		NInitial: 1,
+		Patience: 3,
+		MinDelta: 0.01,
+	})
+	if err != nil {
+		t.Fatalf("failed to create optimizer: %v", err)
+	}
+
+	result, err := opt.Optimize(context.Background(), 20)
+	if err != nil {
+		t.Fatalf("optimization failed: %v", err)
+	}
+	if !result.StoppedEarly || result.StoppedAt != 4 {
+		t.Errorf("expected early stop at iteration 4, got %v at %d", result.StoppedEarly, result.StoppedAt)
+	}
+}
+
+// TestBayesianOptimizerNoEarlyStopWithoutPatience tests the default full run
+func TestBayesianOptimizerNoEarlyStopWithoutPatience(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddContinuous("x", 0.0, 1.0)
+
+	opt, err := NewBayesianOptimizer(BayesianOptimizerConfig{
+		SearchSpace: space,
+		Objective: func(ctx context.Context, config map[string]interface{}) (float64, error) {
+			return 1.0, nil
+		},
+		Maximize: true,
+		NInitial: 2,
+	})
+	if err != nil {
+		t.Fatalf("failed to create optimizer: %v", err)
+	}
+
+	result, err := opt.Optimize(context.Background(), 8)
+	if err != nil {
+		t.Fatalf("optimization failed: %v", err)
+	}
+	if result.StoppedEarly || len(result.History) != 8 {
+		t.Errorf("expected full run, got StoppedEarly=%v history=%d", result.StoppedEarly, len(result.History))
+	}
+}
+
 // TestBayesianOptimizerAcquisitionFunctions tests different acquisition functions
 func TestBayesianOptimizerAcquisitionFunctions(t *testing.T) {
 	acquisitions := []AcquisitionFunction{
@@ -637,3 +931,105 @@ func TestCopyConfig(t *testing.T) {
 		t.Error("modifying copy affected original")
 	}
 }
+
+// TestBayesianOptimizerNEvalsPerConfig tests repeated evaluations per configuration
+func TestBayesianOptimizerNEvalsPerConfig(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddContinuous("x", 0, 1)
+
+	calls := 0
+	noise := []float64{-0.2, 0.0, 0.2}
+	optimizer, err := NewBayesianOptimizer(BayesianOptimizerConfig{
+		SearchSpace: space,
+		Objective: func(ctx context.Context, config map[string]interface{}) (float64, error) {
+			score := config["x"].(float64) + noise[calls%3]
+			calls++
+			return score, nil
+		},
+		Maximize:        true,
+		NInitial:        2,
+		NEvalsPerConfig: 3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := optimizer.Optimize(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 12 {
+		t.Errorf("expected 3 evaluations per config, got %d calls", calls)
+	}
+	for _, step := range result.History {
+		if step.NEvals != 3 {
+			t.Errorf("expected NEvals=3, got %d", step.NEvals)
+		}
+		if math.Abs(step.Score-step.Config["x"].(float64)) > 1e-9 {
+			t.Errorf("expected noise to average out, got score %v for x=%v", step.Score, step.Config["x"])
+		}
+		if math.Abs(step.Variance-0.04) > 1e-9 {
+			t.Errorf("expected variance 0.04, got %v", step.Variance)
+		}
+	}
+	if result.Metadata["n_evals"] != 3 {
+		t.Errorf("expected n_evals metadata, got %v", result.Metadata["n_evals"])
+	}
+}
+
+// TestBayesianOptimizerObserveScores tests aggregation of repeated observations
+func TestBayesianOptimizerObserveScores(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddContinuous("x", 0, 1)
+
+	optimizer, err := NewBayesianOptimizer(BayesianOptimizerConfig{
+		SearchSpace: space,
+		Maximize:    true,
+		Aggregation: EvalAggregationMedian,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A lucky outlier doesn't move the median
+	score := optimizer.ObserveScores(map[string]interface{}{"x": 0.5}, []float64{0.6, 0.5, 5.0})
+	if score != 0.6 {
+		t.Errorf("expected median 0.6, got %v", score)
+	}
+	if !math.IsNaN(optimizer.ObserveScores(map[string]interface{}{"x": 0.1}, nil)) {
+		t.Error("expected NaN for empty scores")
+	}
+
+	history := optimizer.History()
+	if len(history) != 1 || history[0].NEvals != 3 || history[0].Variance <= 0 {
+		t.Errorf("unexpected history: %+v", history)
+	}
+
+	if _, err := NewBayesianOptimizer(BayesianOptimizerConfig{SearchSpace: space, Aggregation: "mode"}); err == nil {
+		t.Error("expected error for unknown aggregation")
+	}
+	if _, err := NewBayesianOptimizer(BayesianOptimizerConfig{SearchSpace: space, NEvalsPerConfig: -1}); err == nil {
+		t.Error("expected error for negative NEvalsPerConfig")
+	}
+}
+
+// TestEstimatePerformanceDiscountsNoise tests inverse-noise weighting in the surrogate
+func TestEstimatePerformanceDiscountsNoise(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddCategorical("model", []string{"a"})
+
+	optimizer, _ := NewBayesianOptimizer(BayesianOptimizerConfig{SearchSpace: space, Maximize: true})
+	config := map[string]interface{}{"model": "a"}
+
+	optimizer.ObserveScores(config, []float64{0.5, 0.5, 0.5})
+	optimizer.ObserveScores(config, []float64{0.0, 2.0, 1.0})
+
+	mu, sigma := optimizer.estimatePerformance(config)
+	if mu >= 0.75 {
+		t.Errorf("expected noisy observation to count less than the exact one, got mu=%v", mu)
+	}
+	exactSigma := stddev([]float64{0.5, 1.0}, mu)
+	if sigma <= exactSigma {
+		t.Errorf("expected observation noise to widen sigma beyond %v, got %v", exactSigma, sigma)
+	}
+}
@@ -0,0 +1,75 @@
+package evaluation
+
+import (
+	"fmt"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// CompositeMetric derives a score from other metrics with a formula, such
+// as quality per dollar or accuracy with a latency penalty. It measures its
+// dependencies on each interaction and combines their scores, so the result
+// can be aggregated, compared and optimized like any other metric.
+//
+// Example:
+//
+//	qualityPerCent := NewCompositeMetric("quality_per_cent",
+//	    []Metric{NewQualityMetrics(false, "", nil), costMetric},
+//	    func(scores map[string]float64) float64 {
+//	        return scores["quality"] / math.Max(scores["cost"]*100, 0.01)
+//	    })
+//	evaluator := NewEvaluator(agent, []Metric{qualityPerCent}, "")
+type CompositeMetric struct {
+	name    string
+	deps    []Metric
+	combine func(perTestScores map[string]float64) float64
+}
+
+// NewCompositeMetric creates a metric named name that combines the scores
+// of deps.
+//
+// Args:
+//
+//	name: Metric name
+//	deps: Metrics measured on each interaction
+//	combine: Formula over the scores, keyed by dependency name
+func NewCompositeMetric(name string, deps []Metric, combine func(perTestScores map[string]float64) float64) *CompositeMetric {
+	return &CompositeMetric{
+		name:    name,
+		deps:    deps,
+		combine: combine,
+	}
+}
+
+// Name returns the metric name.
+func (m *CompositeMetric) Name() string {
+	return m.name
+}
+
+// Dependencies returns the metrics the composite combines.
+func (m *CompositeMetric) Dependencies() []Metric {
+	deps := make([]Metric, len(m.deps))
+	copy(deps, m.deps)
+	return deps
+}
+
+// Measure measures every dependency on the interaction and combines the
+// scores.
+//
+// Returns an error if the formula is nil or any dependency fails, so the
+// interaction is skipped rather than scored from partial inputs.
+func (m *CompositeMetric) Measure(agent agenkit.Agent, inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) (float64, error) {
+	if m.combine == nil {
+		return 0, fmt.Errorf("composite metric %s has no combine function", m.name)
+	}
+
+	scores := make(map[string]float64, len(m.deps))
+	for _, dep := range m.deps {
+		score, err := dep.Measure(agent, inputMessage, outputMessage, ctx)
+		if err != nil {
+			return 0, fmt.Errorf("composite metric %s: %s: %w", m.name, dep.Name(), err)
+		}
+		scores[dep.Name()] = score
+	}
+	return m.combine(scores), nil
+}
@@ -16,6 +16,7 @@ package evaluation
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"time"
 
@@ -31,6 +32,9 @@ import (
 //   - Context usage
 //   - Quality scores
 //   - etc.
+//
+// Implement MetricAggregator as well to control how the Evaluator
+// summarizes a metric's measurements.
 type Metric interface {
 	// Name returns the metric name.
 	Name() string
@@ -46,7 +50,13 @@ type Metric interface {
 	// Returns:
 	//   Metric value (typically 0.0 to 1.0)
 	Measure(agent agenkit.Agent, inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) (float64, error)
+}
 
+// MetricAggregator is implemented by metrics that summarize their
+// measurements with statistics suited to the metric, such as a correct
+// count for accuracy, percentiles for latency or a total for cost.
+// Metrics without it are summarized by DefaultAggregate.
+type MetricAggregator interface {
 	// Aggregate aggregates multiple measurements.
 	//
 	// Args:
@@ -57,6 +67,59 @@ type Metric interface {
 	Aggregate(measurements []float64) map[string]float64
 }
 
+// DimensionedMetric is implemented by metrics that blend several
+// dimensions into one score. The Evaluator records each dimension's
+// measurements alongside the metric's own, named "<metric>.<dimension>"
+// (see DimensionName), so results show which dimension moved the score.
+type DimensionedMetric interface {
+	// MeasureDimensions measures each dimension for a single agent
+	// interaction, keyed by dimension name.
+	MeasureDimensions(agent agenkit.Agent, inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) (map[string]float64, error)
+}
+
+// DimensionName returns the name under which the Evaluator records a
+// dimension of metric, such as "quality.accuracy".
+func DimensionName(metric, dimension string) string {
+	return metric + "." + dimension
+}
+
+// AggregateMetric summarizes measurements of metric with its own Aggregate
+// method if it implements MetricAggregator, or DefaultAggregate otherwise.
+func AggregateMetric(metric Metric, measurements []float64) map[string]float64 {
+	if aggregator, ok := metric.(MetricAggregator); ok {
+		return aggregator.Aggregate(measurements)
+	}
+	return DefaultAggregate(measurements)
+}
+
+// DefaultAggregate returns the mean, min, max and (population) std of
+// measurements, all 0 if there are none.
+func DefaultAggregate(measurements []float64) map[string]float64 {
+	if len(measurements) == 0 {
+		return map[string]float64{
+			"mean": 0.0,
+			"min":  0.0,
+			"max":  0.0,
+			"std":  0.0,
+		}
+	}
+
+	mean := sum(measurements) / float64(len(measurements))
+
+	variance := 0.0
+	for _, x := range measurements {
+		variance += (x - mean) * (x - mean)
+	}
+	variance /= float64(len(measurements))
+
+	return map[string]float64{
+		"mean": mean,
+		"min":  minFloat64(measurements),
+		"max":  maxFloat64(measurements),
+		"std":  math.Sqrt(variance),
+	}
+}
+
 // EvaluationResult contains results from an evaluation run.
 //
 // Includes metrics, metadata, and analysis.
@@ -201,6 +264,19 @@ func NewEvaluator(agent agenkit.Agent, metrics []Metric, sessionID string) *Eval
 //	}
 //	result, err := evaluator.Evaluate(testCases, "")
 func (e *Evaluator) Evaluate(testCases []map[string]interface{}, evaluationID string) (*EvaluationResult, error) {
+	result, _ := e.evaluate(testCases, evaluationID, nil)
+	return result, nil
+}
+
+// caseOutcome is how one test case fared in an evaluation run.
+type caseOutcome struct {
+	passed  bool
+	metrics map[string]float64
+}
+
+// evaluate runs testCases like Evaluate, adding inputMetadata to every
+// input message, and also returns each test case's outcome in order.
+func (e *Evaluator) evaluate(testCases []map[string]interface{}, evaluationID string, inputMetadata map[string]interface{}) (*EvaluationResult, []caseOutcome) {
 	if evaluationID == "" {
 		evaluationID = uuid.New().String()
 	}
@@ -215,8 +291,11 @@ func (e *Evaluator) Evaluate(testCases []map[string]interface{}, evaluationID st
 		Metadata:          make(map[string]interface{}),
 	}
 
+	outcomes := make([]caseOutcome, len(testCases))
+	dimensionNames := make(map[string]bool)
+
 	// Run tests and collect metrics
-	for _, testCase := range testCases {
+	for i, testCase := range testCases {
 		// Extract input
 		inputContent, ok := testCase["input"].(string)
 		if !ok {
@@ -231,6 +310,9 @@ func (e *Evaluator) Evaluate(testCases []map[string]interface{}, evaluationID st
 				"session_id": e.sessionID,
 			},
 		}
+		for key, value := range inputMetadata {
+			inputMsg.Metadata[key] = value
+		}
 
 		// Run agent with timing
 		start := time.Now()
@@ -255,6 +337,7 @@ func (e *Evaluator) Evaluate(testCases []map[string]interface{}, evaluationID st
 
 		// Check test
 		testPassed := e.checkTest(outputMsg, testCase)
+		outcomes[i] = caseOutcome{passed: testPassed, metrics: make(map[string]float64)}
 		if testPassed {
 			result.PassedTests++
 		} else {
@@ -278,15 +361,25 @@ func (e *Evaluator) Evaluate(testCases []map[string]interface{}, evaluationID st
 				result.Metrics[metric.Name()] = []float64{}
 			}
 			result.Metrics[metric.Name()] = append(result.Metrics[metric.Name()], value)
+			outcomes[i].metrics[metric.Name()] = value
+
+			for name, value := range measureDimensions(metric, e.agent, inputMsg, outputMsg, ctx) {
+				result.Metrics[name] = append(result.Metrics[name], value)
+				outcomes[i].metrics[name] = value
+				dimensionNames[name] = true
+			}
 		}
 	}
 
 	// Aggregate metrics
 	for _, metric := range e.metrics {
 		if measurements, ok := result.Metrics[metric.Name()]; ok {
-			result.AggregatedMetrics[metric.Name()] = metric.Aggregate(measurements)
+			result.AggregatedMetrics[metric.Name()] = AggregateMetric(metric, measurements)
 		}
 	}
+	for name := range dimensionNames {
+		result.AggregatedMetrics[name] = DefaultAggregate(result.Metrics[name])
+	}
 
 	// Calculate aggregate statistics
 	accuracy := result.SuccessRate()
@@ -303,7 +396,7 @@ func (e *Evaluator) Evaluate(testCases []map[string]interface{}, evaluationID st
 		result.P95LatencyMs = &p95Latency
 	}
 
-	return result, nil
+	return result, outcomes
 }
 
 // checkTest checks if output passes test case.
@@ -364,11 +457,32 @@ func (e *Evaluator) EvaluateSingle(inputMessage *agenkit.Message, expectedOutput
 			continue
 		}
 		metricsResults[metric.Name()] = value
+		for name, value := range measureDimensions(metric, e.agent, inputMessage, outputMessage, ctx) {
+			metricsResults[name] = value
+		}
 	}
 
 	return metricsResults, nil
 }
 
+// measureDimensions returns the dimension scores of a DimensionedMetric,
+// keyed by DimensionName, or nil for other metrics or on error.
+func measureDimensions(metric Metric, agent agenkit.Agent, inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) map[string]float64 {
+	dimensioned, ok := metric.(DimensionedMetric)
+	if !ok {
+		return nil
+	}
+	scores, err := dimensioned.MeasureDimensions(agent, inputMessage, outputMessage, ctx)
+	if err != nil {
+		return nil
+	}
+	named := make(map[string]float64, len(scores))
+	for dimension, score := range scores {
+		named[DimensionName(metric.Name(), dimension)] = score
+	}
+	return named
+}
+
 // Helper functions
 
 func sum(values []float64) float64 {
@@ -0,0 +1,112 @@
+package evaluation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// lengthMetric measures response length and has no custom aggregation.
+type lengthMetric struct{}
+
+func (m *lengthMetric) Name() string { return "length" }
+
+func (m *lengthMetric) Measure(agent agenkit.Agent, inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) (float64, error) {
+	return float64(len(outputMessage.ContentString())), nil
+}
+
+// costMetric charges a fixed cost per call and aggregates to a total.
+type costMetric struct{}
+
+func (m *costMetric) Name() string { return "cost" }
+
+func (m *costMetric) Measure(agent agenkit.Agent, inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) (float64, error) {
+	return 0.25, nil
+}
+
+func (m *costMetric) Aggregate(measurements []float64) map[string]float64 {
+	return map[string]float64{"total": sum(measurements)}
+}
+
+func TestEvaluator_PerMetricAggregation(t *testing.T) {
+	evaluator := NewEvaluator(&MockAgent{name: "test-agent"}, []Metric{&lengthMetric{}, &costMetric{}}, "")
+	result, err := evaluator.Evaluate([]map[string]interface{}{
+		{"input": "one"},
+		{"input": "two"},
+		{"input": "three"},
+	}, "")
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	cost := result.AggregatedMetrics["cost"]
+	if len(cost) != 1 || cost["total"] != 0.75 {
+		t.Errorf("Expected custom total aggregation, got %v", cost)
+	}
+
+	length := result.AggregatedMetrics["length"]
+	if length["mean"] != 13 || length["min"] != 13 || length["max"] != 13 || length["std"] != 0 {
+		t.Errorf("Expected default statistics, got %v", length)
+	}
+}
+
+func TestDefaultAggregate(t *testing.T) {
+	stats := DefaultAggregate([]float64{1, 2, 3, 4})
+	if stats["mean"] != 2.5 || stats["min"] != 1 || stats["max"] != 4 {
+		t.Errorf("Unexpected statistics: %v", stats)
+	}
+	if diff := stats["std"] - 1.118; diff < -0.001 || diff > 0.001 {
+		t.Errorf("Expected population std ~1.118, got %v", stats["std"])
+	}
+
+	if empty := DefaultAggregate(nil); empty["mean"] != 0 || len(empty) != 4 {
+		t.Errorf("Expected zeroed statistics, got %v", empty)
+	}
+}
+
+// failingMetric always fails to measure.
+type failingMetric struct{}
+
+func (m *failingMetric) Name() string { return "failing" }
+
+func (m *failingMetric) Measure(agent agenkit.Agent, inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) (float64, error) {
+	return 0, errors.New("judge unavailable")
+}
+
+func TestCompositeMetric(t *testing.T) {
+	qualityPerCost := NewCompositeMetric("length_per_cost", []Metric{&lengthMetric{}, &costMetric{}},
+		func(scores map[string]float64) float64 {
+			return scores["length"] / scores["cost"]
+		})
+
+	evaluator := NewEvaluator(&MockAgent{name: "test-agent"}, []Metric{qualityPerCost}, "")
+	result, err := evaluator.Evaluate([]map[string]interface{}{{"input": "one"}, {"input": "two"}}, "")
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	// 13 characters / 0.25 cost per test, aggregated with the defaults
+	stats := result.AggregatedMetrics["length_per_cost"]
+	if stats["mean"] != 52 || stats["max"] != 52 {
+		t.Errorf("Expected composite mean 52, got %v", stats)
+	}
+	if len(qualityPerCost.Dependencies()) != 2 {
+		t.Errorf("Expected 2 dependencies, got %d", len(qualityPerCost.Dependencies()))
+	}
+}
+
+func TestCompositeMetric_DependencyError(t *testing.T) {
+	failing := NewCompositeMetric("broken", []Metric{&lengthMetric{}, &failingMetric{}},
+		func(scores map[string]float64) float64 { return scores["length"] })
+
+	_, err := failing.Measure(&MockAgent{name: "a"}, agenkit.NewMessage("user", "x"), agenkit.NewMessage("agent", "y"), map[string]interface{}{})
+	if err == nil {
+		t.Error("Expected dependency error to propagate")
+	}
+
+	noFormula := NewCompositeMetric("empty", nil, nil)
+	if _, err := noFormula.Measure(&MockAgent{name: "a"}, nil, nil, nil); err == nil {
+		t.Error("Expected error for nil combine function")
+	}
+}
@@ -0,0 +1,189 @@
+// Package evaluation provides deployment decisions for agent versions.
+//
+// Compares a candidate agent version against a baseline on quality,
+// error rate and latency, and decides whether to ship it.
+package evaluation
+
+import (
+	"fmt"
+	"math"
+)
+
+// Decision is a deployment verdict for a candidate agent version.
+type Decision string
+
+const (
+	// DecisionDeploy means every criterion passed
+	DecisionDeploy Decision = "deploy"
+	// DecisionConditional means the candidate is better but a soft
+	// criterion (latency or significance) failed: deploy with monitoring
+	// or gather more data first
+	DecisionConditional Decision = "conditional"
+	// DecisionReject means a hard criterion (quality or errors) failed
+	DecisionReject Decision = "reject"
+)
+
+// ComparisonResult summarizes one agent version's performance on a test
+// suite for DeploymentDecision.
+type ComparisonResult struct {
+	// QualityScores are the per-test-case quality scores. Their mean is
+	// compared across versions, and their spread drives the significance test.
+	QualityScores []float64
+	// AvgLatencyMs is the average response latency in milliseconds
+	AvgLatencyMs float64
+	// ErrorRate is the fraction of failed interactions (0-1)
+	ErrorRate float64
+}
+
+// NewComparisonResult summarizes an EvaluationResult, taking quality
+// scores from the measurements of qualityMetric and the error rate from
+// failed tests.
+func NewComparisonResult(result *EvaluationResult, qualityMetric string) ComparisonResult {
+	comparison := ComparisonResult{
+		QualityScores: result.Metrics[qualityMetric],
+	}
+	if result.AvgLatencyMs != nil {
+		comparison.AvgLatencyMs = *result.AvgLatencyMs
+	}
+	if result.TotalTests > 0 {
+		comparison.ErrorRate = 1.0 - result.SuccessRate()
+	}
+	return comparison
+}
+
+// MeanQuality returns the mean quality score (0 if there are none).
+func (r ComparisonResult) MeanQuality() float64 {
+	return mean(r.QualityScores)
+}
+
+// DeploymentCriteria are the gates a candidate must pass to replace the
+// baseline.
+//
+// Zero values are strict: a zero MaxLatencyRegression allows no latency
+// increase at all. Start from DefaultDeploymentCriteria for typical gates.
+type DeploymentCriteria struct {
+	// MinQuality is an absolute floor on the candidate's mean quality
+	// (0 = no floor). Hard gate.
+	MinQuality float64
+	// MinQualityImprovement is the minimum relative quality gain over the
+	// baseline (0.05 = 5%). The candidate must always improve on the
+	// baseline, so 0 means "any improvement". Hard gate.
+	MinQualityImprovement float64
+	// MaxErrorRateIncrease is the maximum absolute error-rate increase
+	// (0.01 = one percentage point). Hard gate.
+	MaxErrorRateIncrease float64
+	// MaxLatencyRegression is the maximum relative latency increase
+	// (0.10 = 10%). Soft gate.
+	MaxLatencyRegression float64
+	// Significance is the p-value the quality difference must beat in a
+	// Welch's t-test, as used by ABTest (0 disables the test). Soft gate.
+	Significance SignificanceLevel
+}
+
+// DefaultDeploymentCriteria returns criteria requiring any quality
+// improvement at 95% confidence, at most 10% more latency and at most one
+// percentage point more errors.
+func DefaultDeploymentCriteria() DeploymentCriteria {
+	return DeploymentCriteria{
+		MaxErrorRateIncrease: 0.01,
+		MaxLatencyRegression: 0.10,
+		Significance:         SignificanceLevel005,
+	}
+}
+
+// DeploymentDecision decides whether candidate should replace baseline.
+//
+// Hard gates (quality floor, quality improvement, error rate) reject the
+// candidate when they fail. Soft gates (latency, significance) downgrade
+// the verdict to DecisionConditional. The returned reasons explain every
+// criterion checked, prefixed with "pass:" or "fail:", in the order of the
+// DeploymentCriteria fields.
+//
+// Example:
+//
+//	decision, reasons := evaluation.DeploymentDecision(
+//	    evaluation.NewComparisonResult(baselineResult, "quality"),
+//	    evaluation.NewComparisonResult(candidateResult, "quality"),
+//	    evaluation.DefaultDeploymentCriteria(),
+//	)
+//	for _, reason := range reasons {
+//	    fmt.Println(reason)
+//	}
+//	if decision == evaluation.DecisionReject {
+//	    os.Exit(1)
+//	}
+func DeploymentDecision(baseline, candidate ComparisonResult, criteria DeploymentCriteria) (Decision, []string) {
+	var reasons []string
+	hardFailed, softFailed := false, false
+
+	check := func(passed, hard bool, format string, args ...interface{}) {
+		status := "pass"
+		if !passed {
+			status = "fail"
+			if hard {
+				hardFailed = true
+			} else {
+				softFailed = true
+			}
+		}
+		reasons = append(reasons, status+": "+fmt.Sprintf(format, args...))
+	}
+
+	baseQuality, candQuality := baseline.MeanQuality(), candidate.MeanQuality()
+
+	if criteria.MinQuality > 0 {
+		check(candQuality >= criteria.MinQuality, true,
+			"quality %.3f vs floor %.3f", candQuality, criteria.MinQuality)
+	}
+
+	improvement := relativeChange(baseQuality, candQuality)
+	check(candQuality > baseQuality && improvement >= criteria.MinQualityImprovement, true,
+		"quality %.3f -> %.3f (%+.1f%%, minimum %+.1f%%)",
+		baseQuality, candQuality, improvement*100, criteria.MinQualityImprovement*100)
+
+	errorIncrease := candidate.ErrorRate - baseline.ErrorRate
+	check(errorIncrease <= criteria.MaxErrorRateIncrease, true,
+		"error rate %.1f%% -> %.1f%% (%+.1f points, maximum %+.1f)",
+		baseline.ErrorRate*100, candidate.ErrorRate*100, errorIncrease*100, criteria.MaxErrorRateIncrease*100)
+
+	latencyChange := relativeChange(baseline.AvgLatencyMs, candidate.AvgLatencyMs)
+	check(latencyChange <= criteria.MaxLatencyRegression, false,
+		"latency %.1fms -> %.1fms (%+.1f%%, maximum %+.1f%%)",
+		baseline.AvgLatencyMs, candidate.AvgLatencyMs, latencyChange*100, criteria.MaxLatencyRegression*100)
+
+	if criteria.Significance > 0 {
+		if len(baseline.QualityScores) < 2 || len(candidate.QualityScores) < 2 {
+			check(false, false, "significance: need at least 2 quality scores per version, have %d and %d",
+				len(baseline.QualityScores), len(candidate.QualityScores))
+		} else {
+			p := tTest(baseline.QualityScores, candidate.QualityScores)
+			check(p < float64(criteria.Significance), false,
+				"quality difference p=%.4f (required < %.4f)", p, float64(criteria.Significance))
+		}
+	}
+
+	switch {
+	case hardFailed:
+		return DecisionReject, reasons
+	case softFailed:
+		return DecisionConditional, reasons
+	default:
+		return DecisionDeploy, reasons
+	}
+}
+
+// relativeChange returns (current-base)/base, treating any change from a
+// zero base as infinite.
+func relativeChange(base, current float64) float64 {
+	if base == 0 {
+		switch {
+		case current > 0:
+			return math.Inf(1)
+		case current < 0:
+			return math.Inf(-1)
+		default:
+			return 0
+		}
+	}
+	return (current - base) / math.Abs(base)
+}
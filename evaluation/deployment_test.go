@@ -0,0 +1,136 @@
+package evaluation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeploymentDecision_Deploy(t *testing.T) {
+	baseline := ComparisonResult{
+		QualityScores: []float64{0.60, 0.62, 0.58, 0.61, 0.59},
+		AvgLatencyMs:  100,
+		ErrorRate:     0.02,
+	}
+	candidate := ComparisonResult{
+		QualityScores: []float64{0.80, 0.82, 0.78, 0.81, 0.79},
+		AvgLatencyMs:  105,
+		ErrorRate:     0.02,
+	}
+
+	decision, reasons := DeploymentDecision(baseline, candidate, DefaultDeploymentCriteria())
+	if decision != DecisionDeploy {
+		t.Errorf("expected deploy, got %s: %v", decision, reasons)
+	}
+	if len(reasons) != 4 {
+		t.Errorf("expected a reason per criterion, got %v", reasons)
+	}
+	for _, reason := range reasons {
+		if !strings.HasPrefix(reason, "pass: ") {
+			t.Errorf("expected all criteria to pass, got %q", reason)
+		}
+	}
+}
+
+func TestDeploymentDecision_Conditional(t *testing.T) {
+	baseline := ComparisonResult{QualityScores: []float64{0.6, 0.6}, AvgLatencyMs: 100}
+	candidate := ComparisonResult{QualityScores: []float64{0.8, 0.8}, AvgLatencyMs: 150}
+
+	decision, reasons := DeploymentDecision(baseline, candidate, DefaultDeploymentCriteria())
+	if decision != DecisionConditional {
+		t.Errorf("expected conditional for latency regression, got %s", decision)
+	}
+	if !strings.HasPrefix(reasons[2], "fail: latency") {
+		t.Errorf("expected latency failure, got %v", reasons)
+	}
+
+	// Noisy improvement isn't significant
+	baseline = ComparisonResult{QualityScores: []float64{0.2, 0.9, 0.5}}
+	candidate = ComparisonResult{QualityScores: []float64{0.3, 1.0, 0.5}}
+	decision, reasons = DeploymentDecision(baseline, candidate, DefaultDeploymentCriteria())
+	if decision != DecisionConditional || !strings.HasPrefix(reasons[3], "fail: quality difference") {
+		t.Errorf("expected conditional for insignificant result, got %s: %v", decision, reasons)
+	}
+}
+
+func TestDeploymentDecision_Reject(t *testing.T) {
+	baseline := ComparisonResult{QualityScores: []float64{0.8, 0.8}, AvgLatencyMs: 100}
+
+	tests := []struct {
+		name      string
+		candidate ComparisonResult
+		criteria  DeploymentCriteria
+		failing   string
+	}{
+		{
+			name:      "quality regression",
+			candidate: ComparisonResult{QualityScores: []float64{0.7, 0.7}, AvgLatencyMs: 50},
+			criteria:  DefaultDeploymentCriteria(),
+			failing:   "fail: quality 0.800 -> 0.700",
+		},
+		{
+			name:      "improvement below minimum",
+			candidate: ComparisonResult{QualityScores: []float64{0.82, 0.82}, AvgLatencyMs: 100},
+			criteria:  DeploymentCriteria{MinQualityImprovement: 0.05, MaxLatencyRegression: 0.1},
+			failing:   "fail: quality 0.800 -> 0.820",
+		},
+		{
+			name:      "error rate increase",
+			candidate: ComparisonResult{QualityScores: []float64{0.9, 0.9}, AvgLatencyMs: 100, ErrorRate: 0.05},
+			criteria:  DefaultDeploymentCriteria(),
+			failing:   "fail: error rate",
+		},
+		{
+			name:      "below quality floor",
+			candidate: ComparisonResult{QualityScores: []float64{0.85, 0.85}, AvgLatencyMs: 100},
+			criteria:  DeploymentCriteria{MinQuality: 0.9, MaxLatencyRegression: 0.1},
+			failing:   "fail: quality 0.850 vs floor 0.900",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, reasons := DeploymentDecision(baseline, tt.candidate, tt.criteria)
+			if decision != DecisionReject {
+				t.Errorf("expected reject, got %s: %v", decision, reasons)
+			}
+			found := false
+			for _, reason := range reasons {
+				if strings.HasPrefix(reason, tt.failing) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected reason %q, got %v", tt.failing, reasons)
+			}
+		})
+	}
+}
+
+func TestDeploymentDecision_InsufficientSamples(t *testing.T) {
+	baseline := ComparisonResult{QualityScores: []float64{0.5}}
+	candidate := ComparisonResult{QualityScores: []float64{0.9}}
+
+	decision, reasons := DeploymentDecision(baseline, candidate, DefaultDeploymentCriteria())
+	if decision != DecisionConditional {
+		t.Errorf("expected conditional, got %s", decision)
+	}
+	if !strings.Contains(reasons[len(reasons)-1], "need at least 2 quality scores") {
+		t.Errorf("unexpected reasons: %v", reasons)
+	}
+}
+
+func TestNewComparisonResult(t *testing.T) {
+	latency := 120.0
+	result := &EvaluationResult{
+		Metrics:      map[string][]float64{"quality": {0.5, 1.0}},
+		AvgLatencyMs: &latency,
+		TotalTests:   4,
+		PassedTests:  3,
+		FailedTests:  1,
+	}
+
+	comparison := NewComparisonResult(result, "quality")
+	if comparison.MeanQuality() != 0.75 || comparison.AvgLatencyMs != 120 || comparison.ErrorRate != 0.25 {
+		t.Errorf("unexpected comparison: %+v", comparison)
+	}
+}
@@ -0,0 +1,314 @@
+// Package evaluation provides load testing from recorded sessions.
+//
+// Replays recorded inputs against a live agent at a controlled rate and
+// reports latency and error statistics.
+package evaluation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// DefaultLoadMaxConcurrency is the default cap on in-flight requests.
+const DefaultLoadMaxConcurrency = 100
+
+// LoadGenerator replays recorded session inputs against a live agent at a
+// controlled rate, for system-level load testing before deploy.
+//
+// Inputs are taken from the recordings in order and cycled until the test
+// ends. The request rate ramps linearly from zero to the target over the
+// ramp-up period (default: a tenth of the duration), then holds. Requests
+// are dispatched on schedule regardless of how fast the agent responds;
+// when MaxConcurrency requests are already in flight, the request is
+// dropped and counted rather than queued, so saturation shows up in the
+// report instead of silently lowering the rate.
+//
+// Example:
+//
+//	recordings, _ := storage.ListRecordings(100, 0)
+//	generator, err := evaluation.NewLoadGenerator(recordings, 50, 2*time.Minute)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	generator.SetMaxConcurrency(200)
+//
+//	report := generator.Run(ctx, agent)
+//	fmt.Printf("%.1f rps, p99 %v, %.1f%% errors\n",
+//	    report.ThroughputRPS, report.P99, report.ErrorRate*100)
+type LoadGenerator struct {
+	inputs         []*agenkit.Message
+	targetRPS      int
+	duration       time.Duration
+	rampUp         time.Duration
+	maxConcurrency int
+}
+
+// NewLoadGenerator creates a load generator that replays the inputs of
+// recordings at targetRPS requests per second for duration.
+//
+// Returns an error if the recordings contain no interactions or if
+// targetRPS or duration is not positive.
+func NewLoadGenerator(recordings []*SessionRecording, targetRPS int, duration time.Duration) (*LoadGenerator, error) {
+	if targetRPS <= 0 {
+		return nil, fmt.Errorf("targetRPS must be positive, got %d", targetRPS)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive, got %v", duration)
+	}
+
+	var inputs []*agenkit.Message
+	for _, recording := range recordings {
+		if recording == nil {
+			continue
+		}
+		for _, interaction := range recording.Interactions {
+			inputs = append(inputs, interactionInput(interaction))
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("recordings contain no interactions to replay")
+	}
+
+	return &LoadGenerator{
+		inputs:         inputs,
+		targetRPS:      targetRPS,
+		duration:       duration,
+		rampUp:         duration / 10,
+		maxConcurrency: DefaultLoadMaxConcurrency,
+	}, nil
+}
+
+// SetRampUp sets how long the rate takes to climb from zero to the target.
+// Zero starts at the full rate; values above the duration are capped to it.
+func (g *LoadGenerator) SetRampUp(rampUp time.Duration) {
+	if rampUp < 0 {
+		rampUp = 0
+	}
+	if rampUp > g.duration {
+		rampUp = g.duration
+	}
+	g.rampUp = rampUp
+}
+
+// RampUp returns the ramp-up period.
+func (g *LoadGenerator) RampUp() time.Duration {
+	return g.rampUp
+}
+
+// SetMaxConcurrency caps the number of in-flight requests (values below 1
+// restore DefaultLoadMaxConcurrency).
+func (g *LoadGenerator) SetMaxConcurrency(maxConcurrency int) {
+	if maxConcurrency < 1 {
+		maxConcurrency = DefaultLoadMaxConcurrency
+	}
+	g.maxConcurrency = maxConcurrency
+}
+
+// MaxConcurrency returns the cap on in-flight requests.
+func (g *LoadGenerator) MaxConcurrency() int {
+	return g.maxConcurrency
+}
+
+// LoadTestReport summarizes a load test run.
+type LoadTestReport struct {
+	// AgentName is the name of the agent under test
+	AgentName string
+	// TargetRPS is the requested steady-state rate
+	TargetRPS int
+	// Requests is the number of requests sent to the agent
+	Requests int
+	// Succeeded is the number of requests that returned without error
+	Succeeded int
+	// Errors is the number of requests that returned an error
+	Errors int
+	// Dropped is the number of scheduled requests skipped because
+	// MaxConcurrency requests were already in flight
+	Dropped int
+	// ErrorRate is Errors / Requests
+	ErrorRate float64
+	// Duration is the wall-clock time from the first dispatch until the
+	// last in-flight request finished
+	Duration time.Duration
+	// ThroughputRPS is the number of successful requests per second
+	ThroughputRPS float64
+	// PeakConcurrency is the highest number of requests in flight at once
+	PeakConcurrency int
+	// MeanLatency is the average per-request latency
+	MeanLatency time.Duration
+	// P50 is the median per-request latency
+	P50 time.Duration
+	// P95 is the 95th percentile per-request latency
+	P95 time.Duration
+	// P99 is the 99th percentile per-request latency
+	P99 time.Duration
+	// MaxLatency is the slowest observed request
+	MaxLatency time.Duration
+	// ErrorCounts counts errors by message
+	ErrorCounts map[string]int
+}
+
+// ToDict converts the report to a dictionary.
+func (r *LoadTestReport) ToDict() map[string]interface{} {
+	return map[string]interface{}{
+		"agent_name":       r.AgentName,
+		"target_rps":       r.TargetRPS,
+		"requests":         r.Requests,
+		"succeeded":        r.Succeeded,
+		"errors":           r.Errors,
+		"dropped":          r.Dropped,
+		"error_rate":       r.ErrorRate,
+		"duration_ms":      float64(r.Duration) / float64(time.Millisecond),
+		"throughput_rps":   r.ThroughputRPS,
+		"peak_concurrency": r.PeakConcurrency,
+		"mean_ms":          float64(r.MeanLatency) / float64(time.Millisecond),
+		"p50_ms":           float64(r.P50) / float64(time.Millisecond),
+		"p95_ms":           float64(r.P95) / float64(time.Millisecond),
+		"p99_ms":           float64(r.P99) / float64(time.Millisecond),
+		"max_ms":           float64(r.MaxLatency) / float64(time.Millisecond),
+		"error_counts":     r.ErrorCounts,
+	}
+}
+
+// Run drives agent with the recorded inputs until the duration elapses or
+// ctx is cancelled, waits for in-flight requests to finish, and reports
+// latency, error and throughput statistics. Requests receive ctx, so
+// cancelling it also cancels in-flight calls.
+func (g *LoadGenerator) Run(ctx context.Context, agent agenkit.Agent) *LoadTestReport {
+	var (
+		mu          sync.Mutex
+		wg          sync.WaitGroup
+		latencies   []time.Duration
+		errorCounts = make(map[string]int)
+		inFlight    int
+		peak        int
+	)
+	report := &LoadTestReport{
+		AgentName: agent.Name(),
+		TargetRPS: g.targetRPS,
+	}
+	slots := make(chan struct{}, g.maxConcurrency)
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	start := time.Now()
+dispatch:
+	for n := 0; ; n++ {
+		offset := g.dispatchOffset(n)
+		if offset >= g.duration {
+			break
+		}
+		if wait := time.Until(start.Add(offset)); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				break dispatch
+			}
+		} else if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			report.Dropped++
+			continue
+		}
+
+		report.Requests++
+		input := g.input(n)
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			callStart := time.Now()
+			_, err := agent.Process(ctx, input)
+			latency := time.Since(callStart)
+
+			mu.Lock()
+			defer mu.Unlock()
+			inFlight--
+			<-slots
+			latencies = append(latencies, latency)
+			if err != nil {
+				errorCounts[err.Error()]++
+				report.Errors++
+			} else {
+				report.Succeeded++
+			}
+		}()
+	}
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	report.PeakConcurrency = peak
+	report.ErrorCounts = errorCounts
+	if report.Requests > 0 {
+		report.ErrorRate = float64(report.Errors) / float64(report.Requests)
+	}
+	if report.Duration > 0 {
+		report.ThroughputRPS = float64(report.Succeeded) / report.Duration.Seconds()
+	}
+
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		var total time.Duration
+		for _, latency := range latencies {
+			total += latency
+		}
+		report.MeanLatency = total / time.Duration(len(latencies))
+		report.P50 = durationPercentile(latencies, 50)
+		report.P95 = durationPercentile(latencies, 95)
+		report.P99 = durationPercentile(latencies, 99)
+		report.MaxLatency = latencies[len(latencies)-1]
+	}
+
+	return report
+}
+
+// dispatchOffset returns when request n (0-based) is due, relative to the
+// start of the run, under a linear ramp to the target rate.
+func (g *LoadGenerator) dispatchOffset(n int) time.Duration {
+	rate := float64(g.targetRPS)
+	ramp := g.rampUp.Seconds()
+	count := float64(n)
+
+	// The ramp delivers rate*ramp/2 requests; request n is due when the
+	// cumulative count, rate*t²/(2*ramp), reaches n
+	var seconds float64
+	if rampRequests := rate * ramp / 2; count < rampRequests {
+		seconds = math.Sqrt(2 * count * ramp / rate)
+	} else {
+		seconds = ramp + (count-rampRequests)/rate
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// input returns a copy of the n-th replayed input, cycling through the
+// recordings, so concurrent requests don't share metadata maps.
+func (g *LoadGenerator) input(n int) *agenkit.Message {
+	recorded := g.inputs[n%len(g.inputs)]
+	metadata := make(map[string]interface{}, len(recorded.Metadata))
+	for key, value := range recorded.Metadata {
+		metadata[key] = value
+	}
+	return &agenkit.Message{
+		Role:      recorded.Role,
+		Content:   recorded.Content,
+		Metadata:  metadata,
+		Timestamp: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,144 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// loadTestAgent sleeps for delay and fails every failEvery-th call.
+type loadTestAgent struct {
+	echoAgent
+	delay     time.Duration
+	failEvery int64
+	calls     atomic.Int64
+}
+
+func (a *loadTestAgent) Process(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+	n := a.calls.Add(1)
+	time.Sleep(a.delay)
+	if a.failEvery > 0 && n%a.failEvery == 0 {
+		return nil, errors.New("overloaded")
+	}
+	return agenkit.NewMessage("agent", msg.ContentString()), nil
+}
+
+func loadTestRecordings(inputs ...string) []*SessionRecording {
+	recorder := NewSessionRecorder(nil)
+	for _, input := range inputs {
+		recorder.RecordInteraction("s1", agenkit.NewMessage("user", input), agenkit.NewMessage("agent", "ok"), 1, nil)
+	}
+	recording, _ := recorder.FinalizeSession("s1")
+	return []*SessionRecording{recording}
+}
+
+func TestNewLoadGenerator_Validation(t *testing.T) {
+	recordings := loadTestRecordings("hello")
+
+	if _, err := NewLoadGenerator(recordings, 0, time.Second); err == nil {
+		t.Error("expected error for zero rate")
+	}
+	if _, err := NewLoadGenerator(recordings, 10, 0); err == nil {
+		t.Error("expected error for zero duration")
+	}
+	if _, err := NewLoadGenerator([]*SessionRecording{{SessionID: "empty"}}, 10, time.Second); err == nil {
+		t.Error("expected error for recordings without interactions")
+	}
+
+	generator, err := NewLoadGenerator(recordings, 10, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if generator.RampUp() != 100*time.Millisecond || generator.MaxConcurrency() != DefaultLoadMaxConcurrency {
+		t.Errorf("unexpected defaults: ramp %v, concurrency %d", generator.RampUp(), generator.MaxConcurrency())
+	}
+}
+
+func TestLoadGenerator_DispatchOffset(t *testing.T) {
+	generator, _ := NewLoadGenerator(loadTestRecordings("hello"), 100, 10*time.Second)
+	generator.SetRampUp(2 * time.Second)
+
+	// The ramp delivers 100 requests in 2s, then 100 per second
+	if got := generator.dispatchOffset(0); got != 0 {
+		t.Errorf("expected first request at 0, got %v", got)
+	}
+	if got := generator.dispatchOffset(25); got != time.Second {
+		t.Errorf("expected request 25 at 1s, got %v", got)
+	}
+	if got := generator.dispatchOffset(100); got != 2*time.Second {
+		t.Errorf("expected request 100 at 2s, got %v", got)
+	}
+	if got := generator.dispatchOffset(150); got != 2500*time.Millisecond {
+		t.Errorf("expected request 150 at 2.5s, got %v", got)
+	}
+
+	generator.SetRampUp(0)
+	if got := generator.dispatchOffset(50); got != 500*time.Millisecond {
+		t.Errorf("expected request 50 at 0.5s without ramp, got %v", got)
+	}
+}
+
+func TestLoadGenerator_Run(t *testing.T) {
+	generator, err := NewLoadGenerator(loadTestRecordings("a", "b", "c"), 200, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	generator.SetRampUp(0)
+	agent := &loadTestAgent{delay: 5 * time.Millisecond, failEvery: 4}
+
+	report := generator.Run(context.Background(), agent)
+
+	if report.Requests != 40 || report.Dropped != 0 {
+		t.Errorf("expected 40 requests, got %d (%d dropped)", report.Requests, report.Dropped)
+	}
+	if report.Errors != 10 || report.Succeeded != 30 || report.ErrorRate != 0.25 {
+		t.Errorf("unexpected outcome: %+v", report)
+	}
+	if report.ErrorCounts["overloaded"] != 10 {
+		t.Errorf("unexpected error counts: %v", report.ErrorCounts)
+	}
+	if report.P50 < 5*time.Millisecond || report.MaxLatency < report.P99 {
+		t.Errorf("unexpected latencies: p50 %v, p99 %v, max %v", report.P50, report.P99, report.MaxLatency)
+	}
+	if report.Duration < 195*time.Millisecond || report.ThroughputRPS <= 0 {
+		t.Errorf("unexpected duration %v or throughput %v", report.Duration, report.ThroughputRPS)
+	}
+}
+
+func TestLoadGenerator_ConcurrencyCap(t *testing.T) {
+	generator, _ := NewLoadGenerator(loadTestRecordings("a"), 100, 100*time.Millisecond)
+	generator.SetRampUp(0)
+	generator.SetMaxConcurrency(2)
+	agent := &loadTestAgent{delay: 50 * time.Millisecond}
+
+	report := generator.Run(context.Background(), agent)
+
+	if report.PeakConcurrency > 2 {
+		t.Errorf("expected at most 2 in flight, got %d", report.PeakConcurrency)
+	}
+	if report.Dropped == 0 || report.Requests+report.Dropped != 10 {
+		t.Errorf("expected saturated requests to be dropped: %d sent, %d dropped", report.Requests, report.Dropped)
+	}
+	if int(agent.calls.Load()) != report.Requests {
+		t.Errorf("expected %d agent calls, got %d", report.Requests, agent.calls.Load())
+	}
+}
+
+func TestLoadGenerator_RunCancelled(t *testing.T) {
+	generator, _ := NewLoadGenerator(loadTestRecordings("a"), 100, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	report := generator.Run(ctx, &loadTestAgent{})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected run to stop on cancellation, took %v", elapsed)
+	}
+	if report.Requests == 0 {
+		t.Error("expected requests before cancellation")
+	}
+}
@@ -22,6 +22,7 @@ package evaluation
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
@@ -247,24 +248,117 @@ func FromJSON(jsonStr string) (*SessionResult, error) {
 type MetricsCollector struct {
 	mu      sync.RWMutex
 	results []SessionResult
+	// addedAt records when each result was added (parallel to results)
+	addedAt []time.Time
+	// window evicts results older than this duration (0 = unbounded)
+	window time.Duration
+	// maxResults evicts the oldest results beyond this count (0 = unbounded)
+	maxResults int
+	// now returns the current time (overridable in tests)
+	now func() time.Time
+	// store receives results on Flush (nil = in-memory only)
+	store MetricsStore
+	// pending holds results added since the last flush
+	pending []StoredResult
+	// flushMu serializes flushes so results are appended in order
+	flushMu sync.Mutex
 }
 
 // NewMetricsCollector creates a new metrics collector.
 func NewMetricsCollector() *MetricsCollector {
 	return &MetricsCollector{
 		results: make([]SessionResult, 0),
+		addedAt: make([]time.Time, 0),
+		now:     time.Now,
 	}
 }
 
+// NewWindowedMetricsCollector creates a metrics collector whose statistics
+// only reflect results added within the trailing window.
+//
+// Results older than the window are evicted, so long-running processes report
+// recent behavior ("last 5 minutes") rather than lifetime aggregates.
+//
+// Example:
+//
+//	collector := evaluation.NewWindowedMetricsCollector(5 * time.Minute)
+func NewWindowedMetricsCollector(window time.Duration) *MetricsCollector {
+	mc := NewMetricsCollector()
+	mc.window = window
+	return mc
+}
+
+// NewBoundedMetricsCollector creates a metrics collector that keeps only the
+// most recent maxResults results, acting as a ring buffer by count.
+func NewBoundedMetricsCollector(maxResults int) *MetricsCollector {
+	mc := NewMetricsCollector()
+	mc.maxResults = maxResults
+	return mc
+}
+
+// Window returns the time window of the collector (0 if unbounded).
+func (mc *MetricsCollector) Window() time.Duration {
+	return mc.window
+}
+
 // AddResult adds a session result to the collector.
 // Thread-safe for concurrent access.
 func (mc *MetricsCollector) AddResult(result *SessionResult) {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
+	addedAt := mc.currentTime()
 	mc.results = append(mc.results, *result)
+	mc.addedAt = append(mc.addedAt, addedAt)
+	if mc.store != nil {
+		mc.pending = append(mc.pending, StoredResult{Result: *result, AddedAt: addedAt})
+	}
+	mc.evictLocked()
+}
+
+// currentTime returns the collector's notion of now.
+func (mc *MetricsCollector) currentTime() time.Time {
+	if mc.now == nil {
+		return time.Now()
+	}
+	return mc.now()
+}
+
+// evict removes results that fall outside the window or count limit.
+func (mc *MetricsCollector) evict() {
+	if mc.window <= 0 && mc.maxResults <= 0 {
+		return
+	}
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.evictLocked()
+}
+
+// evictLocked removes expired results. Caller must hold the write lock.
+func (mc *MetricsCollector) evictLocked() {
+	drop := 0
+
+	if mc.window > 0 {
+		cutoff := mc.currentTime().Add(-mc.window)
+		for drop < len(mc.addedAt) && mc.addedAt[drop].Before(cutoff) {
+			drop++
+		}
+	}
+
+	if mc.maxResults > 0 && len(mc.results)-drop > mc.maxResults {
+		drop = len(mc.results) - mc.maxResults
+	}
+
+	if drop == 0 {
+		return
+	}
+
+	// Copy into fresh slices so evicted results can be garbage collected
+	mc.results = append(make([]SessionResult, 0, len(mc.results)-drop), mc.results[drop:]...)
+	mc.addedAt = append(make([]time.Time, 0, len(mc.addedAt)-drop), mc.addedAt[drop:]...)
 }
 
 // GetStatistics computes aggregated statistics across all collected results.
+// For windowed or bounded collectors, only results still retained are included.
 // Thread-safe for concurrent access.
 //
 // Returns a map with statistics including:
@@ -276,6 +370,7 @@ func (mc *MetricsCollector) AddResult(result *SessionResult) {
 //   - total_errors: Total number of errors across all sessions
 //   - avg_errors_per_session: Average errors per session
 func (mc *MetricsCollector) GetStatistics() map[string]interface{} {
+	mc.evict()
 	mc.mu.RLock()
 	defer mc.mu.RUnlock()
 
@@ -335,6 +430,7 @@ func (mc *MetricsCollector) GetStatistics() map[string]interface{} {
 //
 //	Map with statistics: count, sum, mean, min, max
 func (mc *MetricsCollector) GetMetricAggregates(metricName string) map[string]interface{} {
+	mc.evict()
 	mc.mu.RLock()
 	defer mc.mu.RUnlock()
 
@@ -377,9 +473,129 @@ func (mc *MetricsCollector) GetMetricAggregates(metricName string) map[string]in
 	}
 }
 
+// ErrorSummary aggregates the errors of one type across collected results.
+type ErrorSummary struct {
+	// Type is the error type shared by the grouped errors
+	Type string `json:"type"`
+	// Count is the number of errors of this type
+	Count int `json:"count"`
+	// Sessions is the number of sessions that recorded this type
+	Sessions int `json:"sessions"`
+	// FirstSeen is when the earliest error of this type occurred
+	FirstSeen time.Time `json:"first_seen"`
+	// LastSeen is when the most recent error of this type occurred
+	LastSeen time.Time `json:"last_seen"`
+	// LastMessage is the message of the most recent error of this type
+	LastMessage string `json:"last_message"`
+}
+
+// TopErrors groups the errors in the retained results by type and returns
+// the n most frequent (all types if n <= 0), most frequent first. Ties are
+// broken by the most recently seen.
+// Thread-safe for concurrent access.
+//
+// Example:
+//
+//	for _, summary := range collector.TopErrors(3) {
+//	    fmt.Printf("%s: %d (last %s ago)\n",
+//	        summary.Type, summary.Count, time.Since(summary.LastSeen).Round(time.Second))
+//	}
+func (mc *MetricsCollector) TopErrors(n int) []ErrorSummary {
+	mc.evict()
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	byType := make(map[string]*ErrorSummary)
+	for i, result := range mc.results {
+		seenInSession := make(map[string]bool)
+		for _, record := range result.Errors {
+			occurred := errorTime(record, mc.addedAt[i])
+
+			summary, ok := byType[record.Type]
+			if !ok {
+				summary = &ErrorSummary{Type: record.Type, FirstSeen: occurred, LastSeen: occurred}
+				byType[record.Type] = summary
+			}
+			summary.Count++
+			if !seenInSession[record.Type] {
+				seenInSession[record.Type] = true
+				summary.Sessions++
+			}
+			if occurred.Before(summary.FirstSeen) {
+				summary.FirstSeen = occurred
+			}
+			if !occurred.Before(summary.LastSeen) {
+				summary.LastSeen = occurred
+				summary.LastMessage = record.Message
+			}
+		}
+	}
+
+	summaries := make([]ErrorSummary, 0, len(byType))
+	for _, summary := range byType {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Count != summaries[j].Count {
+			return summaries[i].Count > summaries[j].Count
+		}
+		if !summaries[i].LastSeen.Equal(summaries[j].LastSeen) {
+			return summaries[i].LastSeen.After(summaries[j].LastSeen)
+		}
+		return summaries[i].Type < summaries[j].Type
+	})
+
+	if n > 0 && len(summaries) > n {
+		summaries = summaries[:n]
+	}
+	return summaries
+}
+
+// ErrorRate returns the fraction of sessions added within the trailing
+// window that recorded at least one error (0 if there are none). A window
+// <= 0 covers every retained result. Comparing a short window with a long
+// one shows whether errors are trending up.
+// Thread-safe for concurrent access.
+func (mc *MetricsCollector) ErrorRate(window time.Duration) float64 {
+	mc.evict()
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	var cutoff time.Time
+	if window > 0 {
+		cutoff = mc.currentTime().Add(-window)
+	}
+
+	sessions, failed := 0, 0
+	for i, result := range mc.results {
+		if window > 0 && mc.addedAt[i].Before(cutoff) {
+			continue
+		}
+		sessions++
+		if len(result.Errors) > 0 {
+			failed++
+		}
+	}
+
+	if sessions == 0 {
+		return 0.0
+	}
+	return float64(failed) / float64(sessions)
+}
+
+// errorTime returns when record occurred, falling back to when its result
+// was added if the timestamp is missing or malformed.
+func errorTime(record ErrorRecord, addedAt time.Time) time.Time {
+	if occurred, err := time.Parse(time.RFC3339, record.Timestamp); err == nil {
+		return occurred
+	}
+	return addedAt
+}
+
 // GetResults returns all collected session results.
 // Thread-safe for concurrent access.
 func (mc *MetricsCollector) GetResults() []SessionResult {
+	mc.evict()
 	mc.mu.RLock()
 	defer mc.mu.RUnlock()
 	// Return a copy to prevent external mutation
@@ -388,12 +604,14 @@ func (mc *MetricsCollector) GetResults() []SessionResult {
 	return results
 }
 
-// Clear removes all collected results.
+// Clear removes all collected results. Results not yet flushed to the
+// attached store are still written by the next Flush.
 // Thread-safe for concurrent access.
 func (mc *MetricsCollector) Clear() {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
 	mc.results = make([]SessionResult, 0)
+	mc.addedAt = make([]time.Time, 0)
 }
 
 // CreateQualityMetric creates a quality score metric measurement.
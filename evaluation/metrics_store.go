@@ -0,0 +1,281 @@
+// Package evaluation provides persistent storage for evaluation metrics.
+//
+// Stores keep MetricsCollector results across restarts, in files or
+// in memory.
+package evaluation
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StoredResult is a session result persisted by a MetricsStore, along with
+// the time it was added to the collector so windows survive a reload.
+type StoredResult struct {
+	Result  SessionResult `json:"result"`
+	AddedAt time.Time     `json:"added_at"`
+}
+
+// MetricsStore persists MetricsCollector results across restarts.
+//
+// Stores are append-only: a collector appends each result once, on the
+// first Flush after it was added. Implement this to create custom storage
+// (Redis, S3, Postgres, etc.).
+type MetricsStore interface {
+	// Append adds results to the store.
+	Append(ctx context.Context, results []StoredResult) error
+
+	// Load returns all stored results in the order they were appended.
+	Load(ctx context.Context) ([]StoredResult, error)
+}
+
+// FileMetricsStore provides file-based metrics storage.
+//
+// Stores results as JSON Lines, one result per line, so flushing only
+// appends new results instead of rewriting the file.
+type FileMetricsStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileMetricsStore creates a new file metrics store.
+//
+// Args:
+//
+//	path: File to store results in (created on first append)
+//
+// Example:
+//
+//	store := NewFileMetricsStore("./metrics/results.jsonl")
+func NewFileMetricsStore(path string) *FileMetricsStore {
+	if path == "" {
+		path = "./metrics.jsonl"
+	}
+	return &FileMetricsStore{path: path}
+}
+
+// Path returns the file the store writes to.
+func (s *FileMetricsStore) Path() string {
+	return s.path
+}
+
+// Append appends results to the file.
+func (s *FileMetricsStore) Append(ctx context.Context, results []StoredResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	// Encode everything first so a failure doesn't leave a partial batch
+	var buf []byte
+	for _, result := range results {
+		line, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode result %s: %w", result.Result.SessionID, err)
+		}
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(buf); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Load reads all results from the file. A missing file holds no results.
+func (s *FileMetricsStore) Load(ctx context.Context) ([]StoredResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make([]StoredResult, 0), nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	results := make([]StoredResult, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var result StoredResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.path, line, err)
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// MemoryMetricsStore provides in-memory metrics storage for testing.
+//
+// Does not persist results across restarts.
+type MemoryMetricsStore struct {
+	mu      sync.Mutex
+	results []StoredResult
+}
+
+// NewMemoryMetricsStore creates a new in-memory metrics store.
+func NewMemoryMetricsStore() *MemoryMetricsStore {
+	return &MemoryMetricsStore{
+		results: make([]StoredResult, 0),
+	}
+}
+
+// Append adds results to memory.
+func (s *MemoryMetricsStore) Append(ctx context.Context, results []StoredResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, results...)
+	return nil
+}
+
+// Load returns a copy of the results in memory.
+func (s *MemoryMetricsStore) Load(ctx context.Context) ([]StoredResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := make([]StoredResult, len(s.results))
+	copy(results, s.results)
+	return results, nil
+}
+
+// LoadMetricsCollector creates an unbounded metrics collector holding every
+// result in store, with store attached so new results are flushed to it.
+//
+// To rehydrate a windowed or bounded collector, create it first and call
+// Restore instead.
+//
+// Example:
+//
+//	store := evaluation.NewFileMetricsStore("./metrics/results.jsonl")
+//	collector, err := evaluation.LoadMetricsCollector(ctx, store)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer collector.Close()
+func LoadMetricsCollector(ctx context.Context, store MetricsStore) (*MetricsCollector, error) {
+	mc := NewMetricsCollector()
+	if err := mc.Restore(ctx, store); err != nil {
+		return nil, err
+	}
+	return mc, nil
+}
+
+// SetStore attaches a store that Flush writes new results to.
+//
+// Only results added after the store is attached are flushed; use Restore
+// to load existing results from the store and attach it in one step.
+func (mc *MetricsCollector) SetStore(store MetricsStore) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.store = store
+	mc.pending = nil
+}
+
+// Store returns the attached store (nil if none).
+func (mc *MetricsCollector) Store() MetricsStore {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+	return mc.store
+}
+
+// Restore loads the results in store into the collector and attaches store.
+//
+// Loaded results keep the time they were originally added, so windowed and
+// bounded collectors immediately evict those outside their limits. Loaded
+// results are not flushed again.
+func (mc *MetricsCollector) Restore(ctx context.Context, store MetricsStore) error {
+	if store == nil {
+		return fmt.Errorf("store is required")
+	}
+	stored, err := store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load metrics: %w", err)
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	for _, s := range stored {
+		mc.results = append(mc.results, s.Result)
+		mc.addedAt = append(mc.addedAt, s.AddedAt)
+	}
+	mc.store = store
+	mc.pending = nil
+	mc.evictLocked()
+	return nil
+}
+
+// Flush appends results added since the last flush to the attached store.
+//
+// Unflushed results are kept even if the collector has since evicted them,
+// so the store accumulates long-term history while the collector reports
+// on its window. Flush is a no-op without a store. On failure the results
+// stay pending and are retried by the next Flush.
+func (mc *MetricsCollector) Flush(ctx context.Context) error {
+	mc.flushMu.Lock()
+	defer mc.flushMu.Unlock()
+
+	mc.mu.Lock()
+	store := mc.store
+	batch := mc.pending
+	mc.pending = nil
+	mc.mu.Unlock()
+
+	if store == nil || len(batch) == 0 {
+		return nil
+	}
+
+	if err := store.Append(ctx, batch); err != nil {
+		mc.mu.Lock()
+		if mc.store == store {
+			mc.pending = append(batch, mc.pending...)
+		}
+		mc.mu.Unlock()
+		return fmt.Errorf("failed to flush metrics: %w", err)
+	}
+	return nil
+}
+
+// Close flushes pending results to the attached store. Call it on shutdown
+// so the last results are not lost; the collector remains usable afterwards.
+func (mc *MetricsCollector) Close() error {
+	return mc.Flush(context.Background())
+}
@@ -0,0 +1,212 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// failingMetricsStore fails every Append until fail is cleared.
+type failingMetricsStore struct {
+	*MemoryMetricsStore
+	fail bool
+}
+
+func (s *failingMetricsStore) Append(ctx context.Context, results []StoredResult) error {
+	if s.fail {
+		return errors.New("store unavailable")
+	}
+	return s.MemoryMetricsStore.Append(ctx, results)
+}
+
+func completedResult(id string) *SessionResult {
+	result := NewSessionResult(id, "agent")
+	result.AddMetricMeasurement(NewMetricMeasurement("latency", 1.5, MetricTypeDuration))
+	result.SetStatus(SessionStatusCompleted)
+	return result
+}
+
+func TestMetricsCollector_FlushAppendsIncrementally(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryMetricsStore()
+	collector := NewMetricsCollector()
+	collector.SetStore(store)
+
+	collector.AddResult(completedResult("s1"))
+	collector.AddResult(completedResult("s2"))
+	if err := collector.Flush(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	collector.AddResult(completedResult("s3"))
+	if err := collector.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := collector.Flush(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, _ := store.Load(ctx)
+	if len(stored) != 3 {
+		t.Fatalf("expected each result stored once, got %d", len(stored))
+	}
+	for i, id := range []string{"s1", "s2", "s3"} {
+		if stored[i].Result.SessionID != id {
+			t.Errorf("expected %s at %d, got %s", id, i, stored[i].Result.SessionID)
+		}
+	}
+}
+
+func TestMetricsCollector_FlushWithoutStore(t *testing.T) {
+	collector := NewMetricsCollector()
+	collector.AddResult(completedResult("s1"))
+	if err := collector.Flush(context.Background()); err != nil {
+		t.Errorf("expected no-op flush, got %v", err)
+	}
+}
+
+func TestMetricsCollector_FlushKeepsEvictedResults(t *testing.T) {
+	store := NewMemoryMetricsStore()
+	collector := NewBoundedMetricsCollector(1)
+	collector.SetStore(store)
+
+	collector.AddResult(completedResult("s1"))
+	collector.AddResult(completedResult("s2"))
+	if err := collector.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(collector.GetResults()); got != 1 {
+		t.Errorf("expected collector to keep 1 result, got %d", got)
+	}
+	if stored, _ := store.Load(context.Background()); len(stored) != 2 {
+		t.Errorf("expected evicted result to be stored, got %d", len(stored))
+	}
+}
+
+func TestMetricsCollector_FlushRetriesAfterFailure(t *testing.T) {
+	store := &failingMetricsStore{MemoryMetricsStore: NewMemoryMetricsStore(), fail: true}
+	collector := NewMetricsCollector()
+	collector.SetStore(store)
+
+	collector.AddResult(completedResult("s1"))
+	if err := collector.Flush(context.Background()); err == nil {
+		t.Fatal("expected flush error")
+	}
+
+	collector.AddResult(completedResult("s2"))
+	store.fail = false
+	if err := collector.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, _ := store.Load(context.Background())
+	if len(stored) != 2 || stored[0].Result.SessionID != "s1" || stored[1].Result.SessionID != "s2" {
+		t.Errorf("expected s1 and s2 in order, got %+v", stored)
+	}
+}
+
+func TestLoadMetricsCollector(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryMetricsStore()
+
+	first := NewMetricsCollector()
+	first.SetStore(store)
+	first.AddResult(completedResult("s1"))
+	failed := NewSessionResult("s2", "agent")
+	failed.AddError("timeout", "took too long", nil)
+	failed.SetStatus(SessionStatusFailed)
+	first.AddResult(failed)
+	if err := first.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Simulated restart
+	second, err := LoadMetricsCollector(ctx, store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stats := second.GetStatistics()
+	if stats["session_count"] != 2 || stats["failed_count"] != 1 || stats["total_errors"] != 1 {
+		t.Errorf("unexpected statistics after reload: %v", stats)
+	}
+
+	// Reloaded results are not flushed again
+	second.AddResult(completedResult("s3"))
+	if err := second.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored, _ := store.Load(ctx); len(stored) != 3 {
+		t.Errorf("expected 3 stored results, got %d", len(stored))
+	}
+}
+
+func TestMetricsCollector_RestoreAppliesWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryMetricsStore()
+	_ = store.Append(ctx, []StoredResult{
+		{Result: *completedResult("old"), AddedAt: now.Add(-time.Hour)},
+		{Result: *completedResult("recent"), AddedAt: now.Add(-time.Minute)},
+	})
+
+	collector := NewWindowedMetricsCollector(5 * time.Minute)
+	collector.now = func() time.Time { return now }
+	if err := collector.Restore(ctx, store); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := collector.GetResults()
+	if len(results) != 1 || results[0].SessionID != "recent" {
+		t.Errorf("expected only the recent result, got %+v", results)
+	}
+	if collector.Store() != store {
+		t.Error("expected store to be attached")
+	}
+}
+
+func TestFileMetricsStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "metrics", "results.jsonl")
+	store := NewFileMetricsStore(path)
+
+	// Missing file holds no results
+	if stored, err := store.Load(ctx); err != nil || len(stored) != 0 {
+		t.Fatalf("expected empty load, got %v, %v", stored, err)
+	}
+
+	collector := NewMetricsCollector()
+	collector.SetStore(store)
+	for i := 0; i < 3; i++ {
+		collector.AddResult(completedResult(fmt.Sprintf("s%d", i)))
+		if err := collector.Flush(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	reloaded, err := LoadMetricsCollector(ctx, NewFileMetricsStore(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results := reloaded.GetResults()
+	if len(results) != 3 || results[2].SessionID != "s2" {
+		t.Fatalf("expected 3 results in order, got %+v", results)
+	}
+	if metric := results[0].GetMetric("latency"); metric == nil || metric.Value != 1.5 {
+		t.Errorf("expected latency measurement to round-trip, got %+v", metric)
+	}
+	if agg := reloaded.GetMetricAggregates("latency"); agg["count"] != 3 {
+		t.Errorf("expected 3 latency measurements, got %v", agg)
+	}
+
+	if err := os.WriteFile(path, []byte("not json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(ctx); err == nil {
+		t.Error("expected error for corrupt file")
+	}
+}
@@ -1,6 +1,8 @@
 package evaluation
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 )
@@ -461,3 +463,172 @@ func TestMetricsCollector_Clear(t *testing.T) {
 		t.Errorf("Expected 0 results after clear, got %d", len(collector.results))
 	}
 }
+
+func TestWindowedMetricsCollector_EvictsOldResults(t *testing.T) {
+	collector := NewWindowedMetricsCollector(5 * time.Minute)
+	current := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	collector.now = func() time.Time { return current }
+
+	old := NewSessionResult("old", "agent")
+	old.SetStatus(SessionStatusFailed)
+	collector.AddResult(old)
+
+	current = current.Add(4 * time.Minute)
+	recent := NewSessionResult("recent", "agent")
+	recent.SetStatus(SessionStatusCompleted)
+	collector.AddResult(recent)
+
+	stats := collector.GetStatistics()
+	if stats["session_count"] != 2 {
+		t.Errorf("Expected 2 sessions within window, got %v", stats["session_count"])
+	}
+
+	// Advance so only the recent result remains in the window
+	current = current.Add(2 * time.Minute)
+	stats = collector.GetStatistics()
+	if stats["session_count"] != 1 {
+		t.Errorf("Expected 1 session within window, got %v", stats["session_count"])
+	}
+	if stats["success_rate"] != 1.0 {
+		t.Errorf("Expected success rate 1.0, got %v", stats["success_rate"])
+	}
+
+	results := collector.GetResults()
+	if len(results) != 1 || results[0].SessionID != "recent" {
+		t.Errorf("Expected only 'recent' result retained, got %v", results)
+	}
+
+	// Advance past everything
+	current = current.Add(10 * time.Minute)
+	aggregates := collector.GetMetricAggregates("accuracy")
+	if aggregates["count"] != 0 {
+		t.Errorf("Expected no measurements after window expiry, got %v", aggregates["count"])
+	}
+	if collector.Window() != 5*time.Minute {
+		t.Errorf("Expected window 5m, got %v", collector.Window())
+	}
+}
+
+func TestBoundedMetricsCollector_KeepsMostRecent(t *testing.T) {
+	collector := NewBoundedMetricsCollector(3)
+
+	for i := 0; i < 5; i++ {
+		result := NewSessionResult(fmt.Sprintf("session-%d", i), "agent")
+		result.AddMetricMeasurement(NewMetricMeasurement("accuracy", float64(i), MetricTypeSuccessRate))
+		collector.AddResult(result)
+	}
+
+	results := collector.GetResults()
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 retained results, got %d", len(results))
+	}
+	if results[0].SessionID != "session-2" {
+		t.Errorf("Expected oldest retained result 'session-2', got %q", results[0].SessionID)
+	}
+
+	aggregates := collector.GetMetricAggregates("accuracy")
+	if aggregates["min"] != 2.0 || aggregates["max"] != 4.0 {
+		t.Errorf("Expected min 2 and max 4, got %v", aggregates)
+	}
+}
+
+func TestWindowedMetricsCollector_ConcurrentAccess(t *testing.T) {
+	collector := NewWindowedMetricsCollector(time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			collector.AddResult(NewSessionResult(fmt.Sprintf("s-%d", n), "agent"))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = collector.GetStatistics()
+		}()
+	}
+	wg.Wait()
+
+	if stats := collector.GetStatistics(); stats["session_count"] != 20 {
+		t.Errorf("Expected 20 sessions, got %v", stats["session_count"])
+	}
+}
+
+func TestMetricsCollector_TopErrors(t *testing.T) {
+	collector := NewMetricsCollector()
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	first := NewSessionResult("s1", "agent")
+	first.Errors = []ErrorRecord{
+		{Type: "timeout", Message: "first timeout", Timestamp: base.Format(time.RFC3339)},
+		{Type: "timeout", Message: "second timeout", Timestamp: base.Add(time.Minute).Format(time.RFC3339)},
+		{Type: "rate_limit", Message: "slow down", Timestamp: base.Format(time.RFC3339)},
+	}
+	collector.AddResult(first)
+
+	second := NewSessionResult("s2", "agent")
+	second.Errors = []ErrorRecord{
+		{Type: "timeout", Message: "latest timeout", Timestamp: base.Add(2 * time.Minute).Format(time.RFC3339)},
+		{Type: "parse_error", Message: "bad json", Timestamp: base.Add(3 * time.Minute).Format(time.RFC3339)},
+	}
+	collector.AddResult(second)
+	collector.AddResult(NewSessionResult("s3", "agent"))
+
+	top := collector.TopErrors(0)
+	if len(top) != 3 {
+		t.Fatalf("Expected 3 error types, got %d", len(top))
+	}
+
+	timeout := top[0]
+	if timeout.Type != "timeout" || timeout.Count != 3 || timeout.Sessions != 2 {
+		t.Errorf("Expected timeout x3 across 2 sessions first, got %+v", timeout)
+	}
+	if !timeout.FirstSeen.Equal(base) || !timeout.LastSeen.Equal(base.Add(2*time.Minute)) {
+		t.Errorf("Unexpected timeout window: %v - %v", timeout.FirstSeen, timeout.LastSeen)
+	}
+	if timeout.LastMessage != "latest timeout" {
+		t.Errorf("Expected latest message, got %q", timeout.LastMessage)
+	}
+
+	// Equal counts are ordered by most recently seen
+	if top[1].Type != "parse_error" || top[2].Type != "rate_limit" {
+		t.Errorf("Expected parse_error before rate_limit, got %s, %s", top[1].Type, top[2].Type)
+	}
+
+	if limited := collector.TopErrors(1); len(limited) != 1 || limited[0].Type != "timeout" {
+		t.Errorf("Expected only timeout with n=1, got %+v", limited)
+	}
+}
+
+func TestMetricsCollector_ErrorRate(t *testing.T) {
+	collector := NewMetricsCollector()
+	current := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	collector.now = func() time.Time { return current }
+
+	if rate := collector.ErrorRate(time.Minute); rate != 0.0 {
+		t.Errorf("Expected 0 with no sessions, got %v", rate)
+	}
+
+	for i := 0; i < 4; i++ {
+		result := NewSessionResult(fmt.Sprintf("early-%d", i), "agent")
+		if i == 0 {
+			result.AddError("timeout", "timed out", nil)
+		}
+		collector.AddResult(result)
+	}
+
+	current = current.Add(10 * time.Minute)
+	for i := 0; i < 2; i++ {
+		result := NewSessionResult(fmt.Sprintf("late-%d", i), "agent")
+		result.AddError("timeout", "timed out", nil)
+		result.AddError("timeout", "timed out again", nil)
+		collector.AddResult(result)
+	}
+
+	if rate := collector.ErrorRate(5 * time.Minute); rate != 1.0 {
+		t.Errorf("Expected recent error rate 1.0, got %v", rate)
+	}
+	if rate := collector.ErrorRate(0); rate != 0.5 {
+		t.Errorf("Expected overall error rate 0.5, got %v", rate)
+	}
+}
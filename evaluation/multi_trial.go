@@ -0,0 +1,276 @@
+package evaluation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/google/uuid"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// MultiTrialConfidenceLevel is the confidence level of the intervals
+// EvaluateMultiTrial reports.
+const MultiTrialConfidenceLevel = 0.95
+
+// TrialStatistics summarizes one metric across the trials of a
+// multi-trial evaluation.
+type TrialStatistics struct {
+	// Values holds the metric's value in each trial, in trial order
+	Values []float64
+	// Mean is the mean across trials
+	Mean float64
+	// StdDev is the sample standard deviation across trials (0 for a
+	// single trial)
+	StdDev float64
+	// ConfidenceInterval is the Student's t confidence interval for the
+	// mean at MultiTrialConfidenceLevel (just the mean for a single trial)
+	ConfidenceInterval [2]float64
+}
+
+// ToDict converts the statistics to a map.
+func (s TrialStatistics) ToDict() map[string]interface{} {
+	return map[string]interface{}{
+		"values":              s.Values,
+		"mean":                s.Mean,
+		"std":                 s.StdDev,
+		"confidence_interval": s.ConfidenceInterval,
+	}
+}
+
+// TestCaseStability reports how consistently one test case behaved across
+// trials.
+type TestCaseStability struct {
+	// Index is the test case's position in the suite
+	Index int
+	// Input is the test case's input
+	Input string
+	// PassRate is the share of trials in which the test case passed
+	PassRate float64
+	// PassVariance is the variance of the pass/fail outcome, from 0 (always
+	// the same) to 0.25 (passes half the time)
+	PassVariance float64
+	// MetricStdDev is the sample standard deviation of each metric's value
+	// for this test case across trials
+	MetricStdDev map[string]float64
+	// Unstable is true if the test case passed in some trials and failed
+	// in others
+	Unstable bool
+}
+
+// MultiTrialResult contains the results of a multi-trial evaluation.
+type MultiTrialResult struct {
+	// EvaluationID identifies the multi-trial run; trial i's evaluation ID
+	// is EvaluationID + "-trial-" + i
+	EvaluationID string
+	// Seed is the seed the run was started with
+	Seed int64
+	// TrialSeeds holds the seed derived for each trial
+	TrialSeeds []int64
+	// Trials holds each trial's full result
+	Trials []*EvaluationResult
+	// Metrics summarizes each metric's per-trial mean measurement, plus
+	// "accuracy" (the trial's success rate) and "avg_latency_ms"
+	Metrics map[string]TrialStatistics
+	// TestCases reports the stability of every test case, in suite order
+	TestCases []TestCaseStability
+}
+
+// UnstableCases returns the test cases that passed in some trials and
+// failed in others.
+func (r *MultiTrialResult) UnstableCases() []TestCaseStability {
+	unstable := make([]TestCaseStability, 0)
+	for _, testCase := range r.TestCases {
+		if testCase.Unstable {
+			unstable = append(unstable, testCase)
+		}
+	}
+	return unstable
+}
+
+// ToDict converts the result to a map.
+func (r *MultiTrialResult) ToDict() map[string]interface{} {
+	metrics := make(map[string]interface{}, len(r.Metrics))
+	for name, statistics := range r.Metrics {
+		metrics[name] = statistics.ToDict()
+	}
+	testCases := make([]map[string]interface{}, len(r.TestCases))
+	for i, testCase := range r.TestCases {
+		testCases[i] = map[string]interface{}{
+			"index":         testCase.Index,
+			"input":         testCase.Input,
+			"pass_rate":     testCase.PassRate,
+			"pass_variance": testCase.PassVariance,
+			"metric_std":    testCase.MetricStdDev,
+			"unstable":      testCase.Unstable,
+		}
+	}
+	trials := make([]map[string]interface{}, len(r.Trials))
+	for i, trial := range r.Trials {
+		trials[i] = trial.ToDict()
+	}
+	return map[string]interface{}{
+		"evaluation_id":    r.EvaluationID,
+		"seed":             r.Seed,
+		"trial_seeds":      r.TrialSeeds,
+		"num_trials":       len(r.Trials),
+		"confidence_level": MultiTrialConfidenceLevel,
+		"metrics":          metrics,
+		"test_cases":       testCases,
+		"unstable_cases":   len(r.UnstableCases()),
+		"trials":           trials,
+	}
+}
+
+// EvaluateMultiTrial runs the suite nTrials times to measure a stochastic
+// agent with confidence rather than from a single run.
+//
+// Args:
+//
+//	testCases: List of test cases, each with 'input' and 'expected' keys
+//	nTrials: Number of times to run the suite (at least 1)
+//	seed: Seed making the run reproducible
+//
+// Returns:
+//
+//	MultiTrialResult with per-metric means, standard deviations and
+//	confidence intervals across trials, and per-test-case stability
+//
+// Each trial derives its own seed from seed. The trial runs the test cases
+// in an order shuffled with that seed, so order effects average out, and
+// passes it to the agent as input metadata "seed" (with "trial", the trial
+// index) for agents that can seed their sampling. Running again with the
+// same seed reproduces the same orders and seeds.
+//
+// Example:
+//
+//	result, err := evaluator.EvaluateMultiTrial(testCases, 10, 42)
+//	accuracy := result.Metrics["accuracy"]
+//	fmt.Printf("Accuracy: %.2f ± %.2f\n", accuracy.Mean, accuracy.StdDev)
+//	for _, testCase := range result.UnstableCases() {
+//	    fmt.Printf("Flaky: %s (passes %.0f%%)\n", testCase.Input, testCase.PassRate*100)
+//	}
+func (e *Evaluator) EvaluateMultiTrial(testCases []map[string]interface{}, nTrials int, seed int64) (*MultiTrialResult, error) {
+	if nTrials < 1 {
+		return nil, fmt.Errorf("nTrials must be at least 1, got %d", nTrials)
+	}
+
+	result := &MultiTrialResult{
+		EvaluationID: uuid.New().String(),
+		Seed:         seed,
+		TrialSeeds:   make([]int64, nTrials),
+		Trials:       make([]*EvaluationResult, nTrials),
+	}
+
+	// outcomes[c][t] is test case c's outcome in trial t
+	outcomes := make([][]caseOutcome, len(testCases))
+	for c := range outcomes {
+		outcomes[c] = make([]caseOutcome, nTrials)
+	}
+
+	seeds := rand.New(rand.NewSource(seed))
+	for t := 0; t < nTrials; t++ {
+		trialSeed := seeds.Int63()
+		result.TrialSeeds[t] = trialSeed
+
+		order := rand.New(rand.NewSource(trialSeed)).Perm(len(testCases))
+		shuffled := make([]map[string]interface{}, len(testCases))
+		for i, c := range order {
+			shuffled[i] = testCases[c]
+		}
+
+		trial, trialOutcomes := e.evaluate(shuffled, fmt.Sprintf("%s-trial-%d", result.EvaluationID, t),
+			map[string]interface{}{"seed": trialSeed, "trial": t})
+		trial.Metadata["seed"] = trialSeed
+		trial.Metadata["trial"] = t
+		result.Trials[t] = trial
+		for i, c := range order {
+			outcomes[c][t] = trialOutcomes[i]
+		}
+	}
+
+	result.Metrics = e.trialStatistics(result.Trials)
+	result.TestCases = make([]TestCaseStability, len(testCases))
+	for c, testCase := range testCases {
+		input, _ := testCase["input"].(string)
+		result.TestCases[c] = caseStability(c, input, outcomes[c])
+	}
+	return result, nil
+}
+
+// trialStatistics summarizes accuracy, latency and each metric's mean
+// across trials.
+func (e *Evaluator) trialStatistics(trials []*EvaluationResult) map[string]TrialStatistics {
+	perTrial := map[string][]float64{}
+	for _, trial := range trials {
+		perTrial["accuracy"] = append(perTrial["accuracy"], trial.SuccessRate())
+		if trial.AvgLatencyMs != nil {
+			perTrial["avg_latency_ms"] = append(perTrial["avg_latency_ms"], *trial.AvgLatencyMs)
+		}
+		for _, metric := range e.metrics {
+			if measurements := trial.Metrics[metric.Name()]; len(measurements) > 0 {
+				perTrial[metric.Name()] = append(perTrial[metric.Name()], stat.Mean(measurements, nil))
+			}
+		}
+	}
+
+	statistics := make(map[string]TrialStatistics, len(perTrial))
+	for name, values := range perTrial {
+		statistics[name] = summarizeTrials(values)
+	}
+	return statistics
+}
+
+// summarizeTrials computes the mean, sample standard deviation and t
+// confidence interval of values.
+func summarizeTrials(values []float64) TrialStatistics {
+	mean := stat.Mean(values, nil)
+	summary := TrialStatistics{
+		Values:             values,
+		Mean:               mean,
+		ConfidenceInterval: [2]float64{mean, mean},
+	}
+	if len(values) < 2 {
+		return summary
+	}
+
+	summary.StdDev = stat.StdDev(values, nil)
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(len(values) - 1)}.Quantile(1 - (1-MultiTrialConfidenceLevel)/2)
+	margin := t * summary.StdDev / math.Sqrt(float64(len(values)))
+	summary.ConfidenceInterval = [2]float64{mean - margin, mean + margin}
+	return summary
+}
+
+// caseStability summarizes one test case's outcomes across trials.
+func caseStability(index int, input string, outcomes []caseOutcome) TestCaseStability {
+	passed := 0
+	metricValues := map[string][]float64{}
+	for _, outcome := range outcomes {
+		if outcome.passed {
+			passed++
+		}
+		for name, value := range outcome.metrics {
+			metricValues[name] = append(metricValues[name], value)
+		}
+	}
+
+	passRate := float64(passed) / float64(len(outcomes))
+	stability := TestCaseStability{
+		Index:        index,
+		Input:        input,
+		PassRate:     passRate,
+		PassVariance: passRate * (1 - passRate),
+		MetricStdDev: make(map[string]float64, len(metricValues)),
+		Unstable:     passed > 0 && passed < len(outcomes),
+	}
+
+	for name, values := range metricValues {
+		if len(values) < 2 {
+			stability.MetricStdDev[name] = 0
+			continue
+		}
+		stability.MetricStdDev[name] = stat.StdDev(values, nil)
+	}
+	return stability
+}
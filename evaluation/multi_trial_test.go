@@ -0,0 +1,131 @@
+package evaluation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// alternatingAgent answers "yes" to "flaky" in even trials and "no" in odd
+// ones, and echoes every other input.
+type alternatingAgent struct {
+	seeds []int64
+}
+
+func (a *alternatingAgent) Name() string           { return "alternating" }
+func (a *alternatingAgent) Capabilities() []string { return nil }
+func (a *alternatingAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+
+func (a *alternatingAgent) Process(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+	if seed, ok := msg.Metadata["seed"].(int64); ok {
+		a.seeds = append(a.seeds, seed)
+	}
+	content := msg.ContentString()
+	if content == "flaky" {
+		content = "yes"
+		if trial, _ := msg.Metadata["trial"].(int); trial%2 == 1 {
+			content = "no"
+		}
+	}
+	return agenkit.NewMessage("agent", content), nil
+}
+
+func multiTrialCases() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"input": "stable", "expected": "stable"},
+		{"input": "flaky", "expected": "yes"},
+	}
+}
+
+func TestEvaluateMultiTrial_AggregatesAcrossTrials(t *testing.T) {
+	evaluator := NewEvaluator(&alternatingAgent{}, []Metric{&lengthMetric{}}, "")
+	result, err := evaluator.EvaluateMultiTrial(multiTrialCases(), 4, 7)
+	if err != nil {
+		t.Fatalf("EvaluateMultiTrial failed: %v", err)
+	}
+	if len(result.Trials) != 4 {
+		t.Fatalf("got %d trials, want 4", len(result.Trials))
+	}
+
+	accuracy := result.Metrics["accuracy"]
+	if accuracy.Mean != 0.75 {
+		t.Errorf("accuracy mean = %v, want 0.75", accuracy.Mean)
+	}
+	if accuracy.StdDev < 0.28 || accuracy.StdDev > 0.29 {
+		t.Errorf("accuracy std = %v, want ~0.289", accuracy.StdDev)
+	}
+	if low, high := accuracy.ConfidenceInterval[0], accuracy.ConfidenceInterval[1]; !(low < 0.75 && high > 0.75) {
+		t.Errorf("confidence interval %v does not bracket the mean", accuracy.ConfidenceInterval)
+	}
+	if _, ok := result.Metrics["length"]; !ok {
+		t.Error("expected statistics for the length metric")
+	}
+
+	unstable := result.UnstableCases()
+	if len(unstable) != 1 || unstable[0].Input != "flaky" {
+		t.Fatalf("unstable cases = %+v, want only flaky", unstable)
+	}
+	if unstable[0].PassRate != 0.5 || unstable[0].PassVariance != 0.25 {
+		t.Errorf("flaky pass rate %v variance %v, want 0.5 and 0.25", unstable[0].PassRate, unstable[0].PassVariance)
+	}
+	if result.TestCases[0].Unstable || result.TestCases[0].PassRate != 1 {
+		t.Errorf("stable case = %+v, want always passing", result.TestCases[0])
+	}
+	if std := result.TestCases[1].MetricStdDev["length"]; std == 0 {
+		t.Error("expected the flaky case's length to vary across trials")
+	}
+
+	dict := result.ToDict()
+	if dict["num_trials"] != 4 || dict["unstable_cases"] != 1 {
+		t.Errorf("ToDict() = %v", dict)
+	}
+}
+
+func TestEvaluateMultiTrial_Reproducible(t *testing.T) {
+	first := &alternatingAgent{}
+	second := &alternatingAgent{}
+	resultA, _ := NewEvaluator(first, nil, "").EvaluateMultiTrial(multiTrialCases(), 3, 42)
+	resultB, _ := NewEvaluator(second, nil, "").EvaluateMultiTrial(multiTrialCases(), 3, 42)
+
+	for i := range resultA.TrialSeeds {
+		if resultA.TrialSeeds[i] != resultB.TrialSeeds[i] {
+			t.Fatalf("trial seeds differ: %v vs %v", resultA.TrialSeeds, resultB.TrialSeeds)
+		}
+	}
+	if len(first.seeds) != 6 {
+		t.Fatalf("agent saw %d seeds, want 6", len(first.seeds))
+	}
+	for i := range first.seeds {
+		if first.seeds[i] != second.seeds[i] {
+			t.Errorf("call %d: seed %d vs %d", i, first.seeds[i], second.seeds[i])
+		}
+	}
+
+	resultC, _ := NewEvaluator(&alternatingAgent{}, nil, "").EvaluateMultiTrial(multiTrialCases(), 3, 43)
+	if resultC.TrialSeeds[0] == resultA.TrialSeeds[0] {
+		t.Error("expected a different seed to derive different trial seeds")
+	}
+}
+
+func TestEvaluateMultiTrial_SingleTrial(t *testing.T) {
+	result, err := NewEvaluator(&alternatingAgent{}, nil, "").EvaluateMultiTrial(multiTrialCases(), 1, 1)
+	if err != nil {
+		t.Fatalf("EvaluateMultiTrial failed: %v", err)
+	}
+	accuracy := result.Metrics["accuracy"]
+	if accuracy.StdDev != 0 || accuracy.ConfidenceInterval != [2]float64{accuracy.Mean, accuracy.Mean} {
+		t.Errorf("single trial statistics = %+v, want zero spread", accuracy)
+	}
+	if len(result.UnstableCases()) != 0 {
+		t.Error("a single trial cannot flag unstable cases")
+	}
+}
+
+func TestEvaluateMultiTrial_InvalidTrials(t *testing.T) {
+	if _, err := NewEvaluator(&alternatingAgent{}, nil, "").EvaluateMultiTrial(multiTrialCases(), 0, 1); err == nil {
+		t.Error("expected error for zero trials")
+	}
+}
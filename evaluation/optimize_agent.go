@@ -0,0 +1,200 @@
+// Package evaluation provides end-to-end agent configuration optimization.
+//
+// Builds agents from suggested configurations, evaluates each on a test
+// suite and returns the best configuration found.
+package evaluation
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// AgentBuilder builds an agent from a configuration suggested by an optimizer.
+type AgentBuilder func(config map[string]interface{}) (agenkit.Agent, error)
+
+// OptimizeAgentConfig configures OptimizeAgent.
+type OptimizeAgentConfig struct {
+	// Optimizer configures the Bayesian optimizer. SearchSpace and Objective
+	// are set by OptimizeAgent and ignored here. Set Maximize to false for
+	// metrics where lower is better, such as latency.
+	Optimizer BayesianOptimizerConfig
+	// NIterations is the number of configurations to evaluate (default: 20)
+	NIterations int
+	// Statistic selects the key of the metric's Aggregate result used as
+	// the score, such as "p95" for LatencyMetric. Empty (the default) uses
+	// the mean of the raw measurements, which works for every metric.
+	Statistic string
+}
+
+// AgentOptimizationResult is the result of OptimizeAgent.
+type AgentOptimizationResult struct {
+	*OptimizationResult
+	// BestEvaluation is the full evaluation of the best configuration
+	BestEvaluation *EvaluationResult
+	// Evaluations holds one evaluation per configuration, in the same order
+	// as History. With Optimizer.NEvalsPerConfig > 1 it is the run whose
+	// score is closest to the configuration's aggregated score.
+	Evaluations []*EvaluationResult
+	// EvaluationRuns holds every run of each configuration, in the same
+	// order as History, NEvalsPerConfig runs each
+	EvaluationRuns [][]*EvaluationResult
+}
+
+// OptimizeAgent tunes an agent's configuration against a test suite.
+//
+// For each configuration suggested by a BayesianOptimizer it builds an
+// agent with buildAgent, evaluates it on testCases with an Evaluator, and
+// uses metric's mean measurement (or config.Statistic) as the objective
+// score. A nil metric scores configurations by test success rate
+// (Evaluator accuracy). The result carries the best configuration along
+// with its full EvaluationResult.
+//
+// An error from buildAgent, or a metric without measurements (for example
+// because every test case failed), aborts the run.
+//
+// Example:
+//
+//	space := evaluation.NewSearchSpace()
+//	space.AddContinuous("temperature", 0.0, 1.0)
+//	space.AddCategorical("model", []string{"small", "large"})
+//
+//	result, err := evaluation.OptimizeAgent(ctx, space,
+//	    func(config map[string]interface{}) (agenkit.Agent, error) {
+//	        return NewSupportAgent(config["model"].(string), config["temperature"].(float64)), nil
+//	    },
+//	    testCases,
+//	    evaluation.NewAccuracyMetric(evaluation.NormalizedMatch(), false),
+//	    evaluation.OptimizeAgentConfig{
+//	        Optimizer:   evaluation.BayesianOptimizerConfig{Maximize: true},
+//	        NIterations: 30,
+//	    })
+//	fmt.Printf("Best config: %v (accuracy %.2f)\n", result.BestConfig, *result.BestEvaluation.Accuracy)
+func OptimizeAgent(
+	ctx context.Context,
+	space *SearchSpace,
+	buildAgent AgentBuilder,
+	testCases []map[string]interface{},
+	metric Metric,
+	config OptimizeAgentConfig,
+) (*AgentOptimizationResult, error) {
+	if buildAgent == nil {
+		return nil, fmt.Errorf("agent builder is required")
+	}
+	if len(testCases) == 0 {
+		return nil, fmt.Errorf("at least one test case is required")
+	}
+
+	nIterations := config.NIterations
+	if nIterations == 0 {
+		nIterations = 20
+	}
+
+	var metrics []Metric
+	if metric != nil {
+		metrics = []Metric{metric}
+	}
+
+	runsPerConfig := config.Optimizer.NEvalsPerConfig
+	if runsPerConfig < 1 {
+		runsPerConfig = 1
+	}
+
+	// The optimizer calls the objective runsPerConfig times in a row for
+	// each configuration it records
+	type run struct {
+		result *EvaluationResult
+		score  float64
+	}
+	runs := make([]run, 0, nIterations*runsPerConfig)
+	objective := func(ctx context.Context, params map[string]interface{}) (float64, error) {
+		agent, err := buildAgent(params)
+		if err != nil {
+			return 0, fmt.Errorf("building agent: %w", err)
+		}
+
+		result, err := NewEvaluator(agent, metrics, "").Evaluate(testCases, "")
+		if err != nil {
+			return 0, err
+		}
+		result.Metadata["config"] = params
+
+		score, err := objectiveScore(result, metric, config.Statistic)
+		if err != nil {
+			return 0, err
+		}
+		runs = append(runs, run{result: result, score: score})
+		return score, nil
+	}
+
+	optimizerConfig := config.Optimizer
+	optimizerConfig.SearchSpace = space
+	optimizerConfig.Objective = objective
+
+	optimizer, err := NewBayesianOptimizer(optimizerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := optimizer.Optimize(ctx, nIterations)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(runs) != len(result.History)*runsPerConfig {
+		return nil, fmt.Errorf("expected %d evaluations for %d configurations, got %d",
+			len(result.History)*runsPerConfig, len(result.History), len(runs))
+	}
+
+	evaluations := make([]*EvaluationResult, len(result.History))
+	evaluationRuns := make([][]*EvaluationResult, len(result.History))
+	var best *EvaluationResult
+	for i, step := range result.History {
+		group := runs[i*runsPerConfig : (i+1)*runsPerConfig]
+		representative := group[0]
+		evaluationRuns[i] = make([]*EvaluationResult, len(group))
+		for j, r := range group {
+			evaluationRuns[i][j] = r.result
+			if math.Abs(r.score-step.Score) < math.Abs(representative.score-step.Score) {
+				representative = r
+			}
+		}
+		evaluations[i] = representative.result
+
+		// The optimizer records only strict improvements, so the best
+		// configuration is the first one reaching the best score
+		if best == nil && step.Score == result.BestScore {
+			best = representative.result
+		}
+	}
+
+	return &AgentOptimizationResult{
+		OptimizationResult: result,
+		BestEvaluation:     best,
+		Evaluations:        evaluations,
+		EvaluationRuns:     evaluationRuns,
+	}, nil
+}
+
+// objectiveScore extracts the optimization score from an evaluation result.
+func objectiveScore(result *EvaluationResult, metric Metric, statistic string) (float64, error) {
+	if metric == nil {
+		return result.SuccessRate(), nil
+	}
+
+	measurements := result.Metrics[metric.Name()]
+	if len(measurements) == 0 {
+		return 0, fmt.Errorf("metric %s produced no measurements", metric.Name())
+	}
+	if statistic == "" {
+		return sum(measurements) / float64(len(measurements)), nil
+	}
+
+	score, ok := result.AggregatedMetrics[metric.Name()][statistic]
+	if !ok {
+		return 0, fmt.Errorf("metric %s has no %q statistic", metric.Name(), statistic)
+	}
+	return score, nil
+}
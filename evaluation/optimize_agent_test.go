@@ -0,0 +1,131 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// answerAgent always replies with the same answer.
+type answerAgent struct {
+	answer string
+}
+
+func (a *answerAgent) Name() string           { return "answer" }
+func (a *answerAgent) Capabilities() []string { return nil }
+func (a *answerAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+func (a *answerAgent) Process(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+	return agenkit.NewMessage("agent", a.answer), nil
+}
+
+func buildAnswerAgent(config map[string]interface{}) (agenkit.Agent, error) {
+	return &answerAgent{answer: config["answer"].(string)}, nil
+}
+
+func TestOptimizeAgent_FindsBestConfig(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddCategorical("answer", []string{"3", "4", "5"})
+	testCases := []map[string]interface{}{
+		{"input": "What is 2+2?", "expected": "4"},
+		{"input": "What is 8/2?", "expected": "4"},
+	}
+
+	result, err := OptimizeAgent(context.Background(), space, buildAnswerAgent, testCases,
+		NewAccuracyMetric(NormalizedMatch(), false),
+		OptimizeAgentConfig{
+			Optimizer:   BayesianOptimizerConfig{Maximize: true, NInitial: 10},
+			NIterations: 10,
+		})
+	if err != nil {
+		t.Fatalf("OptimizeAgent failed: %v", err)
+	}
+
+	if result.BestConfig["answer"] != "4" || result.BestScore != 1.0 {
+		t.Errorf("Expected answer 4 with score 1, got %v (%v)", result.BestConfig, result.BestScore)
+	}
+	if len(result.Evaluations) != 10 || len(result.History) != 10 {
+		t.Errorf("Expected 10 evaluations, got %d", len(result.Evaluations))
+	}
+	best := result.BestEvaluation
+	if best == nil || best.PassedTests != 2 || best.Metadata["config"].(map[string]interface{})["answer"] != "4" {
+		t.Errorf("Expected full evaluation of the best config, got %+v", best)
+	}
+}
+
+func TestOptimizeAgent_SuccessRateWithoutMetric(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddCategorical("answer", []string{"4"})
+	testCases := []map[string]interface{}{{"input": "2+2?", "expected": "4"}}
+
+	result, err := OptimizeAgent(context.Background(), space, buildAnswerAgent, testCases, nil,
+		OptimizeAgentConfig{Optimizer: BayesianOptimizerConfig{Maximize: true}, NIterations: 2})
+	if err != nil {
+		t.Fatalf("OptimizeAgent failed: %v", err)
+	}
+	if result.BestScore != 1.0 {
+		t.Errorf("Expected success rate 1, got %v", result.BestScore)
+	}
+}
+
+func TestOptimizeAgent_GroupsRepeatedEvaluations(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddCategorical("answer", []string{"3", "4", "5"})
+	testCases := []map[string]interface{}{{"input": "What is 2+2?", "expected": "4"}}
+
+	result, err := OptimizeAgent(context.Background(), space, buildAnswerAgent, testCases,
+		NewAccuracyMetric(NormalizedMatch(), false),
+		OptimizeAgentConfig{
+			Optimizer:   BayesianOptimizerConfig{Maximize: true, NInitial: 4, NEvalsPerConfig: 3},
+			NIterations: 4,
+		})
+	if err != nil {
+		t.Fatalf("OptimizeAgent failed: %v", err)
+	}
+
+	if len(result.History) != 4 || len(result.Evaluations) != 4 || len(result.EvaluationRuns) != 4 {
+		t.Fatalf("Expected one entry per configuration, got %d history, %d evaluations, %d run groups",
+			len(result.History), len(result.Evaluations), len(result.EvaluationRuns))
+	}
+	for i, step := range result.History {
+		if len(result.EvaluationRuns[i]) != 3 {
+			t.Errorf("Step %d: expected 3 runs, got %d", i, len(result.EvaluationRuns[i]))
+		}
+		for _, run := range append(result.EvaluationRuns[i], result.Evaluations[i]) {
+			if got := run.Metadata["config"].(map[string]interface{})["answer"]; got != step.Config["answer"] {
+				t.Errorf("Step %d: evaluation of answer %v recorded for config %v", i, got, step.Config["answer"])
+			}
+		}
+	}
+	if result.BestConfig["answer"] == "4" && result.BestEvaluation.Metadata["config"].(map[string]interface{})["answer"] != "4" {
+		t.Errorf("Expected the best evaluation to belong to the best config, got %v", result.BestEvaluation.Metadata["config"])
+	}
+}
+
+func TestOptimizeAgent_Errors(t *testing.T) {
+	space := NewSearchSpace()
+	space.AddCategorical("answer", []string{"4"})
+	testCases := []map[string]interface{}{{"input": "2+2?", "expected": "4"}}
+	ctx := context.Background()
+
+	if _, err := OptimizeAgent(ctx, space, nil, testCases, nil, OptimizeAgentConfig{}); err == nil {
+		t.Error("Expected error for missing builder")
+	}
+	if _, err := OptimizeAgent(ctx, space, buildAnswerAgent, nil, nil, OptimizeAgentConfig{}); err == nil {
+		t.Error("Expected error for missing test cases")
+	}
+
+	buildErr := errors.New("bad config")
+	failing := func(map[string]interface{}) (agenkit.Agent, error) { return nil, buildErr }
+	if _, err := OptimizeAgent(ctx, space, failing, testCases, nil, OptimizeAgentConfig{}); !errors.Is(err, buildErr) {
+		t.Errorf("Expected builder error, got %v", err)
+	}
+
+	metric := NewAccuracyMetric(nil, false)
+	if _, err := OptimizeAgent(ctx, space, buildAnswerAgent, testCases, metric, OptimizeAgentConfig{Statistic: "p99"}); err == nil {
+		t.Error("Expected error for unknown statistic")
+	}
+}
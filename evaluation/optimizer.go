@@ -128,7 +128,10 @@ func (r *RandomSearchOptimizer) Optimize(ctx context.Context, nIterations int) (
 		}
 
 		// Sample random configuration
-		config := r.searchSpace.Sample()
+		config, err := r.searchSpace.TrySample()
+		if err != nil {
+			return nil, err
+		}
 
 		// Evaluate
 		score, err := r.objective(ctx, config)
@@ -140,6 +143,7 @@ func (r *RandomSearchOptimizer) Optimize(ctx context.Context, nIterations int) (
 		r.history = append(r.history, OptimizationStep{
 			Config: copyConfig(config),
 			Score:  score,
+			NEvals: 1,
 		})
 
 		// Update best
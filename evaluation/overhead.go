@@ -0,0 +1,169 @@
+// Package evaluation provides a benchmark harness for pattern overhead.
+//
+// Deep compositions (sequential-of-parallel, nested routers, agents-as-tools)
+// add coordination cost on top of the work their agents do. This module
+// measures that cost in isolation: build the pattern from InstantAgent
+// children, then run it many times with BenchmarkPattern. Because the
+// children return immediately, everything the report measures is framework
+// overhead.
+package evaluation
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// InstantAgent is a no-op agent that echoes its input without doing any work.
+//
+// Use it as the leaf agent when measuring pattern overhead so that agent
+// latency does not pollute the measurement.
+type InstantAgent struct {
+	name string
+}
+
+// NewInstantAgent creates a new no-op agent with the given name.
+func NewInstantAgent(name string) *InstantAgent {
+	if name == "" {
+		name = "instant"
+	}
+	return &InstantAgent{name: name}
+}
+
+// Name returns the agent name.
+func (a *InstantAgent) Name() string {
+	return a.name
+}
+
+// Capabilities returns the agent capabilities.
+func (a *InstantAgent) Capabilities() []string {
+	return []string{"instant"}
+}
+
+// Process echoes the input content back immediately.
+func (a *InstantAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	return &agenkit.Message{Role: "assistant", Content: message.Content}, nil
+}
+
+// Introspect returns introspection data for the agent.
+func (a *InstantAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+
+// PatternBenchmarkReport summarizes the coordination overhead of a pattern.
+type PatternBenchmarkReport struct {
+	// PatternName is the name of the benchmarked pattern
+	PatternName string
+	// Iterations is the number of Process calls made
+	Iterations int
+	// Errors is the number of calls that returned an error
+	Errors int
+	// TotalDuration is the wall-clock time for all iterations
+	TotalDuration time.Duration
+	// MeanLatency is the average per-call latency
+	MeanLatency time.Duration
+	// MinLatency is the fastest observed call
+	MinLatency time.Duration
+	// MaxLatency is the slowest observed call
+	MaxLatency time.Duration
+	// P50 is the median per-call latency
+	P50 time.Duration
+	// P95 is the 95th percentile per-call latency
+	P95 time.Duration
+	// P99 is the 99th percentile per-call latency
+	P99 time.Duration
+	// AllocsPerCall is the average number of heap allocations per call
+	AllocsPerCall float64
+	// BytesPerCall is the average number of heap bytes allocated per call
+	BytesPerCall float64
+}
+
+// ToDict converts the report to a dictionary.
+func (r *PatternBenchmarkReport) ToDict() map[string]interface{} {
+	return map[string]interface{}{
+		"pattern_name":    r.PatternName,
+		"iterations":      r.Iterations,
+		"errors":          r.Errors,
+		"total_ms":        float64(r.TotalDuration) / float64(time.Millisecond),
+		"mean_us":         float64(r.MeanLatency) / float64(time.Microsecond),
+		"min_us":          float64(r.MinLatency) / float64(time.Microsecond),
+		"max_us":          float64(r.MaxLatency) / float64(time.Microsecond),
+		"p50_us":          float64(r.P50) / float64(time.Microsecond),
+		"p95_us":          float64(r.P95) / float64(time.Microsecond),
+		"p99_us":          float64(r.P99) / float64(time.Microsecond),
+		"allocs_per_call": r.AllocsPerCall,
+		"bytes_per_call":  r.BytesPerCall,
+	}
+}
+
+// BenchmarkPattern runs pattern against input the given number of times and
+// reports per-call latency and allocation statistics.
+//
+// Build the pattern from InstantAgent children so that the report reflects
+// framework coordination overhead only. Errors returned by the pattern are
+// counted but do not stop the run. Iterations below 1 are treated as 1.
+func BenchmarkPattern(pattern agenkit.Agent, input *agenkit.Message, iterations int) *PatternBenchmarkReport {
+	if iterations < 1 {
+		iterations = 1
+	}
+
+	ctx := context.Background()
+	latencies := make([]time.Duration, iterations)
+	errors := 0
+
+	// Warm up once so lazy initialization does not skew the first sample.
+	_, _ = pattern.Process(ctx, input)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	for i := 0; i < iterations; i++ {
+		callStart := time.Now()
+		if _, err := pattern.Process(ctx, input); err != nil {
+			errors++
+		}
+		latencies[i] = time.Since(callStart)
+	}
+	total := time.Since(start)
+
+	runtime.ReadMemStats(&after)
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &PatternBenchmarkReport{
+		PatternName:   pattern.Name(),
+		Iterations:    iterations,
+		Errors:        errors,
+		TotalDuration: total,
+		MeanLatency:   total / time.Duration(iterations),
+		MinLatency:    sorted[0],
+		MaxLatency:    sorted[len(sorted)-1],
+		P50:           durationPercentile(sorted, 50),
+		P95:           durationPercentile(sorted, 95),
+		P99:           durationPercentile(sorted, 99),
+		AllocsPerCall: float64(after.Mallocs-before.Mallocs) / float64(iterations),
+		BytesPerCall:  float64(after.TotalAlloc-before.TotalAlloc) / float64(iterations),
+	}
+}
+
+// durationPercentile returns the p-th percentile of sorted using nearest-rank.
+func durationPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
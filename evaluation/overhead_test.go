@@ -0,0 +1,106 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// nestedInstantAgent forwards to an inner agent, simulating a composition layer.
+type nestedInstantAgent struct {
+	inner agenkit.Agent
+	fail  bool
+}
+
+func (a *nestedInstantAgent) Name() string           { return "nested" }
+func (a *nestedInstantAgent) Capabilities() []string { return []string{} }
+func (a *nestedInstantAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+func (a *nestedInstantAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	if a.fail {
+		return nil, errors.New("boom")
+	}
+	return a.inner.Process(ctx, message)
+}
+
+func TestInstantAgent_Echoes(t *testing.T) {
+	agent := NewInstantAgent("")
+	if agent.Name() != "instant" {
+		t.Errorf("Expected default name 'instant', got %q", agent.Name())
+	}
+
+	response, err := agent.Process(context.Background(), agenkit.NewMessage("user", "ping"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.ContentString() != "ping" {
+		t.Errorf("Expected echo 'ping', got %q", response.ContentString())
+	}
+}
+
+func TestBenchmarkPattern_Report(t *testing.T) {
+	pattern := &nestedInstantAgent{inner: NewInstantAgent("leaf")}
+
+	report := BenchmarkPattern(pattern, agenkit.NewMessage("user", "ping"), 200)
+
+	if report.PatternName != "nested" {
+		t.Errorf("Expected pattern name 'nested', got %q", report.PatternName)
+	}
+	if report.Iterations != 200 {
+		t.Errorf("Expected 200 iterations, got %d", report.Iterations)
+	}
+	if report.Errors != 0 {
+		t.Errorf("Expected 0 errors, got %d", report.Errors)
+	}
+	if report.MinLatency > report.P50 || report.P50 > report.P95 || report.P95 > report.P99 || report.P99 > report.MaxLatency {
+		t.Errorf("Expected ordered percentiles, got min=%v p50=%v p95=%v p99=%v max=%v",
+			report.MinLatency, report.P50, report.P95, report.P99, report.MaxLatency)
+	}
+	if report.AllocsPerCall <= 0 {
+		t.Errorf("Expected positive allocations per call, got %.2f", report.AllocsPerCall)
+	}
+
+	dict := report.ToDict()
+	if dict["iterations"] != 200 {
+		t.Errorf("Expected iterations in dict, got %v", dict["iterations"])
+	}
+}
+
+func TestBenchmarkPattern_CountsErrors(t *testing.T) {
+	pattern := &nestedInstantAgent{fail: true}
+
+	report := BenchmarkPattern(pattern, agenkit.NewMessage("user", "ping"), 10)
+
+	if report.Errors != 10 {
+		t.Errorf("Expected 10 errors, got %d", report.Errors)
+	}
+}
+
+func TestBenchmarkPattern_MinimumIterations(t *testing.T) {
+	report := BenchmarkPattern(NewInstantAgent("leaf"), agenkit.NewMessage("user", "ping"), 0)
+
+	if report.Iterations != 1 {
+		t.Errorf("Expected iterations to be clamped to 1, got %d", report.Iterations)
+	}
+}
+
+func TestDurationPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	if got := durationPercentile(sorted, 50); got != 50*time.Millisecond {
+		t.Errorf("Expected p50 = 50ms, got %v", got)
+	}
+	if got := durationPercentile(sorted, 99); got != 99*time.Millisecond {
+		t.Errorf("Expected p99 = 99ms, got %v", got)
+	}
+	if got := durationPercentile(nil, 99); got != 0 {
+		t.Errorf("Expected 0 for empty input, got %v", got)
+	}
+}
@@ -128,6 +128,70 @@ func (m *AccuracyMetric) Aggregate(measurements []float64) map[string]float64 {
 	}
 }
 
+// QualityWeights sets how much each quality dimension counts toward the
+// blended score. NewWeightedQualityMetrics treats them as relative,
+// normalizing by their sum, so {Accuracy: 3, Coherence: 1} makes accuracy
+// count three times as much as coherence and ignores the other dimensions.
+type QualityWeights struct {
+	Relevance    float64
+	Completeness float64
+	Coherence    float64
+	Accuracy     float64
+}
+
+// DefaultQualityWeights returns the default weights: 0.3 for relevance
+// and completeness, 0.2 for coherence and accuracy.
+func DefaultQualityWeights() QualityWeights {
+	return QualityWeights{
+		Relevance:    0.3,
+		Completeness: 0.3,
+		Coherence:    0.2,
+		Accuracy:     0.2,
+	}
+}
+
+// normalized returns the weights scaled to sum to 1. Negative weights count
+// as zero, and all-zero weights fall back to the defaults.
+func (w QualityWeights) normalized() QualityWeights {
+	clamped := QualityWeights{
+		Relevance:    math.Max(w.Relevance, 0),
+		Completeness: math.Max(w.Completeness, 0),
+		Coherence:    math.Max(w.Coherence, 0),
+		Accuracy:     math.Max(w.Accuracy, 0),
+	}
+	total := clamped.Relevance + clamped.Completeness + clamped.Coherence + clamped.Accuracy
+	if total == 0 {
+		return DefaultQualityWeights()
+	}
+	return QualityWeights{
+		Relevance:    clamped.Relevance / total,
+		Completeness: clamped.Completeness / total,
+		Coherence:    clamped.Coherence / total,
+		Accuracy:     clamped.Accuracy / total,
+	}
+}
+
+// qualityWeightsFromMap reads weights keyed by dimension name. Missing
+// dimensions get zero weight.
+func qualityWeightsFromMap(weights map[string]float64) QualityWeights {
+	return QualityWeights{
+		Relevance:    weights["relevance"],
+		Completeness: weights["completeness"],
+		Coherence:    weights["coherence"],
+		Accuracy:     weights["accuracy"],
+	}
+}
+
+// toMap returns the weights keyed by dimension name.
+func (w QualityWeights) toMap() map[string]float64 {
+	return map[string]float64{
+		"relevance":    w.Relevance,
+		"completeness": w.Completeness,
+		"coherence":    w.Coherence,
+		"accuracy":     w.Accuracy,
+	}
+}
+
 // QualityMetrics provides comprehensive quality scoring.
 //
 // Evaluates multiple quality dimensions:
@@ -136,17 +200,22 @@ func (m *AccuracyMetric) Aggregate(measurements []float64) map[string]float64 {
 //   - Coherence: Is response logically structured?
 //   - Accuracy: Is information factually correct?
 //
-// Uses rule-based scoring.
+// Uses rule-based scoring. The dimensions are blended with configurable
+// weights; MeasureDimensions and MeasureDetailed also report each
+// dimension's score, and the Evaluator records them as
+// "quality.relevance" and so on.
 //
 // Example:
 //
-//	metric := NewQualityMetrics(false, "", nil)
+//	metric := NewWeightedQualityMetrics(false, "", QualityWeights{
+//	    Relevance: 1, Completeness: 1, Coherence: 1, Accuracy: 3,
+//	})
 //	score, _ := metric.Measure(agent, inputMsg, outputMsg, nil)
 //	fmt.Printf("Quality: %.2f\n", score)  // 0.0 to 1.0
 type QualityMetrics struct {
 	useLLMJudge bool
 	judgeModel  string
-	weights     map[string]float64
+	weights     QualityWeights
 }
 
 // NewQualityMetrics creates a new quality metrics instance.
@@ -161,19 +230,31 @@ type QualityMetrics struct {
 //
 //	metric := NewQualityMetrics(false, "", nil)
 func NewQualityMetrics(useLLMJudge bool, judgeModel string, weights map[string]float64) *QualityMetrics {
-	if weights == nil {
-		weights = map[string]float64{
-			"relevance":    0.3,
-			"completeness": 0.3,
-			"coherence":    0.2,
-			"accuracy":     0.2,
-		}
+	resolved := DefaultQualityWeights()
+	if weights != nil {
+		resolved = qualityWeightsFromMap(weights)
 	}
 
 	return &QualityMetrics{
 		useLLMJudge: useLLMJudge,
 		judgeModel:  judgeModel,
-		weights:     weights,
+		weights:     resolved,
+	}
+}
+
+// NewWeightedQualityMetrics creates a quality metrics instance with
+// relative dimension weights, normalized to sum to 1. Negative weights
+// count as zero, and all-zero weights fall back to DefaultQualityWeights.
+//
+// Example:
+//
+//	// Accuracy matters 3x more than coherence; ignore the rest
+//	metric := NewWeightedQualityMetrics(false, "", QualityWeights{Accuracy: 3, Coherence: 1})
+func NewWeightedQualityMetrics(useLLMJudge bool, judgeModel string, weights QualityWeights) *QualityMetrics {
+	return &QualityMetrics{
+		useLLMJudge: useLLMJudge,
+		judgeModel:  judgeModel,
+		weights:     weights.normalized(),
 	}
 }
 
@@ -182,6 +263,11 @@ func (m *QualityMetrics) Name() string {
 	return "quality"
 }
 
+// Weights returns the dimension weights in use.
+func (m *QualityMetrics) Weights() QualityWeights {
+	return m.weights
+}
+
 // Measure measures response quality.
 //
 // Args:
@@ -195,9 +281,46 @@ func (m *QualityMetrics) Name() string {
 //
 //	Quality score (0.0 to 1.0)
 func (m *QualityMetrics) Measure(agent agenkit.Agent, inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) (float64, error) {
+	return m.blend(m.ruleBasedQuality(inputMessage, outputMessage, ctx)), nil
+}
+
+// MeasureDimensions measures each quality dimension (relevance,
+// completeness, coherence, accuracy) on its own, from 0.0 to 1.0.
+func (m *QualityMetrics) MeasureDimensions(agent agenkit.Agent, inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) (map[string]float64, error) {
 	return m.ruleBasedQuality(inputMessage, outputMessage, ctx), nil
 }
 
+// MeasureDetailed measures response quality like Measure, and records
+// each dimension's score (relevance, completeness, coherence, accuracy)
+// and the weights used in the measurement metadata, to show which
+// dimension is lowering the blended score.
+//
+// Example:
+//
+//	measurement, _ := metric.MeasureDetailed(agent, inputMsg, outputMsg, nil)
+//	fmt.Printf("quality %.2f, relevance %.2f\n",
+//	    measurement.Value, measurement.Metadata["relevance"])
+func (m *QualityMetrics) MeasureDetailed(agent agenkit.Agent, inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) (*MetricMeasurement, error) {
+	scores := m.ruleBasedQuality(inputMessage, outputMessage, ctx)
+
+	measurement := NewMetricMeasurement(m.Name(), m.blend(scores), MetricTypeQualityScore)
+	for dim, score := range scores {
+		measurement.Metadata[dim] = score
+	}
+	measurement.Metadata["weights"] = m.weights.toMap()
+	return measurement, nil
+}
+
+// blend combines dimension scores into the weighted quality score.
+func (m *QualityMetrics) blend(scores map[string]float64) float64 {
+	weights := m.weights.toMap()
+	totalScore := 0.0
+	for dim, score := range scores {
+		totalScore += score * weights[dim]
+	}
+	return totalScore
+}
+
 // ruleBasedQuality performs rule-based quality scoring.
 //
 // Uses heuristics to evaluate quality:
@@ -208,8 +331,8 @@ func (m *QualityMetrics) Measure(agent agenkit.Agent, inputMessage, outputMessag
 //
 // Returns:
 //
-//	Quality score (0.0 to 1.0)
-func (m *QualityMetrics) ruleBasedQuality(inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) float64 {
+//	Score per dimension (each 0.0 to 1.0)
+func (m *QualityMetrics) ruleBasedQuality(inputMessage, outputMessage *agenkit.Message, ctx map[string]interface{}) map[string]float64 {
 	inputText := strings.ToLower(inputMessage.ContentString())
 	outputText := strings.ToLower(outputMessage.ContentString())
 
@@ -277,13 +400,7 @@ func (m *QualityMetrics) ruleBasedQuality(inputMessage, outputMessage *agenkit.M
 	}
 	scores["accuracy"] = accuracy
 
-	// Weighted average
-	totalScore := 0.0
-	for dim, score := range scores {
-		totalScore += score * m.weights[dim]
-	}
-
-	return totalScore
+	return scores
 }
 
 // hasRepetition checks for excessive repetition in text.
@@ -316,30 +433,7 @@ func (m *QualityMetrics) hasRepetition(text string) bool {
 //
 //	Statistics: mean, min, max, std
 func (m *QualityMetrics) Aggregate(measurements []float64) map[string]float64 {
-	if len(measurements) == 0 {
-		return map[string]float64{
-			"mean": 0.0,
-			"min":  0.0,
-			"max":  0.0,
-			"std":  0.0,
-		}
-	}
-
-	mean := sum(measurements) / float64(len(measurements))
-
-	variance := 0.0
-	for _, x := range measurements {
-		variance += (x - mean) * (x - mean)
-	}
-	variance /= float64(len(measurements))
-	std := math.Sqrt(variance)
-
-	return map[string]float64{
-		"mean": mean,
-		"min":  minFloat64(measurements),
-		"max":  maxFloat64(measurements),
-		"std":  std,
-	}
+	return DefaultAggregate(measurements)
 }
 
 // PrecisionRecallStats contains precision and recall statistics.
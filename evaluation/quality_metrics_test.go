@@ -213,6 +213,92 @@ func TestQualityMetricsLowQuality(t *testing.T) {
 	}
 }
 
+// TestQualityMetricsWeights tests weight normalization and defaults
+func TestQualityMetricsWeights(t *testing.T) {
+	if weights := NewQualityMetrics(false, "", nil).Weights(); weights != DefaultQualityWeights() {
+		t.Errorf("Expected default weights, got %+v", weights)
+	}
+
+	weights := NewWeightedQualityMetrics(false, "", QualityWeights{Coherence: 1, Accuracy: 3, Relevance: -1}).Weights()
+	if weights.Accuracy != 0.75 || weights.Coherence != 0.25 || weights.Relevance != 0 || weights.Completeness != 0 {
+		t.Errorf("Expected weights normalized to 0.75/0.25, got %+v", weights)
+	}
+
+	if weights := NewWeightedQualityMetrics(false, "", QualityWeights{}).Weights(); weights != DefaultQualityWeights() {
+		t.Errorf("Expected all-zero weights to fall back to defaults, got %+v", weights)
+	}
+}
+
+// TestQualityMetricsMeasureDetailed tests per-dimension scores and weighting
+func TestQualityMetricsMeasureDetailed(t *testing.T) {
+	agent := &MockAgent{name: "test-agent"}
+	input := &agenkit.Message{Role: "user", Content: "What is the capital?"}
+	output := &agenkit.Message{Role: "agent", Content: "London."}
+	ctx := map[string]interface{}{"expected": "Paris"}
+
+	metric := NewQualityMetrics(false, "", nil)
+	measurement, err := metric.MeasureDetailed(agent, input, output, ctx)
+	if err != nil {
+		t.Fatalf("MeasureDetailed failed: %v", err)
+	}
+
+	score, _ := metric.Measure(agent, input, output, ctx)
+	if measurement.Value != score {
+		t.Errorf("Expected detailed value %.3f to match Measure %.3f", measurement.Value, score)
+	}
+	if measurement.Type != MetricTypeQualityScore {
+		t.Errorf("Expected quality score type, got %s", measurement.Type)
+	}
+	for _, dim := range []string{"relevance", "completeness", "coherence", "accuracy"} {
+		if _, ok := measurement.Metadata[dim].(float64); !ok {
+			t.Errorf("Expected %s sub-score in metadata, got %v", dim, measurement.Metadata)
+		}
+	}
+	if measurement.Metadata["accuracy"] != 0.0 {
+		t.Errorf("Expected accuracy 0 for wrong answer, got %v", measurement.Metadata["accuracy"])
+	}
+
+	// Weighting only accuracy makes the wrong answer score zero
+	accuracyOnly := NewWeightedQualityMetrics(false, "", QualityWeights{Accuracy: 1})
+	if score, _ := accuracyOnly.Measure(agent, input, output, ctx); score != 0.0 {
+		t.Errorf("Expected 0 with accuracy-only weights, got %.3f", score)
+	}
+}
+
+// TestQualityMetricsDimensionsThroughEvaluator tests that the Evaluator
+// records each quality dimension next to the blended score
+func TestQualityMetricsDimensionsThroughEvaluator(t *testing.T) {
+	evaluator := NewEvaluator(&MockAgent{name: "test-agent"}, []Metric{NewQualityMetrics(false, "", nil)}, "")
+	result, err := evaluator.Evaluate([]map[string]interface{}{
+		{"input": "Give a test response", "expected": "Paris"},
+		{"input": "Give a test response", "expected": "Paris"},
+	}, "")
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	if len(result.Metrics["quality"]) != 2 {
+		t.Fatalf("Expected 2 blended quality scores, got %v", result.Metrics["quality"])
+	}
+	for _, dim := range []string{"relevance", "completeness", "coherence", "accuracy"} {
+		name := DimensionName("quality", dim)
+		if len(result.Metrics[name]) != 2 {
+			t.Errorf("Expected 2 %s measurements, got %v", name, result.Metrics[name])
+		}
+		if _, ok := result.AggregatedMetrics[name]["mean"]; !ok {
+			t.Errorf("Expected aggregated %s, got %v", name, result.AggregatedMetrics[name])
+		}
+	}
+
+	// The wrong answer shows up as the dimension dragging the score down
+	if accuracy := result.AggregatedMetrics["quality.accuracy"]["mean"]; accuracy != 0 {
+		t.Errorf("Expected accuracy dimension 0 for a wrong answer, got %v", accuracy)
+	}
+	if relevance := result.AggregatedMetrics["quality.relevance"]["mean"]; relevance == 0 {
+		t.Errorf("Expected a non-zero relevance dimension, got %v", relevance)
+	}
+}
+
 // TestQualityMetricsAggregate tests aggregation
 func TestQualityMetricsAggregate(t *testing.T) {
 	metric := NewQualityMetrics(false, "", nil)
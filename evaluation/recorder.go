@@ -2,15 +2,18 @@ package evaluation
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/scttfrdmn/agenkit-go/agenkit"
+	"github.com/scttfrdmn/agenkit-go/codec"
 )
 
 // InteractionRecord represents a record of single agent interaction.
@@ -180,11 +183,39 @@ type RecordingStorage interface {
 	DeleteRecording(sessionID string) error
 }
 
+// EncodeRecording encodes recording with c (nil uses JSON).
+func EncodeRecording(c codec.Codec, recording *SessionRecording) ([]byte, error) {
+	if c == nil {
+		c = codec.JSON
+	}
+	return c.Marshal(recording.ToDict())
+}
+
+// DecodeRecording decodes a recording encoded by EncodeRecording with the
+// same codec (nil uses JSON).
+func DecodeRecording(c codec.Codec, data []byte) (*SessionRecording, error) {
+	if c == nil {
+		c = codec.JSON
+	}
+	value, err := c.Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s recording: %w", c.Name(), err)
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("recording must be a map, got %T", value)
+	}
+	return SessionRecordingFromDict(fields)
+}
+
 // LocalRecordingStorage provides file-based recording storage.
 //
-// Stores recordings as JSON files on disk.
+// Stores recordings as files on disk, one per session, encoded with the
+// storage's codec: indented JSON by default, or a compact binary format
+// such as codec/msgpack for high-volume recording.
 type LocalRecordingStorage struct {
 	recordingsDir string
+	codec         codec.Codec
 }
 
 // NewLocalRecordingStorage creates a new file storage.
@@ -206,52 +237,58 @@ func NewLocalRecordingStorage(recordingsDir string) *LocalRecordingStorage {
 
 	return &LocalRecordingStorage{
 		recordingsDir: recordingsDir,
+		codec:         codec.JSON,
+	}
+}
+
+// SetCodec sets the format recordings are saved and loaded in (nil
+// restores JSON). Files are named with the codec's extension, so storage
+// only sees recordings in its current format; to convert, load recordings
+// with one codec and save them with another.
+//
+// Example:
+//
+//	storage.SetCodec(msgpack.Codec)
+func (s *LocalRecordingStorage) SetCodec(c codec.Codec) {
+	if c == nil {
+		c = codec.JSON
 	}
+	s.codec = c
+}
+
+// path returns the file path for a session.
+func (s *LocalRecordingStorage) path(sessionID string) string {
+	return filepath.Join(s.recordingsDir, sessionID+s.codec.Extension())
 }
 
 // SaveRecording saves recording to file.
 func (s *LocalRecordingStorage) SaveRecording(recording *SessionRecording) error {
-	filePath := filepath.Join(s.recordingsDir, fmt.Sprintf("%s.json", recording.SessionID))
-
-	file, err := os.Create(filePath)
+	data, err := EncodeRecording(s.codec, recording)
 	if err != nil {
 		return err
 	}
-	defer func() { _ = file.Close() }()
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(recording.ToDict())
+	return os.WriteFile(s.path(recording.SessionID), data, 0644)
 }
 
 // LoadRecording loads recording from file.
 func (s *LocalRecordingStorage) LoadRecording(sessionID string) (*SessionRecording, error) {
-	filePath := filepath.Join(s.recordingsDir, fmt.Sprintf("%s.json", sessionID))
-
-	file, err := os.Open(filePath)
+	data, err := os.ReadFile(s.path(sessionID))
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	defer func() { _ = file.Close() }()
-
-	var data map[string]interface{}
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&data); err != nil {
-		return nil, err
-	}
 
-	return SessionRecordingFromDict(data)
+	return DecodeRecording(s.codec, data)
 }
 
 // ListRecordings lists all recordings.
 func (s *LocalRecordingStorage) ListRecordings(limit, offset int) ([]*SessionRecording, error) {
 	recordings := make([]*SessionRecording, 0)
 
-	// Find all JSON files
-	files, err := filepath.Glob(filepath.Join(s.recordingsDir, "*.json"))
+	// Find all files in the storage's format
+	files, err := filepath.Glob(filepath.Join(s.recordingsDir, "*"+s.codec.Extension()))
 	if err != nil {
 		return nil, err
 	}
@@ -290,20 +327,12 @@ func (s *LocalRecordingStorage) ListRecordings(limit, offset int) ([]*SessionRec
 
 	// Load recordings
 	for _, fi := range fileInfos[start:end] {
-		file, err := os.Open(fi.path)
+		data, err := os.ReadFile(fi.path)
 		if err != nil {
 			continue
 		}
 
-		var data map[string]interface{}
-		decoder := json.NewDecoder(file)
-		if err := decoder.Decode(&data); err != nil {
-			_ = file.Close()
-			continue
-		}
-		_ = file.Close()
-
-		recording, err := SessionRecordingFromDict(data)
+		recording, err := DecodeRecording(s.codec, data)
 		if err != nil {
 			continue
 		}
@@ -316,7 +345,7 @@ func (s *LocalRecordingStorage) ListRecordings(limit, offset int) ([]*SessionRec
 
 // DeleteRecording deletes recording file.
 func (s *LocalRecordingStorage) DeleteRecording(sessionID string) error {
-	filePath := filepath.Join(s.recordingsDir, fmt.Sprintf("%s.json", sessionID))
+	filePath := s.path(sessionID)
 
 	if _, err := os.Stat(filePath); err != nil {
 		if os.IsNotExist(err) {
@@ -403,9 +432,39 @@ func (s *MemoryRecordingStorage) DeleteRecording(sessionID string) error {
 //
 //	// Save recording
 //	recorder.FinalizeSession("test-123")
+//
+// Use SetMaxContentBytes to cap the size of recorded message content so a
+// runaway agent cannot bloat storage. Truncated messages carry a
+// "truncated": true marker plus the SHA-256 and byte length of the full
+// content, which replay uses for comparison.
+//
+// Use SetSampler to record only a sample of interactions at high request
+// rates (see RecordingSampler).
+//
+// Retries: a recorder wrapped outside a retry layer (a patterns.Task with
+// Retries, or middleware.RetryDecorator) sees one call and records only the
+// final outcome. Either wrap the agent inside the retry layer, so each
+// attempt is a separate call:
+//
+//	task := patterns.NewTask(recorder.Wrap(agent), &patterns.TaskConfig{Retries: 2})
+//
+// or keep the recorder outside and call SetRecordAttempts(true), so the
+// failed attempts the retry layer reports are recorded too. Either way,
+// records carry an "attempt" index (1-based) in their metadata, and
+// failures an "error".
+//
+// Use SetRecordingKey to store recordings under a content-derived ID
+// (see ContentRecordingKey) instead of the session ID, and
+// SetSkipDuplicates to avoid storing an identical session twice.
 type SessionRecorder struct {
-	storage        RecordingStorage
-	activeSessions map[string]*SessionRecording
+	storage         RecordingStorage
+	activeSessions  map[string]*SessionRecording
+	maxContentBytes int
+	sampler         *RecordingSampler
+	droppedCounts   map[string]int
+	recordAttempts  bool
+	recordingKey    RecordingKeyFunc
+	skipDuplicates  bool
 }
 
 // NewSessionRecorder creates a new session recorder.
@@ -425,7 +484,63 @@ func NewSessionRecorder(storage RecordingStorage) *SessionRecorder {
 	return &SessionRecorder{
 		storage:        storage,
 		activeSessions: make(map[string]*SessionRecording),
+		droppedCounts:  make(map[string]int),
+	}
+}
+
+// SetMaxContentBytes limits recorded message content to maxBytes bytes.
+//
+// Content beyond the limit is dropped (at a UTF-8 boundary) and the record
+// is marked truncated with a hash of the full content. Zero or negative
+// disables truncation (the default).
+//
+// Example:
+//
+//	recorder := NewSessionRecorder(storage)
+//	recorder.SetMaxContentBytes(64 * 1024)
+func (r *SessionRecorder) SetMaxContentBytes(maxBytes int) {
+	r.maxContentBytes = maxBytes
+}
+
+// MaxContentBytes returns the content size limit (0 = unlimited).
+func (r *SessionRecorder) MaxContentBytes() int {
+	if r.maxContentBytes < 0 {
+		return 0
 	}
+	return r.maxContentBytes
+}
+
+// SetSampler makes the recorder keep only the interactions sampler selects.
+// nil (the default) records every interaction.
+//
+// Saved recordings of a sampled recorder carry "sampling_rate" and
+// "sampling_dropped" (interactions skipped in that session) metadata, so
+// analyses can reweight them. A session whose interactions were all dropped
+// is not saved; FinalizeSession returns ErrSessionNotSampled for it.
+func (r *SessionRecorder) SetSampler(sampler *RecordingSampler) {
+	r.sampler = sampler
+}
+
+// Sampler returns the recorder's sampler (nil if every interaction is recorded).
+func (r *SessionRecorder) Sampler() *RecordingSampler {
+	return r.sampler
+}
+
+// SetRecordAttempts sets whether wrapped agents also record each failed
+// attempt that a retry layer beneath them retries (default false).
+//
+// Each such attempt becomes a separate interaction with no output and
+// metadata "attempt", "error" and "retried": true; the final interaction's
+// "attempt" is the number of the attempt that produced it. Retry layers
+// report attempts through agenkit.NotifyRetry; patterns.Task and
+// middleware.RetryDecorator do so.
+func (r *SessionRecorder) SetRecordAttempts(enabled bool) {
+	r.recordAttempts = enabled
+}
+
+// RecordAttempts reports whether failed retried attempts are recorded.
+func (r *SessionRecorder) RecordAttempts() bool {
+	return r.recordAttempts
 }
 
 // Wrap wraps agent to record interactions.
@@ -480,13 +595,41 @@ func (w *recordingWrapper) Process(ctx context.Context, message *agenkit.Message
 		w.recorder.StartSession(sessionID, w.agent.Name(), nil)
 	}
 
+	// Record attempts that a retry layer below us retries
+	processCtx := ctx
+	retried := 0
+	if w.recorder.recordAttempts {
+		processCtx = agenkit.WithRetryObserver(ctx, func(attempt int, err error, latency time.Duration) {
+			retried++
+			w.recorder.RecordInteraction(sessionID, message, nil, float64(latency.Milliseconds()), map[string]interface{}{
+				"attempt": attempt,
+				"error":   err.Error(),
+				"retried": true,
+			})
+		})
+	}
+
 	// Process with timing
 	start := time.Now()
-	output, err := w.agent.Process(ctx, message)
+	output, err := w.agent.Process(processCtx, message)
 	latency := time.Since(start).Milliseconds()
 
 	// Record interaction (even if error)
-	w.recorder.RecordInteraction(sessionID, message, output, float64(latency), nil)
+	var metadata map[string]interface{}
+	if err != nil {
+		metadata = map[string]interface{}{"error": err.Error()}
+	}
+	attempt, ok := agenkit.AttemptFromContext(ctx)
+	if !ok && retried > 0 {
+		attempt, ok = retried+1, true
+	}
+	if ok {
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+		}
+		metadata["attempt"] = attempt
+	}
+	w.recorder.RecordInteraction(sessionID, message, output, float64(latency), metadata)
 
 	return output, err
 }
@@ -521,7 +664,16 @@ func (r *SessionRecorder) StartSession(sessionID, agentName string, metadata map
 //	outputMessage: Agent response
 //	latencyMs: Processing time in milliseconds
 //	metadata: Optional interaction metadata
+//
+// If a sampler is set, the interaction may be skipped. It counts as failed
+// (see RecordingSamplerConfig.AlwaysRecordErrors) if outputMessage is nil
+// or metadata has an "error" entry.
 func (r *SessionRecorder) RecordInteraction(sessionID string, inputMessage, outputMessage *agenkit.Message, latencyMs float64, metadata map[string]interface{}) {
+	if r.sampler != nil && !r.sampler.ShouldRecord(sessionID, interactionFailed(outputMessage, metadata)) {
+		r.droppedCounts[sessionID]++
+		return
+	}
+
 	// Get or create session
 	session, ok := r.activeSessions[sessionID]
 	if !ok {
@@ -537,8 +689,8 @@ func (r *SessionRecorder) RecordInteraction(sessionID string, inputMessage, outp
 	record := &InteractionRecord{
 		InteractionID: uuid.New().String(),
 		SessionID:     sessionID,
-		InputMessage:  messageToDict(inputMessage),
-		OutputMessage: messageToDict(outputMessage),
+		InputMessage:  truncateMessageDict(messageToDict(inputMessage), r.maxContentBytes),
+		OutputMessage: truncateMessageDict(messageToDict(outputMessage), r.maxContentBytes),
 		Timestamp:     time.Now().UTC(),
 		LatencyMs:     latencyMs,
 		Metadata:      metadata,
@@ -555,10 +707,16 @@ func (r *SessionRecorder) RecordInteraction(sessionID string, inputMessage, outp
 //
 // Returns:
 //
-//	Session recording
+//	Session recording, stored under its derived ID if SetRecordingKey is
+//	set. With SetSkipDuplicates, an identical stored recording is returned
+//	with an error wrapping ErrDuplicateRecording.
 func (r *SessionRecorder) FinalizeSession(sessionID string) (*SessionRecording, error) {
 	session, ok := r.activeSessions[sessionID]
 	if !ok {
+		if r.droppedCounts[sessionID] > 0 {
+			delete(r.droppedCounts, sessionID)
+			return nil, fmt.Errorf("%w: %s", ErrSessionNotSampled, sessionID)
+		}
 		return nil, fmt.Errorf("no active session: %s", sessionID)
 	}
 
@@ -566,6 +724,22 @@ func (r *SessionRecorder) FinalizeSession(sessionID string) (*SessionRecording,
 	endTime := time.Now().UTC()
 	session.EndTime = &endTime
 
+	if r.sampler != nil {
+		dropped := r.droppedCounts[sessionID]
+		delete(r.droppedCounts, sessionID)
+		if len(session.Interactions) == 0 && dropped > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrSessionNotSampled, sessionID)
+		}
+		session.Metadata["sampling_rate"] = r.sampler.Rate()
+		session.Metadata["sampling_dropped"] = dropped
+	}
+
+	if r.recordingKey != nil {
+		if existing, err := r.applyRecordingKey(session); err != nil {
+			return existing, err
+		}
+	}
+
 	// Save to storage
 	if err := r.storage.SaveRecording(session); err != nil {
 		return nil, err
@@ -615,6 +789,9 @@ func (r *SessionRecorder) DeleteRecording(sessionID string) error {
 //
 //	// Compare
 //	comparison := replay.Compare(resultsA, resultsB)
+//
+//	// Or match outputs regardless of order, for agents whose steps vary
+//	alignment := replay.CompareAligned(resultsA, resultsB, nil)
 type SessionReplay struct{}
 
 // NewSessionReplay creates a new session replay.
@@ -633,6 +810,10 @@ func NewSessionReplay() *SessionReplay {
 // Returns:
 //
 //	Replay results with outputs and metrics
+//
+// Each successful interaction includes "matches_original", which compares
+// content hashes when the recorded output was truncated. Truncated inputs
+// are replayed as recorded and flagged with "input_truncated".
 func (r *SessionReplay) Replay(recording *SessionRecording, agent agenkit.Agent, sessionID string) (map[string]interface{}, error) {
 	if sessionID == "" {
 		sessionID = recording.SessionID
@@ -648,11 +829,8 @@ func (r *SessionReplay) Replay(recording *SessionRecording, agent agenkit.Agent,
 
 	for _, interaction := range recording.Interactions {
 		// Reconstruct input message
-		inputMsg := &agenkit.Message{
-			Role:     interaction.InputMessage["role"].(string),
-			Content:  interaction.InputMessage["content"].(string),
-			Metadata: getMapOrEmpty(interaction.InputMessage, "metadata"),
-		}
+		inputMsg := interactionInput(interaction)
+		inputTruncated := isTruncated(interaction.InputMessage)
 
 		// Replay through agent
 		start := time.Now()
@@ -665,14 +843,18 @@ func (r *SessionReplay) Replay(recording *SessionRecording, agent agenkit.Agent,
 				"input":           interaction.InputMessage,
 				"original_output": interaction.OutputMessage,
 				"error":           err.Error(),
+				"input_truncated": inputTruncated,
 			})
 		} else {
+			replayOutput := messageToDict(outputMsg)
 			results["interactions"] = append(results["interactions"].([]map[string]interface{}), map[string]interface{}{
 				"input":               interaction.InputMessage,
 				"original_output":     interaction.OutputMessage,
-				"replay_output":       messageToDict(outputMsg),
+				"replay_output":       replayOutput,
+				"matches_original":    contentEqual(interaction.OutputMessage, replayOutput),
 				"original_latency_ms": interaction.LatencyMs,
 				"replay_latency_ms":   float64(latency),
+				"input_truncated":     inputTruncated,
 			})
 
 			results["total_latency_ms"] = results["total_latency_ms"].(float64) + float64(latency)
@@ -726,10 +908,12 @@ func (r *SessionReplay) Compare(resultsA, resultsB map[string]interface{}) map[s
 			continue
 		}
 
-		outputA := ia["replay_output"].(map[string]interface{})["content"].(string)
-		outputB := ib["replay_output"].(map[string]interface{})["content"].(string)
+		replayA := ia["replay_output"].(map[string]interface{})
+		replayB := ib["replay_output"].(map[string]interface{})
+		outputA := replayA["content"].(string)
+		outputB := replayB["content"].(string)
 
-		if outputA != outputB {
+		if !contentEqual(replayA, replayB) {
 			comparison["output_differences"] = append(comparison["output_differences"].([]map[string]interface{}), map[string]interface{}{
 				"interaction_index": i,
 				"output_a":          outputA,
@@ -757,11 +941,77 @@ func messageToDict(message *agenkit.Message) map[string]interface{} {
 		metadata = make(map[string]interface{})
 	}
 
-	return map[string]interface{}{
+	dict := map[string]interface{}{
 		"role":     message.Role,
 		"content":  message.ContentString(),
 		"metadata": metadata,
 	}
+
+	// Message lineage links related interactions across recordings
+	if message.ID != "" {
+		dict["id"] = message.ID
+	}
+	if message.ParentID != "" {
+		dict["parent_id"] = message.ParentID
+	}
+	return dict
+}
+
+// truncateMessageDict caps the "content" of a message dict at maxBytes,
+// recording the full content's hash and length. It is a no-op when
+// maxBytes <= 0 or the content fits.
+func truncateMessageDict(dict map[string]interface{}, maxBytes int) map[string]interface{} {
+	content, _ := dict["content"].(string)
+	if maxBytes <= 0 || len(content) <= maxBytes {
+		return dict
+	}
+
+	// Back off to a rune boundary so the stored content stays valid UTF-8
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+
+	dict["content"] = content[:cut]
+	dict["truncated"] = true
+	dict["content_sha256"] = contentHash(content)
+	dict["content_bytes"] = len(content)
+	return dict
+}
+
+// isTruncated reports whether a message dict was truncated on record.
+func isTruncated(dict map[string]interface{}) bool {
+	truncated, _ := dict["truncated"].(bool)
+	return truncated
+}
+
+// fullContentHash returns the SHA-256 of a message dict's full content,
+// using the stored hash when the content was truncated.
+func fullContentHash(dict map[string]interface{}) string {
+	if isTruncated(dict) {
+		if hash, ok := dict["content_sha256"].(string); ok {
+			return hash
+		}
+	}
+	content, _ := dict["content"].(string)
+	return contentHash(content)
+}
+
+// contentEqual compares the full content of two message dicts, comparing
+// hashes when either side was truncated.
+func contentEqual(a, b map[string]interface{}) bool {
+	if !isTruncated(a) && !isTruncated(b) {
+		contentA, _ := a["content"].(string)
+		contentB, _ := b["content"].(string)
+		return contentA == contentB
+	}
+	return fullContentHash(a) == fullContentHash(b)
+}
+
+// contentHash returns the hex-encoded SHA-256 of content.
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
 }
 
 func getMapOrEmpty(data map[string]interface{}, key string) map[string]interface{} {
@@ -0,0 +1,153 @@
+package evaluation_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+	"github.com/scttfrdmn/agenkit-go/evaluation"
+	"github.com/scttfrdmn/agenkit-go/middleware"
+	"github.com/scttfrdmn/agenkit-go/patterns"
+)
+
+// flakyAgent fails its first failures calls, then echoes its input.
+type flakyAgent struct {
+	failures int
+	calls    int
+}
+
+func (a *flakyAgent) Name() string           { return "flaky" }
+func (a *flakyAgent) Capabilities() []string { return nil }
+func (a *flakyAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+func (a *flakyAgent) Process(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+	a.calls++
+	if a.calls <= a.failures {
+		return nil, errors.New("upstream unavailable")
+	}
+	return agenkit.NewMessage("agent", msg.ContentString()), nil
+}
+
+// stageAgent is a pipeline stage that applies transform to its input, or
+// echoes it if transform is nil.
+type stageAgent struct {
+	name      string
+	transform func(string) string
+}
+
+func (a *stageAgent) Name() string           { return a.name }
+func (a *stageAgent) Capabilities() []string { return nil }
+func (a *stageAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+func (a *stageAgent) Process(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+	content := msg.ContentString()
+	if a.transform != nil {
+		content = a.transform(content)
+	}
+	return agenkit.NewMessage("agent", content), nil
+}
+
+func TestSessionRecorder_RecordsRetriedAttempts(t *testing.T) {
+	recorder := evaluation.NewSessionRecorder(nil)
+	recorder.SetRecordAttempts(true)
+	agent := recorder.Wrap(middleware.NewRetryDecorator(&flakyAgent{failures: 2}, middleware.RetryConfig{
+		MaxRetries:        3,
+		InitialRetryDelay: time.Millisecond,
+	}))
+
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recording, err := recorder.FinalizeSession("default")
+	if err != nil {
+		t.Fatalf("FinalizeSession failed: %v", err)
+	}
+	if len(recording.Interactions) != 3 {
+		t.Fatalf("expected 3 interactions, got %d", len(recording.Interactions))
+	}
+	for i, interaction := range recording.Interactions[:2] {
+		if interaction.Metadata["attempt"] != i+1 || interaction.Metadata["error"] != "upstream unavailable" {
+			t.Errorf("unexpected failed attempt metadata %v", interaction.Metadata)
+		}
+	}
+	final := recording.Interactions[2]
+	if final.Metadata["attempt"] != 3 {
+		t.Errorf("expected final attempt 3, got %v", final.Metadata["attempt"])
+	}
+	if _, failed := final.Metadata["error"]; failed {
+		t.Error("expected final attempt to succeed")
+	}
+}
+
+func TestSessionRecorder_OnlyFinalOutcomeByDefault(t *testing.T) {
+	recorder := evaluation.NewSessionRecorder(nil)
+	agent := recorder.Wrap(middleware.NewRetryDecorator(&flakyAgent{failures: 1}, middleware.RetryConfig{
+		MaxRetries:        2,
+		InitialRetryDelay: time.Millisecond,
+	}))
+
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recording, _ := recorder.FinalizeSession("default")
+	if len(recording.Interactions) != 1 {
+		t.Fatalf("expected 1 interaction, got %d", len(recording.Interactions))
+	}
+	if _, ok := recording.Interactions[0].Metadata["attempt"]; ok {
+		t.Error("expected no attempt index without SetRecordAttempts")
+	}
+}
+
+func TestSessionRecorder_InsideTaskRecordsAttemptIndex(t *testing.T) {
+	recorder := evaluation.NewSessionRecorder(nil)
+	task := patterns.NewTask(recorder.Wrap(&flakyAgent{failures: 1}), &patterns.TaskConfig{Retries: 1})
+
+	if _, err := task.Execute(context.Background(), agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recording, _ := recorder.FinalizeSession("default")
+	if len(recording.Interactions) != 2 {
+		t.Fatalf("expected 2 interactions, got %d", len(recording.Interactions))
+	}
+	if recording.Interactions[0].Metadata["attempt"] != 1 || recording.Interactions[0].Metadata["error"] == nil {
+		t.Errorf("unexpected first attempt metadata %v", recording.Interactions[0].Metadata)
+	}
+	if recording.Interactions[1].Metadata["attempt"] != 2 {
+		t.Errorf("unexpected second attempt metadata %v", recording.Interactions[1].Metadata)
+	}
+}
+
+func TestSessionRecorder_RecordsPipelineStages(t *testing.T) {
+	recorder := evaluation.NewSessionRecorder(evaluation.NewMemoryRecordingStorage())
+	pipeline, err := patterns.NewRecordingSequential([]agenkit.Agent{&stageAgent{name: "upper", transform: strings.ToUpper}, &stageAgent{name: "echo"}}, recorder)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := pipeline.Process(context.Background(), agenkit.NewMessage("user", "draft"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recording, err := recorder.FinalizeSession(result.Metadata["pipeline_recording_id"].(string))
+	if err != nil {
+		t.Fatalf("FinalizeSession failed: %v", err)
+	}
+
+	if recording.AgentName != "SequentialAgent" || recording.InteractionCount() != 2 {
+		t.Fatalf("expected 2 stages from SequentialAgent, got %d from %s", recording.InteractionCount(), recording.AgentName)
+	}
+	if got := recording.Interactions[0].OutputMessage["content"]; got != "DRAFT" {
+		t.Errorf("expected the intermediate output recorded, got %v", got)
+	}
+	if got := recording.Interactions[1].Metadata["agent"]; got != "echo" {
+		t.Errorf("expected stage agent recorded, got %v", got)
+	}
+}
@@ -0,0 +1,218 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+	"github.com/scttfrdmn/agenkit-go/codec/msgpack"
+	"github.com/scttfrdmn/agenkit-go/codec/protobuf"
+)
+
+// echoAgent returns its input unchanged.
+type echoAgent struct{}
+
+func (a *echoAgent) Name() string           { return "echo" }
+func (a *echoAgent) Capabilities() []string { return []string{"echo"} }
+func (a *echoAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+func (a *echoAgent) Process(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+	return agenkit.NewMessage("agent", msg.ContentString()), nil
+}
+
+func TestSessionRecorder_TruncatesLargeContent(t *testing.T) {
+	recorder := NewSessionRecorder(nil)
+	recorder.SetMaxContentBytes(16)
+
+	large := strings.Repeat("x", 1000)
+	recorder.RecordInteraction("s1", agenkit.NewMessage("user", "short"), agenkit.NewMessage("agent", large), 1, nil)
+
+	recording, err := recorder.FinalizeSession("s1")
+	if err != nil {
+		t.Fatalf("FinalizeSession failed: %v", err)
+	}
+
+	input := recording.Interactions[0].InputMessage
+	if isTruncated(input) {
+		t.Error("Expected short input not to be truncated")
+	}
+
+	output := recording.Interactions[0].OutputMessage
+	if !isTruncated(output) {
+		t.Fatal("Expected large output to be truncated")
+	}
+	if len(output["content"].(string)) != 16 {
+		t.Errorf("Expected 16 bytes of content, got %d", len(output["content"].(string)))
+	}
+	if output["content_sha256"] != contentHash(large) {
+		t.Error("Expected hash of full content")
+	}
+	if output["content_bytes"] != 1000 {
+		t.Errorf("Expected original length 1000, got %v", output["content_bytes"])
+	}
+}
+
+func TestSessionRecorder_TruncationKeepsValidUTF8(t *testing.T) {
+	recorder := NewSessionRecorder(nil)
+	recorder.SetMaxContentBytes(5)
+
+	// Each rune is 3 bytes, so byte 5 falls mid-rune
+	recorder.RecordInteraction("s1", agenkit.NewMessage("user", "日本語テキスト"), nil, 1, nil)
+
+	recording, _ := recorder.FinalizeSession("s1")
+	content := recording.Interactions[0].InputMessage["content"].(string)
+	if !utf8.ValidString(content) || content != "日" {
+		t.Errorf("Expected truncation at rune boundary, got %q", content)
+	}
+}
+
+func TestSessionRecorder_NoLimitByDefault(t *testing.T) {
+	recorder := NewSessionRecorder(nil)
+	if recorder.MaxContentBytes() != 0 {
+		t.Errorf("Expected no limit by default, got %d", recorder.MaxContentBytes())
+	}
+
+	large := strings.Repeat("y", 10000)
+	recorder.RecordInteraction("s1", agenkit.NewMessage("user", large), nil, 1, nil)
+
+	recording, _ := recorder.FinalizeSession("s1")
+	if recording.Interactions[0].InputMessage["content"] != large {
+		t.Error("Expected content to be recorded in full")
+	}
+}
+
+func TestSessionReplay_ComparesHashesWhenTruncated(t *testing.T) {
+	recorder := NewSessionRecorder(nil)
+	recorder.SetMaxContentBytes(8)
+	agent := recorder.Wrap(&echoAgent{})
+
+	msg := agenkit.NewMessage("user", "short").WithMetadata("session_id", "s1")
+	if _, err := agent.Process(context.Background(), msg); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	recording, _ := recorder.FinalizeSession("s1")
+
+	// Round-trip through JSON as file storage would
+	data, _ := json.Marshal(recording.ToDict())
+	var dict map[string]interface{}
+	_ = json.Unmarshal(data, &dict)
+	restored, err := SessionRecordingFromDict(dict)
+	if err != nil {
+		t.Fatalf("SessionRecordingFromDict failed: %v", err)
+	}
+
+	// Record a long output directly so the recorded output is truncated
+	long := strings.Repeat("z", 100)
+	restored.Interactions[0].InputMessage["content"] = long
+	restored.Interactions[0].OutputMessage = truncateMessageDict(map[string]interface{}{
+		"role": "agent", "content": long, "metadata": map[string]interface{}{},
+	}, 8)
+
+	replay := NewSessionReplay()
+	results, err := replay.Replay(restored, &echoAgent{}, "")
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	interaction := results["interactions"].([]map[string]interface{})[0]
+	if interaction["matches_original"] != true {
+		t.Error("Expected replay output to match truncated original via hash")
+	}
+
+	// A different output must not match
+	restored.Interactions[0].InputMessage["content"] = long + "!"
+	results, _ = replay.Replay(restored, &echoAgent{}, "")
+	interaction = results["interactions"].([]map[string]interface{})[0]
+	if interaction["matches_original"] != false {
+		t.Error("Expected different output not to match")
+	}
+}
+
+func TestContentEqual(t *testing.T) {
+	full := map[string]interface{}{"content": "hello world"}
+	truncated := truncateMessageDict(map[string]interface{}{"content": "hello world"}, 4)
+	other := truncateMessageDict(map[string]interface{}{"content": "hello there"}, 4)
+
+	if !contentEqual(full, truncated) {
+		t.Error("Expected full and truncated forms of same content to be equal")
+	}
+	if contentEqual(truncated, other) {
+		t.Error("Expected different content with same prefix to differ")
+	}
+	if !contentEqual(map[string]interface{}{"content": "a"}, map[string]interface{}{"content": "a"}) {
+		t.Error("Expected identical untruncated content to be equal")
+	}
+}
+
+func TestLocalRecordingStorage_Codecs(t *testing.T) {
+	dir := t.TempDir()
+	storage := NewLocalRecordingStorage(dir)
+	original := recordSession(t, storage, "hello", "world")
+
+	// A JSON recording loads and re-saves in each binary format
+	loaded, err := storage.LoadRecording("golden")
+	if err != nil || loaded == nil {
+		t.Fatalf("LoadRecording failed: %v", err)
+	}
+	storage.SetCodec(msgpack.Codec)
+	if err := storage.SaveRecording(loaded); err != nil {
+		t.Fatalf("SaveRecording failed: %v", err)
+	}
+	reloaded, err := storage.LoadRecording("golden")
+	if err != nil || reloaded == nil {
+		t.Fatalf("LoadRecording failed: %v", err)
+	}
+	if !reflect.DeepEqual(reloaded.ToDict(), loaded.ToDict()) {
+		t.Errorf("msgpack round trip changed recording:\n%v\n%v", reloaded.ToDict(), loaded.ToDict())
+	}
+	if reloaded.Interactions[1].InputMessage["content"] != "world" || len(reloaded.Interactions) != len(original.Interactions) {
+		t.Errorf("unexpected interactions %v", reloaded.Interactions)
+	}
+
+	// Each format lists only its own files
+	recordings, err := storage.ListRecordings(10, 0)
+	if err != nil || len(recordings) != 1 {
+		t.Errorf("expected 1 msgpack recording, got %d (%v)", len(recordings), err)
+	}
+	if err := storage.DeleteRecording("golden"); err != nil {
+		t.Fatalf("DeleteRecording failed: %v", err)
+	}
+	storage.SetCodec(nil)
+	if recording, _ := storage.LoadRecording("golden"); recording == nil {
+		t.Error("expected the JSON recording to remain")
+	}
+}
+
+func TestEncodeRecording_CrossCodec(t *testing.T) {
+	recording := recordSession(t, NewMemoryRecordingStorage(), "hello")
+	want := recording.ToDict()
+
+	data, err := EncodeRecording(protobuf.Codec, recording)
+	if err != nil {
+		t.Fatalf("EncodeRecording failed: %v", err)
+	}
+	decoded, err := DecodeRecording(protobuf.Codec, data)
+	if err != nil {
+		t.Fatalf("DecodeRecording failed: %v", err)
+	}
+	data, err = EncodeRecording(nil, decoded)
+	if err != nil {
+		t.Fatalf("EncodeRecording failed: %v", err)
+	}
+	decoded, err = DecodeRecording(nil, data)
+	if err != nil {
+		t.Fatalf("DecodeRecording failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.ToDict(), want) {
+		t.Errorf("protobuf -> JSON changed recording:\n%v\n%v", decoded.ToDict(), want)
+	}
+
+	if _, err := DecodeRecording(msgpack.Codec, data); err == nil {
+		t.Error("expected error decoding JSON as msgpack")
+	}
+}
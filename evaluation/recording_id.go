@@ -0,0 +1,85 @@
+package evaluation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateRecording is returned by FinalizeSession when duplicate
+// skipping is enabled and storage already holds a recording with the same
+// derived ID.
+var ErrDuplicateRecording = errors.New("duplicate recording")
+
+// RecordingKeyFunc derives a recording's stored ID from its contents.
+type RecordingKeyFunc func(recording *SessionRecording) string
+
+// ContentRecordingKey derives a stable ID from a recording's content: the
+// hex SHA-256 of its agent name and each interaction's input and output
+// roles and content, in order. IDs, timestamps, latencies and metadata are
+// ignored, so identical sessions recorded on different runs get the same
+// ID. Truncated content is hashed by its full-content hash, so the ID does
+// not depend on SetMaxContentBytes.
+//
+// Example:
+//
+//	recorder.SetRecordingKey(ContentRecordingKey)
+func ContentRecordingKey(recording *SessionRecording) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "agent:%s\n", recording.AgentName)
+	for _, interaction := range recording.Interactions {
+		for _, message := range []map[string]interface{}{interaction.InputMessage, interaction.OutputMessage} {
+			if message == nil {
+				fmt.Fprint(hash, "-\n")
+				continue
+			}
+			role, _ := message["role"].(string)
+			fmt.Fprintf(hash, "%s:%s\n", role, fullContentHash(message))
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// SetRecordingKey makes FinalizeSession store recordings under the ID key
+// derives from their content, such as ContentRecordingKey, instead of the
+// session ID. nil (the default) keeps the session ID.
+//
+// The recording's SessionID and its interactions' SessionID become the
+// derived ID; the original session ID is kept in metadata
+// "source_session_id".
+func (r *SessionRecorder) SetRecordingKey(key RecordingKeyFunc) {
+	r.recordingKey = key
+}
+
+// SetSkipDuplicates sets whether FinalizeSession skips saving a recording
+// whose derived ID is already in storage (default false, which overwrites
+// it). Only applies with SetRecordingKey.
+//
+// A skipped recording is not saved; FinalizeSession returns the stored
+// recording together with an error wrapping ErrDuplicateRecording.
+func (r *SessionRecorder) SetSkipDuplicates(enabled bool) {
+	r.skipDuplicates = enabled
+}
+
+// applyRecordingKey re-identifies session by its derived ID. It returns
+// the stored recording if duplicates are skipped and one already exists.
+func (r *SessionRecorder) applyRecordingKey(session *SessionRecording) (*SessionRecording, error) {
+	id := r.recordingKey(session)
+	if id == "" {
+		return nil, fmt.Errorf("recording key function returned an empty ID for session %s", session.SessionID)
+	}
+
+	session.Metadata["source_session_id"] = session.SessionID
+	session.SessionID = id
+	for _, interaction := range session.Interactions {
+		interaction.SessionID = id
+	}
+
+	if r.skipDuplicates {
+		if existing, err := r.storage.LoadRecording(id); err == nil && existing != nil {
+			return existing, fmt.Errorf("%w: %s", ErrDuplicateRecording, id)
+		}
+	}
+	return nil, nil
+}
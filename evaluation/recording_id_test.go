@@ -0,0 +1,95 @@
+package evaluation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// recordCannedSession records one canned exchange under sessionID.
+func recordCannedSession(recorder *SessionRecorder, sessionID, reply string) (*SessionRecording, error) {
+	recorder.StartSession(sessionID, "echo", nil)
+	recorder.RecordInteraction(sessionID, agenkit.NewMessage("user", "hello"), agenkit.NewMessage("agent", reply), 5, nil)
+	return recorder.FinalizeSession(sessionID)
+}
+
+func TestContentRecordingKey_StableAcrossRuns(t *testing.T) {
+	recorder := NewSessionRecorder(nil)
+	recorder.SetRecordingKey(ContentRecordingKey)
+
+	first, err := recordCannedSession(recorder, "run-1", "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := recordCannedSession(recorder, "run-2", "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.SessionID != second.SessionID {
+		t.Errorf("expected identical sessions to share an ID, got %s and %s", first.SessionID, second.SessionID)
+	}
+	if second.Metadata["source_session_id"] != "run-2" {
+		t.Errorf("expected source session ID kept, got %v", second.Metadata["source_session_id"])
+	}
+	if second.Interactions[0].SessionID != second.SessionID {
+		t.Errorf("expected interactions re-identified, got %s", second.Interactions[0].SessionID)
+	}
+
+	different, err := recordCannedSession(recorder, "run-3", "bye")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if different.SessionID == first.SessionID {
+		t.Error("expected different content to get a different ID")
+	}
+
+	recordings, _ := recorder.ListRecordings(10, 0)
+	if len(recordings) != 2 {
+		t.Errorf("expected 2 stored recordings, got %d", len(recordings))
+	}
+}
+
+func TestSessionRecorder_SkipDuplicates(t *testing.T) {
+	recorder := NewSessionRecorder(nil)
+	recorder.SetRecordingKey(ContentRecordingKey)
+	recorder.SetSkipDuplicates(true)
+
+	first, err := recordCannedSession(recorder, "run-1", "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	existing, err := recordCannedSession(recorder, "run-2", "hi")
+	if !errors.Is(err, ErrDuplicateRecording) {
+		t.Fatalf("expected ErrDuplicateRecording, got %v", err)
+	}
+	if existing == nil || existing.Metadata["source_session_id"] != "run-1" {
+		t.Errorf("expected the stored recording back, got %+v", existing)
+	}
+	if existing.SessionID != first.SessionID {
+		t.Errorf("expected duplicate to resolve to %s, got %s", first.SessionID, existing.SessionID)
+	}
+}
+
+func TestSessionRecorder_CustomRecordingKey(t *testing.T) {
+	recorder := NewSessionRecorder(nil)
+	recorder.SetRecordingKey(func(recording *SessionRecording) string {
+		return "corpus-" + recording.AgentName
+	})
+
+	recording, err := recordCannedSession(recorder, "run-1", "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recording.SessionID != "corpus-echo" {
+		t.Errorf("expected custom ID, got %s", recording.SessionID)
+	}
+	if _, err := recorder.LoadRecording("corpus-echo"); err != nil {
+		t.Errorf("expected recording stored under custom ID: %v", err)
+	}
+
+	recorder.SetRecordingKey(func(recording *SessionRecording) string { return "" })
+	if _, err := recordCannedSession(recorder, "run-2", "hi"); err == nil {
+		t.Error("expected error for empty derived ID")
+	}
+}
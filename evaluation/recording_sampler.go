@@ -0,0 +1,181 @@
+// Package evaluation provides sampling for session recording.
+//
+// Keeps a representative fraction of normal traffic while still
+// recording every failure.
+package evaluation
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ErrSessionNotSampled is returned by SessionRecorder.FinalizeSession when
+// the sampler dropped every interaction of the session, so nothing was saved.
+var ErrSessionNotSampled = errors.New("session not sampled")
+
+// RecordingSamplerConfig configures a RecordingSampler.
+type RecordingSamplerConfig struct {
+	// Rate is the probability (0-1) of recording an interaction that did
+	// not fail. 0 records nothing but (optionally) failures.
+	Rate float64
+	// AlwaysRecordErrors records failed interactions regardless of Rate
+	AlwaysRecordErrors bool
+	// PerSession makes one decision per session (head sampling) instead of
+	// one per interaction, so sampled sessions are recorded in full. The
+	// decision is derived from a hash of the session ID, so every recorder
+	// and process agrees on it without coordination.
+	PerSession bool
+}
+
+// SamplingStats reports what a RecordingSampler has recorded so far.
+type SamplingStats struct {
+	// Rate is the configured sampling rate
+	Rate float64
+	// Seen is the number of interactions offered to the sampler
+	Seen int64
+	// Recorded is the number of interactions kept
+	Recorded int64
+	// Dropped is the number of interactions skipped
+	Dropped int64
+	// ErrorsRecorded is the number of failed interactions kept
+	ErrorsRecorded int64
+}
+
+// EffectiveRate returns the fraction of seen interactions that were
+// recorded (0 if none were seen). It exceeds Rate when failures are always
+// recorded and differs from it by sampling noise otherwise.
+func (s SamplingStats) EffectiveRate() float64 {
+	if s.Seen == 0 {
+		return 0.0
+	}
+	return float64(s.Recorded) / float64(s.Seen)
+}
+
+// RecordingSampler decides which interactions a SessionRecorder keeps.
+//
+// Recording every interaction costs serialization and storage IO that
+// adds up at thousands of requests per second. A sampler keeps a
+// representative fraction of normal traffic while still capturing every
+// failure. The decision is made before the messages are serialized, so
+// dropped interactions cost only a random draw (or a hash, for per-session
+// sampling) and a few atomic counter updates.
+//
+// Safe for concurrent use.
+//
+// Example:
+//
+//	sampler, _ := NewRecordingSampler(&RecordingSamplerConfig{
+//	    Rate:               0.01,
+//	    AlwaysRecordErrors: true,
+//	    PerSession:         true,
+//	})
+//	recorder := NewSessionRecorder(storage)
+//	recorder.SetSampler(sampler)
+//
+//	// Later, for dashboards
+//	stats := sampler.Stats()
+//	fmt.Printf("recording %.2f%% of interactions\n", stats.EffectiveRate()*100)
+type RecordingSampler struct {
+	rate               float64
+	alwaysRecordErrors bool
+	perSession         bool
+
+	seen           atomic.Int64
+	recorded       atomic.Int64
+	errorsRecorded atomic.Int64
+}
+
+// NewRecordingSampler creates a new recording sampler.
+func NewRecordingSampler(config *RecordingSamplerConfig) (*RecordingSampler, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+	if math.IsNaN(config.Rate) || config.Rate < 0 || config.Rate > 1 {
+		return nil, fmt.Errorf("rate must be between 0 and 1, got %v", config.Rate)
+	}
+
+	return &RecordingSampler{
+		rate:               config.Rate,
+		alwaysRecordErrors: config.AlwaysRecordErrors,
+		perSession:         config.PerSession,
+	}, nil
+}
+
+// Rate returns the configured sampling rate.
+func (s *RecordingSampler) Rate() float64 {
+	return s.rate
+}
+
+// ShouldRecord reports whether an interaction of sessionID should be
+// recorded, and counts the decision in Stats.
+func (s *RecordingSampler) ShouldRecord(sessionID string, failed bool) bool {
+	s.seen.Add(1)
+
+	keep := false
+	switch {
+	case failed && s.alwaysRecordErrors:
+		keep = true
+	case s.perSession:
+		keep = sessionSampled(sessionID, s.rate)
+	default:
+		keep = s.rate >= 1 || rand.Float64() < s.rate
+	}
+
+	if keep {
+		s.recorded.Add(1)
+		if failed {
+			s.errorsRecorded.Add(1)
+		}
+	}
+	return keep
+}
+
+// Stats returns a snapshot of the sampler's counters.
+func (s *RecordingSampler) Stats() SamplingStats {
+	// Load recorded first: it is incremented after seen, so Dropped is never negative
+	recorded := s.recorded.Load()
+	seen := s.seen.Load()
+	return SamplingStats{
+		Rate:           s.rate,
+		Seen:           seen,
+		Recorded:       recorded,
+		Dropped:        seen - recorded,
+		ErrorsRecorded: s.errorsRecorded.Load(),
+	}
+}
+
+// sessionSampled maps sessionID to a stable point in [0, 1) and reports
+// whether it falls below rate.
+func sessionSampled(sessionID string, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(sessionID))
+
+	// FNV's high bits barely change between IDs like "s-1" and "s-2", so
+	// mix them (splitmix64 finalizer) before scaling
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x>>11)/(1<<53) < rate
+}
+
+// interactionFailed reports whether a recorded interaction represents a
+// failure: no output, or an "error" entry in its metadata.
+func interactionFailed(output *agenkit.Message, metadata map[string]interface{}) bool {
+	if output == nil {
+		return true
+	}
+	_, failed := metadata["error"]
+	return failed
+}
@@ -0,0 +1,121 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestNewRecordingSampler_Validation(t *testing.T) {
+	if _, err := NewRecordingSampler(nil); err == nil {
+		t.Error("Expected error for nil config")
+	}
+	for _, rate := range []float64{-0.1, 1.5} {
+		if _, err := NewRecordingSampler(&RecordingSamplerConfig{Rate: rate}); err == nil {
+			t.Errorf("Expected error for rate %v", rate)
+		}
+	}
+}
+
+func TestRecordingSampler_AlwaysRecordsErrors(t *testing.T) {
+	sampler, _ := NewRecordingSampler(&RecordingSamplerConfig{Rate: 0, AlwaysRecordErrors: true})
+	recorder := NewSessionRecorder(nil)
+	recorder.SetSampler(sampler)
+
+	for i := 0; i < 10; i++ {
+		recorder.RecordInteraction("s1", agenkit.NewMessage("user", "ok"), agenkit.NewMessage("agent", "fine"), 1, nil)
+	}
+	recorder.RecordInteraction("s1", agenkit.NewMessage("user", "bad"), nil, 1, map[string]interface{}{"error": "boom"})
+
+	recording, err := recorder.FinalizeSession("s1")
+	if err != nil {
+		t.Fatalf("FinalizeSession failed: %v", err)
+	}
+	if recording.InteractionCount() != 1 {
+		t.Fatalf("Expected only the failed interaction, got %d", recording.InteractionCount())
+	}
+	if recording.Metadata["sampling_rate"] != 0.0 || recording.Metadata["sampling_dropped"] != 10 {
+		t.Errorf("Unexpected sampling metadata: %v", recording.Metadata)
+	}
+
+	stats := sampler.Stats()
+	if stats.Seen != 11 || stats.Recorded != 1 || stats.Dropped != 10 || stats.ErrorsRecorded != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if rate := stats.EffectiveRate(); rate != 1.0/11 {
+		t.Errorf("Expected effective rate 1/11, got %v", rate)
+	}
+}
+
+func TestRecordingSampler_WrapperRecordsAgentErrors(t *testing.T) {
+	sampler, _ := NewRecordingSampler(&RecordingSamplerConfig{Rate: 0, AlwaysRecordErrors: true})
+	recorder := NewSessionRecorder(nil)
+	recorder.SetSampler(sampler)
+
+	agent := recorder.Wrap(&upperAgent{})
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "fail")); err == nil {
+		t.Fatal("Expected agent error")
+	}
+
+	recording, err := recorder.FinalizeSession("default")
+	if err != nil {
+		t.Fatalf("FinalizeSession failed: %v", err)
+	}
+	if recording.InteractionCount() != 1 || recording.Interactions[0].Metadata["error"] != "boom" {
+		t.Errorf("Expected the failed interaction with its error, got %+v", recording.Interactions)
+	}
+}
+
+func TestRecordingSampler_SessionNotSampled(t *testing.T) {
+	sampler, _ := NewRecordingSampler(&RecordingSamplerConfig{Rate: 0})
+	storage := NewMemoryRecordingStorage()
+	recorder := NewSessionRecorder(storage)
+	recorder.SetSampler(sampler)
+
+	recorder.RecordInteraction("s1", agenkit.NewMessage("user", "hi"), agenkit.NewMessage("agent", "hello"), 1, nil)
+
+	if _, err := recorder.FinalizeSession("s1"); !errors.Is(err, ErrSessionNotSampled) {
+		t.Fatalf("Expected ErrSessionNotSampled, got %v", err)
+	}
+	if recordings, _ := storage.ListRecordings(10, 0); len(recordings) != 0 {
+		t.Errorf("Expected nothing saved, got %d recordings", len(recordings))
+	}
+}
+
+func TestRecordingSampler_PerSessionIsAllOrNothing(t *testing.T) {
+	sampler, _ := NewRecordingSampler(&RecordingSamplerConfig{Rate: 0.5, PerSession: true})
+
+	sampledSessions := 0
+	for i := 0; i < 200; i++ {
+		sessionID := fmt.Sprintf("session-%d", i)
+		first := sampler.ShouldRecord(sessionID, false)
+		for j := 0; j < 5; j++ {
+			if sampler.ShouldRecord(sessionID, false) != first {
+				t.Fatalf("Expected a stable decision for %s", sessionID)
+			}
+		}
+		if first {
+			sampledSessions++
+		}
+	}
+
+	// Hash-based sampling should land near the configured rate
+	if sampledSessions < 70 || sampledSessions > 130 {
+		t.Errorf("Expected about 100 of 200 sessions sampled, got %d", sampledSessions)
+	}
+}
+
+func TestRecordingSampler_FullRateRecordsEverything(t *testing.T) {
+	sampler, _ := NewRecordingSampler(&RecordingSamplerConfig{Rate: 1})
+	for i := 0; i < 100; i++ {
+		if !sampler.ShouldRecord("s", false) {
+			t.Fatal("Expected rate 1 to record every interaction")
+		}
+	}
+	if sampler.Stats().EffectiveRate() != 1.0 {
+		t.Errorf("Expected effective rate 1, got %v", sampler.Stats().EffectiveRate())
+	}
+}
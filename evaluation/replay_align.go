@@ -0,0 +1,295 @@
+// Package evaluation provides order-independent comparison of recordings.
+//
+// Matches the interactions of two recordings by output content, for
+// agents whose step order legitimately varies.
+package evaluation
+
+import (
+	"sort"
+)
+
+// ReorderedMatchWeight is how much a matched interaction that moved counts
+// toward ReplayAlignment.Score, relative to one that kept its position.
+// Reordering is penalized slightly, so "same actions, different order"
+// scores as a near-match rather than a regression.
+const ReorderedMatchWeight = 0.9
+
+// AlignedInteraction pairs an interaction from the original session with
+// the matching interaction from the replayed session.
+type AlignedInteraction struct {
+	// OriginalIndex is the interaction's position in the original session
+	OriginalIndex int `json:"original_index"`
+	// ReplayIndex is the matching interaction's position in the replay
+	ReplayIndex int `json:"replay_index"`
+	// Original and Replay are the two output contents
+	Original string `json:"original"`
+	Replay   string `json:"replay"`
+}
+
+// UnmatchedInteraction is an interaction with no counterpart in the other
+// session.
+type UnmatchedInteraction struct {
+	// Index is the interaction's position in its session
+	Index int `json:"index"`
+	// Content is the interaction's output content (empty on error)
+	Content string `json:"content"`
+	// Error is the agent error, if the interaction failed
+	Error string `json:"error,omitempty"`
+}
+
+// ReplayAlignment matches the interactions of two sessions by content
+// rather than position, so the same outputs produced in a different order
+// are reported as reorderings instead of mismatches.
+type ReplayAlignment struct {
+	// Matched pairs every interaction found in both sessions, sorted by
+	// OriginalIndex
+	Matched []AlignedInteraction `json:"matched"`
+	// Reordered are the matched interactions that moved: the fewest pairs
+	// whose removal leaves the rest in the same relative order
+	Reordered []AlignedInteraction `json:"reordered"`
+	// Added are replay interactions with no match in the original
+	Added []UnmatchedInteraction `json:"added"`
+	// Removed are original interactions with no match in the replay
+	Removed []UnmatchedInteraction `json:"removed"`
+	// Score is the alignment score from 0 to 1: the share of interactions
+	// in either session that matched, with reordered matches weighted by
+	// ReorderedMatchWeight. Identical sessions score 1.
+	Score float64 `json:"score"`
+}
+
+// InOrder reports whether every interaction matched in its original order,
+// with no additions or removals.
+func (a *ReplayAlignment) InOrder() bool {
+	return len(a.Reordered) == 0 && len(a.Added) == 0 && len(a.Removed) == 0
+}
+
+// ToDict converts the alignment to a map.
+func (a *ReplayAlignment) ToDict() map[string]interface{} {
+	pairs := func(aligned []AlignedInteraction) []map[string]interface{} {
+		result := make([]map[string]interface{}, len(aligned))
+		for i, pair := range aligned {
+			result[i] = map[string]interface{}{
+				"original_index": pair.OriginalIndex,
+				"replay_index":   pair.ReplayIndex,
+				"original":       pair.Original,
+				"replay":         pair.Replay,
+			}
+		}
+		return result
+	}
+	unmatched := func(interactions []UnmatchedInteraction) []map[string]interface{} {
+		result := make([]map[string]interface{}, len(interactions))
+		for i, interaction := range interactions {
+			result[i] = map[string]interface{}{
+				"index":   interaction.Index,
+				"content": interaction.Content,
+			}
+			if interaction.Error != "" {
+				result[i]["error"] = interaction.Error
+			}
+		}
+		return result
+	}
+	return map[string]interface{}{
+		"matched":   pairs(a.Matched),
+		"reordered": pairs(a.Reordered),
+		"added":     unmatched(a.Added),
+		"removed":   unmatched(a.Removed),
+		"score":     a.Score,
+		"in_order":  a.InOrder(),
+	}
+}
+
+// AlignRecordings matches the interactions of two recordings by output
+// content, regardless of position. Use it to compare sessions of agents
+// whose step order legitimately varies, such as reasoning agents calling
+// the same tools in a different order.
+//
+// Args:
+//
+//	original: Recording of the baseline session
+//	candidate: Recording of the session to compare
+//	matcher: Decides whether two outputs match (nil means ExactOutputMatch)
+//
+// Returns:
+//
+//	Alignment reporting matches, reorderings, additions and removals
+//
+// Truncated outputs are compared by content hash; matcher is not consulted
+// for them.
+func AlignRecordings(original, candidate *SessionRecording, matcher ValidatorFunc) *ReplayAlignment {
+	return alignOutputs(recordingOutputs(original), recordingOutputs(candidate), matcher)
+}
+
+// CompareAligned compares two replay results like Compare, but matches
+// interactions by output content rather than position.
+//
+// Args:
+//
+//	resultsA: First replay results (the baseline)
+//	resultsB: Second replay results
+//	matcher: Decides whether two outputs match (nil means ExactOutputMatch)
+//
+// Returns:
+//
+//	Alignment reporting matches, reorderings, additions and removals
+//
+// Interactions that failed never match, so they are reported as removed
+// (from resultsA) or added (from resultsB).
+func (r *SessionReplay) CompareAligned(resultsA, resultsB map[string]interface{}, matcher ValidatorFunc) *ReplayAlignment {
+	return alignOutputs(replayOutputs(resultsA), replayOutputs(resultsB), matcher)
+}
+
+// alignedOutput is one interaction's output as a message dict, or the
+// error that prevented it.
+type alignedOutput struct {
+	output map[string]interface{}
+	err    string
+}
+
+func (o alignedOutput) content() string {
+	content, _ := o.output["content"].(string)
+	return content
+}
+
+// recordingOutputs returns the outputs of a recording's interactions.
+func recordingOutputs(recording *SessionRecording) []alignedOutput {
+	if recording == nil {
+		return nil
+	}
+	outputs := make([]alignedOutput, len(recording.Interactions))
+	for i, interaction := range recording.Interactions {
+		outputs[i] = alignedOutput{output: interaction.OutputMessage}
+	}
+	return outputs
+}
+
+// replayOutputs returns the outputs of a Replay result's interactions.
+func replayOutputs(results map[string]interface{}) []alignedOutput {
+	interactions, _ := results["interactions"].([]map[string]interface{})
+	outputs := make([]alignedOutput, len(interactions))
+	for i, interaction := range interactions {
+		if err, ok := interaction["error"].(string); ok {
+			outputs[i] = alignedOutput{err: err}
+			continue
+		}
+		outputs[i] = alignedOutput{output: getMapOrEmpty(interaction, "replay_output")}
+	}
+	return outputs
+}
+
+// alignOutputs matches original and replay outputs. An interaction keeps
+// its position when it matches there; otherwise it is paired with the
+// first unmatched original interaction it matches.
+func alignOutputs(original, replay []alignedOutput, matcher ValidatorFunc) *ReplayAlignment {
+	if matcher == nil {
+		matcher = ExactOutputMatch
+	}
+	matches := func(a, b alignedOutput) bool {
+		if a.err != "" || b.err != "" {
+			return false
+		}
+		if isTruncated(a.output) || isTruncated(b.output) {
+			return contentEqual(a.output, b.output)
+		}
+		return matcher(a.content(), b.content())
+	}
+
+	originalMatch := make([]int, len(original))
+	replayMatch := make([]int, len(replay))
+	for i := range originalMatch {
+		originalMatch[i] = -1
+	}
+	for j := range replayMatch {
+		replayMatch[j] = -1
+	}
+
+	for i := 0; i < len(original) && i < len(replay); i++ {
+		if matches(original[i], replay[i]) {
+			originalMatch[i], replayMatch[i] = i, i
+		}
+	}
+	for j := range replay {
+		if replayMatch[j] >= 0 {
+			continue
+		}
+		for i := range original {
+			if originalMatch[i] < 0 && matches(original[i], replay[j]) {
+				originalMatch[i], replayMatch[j] = j, i
+				break
+			}
+		}
+	}
+
+	alignment := &ReplayAlignment{
+		Matched:   make([]AlignedInteraction, 0),
+		Reordered: make([]AlignedInteraction, 0),
+		Added:     make([]UnmatchedInteraction, 0),
+		Removed:   make([]UnmatchedInteraction, 0),
+	}
+	for i, j := range originalMatch {
+		if j < 0 {
+			alignment.Removed = append(alignment.Removed, UnmatchedInteraction{Index: i, Content: original[i].content(), Error: original[i].err})
+			continue
+		}
+		alignment.Matched = append(alignment.Matched, AlignedInteraction{
+			OriginalIndex: i,
+			ReplayIndex:   j,
+			Original:      original[i].content(),
+			Replay:        replay[j].content(),
+		})
+	}
+	for j, i := range replayMatch {
+		if i < 0 {
+			alignment.Added = append(alignment.Added, UnmatchedInteraction{Index: j, Content: replay[j].content(), Error: replay[j].err})
+		}
+	}
+
+	inOrder := longestIncreasingReplayOrder(alignment.Matched)
+	for k, pair := range alignment.Matched {
+		if !inOrder[k] {
+			alignment.Reordered = append(alignment.Reordered, pair)
+		}
+	}
+
+	total := len(original) + len(replay)
+	if total == 0 {
+		alignment.Score = 1.0
+		return alignment
+	}
+	moved := float64(len(alignment.Reordered))
+	kept := float64(len(alignment.Matched)) - moved
+	alignment.Score = 2 * (kept + ReorderedMatchWeight*moved) / float64(total)
+	return alignment
+}
+
+// longestIncreasingReplayOrder marks the largest set of pairs (sorted by
+// OriginalIndex) whose ReplayIndex also increases; the unmarked pairs are
+// the fewest that must move to explain the replay's order.
+func longestIncreasingReplayOrder(pairs []AlignedInteraction) []bool {
+	// tails[l] is the index of the pair ending the best run of length l+1
+	tails := make([]int, 0, len(pairs))
+	previous := make([]int, len(pairs))
+	for k, pair := range pairs {
+		l := sort.Search(len(tails), func(l int) bool {
+			return pairs[tails[l]].ReplayIndex >= pair.ReplayIndex
+		})
+		previous[k] = -1
+		if l > 0 {
+			previous[k] = tails[l-1]
+		}
+		if l == len(tails) {
+			tails = append(tails, k)
+		} else {
+			tails[l] = k
+		}
+	}
+
+	inOrder := make([]bool, len(pairs))
+	if len(tails) > 0 {
+		for k := tails[len(tails)-1]; k >= 0; k = previous[k] {
+			inOrder[k] = true
+		}
+	}
+	return inOrder
+}
@@ -0,0 +1,131 @@
+package evaluation
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestAlignRecordings_Identical(t *testing.T) {
+	storage := NewMemoryRecordingStorage()
+	alignment := AlignRecordings(
+		recordSession(t, storage, "search", "read", "answer"),
+		recordSession(t, storage, "search", "read", "answer"),
+		nil,
+	)
+
+	if !alignment.InOrder() || alignment.Score != 1.0 || len(alignment.Matched) != 3 {
+		t.Errorf("expected a perfect alignment, got %+v", alignment)
+	}
+}
+
+func TestAlignRecordings_Reordered(t *testing.T) {
+	storage := NewMemoryRecordingStorage()
+	alignment := AlignRecordings(
+		recordSession(t, storage, "search", "read", "calculate", "answer"),
+		recordSession(t, storage, "read", "search", "calculate", "answer"),
+		nil,
+	)
+
+	if len(alignment.Matched) != 4 || len(alignment.Added) != 0 || len(alignment.Removed) != 0 {
+		t.Fatalf("expected every interaction matched, got %+v", alignment)
+	}
+	if len(alignment.Reordered) != 1 {
+		t.Fatalf("expected one reordering, got %+v", alignment.Reordered)
+	}
+	if alignment.InOrder() {
+		t.Error("expected InOrder to be false")
+	}
+	// 3 kept + 1 moved at ReorderedMatchWeight, over 4 interactions
+	want := (3 + ReorderedMatchWeight) / 4
+	if math.Abs(alignment.Score-want) > 1e-9 {
+		t.Errorf("expected score %v, got %v", want, alignment.Score)
+	}
+}
+
+func TestAlignRecordings_AddedAndRemoved(t *testing.T) {
+	storage := NewMemoryRecordingStorage()
+	alignment := AlignRecordings(
+		recordSession(t, storage, "search", "read", "answer"),
+		recordSession(t, storage, "search", "calculate", "answer", "summarize"),
+		nil,
+	)
+
+	if len(alignment.Matched) != 2 || len(alignment.Reordered) != 0 {
+		t.Errorf("expected 2 in-order matches, got %+v", alignment)
+	}
+	if len(alignment.Removed) != 1 || alignment.Removed[0].Content != "read" || alignment.Removed[0].Index != 1 {
+		t.Errorf("unexpected removals: %+v", alignment.Removed)
+	}
+	if len(alignment.Added) != 2 || alignment.Added[0].Content != "calculate" || alignment.Added[1].Content != "summarize" {
+		t.Errorf("unexpected additions: %+v", alignment.Added)
+	}
+	if want := 4.0 / 7.0; math.Abs(alignment.Score-want) > 1e-9 {
+		t.Errorf("expected score %v, got %v", want, alignment.Score)
+	}
+}
+
+func TestAlignRecordings_DuplicatesAndMatcher(t *testing.T) {
+	storage := NewMemoryRecordingStorage()
+	alignment := AlignRecordings(
+		recordSession(t, storage, "Search docs", "search docs", "answer"),
+		recordSession(t, storage, "ANSWER", "search docs", "search  docs"),
+		NormalizedOutputMatch,
+	)
+
+	if len(alignment.Matched) != 3 || len(alignment.Added) != 0 || len(alignment.Removed) != 0 {
+		t.Fatalf("expected every interaction matched once, got %+v", alignment)
+	}
+	// Position 1 keeps its match; the other two swap around it
+	if alignment.Matched[1].ReplayIndex != 1 {
+		t.Errorf("expected the positional match to be kept, got %+v", alignment.Matched)
+	}
+}
+
+func TestAlignRecordings_Empty(t *testing.T) {
+	alignment := AlignRecordings(nil, &SessionRecording{}, nil)
+	if alignment.Score != 1.0 || !alignment.InOrder() {
+		t.Errorf("expected empty sessions to align perfectly, got %+v", alignment)
+	}
+}
+
+func TestSessionReplay_CompareAligned(t *testing.T) {
+	storage := NewMemoryRecordingStorage()
+	recording := recordSession(t, storage, "a", "b", "fail")
+
+	replay := NewSessionReplay()
+	resultsA, _ := replay.Replay(recording, &upperAgent{}, "")
+	resultsB, _ := replay.Replay(recording, &reversingAgent{}, "")
+
+	alignment := replay.CompareAligned(resultsA, resultsB, NormalizedOutputMatch)
+	if len(alignment.Matched) != 2 || len(alignment.Reordered) != 1 {
+		t.Errorf("expected a and b matched with one reordering, got %+v", alignment)
+	}
+	if len(alignment.Removed) != 1 || alignment.Removed[0].Error == "" {
+		t.Errorf("expected the failed interaction reported as removed, got %+v", alignment.Removed)
+	}
+	if len(alignment.Added) != 1 || alignment.Added[0].Error == "" {
+		t.Errorf("expected the failed interaction reported as added, got %+v", alignment.Added)
+	}
+
+	dict := alignment.ToDict()
+	if dict["in_order"] != false || len(dict["reordered"].([]map[string]interface{})) != 1 {
+		t.Errorf("unexpected dict: %v", dict)
+	}
+}
+
+// reversingAgent answers "a" with "b" and "b" with "a", as an agent that
+// takes the same steps in a different order would; it fails like upperAgent.
+type reversingAgent struct{ upperAgent }
+
+func (a *reversingAgent) Process(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+	switch msg.ContentString() {
+	case "a":
+		return agenkit.NewMessage("agent", "b"), nil
+	case "b":
+		return agenkit.NewMessage("agent", "a"), nil
+	}
+	return a.upperAgent.Process(ctx, msg)
+}
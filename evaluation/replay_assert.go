@@ -0,0 +1,246 @@
+// Package evaluation provides golden-output assertions for recorded sessions.
+//
+// Replays a recording through an agent in a test and fails on every
+// interaction whose output differs from the recorded one.
+package evaluation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// UpdateRecordingsEnv is the environment variable that switches
+// AssertReplayMatches from asserting to re-recording golden outputs.
+//
+//	UPDATE_RECORDINGS=1 go test ./...
+const UpdateRecordingsEnv = "UPDATE_RECORDINGS"
+
+// ExactOutputMatch requires the replayed output to equal the recorded one.
+func ExactOutputMatch(expected, actual string) bool {
+	return expected == actual
+}
+
+// NormalizedOutputMatch ignores case, Unicode normalization form and
+// differences in whitespace (see NormalizedMatch).
+func NormalizedOutputMatch(expected, actual string) bool {
+	return normalizeAnswer(expected) == normalizeAnswer(actual)
+}
+
+// TokenSimilarityMatch accepts outputs whose word sets overlap by at least
+// threshold (Jaccard similarity, 0-1, case-insensitive). It is a cheap,
+// lexical stand-in for semantic comparison; pass a custom ValidatorFunc
+// (for example embedding-based) when wording may legitimately change.
+func TokenSimilarityMatch(threshold float64) ValidatorFunc {
+	return func(expected, actual string) bool {
+		return tokenJaccard(expected, actual) >= threshold
+	}
+}
+
+// ReplayMismatch describes one interaction whose replayed output did not
+// match the recording.
+type ReplayMismatch struct {
+	// Index is the interaction's position in the recording
+	Index int
+	// Input is the recorded input content
+	Input string
+	// Expected is the recorded output content
+	Expected string
+	// Actual is the replayed output content (empty on error)
+	Actual string
+	// Error is the agent error, if the replay failed
+	Error string
+	// InputTruncated is true if the input was truncated on record, so the
+	// agent saw only a prefix of the original input
+	InputTruncated bool
+}
+
+func (m ReplayMismatch) String() string {
+	if m.Error != "" {
+		return fmt.Sprintf("interaction %d: input %q: replay failed: %s", m.Index, m.Input, m.Error)
+	}
+	return fmt.Sprintf("interaction %d: input %q:\n  recorded: %q\n  replayed: %q", m.Index, m.Input, m.Expected, m.Actual)
+}
+
+// CompareToRecording replays every interaction in recording through agent
+// and returns those whose output does not satisfy matcher (nil means
+// ExactOutputMatch). An error is returned only if ctx is cancelled.
+//
+// Recorded outputs that were truncated are compared by content hash,
+// since the full text is unavailable; matcher is not consulted for them.
+func CompareToRecording(ctx context.Context, recording *SessionRecording, agent agenkit.Agent, matcher ValidatorFunc) ([]ReplayMismatch, error) {
+	if matcher == nil {
+		matcher = ExactOutputMatch
+	}
+
+	mismatches := make([]ReplayMismatch, 0)
+	for i, interaction := range recording.Interactions {
+		if err := ctx.Err(); err != nil {
+			return mismatches, err
+		}
+
+		input := interactionInput(interaction)
+		mismatch := ReplayMismatch{
+			Index:          i,
+			Input:          input.ContentString(),
+			InputTruncated: isTruncated(interaction.InputMessage),
+		}
+		mismatch.Expected, _ = interaction.OutputMessage["content"].(string)
+
+		output, err := agent.Process(ctx, input)
+		if err != nil {
+			mismatch.Error = err.Error()
+			mismatches = append(mismatches, mismatch)
+			continue
+		}
+
+		replayed := messageToDict(output)
+		mismatch.Actual, _ = replayed["content"].(string)
+
+		matched := false
+		if isTruncated(interaction.OutputMessage) {
+			matched = contentEqual(interaction.OutputMessage, replayed)
+		} else {
+			matched = matcher(mismatch.Expected, mismatch.Actual)
+		}
+		if !matched {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+
+	return mismatches, nil
+}
+
+// ReplayT is the subset of testing.TB used by AssertReplayMatches.
+type ReplayT interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+	Logf(format string, args ...any)
+}
+
+// ReplayAssertOptions configures AssertReplayMatches.
+type ReplayAssertOptions struct {
+	// Matcher decides whether outputs match (default: ExactOutputMatch)
+	Matcher ValidatorFunc
+	// Storage persists re-recorded outputs in update mode. Required when
+	// UPDATE_RECORDINGS is set.
+	Storage RecordingStorage
+}
+
+// AssertReplayMatches replays recording through agent and fails the test
+// for every interaction whose output differs from the recorded one.
+//
+// When the UPDATE_RECORDINGS environment variable is true, it instead
+// re-records each interaction's output from agent and saves the recording
+// to opts.Storage, following the usual golden-file convention.
+//
+// Example:
+//
+//	func TestSupportAgentRegression(t *testing.T) {
+//	    storage := evaluation.NewLocalRecordingStorage("testdata/recordings")
+//	    recording, err := storage.LoadRecording("support-golden")
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    evaluation.AssertReplayMatches(t, recording, newSupportAgent(), &evaluation.ReplayAssertOptions{
+//	        Matcher: evaluation.NormalizedOutputMatch,
+//	        Storage: storage,
+//	    })
+//	}
+func AssertReplayMatches(t ReplayT, recording *SessionRecording, agent agenkit.Agent, opts *ReplayAssertOptions) {
+	t.Helper()
+
+	if opts == nil {
+		opts = &ReplayAssertOptions{}
+	}
+
+	if updateRecordings() {
+		if opts.Storage == nil {
+			t.Fatalf("%s is set but no Storage was provided to save recording %s", UpdateRecordingsEnv, recording.SessionID)
+			return
+		}
+		if err := rerecord(context.Background(), recording, agent); err != nil {
+			t.Fatalf("re-recording %s: %v", recording.SessionID, err)
+			return
+		}
+		if err := opts.Storage.SaveRecording(recording); err != nil {
+			t.Fatalf("saving recording %s: %v", recording.SessionID, err)
+			return
+		}
+		t.Logf("updated recording %s (%d interactions)", recording.SessionID, recording.InteractionCount())
+		return
+	}
+
+	mismatches, err := CompareToRecording(context.Background(), recording, agent, opts.Matcher)
+	if err != nil {
+		t.Fatalf("replaying %s: %v", recording.SessionID, err)
+		return
+	}
+	for _, mismatch := range mismatches {
+		t.Errorf("recording %s: %s", recording.SessionID, mismatch)
+	}
+}
+
+// updateRecordings reports whether UPDATE_RECORDINGS is set to a true value.
+func updateRecordings() bool {
+	update, _ := strconv.ParseBool(os.Getenv(UpdateRecordingsEnv))
+	return update
+}
+
+// rerecord replaces each interaction's output with agent's current output.
+func rerecord(ctx context.Context, recording *SessionRecording, agent agenkit.Agent) error {
+	for i, interaction := range recording.Interactions {
+		start := time.Now()
+		output, err := agent.Process(ctx, interactionInput(interaction))
+		if err != nil {
+			return fmt.Errorf("interaction %d: %w", i, err)
+		}
+		interaction.OutputMessage = messageToDict(output)
+		interaction.LatencyMs = float64(time.Since(start).Milliseconds())
+		interaction.Timestamp = time.Now().UTC()
+	}
+	return nil
+}
+
+// interactionInput reconstructs the input message of a recorded interaction.
+func interactionInput(interaction *InteractionRecord) *agenkit.Message {
+	role, _ := interaction.InputMessage["role"].(string)
+	content, _ := interaction.InputMessage["content"].(string)
+	return &agenkit.Message{
+		Role:     role,
+		Content:  content,
+		Metadata: getMapOrEmpty(interaction.InputMessage, "metadata"),
+	}
+}
+
+// tokenJaccard returns the Jaccard similarity of the lower-cased word sets
+// of a and b. Two empty strings are identical.
+func tokenJaccard(a, b string) float64 {
+	setA := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(a)) {
+		setA[word] = true
+	}
+	setB := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(b)) {
+		setB[word] = true
+	}
+
+	if len(setA) == 0 && len(setB) == 0 {
+		return 1.0
+	}
+
+	intersection := 0
+	for word := range setA {
+		if setB[word] {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	return float64(intersection) / float64(union)
+}
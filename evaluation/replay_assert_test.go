@@ -0,0 +1,163 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// upperAgent returns its input in upper case.
+type upperAgent struct{}
+
+func (a *upperAgent) Name() string           { return "upper" }
+func (a *upperAgent) Capabilities() []string { return nil }
+func (a *upperAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+func (a *upperAgent) Process(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+	if msg.ContentString() == "fail" {
+		return nil, errors.New("boom")
+	}
+	return agenkit.NewMessage("agent", strings.ToUpper(msg.ContentString())), nil
+}
+
+// fakeT records assertion calls made by AssertReplayMatches.
+type fakeT struct {
+	errors []string
+	fatals []string
+	logs   []string
+}
+
+func (f *fakeT) Helper() {}
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+func (f *fakeT) Fatalf(format string, args ...any) {
+	f.fatals = append(f.fatals, fmt.Sprintf(format, args...))
+}
+func (f *fakeT) Logf(format string, args ...any) {
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+// recordSession records echoAgent's responses to inputs.
+func recordSession(t *testing.T, storage RecordingStorage, inputs ...string) *SessionRecording {
+	t.Helper()
+	recorder := NewSessionRecorder(storage)
+	for _, input := range inputs {
+		recorder.RecordInteraction("golden", agenkit.NewMessage("user", input), agenkit.NewMessage("agent", input), 1, nil)
+	}
+	recording, err := recorder.FinalizeSession("golden")
+	if err != nil {
+		t.Fatalf("FinalizeSession failed: %v", err)
+	}
+	return recording
+}
+
+func TestCompareToRecording_Matches(t *testing.T) {
+	recording := recordSession(t, NewMemoryRecordingStorage(), "hello", "world")
+
+	mismatches, err := CompareToRecording(context.Background(), recording, &echoAgent{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("expected no mismatches, got %v", mismatches)
+	}
+}
+
+func TestCompareToRecording_Mismatches(t *testing.T) {
+	recording := recordSession(t, NewMemoryRecordingStorage(), "hello", "fail")
+
+	mismatches, err := CompareToRecording(context.Background(), recording, &upperAgent{}, ExactOutputMatch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mismatches) != 2 {
+		t.Fatalf("expected 2 mismatches, got %d", len(mismatches))
+	}
+	if mismatches[0].Expected != "hello" || mismatches[0].Actual != "HELLO" {
+		t.Errorf("unexpected mismatch: %+v", mismatches[0])
+	}
+	if mismatches[1].Error != "boom" {
+		t.Errorf("expected replay error, got %+v", mismatches[1])
+	}
+
+	// A tolerant matcher accepts the case change
+	mismatches, _ = CompareToRecording(context.Background(), recording, &upperAgent{}, NormalizedOutputMatch)
+	if len(mismatches) != 1 || mismatches[0].Index != 1 {
+		t.Errorf("expected only the failing interaction, got %v", mismatches)
+	}
+}
+
+func TestOutputMatchers(t *testing.T) {
+	if !NormalizedOutputMatch("Hello  World\n", "hello world") {
+		t.Error("expected normalized match")
+	}
+	if NormalizedOutputMatch("hello", "goodbye") {
+		t.Error("expected normalized mismatch")
+	}
+
+	similar := TokenSimilarityMatch(0.5)
+	if !similar("the capital of France is Paris", "Paris is the capital of France") {
+		t.Error("expected reordered words to match")
+	}
+	if similar("the capital is Paris", "I do not know") {
+		t.Error("expected unrelated answers not to match")
+	}
+}
+
+func TestAssertReplayMatches(t *testing.T) {
+	t.Setenv(UpdateRecordingsEnv, "")
+	recording := recordSession(t, NewMemoryRecordingStorage(), "hello", "world")
+
+	pass := &fakeT{}
+	AssertReplayMatches(pass, recording, &echoAgent{}, nil)
+	if len(pass.errors) != 0 || len(pass.fatals) != 0 {
+		t.Errorf("expected pass, got errors=%v fatals=%v", pass.errors, pass.fatals)
+	}
+
+	fail := &fakeT{}
+	AssertReplayMatches(fail, recording, &upperAgent{}, nil)
+	if len(fail.errors) != 2 {
+		t.Errorf("expected 2 errors, got %v", fail.errors)
+	}
+}
+
+func TestAssertReplayMatches_UpdateRecordings(t *testing.T) {
+	t.Setenv(UpdateRecordingsEnv, "1")
+	storage := NewMemoryRecordingStorage()
+	recording := recordSession(t, storage, "hello")
+
+	ft := &fakeT{}
+	AssertReplayMatches(ft, recording, &upperAgent{}, &ReplayAssertOptions{Storage: storage})
+	if len(ft.errors) != 0 || len(ft.fatals) != 0 {
+		t.Fatalf("expected update to succeed, got errors=%v fatals=%v", ft.errors, ft.fatals)
+	}
+
+	saved, err := storage.LoadRecording("golden")
+	if err != nil {
+		t.Fatalf("LoadRecording failed: %v", err)
+	}
+	if got := saved.Interactions[0].OutputMessage["content"]; got != "HELLO" {
+		t.Errorf("expected re-recorded output HELLO, got %v", got)
+	}
+
+	// Without storage, update mode is a fatal misconfiguration
+	noStorage := &fakeT{}
+	AssertReplayMatches(noStorage, recording, &upperAgent{}, nil)
+	if len(noStorage.fatals) != 1 {
+		t.Errorf("expected fatal without storage, got %v", noStorage.fatals)
+	}
+
+	// Once updated, the assertion passes in normal mode
+	t.Setenv(UpdateRecordingsEnv, "")
+	verify := &fakeT{}
+	AssertReplayMatches(verify, saved, &upperAgent{}, nil)
+	if len(verify.errors) != 0 {
+		t.Errorf("expected updated recording to match, got %v", verify.errors)
+	}
+}
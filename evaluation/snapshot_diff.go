@@ -0,0 +1,314 @@
+// Package evaluation provides comparison of statistics snapshots.
+//
+// Diffs every metric between two snapshots, with percent change and
+// significance.
+package evaluation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// Session-level metrics that Snapshot derives from every result, alongside
+// the measured metrics.
+const (
+	// SnapshotMetricSuccess is 1 for a completed session and 0 otherwise,
+	// so its mean is the success rate
+	SnapshotMetricSuccess = "session_success"
+	// SnapshotMetricDuration is the session duration in seconds (ended
+	// sessions only)
+	SnapshotMetricDuration = "session_duration"
+	// SnapshotMetricErrors is the number of errors a session recorded
+	SnapshotMetricErrors = "session_errors"
+)
+
+// MetricSummary aggregates one metric's values within a snapshot.
+type MetricSummary struct {
+	// Count is the number of values
+	Count int `json:"count"`
+	// Mean is the average value
+	Mean float64 `json:"mean"`
+	// StdDev is the sample standard deviation (0 for fewer than 2 values)
+	StdDev float64 `json:"std_dev"`
+	// Min is the smallest value
+	Min float64 `json:"min"`
+	// Max is the largest value
+	Max float64 `json:"max"`
+	// Values are the raw values, kept for significance testing
+	Values []float64 `json:"values,omitempty"`
+}
+
+// summarize computes a MetricSummary over values.
+func summarize(values []float64) MetricSummary {
+	summary := MetricSummary{Count: len(values), Values: values}
+	if len(values) == 0 {
+		return summary
+	}
+	summary.Mean = stat.Mean(values, nil)
+	if len(values) > 1 {
+		summary.StdDev = stat.StdDev(values, nil)
+	}
+	summary.Min, summary.Max = values[0], values[0]
+	for _, v := range values {
+		summary.Min = math.Min(summary.Min, v)
+		summary.Max = math.Max(summary.Max, v)
+	}
+	return summary
+}
+
+// StatisticsSnapshot is a point-in-time copy of a MetricsCollector's
+// aggregated statistics, for comparing two periods with DiffSnapshots.
+type StatisticsSnapshot struct {
+	// TakenAt is when the snapshot was taken
+	TakenAt time.Time `json:"taken_at"`
+	// SessionCount is the number of results covered
+	SessionCount int `json:"session_count"`
+	// Metrics summarizes every measured metric by name, plus the
+	// SnapshotMetric* session-level metrics
+	Metrics map[string]MetricSummary `json:"metrics"`
+}
+
+// Snapshot captures the collector's current statistics: a summary of every
+// measured metric and of the SnapshotMetric* session-level metrics. For
+// windowed or bounded collectors, only results still retained are included.
+// Thread-safe for concurrent access.
+//
+// Example:
+//
+//	before := collector.Snapshot()
+//	collector.Clear()
+//	// ... deploy, collect new results ...
+//	fmt.Println(evaluation.DiffSnapshots(before, collector.Snapshot()))
+func (mc *MetricsCollector) Snapshot() *StatisticsSnapshot {
+	mc.evict()
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	values := make(map[string][]float64)
+	for _, result := range mc.results {
+		success := 0.0
+		if result.Status == SessionStatusCompleted {
+			success = 1.0
+		}
+		values[SnapshotMetricSuccess] = append(values[SnapshotMetricSuccess], success)
+		if duration := result.DurationSeconds(); duration != nil {
+			values[SnapshotMetricDuration] = append(values[SnapshotMetricDuration], *duration)
+		}
+		values[SnapshotMetricErrors] = append(values[SnapshotMetricErrors], float64(len(result.Errors)))
+
+		for _, measurement := range result.Measurements {
+			values[measurement.Name] = append(values[measurement.Name], measurement.Value)
+		}
+	}
+
+	snapshot := &StatisticsSnapshot{
+		TakenAt:      mc.currentTime(),
+		SessionCount: len(mc.results),
+		Metrics:      make(map[string]MetricSummary, len(values)),
+	}
+	for name, vs := range values {
+		snapshot.Metrics[name] = summarize(vs)
+	}
+	return snapshot
+}
+
+// MetricDiffStatus describes how a metric differs between two snapshots.
+type MetricDiffStatus string
+
+const (
+	// MetricDiffAdded means the metric only appears in the after snapshot
+	MetricDiffAdded MetricDiffStatus = "added"
+	// MetricDiffRemoved means the metric only appears in the before snapshot
+	MetricDiffRemoved MetricDiffStatus = "removed"
+	// MetricDiffChanged means the metric's mean changed
+	MetricDiffChanged MetricDiffStatus = "changed"
+	// MetricDiffUnchanged means the metric's mean is the same
+	MetricDiffUnchanged MetricDiffStatus = "unchanged"
+)
+
+// MetricDiff compares one metric across two snapshots.
+type MetricDiff struct {
+	// Name of the metric
+	Name string `json:"name"`
+	// Status is how the metric differs
+	Status MetricDiffStatus `json:"status"`
+	// Before and After summarize the metric in each snapshot (Count 0 if
+	// absent)
+	Before MetricSummary `json:"before"`
+	After  MetricSummary `json:"after"`
+	// Delta is the change in mean (after - before)
+	Delta float64 `json:"delta"`
+	// PercentChange is Delta relative to the before mean, in percent (nil
+	// if the before mean is 0 or the metric was added or removed)
+	PercentChange *float64 `json:"percent_change,omitempty"`
+	// PValue is Welch's t-test p-value for the difference in means (nil
+	// unless both snapshots have at least 2 values)
+	PValue *float64 `json:"p_value,omitempty"`
+	// Significant reports whether PValue is below the diff's Alpha
+	Significant bool `json:"significant"`
+}
+
+// SnapshotDiff compares every metric across two snapshots.
+type SnapshotDiff struct {
+	// Before and After are the compared snapshots
+	Before *StatisticsSnapshot `json:"-"`
+	After  *StatisticsSnapshot `json:"-"`
+	// Alpha is the significance level for MetricDiff.Significant
+	Alpha SignificanceLevel `json:"alpha"`
+	// Metrics compares each metric in either snapshot, sorted by name
+	Metrics []MetricDiff `json:"metrics"`
+}
+
+// DiffSnapshots compares every metric in before and after: the change in
+// mean, the percent change and, where both snapshots have at least two
+// values, whether the change is significant at the 95% level.
+//
+// Args:
+//
+//	before: Snapshot of the earlier period (e.g. before a deploy)
+//	after: Snapshot of the later period
+//
+// Returns:
+//
+//	Diff of every metric; print it for a readable report
+func DiffSnapshots(before, after *StatisticsSnapshot) *SnapshotDiff {
+	if before == nil {
+		before = &StatisticsSnapshot{}
+	}
+	if after == nil {
+		after = &StatisticsSnapshot{}
+	}
+	diff := &SnapshotDiff{Before: before, After: after, Alpha: SignificanceLevel005}
+
+	names := make(map[string]bool)
+	for name := range before.Metrics {
+		names[name] = true
+	}
+	for name := range after.Metrics {
+		names[name] = true
+	}
+
+	for name := range names {
+		b, inBefore := before.Metrics[name]
+		a, inAfter := after.Metrics[name]
+		metric := MetricDiff{Name: name, Before: b, After: a}
+
+		switch {
+		case !inBefore:
+			metric.Status = MetricDiffAdded
+		case !inAfter:
+			metric.Status = MetricDiffRemoved
+		default:
+			metric.Delta = a.Mean - b.Mean
+			metric.Status = MetricDiffUnchanged
+			if metric.Delta != 0 {
+				metric.Status = MetricDiffChanged
+			}
+			if b.Mean != 0 {
+				percent := metric.Delta / math.Abs(b.Mean) * 100
+				metric.PercentChange = &percent
+			}
+			if len(b.Values) >= 2 && len(a.Values) >= 2 {
+				pValue := tTest(b.Values, a.Values)
+				metric.PValue = &pValue
+				metric.Significant = pValue < float64(diff.Alpha)
+			}
+		}
+		diff.Metrics = append(diff.Metrics, metric)
+	}
+
+	sort.Slice(diff.Metrics, func(i, j int) bool {
+		return diff.Metrics[i].Name < diff.Metrics[j].Name
+	})
+	return diff
+}
+
+// Get returns the diff for the named metric, if either snapshot has it.
+func (d *SnapshotDiff) Get(name string) (MetricDiff, bool) {
+	for _, metric := range d.Metrics {
+		if metric.Name == name {
+			return metric, true
+		}
+	}
+	return MetricDiff{}, false
+}
+
+// Significant returns the metrics whose change is statistically
+// significant, sorted by name.
+func (d *SnapshotDiff) Significant() []MetricDiff {
+	significant := make([]MetricDiff, 0)
+	for _, metric := range d.Metrics {
+		if metric.Significant {
+			significant = append(significant, metric)
+		}
+	}
+	return significant
+}
+
+// String renders the diff as a readable table, one metric per line;
+// significant changes are marked with "*".
+func (d *SnapshotDiff) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Snapshot diff: %d -> %d sessions\n", d.Before.SessionCount, d.After.SessionCount)
+	fmt.Fprintf(&b, "%-24s %12s %12s %12s %9s %8s\n", "metric", "before", "after", "delta", "change", "p-value")
+
+	for _, metric := range d.Metrics {
+		before, after, delta, change, pValue := "-", "-", "-", "-", "-"
+		if metric.Status != MetricDiffAdded {
+			before = fmt.Sprintf("%.4f", metric.Before.Mean)
+		}
+		if metric.Status != MetricDiffRemoved {
+			after = fmt.Sprintf("%.4f", metric.After.Mean)
+		}
+		if metric.Status == MetricDiffChanged || metric.Status == MetricDiffUnchanged {
+			delta = fmt.Sprintf("%+.4f", metric.Delta)
+		}
+		if metric.PercentChange != nil {
+			change = fmt.Sprintf("%+.1f%%", *metric.PercentChange)
+		}
+		if metric.PValue != nil {
+			pValue = fmt.Sprintf("%.4f", *metric.PValue)
+		}
+		marker := ""
+		if metric.Significant {
+			marker = " *"
+		}
+		fmt.Fprintf(&b, "%-24s %12s %12s %12s %9s %8s%s\n", metric.Name, before, after, delta, change, pValue, marker)
+	}
+	return b.String()
+}
+
+// ToDict converts the diff to a map.
+func (d *SnapshotDiff) ToDict() map[string]interface{} {
+	metrics := make([]map[string]interface{}, len(d.Metrics))
+	for i, metric := range d.Metrics {
+		entry := map[string]interface{}{
+			"name":         metric.Name,
+			"status":       string(metric.Status),
+			"before_mean":  metric.Before.Mean,
+			"after_mean":   metric.After.Mean,
+			"before_count": metric.Before.Count,
+			"after_count":  metric.After.Count,
+			"delta":        metric.Delta,
+			"significant":  metric.Significant,
+		}
+		if metric.PercentChange != nil {
+			entry["percent_change"] = *metric.PercentChange
+		}
+		if metric.PValue != nil {
+			entry["p_value"] = *metric.PValue
+		}
+		metrics[i] = entry
+	}
+	return map[string]interface{}{
+		"before_sessions": d.Before.SessionCount,
+		"after_sessions":  d.After.SessionCount,
+		"alpha":           float64(d.Alpha),
+		"metrics":         metrics,
+	}
+}
@@ -0,0 +1,120 @@
+package evaluation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// addLatencySessions adds completed sessions measuring latency with the
+// given values, failing the first failures of them.
+func addLatencySessions(mc *MetricsCollector, latencies []float64, failures int) {
+	for i, latency := range latencies {
+		result := NewSessionResult(fmt.Sprintf("s%d", i), "agent")
+		result.AddMetricMeasurement(NewMetricMeasurement("latency", latency, MetricTypeDuration))
+		if i < failures {
+			result.AddError("timeout", "too slow", nil)
+			result.SetStatus(SessionStatusFailed)
+		} else {
+			result.SetStatus(SessionStatusCompleted)
+		}
+		mc.AddResult(result)
+	}
+}
+
+func TestMetricsCollector_Snapshot(t *testing.T) {
+	mc := NewMetricsCollector()
+	addLatencySessions(mc, []float64{1, 2, 3, 4}, 1)
+
+	snapshot := mc.Snapshot()
+	if snapshot.SessionCount != 4 {
+		t.Errorf("expected 4 sessions, got %d", snapshot.SessionCount)
+	}
+	latency := snapshot.Metrics["latency"]
+	if latency.Count != 4 || latency.Mean != 2.5 || latency.Min != 1 || latency.Max != 4 {
+		t.Errorf("unexpected latency summary: %+v", latency)
+	}
+	if got := snapshot.Metrics[SnapshotMetricSuccess].Mean; got != 0.75 {
+		t.Errorf("expected success mean 0.75, got %v", got)
+	}
+	if got := snapshot.Metrics[SnapshotMetricErrors].Mean; got != 0.25 {
+		t.Errorf("expected 0.25 errors per session, got %v", got)
+	}
+	if snapshot.Metrics[SnapshotMetricDuration].Count != 4 {
+		t.Error("expected durations for ended sessions")
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	before := NewMetricsCollector()
+	addLatencySessions(before, []float64{1.0, 1.1, 0.9, 1.0, 1.05, 0.95, 1.0, 1.0}, 0)
+	beforeSnapshot := before.Snapshot()
+	beforeSnapshot.Metrics["legacy"] = summarize([]float64{1})
+
+	after := NewMetricsCollector()
+	addLatencySessions(after, []float64{2.0, 2.1, 1.9, 2.0, 2.05, 1.95, 2.0, 2.0}, 0)
+	afterSnapshot := after.Snapshot()
+	afterSnapshot.Metrics["tokens"] = summarize([]float64{100})
+
+	diff := DiffSnapshots(beforeSnapshot, afterSnapshot)
+
+	latency, ok := diff.Get("latency")
+	if !ok {
+		t.Fatal("expected latency diff")
+	}
+	if latency.Status != MetricDiffChanged || latency.Delta != 1.0 {
+		t.Errorf("unexpected latency diff: %+v", latency)
+	}
+	if latency.PercentChange == nil || *latency.PercentChange != 100 {
+		t.Errorf("expected +100%% change, got %v", latency.PercentChange)
+	}
+	if latency.PValue == nil || !latency.Significant {
+		t.Errorf("expected significant latency change, got p=%v", latency.PValue)
+	}
+
+	success, _ := diff.Get(SnapshotMetricSuccess)
+	if success.Status != MetricDiffUnchanged || success.Significant {
+		t.Errorf("expected unchanged success, got %+v", success)
+	}
+	if added, _ := diff.Get("tokens"); added.Status != MetricDiffAdded || added.PValue != nil {
+		t.Errorf("expected tokens added, got %+v", added)
+	}
+	if removed, _ := diff.Get("legacy"); removed.Status != MetricDiffRemoved {
+		t.Errorf("expected legacy removed, got %+v", removed)
+	}
+
+	significant := diff.Significant()
+	if len(significant) != 1 || significant[0].Name != "latency" {
+		t.Errorf("expected only latency significant, got %+v", significant)
+	}
+
+	report := diff.String()
+	for _, want := range []string{"8 -> 8 sessions", "latency", "+100.0%", "*", "tokens"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if dict := diff.ToDict(); len(dict["metrics"].([]map[string]interface{})) != len(diff.Metrics) {
+		t.Error("expected every metric in ToDict")
+	}
+}
+
+func TestDiffSnapshots_SmallSamples(t *testing.T) {
+	before := &StatisticsSnapshot{Metrics: map[string]MetricSummary{"score": summarize([]float64{0})}}
+	after := &StatisticsSnapshot{Metrics: map[string]MetricSummary{"score": summarize([]float64{0.5})}}
+
+	score, _ := DiffSnapshots(before, after).Get("score")
+	if score.PValue != nil || score.Significant {
+		t.Errorf("expected no significance for single samples, got %+v", score)
+	}
+	if score.PercentChange != nil {
+		t.Error("expected no percent change from a zero mean")
+	}
+	if score.Delta != 0.5 {
+		t.Errorf("expected delta 0.5, got %v", score.Delta)
+	}
+
+	if diff := DiffSnapshots(nil, nil); len(diff.Metrics) != 0 {
+		t.Error("expected empty diff for nil snapshots")
+	}
+}
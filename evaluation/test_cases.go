@@ -0,0 +1,113 @@
+package evaluation
+
+import (
+	"math"
+)
+
+// TestCaseOption configures TestCasesFromRecordings.
+type TestCaseOption func(*testCaseOptions)
+
+// testCaseOptions holds the settings applied by TestCaseOptions.
+type testCaseOptions struct {
+	filter   func(*InteractionRecord) bool
+	rate     float64
+	maxCases int
+}
+
+// WithInteractionFilter converts only the interactions keep returns true
+// for, e.g. those of one agent or with a minimum latency.
+func WithInteractionFilter(keep func(*InteractionRecord) bool) TestCaseOption {
+	return func(o *testCaseOptions) {
+		o.filter = keep
+	}
+}
+
+// WithTestCaseSampleRate converts a fraction (0-1) of the interactions.
+// The choice is derived from a hash of each interaction ID, so rebuilding
+// a suite from the same recordings selects the same cases.
+func WithTestCaseSampleRate(rate float64) TestCaseOption {
+	return func(o *testCaseOptions) {
+		o.rate = rate
+	}
+}
+
+// WithMaxTestCases stops after maxCases test cases (0 = no limit).
+func WithMaxTestCases(maxCases int) TestCaseOption {
+	return func(o *testCaseOptions) {
+		o.maxCases = maxCases
+	}
+}
+
+// TestCasesFromRecordings builds a regression suite for Evaluator.Evaluate
+// from recorded production sessions.
+//
+// Each interaction becomes a test case whose "input" is the recorded
+// input content and whose "expected" is the recorded output, so the suite
+// checks that the agent's answers haven't changed. Cases also carry
+// "session_id" and "interaction_id" to trace a failure back to its
+// recording. Interactions that can't be replayed faithfully are skipped:
+// failed attempts, which have no output to expect, and inputs truncated
+// on record.
+//
+// Options narrow the conversion to a subset: a filter, a deterministic
+// sample rate and a cap on the number of cases, applied in that order.
+//
+// Example:
+//
+//	recordings, _ := storage.ListRecordings(100, 0)
+//	testCases := evaluation.TestCasesFromRecordings(recordings,
+//	    evaluation.WithTestCaseSampleRate(0.1),
+//	    evaluation.WithMaxTestCases(500),
+//	)
+//	result, _ := evaluator.Evaluate(testCases, nil)
+func TestCasesFromRecordings(recordings []*SessionRecording, opts ...TestCaseOption) []map[string]interface{} {
+	options := &testCaseOptions{rate: 1}
+	for _, opt := range opts {
+		opt(options)
+	}
+	rate := math.Max(0, math.Min(options.rate, 1))
+
+	testCases := make([]map[string]interface{}, 0)
+	for _, recording := range recordings {
+		if recording == nil {
+			continue
+		}
+		for _, interaction := range recording.Interactions {
+			if options.maxCases > 0 && len(testCases) >= options.maxCases {
+				return testCases
+			}
+			if !replayable(interaction) {
+				continue
+			}
+			if options.filter != nil && !options.filter(interaction) {
+				continue
+			}
+			if !sessionSampled(interaction.InteractionID, rate) {
+				continue
+			}
+
+			input, _ := interaction.InputMessage["content"].(string)
+			expected, _ := interaction.OutputMessage["content"].(string)
+			testCases = append(testCases, map[string]interface{}{
+				"input":          input,
+				"expected":       expected,
+				"session_id":     interaction.SessionID,
+				"interaction_id": interaction.InteractionID,
+			})
+		}
+	}
+	return testCases
+}
+
+// replayable reports whether interaction recorded a successful call whose
+// full input is available.
+func replayable(interaction *InteractionRecord) bool {
+	if interaction == nil || isTruncated(interaction.InputMessage) {
+		return false
+	}
+	if _, failed := interaction.Metadata["error"]; failed {
+		return false
+	}
+	_, ok := interaction.InputMessage["content"].(string)
+	return ok
+}
@@ -0,0 +1,68 @@
+package evaluation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestTestCasesFromRecordings(t *testing.T) {
+	recorder := NewSessionRecorder(NewMemoryRecordingStorage())
+	recorder.SetMaxContentBytes(10)
+	recorder.RecordInteraction("s1", agenkit.NewMessage("user", "hello"), agenkit.NewMessage("agent", "HELLO"), 1, nil)
+	recorder.RecordInteraction("s1", agenkit.NewMessage("user", "a very long input"), agenkit.NewMessage("agent", "ok"), 1, nil)
+	recorder.RecordInteraction("s1", agenkit.NewMessage("user", "flaky"), nil, 1, map[string]interface{}{"error": "timeout"})
+	recorder.RecordInteraction("s1", agenkit.NewMessage("user", "bye"), agenkit.NewMessage("agent", "BYE"), 1, nil)
+	recording, err := recorder.FinalizeSession("s1")
+	if err != nil {
+		t.Fatalf("FinalizeSession failed: %v", err)
+	}
+
+	testCases := TestCasesFromRecordings([]*SessionRecording{recording})
+	if len(testCases) != 2 {
+		t.Fatalf("expected truncated and failed interactions skipped, got %v", testCases)
+	}
+	if testCases[0]["input"] != "hello" || testCases[0]["expected"] != "HELLO" || testCases[0]["session_id"] != "s1" {
+		t.Errorf("unexpected first case %v", testCases[0])
+	}
+	if testCases[1]["input"] != "bye" || testCases[1]["interaction_id"] != recording.Interactions[3].InteractionID {
+		t.Errorf("unexpected second case %v", testCases[1])
+	}
+
+	// The suite replays through the evaluator
+	result, err := NewEvaluator(&upperAgent{}, nil, "").Evaluate(testCases, "")
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if result.TotalTests != 2 || result.PassedTests != 2 {
+		t.Errorf("expected both cases to pass, got %d/%d", result.PassedTests, result.TotalTests)
+	}
+}
+
+func TestTestCasesFromRecordings_Subsets(t *testing.T) {
+	inputs := make([]string, 100)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("q%d", i)
+	}
+	recordings := []*SessionRecording{recordSession(t, nil, inputs...)}
+
+	filtered := TestCasesFromRecordings(recordings, WithInteractionFilter(func(r *InteractionRecord) bool {
+		return r.InputMessage["content"] == "q7"
+	}))
+	if len(filtered) != 1 || filtered[0]["input"] != "q7" {
+		t.Errorf("unexpected filtered cases %v", filtered)
+	}
+
+	sampled := TestCasesFromRecordings(recordings, WithTestCaseSampleRate(0.3))
+	if len(sampled) == 0 || len(sampled) >= 60 {
+		t.Errorf("expected roughly 30 sampled cases, got %d", len(sampled))
+	}
+	if again := TestCasesFromRecordings(recordings, WithTestCaseSampleRate(0.3)); fmt.Sprint(again) != fmt.Sprint(sampled) {
+		t.Error("expected sampling to be deterministic")
+	}
+
+	if capped := TestCasesFromRecordings(recordings, WithMaxTestCases(5)); len(capped) != 5 {
+		t.Errorf("expected 5 cases, got %d", len(capped))
+	}
+}
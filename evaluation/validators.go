@@ -0,0 +1,113 @@
+package evaluation
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Built-in validators for NewAccuracyMetric.
+//
+// Each constructor returns a ValidatorFunc(expected, actual) covering a
+// common answer-scoring need, so callers don't have to hand-roll one:
+//
+//	metric := NewAccuracyMetric(NumericMatch(0.01), false)
+//	score, _ := metric.Measure(agent, input, output, map[string]interface{}{"expected": 4})
+
+// NormalizedMatch returns a validator that compares whole answers after
+// Unicode NFC normalization, case folding and whitespace collapsing, so
+// "Paris", " paris\n" and "PARIS" all match. Unlike the default metric
+// behavior it does not accept answers that merely contain expected.
+func NormalizedMatch() ValidatorFunc {
+	return func(expected, actual string) bool {
+		return normalizeAnswer(expected) == normalizeAnswer(actual)
+	}
+}
+
+// NumericMatch returns a validator that parses expected as a number and
+// accepts actual if any number it contains is within tolerance of it
+// (absolute difference). Thousands separators are allowed, so "1,000"
+// parses as 1000. If expected is not a number, the validator fails.
+//
+// Example: with expected "4", "2+2 equals 4." matches.
+func NumericMatch(tolerance float64) ValidatorFunc {
+	tolerance = math.Abs(tolerance)
+	return func(expected, actual string) bool {
+		want, ok := parseNumber(strings.TrimSpace(expected))
+		if !ok {
+			return false
+		}
+		for _, token := range numberPattern.FindAllString(actual, -1) {
+			if got, ok := parseNumber(token); ok && math.Abs(got-want) <= tolerance {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// EditDistanceMatch returns a validator that accepts actual if its
+// Levenshtein distance from expected, counted in Unicode code points after
+// the same normalization as NormalizedMatch, is at most maxDistance. It
+// tolerates typos such as "Pariss" for "Paris".
+func EditDistanceMatch(maxDistance int) ValidatorFunc {
+	return func(expected, actual string) bool {
+		return levenshtein(normalizeAnswer(expected), normalizeAnswer(actual)) <= maxDistance
+	}
+}
+
+// numberPattern matches decimal numbers with optional sign, thousands
+// separators, fraction and exponent.
+var numberPattern = regexp.MustCompile(`[-+]?(?:\d{1,3}(?:,\d{3})+|\d+)(?:\.\d+)?(?:[eE][-+]?\d+)?|[-+]?\.\d+`)
+
+// parseNumber parses s as a float, ignoring thousands separators.
+func parseNumber(s string) (float64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
+}
+
+// normalizeAnswer applies NFC normalization, lower-cases and collapses
+// runs of whitespace to single spaces.
+func normalizeAnswer(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(norm.NFC.String(s)), " "))
+}
+
+// levenshtein returns the edit distance between a and b in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(min(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
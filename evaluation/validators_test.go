@@ -0,0 +1,124 @@
+package evaluation
+
+import (
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestNormalizedMatch(t *testing.T) {
+	match := NormalizedMatch()
+
+	tests := []struct {
+		expected, actual string
+		want             bool
+	}{
+		{"Paris", "paris", true},
+		{"New York", "  new\tyork\n", true},
+		{"café", "café", true}, // precomposed vs combining accent
+		{"ÉCOLE", "école", true},
+		{"", "", true},
+		{"", "   ", true},
+		{"Paris", "Paris, France", false},
+		{"Paris", "", false},
+		{"東京", "東京", true},
+	}
+
+	for _, tt := range tests {
+		if got := match(tt.expected, tt.actual); got != tt.want {
+			t.Errorf("NormalizedMatch(%q, %q) = %v, want %v", tt.expected, tt.actual, got, tt.want)
+		}
+	}
+}
+
+func TestNumericMatch(t *testing.T) {
+	tests := []struct {
+		tolerance        float64
+		expected, actual string
+		want             bool
+	}{
+		{0, "4", "2+2 equals 4.", true},
+		{0, "4", "The answer is four", false},
+		{0.001, "3.14159", "pi is about 3.14", false},
+		{0.001, "3.14159", "pi is about 3.142", true},
+		{0.5, "10", "roughly 9.6 units", true},
+		{0, "1000", "It costs 1,000 dollars", true},
+		{0, "-5", "the temperature was -5°C", true},
+		{0, "1e3", "1000", true},
+		{-0.1, "2", "2.05", true}, // negative tolerance is treated as absolute
+		{0, "four", "4", false},   // non-numeric expected never matches
+		{0, "", "4", false},
+		{0, "4", "", false},
+		{0, "42", "答えは42です", true},
+	}
+
+	for _, tt := range tests {
+		match := NumericMatch(tt.tolerance)
+		if got := match(tt.expected, tt.actual); got != tt.want {
+			t.Errorf("NumericMatch(%v)(%q, %q) = %v, want %v", tt.tolerance, tt.expected, tt.actual, got, tt.want)
+		}
+	}
+}
+
+func TestEditDistanceMatch(t *testing.T) {
+	tests := []struct {
+		maxDistance      int
+		expected, actual string
+		want             bool
+	}{
+		{0, "Paris", "paris", true},
+		{1, "Paris", "Pariss", true},
+		{1, "Paris", "Parris!", false},
+		{2, "Paris", "Parris!", true},
+		{0, "", "", true},
+		{3, "", "abc", true},
+		{2, "", "abc", false},
+		{1, "naïve", "naive", true}, // one rune substitution, not two bytes
+		{0, "naïve", "naive", false},
+		{1, "東京", "東京都", true},
+	}
+
+	for _, tt := range tests {
+		match := EditDistanceMatch(tt.maxDistance)
+		if got := match(tt.expected, tt.actual); got != tt.want {
+			t.Errorf("EditDistanceMatch(%d)(%q, %q) = %v, want %v", tt.maxDistance, tt.expected, tt.actual, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"abc", "", 3},
+		{"héllo", "hello", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAccuracyMetric_WithBuiltinValidators(t *testing.T) {
+	metric := NewAccuracyMetric(NumericMatch(0), false)
+	output := agenkit.NewMessage("agent", "2+2 equals 4.")
+
+	score, err := metric.Measure(nil, nil, output, map[string]interface{}{"expected": 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if score != 1.0 {
+		t.Errorf("expected 1.0, got %v", score)
+	}
+
+	score, _ = metric.Measure(nil, nil, output, map[string]interface{}{"expected": 5})
+	if score != 0.0 {
+		t.Errorf("expected 0.0, got %v", score)
+	}
+}
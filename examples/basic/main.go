@@ -31,7 +31,7 @@ func (e *EchoAgent) Name() string {
 }
 
 func (e *EchoAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	return agenkit.NewMessage("agent", "Echo: "+message.ContentString()), nil
+	return agenkit.NewMessage("assistant", "Echo: "+message.ContentString()), nil
 }
 
 func (e *EchoAgent) Capabilities() []string {
@@ -47,7 +47,7 @@ func (g *GreetingAgent) Name() string {
 
 func (g *GreetingAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
 	greeting := fmt.Sprintf("Hello, %s! Welcome to agenkit-go.", message.ContentString())
-	return agenkit.NewMessage("agent", greeting), nil
+	return agenkit.NewMessage("assistant", greeting), nil
 }
 
 func (g *GreetingAgent) Capabilities() []string {
@@ -64,7 +64,7 @@ func (a *CodeAgent) Process(ctx context.Context, message *agenkit.Message) (*age
     return fibonacci(n-1) + fibonacci(n-2)
 }`
 
-	return agenkit.NewMessage("agent", fmt.Sprintf("Here's the implementation:\n```go\n%s\n```", code)).
+	return agenkit.NewMessage("assistant", fmt.Sprintf("Here's the implementation:\n```go\n%s\n```", code)).
 		WithMetadata("type", "code").
 		WithMetadata("language", "go"), nil
 }
@@ -94,7 +94,7 @@ Goroutines are lightweight threads managed by Go:
 
 Example: go processData()`
 
-	return agenkit.NewMessage("agent", docs).
+	return agenkit.NewMessage("assistant", docs).
 		WithMetadata("type", "documentation").
 		WithMetadata("format", "markdown"), nil
 }
@@ -114,7 +114,7 @@ func (a *GeneralAgent) Introspect() *agenkit.IntrospectionResult {
 func (a *GeneralAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
 	time.Sleep(100 * time.Millisecond)
 
-	return agenkit.NewMessage("agent", "I'm here to help! I can answer general questions and assist with various tasks.").
+	return agenkit.NewMessage("assistant", "I'm here to help! I can answer general questions and assist with various tasks.").
 		WithMetadata("type", "general"), nil
 }
 
@@ -209,7 +209,7 @@ func (a *SimpleQueryAgent) Process(ctx context.Context, message *agenkit.Message
 		response = "Simple answer to your question."
 	}
 
-	return agenkit.NewMessage("agent", response).
+	return agenkit.NewMessage("assistant", response).
 		WithMetadata("complexity", "simple").
 		WithMetadata("latency", 0.05).
 		WithMetadata("cost", 0.0001), nil
@@ -230,7 +230,7 @@ func (a *ComplexQueryAgent) Introspect() *agenkit.IntrospectionResult {
 func (a *ComplexQueryAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
 	time.Sleep(500 * time.Millisecond)
 
-	return agenkit.NewMessage("agent", "After careful analysis considering multiple perspectives: [detailed response]").
+	return agenkit.NewMessage("assistant", "After careful analysis considering multiple perspectives: [detailed response]").
 		WithMetadata("complexity", "complex").
 		WithMetadata("latency", 0.5).
 		WithMetadata("cost", 0.01), nil
@@ -320,7 +320,7 @@ func (a *PremiumAgent) Introspect() *agenkit.IntrospectionResult {
 func (a *PremiumAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
 	time.Sleep(300 * time.Millisecond)
 
-	return agenkit.NewMessage("agent", "Premium response with advanced analysis and priority support.").
+	return agenkit.NewMessage("assistant", "Premium response with advanced analysis and priority support.").
 		WithMetadata("tier", "premium").
 		WithMetadata("features", []string{"advanced_analysis", "priority", "personalization"}), nil
 }
@@ -340,7 +340,7 @@ func (a *FreeAgent) Introspect() *agenkit.IntrospectionResult {
 func (a *FreeAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
 	time.Sleep(100 * time.Millisecond)
 
-	return agenkit.NewMessage("agent", "Basic response. Upgrade to premium for advanced features!").
+	return agenkit.NewMessage("assistant", "Basic response. Upgrade to premium for advanced features!").
 		WithMetadata("tier", "free").
 		WithMetadata("features", []string{"basic"}), nil
 }
@@ -73,7 +73,7 @@ func (a *PremiumLLM) Process(ctx context.Context, message *agenkit.Message) (*ag
 
 	time.Sleep(300 * time.Millisecond)
 
-	return agenkit.NewMessage("agent", "Premium response: High-quality analysis with detailed reasoning.").
+	return agenkit.NewMessage("assistant", "Premium response: High-quality analysis with detailed reasoning.").
 		WithMetadata("model", "gpt-4").
 		WithMetadata("cost", 0.03).
 		WithMetadata("quality", 0.95), nil
@@ -105,7 +105,7 @@ func (a *StandardLLM) Process(ctx context.Context, message *agenkit.Message) (*a
 
 	time.Sleep(150 * time.Millisecond)
 
-	return agenkit.NewMessage("agent", "Standard response: Good quality answer.").
+	return agenkit.NewMessage("assistant", "Standard response: Good quality answer.").
 		WithMetadata("model", "gpt-3.5-turbo").
 		WithMetadata("cost", 0.002).
 		WithMetadata("quality", 0.80), nil
@@ -127,7 +127,7 @@ func (a *BasicLLM) Process(ctx context.Context, message *agenkit.Message) (*agen
 	// Always succeeds (local model)
 	time.Sleep(50 * time.Millisecond)
 
-	return agenkit.NewMessage("agent", "Basic response: Simple answer.").
+	return agenkit.NewMessage("assistant", "Basic response: Simple answer.").
 		WithMetadata("model", "llama-3-8b").
 		WithMetadata("cost", 0.0).
 		WithMetadata("quality", 0.65), nil
@@ -215,7 +215,7 @@ func (a *RegionalService) Process(ctx context.Context, message *agenkit.Message)
 
 	time.Sleep(100 * time.Millisecond)
 
-	return agenkit.NewMessage("agent", fmt.Sprintf("Processed in %s", a.region)).
+	return agenkit.NewMessage("assistant", fmt.Sprintf("Processed in %s", a.region)).
 		WithMetadata("region", a.region), nil
 }
 
@@ -290,7 +290,7 @@ func (a *HighQualityAgent) Process(ctx context.Context, message *agenkit.Message
 
 	time.Sleep(500 * time.Millisecond)
 
-	return agenkit.NewMessage("agent", "Detailed analysis with citations, reasoning, and examples.").
+	return agenkit.NewMessage("assistant", "Detailed analysis with citations, reasoning, and examples.").
 		WithMetadata("quality", "high").
 		WithMetadata("detail_level", 5), nil
 }
@@ -314,7 +314,7 @@ func (a *MediumQualityAgent) Process(ctx context.Context, message *agenkit.Messa
 
 	time.Sleep(200 * time.Millisecond)
 
-	return agenkit.NewMessage("agent", "Good analysis with key points.").
+	return agenkit.NewMessage("assistant", "Good analysis with key points.").
 		WithMetadata("quality", "medium").
 		WithMetadata("detail_level", 3), nil
 }
@@ -334,7 +334,7 @@ func (a *LowQualityAgent) Process(ctx context.Context, message *agenkit.Message)
 	// Always succeeds
 	time.Sleep(50 * time.Millisecond)
 
-	return agenkit.NewMessage("agent", "Basic summary.").
+	return agenkit.NewMessage("assistant", "Basic summary.").
 		WithMetadata("quality", "low").
 		WithMetadata("detail_level", 1), nil
 }
@@ -101,7 +101,7 @@ func (a *SentimentAgent) Process(ctx context.Context, message *agenkit.Message)
 		sentiment = "neutral"
 	}
 
-	return agenkit.NewMessage("agent", sentiment).
+	return agenkit.NewMessage("assistant", sentiment).
 		WithMetadata("sentiment", sentiment).
 		WithMetadata("score", score).
 		WithMetadata("approach", a.approach), nil
@@ -188,7 +188,7 @@ func (a *SearchAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 	}
 
 	content := fmt.Sprintf("Found %d results from %s", len(results), a.source)
-	return agenkit.NewMessage("agent", content).
+	return agenkit.NewMessage("assistant", content).
 		WithMetadata("source", a.source).
 		WithMetadata("results", results).
 		WithMetadata("count", len(results)), nil
@@ -266,7 +266,7 @@ func (a *LLMAgent) Process(ctx context.Context, message *agenkit.Message) (*agen
 	}
 
 	content := fmt.Sprintf("Response from %s %s", a.model, qualityDesc)
-	return agenkit.NewMessage("agent", content).
+	return agenkit.NewMessage("assistant", content).
 		WithMetadata("model", a.model).
 		WithMetadata("latency", a.latency).
 		WithMetadata("quality", a.quality), nil
@@ -69,7 +69,7 @@ func (a *TranslationAgent) Process(ctx context.Context, message *agenkit.Message
 		lang = "Spanish"
 	}
 
-	return agenkit.NewMessage("agent", text).
+	return agenkit.NewMessage("assistant", text).
 		WithMetadata("source_language", lang).
 		WithMetadata("translated", lang != "English"), nil
 }
@@ -93,7 +93,7 @@ func (a *SummarizationAgent) Process(ctx context.Context, message *agenkit.Messa
 	sentences := strings.Split(text, ".")
 	summary := fmt.Sprintf("Summary: %d sentences", len(sentences))
 
-	result := agenkit.NewMessage("agent", summary)
+	result := agenkit.NewMessage("assistant", summary)
 	result.Metadata["original_length"] = len(text)
 	result.Metadata["summary_length"] = len(summary)
 
@@ -149,7 +149,7 @@ func (a *SentimentAgent) Process(ctx context.Context, message *agenkit.Message)
 		sentiment = "negative"
 	}
 
-	result := agenkit.NewMessage("agent", fmt.Sprintf("%s [Sentiment: %s]", message.ContentString(), sentiment))
+	result := agenkit.NewMessage("assistant", fmt.Sprintf("%s [Sentiment: %s]", message.ContentString(), sentiment))
 	result.Metadata["sentiment"] = sentiment
 
 	// Preserve upstream metadata
@@ -231,12 +231,12 @@ func (a *ValidationAgent) Process(ctx context.Context, message *agenkit.Message)
 	}
 
 	if len(errors) > 0 {
-		return agenkit.NewMessage("agent", fmt.Sprintf("VALIDATION FAILED: %s", strings.Join(errors, "; "))).
+		return agenkit.NewMessage("assistant", fmt.Sprintf("VALIDATION FAILED: %s", strings.Join(errors, "; "))).
 			WithMetadata("valid", false).
 			WithMetadata("errors", errors), nil
 	}
 
-	return agenkit.NewMessage("agent", text).WithMetadata("valid", true), nil
+	return agenkit.NewMessage("assistant", text).WithMetadata("valid", true), nil
 }
 
 // NormalizationAgent normalizes text format
@@ -261,7 +261,7 @@ func (a *NormalizationAgent) Process(ctx context.Context, message *agenkit.Messa
 	text := strings.Join(strings.Fields(message.ContentString()), " ")
 	text = strings.ToLower(text)
 
-	result := agenkit.NewMessage("agent", text)
+	result := agenkit.NewMessage("assistant", text)
 	result.Metadata["normalized"] = true
 
 	// Preserve upstream metadata
@@ -186,7 +186,7 @@ func main() {
 
 	qualityMetric := evaluation.NewQualityMetrics(false, "", nil)
 
-	var totalQualityV1, totalQualityV2 float64
+	var qualityScoresV1, qualityScoresV2 []float64
 	for i := 0; i < len(testCases); i++ {
 		inputMsg := &agenkit.Message{
 			Role:    "user",
@@ -199,7 +199,7 @@ func main() {
 			Content: interactionsV1[i]["replay_output"].(map[string]interface{})["content"].(string),
 		}
 		qualityV1, _ := qualityMetric.Measure(agentV1, inputMsg, outputV1, nil)
-		totalQualityV1 += qualityV1
+		qualityScoresV1 = append(qualityScoresV1, qualityV1)
 
 		// V2 quality
 		outputV2 := &agenkit.Message{
@@ -207,11 +207,19 @@ func main() {
 			Content: interactionsV2[i]["replay_output"].(map[string]interface{})["content"].(string),
 		}
 		qualityV2, _ := qualityMetric.Measure(agentV2, inputMsg, outputV2, nil)
-		totalQualityV2 += qualityV2
+		qualityScoresV2 = append(qualityScoresV2, qualityV2)
 	}
 
-	avgQualityV1 := totalQualityV1 / float64(len(testCases))
-	avgQualityV2 := totalQualityV2 / float64(len(testCases))
+	baseline := evaluation.ComparisonResult{
+		QualityScores: qualityScoresV1,
+		AvgLatencyMs:  resultsV1["total_latency_ms"].(float64) / float64(len(interactionsV1)),
+	}
+	candidate := evaluation.ComparisonResult{
+		QualityScores: qualityScoresV2,
+		AvgLatencyMs:  resultsV2["total_latency_ms"].(float64) / float64(len(interactionsV2)),
+	}
+	avgQualityV1 := baseline.MeanQuality()
+	avgQualityV2 := candidate.MeanQuality()
 
 	fmt.Printf("Average Quality Scores:\n")
 	fmt.Printf("  V1 (Control): %.3f\n", avgQualityV1)
@@ -228,29 +236,24 @@ func main() {
 	fmt.Println("\n\nStep 6: Deployment Recommendation")
 	fmt.Println("----------------------------------")
 
-	shouldDeploy := avgQualityV2 > avgQualityV1
-	latencyIncrease := comparison["latency_diff_percent"].(float64)
+	// Quality and error rate are hard gates; latency and significance
+	// only downgrade the verdict to a conditional deploy
+	criteria := evaluation.DefaultDeploymentCriteria()
+	decision, reasons := evaluation.DeploymentDecision(baseline, candidate, criteria)
 
 	fmt.Println("Analysis:")
-	if shouldDeploy {
-		fmt.Println("  ✓ V2 shows quality improvement")
-	} else {
-		fmt.Println("  ✗ V2 does not show quality improvement")
-	}
-
-	if latencyIncrease < 10 {
-		fmt.Println("  ✓ Latency increase is acceptable (<10%)")
-	} else {
-		fmt.Printf("  ⚠ Latency increased by %.1f%% (review required)\n", latencyIncrease)
+	for _, reason := range reasons {
+		fmt.Printf("  %s\n", reason)
 	}
 
 	fmt.Println("\nRecommendation:")
-	if shouldDeploy && latencyIncrease < 10 {
-		fmt.Println("  🚀 DEPLOY V2 - Shows improvement without significant latency cost")
-	} else if shouldDeploy {
-		fmt.Println("  ⚠ CONDITIONAL DEPLOY - Improvement present but review latency impact")
-	} else {
-		fmt.Println("  ❌ DO NOT DEPLOY - V2 does not show improvement over V1")
+	switch decision {
+	case evaluation.DecisionDeploy:
+		fmt.Println("  🚀 DEPLOY V2 - Passes every deployment criterion")
+	case evaluation.DecisionConditional:
+		fmt.Println("  ⚠ CONDITIONAL DEPLOY - Improvement present but review the failed criteria")
+	default:
+		fmt.Println("  ❌ DO NOT DEPLOY - V2 fails a quality or error-rate criterion")
 	}
 
 	// Summary
@@ -277,7 +280,7 @@ func main() {
 	fmt.Println("3. Test with sufficient sample size (50+ interactions)")
 	fmt.Println("4. Consider multiple metrics, not just one")
 	fmt.Println("5. Set acceptance criteria before testing")
-	fmt.Println("6. Run multiple trials for statistical significance")
+	fmt.Println("6. Run multiple trials for statistical significance (Evaluator.EvaluateMultiTrial)")
 
 	fmt.Println("\nDecision Criteria:")
 	fmt.Println("Deploy if:")
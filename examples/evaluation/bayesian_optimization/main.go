@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"math/rand"
 	"strings"
 
 	"github.com/scttfrdmn/agenkit-go/evaluation"
@@ -62,7 +63,7 @@ func simulateAgentPerformance(config map[string]interface{}) float64 {
 
 	// Add some noise to simulate real-world variability
 	// (In real optimization, noise comes from actual evaluation variability)
-	return score
+	return score + rand.NormFloat64()*2.0
 }
 
 func main() {
@@ -119,6 +120,11 @@ func main() {
 		NInitial:    5,
 		Acquisition: evaluation.AcquisitionEI,
 		Xi:          0.01, // Exploration parameter
+		Patience:    10,   // Stop after 10 iterations without improvement
+		MinDelta:    0.1,
+		// Evaluate each config 3 times so a lucky sample doesn't mislead
+		// the surrogate
+		NEvalsPerConfig: 3,
 	})
 	if err != nil {
 		log.Fatalf("Failed to create optimizer: %v", err)
@@ -136,6 +142,9 @@ func main() {
 
 	fmt.Printf("Duration: %v\n", result.Duration())
 	fmt.Printf("Total Iterations: %d\n", result.NIterations)
+	if result.StoppedEarly {
+		fmt.Printf("Stopped Early: plateaued after %d iterations\n", result.StoppedAt)
+	}
 	fmt.Printf("Best Score: %.2f\n\n", result.BestScore)
 
 	fmt.Println("Best Configuration:")
@@ -147,7 +156,7 @@ func main() {
 	// Show convergence history
 	fmt.Println("Convergence History:")
 	fmt.Println(strings.Repeat("-", 70))
-	fmt.Printf("%-10s %-15s %-15s\n", "Iteration", "Score", "Best So Far")
+	fmt.Printf("%-10s %-15s %-15s %-15s\n", "Iteration", "Score", "Std Dev", "Best So Far")
 	fmt.Println(strings.Repeat("-", 70))
 
 	bestSoFar := result.History[0].Score
@@ -155,7 +164,7 @@ func main() {
 		if step.Score > bestSoFar {
 			bestSoFar = step.Score
 		}
-		fmt.Printf("%-10d %-15.2f %-15.2f", i+1, step.Score, bestSoFar)
+		fmt.Printf("%-10d %-15.2f %-15.2f %-15.2f", i+1, step.Score, math.Sqrt(step.Variance), bestSoFar)
 		if step.Score == result.BestScore {
 			fmt.Print(" ← Best")
 		}
@@ -222,6 +231,37 @@ func main() {
 
 	fmt.Println()
 
+	// Ask/tell loop for asynchronous evaluation
+	fmt.Println("Step 7: Ask/Tell for Asynchronous Evaluation")
+	fmt.Println("---------------------------------------------")
+	fmt.Println("Suggest a batch of configs, evaluate them elsewhere, Observe results as they arrive")
+
+	askTell, err := evaluation.NewBayesianOptimizer(evaluation.BayesianOptimizerConfig{
+		SearchSpace: space,
+		Maximize:    true,
+		NInitial:    5,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create ask/tell optimizer: %v", err)
+	}
+
+	// Warm-start with a known result from an earlier experiment
+	askTell.Observe(result.BestConfig, result.BestScore)
+
+	for batch := 1; batch <= 3; batch++ {
+		pending := make([]map[string]interface{}, 4)
+		for i := range pending {
+			pending[i] = askTell.Suggest()
+		}
+		// In practice these would be submitted as eval jobs
+		for _, config := range pending {
+			askTell.Observe(config, simulateAgentPerformance(config))
+		}
+		_, best := askTell.Best()
+		fmt.Printf("  Batch %d: %d observations, best score %.2f\n", batch, len(askTell.History()), best)
+	}
+	fmt.Println()
+
 	// Summary and best practices
 	fmt.Println(strings.Repeat("=", 70))
 	fmt.Println("Summary: Bayesian Optimization")
@@ -247,7 +287,7 @@ func main() {
 	fmt.Println("1. Start with 3-5 random samples to build initial surrogate model")
 	fmt.Println("2. Use log scale for learning rates (transform externally)")
 	fmt.Println("3. Run multiple trials for robust results")
-	fmt.Println("4. Monitor convergence - stop if no improvement")
+	fmt.Println("4. Set Patience to stop automatically when scores plateau")
 	fmt.Println("5. Consider parameter interactions in objective function")
 
 	fmt.Println("\nReal-World Applications:")
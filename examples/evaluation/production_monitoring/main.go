@@ -9,6 +9,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
 	"math/rand"
 	"strings"
 	"time"
@@ -52,14 +53,38 @@ func main() {
 	fmt.Println("Step 1: Initializing Monitoring Infrastructure")
 	fmt.Println("-----------------------------------------------")
 
-	// Create metrics collector (thread-safe for concurrent access)
-	collector := evaluation.NewMetricsCollector()
+	// Create metrics collector over a trailing 5-minute window so dashboards
+	// and alerts reflect recent behavior (thread-safe for concurrent access)
+	collector := evaluation.NewWindowedMetricsCollector(5 * time.Minute)
+
+	// Persist results so history survives restarts: reload what previous
+	// runs stored, and flush new results on shutdown
+	if err := collector.Restore(context.Background(),
+		evaluation.NewFileMetricsStore("./production_metrics/results.jsonl")); err != nil {
+		log.Fatalf("Failed to load metrics: %v", err)
+	}
+	defer func() {
+		if err := collector.Close(); err != nil {
+			log.Printf("Failed to flush metrics: %v", err)
+		}
+	}()
 
 	// Create session recorder with file storage
 	recorder := evaluation.NewSessionRecorder(
 		evaluation.NewFileRecordingStorage("./production_recordings"),
 	)
 
+	// Record 10% of sessions in full, plus every failed interaction
+	sampler, err := evaluation.NewRecordingSampler(&evaluation.RecordingSamplerConfig{
+		Rate:               0.1,
+		AlwaysRecordErrors: true,
+		PerSession:         true,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create sampler: %v", err)
+	}
+	recorder.SetSampler(sampler)
+
 	// Create regression detector with baseline
 	baseline := &evaluation.EvaluationResult{
 		EvaluationID: "baseline",
@@ -75,8 +100,8 @@ func main() {
 
 	detector := evaluation.NewRegressionDetector(nil, baseline)
 
-	fmt.Println("✓ MetricsCollector initialized (thread-safe)")
-	fmt.Println("✓ SessionRecorder configured with file storage")
+	fmt.Println("✓ MetricsCollector initialized (5-minute window, thread-safe, persisted to file)")
+	fmt.Println("✓ SessionRecorder configured with file storage (10% sampled, errors always kept)")
 	fmt.Println("✓ RegressionDetector configured with baseline")
 
 	// Step 2: Wrap agent for automatic monitoring
@@ -157,7 +182,24 @@ func main() {
 	fmt.Printf("  Total Sessions: %d\n", stats["session_count"])
 	fmt.Printf("  Success Rate: %.1f%%\n", stats["success_rate"].(float64)*100)
 	fmt.Printf("  Avg Duration: %.3fs\n", stats["avg_duration"])
-	fmt.Printf("  Total Errors: %d\n\n", stats["total_errors"])
+	fmt.Printf("  Total Errors: %d\n", stats["total_errors"])
+	fmt.Printf("  Error Rate (last minute): %.1f%% (overall %.1f%%)\n\n",
+		collector.ErrorRate(time.Minute)*100, collector.ErrorRate(0)*100)
+
+	if topErrors := collector.TopErrors(3); len(topErrors) > 0 {
+		fmt.Printf("Top Errors:\n")
+		for _, summary := range topErrors {
+			fmt.Printf("  %s: %d in %d sessions (last: %q)\n",
+				summary.Type, summary.Count, summary.Sessions, summary.LastMessage)
+		}
+		fmt.Println()
+	}
+
+	samplingStats := sampler.Stats()
+	fmt.Printf("Recording Sampling:\n")
+	fmt.Printf("  Interactions Seen: %d\n", samplingStats.Seen)
+	fmt.Printf("  Recorded: %d (%d errors)\n", samplingStats.Recorded, samplingStats.ErrorsRecorded)
+	fmt.Printf("  Effective Rate: %.1f%% (configured %.0f%%)\n\n", samplingStats.EffectiveRate()*100, samplingStats.Rate*100)
 
 	qualityStats := collector.GetMetricAggregates("response_quality")
 	if qualityStats["count"].(int) > 0 {
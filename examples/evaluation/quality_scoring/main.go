@@ -244,6 +244,15 @@ func main() {
 	fmt.Println("   - Coherence: Is it well-structured?")
 	fmt.Println("   - Accuracy: Is it factually correct?")
 	fmt.Println("   - Output: 0.0-1.0 weighted score")
+	fmt.Println("   - Weights: NewWeightedQualityMetrics(false, \"\", QualityWeights{Accuracy: 3, Coherence: 1, ...})")
+
+	capitalInput := agenkit.NewMessage("user", "What is the capital of France?")
+	capitalOutput, _ := agent.Process(context.Background(), capitalInput)
+	detailed, _ := qualityMetric.MeasureDetailed(agent, capitalInput, capitalOutput, map[string]interface{}{"expected": "Paris"})
+	fmt.Printf("   - Breakdown for %q: quality %.2f (relevance %.2f, completeness %.2f, coherence %.2f, accuracy %.2f)\n",
+		capitalOutput.ContentString(), detailed.Value,
+		detailed.Metadata["relevance"], detailed.Metadata["completeness"],
+		detailed.Metadata["coherence"], detailed.Metadata["accuracy"])
 
 	fmt.Println("\n3. PrecisionRecallMetric: Classification performance")
 	fmt.Println("   - Precision: Of predicted positives, how many were correct?")
@@ -258,6 +267,15 @@ func main() {
 	fmt.Println("      return strings.Contains(actual, expected)")
 	fmt.Println("  }")
 	fmt.Println("  metric := NewAccuracyMetric(customValidator, false)")
+	fmt.Println("Built-in validators cover the common cases:")
+	fmt.Println("  NormalizedMatch()       - whole answer, ignoring case/whitespace/Unicode form")
+	fmt.Println("  NumericMatch(0.01)      - any number in the answer within tolerance (\"2+2 equals 4.\" vs 4)")
+	fmt.Println("  EditDistanceMatch(2)    - tolerate small typos (\"Pariss\" vs \"Paris\")")
+
+	mathMetric := evaluation.NewAccuracyMetric(evaluation.NumericMatch(0.01), false)
+	mathOutput, _ := agent.Process(context.Background(), agenkit.NewMessage("user", "What is 2+2?"))
+	mathScore, _ := mathMetric.Measure(agent, nil, mathOutput, map[string]interface{}{"expected": 4.0})
+	fmt.Printf("  NumericMatch on %q vs 4.0: %.0f\n", mathOutput.ContentString(), mathScore)
 
 	fmt.Println("\nBest Practices:")
 	fmt.Println("1. Use multiple metrics for comprehensive evaluation")
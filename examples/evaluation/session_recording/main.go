@@ -237,7 +237,8 @@ func main() {
 	fmt.Println("2. Use descriptive session IDs (e.g., user-id-timestamp)")
 	fmt.Println("3. Finalize sessions promptly to free memory")
 	fmt.Println("4. Store recordings in version control as regression tests")
-	fmt.Println("5. Replay after every code change to detect regressions")
+	fmt.Println("5. Replay after every code change with evaluation.AssertReplayMatches")
+	fmt.Println("   (set UPDATE_RECORDINGS=1 to re-record golden outputs)")
 	fmt.Println("6. Use metadata to tag recordings (version, feature, user)")
 
 	fmt.Println("\nReal-World Applications:")
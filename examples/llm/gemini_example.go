@@ -168,7 +168,7 @@ func multiTurnConversation(llmAdapter llm.LLM) {
 	// Build up conversation history
 	messages := []*agenkit.Message{
 		agenkit.NewMessage("user", "My favorite color is blue."),
-		agenkit.NewMessage("agent", "That's nice! Blue is a calming color."),
+		agenkit.NewMessage("assistant", "That's nice! Blue is a calming color."),
 		agenkit.NewMessage("user", "What was my favorite color again?"),
 	}
 
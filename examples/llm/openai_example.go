@@ -73,7 +73,7 @@ func conversationWithHistory(llmAdapter llm.LLM) {
 	messages := []*agenkit.Message{
 		agenkit.NewMessage("system", "You are a helpful math tutor."),
 		agenkit.NewMessage("user", "Can you help me with algebra?"),
-		agenkit.NewMessage("agent", "Of course! I'd be happy to help you with algebra. What specific topic would you like to work on?"),
+		agenkit.NewMessage("assistant", "Of course! I'd be happy to help you with algebra. What specific topic would you like to work on?"),
 		agenkit.NewMessage("user", "How do I solve x + 5 = 10?"),
 	}
 
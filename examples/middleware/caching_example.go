@@ -81,7 +81,7 @@ func (a *SlowAgent) Process(ctx context.Context, message *agenkit.Message) (*age
 	// Simulate expensive operation (e.g., LLM API call, database query)
 	time.Sleep(500 * time.Millisecond)
 
-	response := agenkit.NewMessage("agent", fmt.Sprintf("Processed: %s", message.ContentString()))
+	response := agenkit.NewMessage("assistant", fmt.Sprintf("Processed: %s", message.ContentString()))
 	response.WithMetadata("processing_time", 0.5)
 	return response, nil
 }
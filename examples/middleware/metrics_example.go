@@ -82,7 +82,7 @@ func (a *LLMAgent) Process(ctx context.Context, message *agenkit.Message) (*agen
 		return nil, fmt.Errorf("LLM API Error: Rate limit exceeded (429)")
 	}
 
-	return agenkit.NewMessage("agent", fmt.Sprintf("Response from %s", a.name)).
+	return agenkit.NewMessage("assistant", fmt.Sprintf("Response from %s", a.name)).
 		WithMetadata("model", a.name).
 		WithMetadata("tokens", 150), nil
 }
@@ -59,7 +59,7 @@ func (a *SimpleAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 	)
 
 	return &agenkit.Message{
-		Role:    "agent",
+		Role:    "assistant",
 		Content: responseContent,
 		Metadata: map[string]interface{}{
 			"processed_by": a.name,
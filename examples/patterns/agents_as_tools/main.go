@@ -217,7 +217,7 @@ func (s *SupervisorAgent) Process(ctx context.Context, message *agenkit.Message)
 	}
 
 	// Return the specialist's response
-	response := fmt.Sprintf("[Delegated to %s]\n\n%s", toolName, result.Data)
+	response := fmt.Sprintf("[Delegated to %s]\n\n%s", toolName, result.DataString())
 	return agenkit.NewMessage("assistant", response), nil
 }
 
@@ -316,7 +316,7 @@ func main() {
 		log.Fatalf("Direct tool execution failed: %v", err)
 	}
 
-	fmt.Printf("Direct call to code_expert:\n%s\n", directResult.Data)
+	fmt.Printf("Direct call to code_expert:\n%s\n", directResult.DataString())
 
 	// Summary
 	fmt.Println("\n\n" + strings.Repeat("=", 70))
@@ -307,6 +307,35 @@ func exampleGoalCompletionTimes() error {
 	return nil
 }
 
+// Example 9: Streaming a run
+func exampleStreaming() error {
+	fmt.Println("\n=== Example 9: Streaming a Run ===")
+
+	agent := patterns.NewAutonomousAgent("Continuous monitoring", 1000)
+	agent.AddGoal("Monitor system health", 10)
+	agent.SetWorker(func(ctx context.Context, goal *patterns.Goal) (string, error) {
+		time.Sleep(20 * time.Millisecond)
+		return "all services healthy", nil
+	})
+
+	// Tail the run as it happens; cancelling ends it
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	events, err := agent.RunStream(ctx)
+	if err != nil {
+		return err
+	}
+	for event := range events {
+		if event.Type == patterns.GoalEventProgress {
+			fmt.Printf("  [iteration %d] %s: %s\n", event.Iteration, event.Goal.Description, event.Output)
+		}
+	}
+	fmt.Printf("\nMonitoring ended after %d iterations\n", agent.GetIterationCount())
+
+	return nil
+}
+
 func main() {
 	fmt.Println("Autonomous Agent Pattern Examples")
 	fmt.Println(strings.Repeat("=", 60))
@@ -344,6 +373,10 @@ func main() {
 		log.Fatalf("Example 8 failed: %v", err)
 	}
 
+	if err := exampleStreaming(); err != nil {
+		log.Fatalf("Example 9 failed: %v", err)
+	}
+
 	fmt.Println(strings.Repeat("=", 60))
 	fmt.Println("✓ All examples completed successfully!")
 	fmt.Println("\n💡 Key takeaways:")
@@ -76,7 +76,7 @@ func (e *EditorAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 		}
 	}
 
-	return agenkit.NewMessage("agent", feedback), nil
+	return agenkit.NewMessage("assistant", feedback), nil
 }
 
 // CodeReviewerAgent reviews code
@@ -131,7 +131,7 @@ func (c *CodeReviewerAgent) Process(ctx context.Context, message *agenkit.Messag
 		}
 	}
 
-	return agenkit.NewMessage("agent", review), nil
+	return agenkit.NewMessage("assistant", review), nil
 }
 
 // AnalystAgent provides analysis
@@ -167,7 +167,7 @@ func (a *AnalystAgent) Process(ctx context.Context, message *agenkit.Message) (*
 		analysis = fmt.Sprintf("%s Analysis: All issues addressed. Ready for approval. ✅", a.perspective)
 	}
 
-	return agenkit.NewMessage("agent", analysis), nil
+	return agenkit.NewMessage("assistant", analysis), nil
 }
 
 func main() {
@@ -333,6 +333,8 @@ func main() {
 		MaxRounds:     2,
 		ConsensusFunc: patterns.DefaultConsensusFunc.ExactMatch,
 		MergeFunc:     patterns.DefaultMergeFunc.Last,
+		// Without consensus, show every position instead of just the last
+		BestEffortMergeFunc: patterns.DefaultMergeFunc.BestEffort,
 	})
 	if err != nil {
 		log.Fatalf("Failed to create limited team: %v", err)
@@ -354,6 +356,9 @@ func main() {
 	if rounds, ok := result.Metadata["collaboration_rounds"].(int); ok {
 		fmt.Printf("\nCompleted %d rounds without full consensus\n", rounds)
 	}
+	if fraction, ok := result.Metadata["agreement_fraction"].(float64); ok {
+		fmt.Printf("Agreement: %.0f%%, dissenting: %v\n", fraction*100, result.Metadata["dissenting_agents"])
+	}
 
 	// Example 5: Custom merge strategy
 	fmt.Println("\n" + strings.Repeat("=", 50))
@@ -386,7 +391,7 @@ func main() {
 			summary.WriteString("Decision: NEEDS WORK ⚠️")
 		}
 
-		return agenkit.NewMessage("agent", summary.String())
+		return agenkit.NewMessage("assistant", summary.String())
 	}
 
 	customTeam, err := patterns.NewCollaborativeAgent(&patterns.CollaborativeConfig{
@@ -435,5 +440,5 @@ func (o *OpinionAgent) Introspect() *agenkit.IntrospectionResult {
 }
 
 func (o *OpinionAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	return agenkit.NewMessage("agent", o.opinion), nil
+	return agenkit.NewMessage("assistant", o.opinion), nil
 }
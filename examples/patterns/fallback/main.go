@@ -17,7 +17,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"math/rand"
+	"os"
 	"strings"
 	"time"
 
@@ -54,7 +56,7 @@ func (p *PrimaryAgent) Process(ctx context.Context, message *agenkit.Message) (*
 		return nil, fmt.Errorf("primary service unavailable")
 	}
 
-	result := agenkit.NewMessage("agent", "Response from primary service (high quality)")
+	result := agenkit.NewMessage("assistant", "Response from primary service (high quality)")
 	result.WithMetadata("service", "primary").WithMetadata("quality", "high")
 	fmt.Println("   ✓ Primary service succeeded")
 	return result, nil
@@ -82,7 +84,7 @@ func (b *BackupAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 	fmt.Println("   🟡 Backup service attempting...")
 	time.Sleep(80 * time.Millisecond)
 
-	result := agenkit.NewMessage("agent", "Response from backup service (reliable)")
+	result := agenkit.NewMessage("assistant", "Response from backup service (reliable)")
 	result.WithMetadata("service", "backup").WithMetadata("quality", "medium")
 	fmt.Println("   ✓ Backup service succeeded")
 	return result, nil
@@ -110,7 +112,7 @@ func (c *CacheAgent) Process(ctx context.Context, message *agenkit.Message) (*ag
 	fmt.Println("   🟢 Cache service attempting...")
 	time.Sleep(20 * time.Millisecond)
 
-	result := agenkit.NewMessage("agent", "Response from cache (fast, may be stale)")
+	result := agenkit.NewMessage("assistant", "Response from cache (fast, may be stale)")
 	result.WithMetadata("service", "cache").WithMetadata("quality", "low")
 	fmt.Println("   ✓ Cache service succeeded")
 	return result, nil
@@ -141,7 +143,7 @@ func (u *UnreliableAgent) Process(ctx context.Context, message *agenkit.Message)
 	if rand.Float64() < u.failureRate {
 		return nil, fmt.Errorf("%s failed", u.name)
 	}
-	return agenkit.NewMessage("agent", fmt.Sprintf("Response from %s", u.name)), nil
+	return agenkit.NewMessage("assistant", fmt.Sprintf("Response from %s", u.name)), nil
 }
 
 func main() {
@@ -150,6 +152,9 @@ func main() {
 
 	ctx := context.Background()
 
+	// Show failover events on stdout (patterns are silent by default)
+	patterns.SetLogger(slog.New(patterns.NewConsoleHandler(os.Stdout, nil)))
+
 	// Example 1: Basic fallback with primary/backup
 	fmt.Println("📊 Example 1: Primary/Backup Failover")
 	fmt.Println(strings.Repeat("-", 50))
@@ -276,7 +281,7 @@ func main() {
 		recovery := fmt.Sprintf("Unable to process request: '%s'. Error: %v\n\nSuggestion: Please try again or contact support.",
 			msg.ContentString(), originalError)
 
-		result := agenkit.NewMessage("agent", recovery)
+		result := agenkit.NewMessage("assistant", recovery)
 		result.WithMetadata("recovery_type", "custom")
 		return result, nil
 	}
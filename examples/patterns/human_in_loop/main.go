@@ -61,7 +61,7 @@ func (t *TradingAgent) Process(ctx context.Context, message *agenkit.Message) (*
 		action, amount,
 		map[bool]string{true: "LOW", false: "MEDIUM"}[confidence > 0.85])
 
-	result := agenkit.NewMessage("agent", response)
+	result := agenkit.NewMessage("assistant", response)
 	result.WithMetadata("confidence", confidence).
 		WithMetadata("action", action).
 		WithMetadata("amount", amount)
@@ -110,7 +110,7 @@ func (m *ModerationAgent) Process(ctx context.Context, message *agenkit.Message)
 		action,
 		map[bool]string{true: "LOW", false: "MEDIUM"}[confidence > 0.8])
 
-	result := agenkit.NewMessage("agent", response)
+	result := agenkit.NewMessage("assistant", response)
 	result.WithMetadata("confidence", confidence).
 		WithMetadata("action", action)
 
@@ -153,7 +153,7 @@ func (m *MedicalAgent) Process(ctx context.Context, message *agenkit.Message) (*
 		"Note: This recommendation requires physician approval.",
 		treatment, confidence*100)
 
-	result := agenkit.NewMessage("agent", response)
+	result := agenkit.NewMessage("assistant", response)
 	result.WithMetadata("confidence", confidence).
 		WithMetadata("treatment", treatment)
 
@@ -289,6 +289,12 @@ func main() {
 		if status, ok := result.Metadata["approval_status"].(string); ok {
 			fmt.Printf("  Status: %s\n", status)
 		}
+		if decision, ok := patterns.GetApprovalDecision(result); ok {
+			fmt.Printf("  Tier: %s (threshold %.2f) -> %s\n", decision.Tier, decision.Threshold, decision.Action)
+			if decision.Tier == patterns.ApprovalTierManual {
+				fmt.Println("  → Queued for human review")
+			}
+		}
 		fmt.Println()
 	}
 
@@ -353,7 +359,7 @@ func main() {
 		modified := strings.Replace(original, "BUY", "BUY (reduced amount)", 1)
 		modified = strings.Replace(modified, "$1000", "$500", 1)
 
-		modifiedMsg := agenkit.NewMessage("agent", modified)
+		modifiedMsg := agenkit.NewMessage("assistant", modified)
 		modifiedMsg.Metadata = request.Message.Metadata
 
 		return &patterns.ApprovalResponse{
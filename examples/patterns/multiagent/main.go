@@ -120,6 +120,33 @@ func (e *EditorAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 	return agenkit.NewMessage("assistant", feedback), nil
 }
 
+// SummaryAgent simulates a synthesizer that condenses pipeline stages
+type SummaryAgent struct{}
+
+func (s *SummaryAgent) Name() string {
+	return "Summarizer"
+}
+
+func (s *SummaryAgent) Capabilities() []string {
+	return []string{"summarization"}
+}
+
+func (s *SummaryAgent) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    s.Name(),
+		Capabilities: s.Capabilities(),
+	}
+}
+
+func (s *SummaryAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	stages, _ := message.Metadata["stage_outputs"].([]map[string]interface{})
+
+	summary := fmt.Sprintf("[Summary] Final article drawing on %d stages: researched findings, "+
+		"written up in a technical style and edited for publication.", len(stages))
+
+	return agenkit.NewMessage("assistant", summary), nil
+}
+
 // CriticAgent simulates a critique specialist
 type CriticAgent struct {
 	perspective string
@@ -231,7 +258,8 @@ func exampleResearchTeam() error {
 func exampleContentPipeline() error {
 	fmt.Println("\n=== Example 4: Content Creation Pipeline ===")
 
-	orchestrator := patterns.NewMultiAgentOrchestrator(patterns.StrategySequential)
+	orchestrator := patterns.NewMultiAgentOrchestrator(patterns.StrategySequential).
+		WithSynthesizer(&SummaryAgent{})
 
 	fmt.Println("Stage 1: Research")
 	orchestrator.RegisterAgent("researcher", &ResearchAgent{specialty: "Technical"})
@@ -252,6 +280,10 @@ func exampleContentPipeline() error {
 
 	fmt.Printf("Pipeline output:\n%s\n\n", result.ContentString())
 
+	// Raw stage outputs are kept alongside the synthesis
+	stages, _ := result.Metadata["stage_outputs"].([]map[string]interface{})
+	fmt.Printf("Raw stage outputs preserved: %d\n", len(stages))
+
 	// Show pipeline stages
 	tasks := orchestrator.GetTasks()
 	fmt.Println("Pipeline stages completed:")
@@ -26,30 +26,16 @@ import (
 	"github.com/scttfrdmn/agenkit-go/patterns"
 )
 
-// ValidatorAgent validates input data
-type ValidatorAgent struct{}
-
-func (v *ValidatorAgent) Name() string {
-	return "Validator"
-}
-
-func (v *ValidatorAgent) Capabilities() []string {
-	return []string{"validation"}
-}
-
-func (v *ValidatorAgent) Introspect() *agenkit.IntrospectionResult {
-	return &agenkit.IntrospectionResult{
-		AgentName:    v.Name(),
-		Capabilities: v.Capabilities(),
+// newValidator builds a validator stage that rejects empty or oversized input
+func newValidator() agenkit.Agent {
+	validator, err := patterns.NewValidatorAgent([]patterns.ValidationRule{
+		patterns.NonEmptyContentRule(),
+		patterns.MaxLengthRule(500),
+	})
+	if err != nil {
+		log.Fatal(err)
 	}
-}
-
-func (v *ValidatorAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	content := message.ContentString()
-	fmt.Println("   🔍 Validator: Checking input...")
-	time.Sleep(50 * time.Millisecond)
-	validated := fmt.Sprintf("✓ Validated: %s", content)
-	return agenkit.NewMessage("assistant", validated), nil
+	return validator.WithHaltOnFailure(true)
 }
 
 // ProcessorAgent processes data
@@ -153,7 +139,7 @@ func exampleSequentialPattern() error {
 	fmt.Println("📋 Sequential Pattern: validator → processor → formatter")
 	fmt.Println(strings.Repeat("=", 60))
 
-	validator := &ValidatorAgent{}
+	validator := newValidator()
 	processor := &ProcessorAgent{}
 	formatter := &FormatterAgent{}
 
@@ -184,17 +170,7 @@ func exampleParallelPattern() error {
 	reviewerB := &ReviewerAgent{perspective: "performance", icon: "⚡"}
 	reviewerC := &ReviewerAgent{perspective: "usability", icon: "🎨"}
 
-	// Aggregator that returns first result and stores all in metadata
-	aggregator := func(messages []*agenkit.Message) *agenkit.Message {
-		if len(messages) == 0 {
-			return agenkit.NewMessage("agent", "No results")
-		}
-		result := messages[0]
-		result.WithMetadata("parallel_results", messages)
-		return result
-	}
-
-	parallel, err := patterns.NewParallelPattern([]agenkit.Agent{reviewerA, reviewerB, reviewerC}, aggregator, nil)
+	parallel, err := patterns.NewParallelPattern([]agenkit.Agent{reviewerA, reviewerB, reviewerC}, patterns.DefaultAggregators.First, nil)
 	if err != nil {
 		return err
 	}
@@ -209,14 +185,12 @@ func exampleParallelPattern() error {
 	fmt.Printf("\n✅ Primary Result (first reviewer):\n%s\n", result.ContentString())
 
 	// Show all parallel results from metadata
-	if parallelResults, ok := result.Metadata["parallel_results"].([]interface{}); ok {
+	if parallelResults, ok := result.Metadata[patterns.ParallelResultsKey].([]*agenkit.Message); ok {
 		fmt.Println("\n📊 All Parallel Results:")
-		for i, res := range parallelResults {
-			if msg, ok := res.(*agenkit.Message); ok {
-				fmt.Printf("\n   Result %d:\n", i+1)
-				for _, line := range strings.Split(msg.ContentString(), "\n") {
-					fmt.Printf("      %s\n", line)
-				}
+		for i, msg := range parallelResults {
+			fmt.Printf("\n   Result %d:\n", i+1)
+			for _, line := range strings.Split(msg.ContentString(), "\n") {
+				fmt.Printf("      %s\n", line)
 			}
 		}
 	}
@@ -231,7 +205,7 @@ func exampleComposedPattern() error {
 	fmt.Println(strings.Repeat("=", 60))
 
 	// Stage 1: sequential validation and processing
-	stage1Validator := &ValidatorAgent{}
+	stage1Validator := newValidator()
 	stage1Processor := &ProcessorAgent{}
 	stage1, err := patterns.NewSequentialPattern(
 		[]agenkit.Agent{stage1Validator, stage1Processor},
@@ -246,19 +220,9 @@ func exampleComposedPattern() error {
 	stage2ReviewerB := &ReviewerAgent{perspective: "performance", icon: "⚡"}
 	stage2ReviewerC := &ReviewerAgent{perspective: "usability", icon: "🎨"}
 
-	// Aggregator that returns first result and stores all in metadata
-	aggregator := func(messages []*agenkit.Message) *agenkit.Message {
-		if len(messages) == 0 {
-			return agenkit.NewMessage("agent", "No results")
-		}
-		result := messages[0]
-		result.WithMetadata("parallel_results", messages)
-		return result
-	}
-
 	stage2, err := patterns.NewParallelPattern(
 		[]agenkit.Agent{stage2ReviewerA, stage2ReviewerB, stage2ReviewerC},
-		aggregator,
+		patterns.DefaultAggregators.First,
 		&patterns.ParallelPatternConfig{Name: "stage2_review"},
 	)
 	if err != nil {
@@ -280,12 +244,16 @@ func exampleComposedPattern() error {
 
 	fmt.Println("\n➡️  Input: Feature implementation")
 	message := agenkit.NewMessage("user", "Feature implementation")
-	result, err := composedPipeline.Process(context.Background(), message)
+
+	// Record every agent invocation in the nested pipeline
+	ctx, trace := patterns.WithExecutionTrace(context.Background())
+	result, err := patterns.ProcessTraced(ctx, composedPipeline, message)
 	if err != nil {
 		return err
 	}
 
 	fmt.Printf("\n✅ Final Composed Output:\n%s\n", result.ContentString())
+	fmt.Printf("\n🌳 Execution Trace (%d invocations):\n%s\n", trace.Len(), trace)
 
 	return nil
 }
@@ -297,7 +265,7 @@ func exampleErrorHandling() error {
 	fmt.Println(strings.Repeat("=", 60))
 
 	// Create a simple pipeline
-	validator := &ValidatorAgent{}
+	validator := newValidator()
 	processor := &ProcessorAgent{}
 
 	pipeline, err := patterns.NewSequentialPattern([]agenkit.Agent{validator, processor}, nil)
@@ -314,6 +282,11 @@ func exampleErrorHandling() error {
 	}
 
 	fmt.Printf("\n✅ Success:\n%s\n", result.ContentString())
+
+	fmt.Println("\n➡️  Input: (empty)")
+	if _, err := pipeline.Process(context.Background(), agenkit.NewMessage("user", "")); err != nil {
+		fmt.Printf("\n❌ Halted by validator: %v\n", err)
+	}
 	fmt.Println("\n💡 Note: Errors in any stage will terminate the pipeline")
 
 	return nil
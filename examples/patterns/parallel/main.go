@@ -6,8 +6,8 @@
 //
 // This example shows:
 //   - Running multiple agents concurrently
-//   - Different aggregation strategies (voting, concatenation)
-//   - Handling partial failures in parallel execution
+//   - Different aggregation strategies (voting, concatenation, deduplication)
+//   - Handling partial and total failures in parallel execution
 //   - Observing parallel execution metadata
 //
 // Run with: go run parallel_pattern.go
@@ -15,6 +15,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -55,7 +56,7 @@ func (s *SentimentAgent) Process(ctx context.Context, message *agenkit.Message)
 		sentiment = "negative"
 	}
 
-	result := agenkit.NewMessage("agent", fmt.Sprintf("Sentiment: %s", sentiment))
+	result := agenkit.NewMessage("assistant", fmt.Sprintf("Sentiment: %s", sentiment))
 	result.WithMetadata("analysis_type", "sentiment")
 	return result, nil
 }
@@ -96,7 +97,7 @@ func (e *EntityAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 		entitiesStr = "none"
 	}
 
-	result := agenkit.NewMessage("agent", fmt.Sprintf("Entities: %s", entitiesStr))
+	result := agenkit.NewMessage("assistant", fmt.Sprintf("Entities: %s", entitiesStr))
 	result.WithMetadata("analysis_type", "entities")
 	return result, nil
 }
@@ -132,7 +133,7 @@ func (t *TopicAgent) Process(ctx context.Context, message *agenkit.Message) (*ag
 		topic = "technology"
 	}
 
-	result := agenkit.NewMessage("agent", fmt.Sprintf("Topic: %s", topic))
+	result := agenkit.NewMessage("assistant", fmt.Sprintf("Topic: %s", topic))
 	result.WithMetadata("analysis_type", "topic")
 	return result, nil
 }
@@ -160,7 +161,7 @@ func (c *ClassifierAgent) Introspect() *agenkit.IntrospectionResult {
 
 func (c *ClassifierAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
 	time.Sleep(50 * time.Millisecond) // Simulate processing
-	return agenkit.NewMessage("agent", c.result), nil
+	return agenkit.NewMessage("assistant", c.result), nil
 }
 
 func main() {
@@ -245,6 +246,9 @@ func main() {
 			fmt.Printf("   Votes: %d/%d\n", votes, total)
 		}
 	}
+	if tally, ok := result.Metadata["vote_tally"].(map[string]int); ok {
+		fmt.Printf("   Tally: %v (ties go to the earliest classifier)\n", tally)
+	}
 
 	// Example 3: Handling partial failures
 	fmt.Println("\n" + strings.Repeat("=", 50))
@@ -275,17 +279,35 @@ func main() {
 
 	fmt.Printf("\n📤 Results (from successful agents):\n%s\n", result.ContentString())
 
-	if errors, ok := result.Metadata["errors"].([]map[string]interface{}); ok {
-		fmt.Printf("\n⚠️  Errors encountered: %d\n", len(errors))
-		for _, errInfo := range errors {
-			if agent, ok := errInfo["agent"].(string); ok {
-				if errMsg, ok := errInfo["error"].(string); ok {
-					fmt.Printf("   - %s: %s\n", agent, errMsg)
-				}
-			}
+	if failures := patterns.GetPartialFailures(result); len(failures) > 0 {
+		fmt.Printf("\n⚠️  Errors encountered: %d\n", len(failures))
+		for _, failure := range failures {
+			fmt.Printf("   - %s: %v\n", failure.AgentName, failure.Err)
 		}
 	}
 
+	// When every agent fails, Process returns ErrAllAgentsFailed by default
+	allFailing, err := patterns.NewParallelAgent(
+		[]agenkit.Agent{&FailingAgent{}, &FailingAgent{}},
+		patterns.DefaultAggregators.Concatenate,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create all-failing agent: %v", err)
+	}
+
+	fmt.Println("\nRunning with every agent failing...")
+	if _, err := allFailing.Process(ctx, text); errors.Is(err, patterns.ErrAllAgentsFailed) {
+		fmt.Printf("   ❌ %v\n", err)
+	}
+
+	// Or opt in to letting the aggregator produce a fallback message
+	allFailing.WithAggregateOnAllFailed(true)
+	result, err = allFailing.Process(ctx, text)
+	if err != nil {
+		log.Fatalf("Aggregator fallback failed: %v", err)
+	}
+	fmt.Printf("   ↩️  Aggregator fallback: %s\n", result.ContentString())
+
 	// Example 4: Custom aggregation
 	fmt.Println("\n" + strings.Repeat("=", 50))
 	fmt.Println("\n📊 Example 4: Custom Aggregation")
@@ -301,7 +323,7 @@ func main() {
 			summary.WriteString(fmt.Sprintf("- Analysis %d: %s\n", i+1, msg.ContentString()))
 		}
 
-		return agenkit.NewMessage("agent", summary.String())
+		return agenkit.NewMessage("assistant", summary.String())
 	}
 
 	customAnalyzer, err := patterns.NewParallelAgent(
@@ -319,6 +341,38 @@ func main() {
 
 	fmt.Printf("\n📤 Custom Aggregation Result:\n%s\n", result.ContentString())
 
+	// Example 5: Deduplicating overlapping reviews
+	fmt.Println("\n" + strings.Repeat("=", 50))
+	fmt.Println("\n📊 Example 5: Deduplicating Overlapping Reviews")
+	fmt.Println(strings.Repeat("-", 50))
+
+	reviewers := []agenkit.Agent{
+		&ClassifierAgent{name: "Reviewer1", result: "SQL injection in the login handler"},
+		&ClassifierAgent{name: "Reviewer2", result: "Missing tests for the parser"},
+		&ClassifierAgent{name: "Reviewer3", result: "Possible SQL injection in login handler"},
+		&ClassifierAgent{name: "Reviewer4", result: "sql injection in login handler"},
+		&ClassifierAgent{name: "Reviewer5", result: "missing tests for parser"},
+	}
+
+	// Cluster reviews sharing at least 60% of their words
+	reviewPanel, err := patterns.NewParallelAgent(
+		reviewers,
+		patterns.DefaultAggregators.Dedupe(patterns.WordOverlapSimilarity, 0.6),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create review panel: %v", err)
+	}
+
+	result, err = reviewPanel.Process(ctx, agenkit.NewMessage("user", "Review this pull request"))
+	if err != nil {
+		log.Fatalf("Review failed: %v", err)
+	}
+
+	fmt.Printf("\n📤 Deduplicated Reviews:\n%s\n", result.ContentString())
+	if sizes, ok := result.Metadata["cluster_sizes"].([]int); ok {
+		fmt.Printf("   Cluster sizes: %v (from %d reviews)\n", sizes, len(reviewers))
+	}
+
 	fmt.Println("\n✅ Parallel pattern demo complete!")
 }
 
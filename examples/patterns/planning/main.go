@@ -281,6 +281,10 @@ func exampleDependencies() error {
 	fmt.Println("\nAll steps completed!")
 	fmt.Printf("Final Progress: %.1f%%\n", patterns.GetPlanProgress(plan))
 
+	// Export the graph for docs or dashboards (plan.ToDOT() for Graphviz)
+	fmt.Println("\nMermaid Diagram:")
+	fmt.Println(plan.ToMermaid())
+
 	return nil
 }
 
@@ -53,7 +53,7 @@ func (b *BillingAgent) Process(ctx context.Context, message *agenkit.Message) (*
 		"Please provide your account number for assistance.",
 		message.ContentString())
 
-	return agenkit.NewMessage("agent", response), nil
+	return agenkit.NewMessage("assistant", response), nil
 }
 
 // TechnicalAgent handles technical support
@@ -86,7 +86,7 @@ func (t *TechnicalAgent) Process(ctx context.Context, message *agenkit.Message)
 		"Let's diagnose the problem together.",
 		message.ContentString())
 
-	return agenkit.NewMessage("agent", response), nil
+	return agenkit.NewMessage("assistant", response), nil
 }
 
 // AccountAgent handles account management
@@ -119,7 +119,7 @@ func (a *AccountAgent) Process(ctx context.Context, message *agenkit.Message) (*
 		"How can I assist with your account today?",
 		message.ContentString())
 
-	return agenkit.NewMessage("agent", response), nil
+	return agenkit.NewMessage("assistant", response), nil
 }
 
 // GeneralAgent handles general inquiries
@@ -152,7 +152,7 @@ func (g *GeneralAgent) Process(ctx context.Context, message *agenkit.Message) (*
 		"How may I assist you today?",
 		message.ContentString())
 
-	return agenkit.NewMessage("agent", response), nil
+	return agenkit.NewMessage("assistant", response), nil
 }
 
 // MockLLMAgent simulates an LLM for classification
@@ -355,6 +355,7 @@ func main() {
 	}
 
 	techSubRouter, err := patterns.NewRouterAgent(&patterns.RouterConfig{
+		Name:       "TechRouter",
 		Classifier: patterns.NewSimpleClassifier(&MockLLMAgent{}, techKeywords),
 		Agents: map[string]agenkit.Agent{
 			"software": technical,
@@ -367,6 +368,7 @@ func main() {
 	}
 
 	multiRouter, err := patterns.NewRouterAgent(&patterns.RouterConfig{
+		Name:       "MainRouter",
 		Classifier: keywordClassifier,
 		Agents: map[string]agenkit.Agent{
 			"billing":   billing,
@@ -385,12 +387,17 @@ func main() {
 	fmt.Println("   Level 1: Main router → technical")
 	fmt.Println("   Level 2: Tech sub-router → hardware specialist")
 
-	_, err = multiRouter.Process(ctx, techRequest)
+	result, err = multiRouter.Process(ctx, techRequest)
 	if err != nil {
 		log.Fatalf("Multi-level routing failed: %v", err)
 	}
 
 	fmt.Printf("\n📤 Routed successfully through nested routers\n")
+	if path, ok := result.Metadata["routing_path"].([]map[string]interface{}); ok {
+		for level, decision := range path {
+			fmt.Printf("   Level %d: %v → %v (%v)\n", level+1, decision["router"], decision["category"], decision["agent"])
+		}
+	}
 
 	fmt.Println("\n✅ Router pattern demo complete!")
 }
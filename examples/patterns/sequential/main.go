@@ -17,6 +17,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"os"
 	"strings"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
@@ -60,7 +62,7 @@ func (e *ExtractorAgent) Process(ctx context.Context, message *agenkit.Message)
 	words := strings.Fields(content)
 	extracted.WriteString(fmt.Sprintf("- Word count: %d\n", len(words)))
 
-	result := agenkit.NewMessage("agent", extracted.String())
+	result := agenkit.NewMessage("assistant", extracted.String())
 	result.WithMetadata("stage", "extraction").
 		WithMetadata("original_length", len(content))
 
@@ -105,7 +107,7 @@ func (t *TranslatorAgent) Process(ctx context.Context, message *agenkit.Message)
 
 	translated.WriteString("}\n")
 
-	result := agenkit.NewMessage("agent", translated.String())
+	result := agenkit.NewMessage("assistant", translated.String())
 	result.WithMetadata("stage", "translation").
 		WithMetadata("format", "structured")
 
@@ -154,7 +156,7 @@ func (s *SummarizerAgent) Process(ctx context.Context, message *agenkit.Message)
 	summary.WriteString("translation, and summarization stages. All key information has\n")
 	summary.WriteString("been preserved and transformed into a structured format.\n")
 
-	result := agenkit.NewMessage("agent", summary.String())
+	result := agenkit.NewMessage("assistant", summary.String())
 	result.WithMetadata("stage", "summarization").
 		WithMetadata("final", true)
 
@@ -166,6 +168,9 @@ func main() {
 	fmt.Println("=== Sequential Pattern Demo ===")
 	fmt.Println("Demonstrating document processing pipeline")
 
+	// Show each pipeline stage on stdout (patterns are silent by default)
+	patterns.SetLogger(slog.New(patterns.NewConsoleHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
 	// Create agents for each stage
 	extractor := &ExtractorAgent{}
 	translator := &TranslatorAgent{}
@@ -66,7 +66,7 @@ func Calculate(operation string, a, b int) (int, error) {
 
 	response := fmt.Sprintf("Code Implementation:\n\n```go\n%s\n```\n\nImplemented requested functionality.", code)
 
-	result := agenkit.NewMessage("agent", response)
+	result := agenkit.NewMessage("assistant", response)
 	result.WithMetadata("specialist", "coder").
 		WithMetadata("lines_of_code", 15)
 
@@ -133,7 +133,7 @@ func TestCalculate(t *testing.T) {
 
 	response := fmt.Sprintf("Test Suite:\n\n```go\n%s\n```\n\nAll tests passing ✓", tests)
 
-	result := agenkit.NewMessage("agent", response)
+	result := agenkit.NewMessage("assistant", response)
 	result.WithMetadata("specialist", "tester").
 		WithMetadata("test_count", 3).
 		WithMetadata("coverage", 100)
@@ -181,7 +181,7 @@ Suggestions:
 Overall: Well-structured implementation with solid testing.
 Ready for merge.`
 
-	result := agenkit.NewMessage("agent", review)
+	result := agenkit.NewMessage("assistant", review)
 	result.WithMetadata("specialist", "reviewer").
 		WithMetadata("status", "approved").
 		WithMetadata("issues_found", 0)
@@ -313,7 +313,7 @@ func (s *SoftwarePlannerAgent) Synthesize(ctx context.Context, original *agenkit
 
 	fmt.Println("   ✓ Results synthesized")
 
-	return agenkit.NewMessage("agent", synthesis.String()), nil
+	return agenkit.NewMessage("assistant", synthesis.String()), nil
 }
 
 // MockLLMAgent for basic agent behavior
@@ -15,6 +15,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -175,8 +176,8 @@ func exampleTimeout() error {
 
 	if err != nil {
 		fmt.Printf("Task failed as expected: %v\n", err)
-		if strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "context deadline exceeded") {
-			fmt.Println("✓ Timeout error correctly detected")
+		if errors.Is(err, patterns.ErrDeadlineExceeded) {
+			fmt.Printf("✓ Timeout error correctly detected (failure_cause: %s)\n", patterns.FailureCause(err))
 		}
 	} else {
 		fmt.Println("Task succeeded")
@@ -48,7 +48,7 @@ func (a *SimpleAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 		confidence = 0.9 // High confidence by default
 	}
 
-	result := agenkit.NewMessage("agent", fmt.Sprintf("%s processed: %s", a.name, message.ContentString()))
+	result := agenkit.NewMessage("assistant", fmt.Sprintf("%s processed: %s", a.name, message.ContentString()))
 	result.WithMetadata("confidence", confidence)
 	return result, nil
 }
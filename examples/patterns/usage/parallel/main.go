@@ -49,7 +49,7 @@ func (a *SimpleAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 	}
 	time.Sleep(duration)
 
-	result := agenkit.NewMessage("agent", fmt.Sprintf("%s: %s", a.name, message.ContentString()))
+	result := agenkit.NewMessage("assistant", fmt.Sprintf("%s: %s", a.name, message.ContentString()))
 	result.WithMetadata("processed_by", a.name)
 	result.WithMetadata("duration_ms", duration.Milliseconds())
 	return result, nil
@@ -114,7 +114,7 @@ func main() {
 			}
 		}
 
-		result := agenkit.NewMessage("agent", combined)
+		result := agenkit.NewMessage("assistant", combined)
 		result.WithMetadata("agents", agents)
 		result.WithMetadata("total_processing_ms", totalDuration)
 		return result
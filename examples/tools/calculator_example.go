@@ -253,14 +253,14 @@ func (a *SimpleAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 		if result != nil {
 			errorMsg = result.Error
 		}
-		return agenkit.NewMessage("agent", fmt.Sprintf("Error: %s", errorMsg)), nil
+		return agenkit.NewMessage("assistant", fmt.Sprintf("Error: %s", errorMsg)), nil
 	}
 
 	// Format response
 	data := result.Data.(map[string]interface{})
 	response := fmt.Sprintf("Result: %v", data["result"])
 
-	return agenkit.NewMessage("agent", response).
+	return agenkit.NewMessage("assistant", response).
 		WithMetadata("tool_result", data), nil
 }
 
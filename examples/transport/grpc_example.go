@@ -82,7 +82,7 @@ func (e *EchoAgent) Name() string {
 }
 
 func (e *EchoAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	return agenkit.NewMessage("agent", "Echo: "+message.ContentString()).
+	return agenkit.NewMessage("assistant", "Echo: "+message.ContentString()).
 		WithMetadata("original", message.ContentString()), nil
 }
 
@@ -98,7 +98,7 @@ func (s *StreamingEchoAgent) Name() string {
 }
 
 func (s *StreamingEchoAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	return agenkit.NewMessage("agent", "Echo: "+message.ContentString()), nil
+	return agenkit.NewMessage("assistant", "Echo: "+message.ContentString()), nil
 }
 
 func (s *StreamingEchoAgent) Stream(ctx context.Context, message *agenkit.Message) (<-chan *agenkit.Message, <-chan error) {
@@ -118,7 +118,7 @@ func (s *StreamingEchoAgent) Stream(ctx context.Context, message *agenkit.Messag
 				errorChan <- ctx.Err()
 				return
 			case <-time.After(100 * time.Millisecond): // Simulate processing delay
-				msg := agenkit.NewMessage("agent", word).
+				msg := agenkit.NewMessage("assistant", word).
 					WithMetadata("word_index", i).
 					WithMetadata("total_words", len(words))
 
@@ -160,7 +160,7 @@ func (m *MetadataAgent) Process(ctx context.Context, message *agenkit.Message) (
 		"processed_by":   m.Name(),
 	}
 
-	response := agenkit.NewMessage("agent", "Processed: "+message.ContentString())
+	response := agenkit.NewMessage("assistant", "Processed: "+message.ContentString())
 	for k, v := range responseMetadata {
 		response.WithMetadata(k, v)
 	}
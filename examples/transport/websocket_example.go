@@ -93,7 +93,7 @@ func (c *ChatAgent) Process(ctx context.Context, message *agenkit.Message) (*age
 		response = fmt.Sprintf("You said: '%s'. That's interesting!", message.ContentString())
 	}
 
-	return agenkit.NewMessage("agent", response), nil
+	return agenkit.NewMessage("assistant", response), nil
 }
 
 // Capabilities returns the agent capabilities.
@@ -116,7 +116,7 @@ func (s *StoryAgent) Process(ctx context.Context, message *agenkit.Message) (*ag
 		"They communicated over many transports, " +
 		"but WebSocket was their favorite for real-time chat. " +
 		"The End."
-	return agenkit.NewMessage("agent", story), nil
+	return agenkit.NewMessage("assistant", story), nil
 }
 
 // Stream streams the story one sentence at a time.
@@ -145,7 +145,7 @@ func (s *StoryAgent) Stream(ctx context.Context, message *agenkit.Message) (<-ch
 			// Simulate natural typing delay
 			time.Sleep(300 * time.Millisecond)
 
-			msg := agenkit.NewMessage("agent", part)
+			msg := agenkit.NewMessage("assistant", part)
 			msg.WithMetadata("part", i+1)
 			msg.WithMetadata("total", len(storyParts))
 
@@ -48,6 +48,11 @@ type HierarchyConfig struct {
 	// Increase for longer context windows.
 	WorkingCapacity int
 
+	// WorkingMaxBytes caps the total content size in working memory
+	// (default: 0 = no byte limit). Set it to keep a few large messages,
+	// such as pasted documents, from ballooning the working tier.
+	WorkingMaxBytes int
+
 	// ShortTermCapacity is the max messages in short-term memory (default: 100).
 	// Increase for longer session history.
 	ShortTermCapacity int
@@ -97,6 +102,7 @@ func NewHierarchyMemory(config HierarchyConfig) (*HierarchyMemory, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create working memory: %w", err)
 	}
+	working.WithMaxBytes(config.WorkingMaxBytes)
 
 	shortTerm, err := patterns.NewShortTermMemory(
 		config.ShortTermCapacity,
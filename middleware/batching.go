@@ -108,6 +108,7 @@ func (m *BatchingMetrics) AvgWaitTime() time.Duration {
 
 // batchRequest represents a single request in a batch.
 type batchRequest struct {
+	ctx        context.Context
 	message    *agenkit.Message
 	resultChan chan batchResult
 	enqueuedAt time.Time
@@ -194,6 +195,7 @@ func (d *BatchingDecorator) Metrics() *BatchingMetrics {
 func (d *BatchingDecorator) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
 	// Create batch request with result channel
 	req := &batchRequest{
+		ctx:        ctx,
 		message:    message,
 		resultChan: make(chan batchResult, 1),
 		enqueuedAt: time.Now(),
@@ -281,17 +283,24 @@ func (d *BatchingDecorator) processBatch(batch []*batchRequest) {
 		totalWait += now.Sub(req.enqueuedAt)
 	}
 
-	// Process all requests in parallel using goroutines
+	// Process all requests in parallel using goroutines. Each runs on its
+	// caller's context, so a caller that gives up cancels its own work
+	// without affecting the rest of the batch.
 	var wg sync.WaitGroup
 	results := make([]batchResult, batchSize)
 
 	for i, req := range batch {
+		if err := req.ctx.Err(); err != nil {
+			// The caller is gone; don't start work nobody will read
+			results[i] = batchResult{err: err}
+			continue
+		}
 		wg.Add(1)
 		go func(idx int, request *batchRequest) {
 			defer wg.Done()
 
 			// Process the request
-			msg, err := d.agent.Process(context.Background(), request.message)
+			msg, err := d.agent.Process(request.ctx, request.message)
 			results[idx] = batchResult{message: msg, err: err}
 		}(i, req)
 	}
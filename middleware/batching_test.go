@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"go.uber.org/goleak"
+
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
 
@@ -668,6 +670,64 @@ func TestContextCancellation(t *testing.T) {
 	}
 }
 
+// blockingAgent blocks until its context is cancelled.
+type blockingAgent struct {
+	started chan struct{}
+	stopped chan struct{}
+}
+
+func (a *blockingAgent) Name() string {
+	return "blocking"
+}
+
+func (a *blockingAgent) Capabilities() []string {
+	return []string{}
+}
+
+func (a *blockingAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+
+func (a *blockingAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	close(a.started)
+	<-ctx.Done()
+	close(a.stopped)
+	return nil, ctx.Err()
+}
+
+// TestCancellationStopsBatchedWork tests that cancelling a caller cancels
+// the agent call made on its behalf, and that no goroutines leak
+func TestCancellationStopsBatchedWork(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	agent := &blockingAgent{started: make(chan struct{}), stopped: make(chan struct{})}
+	batchingAgent := NewBatchingDecorator(agent, BatchingConfig{
+		MaxBatchSize: 1,
+		MaxWaitTime:  time.Millisecond,
+		MaxQueueSize: 10,
+	})
+	defer batchingAgent.Shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := batchingAgent.Process(ctx, &agenkit.Message{Role: "user", Content: "test"})
+		done <- err
+	}()
+
+	<-agent.started
+	cancel()
+
+	select {
+	case <-agent.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Agent call was not cancelled with its caller")
+	}
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
 // partialFailAgent fails on messages containing "fail"
 type partialFailAgent struct{}
 
@@ -195,14 +195,18 @@ func WithCacheStore(store CacheStore) func(*CachingConfig) {
 }
 
 // CachingMetrics tracks caching middleware metrics.
+//
+// SharedRequests counts requests that waited for an identical in-flight
+// request instead of calling the agent; they are neither hits nor misses.
 type CachingMetrics struct {
-	mu            sync.RWMutex
-	TotalRequests int64
-	CacheHits     int64
-	CacheMisses   int64
-	Evictions     int64
-	Invalidations int64
-	CurrentSize   int64
+	mu             sync.RWMutex
+	TotalRequests  int64
+	CacheHits      int64
+	CacheMisses    int64
+	SharedRequests int64
+	Evictions      int64
+	Invalidations  int64
+	CurrentSize    int64
 }
 
 // NewCachingMetrics creates a new metrics instance.
@@ -226,6 +230,14 @@ func (m *CachingMetrics) RecordMiss() {
 	m.CacheMisses++
 }
 
+// RecordShared records a request that joined an identical in-flight request.
+func (m *CachingMetrics) RecordShared() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.TotalRequests++
+	m.SharedRequests++
+}
+
 // RecordEviction records a cache eviction.
 func (m *CachingMetrics) RecordEviction() {
 	m.mu.Lock()
@@ -278,6 +290,7 @@ func (m *CachingMetrics) GetStats() map[string]interface{} {
 		"total_requests": m.TotalRequests,
 		"cache_hits":     m.CacheHits,
 		"cache_misses":   m.CacheMisses,
+		"shared":         m.SharedRequests,
 		"hit_rate":       float64(m.CacheHits) / float64(max(m.TotalRequests, 1)),
 		"miss_rate":      float64(m.CacheMisses) / float64(max(m.TotalRequests, 1)),
 		"evictions":      m.Evictions,
@@ -311,6 +324,7 @@ type lruItem struct {
 // - Pluggable cache backends via CacheStore (default: MemoryCacheStore with LRU + TTL)
 // - Cache invalidation (specific entries or entire cache)
 // - Configurable cache keys with custom key generator support
+// - Single-flight: concurrent identical requests share one agent call
 // - Thread-safe operations
 // - Comprehensive metrics (hits, misses, hit rate, evictions, invalidations)
 //
@@ -334,6 +348,19 @@ type CachingDecorator struct {
 	config  CachingConfig
 	metrics *CachingMetrics
 	store   CacheStore
+
+	mu       sync.Mutex
+	inflight map[string]*inflightCall
+}
+
+// inflightCall is an agent call that identical concurrent requests share.
+type inflightCall struct {
+	done     chan struct{}
+	response *agenkit.Message
+	err      error
+	// canceled is set when the computing request's context ended, so
+	// waiters with a live context compute the response themselves
+	canceled bool
 }
 
 // Verify that CachingDecorator implements Agent interface.
@@ -354,9 +381,10 @@ func NewCachingDecorator(agent agenkit.Agent, config CachingConfig) (*CachingDec
 	}
 
 	d := &CachingDecorator{
-		agent:   agent,
-		config:  config,
-		metrics: NewCachingMetrics(),
+		agent:    agent,
+		config:   config,
+		metrics:  NewCachingMetrics(),
+		inflight: make(map[string]*inflightCall),
 	}
 
 	// Use provided store or fall back to an in-process LRU store.
@@ -396,7 +424,12 @@ func (c *CachingDecorator) generateCacheKey(message *agenkit.Message) string {
 	if c.config.KeyGenerator != nil {
 		return c.config.KeyGenerator(message)
 	}
+	return DefaultCacheKey(message)
+}
 
+// DefaultCacheKey returns a SHA256 key over the message role, content and
+// metadata, so messages differing only in ID or timestamp share a key.
+func DefaultCacheKey(message *agenkit.Message) string {
 	keyData := map[string]interface{}{
 		"role":     message.Role,
 		"content":  message.ContentString(),
@@ -414,19 +447,79 @@ func (c *CachingDecorator) generateCacheKey(message *agenkit.Message) string {
 
 // Process implements the Agent interface with caching.
 func (c *CachingDecorator) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	response, _, err := c.ProcessCached(ctx, message)
+	return response, err
+}
+
+// ProcessCached is Process, also reporting whether the response was served
+// without calling the agent, from the cache or an identical in-flight
+// request.
+//
+// If the request being waited on is cancelled while this one's context is
+// still live, this request calls the agent itself. Errors are not cached.
+func (c *CachingDecorator) ProcessCached(ctx context.Context, message *agenkit.Message) (*agenkit.Message, bool, error) {
 	cacheKey := c.generateCacheKey(message)
 
-	if cached, ok := c.store.Get(cacheKey); ok {
-		c.metrics.RecordHit()
-		return cached, nil
+	for {
+		if cached, ok := c.store.Get(cacheKey); ok {
+			c.metrics.RecordHit()
+			return cached, true, nil
+		}
+
+		c.mu.Lock()
+		call, shared := c.inflight[cacheKey]
+		if !shared {
+			call = &inflightCall{done: make(chan struct{})}
+			c.inflight[cacheKey] = call
+			c.mu.Unlock()
+			c.metrics.RecordMiss()
+			response, err := c.compute(ctx, cacheKey, call, message)
+			return response, false, err
+		}
+		c.mu.Unlock()
+		c.metrics.RecordShared()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+		if !call.canceled {
+			return call.response, call.err == nil, call.err
+		}
 	}
-	c.metrics.RecordMiss()
+}
+
+// compute calls the agent for an in-flight call, caching and publishing the
+// result. If the agent panics, waiters get an error and the panic continues
+// in the caller.
+func (c *CachingDecorator) compute(ctx context.Context, cacheKey string, call *inflightCall, message *agenkit.Message) (*agenkit.Message, error) {
+	defer func() {
+		recovered := recover()
+		if recovered != nil {
+			call.response = nil
+			call.err = fmt.Errorf("agent %s panicked: %v", c.agent.Name(), recovered)
+		}
+		c.mu.Lock()
+		delete(c.inflight, cacheKey)
+		c.mu.Unlock()
+		close(call.done)
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
 
 	response, err := c.agent.Process(ctx, message)
+	if err == nil && response == nil {
+		err = fmt.Errorf("agent %s returned no message", c.agent.Name())
+	}
 	if err != nil {
+		call.err = err
+		call.canceled = ctx.Err() != nil
 		return nil, err
 	}
 
+	call.response = response
 	c.store.Set(cacheKey, response, c.config.DefaultTTL)
 	c.metrics.UpdateSize(int64(c.store.Size()))
 
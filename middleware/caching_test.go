@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -61,6 +62,27 @@ func (a *TestAgent) GetCallCount() int {
 	return a.callCount
 }
 
+// funcAgent is an agent whose Process calls fn.
+type funcAgent struct {
+	fn func(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error)
+}
+
+func (a *funcAgent) Name() string {
+	return "func"
+}
+
+func (a *funcAgent) Capabilities() []string {
+	return []string{}
+}
+
+func (a *funcAgent) Introspect() *agenkit.IntrospectionResult {
+	return agenkit.DefaultIntrospectionResult(a)
+}
+
+func (a *funcAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	return a.fn(ctx, message)
+}
+
 // ============================================
 // Configuration Tests
 // ============================================
@@ -686,6 +708,89 @@ func TestConcurrentCacheAccess(t *testing.T) {
 	}
 }
 
+func TestConcurrentIdenticalRequestsShareCall(t *testing.T) {
+	release := make(chan struct{})
+	var calls int32
+	agent := &funcAgent{fn: func(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return agenkit.NewMessage("agent", "done"), nil
+	}}
+	cachedAgent, err := NewCachingDecorator(agent, DefaultCachingConfig())
+	if err != nil {
+		t.Fatalf("Failed to create caching decorator: %v", err)
+	}
+
+	const numRequests = 5
+	var wg sync.WaitGroup
+	wg.Add(numRequests)
+	for i := 0; i < numRequests; i++ {
+		go func() {
+			defer wg.Done()
+			response, err := cachedAgent.Process(context.Background(), agenkit.NewMessage("user", "same"))
+			if err != nil || response.ContentString() != "done" {
+				t.Errorf("Expected shared response, got %v, %v", response, err)
+			}
+		}()
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for cachedAgent.Metrics().GetStats()["shared"].(int64) < numRequests-1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Requests didn't join the in-flight call: %v", cachedAgent.Metrics().GetStats())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected agent call count=1, got %d", calls)
+	}
+	if cachedAgent.Metrics().CacheMisses != 1 {
+		t.Errorf("Expected cache misses=1, got %d", cachedAgent.Metrics().CacheMisses)
+	}
+}
+
+func TestCancelledCallIsRetriedByWaiter(t *testing.T) {
+	started := make(chan struct{}, 2)
+	var calls int32
+	agent := &funcAgent{fn: func(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			started <- struct{}{}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return agenkit.NewMessage("agent", "done"), nil
+	}}
+	cachedAgent, _ := NewCachingDecorator(agent, DefaultCachingConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := cachedAgent.Process(ctx, agenkit.NewMessage("user", "same"))
+		firstErr <- err
+	}()
+	<-started
+
+	waiter := make(chan *agenkit.Message, 1)
+	go func() {
+		response, _ := cachedAgent.Process(context.Background(), agenkit.NewMessage("user", "same"))
+		waiter <- response
+	}()
+	for cachedAgent.Metrics().GetStats()["shared"].(int64) < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-firstErr; err == nil {
+		t.Error("Expected cancelled request to fail")
+	}
+	if response := <-waiter; response == nil || response.ContentString() != "done" {
+		t.Errorf("Expected waiter to compute the response itself, got %v", response)
+	}
+}
+
 // ============================================
 // Edge Cases
 // ============================================
@@ -143,7 +143,8 @@ func (r *RetryDecorator) Process(ctx context.Context, message *agenkit.Message)
 		r.metrics.mu.Unlock()
 
 		// Try the operation
-		response, err := r.agent.Process(ctx, message)
+		start := time.Now()
+		response, err := r.agent.Process(agenkit.WithAttempt(ctx, attempt), message)
 
 		// Success
 		if err == nil {
@@ -179,6 +180,7 @@ func (r *RetryDecorator) Process(ctx context.Context, message *agenkit.Message)
 		r.metrics.mu.Lock()
 		r.metrics.TotalRetries++
 		r.metrics.mu.Unlock()
+		agenkit.NotifyRetry(ctx, attempt, err, time.Since(start))
 
 		// Wait before retrying
 		select {
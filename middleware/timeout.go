@@ -112,6 +112,12 @@ func (e *TimeoutError) Error() string {
 	return fmt.Sprintf("Request to agent '%s' timed out after %v", e.AgentName, e.Timeout)
 }
 
+// Is reports whether target is context.DeadlineExceeded, so callers can
+// treat a middleware timeout like any other deadline.
+func (e *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
 // TimeoutDecorator wraps an agent with timeout protection.
 //
 // The timeout middleware prevents long-running requests from blocking resources
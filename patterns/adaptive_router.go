@@ -0,0 +1,465 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Adaptive router pattern routes messages by keyword scores whose weights
+// are learned from corrections, so misrouted requests improve later routing.
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// RoutingWeights maps each category to per-term weights. A message's score
+// for a category is the sum of the weights of the distinct terms it
+// contains.
+type RoutingWeights map[string]map[string]float64
+
+// clone returns a deep copy of w.
+func (w RoutingWeights) clone() RoutingWeights {
+	c := make(RoutingWeights, len(w))
+	for category, terms := range w {
+		c[category] = make(map[string]float64, len(terms))
+		for term, weight := range terms {
+			c[category][term] = weight
+		}
+	}
+	return c
+}
+
+// RoutingWeightStore persists an AdaptiveRouter's learned weights so
+// learning survives restarts.
+//
+// Implement this to share weights across processes (a database, Redis,
+// etc.). Implementations must be safe for concurrent use.
+type RoutingWeightStore interface {
+	// Load returns the saved weights, or nil if nothing has been saved.
+	Load(ctx context.Context) (RoutingWeights, error)
+	// Save replaces the saved weights.
+	Save(ctx context.Context, weights RoutingWeights) error
+}
+
+// MemoryRoutingWeightStore is an in-process RoutingWeightStore.
+type MemoryRoutingWeightStore struct {
+	mu      sync.Mutex
+	weights RoutingWeights
+}
+
+// NewMemoryRoutingWeightStore creates an empty in-memory weight store.
+func NewMemoryRoutingWeightStore() *MemoryRoutingWeightStore {
+	return &MemoryRoutingWeightStore{}
+}
+
+// Load implements RoutingWeightStore.
+func (s *MemoryRoutingWeightStore) Load(ctx context.Context) (RoutingWeights, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.weights == nil {
+		return nil, nil
+	}
+	return s.weights.clone(), nil
+}
+
+// Save implements RoutingWeightStore.
+func (s *MemoryRoutingWeightStore) Save(ctx context.Context, weights RoutingWeights) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.weights = weights.clone()
+	return nil
+}
+
+// RoutingDecision records one classification made by an AdaptiveRouter.
+type RoutingDecision struct {
+	// MessageID is the ID of the routed message
+	MessageID string
+	// Input is the routed message content
+	Input string
+	// Category is the category the router chose
+	Category string
+	// Corrected is the category given by RecordCorrection, if any
+	Corrected string
+	// Timestamp is when the decision was made
+	Timestamp time.Time
+}
+
+// AdaptiveRouterConfig configures an AdaptiveRouter.
+type AdaptiveRouterConfig struct {
+	// Agents maps categories to specialist agents
+	Agents map[string]agenkit.Agent
+	// Keywords seeds each category's terms with weight 1 (optional)
+	Keywords map[string][]string
+	// DefaultKey routes messages that match no category (optional)
+	DefaultKey string
+	// Store persists learned weights (default: a MemoryRoutingWeightStore).
+	// Saved weights replace the keyword seeds of their categories.
+	Store RoutingWeightStore
+	// LearningRate is how much each correction shifts term weights
+	// (default: 0.5)
+	LearningRate float64
+	// UpdateEvery is how many corrections are batched before weights are
+	// updated and saved (default: 1)
+	UpdateEvery int
+	// MaxDecisions bounds the decision log, oldest dropped first
+	// (default: 1000)
+	MaxDecisions int
+	// Name identifies the router in logs and routing paths
+	// (default: "AdaptiveRouter")
+	Name string
+	// MaxDepth is passed to the underlying RouterAgent
+	MaxDepth int
+	// Logger receives structured routing events (default: package logger)
+	Logger *slog.Logger
+}
+
+// routingCorrection is a correction waiting for the next weight update.
+type routingCorrection struct {
+	terms     []string
+	predicted string
+	correct   string
+}
+
+// AdaptiveRouter is a RouterAgent whose keyword classifier learns from
+// corrected routing decisions.
+//
+// Every decision is logged. When a decision turns out to be wrong (for
+// example from an evaluation run measuring routing accuracy, or a human
+// reviewer), RecordCorrection feeds back the right category: the input's
+// terms gain weight for the correct category and lose it for the one that
+// was wrongly chosen. Corrections are applied in batches of UpdateEvery and
+// the weights saved to the store.
+//
+// Example:
+//
+//	router, err := patterns.NewAdaptiveRouter(&patterns.AdaptiveRouterConfig{
+//	    Agents:   map[string]agenkit.Agent{"billing": billing, "technical": tech},
+//	    Keywords: map[string][]string{"billing": {"invoice", "refund"}, "technical": {"error", "crash"}},
+//	    DefaultKey: "technical",
+//	})
+//	result, _ := router.Process(ctx, msg)
+//	// A reviewer decides msg should have gone to billing:
+//	err = router.RecordCorrection(ctx, msg, "billing")
+type AdaptiveRouter struct {
+	router *RouterAgent
+
+	mu           sync.Mutex
+	weights      RoutingWeights
+	store        RoutingWeightStore
+	defaultKey   string
+	learningRate float64
+	updateEvery  int
+	maxDecisions int
+	decisions    []RoutingDecision
+	pending      []routingCorrection
+}
+
+// NewAdaptiveRouter creates a learning router, loading any weights saved in
+// the store.
+//
+// Returns an error if config is nil, has no agents, names a default or
+// keyword category without an agent, or the store fails to load.
+func NewAdaptiveRouter(config *AdaptiveRouterConfig) (*AdaptiveRouter, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+	for category := range config.Keywords {
+		if _, ok := config.Agents[category]; !ok {
+			return nil, fmt.Errorf("keyword category '%s' not found in agents map", category)
+		}
+	}
+
+	store := config.Store
+	if store == nil {
+		store = NewMemoryRoutingWeightStore()
+	}
+	learningRate := config.LearningRate
+	if learningRate <= 0 {
+		learningRate = 0.5
+	}
+	updateEvery := config.UpdateEvery
+	if updateEvery <= 0 {
+		updateEvery = 1
+	}
+	maxDecisions := config.MaxDecisions
+	if maxDecisions <= 0 {
+		maxDecisions = 1000
+	}
+	name := config.Name
+	if name == "" {
+		name = "AdaptiveRouter"
+	}
+
+	weights := make(RoutingWeights)
+	for category, keywords := range config.Keywords {
+		weights[category] = make(map[string]float64)
+		for _, keyword := range keywords {
+			for _, term := range routingTerms(keyword) {
+				weights[category][term] = 1
+			}
+		}
+	}
+	saved, err := store.Load(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("load routing weights: %w", err)
+	}
+	for category, terms := range saved {
+		weights[category] = terms
+	}
+
+	a := &AdaptiveRouter{
+		weights:      weights,
+		store:        store,
+		defaultKey:   config.DefaultKey,
+		learningRate: learningRate,
+		updateEvery:  updateEvery,
+		maxDecisions: maxDecisions,
+	}
+	router, err := NewRouterAgent(&RouterConfig{
+		Classifier: &adaptiveClassifier{router: a},
+		Agents:     config.Agents,
+		DefaultKey: config.DefaultKey,
+		Name:       name,
+		MaxDepth:   config.MaxDepth,
+		Logger:     config.Logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.router = router
+	return a, nil
+}
+
+// Name returns the router's identifier.
+func (a *AdaptiveRouter) Name() string {
+	return a.router.Name()
+}
+
+// Capabilities returns the combined capabilities of all agents.
+func (a *AdaptiveRouter) Capabilities() []string {
+	return append(a.router.Capabilities(), "adaptive")
+}
+
+// Introspect returns introspection information for the router.
+func (a *AdaptiveRouter) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    a.Name(),
+		Capabilities: a.Capabilities(),
+	}
+}
+
+// Process classifies the message with the learned weights, logs the
+// decision and routes it as RouterAgent does.
+func (a *AdaptiveRouter) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	return a.router.Process(ctx, message)
+}
+
+// RecordCorrection records that input should have been routed to
+// correctCategory. If input was routed by this router (matched by message
+// ID), its logged decision is marked corrected and is the decision learned
+// from; otherwise input is classified again to find what the router would
+// have chosen. Confirmations, where the router chose correctly, are logged
+// but don't change the weights.
+//
+// Returns an error if correctCategory has no agent or saving the updated
+// weights fails.
+func (a *AdaptiveRouter) RecordCorrection(ctx context.Context, input *agenkit.Message, correctCategory string) error {
+	if input == nil {
+		return ErrNilMessage
+	}
+	if _, ok := a.router.agents[correctCategory]; !ok {
+		return fmt.Errorf("unknown category '%s'", correctCategory)
+	}
+
+	a.mu.Lock()
+	predicted, found := "", false
+	if input.ID != "" {
+		for i := len(a.decisions) - 1; i >= 0; i-- {
+			if a.decisions[i].MessageID == input.ID {
+				a.decisions[i].Corrected = correctCategory
+				predicted, found = a.decisions[i].Category, true
+				break
+			}
+		}
+	}
+	terms := routingTerms(input.ContentString())
+	if !found {
+		predicted = a.classifyLocked(terms)
+	}
+	if predicted != correctCategory {
+		a.pending = append(a.pending, routingCorrection{terms: terms, predicted: predicted, correct: correctCategory})
+	}
+	due := len(a.pending) >= a.updateEvery
+	a.mu.Unlock()
+
+	if !due {
+		return nil
+	}
+	return a.Learn(ctx)
+}
+
+// Learn applies pending corrections now, without waiting for UpdateEvery,
+// and saves the weights.
+func (a *AdaptiveRouter) Learn(ctx context.Context) error {
+	a.mu.Lock()
+	if len(a.pending) == 0 {
+		a.mu.Unlock()
+		return nil
+	}
+	for _, correction := range a.pending {
+		a.adjust(correction.correct, correction.terms, a.learningRate)
+		if correction.predicted != "" && correction.predicted != correction.correct {
+			a.adjust(correction.predicted, correction.terms, -a.learningRate)
+		}
+	}
+	a.pending = nil
+	snapshot := a.weights.clone()
+	a.mu.Unlock()
+
+	if err := a.store.Save(ctx, snapshot); err != nil {
+		return fmt.Errorf("save routing weights: %w", err)
+	}
+	return nil
+}
+
+// Decisions returns a copy of the decision log, oldest first.
+func (a *AdaptiveRouter) Decisions() []RoutingDecision {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	decisions := make([]RoutingDecision, len(a.decisions))
+	copy(decisions, a.decisions)
+	return decisions
+}
+
+// Weights returns a copy of the current term weights.
+func (a *AdaptiveRouter) Weights() RoutingWeights {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.weights.clone()
+}
+
+// adjust adds delta to category's weight for each term. Callers hold a.mu.
+func (a *AdaptiveRouter) adjust(category string, terms []string, delta float64) {
+	weights, ok := a.weights[category]
+	if !ok {
+		weights = make(map[string]float64)
+		a.weights[category] = weights
+	}
+	for _, term := range terms {
+		weights[term] += delta
+	}
+}
+
+// classifyLocked returns the category with the highest positive score for
+// terms, ties broken by name, or the default key if none scores above
+// zero. Callers hold a.mu.
+func (a *AdaptiveRouter) classifyLocked(terms []string) string {
+	categories := make([]string, 0, len(a.weights))
+	for category := range a.weights {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	best, bestScore := a.defaultKey, 0.0
+	for _, category := range categories {
+		score := 0.0
+		for _, term := range terms {
+			score += a.weights[category][term]
+		}
+		if score > bestScore {
+			best, bestScore = category, score
+		}
+	}
+	return best
+}
+
+// classify chooses a category for message and logs the decision.
+func (a *AdaptiveRouter) classify(message *agenkit.Message) (string, error) {
+	content := message.ContentString()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	category := a.classifyLocked(routingTerms(content))
+	if category == "" {
+		return "", fmt.Errorf("unable to classify message - no learned terms matched")
+	}
+
+	message.EnsureID()
+	a.decisions = append(a.decisions, RoutingDecision{
+		MessageID: message.ID,
+		Input:     content,
+		Category:  category,
+		Timestamp: time.Now(),
+	})
+	if len(a.decisions) > a.maxDecisions {
+		a.decisions = a.decisions[len(a.decisions)-a.maxDecisions:]
+	}
+	return category, nil
+}
+
+// routingTerms returns the distinct lowercase words in text.
+func routingTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool, len(words))
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// adaptiveClassifier adapts an AdaptiveRouter's weights to ClassifierAgent.
+type adaptiveClassifier struct {
+	router *AdaptiveRouter
+}
+
+// Name returns the classifier's identifier.
+func (c *adaptiveClassifier) Name() string {
+	return "AdaptiveClassifier"
+}
+
+// Capabilities returns the classifier's capabilities.
+func (c *adaptiveClassifier) Capabilities() []string {
+	return []string{"classification", "adaptive-classification"}
+}
+
+// Introspect returns introspection information for the classifier.
+func (c *adaptiveClassifier) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    c.Name(),
+		Capabilities: c.Capabilities(),
+	}
+}
+
+// Process returns the message's category as content.
+func (c *adaptiveClassifier) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+
+	category, err := c.Classify(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	return agenkit.NewMessage(agenkit.RoleAssistant, category), nil
+}
+
+// Classify chooses a category using the learned weights.
+func (c *adaptiveClassifier) Classify(ctx context.Context, message *agenkit.Message) (string, error) {
+	if message == nil {
+		return "", ErrNilMessage
+	}
+	return c.router.classify(message)
+}
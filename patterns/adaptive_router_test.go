@@ -0,0 +1,182 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func newTestAdaptiveRouter(t *testing.T, store RoutingWeightStore) *AdaptiveRouter {
+	t.Helper()
+	router, err := NewAdaptiveRouter(&AdaptiveRouterConfig{
+		Agents: map[string]agenkit.Agent{
+			"billing":   &extendedMockAgent{name: "billing", response: "billing"},
+			"technical": &extendedMockAgent{name: "technical", response: "technical"},
+		},
+		Keywords: map[string][]string{
+			"billing":   {"invoice", "refund"},
+			"technical": {"error", "crash"},
+		},
+		DefaultKey: "technical",
+		Store:      store,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return router
+}
+
+func TestNewAdaptiveRouter_Validation(t *testing.T) {
+	if _, err := NewAdaptiveRouter(nil); err == nil {
+		t.Error("expected error for nil config")
+	}
+	_, err := NewAdaptiveRouter(&AdaptiveRouterConfig{
+		Agents:   map[string]agenkit.Agent{"billing": &extendedMockAgent{name: "billing"}},
+		Keywords: map[string][]string{"shipping": {"parcel"}},
+	})
+	if err == nil {
+		t.Error("expected error for keyword category without an agent")
+	}
+}
+
+func TestAdaptiveRouter_RoutesByKeywordsAndLogsDecisions(t *testing.T) {
+	router := newTestAdaptiveRouter(t, nil)
+
+	result, err := router.Process(context.Background(), agenkit.NewMessage("user", "I need a refund"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["routed_category"] != "billing" {
+		t.Errorf("expected billing, got %v", result.Metadata["routed_category"])
+	}
+
+	result, err = router.Process(context.Background(), agenkit.NewMessage("user", "hello there"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["routed_category"] != "technical" {
+		t.Errorf("expected default technical, got %v", result.Metadata["routed_category"])
+	}
+
+	decisions := router.Decisions()
+	if len(decisions) != 2 {
+		t.Fatalf("expected 2 decisions, got %d", len(decisions))
+	}
+	if decisions[0].Category != "billing" || decisions[0].Input != "I need a refund" {
+		t.Errorf("unexpected decision %+v", decisions[0])
+	}
+}
+
+func TestAdaptiveRouter_LearnsFromCorrections(t *testing.T) {
+	store := NewMemoryRoutingWeightStore()
+	router := newTestAdaptiveRouter(t, store)
+	ctx := context.Background()
+
+	msg := agenkit.NewMessage("user", "I was charged twice")
+	result, err := router.Process(ctx, msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["routed_category"] != "technical" {
+		t.Fatalf("expected default route before learning, got %v", result.Metadata["routed_category"])
+	}
+
+	if err := router.RecordCorrection(ctx, msg, "billing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := router.Decisions()[0].Corrected; got != "billing" {
+		t.Errorf("expected decision marked corrected, got %q", got)
+	}
+
+	result, err = router.Process(ctx, agenkit.NewMessage("user", "charged twice again"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["routed_category"] != "billing" {
+		t.Errorf("expected billing after correction, got %v", result.Metadata["routed_category"])
+	}
+
+	// Learning persists in the store
+	restored := newTestAdaptiveRouter(t, store)
+	result, err = restored.Process(ctx, agenkit.NewMessage("user", "charged twice"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["routed_category"] != "billing" {
+		t.Errorf("expected restored router to use saved weights, got %v", result.Metadata["routed_category"])
+	}
+}
+
+func TestAdaptiveRouter_BatchesCorrections(t *testing.T) {
+	router, err := NewAdaptiveRouter(&AdaptiveRouterConfig{
+		Agents: map[string]agenkit.Agent{
+			"billing":   &extendedMockAgent{name: "billing", response: "billing"},
+			"technical": &extendedMockAgent{name: "technical", response: "technical"},
+		},
+		Keywords:    map[string][]string{"technical": {"error"}},
+		DefaultKey:  "technical",
+		UpdateEvery: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := router.RecordCorrection(ctx, agenkit.NewMessage("user", "invoice error"), "billing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w := router.Weights()["billing"]["invoice"]; w != 0 {
+		t.Errorf("expected no update before UpdateEvery corrections, got weight %v", w)
+	}
+
+	if err := router.Learn(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	weights := router.Weights()
+	if weights["billing"]["invoice"] != 0.5 {
+		t.Errorf("expected billing/invoice weight 0.5, got %v", weights["billing"]["invoice"])
+	}
+	if weights["technical"]["error"] != 0.5 {
+		t.Errorf("expected technical/error weight lowered to 0.5, got %v", weights["technical"]["error"])
+	}
+}
+
+func TestAdaptiveRouter_ConfirmationDoesNotChangeWeights(t *testing.T) {
+	router := newTestAdaptiveRouter(t, nil)
+	before := router.Weights()
+
+	if err := router.RecordCorrection(context.Background(), agenkit.NewMessage("user", "refund please"), "billing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if router.Weights()["billing"]["please"] != before["billing"]["please"] {
+		t.Error("expected confirmation to leave weights unchanged")
+	}
+}
+
+func TestAdaptiveRouter_RecordCorrectionErrors(t *testing.T) {
+	router := newTestAdaptiveRouter(t, &failingWeightStore{})
+	ctx := context.Background()
+
+	if err := router.RecordCorrection(ctx, agenkit.NewMessage("user", "x"), "shipping"); err == nil {
+		t.Error("expected error for unknown category")
+	}
+	err := router.RecordCorrection(ctx, agenkit.NewMessage("user", "charged twice"), "billing")
+	if !errors.Is(err, errSaveFailed) {
+		t.Errorf("expected store error, got %v", err)
+	}
+}
+
+var errSaveFailed = errors.New("save failed")
+
+// failingWeightStore loads nothing and fails every save.
+type failingWeightStore struct{}
+
+func (s *failingWeightStore) Load(ctx context.Context) (RoutingWeights, error) {
+	return nil, nil
+}
+
+func (s *failingWeightStore) Save(ctx context.Context, weights RoutingWeights) error {
+	return errSaveFailed
+}
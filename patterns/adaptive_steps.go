@@ -0,0 +1,117 @@
+package patterns
+
+import "strings"
+
+// ProgressFunc reports whether a loop step made progress, given the step's
+// observation (a tool result, or the reasoning itself for a step without a
+// tool call) and the observations of earlier steps.
+type ProgressFunc func(observation string, previous []string) bool
+
+// NewInformationProgress is the default ProgressFunc: a step makes
+// progress if its observation is non-empty and differs from every earlier
+// one, ignoring case and whitespace.
+func NewInformationProgress(observation string, previous []string) bool {
+	normalized := normalizeObservation(observation)
+	if normalized == "" {
+		return false
+	}
+	for _, earlier := range previous {
+		if normalizeObservation(earlier) == normalized {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeObservation lower-cases s and collapses its whitespace.
+func normalizeObservation(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// AdaptiveSteps makes a loop's step limit adaptive (AdaptiveSteps in
+// ReActConfig and ReasoningWithToolsConfig).
+//
+// The loop's configured step limit becomes a soft budget: past it, the loop
+// keeps going only while each step makes progress (see Progress), up to
+// MaxSteps. The loop also stops early with StopLoopDetected once it repeats
+// an identical action (same tool, same input) RepeatLimit times.
+type AdaptiveSteps struct {
+	// MaxSteps is the hard cap on steps, including extensions (default:
+	// twice the soft budget)
+	MaxSteps int
+	// RepeatLimit is how many identical actions count as a loop (default:
+	// 3; values below 2 mean 2)
+	RepeatLimit int
+	// Progress decides whether a step made progress (default:
+	// NewInformationProgress)
+	Progress ProgressFunc
+}
+
+// stepBudget tracks one run's adaptive step limit and repeated actions.
+// A nil config gives a fixed limit with no loop detection.
+type stepBudget struct {
+	limit        int
+	hardCap      int
+	repeatLimit  int
+	progress     ProgressFunc
+	adaptive     bool
+	actions      map[string]int
+	observations []string
+}
+
+// newStepBudget creates a budget of base steps, adapted by config if set.
+func newStepBudget(base int, config *AdaptiveSteps) *stepBudget {
+	b := &stepBudget{limit: base, hardCap: base}
+	if config == nil {
+		return b
+	}
+
+	b.adaptive = true
+	b.hardCap = config.MaxSteps
+	if b.hardCap <= 0 {
+		b.hardCap = 2 * base
+	}
+	if b.hardCap < base {
+		b.hardCap = base
+	}
+	b.repeatLimit = config.RepeatLimit
+	if b.repeatLimit == 0 {
+		b.repeatLimit = 3
+	}
+	if b.repeatLimit < 2 {
+		b.repeatLimit = 2
+	}
+	b.progress = config.Progress
+	if b.progress == nil {
+		b.progress = NewInformationProgress
+	}
+	b.actions = make(map[string]int)
+	return b
+}
+
+// allows reports whether step (0-based) may run.
+func (b *stepBudget) allows(step int) bool {
+	return step < b.limit
+}
+
+// observe records step's action (empty for a step without a tool call) and
+// observation, extending the limit by one step if it made progress.
+//
+// Returns true if the action has now been repeated RepeatLimit times.
+func (b *stepBudget) observe(step int, action, input, observation string) bool {
+	if !b.adaptive {
+		return false
+	}
+
+	if b.progress(observation, b.observations) && step+1 >= b.limit && b.limit < b.hardCap {
+		b.limit++
+	}
+	b.observations = append(b.observations, observation)
+
+	if action == "" {
+		return false
+	}
+	key := action + "\x00" + strings.TrimSpace(input)
+	b.actions[key]++
+	return b.actions[key] >= b.repeatLimit
+}
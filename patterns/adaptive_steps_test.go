@@ -0,0 +1,175 @@
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// countingTool returns a different result on every call.
+type countingTool struct{ calls int }
+
+func (c *countingTool) Name() string        { return "search" }
+func (c *countingTool) Description() string { return "Search" }
+func (c *countingTool) Execute(ctx context.Context, params map[string]any) (*agenkit.ToolResult, error) {
+	c.calls++
+	return agenkit.NewToolResult(fmt.Sprintf("finding %d", c.calls)), nil
+}
+
+// searchSteps returns n ReAct responses searching distinct queries.
+func searchSteps(n int) []string {
+	responses := make([]string, n)
+	for i := range responses {
+		responses[i] = fmt.Sprintf("Thought: Look further\nAction: search\nAction Input: query %d", i)
+	}
+	return responses
+}
+
+func TestNewInformationProgress(t *testing.T) {
+	if !NewInformationProgress("new fact", []string{"old fact"}) {
+		t.Error("expected a new observation to be progress")
+	}
+	if NewInformationProgress("Old  Fact", []string{"old fact"}) {
+		t.Error("expected a repeated observation, ignoring case and spacing, not to be progress")
+	}
+	if NewInformationProgress("  ", nil) {
+		t.Error("expected an empty observation not to be progress")
+	}
+}
+
+func TestReActAgent_AdaptiveStepsExtendsOnProgress(t *testing.T) {
+	llm := &mockReActAgent{name: "llm", responses: append(searchSteps(3), "Thought: Done\nFinal Answer: 42")}
+	agent, _ := NewReActAgent(&ReActConfig{
+		Agent:         llm,
+		Tools:         []agenkit.Tool{&countingTool{}},
+		MaxSteps:      2,
+		AdaptiveSteps: &AdaptiveSteps{MaxSteps: 6},
+	})
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if GetStopReason(result) != StopCompleted || result.ContentString() != "42" {
+		t.Errorf("expected the extended budget to reach the answer, got %v: %s", GetStopReason(result), result.ContentString())
+	}
+	if result.Metadata["step_budget"] != 4 {
+		t.Errorf("expected the budget extended to 4, got %v", result.Metadata["step_budget"])
+	}
+}
+
+func TestReActAgent_AdaptiveStepsHardCap(t *testing.T) {
+	llm := &mockReActAgent{name: "llm", responses: searchSteps(10)}
+	agent, _ := NewReActAgent(&ReActConfig{
+		Agent:         llm,
+		Tools:         []agenkit.Tool{&countingTool{}},
+		MaxSteps:      2,
+		AdaptiveSteps: &AdaptiveSteps{MaxSteps: 4},
+	})
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if GetStopReason(result) != StopMaxIterations || result.Metadata["steps"] != 4 {
+		t.Errorf("expected to stop at the hard cap of 4, got %v after %v steps", GetStopReason(result), result.Metadata["steps"])
+	}
+}
+
+func TestReActAgent_AdaptiveStepsNoProgress(t *testing.T) {
+	llm := &mockReActAgent{name: "llm", responses: searchSteps(10)}
+	tool := &mockTool{name: "search", description: "Search", response: "same result"}
+	agent, _ := NewReActAgent(&ReActConfig{
+		Agent:         llm,
+		Tools:         []agenkit.Tool{tool},
+		MaxSteps:      2,
+		AdaptiveSteps: &AdaptiveSteps{MaxSteps: 6},
+	})
+
+	result, _ := agent.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	if result.Metadata["steps"] != 2 || result.Metadata["step_budget"] != 2 {
+		t.Errorf("expected no extension without new information, got %v steps (budget %v)",
+			result.Metadata["steps"], result.Metadata["step_budget"])
+	}
+}
+
+func TestReActAgent_LoopDetected(t *testing.T) {
+	repeated := "Thought: Try again\nAction: search\nAction Input: same query"
+	llm := &mockReActAgent{name: "llm", responses: []string{repeated, repeated, repeated, repeated}}
+	agent, _ := NewReActAgent(&ReActConfig{
+		Agent:         llm,
+		Tools:         []agenkit.Tool{&countingTool{}},
+		MaxSteps:      10,
+		AdaptiveSteps: &AdaptiveSteps{},
+	})
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["stop_reason"] != string(StopReasonLoopDetected) || GetStopReason(result) != StopLoopDetected {
+		t.Errorf("expected loop_detected, got %v", result.Metadata["stop_reason"])
+	}
+	if result.Metadata["steps"] != 3 {
+		t.Errorf("expected to stop on the third repeat, got %v steps", result.Metadata["steps"])
+	}
+}
+
+func TestReActAgent_NoLoopDetectionByDefault(t *testing.T) {
+	repeated := "Thought: Try again\nAction: search\nAction Input: same query"
+	llm := &mockReActAgent{name: "llm", responses: []string{repeated, repeated, repeated, repeated}}
+	agent, _ := NewReActAgent(&ReActConfig{Agent: llm, Tools: []agenkit.Tool{&countingTool{}}, MaxSteps: 4})
+
+	result, _ := agent.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	if GetStopReason(result) != StopMaxIterations {
+		t.Errorf("expected a fixed budget without AdaptiveSteps, got %v", GetStopReason(result))
+	}
+	if _, ok := result.Metadata["step_budget"]; ok {
+		t.Error("expected no step_budget without AdaptiveSteps")
+	}
+}
+
+func TestReasoningWithTools_LoopDetected(t *testing.T) {
+	call := "TOOL_CALL: calculator\nPARAMETERS: {\"b\": 2, \"a\": 1}"
+	llm := &mockReasoningAgent{name: "llm", responses: []string{call, call, call, "FINAL ANSWER: 3"}}
+	calculator := &mockReasoningTool{name: "calculator", description: "Calculate", response: "3"}
+	agent := NewReasoningWithToolsAgent(llm, []agenkit.Tool{calculator}, &ReasoningWithToolsConfig{
+		MaxReasoningSteps: 10,
+		AdaptiveSteps:     &AdaptiveSteps{RepeatLimit: 2},
+	})
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "1 + 2"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if GetStopReason(result) != StopLoopDetected {
+		t.Errorf("expected loop_detected, got %v", GetStopReason(result))
+	}
+	if calculator.callCount != 2 {
+		t.Errorf("expected to stop after the second identical call, got %d calls", calculator.callCount)
+	}
+}
+
+func TestReasoningWithTools_AdaptiveStepsExtends(t *testing.T) {
+	llm := &mockReasoningAgent{name: "llm", responses: []string{
+		"First, consider the inputs.",
+		"Next, the units.",
+		"Then the rounding.",
+		"FINAL ANSWER: 3",
+	}}
+	agent := NewReasoningWithToolsAgent(llm, nil, &ReasoningWithToolsConfig{
+		MaxReasoningSteps: 2,
+		AdaptiveSteps:     &AdaptiveSteps{MaxSteps: 5},
+	})
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if GetStopReason(result) != StopCompleted || result.Metadata["step_budget"] != 4 {
+		t.Errorf("expected completion within an extended budget of 4, got %v (budget %v)",
+			GetStopReason(result), result.Metadata["step_budget"])
+	}
+}
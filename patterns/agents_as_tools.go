@@ -54,6 +54,8 @@ type AgentTool struct {
 	inputKey        string
 	outputFormat    OutputFormat
 	includeMetadata bool
+
+	lazy lazyInit
 }
 
 // AgentToolConfig contains configuration for creating an AgentTool.
@@ -141,10 +143,16 @@ func (t *AgentTool) Execute(ctx context.Context, params map[string]any) (*agenki
 	}
 
 	// Create message
-	message := agenkit.NewMessage("user", fmt.Sprintf("%v", query))
+	message := agenkit.NewMessage(agenkit.RoleUser, fmt.Sprintf("%v", query))
 
 	// Call agent
-	response, err := t.agent.Process(ctx, message)
+	if err := t.Init(ctx); err != nil {
+		return agenkit.NewToolError(fmt.Sprintf(
+			"Agent '%s' failed to initialize: %v",
+			t.agent.Name(), err,
+		)), nil
+	}
+	response, err := ProcessTraced(ctx, t.agent, message)
 	if err != nil {
 		return agenkit.NewToolError(fmt.Sprintf(
 			"Agent '%s' failed: %v",
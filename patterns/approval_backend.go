@@ -0,0 +1,291 @@
+package patterns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ErrApprovalTimeout is returned by WebhookApprovalBackend when no decision
+// arrives within its timeout.
+var ErrApprovalTimeout = errors.New("approval timed out")
+
+// ErrUnknownApproval is returned when a decision names an approval request
+// that isn't pending, because it was never sent, was already decided or
+// timed out.
+var ErrUnknownApproval = errors.New("unknown approval request")
+
+// ApprovalBackend delivers approval requests to wherever humans decide them
+// (a Slack channel, an email inbox, a web dashboard) and returns their
+// decision. HumanInLoopAgent calls RequestApproval when a response needs
+// approval.
+//
+// RequestApproval may block until the decision arrives; it should return
+// promptly with the context error if ctx is cancelled.
+type ApprovalBackend interface {
+	RequestApproval(ctx context.Context, request *ApprovalRequest) (*ApprovalResponse, error)
+}
+
+// RequestApproval calls f, so an ApprovalFunc is the simplest
+// ApprovalBackend.
+func (f ApprovalFunc) RequestApproval(ctx context.Context, request *ApprovalRequest) (*ApprovalResponse, error) {
+	return f(ctx, request)
+}
+
+// WebhookApprovalPayload is the JSON body WebhookApprovalBackend posts for
+// each approval request.
+type WebhookApprovalPayload struct {
+	// ID identifies the request; the decision must carry it back
+	ID string `json:"id"`
+	// Content is the agent's proposed response
+	Content string `json:"content"`
+	// Confidence is the agent's confidence in the response
+	Confidence float64 `json:"confidence"`
+	// Context is the request's decision context
+	Context map[string]interface{} `json:"context,omitempty"`
+	// Timestamp is when approval was requested
+	Timestamp time.Time `json:"timestamp"`
+	// CallbackURL is where the decision should be posted, if configured
+	CallbackURL string `json:"callback_url,omitempty"`
+}
+
+// WebhookApprovalDecision is the JSON body a human interface posts back to
+// WebhookApprovalBackend's handler.
+type WebhookApprovalDecision struct {
+	// ID is the WebhookApprovalPayload ID being decided
+	ID string `json:"id"`
+	// Approved indicates if the response is approved
+	Approved bool `json:"approved"`
+	// Feedback is optional reviewer feedback
+	Feedback string `json:"feedback,omitempty"`
+	// ModifiedContent, if set, replaces the response content
+	ModifiedContent *string `json:"modified_content,omitempty"`
+}
+
+// WebhookApprovalConfig configures a WebhookApprovalBackend.
+type WebhookApprovalConfig struct {
+	// URL receives each approval request as a JSON POST (required)
+	URL string
+	// CallbackURL is sent in each payload so the receiver knows where to
+	// post the decision, typically the address WebhookApprovalBackend is
+	// served on (optional)
+	CallbackURL string
+	// Headers are added to each webhook request, e.g. authorization
+	// (optional)
+	Headers map[string]string
+	// Timeout bounds the wait for a decision (default: 0, wait until the
+	// context is done)
+	Timeout time.Duration
+	// Client sends the webhook requests (default: a client with a 30s
+	// timeout)
+	Client *http.Client
+}
+
+// pendingApproval is a request awaiting its decision.
+type pendingApproval struct {
+	request  *ApprovalRequest
+	decision chan *ApprovalResponse
+}
+
+// WebhookApprovalBackend is a reference ApprovalBackend for external human
+// interfaces. It posts each request to a webhook as a
+// WebhookApprovalPayload and waits for the decision, which arrives as a
+// WebhookApprovalDecision posted to the backend's HTTP handler or through
+// Resolve.
+//
+// A Slack app, email service or web dashboard receives the webhook, shows
+// the proposed response to a reviewer and posts their decision back.
+//
+// Example:
+//
+//	backend, _ := patterns.NewWebhookApprovalBackend(patterns.WebhookApprovalConfig{
+//	    URL:         "https://hooks.example.com/approvals",
+//	    CallbackURL: "https://agent.example.com/approvals/decision",
+//	    Timeout:     30 * time.Minute,
+//	})
+//	http.Handle("/approvals/decision", backend)
+//	agent, _ := patterns.NewHumanInLoopAgent(&patterns.HumanInLoopConfig{
+//	    Agent:           trader,
+//	    ApprovalBackend: backend,
+//	})
+type WebhookApprovalBackend struct {
+	url         string
+	callbackURL string
+	headers     map[string]string
+	timeout     time.Duration
+	client      *http.Client
+
+	mu      sync.Mutex
+	pending map[string]*pendingApproval
+}
+
+// NewWebhookApprovalBackend creates a webhook approval backend.
+//
+// Returns an error if config.URL is empty.
+func NewWebhookApprovalBackend(config WebhookApprovalConfig) (*WebhookApprovalBackend, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	headers := make(map[string]string, len(config.Headers))
+	for key, value := range config.Headers {
+		headers[key] = value
+	}
+	return &WebhookApprovalBackend{
+		url:         config.URL,
+		callbackURL: config.CallbackURL,
+		headers:     headers,
+		timeout:     config.Timeout,
+		client:      client,
+		pending:     make(map[string]*pendingApproval),
+	}, nil
+}
+
+// RequestApproval posts request to the webhook and waits for its decision.
+//
+// Returns an error if the webhook can't be reached or responds with a
+// non-2xx status, ErrApprovalTimeout if the timeout passes first, or the
+// context error if ctx is done first.
+func (b *WebhookApprovalBackend) RequestApproval(ctx context.Context, request *ApprovalRequest) (*ApprovalResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("approval request is required")
+	}
+
+	id := uuid.NewString()
+	pending := &pendingApproval{request: request, decision: make(chan *ApprovalResponse, 1)}
+	b.mu.Lock()
+	b.pending[id] = pending
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.pending, id)
+		b.mu.Unlock()
+	}()
+
+	if err := b.post(ctx, id, request); err != nil {
+		return nil, err
+	}
+
+	var timeout <-chan time.Time
+	if b.timeout > 0 {
+		timer := time.NewTimer(b.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case response := <-pending.decision:
+		return response, nil
+	case <-timeout:
+		return nil, fmt.Errorf("%w after %v (request %s)", ErrApprovalTimeout, b.timeout, id)
+	case <-ctx.Done():
+		return nil, contextError(ctx)
+	}
+}
+
+// post sends the webhook for request.
+func (b *WebhookApprovalBackend) post(ctx context.Context, id string, request *ApprovalRequest) error {
+	payload := WebhookApprovalPayload{
+		ID:          id,
+		Confidence:  request.Confidence,
+		Context:     request.Context,
+		Timestamp:   request.Timestamp,
+		CallbackURL: b.callbackURL,
+	}
+	if request.Message != nil {
+		payload.Content = request.Message.ContentString()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode approval request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for key, value := range b.headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("approval webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("approval webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Resolve delivers the decision for the pending request id, for
+// interfaces that receive decisions some other way than the HTTP handler.
+// A modified content replaces the response content, keeping its role.
+//
+// Returns ErrUnknownApproval if id isn't pending.
+func (b *WebhookApprovalBackend) Resolve(decision WebhookApprovalDecision) error {
+	b.mu.Lock()
+	pending, ok := b.pending[decision.ID]
+	if ok {
+		delete(b.pending, decision.ID)
+	}
+	b.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownApproval, decision.ID)
+	}
+
+	response := &ApprovalResponse{Approved: decision.Approved, Feedback: decision.Feedback}
+	if decision.ModifiedContent != nil {
+		role := agenkit.RoleAssistant
+		if pending.request.Message != nil {
+			role = pending.request.Message.Role
+		}
+		response.ModifiedMessage = agenkit.NewMessage(role, *decision.ModifiedContent)
+	}
+	pending.decision <- response
+	return nil
+}
+
+// Pending returns the number of requests awaiting a decision.
+func (b *WebhookApprovalBackend) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending)
+}
+
+// ServeHTTP receives decisions: a POST with a WebhookApprovalDecision JSON
+// body. It responds 204 once the decision is delivered, 400 for a malformed
+// body, 404 if the request isn't pending and 405 for other methods.
+func (b *WebhookApprovalBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var decision WebhookApprovalDecision
+	if err := json.NewDecoder(r.Body).Decode(&decision); err != nil || decision.ID == "" {
+		http.Error(w, "invalid approval decision", http.StatusBadRequest)
+		return
+	}
+	if err := b.Resolve(decision); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
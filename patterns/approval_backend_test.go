@@ -0,0 +1,199 @@
+package patterns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// newApprovalWebhook starts a webhook that records payloads and answers
+// each with decide, posting the decision to the payload's callback URL.
+func newApprovalWebhook(t *testing.T, decide func(WebhookApprovalPayload) *WebhookApprovalDecision) (*httptest.Server, chan WebhookApprovalPayload) {
+	t.Helper()
+	received := make(chan WebhookApprovalPayload, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookApprovalPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		received <- payload
+		w.WriteHeader(http.StatusAccepted)
+
+		if decision := decide(payload); decision != nil {
+			go func() {
+				body, _ := json.Marshal(decision)
+				resp, err := http.Post(payload.CallbackURL, "application/json", bytes.NewReader(body))
+				if err == nil {
+					resp.Body.Close()
+				}
+			}()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestWebhookApprovalBackend_HumanInLoop(t *testing.T) {
+	modified := "sell 50 shares"
+	webhook, received := newApprovalWebhook(t, func(p WebhookApprovalPayload) *WebhookApprovalDecision {
+		return &WebhookApprovalDecision{ID: p.ID, Approved: true, Feedback: "halve it", ModifiedContent: &modified}
+	})
+
+	backend, err := NewWebhookApprovalBackend(WebhookApprovalConfig{
+		URL:     webhook.URL,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	callback := httptest.NewServer(backend)
+	defer callback.Close()
+	backend.callbackURL = callback.URL
+
+	hil, err := NewHumanInLoopAgent(&HumanInLoopConfig{
+		Agent: &extendedMockAgent{
+			name: "trader",
+			processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+				return agenkit.NewMessage("assistant", "sell 100 shares").WithMetadata("confidence", 0.5), nil
+			},
+		},
+		ApprovalBackend: backend,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := hil.Process(context.Background(), agenkit.NewMessage("user", "rebalance"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != modified || result.Role != "assistant" {
+		t.Errorf("expected modified response, got %s: %q", result.Role, result.ContentString())
+	}
+	if result.Metadata["approval_feedback"] != "halve it" {
+		t.Errorf("expected feedback, got %v", result.Metadata["approval_feedback"])
+	}
+
+	payload := <-received
+	if payload.Content != "sell 100 shares" || payload.Confidence != 0.5 || payload.CallbackURL != callback.URL {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+	if backend.Pending() != 0 {
+		t.Errorf("expected no pending requests, got %d", backend.Pending())
+	}
+}
+
+func TestWebhookApprovalBackend_Timeout(t *testing.T) {
+	webhook, _ := newApprovalWebhook(t, func(WebhookApprovalPayload) *WebhookApprovalDecision { return nil })
+	backend, _ := NewWebhookApprovalBackend(WebhookApprovalConfig{URL: webhook.URL, Timeout: 20 * time.Millisecond})
+
+	_, err := backend.RequestApproval(context.Background(), &ApprovalRequest{Message: agenkit.NewMessage("assistant", "x")})
+	if !errors.Is(err, ErrApprovalTimeout) {
+		t.Fatalf("expected ErrApprovalTimeout, got %v", err)
+	}
+	if backend.Pending() != 0 {
+		t.Error("expected timed-out request to be removed")
+	}
+}
+
+func TestWebhookApprovalBackend_ContextCancelled(t *testing.T) {
+	webhook, received := newApprovalWebhook(t, func(WebhookApprovalPayload) *WebhookApprovalDecision { return nil })
+	backend, _ := NewWebhookApprovalBackend(WebhookApprovalConfig{URL: webhook.URL})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+	_, err := backend.RequestApproval(ctx, &ApprovalRequest{Message: agenkit.NewMessage("assistant", "x")})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWebhookApprovalBackend_WebhookError(t *testing.T) {
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer webhook.Close()
+	backend, _ := NewWebhookApprovalBackend(WebhookApprovalConfig{URL: webhook.URL})
+
+	_, err := backend.RequestApproval(context.Background(), &ApprovalRequest{Message: agenkit.NewMessage("assistant", "x")})
+	if err == nil || !strings.Contains(err.Error(), "status 500") {
+		t.Fatalf("expected webhook status error, got %v", err)
+	}
+	if backend.Pending() != 0 {
+		t.Error("expected failed request to be removed")
+	}
+}
+
+func TestWebhookApprovalBackend_ServeHTTP(t *testing.T) {
+	backend, _ := NewWebhookApprovalBackend(WebhookApprovalConfig{URL: "http://unused"})
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   int
+	}{
+		{"wrong method", http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"malformed body", http.MethodPost, "{", http.StatusBadRequest},
+		{"missing id", http.MethodPost, `{"approved": true}`, http.StatusBadRequest},
+		{"unknown request", http.MethodPost, `{"id": "nope", "approved": true}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			backend.ServeHTTP(rec, httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+
+	if err := backend.Resolve(WebhookApprovalDecision{ID: "nope"}); !errors.Is(err, ErrUnknownApproval) {
+		t.Errorf("expected ErrUnknownApproval, got %v", err)
+	}
+}
+
+func TestNewWebhookApprovalBackend_RequiresURL(t *testing.T) {
+	if _, err := NewWebhookApprovalBackend(WebhookApprovalConfig{}); err == nil {
+		t.Fatal("expected error for missing URL")
+	}
+}
+
+func TestHumanInLoopAgent_ApprovalFuncIsBackend(t *testing.T) {
+	var backend ApprovalBackend = SimpleApprovalFunc(false)
+	hil, err := NewHumanInLoopAgent(&HumanInLoopConfig{
+		Agent:           &extendedMockAgent{name: "agent", response: "result"},
+		ApprovalBackend: backend,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := hil.Process(context.Background(), agenkit.NewMessage("user", "go"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["approval_status"] != "rejected" {
+		t.Errorf("expected rejection, got %v", result.Metadata["approval_status"])
+	}
+
+	_, err = NewHumanInLoopAgent(&HumanInLoopConfig{
+		Agent:           &extendedMockAgent{name: "agent"},
+		ApprovalFunc:    SimpleApprovalFunc(true),
+		ApprovalBackend: backend,
+	})
+	if err == nil {
+		t.Error("expected error when both approval function and backend are set")
+	}
+}
@@ -0,0 +1,356 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Attempt budgets cap the total attempts and wall-clock time of composed
+// resilience patterns, so retries and fallbacks cannot multiply unbounded.
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ErrBudgetExhausted is returned (wrapped) when an AttemptBudget runs out.
+var ErrBudgetExhausted = errors.New("attempt budget exhausted")
+
+// AttemptBudget limits total attempts and wall-clock time across a chain
+// of retries and fallbacks. It is safe for concurrent use.
+type AttemptBudget struct {
+	mu          sync.Mutex
+	maxAttempts int
+	maxDuration time.Duration
+	used        int
+	start       time.Time
+}
+
+// NewAttemptBudget creates a budget allowing at most maxAttempts attempts
+// within maxDuration. A zero value for either limit means unlimited.
+func NewAttemptBudget(maxAttempts int, maxDuration time.Duration) *AttemptBudget {
+	return &AttemptBudget{
+		maxAttempts: maxAttempts,
+		maxDuration: maxDuration,
+		start:       time.Now(),
+	}
+}
+
+// Acquire reserves one attempt. Returns false if the budget is exhausted.
+func (b *AttemptBudget) Acquire() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.exhaustedLocked() {
+		return false
+	}
+	b.used++
+	return true
+}
+
+// Exhausted reports whether no further attempts are allowed.
+func (b *AttemptBudget) Exhausted() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.exhaustedLocked()
+}
+
+// exhaustedLocked reports exhaustion. Caller must hold the lock.
+func (b *AttemptBudget) exhaustedLocked() bool {
+	if b.maxAttempts > 0 && b.used >= b.maxAttempts {
+		return true
+	}
+	if b.maxDuration > 0 && time.Since(b.start) >= b.maxDuration {
+		return true
+	}
+	return false
+}
+
+// Used returns the number of attempts consumed.
+func (b *AttemptBudget) Used() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// Remaining returns the number of attempts left (-1 if unlimited).
+func (b *AttemptBudget) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.maxAttempts <= 0 {
+		return -1
+	}
+	return b.maxAttempts - b.used
+}
+
+// Deadline returns when the wall-clock budget expires, if one is set.
+func (b *AttemptBudget) Deadline() (time.Time, bool) {
+	if b.maxDuration <= 0 {
+		return time.Time{}, false
+	}
+	return b.start.Add(b.maxDuration), true
+}
+
+// attemptBudgetKey is the private context key for AttemptBudget values.
+type attemptBudgetKey struct{}
+
+// WithAttemptBudget returns a copy of ctx carrying budget so that nested
+// budget-aware wrappers draw from the same pool.
+func WithAttemptBudget(ctx context.Context, budget *AttemptBudget) context.Context {
+	return context.WithValue(ctx, attemptBudgetKey{}, budget)
+}
+
+// AttemptBudgetFromContext retrieves the AttemptBudget stored in ctx.
+func AttemptBudgetFromContext(ctx context.Context) (*AttemptBudget, bool) {
+	budget, ok := ctx.Value(attemptBudgetKey{}).(*AttemptBudget)
+	return budget, ok && budget != nil
+}
+
+// BudgetExhaustedError reports that the budget ran out before any agent
+// succeeded. It wraps the best error collected so far.
+type BudgetExhaustedError struct {
+	// Attempts is the number of attempts made by this chain
+	Attempts int
+	// Elapsed is the wall-clock time spent
+	Elapsed time.Duration
+	// BestErr is the most informative agent error observed (may be nil)
+	BestErr error
+}
+
+func (e *BudgetExhaustedError) Error() string {
+	if e.BestErr != nil {
+		return fmt.Sprintf("%v after %d attempts in %v: %v", ErrBudgetExhausted, e.Attempts, e.Elapsed, e.BestErr)
+	}
+	return fmt.Sprintf("%v after %d attempts in %v", ErrBudgetExhausted, e.Attempts, e.Elapsed)
+}
+
+// Unwrap returns both the sentinel and the best error so errors.Is works
+// for either.
+func (e *BudgetExhaustedError) Unwrap() []error {
+	if e.BestErr != nil {
+		return []error{ErrBudgetExhausted, e.BestErr}
+	}
+	return []error{ErrBudgetExhausted}
+}
+
+// BudgetedFallbackConfig configures a BudgetedFallbackAgent.
+type BudgetedFallbackConfig struct {
+	// Agents to try in order (required)
+	Agents []agenkit.Agent
+	// RetriesPerAgent is the number of retries per agent before falling
+	// back to the next one (default: 0)
+	RetriesPerAgent int
+	// RetryBackoff is the delay between retries of the same agent
+	RetryBackoff time.Duration
+	// MaxAttempts caps total attempts across the chain (0 = unlimited)
+	MaxAttempts int
+	// MaxDuration caps total wall-clock time across the chain (0 = unlimited)
+	MaxDuration time.Duration
+	// Logger receives structured retry and fallback events (default: package logger)
+	Logger *slog.Logger
+}
+
+// BudgetedFallbackAgent combines per-agent retries with a fallback chain
+// under one shared AttemptBudget.
+//
+// Each Process call creates a fresh budget from MaxAttempts/MaxDuration,
+// unless ctx already carries one (see WithAttemptBudget), in which case the
+// enclosing budget is shared. When the budget is exhausted mid-chain, the
+// best error collected so far is returned wrapped in BudgetExhaustedError.
+//
+// Example:
+//
+//	agent, _ := patterns.NewBudgetedFallbackAgent(&patterns.BudgetedFallbackConfig{
+//	    Agents:          []agenkit.Agent{primary, secondary, tertiary},
+//	    RetriesPerAgent: 2,
+//	    MaxAttempts:     4,
+//	    MaxDuration:     5 * time.Second,
+//	})
+type BudgetedFallbackAgent struct {
+	name            string
+	agents          []agenkit.Agent
+	retriesPerAgent int
+	retryBackoff    time.Duration
+	maxAttempts     int
+	maxDuration     time.Duration
+	logger          *slog.Logger
+
+	lazy lazyInit
+}
+
+// NewBudgetedFallbackAgent creates a new budgeted fallback agent.
+func NewBudgetedFallbackAgent(config *BudgetedFallbackConfig) (*BudgetedFallbackAgent, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+	if len(config.Agents) == 0 {
+		return nil, fmt.Errorf("at least one agent is required")
+	}
+	if config.RetriesPerAgent < 0 {
+		return nil, fmt.Errorf("retries per agent cannot be negative")
+	}
+
+	return &BudgetedFallbackAgent{
+		name:            "BudgetedFallbackAgent",
+		agents:          config.Agents,
+		retriesPerAgent: config.RetriesPerAgent,
+		retryBackoff:    config.RetryBackoff,
+		maxAttempts:     config.MaxAttempts,
+		maxDuration:     config.MaxDuration,
+		logger:          config.Logger,
+	}, nil
+}
+
+// Name returns the agent's identifier.
+func (b *BudgetedFallbackAgent) Name() string {
+	return b.name
+}
+
+// Capabilities returns the combined capabilities of all agents.
+func (b *BudgetedFallbackAgent) Capabilities() []string {
+	capMap := make(map[string]bool)
+	for _, agent := range b.agents {
+		for _, cap := range agent.Capabilities() {
+			capMap[cap] = true
+		}
+	}
+
+	capabilities := make([]string, 0, len(capMap))
+	for cap := range capMap {
+		capabilities = append(capabilities, cap)
+	}
+	capabilities = append(capabilities, "fallback", "retry", "attempt-budget")
+
+	return capabilities
+}
+
+// Introspect returns introspection information for the agent.
+func (b *BudgetedFallbackAgent) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    b.Name(),
+		Capabilities: b.Capabilities(),
+		InternalState: map[string]interface{}{
+			"agent_count":       len(b.agents),
+			"retries_per_agent": b.retriesPerAgent,
+			"max_attempts":      b.maxAttempts,
+			"max_duration_ms":   b.maxDuration.Milliseconds(),
+		},
+	}
+}
+
+// Process tries each agent with retries until one succeeds or the budget
+// is exhausted.
+func (b *BudgetedFallbackAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := b.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	budget, shared := AttemptBudgetFromContext(ctx)
+	if !shared {
+		budget = NewAttemptBudget(b.maxAttempts, b.maxDuration)
+		ctx = WithAttemptBudget(ctx, budget)
+	}
+
+	// Bound in-flight attempts by the wall-clock budget
+	if deadline, ok := budget.Deadline(); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	logger := resolveLogger(b.logger).With(slog.String("pattern", b.name))
+	start := time.Now()
+	attempts := make([]attemptResult, 0)
+	var bestErr error
+
+	exhausted := func() error {
+		return &BudgetExhaustedError{
+			Attempts: len(attempts),
+			Elapsed:  time.Since(start),
+			BestErr:  bestErr,
+		}
+	}
+
+	for i, agent := range b.agents {
+		if i > 0 && len(attempts) > 0 {
+			logger.WarnContext(ctx, LogEventFallback, slog.String("agent", b.agents[i-1].Name()),
+				slog.String("next_agent", agent.Name()), slog.Any("error", attempts[len(attempts)-1].err))
+		}
+
+		for retry := 0; retry <= b.retriesPerAgent; retry++ {
+			if retry > 0 {
+				logger.WarnContext(ctx, LogEventRetry, slog.String("agent", agent.Name()),
+					slog.Int("retry", retry), slog.Int("attempt", len(attempts)+1))
+			}
+			if retry > 0 && b.retryBackoff > 0 {
+				select {
+				case <-time.After(b.retryBackoff):
+				case <-ctx.Done():
+					if budget.Exhausted() {
+						return nil, exhausted()
+					}
+					return nil, fmt.Errorf("budgeted fallback cancelled after %d attempts: %w", len(attempts), contextError(ctx))
+				}
+			}
+
+			if ctx.Err() != nil && !budget.Exhausted() {
+				return nil, fmt.Errorf("budgeted fallback cancelled after %d attempts: %w", len(attempts), contextError(ctx))
+			}
+			if !budget.Acquire() {
+				return nil, exhausted()
+			}
+
+			result, err := ProcessTraced(ctx, agent, message)
+			attempts = append(attempts, attemptResult{
+				agentIndex: i,
+				agentName:  agent.Name(),
+				success:    err == nil,
+				message:    result,
+				err:        err,
+			})
+
+			if err == nil {
+				return b.buildSuccessResult(result, attempts, budget), nil
+			}
+
+			// Prefer agent errors over context errors caused by the budget
+			if bestErr == nil || !isContextError(err) {
+				bestErr = err
+			}
+		}
+	}
+
+	var errorMsg strings.Builder
+	errorMsg.WriteString(fmt.Sprintf("all %d attempts failed:\n", len(attempts)))
+	for _, attempt := range attempts {
+		errorMsg.WriteString(fmt.Sprintf("  [%d] %s: %v\n", attempt.agentIndex, attempt.agentName, attempt.err))
+	}
+	return nil, fmt.Errorf("%s", errorMsg.String())
+}
+
+// buildSuccessResult returns a copy of a successful response with budget
+// metadata, leaving the agent's message unmodified.
+func (b *BudgetedFallbackAgent) buildSuccessResult(response *agenkit.Message, attempts []attemptResult, budget *AttemptBudget) *agenkit.Message {
+	message := copyMessage(response)
+
+	successful := attempts[len(attempts)-1]
+	message.Metadata["fallback_attempts"] = len(attempts)
+	message.Metadata["fallback_success_index"] = successful.agentIndex
+	message.Metadata["fallback_success_agent"] = successful.agentName
+	message.Metadata["budget_attempts_used"] = budget.Used()
+
+	return message
+}
+
+// isContextError reports whether err is a context cancellation or deadline.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
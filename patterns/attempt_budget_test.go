@@ -0,0 +1,224 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// countingAgent fails a fixed number of times before succeeding.
+func countingAgent(name string, failures int32, calls *int32) *extendedMockAgent {
+	return &extendedMockAgent{
+		name: name,
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			n := atomic.AddInt32(calls, 1)
+			if n <= failures {
+				return nil, errors.New(name + " unavailable")
+			}
+			return agenkit.NewMessage("assistant", name+" ok"), nil
+		},
+	}
+}
+
+func TestAttemptBudget_MaxAttempts(t *testing.T) {
+	budget := NewAttemptBudget(2, 0)
+
+	if !budget.Acquire() || !budget.Acquire() {
+		t.Fatal("expected first two attempts to be allowed")
+	}
+	if budget.Acquire() {
+		t.Error("expected third attempt to be rejected")
+	}
+	if !budget.Exhausted() {
+		t.Error("expected budget to be exhausted")
+	}
+	if budget.Used() != 2 || budget.Remaining() != 0 {
+		t.Errorf("expected used=2 remaining=0, got used=%d remaining=%d", budget.Used(), budget.Remaining())
+	}
+}
+
+func TestAttemptBudget_Unlimited(t *testing.T) {
+	budget := NewAttemptBudget(0, 0)
+	for i := 0; i < 100; i++ {
+		if !budget.Acquire() {
+			t.Fatalf("expected unlimited budget to allow attempt %d", i)
+		}
+	}
+	if budget.Remaining() != -1 {
+		t.Errorf("expected remaining -1 for unlimited budget, got %d", budget.Remaining())
+	}
+	if _, ok := budget.Deadline(); ok {
+		t.Error("expected no deadline for unlimited budget")
+	}
+}
+
+func TestBudgetedFallbackAgent_RetriesThenFallsBack(t *testing.T) {
+	var primaryCalls, backupCalls int32
+	agent, err := NewBudgetedFallbackAgent(&BudgetedFallbackConfig{
+		Agents: []agenkit.Agent{
+			countingAgent("primary", 100, &primaryCalls),
+			countingAgent("backup", 0, &backupCalls),
+		},
+		RetriesPerAgent: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "go"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if primaryCalls != 3 || backupCalls != 1 {
+		t.Errorf("expected 3 primary and 1 backup calls, got %d and %d", primaryCalls, backupCalls)
+	}
+	if result.Metadata["fallback_success_agent"] != "backup" {
+		t.Errorf("expected backup to succeed, got %v", result.Metadata["fallback_success_agent"])
+	}
+	if result.Metadata["budget_attempts_used"] != 4 {
+		t.Errorf("expected 4 attempts used, got %v", result.Metadata["budget_attempts_used"])
+	}
+}
+
+func TestBudgetedFallbackAgent_DoesNotModifyAgentResponse(t *testing.T) {
+	shared := agenkit.NewMessage("assistant", "cached answer")
+	agent, _ := NewBudgetedFallbackAgent(&BudgetedFallbackConfig{
+		Agents: []agenkit.Agent{&extendedMockAgent{name: "cache", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			return shared, nil
+		}}},
+	})
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "go"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["fallback_success_agent"] != "cache" {
+		t.Errorf("expected budget metadata on the result, got %v", result.Metadata)
+	}
+	if len(shared.Metadata) != 0 {
+		t.Errorf("expected the agent's message unmodified, got %v", shared.Metadata)
+	}
+}
+
+func TestBudgetedFallbackAgent_MaxAttemptsStopsChain(t *testing.T) {
+	var aCalls, bCalls, cCalls int32
+	agent, err := NewBudgetedFallbackAgent(&BudgetedFallbackConfig{
+		Agents: []agenkit.Agent{
+			countingAgent("a", 100, &aCalls),
+			countingAgent("b", 100, &bCalls),
+			countingAgent("c", 0, &cCalls),
+		},
+		RetriesPerAgent: 2,
+		MaxAttempts:     4,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = agent.Process(context.Background(), agenkit.NewMessage("user", "go"))
+
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected ErrBudgetExhausted, got %v", err)
+	}
+	var budgetErr *BudgetExhaustedError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("expected BudgetExhaustedError, got %T", err)
+	}
+	if budgetErr.Attempts != 4 {
+		t.Errorf("expected 4 attempts, got %d", budgetErr.Attempts)
+	}
+	if budgetErr.BestErr == nil || !strings.Contains(budgetErr.BestErr.Error(), "b unavailable") {
+		t.Errorf("expected best error from agent b, got %v", budgetErr.BestErr)
+	}
+	if cCalls != 0 {
+		t.Errorf("expected agent c never to run, ran %d times", cCalls)
+	}
+}
+
+func TestBudgetedFallbackAgent_MaxDurationBoundsInFlight(t *testing.T) {
+	slow := &extendedMockAgent{
+		name: "slow",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			select {
+			case <-time.After(time.Second):
+				return agenkit.NewMessage("assistant", "late"), nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	}
+	var backupCalls int32
+	agent, err := NewBudgetedFallbackAgent(&BudgetedFallbackConfig{
+		Agents:      []agenkit.Agent{slow, countingAgent("backup", 0, &backupCalls)},
+		MaxDuration: 30 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	_, err = agent.Process(context.Background(), agenkit.NewMessage("user", "go"))
+
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected ErrBudgetExhausted, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected wall-clock budget to bound the chain, took %v", elapsed)
+	}
+	if backupCalls != 0 {
+		t.Errorf("expected backup not to run after budget expired, ran %d times", backupCalls)
+	}
+}
+
+func TestBudgetedFallbackAgent_SharesBudgetFromContext(t *testing.T) {
+	var innerCalls, outerCalls int32
+	inner, err := NewBudgetedFallbackAgent(&BudgetedFallbackConfig{
+		Agents:          []agenkit.Agent{countingAgent("inner", 100, &innerCalls)},
+		RetriesPerAgent: 5,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	outer, err := NewBudgetedFallbackAgent(&BudgetedFallbackConfig{
+		Agents:          []agenkit.Agent{inner, countingAgent("outer", 0, &outerCalls)},
+		RetriesPerAgent: 5,
+		MaxAttempts:     3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = outer.Process(context.Background(), agenkit.NewMessage("user", "go"))
+
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected shared budget to be exhausted, got %v", err)
+	}
+	// Outer consumed 1 attempt to call inner; inner may use the remaining 2
+	if innerCalls != 2 {
+		t.Errorf("expected inner agent to get 2 attempts from the shared budget, got %d", innerCalls)
+	}
+	if outerCalls != 0 {
+		t.Errorf("expected outer fallback not to run, ran %d times", outerCalls)
+	}
+}
+
+func TestNewBudgetedFallbackAgent_Validation(t *testing.T) {
+	if _, err := NewBudgetedFallbackAgent(nil); err == nil {
+		t.Error("expected error for nil config")
+	}
+	if _, err := NewBudgetedFallbackAgent(&BudgetedFallbackConfig{}); err == nil {
+		t.Error("expected error for no agents")
+	}
+	if _, err := NewBudgetedFallbackAgent(&BudgetedFallbackConfig{
+		Agents:          []agenkit.Agent{&extendedMockAgent{name: "a"}},
+		RetriesPerAgent: -1,
+	}); err == nil {
+		t.Error("expected error for negative retries")
+	}
+}
@@ -16,7 +16,11 @@
 //   - Objective: High-level goal the agent is working towards
 //   - Goals: Specific sub-tasks the agent pursues
 //   - Iterations: Number of work cycles completed
+//   - Completion Check: Per-goal definition of "done"; goals that exceed
+//     their iteration budget are abandoned
 //   - Stop Condition: Optional function to halt execution early
+//   - Run Control: Pause, Resume, Step and Stop a run from another goroutine
+//   - Streaming: RunStream reports goals and iterations as they happen
 //
 // Example:
 //
@@ -32,6 +36,7 @@ package patterns
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
@@ -61,6 +66,59 @@ type Goal struct {
 	Progress float64
 	// CreatedAt timestamp
 	CreatedAt time.Time
+	// CompletionCheck decides after each iteration whether the goal is
+	// done (nil uses the agent's default)
+	CompletionCheck CompletionCheck
+	// MaxIterations abandons the goal after this many iterations without
+	// completing (0 = no limit)
+	MaxIterations int
+	// Iterations is the number of iterations spent on the goal
+	Iterations int
+}
+
+// CompletionCheck decides whether a goal is done after the worker
+// returned workerOutput without error. It may also update goal.Progress.
+type CompletionCheck func(goal *Goal, workerOutput string) bool
+
+// CompleteOnSuccess is the default CompletionCheck: it completes a goal as
+// soon as the worker returns without error.
+func CompleteOnSuccess(goal *Goal, workerOutput string) bool {
+	goal.Progress = 1.0
+	return true
+}
+
+// CompleteAfter returns a CompletionCheck that advances a goal's progress
+// by 1/n on each successful iteration, completing it after n.
+func CompleteAfter(n int) CompletionCheck {
+	if n < 1 {
+		n = 1
+	}
+	return func(goal *Goal, workerOutput string) bool {
+		goal.Progress += 1 / float64(n)
+		if goal.Progress >= 1.0-1e-9 {
+			goal.Progress = 1.0
+			return true
+		}
+		return false
+	}
+}
+
+// GoalOption configures a goal added with AddGoalWithOptions.
+type GoalOption func(*Goal)
+
+// WithCompletionCheck sets how the goal decides it is done.
+func WithCompletionCheck(check CompletionCheck) GoalOption {
+	return func(g *Goal) {
+		g.CompletionCheck = check
+	}
+}
+
+// WithGoalMaxIterations abandons the goal after maxIterations iterations
+// without completing.
+func WithGoalMaxIterations(maxIterations int) GoalOption {
+	return func(g *Goal) {
+		g.MaxIterations = maxIterations
+	}
 }
 
 // CreateGoal creates a new goal.
@@ -82,8 +140,56 @@ type AutonomousResult struct {
 	Iterations int
 	// GoalsCompleted count
 	GoalsCompleted int
+	// GoalsAbandoned count
+	GoalsAbandoned int
 	// Results from each iteration
 	Results []string
+	// StopReason is why the run ended: StopCompleted when every goal
+	// completed, StopBudgetExceeded when goals ran out with some abandoned,
+	// StopMaxIterations, or StopStopped for a stop condition or Stop
+	StopReason StopReason
+}
+
+// GoalEventType identifies a GoalEvent.
+type GoalEventType string
+
+const (
+	// GoalEventIteration is emitted when an iteration starts work on a goal
+	GoalEventIteration GoalEventType = "iteration"
+	// GoalEventStarted is emitted before a goal's first iteration
+	GoalEventStarted GoalEventType = "goal_started"
+	// GoalEventProgress is emitted after each iteration with the worker's output
+	GoalEventProgress GoalEventType = "progress"
+	// GoalEventCompleted is emitted when a goal's completion check passes
+	GoalEventCompleted GoalEventType = "goal_completed"
+	// GoalEventAbandoned is emitted when a goal spends its iteration budget
+	GoalEventAbandoned GoalEventType = "goal_abandoned"
+	// GoalEventRunEnded is the last event of a run, carrying its result
+	GoalEventRunEnded GoalEventType = "run_ended"
+)
+
+// GoalEvent reports one step of a run streamed with RunStream.
+type GoalEvent struct {
+	// Type of the event
+	Type GoalEventType
+	// Iteration is the run's iteration count when the event was emitted
+	Iteration int
+	// Goal is a snapshot of the goal worked on (nil for GoalEventRunEnded)
+	Goal *Goal
+	// Output is the worker's output (GoalEventProgress only)
+	Output string
+	// Result is the run's result (GoalEventRunEnded only, nil on error)
+	Result *AutonomousResult
+	// Err is the worker's error for GoalEventProgress, or the run's error
+	// for GoalEventRunEnded
+	Err error
+}
+
+// newGoalEvent creates an event with a snapshot of goal, so the caller
+// can read it while the run continues.
+func newGoalEvent(eventType GoalEventType, iteration int, goal *Goal) GoalEvent {
+	snapshot := *goal
+	return GoalEvent{Type: eventType, Iteration: iteration, Goal: &snapshot}
 }
 
 // StopCondition is a function that determines if the agent should stop.
@@ -97,7 +203,8 @@ type GoalWorker func(ctx context.Context, goal *Goal) (string, error)
 // The autonomous agent:
 //   - Manages multiple goals with different priorities
 //   - Works on the highest priority active goal each iteration
-//   - Updates progress and marks goals as completed
+//   - Marks goals completed by their completion check, or abandoned once
+//     their iteration budget is spent
 //   - Runs until max iterations, all goals complete, or stop condition met
 //
 // Example:
@@ -114,6 +221,15 @@ type GoalWorker func(ctx context.Context, goal *Goal) (string, error)
 //	})
 //
 //	result, _ := agent.Run(context.Background())
+//
+// A run can be controlled from another goroutine: Pause holds Run before
+// its next iteration, Step lets exactly one more iteration through, Resume
+// continues normally and Stop ends the run, paused or not.
+//
+//	agent.Pause()
+//	go agent.Run(ctx)
+//	agent.Step() // watch one iteration at a time
+//	agent.Resume()
 type AutonomousAgent struct {
 	name           string
 	objective      string
@@ -121,8 +237,16 @@ type AutonomousAgent struct {
 	stopCondition  StopCondition
 	goals          []*Goal
 	iterationCount int
-	isRunning      bool
 	worker         GoalWorker
+	completion     CompletionCheck
+
+	// Run control state, guarded by mu. control wakes a paused Run when
+	// the state changes.
+	mu        sync.Mutex
+	isRunning bool
+	paused    bool
+	steps     int
+	control   chan struct{}
 }
 
 // NewAutonomousAgent creates a new autonomous agent.
@@ -139,6 +263,8 @@ func NewAutonomousAgent(objective string, maxIterations int) *AutonomousAgent {
 		iterationCount: 0,
 		isRunning:      false,
 		worker:         defaultWorker,
+		completion:     CompleteOnSuccess,
+		control:        make(chan struct{}, 1),
 	}
 }
 
@@ -158,9 +284,15 @@ func (a *AutonomousAgent) Capabilities() []string {
 }
 
 // Process processes a message (autonomous agents don't need messages).
-func (a *AutonomousAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+func (a *AutonomousAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+
 	return &agenkit.Message{
-		Role:    "assistant",
+		Role:    agenkit.RoleAssistant,
 		Content: fmt.Sprintf("Autonomous agent working on: %s", a.objective),
 	}, nil
 }
@@ -172,6 +304,35 @@ func (a *AutonomousAgent) AddGoal(description string, priority int) *Goal {
 	return goal
 }
 
+// AddGoalWithOptions adds a goal configured by opts, such as its
+// completion check and iteration budget.
+//
+// Example:
+//
+//	agent.AddGoalWithOptions("Fix failing tests", 10,
+//	    patterns.WithCompletionCheck(func(goal *patterns.Goal, output string) bool {
+//	        return strings.Contains(output, "PASS")
+//	    }),
+//	    patterns.WithGoalMaxIterations(3),
+//	)
+func (a *AutonomousAgent) AddGoalWithOptions(description string, priority int, opts ...GoalOption) *Goal {
+	goal := a.AddGoal(description, priority)
+	for _, opt := range opts {
+		opt(goal)
+	}
+	return goal
+}
+
+// SetCompletionCheck sets the completion check for goals without their
+// own. The default, CompleteOnSuccess, completes a goal on the first
+// iteration its worker succeeds; nil restores it.
+func (a *AutonomousAgent) SetCompletionCheck(check CompletionCheck) {
+	if check == nil {
+		check = CompleteOnSuccess
+	}
+	a.completion = check
+}
+
 // SetStopCondition sets the stop condition function.
 func (a *AutonomousAgent) SetStopCondition(condition StopCondition) {
 	a.stopCondition = condition
@@ -186,26 +347,91 @@ func (a *AutonomousAgent) SetWorker(worker GoalWorker) {
 //
 // Executes work iterations until:
 //   - Max iterations reached
-//   - All goals completed
+//   - All goals completed or abandoned
 //   - Stop condition met
 //   - Agent manually stopped
 //   - Context cancelled
+//
+// While paused, Run blocks before the next iteration until Resume, Step,
+// Stop or context cancellation.
 func (a *AutonomousAgent) Run(ctx context.Context) (*AutonomousResult, error) {
-	a.isRunning = true
+	return a.run(ctx, func(GoalEvent) {})
+}
+
+// RunStream runs the agent like Run, reporting each step as a GoalEvent
+// instead of blocking until the end. The channel closes after the
+// GoalEventRunEnded event, which carries the result or error. If ctx is
+// cancelled the run stops and the channel closes, possibly without the
+// final event.
+//
+// Events are delivered unbuffered, so the run advances only as fast as
+// the caller reads; stop reading only after cancelling ctx.
+//
+// Example:
+//
+//	events, err := agent.RunStream(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	for event := range events {
+//	    fmt.Printf("[%d] %s: %s\n", event.Iteration, event.Type, event.Goal.Description)
+//	}
+func (a *AutonomousAgent) RunStream(ctx context.Context) (<-chan GoalEvent, error) {
+	if a.IsRunning() {
+		return nil, fmt.Errorf("autonomous agent %s is already running", a.name)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, contextError(ctx)
+	}
+
+	events := make(chan GoalEvent)
+	emit := func(event GoalEvent) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		defer close(events)
+		result, err := a.run(ctx, emit)
+		emit(GoalEvent{Type: GoalEventRunEnded, Iteration: a.iterationCount, Result: result, Err: err})
+	}()
+	return events, nil
+}
+
+// run is Run, reporting each step to emit.
+func (a *AutonomousAgent) run(ctx context.Context, emit func(GoalEvent)) (*AutonomousResult, error) {
+	a.setRunning(true)
 	results := make([]string, 0)
+	stopReason := StopMaxIterations
 
-	for a.iterationCount < a.maxIterations && a.isRunning {
+	for a.iterationCount < a.maxIterations && a.IsRunning() {
 		// Check context cancellation
 		select {
 		case <-ctx.Done():
-			a.isRunning = false
-			return nil, ctx.Err()
+			a.setRunning(false)
+			return nil, contextError(ctx)
 		default:
 		}
 
+		// Hold here while paused
+		proceed, err := a.awaitTurn(ctx)
+		if err != nil {
+			a.setRunning(false)
+			return nil, err
+		}
+		if !proceed {
+			stopReason = StopStopped
+			break
+		}
+
 		// Get active goals
 		activeGoals := a.getActiveGoals()
 		if len(activeGoals) == 0 {
+			stopReason = StopCompleted
+			if a.countGoals(GoalStatusAbandoned) > 0 {
+				stopReason = StopBudgetExceeded
+			}
 			break
 		}
 
@@ -213,35 +439,73 @@ func (a *AutonomousAgent) Run(ctx context.Context) (*AutonomousResult, error) {
 
 		// Check stop condition (after increment to match TypeScript behavior)
 		if a.stopCondition != nil && a.stopCondition() {
+			stopReason = StopStopped
 			break
 		}
 
 		// Work on highest priority goal
 		goal := a.selectHighestPriorityGoal(activeGoals)
+		emit(newGoalEvent(GoalEventIteration, a.iterationCount, goal))
+		if goal.Iterations == 0 {
+			emit(newGoalEvent(GoalEventStarted, a.iterationCount, goal))
+		}
 		result, err := a.worker(ctx, goal)
-		if err != nil {
-			return nil, fmt.Errorf("work on goal failed: %w", err)
+		if err != nil && ctx.Err() != nil {
+			a.setRunning(false)
+			return nil, contextError(ctx)
 		}
 
-		results = append(results, result)
-
-		// Update progress
-		goal.Progress += 0.2
-		if goal.Progress >= 1.0 {
-			goal.Status = GoalStatusCompleted
+		if err == nil {
+			results = append(results, result)
+		}
+		a.updateGoalStatus(goal, result, err)
+
+		progress := newGoalEvent(GoalEventProgress, a.iterationCount, goal)
+		progress.Output = result
+		progress.Err = err
+		emit(progress)
+		switch goal.Status {
+		case GoalStatusCompleted:
+			emit(newGoalEvent(GoalEventCompleted, a.iterationCount, goal))
+		case GoalStatusAbandoned:
+			emit(newGoalEvent(GoalEventAbandoned, a.iterationCount, goal))
 		}
 	}
 
-	a.isRunning = false
+	// The loop also exits early when Stop is called mid-iteration
+	if stopReason == StopMaxIterations && a.iterationCount < a.maxIterations {
+		stopReason = StopStopped
+	}
+	a.setRunning(false)
 
 	return &AutonomousResult{
 		Objective:      a.objective,
 		Iterations:     a.iterationCount,
 		GoalsCompleted: a.countCompletedGoals(),
+		GoalsAbandoned: a.countGoals(GoalStatusAbandoned),
 		Results:        results,
+		StopReason:     stopReason,
 	}, nil
 }
 
+// updateGoalStatus completes goal if the worker succeeded and its
+// completion check passes, or abandons it once its iteration budget is
+// spent. A failed iteration counts against the budget like any other.
+func (a *AutonomousAgent) updateGoalStatus(goal *Goal, workerOutput string, workerErr error) {
+	goal.Iterations++
+
+	check := goal.CompletionCheck
+	if check == nil {
+		check = a.completion
+	}
+	switch {
+	case workerErr == nil && check(goal, workerOutput):
+		goal.Status = GoalStatusCompleted
+	case goal.MaxIterations > 0 && goal.Iterations >= goal.MaxIterations:
+		goal.Status = GoalStatusAbandoned
+	}
+}
+
 // getActiveGoals returns all active goals.
 func (a *AutonomousAgent) getActiveGoals() []*Goal {
 	active := make([]*Goal, 0)
@@ -271,18 +535,105 @@ func (a *AutonomousAgent) selectHighestPriorityGoal(goals []*Goal) *Goal {
 
 // countCompletedGoals counts completed goals.
 func (a *AutonomousAgent) countCompletedGoals() int {
+	return a.countGoals(GoalStatusCompleted)
+}
+
+// countGoals counts goals with the given status.
+func (a *AutonomousAgent) countGoals(status GoalStatus) int {
 	count := 0
 	for _, goal := range a.goals {
-		if goal.Status == GoalStatusCompleted {
+		if goal.Status == status {
 			count++
 		}
 	}
 	return count
 }
 
-// Stop stops the autonomous agent.
+// Stop stops the autonomous agent. A paused run stops without executing
+// another iteration.
 func (a *AutonomousAgent) Stop() {
-	a.isRunning = false
+	a.setRunning(false)
+}
+
+// Pause holds the run before its next iteration until Resume, Step or
+// Stop is called. The iteration in progress, if any, completes first.
+// Pausing before Run starts the run paused.
+func (a *AutonomousAgent) Pause() {
+	a.mu.Lock()
+	a.paused = true
+	a.mu.Unlock()
+	a.signalControl()
+}
+
+// Resume continues a paused run and discards pending steps.
+func (a *AutonomousAgent) Resume() {
+	a.mu.Lock()
+	a.paused = false
+	a.steps = 0
+	a.mu.Unlock()
+	a.signalControl()
+}
+
+// Step lets exactly one more iteration run and then pauses. Calling it on
+// a running agent pauses it after the next iteration. Calls accumulate:
+// three Steps allow three iterations.
+func (a *AutonomousAgent) Step() {
+	a.mu.Lock()
+	a.paused = true
+	a.steps++
+	a.mu.Unlock()
+	a.signalControl()
+}
+
+// IsPaused returns whether the agent is paused.
+func (a *AutonomousAgent) IsPaused() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.paused
+}
+
+// awaitTurn blocks while the agent is paused without a pending step. It
+// returns false if the agent was stopped, or the context error if ctx is
+// cancelled while waiting.
+func (a *AutonomousAgent) awaitTurn(ctx context.Context) (bool, error) {
+	for {
+		a.mu.Lock()
+		switch {
+		case !a.isRunning:
+			a.mu.Unlock()
+			return false, nil
+		case !a.paused:
+			a.mu.Unlock()
+			return true, nil
+		case a.steps > 0:
+			a.steps--
+			a.mu.Unlock()
+			return true, nil
+		}
+		a.mu.Unlock()
+
+		select {
+		case <-a.control:
+		case <-ctx.Done():
+			return false, contextError(ctx)
+		}
+	}
+}
+
+// setRunning updates the running state and wakes a paused run.
+func (a *AutonomousAgent) setRunning(running bool) {
+	a.mu.Lock()
+	a.isRunning = running
+	a.mu.Unlock()
+	a.signalControl()
+}
+
+// signalControl wakes a paused run to re-check the control state.
+func (a *AutonomousAgent) signalControl() {
+	select {
+	case a.control <- struct{}{}:
+	default:
+	}
 }
 
 // GetProgress returns overall progress as a percentage (0-100).
@@ -321,5 +672,7 @@ func (a *AutonomousAgent) GetIterationCount() int {
 
 // IsRunning returns whether the agent is currently running.
 func (a *AutonomousAgent) IsRunning() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	return a.isRunning
 }
@@ -8,6 +8,8 @@ import (
 	"testing"
 	"time"
 
+	"go.uber.org/goleak"
+
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
 
@@ -155,6 +157,7 @@ func TestAutonomousAgent_Run_NoGoals(t *testing.T) {
 
 func TestAutonomousAgent_Run_SingleGoal(t *testing.T) {
 	agent := NewAutonomousAgent("Test", 10)
+	agent.SetCompletionCheck(CompleteAfter(5))
 	agent.AddGoal("Test goal", 1)
 
 	result, err := agent.Run(context.Background())
@@ -163,7 +166,7 @@ func TestAutonomousAgent_Run_SingleGoal(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	// With progress increment of 0.2 per iteration, needs 5 iterations to complete
+	// CompleteAfter(5) advances progress by 0.2 per iteration, so 5 iterations complete the goal
 	if result.Iterations != 5 {
 		t.Errorf("expected 5 iterations to complete goal, got %d", result.Iterations)
 	}
@@ -179,6 +182,7 @@ func TestAutonomousAgent_Run_SingleGoal(t *testing.T) {
 
 func TestAutonomousAgent_Run_MultipleGoals(t *testing.T) {
 	agent := NewAutonomousAgent("Test", 20)
+	agent.SetCompletionCheck(CompleteAfter(5))
 	agent.AddGoal("Low priority", 1)
 	agent.AddGoal("High priority", 10)
 
@@ -201,6 +205,7 @@ func TestAutonomousAgent_Run_MultipleGoals(t *testing.T) {
 
 func TestAutonomousAgent_Run_MaxIterationsReached(t *testing.T) {
 	agent := NewAutonomousAgent("Test", 3)
+	agent.SetCompletionCheck(CompleteAfter(5))
 	agent.AddGoal("Test goal", 1)
 
 	result, err := agent.Run(context.Background())
@@ -222,6 +227,7 @@ func TestAutonomousAgent_Run_MaxIterationsReached(t *testing.T) {
 
 func TestAutonomousAgent_Run_WithStopCondition(t *testing.T) {
 	agent := NewAutonomousAgent("Test", 10)
+	agent.SetCompletionCheck(CompleteAfter(5))
 	agent.AddGoal("Test goal", 1)
 
 	iterationLimit := 0
@@ -243,6 +249,7 @@ func TestAutonomousAgent_Run_WithStopCondition(t *testing.T) {
 
 func TestAutonomousAgent_Run_WithCustomWorker(t *testing.T) {
 	agent := NewAutonomousAgent("Test", 5)
+	agent.SetCompletionCheck(CompleteAfter(5))
 	agent.AddGoal("Test goal", 1)
 
 	callCount := 0
@@ -268,20 +275,48 @@ func TestAutonomousAgent_Run_WithCustomWorker(t *testing.T) {
 
 func TestAutonomousAgent_Run_WorkerError(t *testing.T) {
 	agent := NewAutonomousAgent("Test", 5)
-	agent.AddGoal("Test goal", 1)
+	goal := agent.AddGoalWithOptions("Test goal", 1, WithGoalMaxIterations(2))
 
 	agent.SetWorker(func(ctx context.Context, goal *Goal) (string, error) {
 		return "", errors.New("worker failed")
 	})
 
-	_, err := agent.Run(context.Background())
+	result, err := agent.Run(context.Background())
+	if err != nil {
+		t.Fatalf("a failing worker should not abort the run: %v", err)
+	}
+
+	if goal.Status != GoalStatusAbandoned || goal.Iterations != 2 {
+		t.Errorf("expected the goal abandoned after 2 failed iterations, got %s after %d", goal.Status, goal.Iterations)
+	}
+	if result.StopReason != StopBudgetExceeded || len(result.Results) != 0 {
+		t.Errorf("expected StopBudgetExceeded with no results, got %s with %v", result.StopReason, result.Results)
+	}
+}
+
+func TestAutonomousAgent_Run_WorkerErrorThenSuccess(t *testing.T) {
+	agent := NewAutonomousAgent("Test", 5)
+	goal := agent.AddGoalWithOptions("Test goal", 1, WithGoalMaxIterations(3))
+
+	calls := 0
+	agent.SetWorker(func(ctx context.Context, goal *Goal) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errors.New("transient failure")
+		}
+		return "done", nil
+	})
 
-	if err == nil {
-		t.Fatal("expected error when worker fails")
+	result, err := agent.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if !strings.Contains(err.Error(), "work on goal failed") {
-		t.Errorf("expected 'work on goal failed' error, got: %v", err)
+	if goal.Status != GoalStatusCompleted || goal.Iterations != 2 {
+		t.Errorf("expected the goal completed on its second iteration, got %s after %d", goal.Status, goal.Iterations)
+	}
+	if result.StopReason != StopCompleted {
+		t.Errorf("expected StopCompleted, got %s", result.StopReason)
 	}
 }
 
@@ -299,13 +334,14 @@ func TestAutonomousAgent_Run_ContextCancellation(t *testing.T) {
 
 	_, err := agent.Run(ctx)
 
-	if err != context.Canceled {
+	if !errors.Is(err, ErrCanceled) {
 		t.Errorf("expected context.Canceled error, got %v", err)
 	}
 }
 
 func TestAutonomousAgent_Stop(t *testing.T) {
 	agent := NewAutonomousAgent("Test", 100)
+	agent.SetCompletionCheck(CompleteAfter(5))
 	agent.AddGoal("Test goal", 1)
 
 	// Set worker that stops agent after 2 iterations
@@ -378,6 +414,7 @@ func TestAutonomousAgent_GetGoals(t *testing.T) {
 
 func TestAutonomousAgent_GoalPrioritySelection(t *testing.T) {
 	agent := NewAutonomousAgent("Test", 10)
+	agent.SetCompletionCheck(CompleteAfter(5))
 
 	// Add goals in random priority order
 	agent.AddGoal("Priority 3", 3)
@@ -442,6 +479,7 @@ func TestAutonomousAgent_GoalStatusTransitions(t *testing.T) {
 
 func TestAutonomousAgent_RealWorldScenario(t *testing.T) {
 	agent := NewAutonomousAgent("Complete research project", 20)
+	agent.SetCompletionCheck(CompleteAfter(5))
 
 	// Add goals with different priorities
 	agent.AddGoal("Literature review", 10)
@@ -501,3 +539,231 @@ func TestAutonomousAgent_WithTimeBasedStopCondition(t *testing.T) {
 		t.Errorf("expected early stop due to time condition, got %d iterations", result.Iterations)
 	}
 }
+
+// steppedAgent returns a paused agent whose worker reports each iteration.
+func steppedAgent(maxIterations int) (*AutonomousAgent, chan int) {
+	agent := NewAutonomousAgent("Test", maxIterations)
+	agent.SetCompletionCheck(CompleteAfter(5))
+	agent.AddGoal("Test goal", 1)
+
+	iterations := make(chan int, maxIterations)
+	count := 0
+	agent.SetWorker(func(ctx context.Context, goal *Goal) (string, error) {
+		count++
+		iterations <- count
+		return "work", nil
+	})
+	agent.Pause()
+	return agent, iterations
+}
+
+func expectNoIteration(t *testing.T, iterations chan int) {
+	t.Helper()
+	select {
+	case n := <-iterations:
+		t.Fatalf("expected paused agent to hold, but iteration %d ran", n)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestAutonomousAgent_PauseStepResume(t *testing.T) {
+	agent, iterations := steppedAgent(4)
+
+	done := make(chan *AutonomousResult)
+	go func() {
+		result, _ := agent.Run(context.Background())
+		done <- result
+	}()
+
+	expectNoIteration(t, iterations)
+
+	agent.Step()
+	if n := <-iterations; n != 1 {
+		t.Fatalf("expected iteration 1, got %d", n)
+	}
+	expectNoIteration(t, iterations)
+	if !agent.IsPaused() || !agent.IsRunning() {
+		t.Error("expected agent to stay paused and running after a step")
+	}
+
+	agent.Step()
+	agent.Step()
+	<-iterations
+	<-iterations
+	expectNoIteration(t, iterations)
+
+	agent.Resume()
+	result := <-done
+	if result.Iterations != 4 {
+		t.Errorf("expected 4 iterations, got %d", result.Iterations)
+	}
+	if agent.IsPaused() {
+		t.Error("expected agent not paused after resume")
+	}
+}
+
+func TestAutonomousAgent_StopWhilePaused(t *testing.T) {
+	agent, iterations := steppedAgent(10)
+
+	done := make(chan *AutonomousResult)
+	go func() {
+		result, _ := agent.Run(context.Background())
+		done <- result
+	}()
+
+	agent.Step()
+	<-iterations
+	agent.Stop()
+
+	select {
+	case result := <-done:
+		if result.Iterations != 1 {
+			t.Errorf("expected 1 iteration before stop, got %d", result.Iterations)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Stop to end a paused run")
+	}
+}
+
+func TestAutonomousAgent_CancelWhilePaused(t *testing.T) {
+	agent, _ := steppedAgent(10)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := agent.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if agent.IsRunning() {
+		t.Error("expected agent not running after cancellation")
+	}
+}
+
+func TestAutonomousAgent_CompletionCheck(t *testing.T) {
+	agent := NewAutonomousAgent("Test", 10)
+	attempts := 0
+	agent.SetWorker(func(ctx context.Context, goal *Goal) (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "FAIL", nil
+		}
+		return "PASS", nil
+	})
+
+	goal := agent.AddGoalWithOptions("Fix tests", 1,
+		WithCompletionCheck(func(goal *Goal, output string) bool {
+			return output == "PASS"
+		}),
+	)
+	fast := agent.AddGoalWithOptions("Quick lookup", 0, WithCompletionCheck(CompleteOnSuccess))
+
+	result, err := agent.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if goal.Status != GoalStatusCompleted || goal.Iterations != 3 {
+		t.Errorf("expected goal completed after 3 iterations, got %s after %d", goal.Status, goal.Iterations)
+	}
+	if fast.Status != GoalStatusCompleted || fast.Iterations != 1 || fast.Progress != 1.0 {
+		t.Errorf("expected quick goal completed after 1 iteration, got %+v", fast)
+	}
+	if result.Iterations != 4 || result.GoalsCompleted != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestAutonomousAgent_GoalMaxIterationsAbandons(t *testing.T) {
+	agent := NewAutonomousAgent("Test", 20)
+	agent.SetCompletionCheck(func(goal *Goal, output string) bool { return false })
+
+	stuck := agent.AddGoalWithOptions("Unreachable", 10, WithGoalMaxIterations(3))
+	next := agent.AddGoalWithOptions("Also unreachable", 1, WithGoalMaxIterations(2))
+
+	result, err := agent.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stuck.Status != GoalStatusAbandoned || stuck.Iterations != 3 {
+		t.Errorf("expected goal abandoned after 3 iterations, got %s after %d", stuck.Status, stuck.Iterations)
+	}
+	if next.Status != GoalStatusAbandoned || next.Iterations != 2 {
+		t.Errorf("expected second goal abandoned after 2 iterations, got %s after %d", next.Status, next.Iterations)
+	}
+	if result.Iterations != 5 || result.GoalsAbandoned != 2 || result.GoalsCompleted != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestAutonomousAgent_RunStream(t *testing.T) {
+	agent := NewAutonomousAgent("Stream", 10)
+	agent.AddGoalWithOptions("Goal", 1, WithGoalMaxIterations(2),
+		WithCompletionCheck(func(goal *Goal, output string) bool { return false }))
+	agent.AddGoalWithOptions("Quick", 0, WithCompletionCheck(CompleteOnSuccess))
+
+	events, err := agent.RunStream(context.Background())
+	if err != nil {
+		t.Fatalf("RunStream: %v", err)
+	}
+
+	var types []string
+	var last GoalEvent
+	for event := range events {
+		if event.Goal != nil {
+			types = append(types, fmt.Sprintf("%s:%s", event.Type, event.Goal.Description))
+		} else {
+			types = append(types, string(event.Type))
+		}
+		last = event
+	}
+
+	want := []string{
+		"iteration:Goal", "goal_started:Goal", "progress:Goal",
+		"iteration:Goal", "progress:Goal", "goal_abandoned:Goal",
+		"iteration:Quick", "goal_started:Quick", "progress:Quick", "goal_completed:Quick",
+		"run_ended",
+	}
+	if strings.Join(types, " ") != strings.Join(want, " ") {
+		t.Errorf("events =\n%v\nwant\n%v", types, want)
+	}
+	if last.Err != nil || last.Result == nil || last.Result.GoalsCompleted != 1 || last.Result.GoalsAbandoned != 1 {
+		t.Errorf("unexpected final event: %+v", last)
+	}
+}
+
+func TestAutonomousAgent_RunStreamCancel(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	agent := NewAutonomousAgent("Monitor", 1000)
+	agent.AddGoalWithOptions("Watch", 1, WithCompletionCheck(func(goal *Goal, output string) bool { return false }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := agent.RunStream(ctx)
+	if err != nil {
+		t.Fatalf("RunStream: %v", err)
+	}
+
+	// Read a few events, then walk away without draining
+	for i := 0; i < 3; i++ {
+		<-events
+	}
+	cancel()
+
+	deadline := time.After(time.Second)
+	for agent.IsRunning() {
+		select {
+		case <-deadline:
+			t.Fatal("run did not stop after cancellation")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestAutonomousAgent_RunStreamCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewAutonomousAgent("Stream", 1).RunStream(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Best-of-N pattern samples the same agent N times concurrently and returns
+// the highest-scoring response.
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ScoreFunc rates a candidate response; higher is better.
+type ScoreFunc func(candidate *agenkit.Message) float64
+
+// ConfidenceScore is the default ScoreFunc: the candidate's "confidence"
+// metadata, or 0 if it has none.
+func ConfidenceScore(candidate *agenkit.Message) float64 {
+	score, _ := metadataFloat(candidate.Metadata, "confidence")
+	return score
+}
+
+// BestOfNAgent improves quality through redundancy: it samples the same
+// agent N times concurrently (relying on its temperature for variety) and
+// returns the best candidate.
+//
+// By default the best candidate is the highest-scoring one. With
+// WithMajority it is instead the candidate most others agree with
+// (self-consistency), for tasks whose correct outputs should converge.
+// Unlike ParallelAgent, which ensembles different agents, every candidate
+// comes from the same agent.
+//
+// Each sample receives a copy of the input with metadata "sample_index"
+// (0 to N-1). Samples that fail are skipped; Process fails only if all do.
+//
+// Example:
+//
+//	best, _ := patterns.NewBestOfN(llmAgent, 5, func(m *agenkit.Message) float64 {
+//	    return float64(len(m.ContentString())) // prefer detailed answers
+//	})
+//	result, err := best.Process(ctx, message)
+//	fmt.Println(result.Metadata["candidate_scores"])
+type BestOfNAgent struct {
+	name      string
+	agent     agenkit.Agent
+	n         int
+	scorer    ScoreFunc
+	similar   SimilarityFunc
+	threshold float64
+	group     *WorkGroup
+
+	lazy lazyInit
+}
+
+// NewBestOfN creates a best-of-N agent.
+//
+// Parameters:
+//   - agent: The agent to sample
+//   - n: How many samples to take (must be at least 1)
+//   - scorer: Rates candidates (default: ConfidenceScore)
+func NewBestOfN(agent agenkit.Agent, n int, scorer ScoreFunc) (*BestOfNAgent, error) {
+	if agent == nil {
+		return nil, fmt.Errorf("agent is required")
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("n must be at least 1, got %d", n)
+	}
+	if scorer == nil {
+		scorer = ConfidenceScore
+	}
+	return &BestOfNAgent{
+		name:   "BestOfN",
+		agent:  agent,
+		n:      n,
+		scorer: scorer,
+	}, nil
+}
+
+// WithMajority selects the candidate that agrees with the most other
+// candidates, where two candidates agree if similarity (default:
+// WordOverlapSimilarity) rates them at or above threshold. Ties go to the
+// higher-scoring candidate. Returns the agent for chaining.
+func (b *BestOfNAgent) WithMajority(similarity SimilarityFunc, threshold float64) *BestOfNAgent {
+	if similarity == nil {
+		similarity = WordOverlapSimilarity
+	}
+	b.similar = similarity
+	b.threshold = threshold
+	return b
+}
+
+// WithWorkGroup runs samples under group, so a graceful shutdown waits for
+// them, and returns the agent for chaining.
+func (b *BestOfNAgent) WithWorkGroup(group *WorkGroup) *BestOfNAgent {
+	b.group = group
+	return b
+}
+
+// Name returns the agent's identifier.
+func (b *BestOfNAgent) Name() string {
+	return b.name
+}
+
+// Capabilities returns the sampled agent's capabilities.
+func (b *BestOfNAgent) Capabilities() []string {
+	return append(append([]string(nil), b.agent.Capabilities()...), "best_of_n")
+}
+
+// Introspect returns introspection information for the agent.
+func (b *BestOfNAgent) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    b.Name(),
+		Capabilities: b.Capabilities(),
+	}
+}
+
+// bestOfNSample is one candidate, or the error that prevented it.
+type bestOfNSample struct {
+	index   int
+	message *agenkit.Message
+	err     error
+}
+
+// Process samples the agent N times and returns the best candidate.
+//
+// The result includes metadata "candidates" (a []*agenkit.Message holding
+// copies of the successful candidates, in sample order), "candidate_scores"
+// (their scores, in the same order), "selected_index" (the winner's sample
+// index), "selection" ("score" or "majority") and "failed_samples". In
+// majority mode it also includes "majority_votes", the number of other
+// candidates agreeing with the winner.
+//
+// Returns an *AllAgentsFailedError if every sample fails.
+func (b *BestOfNAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := b.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	samples := make(chan bestOfNSample, b.n)
+	for i := 0; i < b.n; i++ {
+		index := i
+		input := copyMessage(message)
+		input.Metadata["sample_index"] = index
+		err := startWork(ctx, b.group, b.agent.Name(), func(ctx context.Context) error {
+			result, err := ProcessTraced(ctx, b.agent, input)
+			samples <- bestOfNSample{index: index, message: result, err: err}
+			return err
+		})
+		if err != nil {
+			samples <- bestOfNSample{index: index, err: err}
+		}
+	}
+
+	logger := Logger().With(slog.String("pattern", b.name), slog.String("agent", b.agent.Name()))
+	ordered := make([]*agenkit.Message, b.n)
+	failed := &AllAgentsFailedError{}
+	for received := 0; received < b.n; received++ {
+		select {
+		case s := <-samples:
+			if s.err == nil && s.message == nil {
+				s.err = fmt.Errorf("sample %d returned no message", s.index)
+			}
+			if s.err != nil {
+				logger.WarnContext(ctx, LogEventAgentError, slog.Int("sample", s.index), slog.Any("error", s.err))
+				failed.Agents = append(failed.Agents, b.agent.Name())
+				failed.Errors = append(failed.Errors, s.err)
+				continue
+			}
+			ordered[s.index] = s.message
+		case <-ctx.Done():
+			return nil, fmt.Errorf("best-of-n sampling cancelled: %w", contextError(ctx))
+		}
+	}
+
+	indexes := make([]int, 0, b.n)
+	candidates := make([]*agenkit.Message, 0, b.n)
+	for i, candidate := range ordered {
+		if candidate != nil {
+			indexes = append(indexes, i)
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, failed
+	}
+
+	scores := make([]float64, len(candidates))
+	for i, candidate := range candidates {
+		scores[i] = b.scorer(candidate)
+	}
+
+	best, selection := 0, "score"
+	var votes []int
+	if b.similar != nil {
+		selection = "majority"
+		votes = b.agreement(candidates)
+		for i := range candidates {
+			if votes[i] > votes[best] || (votes[i] == votes[best] && scores[i] > scores[best]) {
+				best = i
+			}
+		}
+	} else {
+		for i := range candidates {
+			if scores[i] > scores[best] {
+				best = i
+			}
+		}
+	}
+
+	copies := make([]*agenkit.Message, len(candidates))
+	for i, candidate := range candidates {
+		copies[i] = copyMessage(candidate)
+	}
+
+	result := copyMessage(candidates[best])
+	result.Metadata["candidates"] = copies
+	result.Metadata["candidate_scores"] = scores
+	result.Metadata["selected_index"] = indexes[best]
+	result.Metadata["selection"] = selection
+	result.Metadata["failed_samples"] = len(failed.Errors)
+	if votes != nil {
+		result.Metadata["majority_votes"] = votes[best]
+	}
+	return result, nil
+}
+
+// agreement counts, for each candidate, how many others it agrees with.
+func (b *BestOfNAgent) agreement(candidates []*agenkit.Message) []int {
+	votes := make([]int, len(candidates))
+	for i := range candidates {
+		for j := i + 1; j < len(candidates); j++ {
+			if b.similar(candidates[i], candidates[j]) >= b.threshold {
+				votes[i]++
+				votes[j]++
+			}
+		}
+	}
+	return votes
+}
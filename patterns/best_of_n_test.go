@@ -0,0 +1,107 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// samplingAgent answers with responses[sample_index].
+func samplingAgent(responses ...string) *extendedMockAgent {
+	return &extendedMockAgent{
+		name: "sampler",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			index, _ := msg.Metadata["sample_index"].(int)
+			if responses[index] == "" {
+				return nil, fmt.Errorf("sample %d failed", index)
+			}
+			return agenkit.NewMessage("assistant", responses[index]), nil
+		},
+	}
+}
+
+func TestBestOfN_SelectsHighestScore(t *testing.T) {
+	best, err := NewBestOfN(samplingAgent("short", "the longest answer", "medium one"), 3, func(m *agenkit.Message) float64 {
+		return float64(len(m.ContentString()))
+	})
+	if err != nil {
+		t.Fatalf("NewBestOfN: %v", err)
+	}
+
+	input := agenkit.NewMessage("user", "question")
+	result, err := best.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != "the longest answer" {
+		t.Errorf("content = %q, want the longest answer", result.Content)
+	}
+	if result.Metadata["selected_index"] != 1 || result.Metadata["selection"] != "score" {
+		t.Errorf("metadata = %v", result.Metadata)
+	}
+	candidates, _ := result.Metadata["candidates"].([]*agenkit.Message)
+	scores, _ := result.Metadata["candidate_scores"].([]float64)
+	if len(candidates) != 3 || len(scores) != 3 || scores[0] != 5 {
+		t.Errorf("got %d candidates with scores %v", len(candidates), scores)
+	}
+	if result.ParentID != input.ID {
+		t.Errorf("ParentID = %q, want the input's ID", result.ParentID)
+	}
+}
+
+func TestBestOfN_Majority(t *testing.T) {
+	best, _ := NewBestOfN(samplingAgent("the answer is 42", "the answer is 41", "the answer is 42", "the answer is 42"), 4, nil)
+	best.WithMajority(func(a, b *agenkit.Message) float64 {
+		if a.ContentString() == b.ContentString() {
+			return 1
+		}
+		return 0
+	}, 1)
+
+	result, err := best.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != "the answer is 42" || result.Metadata["majority_votes"] != 2 {
+		t.Errorf("got %q with %v votes, want 42 with 2", result.Content, result.Metadata["majority_votes"])
+	}
+	if result.Metadata["selection"] != "majority" {
+		t.Errorf("selection = %v, want majority", result.Metadata["selection"])
+	}
+}
+
+func TestBestOfN_SkipsFailedSamples(t *testing.T) {
+	best, _ := NewBestOfN(samplingAgent("", "ok", ""), 3, nil)
+	result, err := best.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != "ok" || result.Metadata["failed_samples"] != 2 || result.Metadata["selected_index"] != 1 {
+		t.Errorf("result = %q, metadata %v", result.Content, result.Metadata)
+	}
+}
+
+func TestBestOfN_AllFail(t *testing.T) {
+	best, _ := NewBestOfN(samplingAgent("", ""), 2, nil)
+	_, err := best.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	var allFailed *AllAgentsFailedError
+	if !errors.As(err, &allFailed) || len(allFailed.Errors) != 2 {
+		t.Fatalf("expected AllAgentsFailedError with 2 errors, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "failed") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestNewBestOfN_Validation(t *testing.T) {
+	if _, err := NewBestOfN(nil, 3, nil); err == nil {
+		t.Error("expected error for nil agent")
+	}
+	if _, err := NewBestOfN(samplingAgent("a"), 0, nil); err == nil {
+		t.Error("expected error for n < 1")
+	}
+}
@@ -0,0 +1,206 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Caching pattern reuses an agent's response for identical inputs instead
+// of recomputing it.
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+	"github.com/scttfrdmn/agenkit-go/middleware"
+)
+
+// DefaultCacheTTL is how long a CachingAgent keeps responses by default.
+const DefaultCacheTTL = 5 * time.Minute
+
+// CacheKeyFunc derives the cache key for an input message. Messages with
+// the same key are treated as identical requests.
+type CacheKeyFunc func(message *agenkit.Message) string
+
+// CachingAgent wraps an agent, reusing its response for identical inputs
+// (retries, duplicate submissions) instead of recomputing them.
+//
+// It is a middleware.CachingDecorator around the agent: responses are
+// cached by key with a TTL in a middleware.CacheStore (an in-process LRU by
+// default), and concurrent requests with the same key share one call to
+// the agent. Responses served without calling the agent, from the cache or
+// a shared call, carry "cache_hit": true in metadata. Errors are not
+// cached.
+//
+// Only wrap agents whose response depends solely on the input message.
+// Stateful agents, such as a ConversationalAgent, must not be cached.
+//
+// Example:
+//
+//	cached, err := patterns.NewCachingAgent(summarizer, middleware.NewMemoryCacheStore(500), nil)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	cached.WithTTL(time.Hour)
+type CachingAgent struct {
+	agent     agenkit.Agent
+	cache     middleware.CacheStore
+	keyFn     CacheKeyFunc
+	decorator *middleware.CachingDecorator
+
+	lazy lazyInit
+}
+
+// CacheStats counts how a CachingAgent served requests.
+type CacheStats struct {
+	// Hits were served from the cache
+	Hits int64
+	// Shared waited for an identical in-flight request
+	Shared int64
+	// Misses called the wrapped agent
+	Misses int64
+}
+
+// tracedAgent calls its agent through ProcessTraced, so calls made by a
+// middleware decorator are recorded in the trace.
+type tracedAgent struct {
+	agenkit.Agent
+}
+
+// Process calls the agent through ProcessTraced.
+func (t tracedAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	return ProcessTraced(ctx, t.Agent, message)
+}
+
+// NewCachingAgent creates a caching wrapper around agent. A nil cache uses
+// a middleware.MemoryCacheStore of 1000 entries; a nil keyFn uses
+// middleware.DefaultCacheKey, a hash of the message role, content and
+// metadata.
+//
+// Returns an error if agent is nil.
+func NewCachingAgent(agent agenkit.Agent, cache middleware.CacheStore, keyFn CacheKeyFunc) (*CachingAgent, error) {
+	if agent == nil {
+		return nil, fmt.Errorf("agent is required")
+	}
+	if cache == nil {
+		cache = middleware.NewMemoryCacheStore(1000)
+	}
+	if keyFn == nil {
+		keyFn = middleware.DefaultCacheKey
+	}
+
+	c := &CachingAgent{
+		agent: agent,
+		cache: cache,
+		keyFn: keyFn,
+	}
+	if err := c.setTTL(DefaultCacheTTL); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// setTTL rebuilds the decorator with the given TTL.
+func (c *CachingAgent) setTTL(ttl time.Duration) error {
+	decorator, err := middleware.NewCachingDecorator(tracedAgent{c.agent}, middleware.CachingConfig{
+		DefaultTTL:   ttl,
+		KeyGenerator: c.keyFn,
+		Store:        c.cache,
+	})
+	if err != nil {
+		return err
+	}
+	c.decorator = decorator
+	return nil
+}
+
+// WithTTL sets how long responses stay cached (values <= 0 restore
+// DefaultCacheTTL) and returns the agent for chaining. Call it before the
+// agent is used; it resets Stats.
+func (c *CachingAgent) WithTTL(ttl time.Duration) *CachingAgent {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	// Cannot fail: the TTL is positive and a store is set
+	_ = c.setTTL(ttl)
+	return c
+}
+
+// Name returns the name of the wrapped agent.
+func (c *CachingAgent) Name() string {
+	return c.agent.Name()
+}
+
+// Capabilities returns the capabilities of the wrapped agent.
+func (c *CachingAgent) Capabilities() []string {
+	return c.agent.Capabilities()
+}
+
+// Introspect returns introspection information for the wrapped agent.
+func (c *CachingAgent) Introspect() *agenkit.IntrospectionResult {
+	return c.agent.Introspect()
+}
+
+// Stats returns a snapshot of the request counts.
+func (c *CachingAgent) Stats() CacheStats {
+	stats := c.decorator.Metrics().GetStats()
+	return CacheStats{
+		Hits:   stats["cache_hits"].(int64),
+		Shared: stats["shared"].(int64),
+		Misses: stats["cache_misses"].(int64),
+	}
+}
+
+// Invalidate removes the cached response for message.
+func (c *CachingAgent) Invalidate(message *agenkit.Message) {
+	c.decorator.Invalidate(message)
+}
+
+// Process returns the cached response for an identical input, waits for an
+// identical in-flight request, or calls the wrapped agent.
+//
+// If the request being waited on is cancelled while this one's context is
+// still live, this request computes the response itself.
+func (c *CachingAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	response, cached, err := c.decorator.ProcessCached(ctx, message)
+	if err != nil {
+		if ctxErr := contextError(ctx); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	if cached {
+		return cacheHit(response), nil
+	}
+	// The cache keeps the agent's response, so the caller gets a copy it
+	// can modify
+	return copyMessage(response), nil
+}
+
+// cacheHit returns a copy of a shared response for another request, with
+// its own ID and metadata marked as a cache hit.
+func cacheHit(response *agenkit.Message) *agenkit.Message {
+	hit := copyMessage(response)
+	hit.ID = ""
+	hit.ParentID = ""
+	hit.EnsureID()
+	hit.Metadata["cache_hit"] = true
+	return hit
+}
+
+// copyMessage copies a message and its metadata map.
+func copyMessage(message *agenkit.Message) *agenkit.Message {
+	c := *message
+	c.Metadata = make(map[string]interface{}, len(message.Metadata)+1)
+	for key, value := range message.Metadata {
+		c.Metadata[key] = value
+	}
+	return &c
+}
@@ -0,0 +1,188 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestCachingAgent_CachesIdenticalInputs(t *testing.T) {
+	var calls int32
+	inner := &extendedMockAgent{name: "summarizer", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		atomic.AddInt32(&calls, 1)
+		return agenkit.NewMessage("assistant", "summary of "+msg.ContentString()), nil
+	}}
+	cached, err := NewCachingAgent(inner, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	first, err := cached.Process(ctx, agenkit.NewMessage("user", "doc"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := first.Metadata["cache_hit"]; ok {
+		t.Error("expected computed response not to be marked as a hit")
+	}
+
+	input := agenkit.NewMessage("user", "doc")
+	second, err := cached.Process(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.ContentString() != "summary of doc" || second.Metadata["cache_hit"] != true {
+		t.Errorf("expected cached response marked as hit, got %q %v", second.ContentString(), second.Metadata)
+	}
+	if second.ID == first.ID || second.ParentID != input.ID {
+		t.Error("expected cache hit to be a new message linked to its own input")
+	}
+
+	if _, err := cached.Process(ctx, agenkit.NewMessage("user", "other doc")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 agent calls, got %d", calls)
+	}
+	if stats := cached.Stats(); stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	cached.Invalidate(agenkit.NewMessage("user", "doc"))
+	if _, err := cached.Process(ctx, agenkit.NewMessage("user", "doc")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected recompute after invalidation, got %d calls", calls)
+	}
+}
+
+func TestCachingAgent_SingleFlight(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	inner := &extendedMockAgent{name: "slow", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return agenkit.NewMessage("assistant", "done"), nil
+	}}
+	cached, _ := NewCachingAgent(inner, nil, nil)
+
+	const requests = 5
+	var wg sync.WaitGroup
+	results := make([]*agenkit.Message, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = cached.Process(context.Background(), agenkit.NewMessage("user", "same"))
+		}(i)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for cached.Stats().Shared < requests-1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("requests didn't join the in-flight call: %+v", cached.Stats())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected one computation, got %d", calls)
+	}
+	hits := 0
+	for _, result := range results {
+		if result == nil || result.ContentString() != "done" {
+			t.Fatalf("expected all requests to get the response, got %v", result)
+		}
+		if result.Metadata["cache_hit"] == true {
+			hits++
+		}
+	}
+	if hits != requests-1 {
+		t.Errorf("expected %d shared responses marked as hits, got %d", requests-1, hits)
+	}
+}
+
+func TestCachingAgent_ErrorsNotCached(t *testing.T) {
+	var calls int32
+	inner := &extendedMockAgent{name: "flaky", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return nil, errors.New("temporary failure")
+		}
+		return agenkit.NewMessage("assistant", "ok"), nil
+	}}
+	cached, _ := NewCachingAgent(inner, nil, func(msg *agenkit.Message) string { return msg.ContentString() })
+
+	if _, err := cached.Process(context.Background(), agenkit.NewMessage("user", "q")); err == nil {
+		t.Fatal("expected first call to fail")
+	}
+	result, err := cached.Process(context.Background(), agenkit.NewMessage("user", "q"))
+	if err != nil || result.ContentString() != "ok" {
+		t.Errorf("expected retry to reach the agent, got %v, %v", result, err)
+	}
+}
+
+func TestNewCachingAgent_RequiresAgent(t *testing.T) {
+	if _, err := NewCachingAgent(nil, nil, nil); err == nil {
+		t.Error("expected error for nil agent")
+	}
+}
+
+func TestCachingAgent_PanicReleasesWaiters(t *testing.T) {
+	release := make(chan struct{})
+	inner := &extendedMockAgent{name: "broken", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		<-release
+		panic("boom")
+	}}
+	cached, _ := NewCachingAgent(inner, nil, nil)
+
+	panicked := make(chan interface{}, 1)
+	go func() {
+		defer func() { panicked <- recover() }()
+		_, _ = cached.Process(context.Background(), agenkit.NewMessage("user", "same"))
+	}()
+	for cached.Stats().Misses < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiterErr := make(chan error, 1)
+	go func() {
+		result, err := cached.Process(context.Background(), agenkit.NewMessage("user", "same"))
+		if result != nil {
+			t.Errorf("expected no result, got %v", result)
+		}
+		waiterErr <- err
+	}()
+	for cached.Stats().Shared < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	if recovered := <-panicked; recovered != "boom" {
+		t.Errorf("expected panic to reach the computing caller, got %v", recovered)
+	}
+	if err := <-waiterErr; err == nil {
+		t.Error("expected waiter to get an error")
+	}
+}
+
+func TestCachingAgent_NilResponseIsError(t *testing.T) {
+	inner := &extendedMockAgent{name: "empty", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		return nil, nil
+	}}
+	cached, _ := NewCachingAgent(inner, nil, nil)
+
+	if _, err := cached.Process(context.Background(), agenkit.NewMessage("user", "q")); err == nil {
+		t.Error("expected error for nil response")
+	}
+	if cached.cache.Size() != 0 {
+		t.Error("expected nil response not to be cached")
+	}
+}
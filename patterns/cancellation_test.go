@@ -0,0 +1,155 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// blockingAgent blocks until its context is cancelled.
+func blockingAgent(name string, started *int32) *extendedMockAgent {
+	return &extendedMockAgent{
+		name: name,
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			if started != nil {
+				atomic.AddInt32(started, 1)
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+}
+
+// assertPromptCancellation cancels ctx shortly after Process starts and
+// checks that Process returns promptly with a context error and that no
+// goroutines are leaked.
+func assertPromptCancellation(t *testing.T, process func(ctx context.Context) error) {
+	t.Helper()
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := process(ctx)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected error after cancellation")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if elapsed > time.Second {
+		t.Errorf("expected prompt return after cancellation, took %v", elapsed)
+	}
+}
+
+func TestParallelAgent_CancellationNoLeak(t *testing.T) {
+	agent, err := NewParallelAgent([]agenkit.Agent{
+		blockingAgent("a", nil),
+		blockingAgent("b", nil),
+		blockingAgent("c", nil),
+	}, DefaultAggregators.First)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertPromptCancellation(t, func(ctx context.Context) error {
+		_, err := agent.Process(ctx, agenkit.NewMessage("user", "go"))
+		return err
+	})
+}
+
+func TestParallelPattern_CancellationNoLeak(t *testing.T) {
+	pattern, err := NewParallelPattern([]agenkit.Agent{
+		blockingAgent("a", nil),
+		blockingAgent("b", nil),
+	}, func(msgs []*agenkit.Message) *agenkit.Message { return msgs[0] }, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertPromptCancellation(t, func(ctx context.Context) error {
+		_, err := pattern.Process(ctx, agenkit.NewMessage("user", "go"))
+		return err
+	})
+}
+
+func TestParallelPattern_FailFastCancelsSiblings(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	failing := &extendedMockAgent{name: "failing", err: errors.New("boom")}
+	pattern, err := NewParallelPattern([]agenkit.Agent{
+		failing,
+		blockingAgent("slow", nil),
+	}, func(msgs []*agenkit.Message) *agenkit.Message { return msgs[0] }, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = pattern.Process(context.Background(), agenkit.NewMessage("user", "go"))
+	if err == nil || errors.Is(err, context.Canceled) {
+		t.Errorf("expected agent failure, got %v", err)
+	}
+}
+
+func TestParallelAgent_DoesNotLaunchWhenAlreadyCancelled(t *testing.T) {
+	var started int32
+	agent, err := NewParallelAgent([]agenkit.Agent{
+		blockingAgent("a", &started),
+		blockingAgent("b", &started),
+	}, DefaultAggregators.First)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = agent.Process(ctx, agenkit.NewMessage("user", "go"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if n := atomic.LoadInt32(&started); n != 0 {
+		t.Errorf("expected no agents launched after cancellation, got %d", n)
+	}
+}
+
+func TestSequentialPatterns_CancellationNoLeak(t *testing.T) {
+	sequential, err := NewSequentialAgent([]agenkit.Agent{blockingAgent("a", nil), blockingAgent("b", nil)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertPromptCancellation(t, func(ctx context.Context) error {
+		_, err := sequential.Process(ctx, agenkit.NewMessage("user", "go"))
+		return err
+	})
+
+	fallback, err := NewFallbackAgent([]agenkit.Agent{blockingAgent("a", nil), blockingAgent("b", nil)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertPromptCancellation(t, func(ctx context.Context) error {
+		_, err := fallback.Process(ctx, agenkit.NewMessage("user", "go"))
+		return err
+	})
+
+	collaborative, err := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:    []agenkit.Agent{blockingAgent("a", nil), blockingAgent("b", nil)},
+		MergeFunc: DefaultMergeFunc.First,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertPromptCancellation(t, func(ctx context.Context) error {
+		_, err := collaborative.Process(ctx, agenkit.NewMessage("user", "go"))
+		return err
+	})
+}
@@ -0,0 +1,254 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Capability router selects an agent by matching the capabilities a message
+// requires against the capabilities each agent advertises.
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// CapabilityMatcher returns the capabilities required to handle a message.
+type CapabilityMatcher func(message *agenkit.Message) []string
+
+// CapabilityScoreFunc scores a candidate agent for tie-breaking.
+//
+// It receives the candidate, the required capabilities, and the subset the
+// agent advertises. Higher scores win.
+type CapabilityScoreFunc func(agent agenkit.Agent, required []string, matched []string) float64
+
+// CapabilityRouter routes each message to the agent whose advertised
+// capabilities best cover the capabilities the message requires.
+//
+// Agents that implement agenkit.CapabilityScorer are ranked by their
+// proficiency, so an expert beats an agent that merely lists the same
+// capability. Without scores, coverage (the number of matched
+// capabilities) decides.
+//
+// Example:
+//
+//	router, _ := patterns.NewCapabilityRouter(
+//	    []agenkit.Agent{codeAgent, mathAgent, writerAgent},
+//	    func(msg *agenkit.Message) []string {
+//	        if strings.Contains(msg.ContentString(), "```") {
+//	            return []string{"code"}
+//	        }
+//	        return []string{"writing"}
+//	    },
+//	)
+//	result, _ := router.Process(ctx, message)
+type CapabilityRouter struct {
+	name    string
+	agents  []agenkit.Agent
+	matcher CapabilityMatcher
+	scorer  CapabilityScoreFunc
+
+	lazy lazyInit
+}
+
+// NewCapabilityRouter creates a new capability-based router.
+//
+// Parameters:
+//   - agents: Candidate agents (must have at least one)
+//   - matcher: Extracts required capabilities from a message
+func NewCapabilityRouter(agents []agenkit.Agent, matcher CapabilityMatcher) (*CapabilityRouter, error) {
+	if len(agents) == 0 {
+		return nil, fmt.Errorf("at least one agent is required")
+	}
+	if matcher == nil {
+		return nil, fmt.Errorf("capability matcher is required")
+	}
+
+	return &CapabilityRouter{
+		name:    "CapabilityRouter",
+		agents:  agents,
+		matcher: matcher,
+	}, nil
+}
+
+// WithScorer sets the tie-breaking score function and returns the router
+// for chaining.
+func (r *CapabilityRouter) WithScorer(scorer CapabilityScoreFunc) *CapabilityRouter {
+	r.scorer = scorer
+	return r
+}
+
+// Name returns the agent's identifier.
+func (r *CapabilityRouter) Name() string {
+	return r.name
+}
+
+// Capabilities returns the combined capabilities of all agents.
+func (r *CapabilityRouter) Capabilities() []string {
+	capMap := make(map[string]bool)
+	for _, agent := range r.agents {
+		for _, cap := range advertisedCapabilities(agent) {
+			capMap[cap] = true
+		}
+	}
+
+	capabilities := make([]string, 0, len(capMap))
+	for cap := range capMap {
+		capabilities = append(capabilities, cap)
+	}
+	capabilities = append(capabilities, "router", "capability-routing")
+
+	return capabilities
+}
+
+// CapabilityScores returns, for each capability, the highest proficiency
+// among the router's agents, so routers can be nested.
+func (r *CapabilityRouter) CapabilityScores() map[string]float64 {
+	scores := map[string]float64{"router": 1.0, "capability-routing": 1.0}
+	for _, agent := range r.agents {
+		for cap, score := range advertisedScores(agent) {
+			if score > scores[cap] {
+				scores[cap] = score
+			}
+		}
+	}
+	return scores
+}
+
+// Introspect returns introspection information for the router.
+func (r *CapabilityRouter) Introspect() *agenkit.IntrospectionResult {
+	names := make([]string, len(r.agents))
+	for i, agent := range r.agents {
+		names[i] = agent.Name()
+	}
+
+	return &agenkit.IntrospectionResult{
+		AgentName:    r.Name(),
+		Capabilities: r.Capabilities(),
+		InternalState: map[string]interface{}{
+			"agents":      names,
+			"has_scorer":  r.scorer != nil,
+			"agent_count": len(r.agents),
+		},
+	}
+}
+
+// Select returns the best agent for message along with the required and
+// matched capabilities.
+//
+// If the message requires no capabilities, the first agent is selected.
+// If no agent advertises any required capability, an error is returned.
+func (r *CapabilityRouter) Select(message *agenkit.Message) (agenkit.Agent, []string, []string, error) {
+	agent, required, matched, _, err := r.selectAgent(message)
+	return agent, required, matched, err
+}
+
+// selectAgent implements Select and also returns the winner's proficiency
+// sum over the required capabilities.
+func (r *CapabilityRouter) selectAgent(message *agenkit.Message) (agenkit.Agent, []string, []string, float64, error) {
+	required := r.matcher(message)
+	if len(required) == 0 {
+		return r.agents[0], required, []string{}, 0, nil
+	}
+
+	var best agenkit.Agent
+	var bestMatched []string
+	bestProficiency := 0.0
+	bestScore := 0.0
+
+	for _, agent := range r.agents {
+		proficiencies := advertisedScores(agent)
+
+		matched := make([]string, 0, len(required))
+		proficiency := 0.0
+		for _, cap := range required {
+			if p := proficiencies[cap]; p > 0 {
+				matched = append(matched, cap)
+				proficiency += p
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		score := 0.0
+		if r.scorer != nil {
+			score = r.scorer(agent, required, matched)
+		}
+
+		// Higher summed proficiency wins (with unscored agents this is the
+		// number of matched capabilities); equal proficiency falls to the
+		// scorer; remaining ties keep the earlier agent.
+		if best == nil || proficiency > bestProficiency ||
+			(proficiency == bestProficiency && score > bestScore) {
+			best = agent
+			bestMatched = matched
+			bestProficiency = proficiency
+			bestScore = score
+		}
+	}
+
+	if best == nil {
+		return nil, required, nil, 0, fmt.Errorf("no agent advertises required capabilities: %s",
+			strings.Join(required, ", "))
+	}
+
+	return best, required, bestMatched, bestProficiency, nil
+}
+
+// Process routes the message to the best-fit agent.
+//
+// The final message includes metadata about the routing decision.
+func (r *CapabilityRouter) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := r.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	agent, required, matched, proficiency, err := r.selectAgent(message)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := Logger().With(slog.String("pattern", r.name), slog.String("agent", agent.Name()))
+	logger.DebugContext(ctx, LogEventRoute, slog.Any("matched_capabilities", matched))
+	AnnotateTrace(ctx, fmt.Sprintf("matched %s on %s (score %.2f)", agent.Name(), strings.Join(matched, ", "), proficiency))
+	result, err := ProcessTraced(ctx, agent, message)
+	if err != nil {
+		logger.WarnContext(ctx, LogEventAgentError, slog.Any("error", err))
+		return nil, fmt.Errorf("agent '%s' failed: %w", agent.Name(), err)
+	}
+
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	result.Metadata["routed_agent"] = agent.Name()
+	result.Metadata["required_capabilities"] = required
+	result.Metadata["matched_capabilities"] = matched
+	result.Metadata["capability_score"] = proficiency
+
+	return result, nil
+}
+
+// advertisedCapabilities returns the capabilities an agent reports through
+// Introspect, falling back to Capabilities when introspection has none.
+func advertisedCapabilities(agent agenkit.Agent) []string {
+	if result := agent.Introspect(); result != nil && len(result.Capabilities) > 0 {
+		return result.Capabilities
+	}
+	return agent.Capabilities()
+}
+
+// advertisedScores returns an agent's proficiency per capability: its own
+// scores if it implements agenkit.CapabilityScorer, otherwise 1.0 for each
+// advertised capability.
+func advertisedScores(agent agenkit.Agent) map[string]float64 {
+	if _, ok := agent.(agenkit.CapabilityScorer); ok {
+		return agenkit.CapabilityScores(agent)
+	}
+	return agenkit.UniformCapabilityScores(advertisedCapabilities(agent))
+}
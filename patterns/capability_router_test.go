@@ -0,0 +1,199 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// keywordMatcher requires each capability whose name appears in the message.
+func keywordMatcher(capabilities ...string) CapabilityMatcher {
+	return func(msg *agenkit.Message) []string {
+		required := []string{}
+		for _, cap := range capabilities {
+			if strings.Contains(msg.ContentString(), cap) {
+				required = append(required, cap)
+			}
+		}
+		return required
+	}
+}
+
+func TestNewCapabilityRouter_Validation(t *testing.T) {
+	if _, err := NewCapabilityRouter(nil, keywordMatcher()); err == nil {
+		t.Error("expected error for no agents")
+	}
+	if _, err := NewCapabilityRouter([]agenkit.Agent{&extendedMockAgent{name: "a"}}, nil); err == nil {
+		t.Error("expected error for nil matcher")
+	}
+}
+
+func TestCapabilityRouter_RoutesToBestCoverage(t *testing.T) {
+	coder := &extendedMockAgent{name: "coder", response: "code", capabilities: []string{"code"}}
+	fullstack := &extendedMockAgent{name: "fullstack", response: "both", capabilities: []string{"code", "sql"}}
+	writer := &extendedMockAgent{name: "writer", response: "prose", capabilities: []string{"writing"}}
+
+	router, err := NewCapabilityRouter([]agenkit.Agent{coder, fullstack, writer}, keywordMatcher("code", "sql", "writing"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := router.Process(context.Background(), agenkit.NewMessage("user", "write code with sql"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["routed_agent"] != "fullstack" {
+		t.Errorf("expected fullstack agent, got %v", result.Metadata["routed_agent"])
+	}
+
+	result, err = router.Process(context.Background(), agenkit.NewMessage("user", "some writing please"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "prose" {
+		t.Errorf("expected writer response, got %s", result.ContentString())
+	}
+}
+
+func TestCapabilityRouter_TieBreaking(t *testing.T) {
+	cheap := &extendedMockAgent{name: "cheap", response: "cheap", capabilities: []string{"code"}}
+	premium := &extendedMockAgent{name: "premium", response: "premium", capabilities: []string{"code"}}
+
+	router, err := NewCapabilityRouter([]agenkit.Agent{cheap, premium}, keywordMatcher("code"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without a scorer, the earlier agent wins ties
+	agent, _, _, err := router.Select(agenkit.NewMessage("user", "code"))
+	if err != nil || agent.Name() != "cheap" {
+		t.Errorf("expected cheap agent on tie, got %v (err %v)", agent, err)
+	}
+
+	router.WithScorer(func(a agenkit.Agent, required, matched []string) float64 {
+		if a.Name() == "premium" {
+			return 1
+		}
+		return 0
+	})
+
+	agent, _, matched, err := router.Select(agenkit.NewMessage("user", "code"))
+	if err != nil || agent.Name() != "premium" {
+		t.Errorf("expected scorer to pick premium, got %v (err %v)", agent, err)
+	}
+	if len(matched) != 1 || matched[0] != "code" {
+		t.Errorf("expected matched [code], got %v", matched)
+	}
+}
+
+func TestCapabilityRouter_NoMatch(t *testing.T) {
+	coder := &extendedMockAgent{name: "coder", capabilities: []string{"code"}}
+
+	router, err := NewCapabilityRouter([]agenkit.Agent{coder}, func(*agenkit.Message) []string {
+		return []string{"translation"}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = router.Process(context.Background(), agenkit.NewMessage("user", "translate"))
+	if err == nil || !strings.Contains(err.Error(), "translation") {
+		t.Errorf("expected no-match error naming the capability, got %v", err)
+	}
+}
+
+func TestCapabilityRouter_NoRequirementsUsesFirstAgent(t *testing.T) {
+	first := &extendedMockAgent{name: "first", response: "first"}
+	second := &extendedMockAgent{name: "second", response: "second"}
+
+	router, err := NewCapabilityRouter([]agenkit.Agent{first, second}, keywordMatcher("code"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := router.Process(context.Background(), agenkit.NewMessage("user", "hello"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "first" {
+		t.Errorf("expected first agent, got %s", result.ContentString())
+	}
+}
+
+func TestCapabilityRouter_AgentError(t *testing.T) {
+	failing := &extendedMockAgent{name: "failing", capabilities: []string{"code"}, err: errors.New("boom")}
+
+	router, err := NewCapabilityRouter([]agenkit.Agent{failing}, keywordMatcher("code"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := router.Process(context.Background(), agenkit.NewMessage("user", "code")); err == nil {
+		t.Error("expected agent error to propagate")
+	}
+	if _, err := router.Process(context.Background(), nil); err == nil {
+		t.Error("expected error for nil message")
+	}
+}
+
+// scoredMockAgent advertises proficiency scores.
+type scoredMockAgent struct {
+	extendedMockAgent
+	scores map[string]float64
+}
+
+func (m *scoredMockAgent) CapabilityScores() map[string]float64 {
+	return m.scores
+}
+
+func TestCapabilityRouter_PrefersProficiency(t *testing.T) {
+	generalist := &extendedMockAgent{name: "generalist", response: "ok", capabilities: []string{"code", "writing"}}
+	novice := &scoredMockAgent{
+		extendedMockAgent: extendedMockAgent{name: "novice", response: "meh"},
+		scores:            map[string]float64{"code": 0.3},
+	}
+	expert := &scoredMockAgent{
+		extendedMockAgent: extendedMockAgent{name: "expert", response: "great"},
+		scores:            map[string]float64{"code": 1.5, "sql": 0.5, "writing": 0},
+	}
+
+	router, err := NewCapabilityRouter([]agenkit.Agent{generalist, novice, expert}, keywordMatcher("code", "sql", "writing"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := router.Process(context.Background(), agenkit.NewMessage("user", "code"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["routed_agent"] != "expert" {
+		t.Errorf("expected expert, got %v", result.Metadata["routed_agent"])
+	}
+	if result.Metadata["capability_score"] != 1.5 {
+		t.Errorf("expected capability_score 1.5, got %v", result.Metadata["capability_score"])
+	}
+
+	// Summed proficiency: generalist 2.0 (code + writing) beats expert 1.5,
+	// whose zero writing score doesn't count as a match
+	agent, _, matched, err := router.Select(agenkit.NewMessage("user", "code and writing"))
+	if err != nil || agent.Name() != "generalist" {
+		t.Errorf("expected generalist, got %v (err %v)", agent, err)
+	}
+	if len(matched) != 2 {
+		t.Errorf("expected 2 matched capabilities, got %v", matched)
+	}
+
+	// Expert's 1.5 + 0.5 beats generalist's 1.0
+	agent, _, matched, _ = router.Select(agenkit.NewMessage("user", "code with sql"))
+	if agent.Name() != "expert" || len(matched) != 2 {
+		t.Errorf("expected expert matching code and sql, got %s %v", agent.Name(), matched)
+	}
+
+	scores := router.CapabilityScores()
+	if scores["code"] != 1.5 || scores["writing"] != 1.0 || scores["router"] != 1.0 {
+		t.Errorf("unexpected router scores: %v", scores)
+	}
+}
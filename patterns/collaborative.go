@@ -19,6 +19,7 @@ package patterns
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
@@ -44,6 +45,24 @@ type ConsensusFunc func([]*agenkit.Message) bool
 //   - Best response selection
 type MergeFunc func([]*agenkit.Message) *agenkit.Message
 
+// SynthesizeFunc produces a final explanatory message from the full
+// collaboration history.
+//
+// rounds holds each round's responses in order; reached reports whether
+// consensus was detected. Use it to turn raw responses into a human-readable
+// decision summary, e.g. "Consensus reached after 2 rounds; security and
+// performance concerns resolved; testing approved".
+type SynthesizeFunc func(ctx context.Context, rounds [][]*agenkit.Message, reached bool) (*agenkit.Message, error)
+
+// RoundSelector chooses which agents participate in a round.
+//
+// round is zero-based; lastMessages holds the previous round's responses
+// (empty for round 0). Returning no agents after round 0 ends the
+// collaboration early with stop reason "no_participants". Use it to drop
+// agents that have already approved, or to bring in specialists only when
+// needed.
+type RoundSelector func(round int, lastMessages []*agenkit.Message) []agenkit.Agent
+
 // CollaborativeAgent enables peer collaboration with iterative refinement.
 //
 // Agents work together in rounds, each seeing previous responses and
@@ -65,6 +84,12 @@ type CollaborativeAgent struct {
 	maxRounds     int
 	consensusFunc ConsensusFunc
 	mergeFunc     MergeFunc
+	bestEffort    MergeFunc
+	synthesize    SynthesizeFunc
+	selector      RoundSelector
+	logger        *slog.Logger
+
+	lazy lazyInit
 }
 
 // CollaborativeConfig configures a CollaborativeAgent.
@@ -75,8 +100,20 @@ type CollaborativeConfig struct {
 	MaxRounds int
 	// ConsensusFunc detects agreement (optional)
 	ConsensusFunc ConsensusFunc
-	// MergeFunc combines responses (required)
+	// MergeFunc combines responses (required unless SynthesizeFunc is set)
 	MergeFunc MergeFunc
+	// BestEffortMergeFunc replaces MergeFunc when collaboration ends without
+	// consensus (optional). DefaultMergeFunc.BestEffort surfaces the points
+	// of disagreement instead of silently picking one response.
+	BestEffortMergeFunc MergeFunc
+	// SynthesizeFunc builds the final message from all rounds (optional).
+	// When set, it replaces MergeFunc for producing the final result.
+	SynthesizeFunc SynthesizeFunc
+	// RoundSelector picks the participating agents each round (optional).
+	// When unset, all agents participate in every round.
+	RoundSelector RoundSelector
+	// Logger receives structured round events (default: package logger)
+	Logger *slog.Logger
 }
 
 // NewCollaborativeAgent creates a new collaborative agent.
@@ -85,7 +122,8 @@ type CollaborativeConfig struct {
 //   - config: Configuration with agents and collaboration settings
 //
 // If no consensus function is provided, collaboration continues for all rounds.
-// The merge function is required and determines how responses are combined.
+// Either a merge function or a synthesize function is required to determine
+// how responses are combined.
 func NewCollaborativeAgent(config *CollaborativeConfig) (*CollaborativeAgent, error) {
 	if config == nil {
 		return nil, fmt.Errorf("config is required")
@@ -93,8 +131,8 @@ func NewCollaborativeAgent(config *CollaborativeConfig) (*CollaborativeAgent, er
 	if len(config.Agents) < 2 {
 		return nil, fmt.Errorf("at least two agents are required for collaboration")
 	}
-	if config.MergeFunc == nil {
-		return nil, fmt.Errorf("merge function is required")
+	if config.MergeFunc == nil && config.SynthesizeFunc == nil {
+		return nil, fmt.Errorf("MergeFunc or SynthesizeFunc is required")
 	}
 
 	maxRounds := config.MaxRounds
@@ -108,6 +146,10 @@ func NewCollaborativeAgent(config *CollaborativeConfig) (*CollaborativeAgent, er
 		maxRounds:     maxRounds,
 		consensusFunc: config.ConsensusFunc,
 		mergeFunc:     config.MergeFunc,
+		bestEffort:    config.BestEffortMergeFunc,
+		synthesize:    config.SynthesizeFunc,
+		selector:      config.RoundSelector,
+		logger:        config.Logger,
 	}, nil
 }
 
@@ -137,52 +179,82 @@ func (c *CollaborativeAgent) Capabilities() []string {
 
 // roundResult holds responses from a single collaboration round.
 type roundResult struct {
-	round     int
-	responses []*agenkit.Message
-	consensus bool
+	round        int
+	participants []string
+	responses    []*agenkit.Message
+	consensus    bool
 }
 
 // Process executes collaborative refinement through multiple rounds.
 //
 // The process follows these steps for each round:
-//  1. Each agent processes the current context (original + previous responses)
+//  1. Each participating agent processes the current context (original +
+//     previous responses); all agents participate unless a RoundSelector is set
 //  2. All responses are collected
 //  3. Consensus is checked (if function provided)
 //  4. If consensus or max rounds, merge and return
 //  5. Otherwise, prepare next round with all responses as context
 //
-// The final message includes metadata about rounds, consensus, and participation.
-func (c *CollaborativeAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	if message == nil {
-		return nil, fmt.Errorf("message cannot be nil")
+// The final message includes metadata about rounds, consensus, and
+// participation. "agreement_fraction" is the share of agents whose latest
+// response agrees (per the consensus function, or exact match if none is
+// set) with the most widely agreed-with response, and "dissenting_agents"
+// lists the others, so a max-rounds outcome shows how close the agents got.
+func (c *CollaborativeAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := c.Init(ctx); err != nil {
+		return nil, err
 	}
 
+	logger := resolveLogger(c.logger).With(slog.String("pattern", c.name))
 	rounds := make([]roundResult, 0, c.maxRounds)
 	currentContext := []*agenkit.Message{message}
+	var lastResponses []*agenkit.Message
 
 	for round := 0; round < c.maxRounds; round++ {
 		// Check for context cancellation
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("collaboration cancelled at round %d: %w", round, ctx.Err())
+			return nil, fmt.Errorf("collaboration cancelled at round %d: %w", round, contextError(ctx))
 		default:
 		}
 
-		// Collect responses from all agents
-		responses := make([]*agenkit.Message, 0, len(c.agents))
+		// Select this round's participants
+		participants := c.agents
+		if c.selector != nil {
+			participants = c.selector(round, lastResponses)
+			if len(participants) == 0 {
+				if round == 0 {
+					return nil, fmt.Errorf("round selector chose no agents for round 0")
+				}
+				return c.buildFinalResult(ctx, rounds, "no_participants")
+			}
+		}
+
+		// Collect responses from participating agents
+		responses := make([]*agenkit.Message, 0, len(participants))
+		names := make([]string, 0, len(participants))
 
-		for _, agent := range c.agents {
+		for _, agent := range participants {
 			// Build context message with conversation history
-			contextMsg := c.buildContextMessage(currentContext, round, agent.Name())
+			contextMsg := c.buildContextMessage(currentContext, round, agent.Name()).WithParent(message)
 
 			// Get agent response
-			response, err := agent.Process(ctx, contextMsg)
+			response, err := ProcessTraced(ctx, agent, contextMsg)
 			if err != nil {
+				logger.WarnContext(ctx, LogEventAgentError, slog.String("agent", agent.Name()),
+					slog.Int("round", round), slog.Any("error", err))
 				return nil, fmt.Errorf("agent %s failed in round %d: %w",
 					agent.Name(), round, err)
 			}
 
+			response = linkToInput(contextMsg, response)
 			responses = append(responses, response)
+			names = append(names, agent.Name())
 		}
 
 		// Check for consensus
@@ -191,24 +263,29 @@ func (c *CollaborativeAgent) Process(ctx context.Context, message *agenkit.Messa
 			hasConsensus = c.consensusFunc(responses)
 		}
 
+		logger.DebugContext(ctx, LogEventRound, slog.Int("round", round),
+			slog.Int("responses", len(responses)), slog.Bool("consensus", hasConsensus))
+
 		// Record round
 		rounds = append(rounds, roundResult{
-			round:     round,
-			responses: responses,
-			consensus: hasConsensus,
+			round:        round,
+			participants: names,
+			responses:    responses,
+			consensus:    hasConsensus,
 		})
 
 		// Stop if consensus reached
 		if hasConsensus {
-			return c.buildFinalResult(rounds, "consensus"), nil
+			return c.buildFinalResult(ctx, rounds, "consensus")
 		}
 
 		// Prepare next round context
 		currentContext = append(currentContext, responses...)
+		lastResponses = responses
 	}
 
 	// Max rounds reached
-	return c.buildFinalResult(rounds, "max_rounds"), nil
+	return c.buildFinalResult(ctx, rounds, "max_rounds")
 }
 
 // buildContextMessage creates a message with full conversation context.
@@ -236,14 +313,36 @@ func (c *CollaborativeAgent) buildContextMessage(context []*agenkit.Message, rou
 		content.WriteString("Please review the above responses and provide your refined contribution.\n")
 	}
 
-	return agenkit.NewMessage("user", content.String())
+	return agenkit.NewMessage(agenkit.RoleUser, content.String())
 }
 
-// buildFinalResult merges all responses and adds metadata.
-func (c *CollaborativeAgent) buildFinalResult(rounds []roundResult, stopReason string) *agenkit.Message {
-	// Collect all responses from final round
-	finalRound := rounds[len(rounds)-1]
-	merged := c.mergeFunc(finalRound.responses)
+// buildFinalResult merges or synthesizes all responses and adds metadata.
+func (c *CollaborativeAgent) buildFinalResult(ctx context.Context, rounds []roundResult, stopReason string) (*agenkit.Message, error) {
+	var merged *agenkit.Message
+
+	if c.synthesize != nil {
+		history := make([][]*agenkit.Message, len(rounds))
+		for i, r := range rounds {
+			history[i] = r.responses
+		}
+
+		synthesized, err := c.synthesize(ctx, history, stopReason == "consensus")
+		if err != nil {
+			return nil, fmt.Errorf("synthesis failed: %w", err)
+		}
+		if synthesized == nil {
+			return nil, fmt.Errorf("synthesis returned no message")
+		}
+		merged = synthesized
+	} else {
+		// Collect all responses from final round
+		finalRound := rounds[len(rounds)-1]
+		if c.bestEffort != nil && stopReason != "consensus" {
+			merged = c.bestEffort(finalRound.responses)
+		} else {
+			merged = c.mergeFunc(finalRound.responses)
+		}
+	}
 
 	// Add collaboration metadata
 	if merged.Metadata == nil {
@@ -253,22 +352,82 @@ func (c *CollaborativeAgent) buildFinalResult(rounds []roundResult, stopReason s
 	merged.Metadata["collaboration_rounds"] = len(rounds)
 	merged.Metadata["collaboration_agents"] = len(c.agents)
 	merged.Metadata["stop_reason"] = stopReason
+	switch stopReason {
+	case "consensus":
+		merged.Metadata[StopReasonKey] = StopConsensus
+	case "max_rounds":
+		merged.Metadata[StopReasonKey] = StopMaxIterations
+	default:
+		merged.Metadata[StopReasonKey] = StopStopped
+	}
+
+	dissenting, fraction := c.agreement(rounds)
+	merged.Metadata["dissenting_agents"] = dissenting
+	merged.Metadata["agreement_fraction"] = fraction
 
 	// Add round details
 	roundDetails := make([]map[string]interface{}, len(rounds))
 	for i, r := range rounds {
 		roundDetails[i] = map[string]interface{}{
-			"round":     r.round,
-			"responses": len(r.responses),
-			"consensus": r.consensus,
+			"round":        r.round,
+			"participants": r.participants,
+			"responses":    len(r.responses),
+			"consensus":    r.consensus,
 		}
 	}
 	merged.Metadata["rounds"] = roundDetails
 
-	return merged
+	return merged, nil
+}
+
+// agreement compares each agent's latest response against the response most
+// other agents agree with. It returns the agents that disagree with it and
+// the fraction that agree.
+func (c *CollaborativeAgent) agreement(rounds []roundResult) ([]string, float64) {
+	// Latest response per agent, in order of first participation; agents
+	// dropped by a RoundSelector keep their last word
+	var names []string
+	latest := make(map[string]*agenkit.Message)
+	for _, r := range rounds {
+		for i, name := range r.participants {
+			if _, seen := latest[name]; !seen {
+				names = append(names, name)
+			}
+			latest[name] = r.responses[i]
+		}
+	}
+
+	agrees := c.consensusFunc
+	if agrees == nil {
+		agrees = DefaultConsensusFunc.ExactMatch
+	}
+
+	// The reference is the response agreed with by the most agents
+	reference, bestCount := 0, -1
+	for i, a := range names {
+		count := 0
+		for _, b := range names {
+			if agrees([]*agenkit.Message{latest[a], latest[b]}) {
+				count++
+			}
+		}
+		if count > bestCount {
+			reference, bestCount = i, count
+		}
+	}
+
+	dissenting := make([]string, 0)
+	for _, name := range names {
+		if !agrees([]*agenkit.Message{latest[names[reference]], latest[name]}) {
+			dissenting = append(dissenting, name)
+		}
+	}
+
+	return dissenting, float64(len(names)-len(dissenting)) / float64(len(names))
 }
 
-// DefaultConsensusFunc provides common consensus detection strategies.
+// DefaultConsensusFunc provides common consensus detection strategies. They
+// skip nil messages.
 var DefaultConsensusFunc = struct {
 	// ExactMatch requires all responses to be identical
 	ExactMatch ConsensusFunc
@@ -280,6 +439,7 @@ var DefaultConsensusFunc = struct {
 	MajorityAgreement ConsensusFunc
 }{
 	ExactMatch: func(messages []*agenkit.Message) bool {
+		messages = compactMessages(messages)
 		if len(messages) <= 1 {
 			return true
 		}
@@ -295,6 +455,7 @@ var DefaultConsensusFunc = struct {
 
 	SimilarityThreshold: func(threshold float64) ConsensusFunc {
 		return func(messages []*agenkit.Message) bool {
+			messages = compactMessages(messages)
 			if len(messages) <= 1 {
 				return true
 			}
@@ -313,6 +474,7 @@ var DefaultConsensusFunc = struct {
 	},
 
 	MajorityAgreement: func(messages []*agenkit.Message) bool {
+		messages = compactMessages(messages)
 		if len(messages) <= 1 {
 			return true
 		}
@@ -335,7 +497,8 @@ var DefaultConsensusFunc = struct {
 	},
 }
 
-// DefaultMergeFunc provides common merge strategies.
+// DefaultMergeFunc provides common merge strategies. They skip nil messages
+// and return a placeholder message for an empty slice.
 var DefaultMergeFunc = struct {
 	// Concatenate combines all responses with separators
 	Concatenate MergeFunc
@@ -348,10 +511,15 @@ var DefaultMergeFunc = struct {
 
 	// Last returns last response
 	Last MergeFunc
+
+	// BestEffort returns the most common response followed by the
+	// responses that differ from it, for outcomes without consensus
+	BestEffort MergeFunc
 }{
 	Concatenate: func(messages []*agenkit.Message) *agenkit.Message {
+		messages = compactMessages(messages)
 		if len(messages) == 0 {
-			return agenkit.NewMessage("assistant", "No responses to merge")
+			return agenkit.NewMessage(agenkit.RoleAssistant, "No responses to merge")
 		}
 
 		var combined strings.Builder
@@ -362,12 +530,13 @@ var DefaultMergeFunc = struct {
 			combined.WriteString(msg.ContentString())
 		}
 
-		return agenkit.NewMessage("assistant", combined.String())
+		return agenkit.NewMessage(agenkit.RoleAssistant, combined.String())
 	},
 
 	Vote: func(messages []*agenkit.Message) *agenkit.Message {
+		messages = compactMessages(messages)
 		if len(messages) == 0 {
-			return agenkit.NewMessage("assistant", "No responses to merge")
+			return agenkit.NewMessage(agenkit.RoleAssistant, "No responses to merge")
 		}
 
 		// Count votes
@@ -390,6 +559,9 @@ var DefaultMergeFunc = struct {
 		}
 
 		result := msgByContent[winner]
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]interface{})
+		}
 		result.WithMetadata("votes", maxVotes).
 			WithMetadata("total", len(messages))
 
@@ -397,18 +569,59 @@ var DefaultMergeFunc = struct {
 	},
 
 	First: func(messages []*agenkit.Message) *agenkit.Message {
+		messages = compactMessages(messages)
 		if len(messages) == 0 {
-			return agenkit.NewMessage("assistant", "No responses to merge")
+			return agenkit.NewMessage(agenkit.RoleAssistant, "No responses to merge")
 		}
 		return messages[0]
 	},
 
 	Last: func(messages []*agenkit.Message) *agenkit.Message {
+		messages = compactMessages(messages)
 		if len(messages) == 0 {
-			return agenkit.NewMessage("assistant", "No responses to merge")
+			return agenkit.NewMessage(agenkit.RoleAssistant, "No responses to merge")
 		}
 		return messages[len(messages)-1]
 	},
+
+	BestEffort: func(messages []*agenkit.Message) *agenkit.Message {
+		messages = compactMessages(messages)
+		if len(messages) == 0 {
+			return agenkit.NewMessage(agenkit.RoleAssistant, "No responses to merge")
+		}
+
+		// Most common response, earliest on ties
+		votes := make(map[string]int)
+		for _, msg := range messages {
+			votes[msg.ContentString()]++
+		}
+		leading := messages[0].ContentString()
+		for _, msg := range messages {
+			if votes[msg.ContentString()] > votes[leading] {
+				leading = msg.ContentString()
+			}
+		}
+
+		var content strings.Builder
+		content.WriteString(leading)
+
+		disagreements := 0
+		for i, msg := range messages {
+			if msg.ContentString() == leading {
+				continue
+			}
+			if disagreements == 0 {
+				content.WriteString("\n\n--- Points of disagreement ---")
+			}
+			disagreements++
+			content.WriteString(fmt.Sprintf("\n\nResponse %d:\n%s", i+1, msg.ContentString()))
+		}
+
+		return agenkit.NewMessage(agenkit.RoleAssistant, content.String()).
+			WithMetadata("votes", votes[leading]).
+			WithMetadata("total", len(messages)).
+			WithMetadata("disagreements", disagreements)
+	},
 }
 
 func min(a, b int) int {
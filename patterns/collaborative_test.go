@@ -74,8 +74,8 @@ func TestCollaborativeAgent_ConstructorNilMergeFunc(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for nil merge function")
 	}
-	if !strings.Contains(err.Error(), "merge function") {
-		t.Errorf("expected 'merge function' error, got %v", err)
+	if !strings.Contains(err.Error(), "MergeFunc or SynthesizeFunc") {
+		t.Errorf("expected 'MergeFunc or SynthesizeFunc' error, got %v", err)
 	}
 }
 
@@ -525,3 +525,314 @@ func TestCollaborativeAgent_ContextInMessages(t *testing.T) {
 		t.Errorf("expected round 1 message to contain 'Previous Responses', got: %s", receivedMessages[2][:100])
 	}
 }
+
+// TestCollaborativeAgent_SynthesizeFunc tests synthesized explanations
+func TestCollaborativeAgent_SynthesizeFunc(t *testing.T) {
+	agent1 := &extendedMockAgent{name: "security", response: "approved"}
+	agent2 := &extendedMockAgent{name: "performance", response: "approved"}
+
+	var gotRounds [][]*agenkit.Message
+	var gotReached bool
+
+	collab, err := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:        []agenkit.Agent{agent1, agent2},
+		MaxRounds:     3,
+		ConsensusFunc: DefaultConsensusFunc.ExactMatch,
+		SynthesizeFunc: func(ctx context.Context, rounds [][]*agenkit.Message, reached bool) (*agenkit.Message, error) {
+			gotRounds = rounds
+			gotReached = reached
+			return agenkit.NewMessage("assistant",
+				fmt.Sprintf("Consensus reached after %d rounds; all reviewers approved", len(rounds))), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := collab.Process(context.Background(), agenkit.NewMessage("user", "review PR"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !gotReached {
+		t.Error("expected synthesize to be told consensus was reached")
+	}
+	if len(gotRounds) != 1 || len(gotRounds[0]) != 2 {
+		t.Errorf("expected 1 round with 2 responses, got %v", gotRounds)
+	}
+	if result.ContentString() != "Consensus reached after 1 rounds; all reviewers approved" {
+		t.Errorf("unexpected synthesized content: %s", result.ContentString())
+	}
+	if result.Metadata["stop_reason"] != "consensus" {
+		t.Errorf("expected stop_reason='consensus', got %v", result.Metadata["stop_reason"])
+	}
+}
+
+// TestCollaborativeAgent_SynthesizeFuncMaxRounds tests synthesis without consensus
+func TestCollaborativeAgent_SynthesizeFuncMaxRounds(t *testing.T) {
+	agent1 := &extendedMockAgent{name: "agent1", response: "yes"}
+	agent2 := &extendedMockAgent{name: "agent2", response: "no"}
+
+	reached := true
+	roundCount := 0
+	collab, err := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:        []agenkit.Agent{agent1, agent2},
+		MaxRounds:     2,
+		ConsensusFunc: DefaultConsensusFunc.ExactMatch,
+		MergeFunc:     DefaultMergeFunc.First,
+		SynthesizeFunc: func(ctx context.Context, rounds [][]*agenkit.Message, r bool) (*agenkit.Message, error) {
+			reached = r
+			roundCount = len(rounds)
+			return agenkit.NewMessage("assistant", "No consensus"), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := collab.Process(context.Background(), agenkit.NewMessage("user", "decide"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if reached {
+		t.Error("expected synthesize to be told consensus was not reached")
+	}
+	if roundCount != 2 {
+		t.Errorf("expected 2 rounds of history, got %d", roundCount)
+	}
+	if result.ContentString() != "No consensus" {
+		t.Errorf("expected synthesized output to replace merge output, got %s", result.ContentString())
+	}
+}
+
+// TestCollaborativeAgent_SynthesizeFuncError tests synthesis error propagation
+func TestCollaborativeAgent_SynthesizeFuncError(t *testing.T) {
+	agent1 := &extendedMockAgent{name: "agent1", response: "a"}
+	agent2 := &extendedMockAgent{name: "agent2", response: "b"}
+
+	collab, err := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:    []agenkit.Agent{agent1, agent2},
+		MaxRounds: 1,
+		SynthesizeFunc: func(ctx context.Context, rounds [][]*agenkit.Message, reached bool) (*agenkit.Message, error) {
+			return nil, errors.New("llm unavailable")
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = collab.Process(context.Background(), agenkit.NewMessage("user", "decide"))
+	if err == nil || !strings.Contains(err.Error(), "synthesis failed") {
+		t.Errorf("expected synthesis error, got %v", err)
+	}
+}
+
+// TestCollaborativeAgent_RoundSelector tests dropping agents that approved
+func TestCollaborativeAgent_RoundSelector(t *testing.T) {
+	var grammarCalls, securityCalls int
+	grammar := &extendedMockAgent{name: "grammar", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		grammarCalls++
+		return agenkit.NewMessage("assistant", "APPROVED").WithMetadata("agent", "grammar"), nil
+	}}
+	security := &extendedMockAgent{name: "security", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		securityCalls++
+		return agenkit.NewMessage("assistant", "needs work").WithMetadata("agent", "security"), nil
+	}}
+	agents := []agenkit.Agent{grammar, security}
+
+	// Keep only agents that have not yet approved
+	selector := func(round int, last []*agenkit.Message) []agenkit.Agent {
+		if round == 0 {
+			return agents
+		}
+		selected := make([]agenkit.Agent, 0)
+		for _, msg := range last {
+			if msg.ContentString() != "APPROVED" {
+				for _, a := range agents {
+					if a.Name() == msg.Metadata["agent"] {
+						selected = append(selected, a)
+					}
+				}
+			}
+		}
+		return selected
+	}
+
+	collab, err := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:        agents,
+		MaxRounds:     3,
+		MergeFunc:     DefaultMergeFunc.Last,
+		RoundSelector: selector,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := collab.Process(context.Background(), agenkit.NewMessage("user", "review"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if grammarCalls != 1 || securityCalls != 3 {
+		t.Errorf("expected grammar=1 security=3 calls, got grammar=%d security=%d", grammarCalls, securityCalls)
+	}
+
+	rounds := result.Metadata["rounds"].([]map[string]interface{})
+	wantParticipants := [][]string{{"grammar", "security"}, {"security"}, {"security"}}
+	for i, want := range wantParticipants {
+		got := rounds[i]["participants"].([]string)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("round %d: expected participants %v, got %v", i, want, got)
+		}
+	}
+}
+
+// TestCollaborativeAgent_RoundSelectorNoParticipants tests early stop
+func TestCollaborativeAgent_RoundSelectorNoParticipants(t *testing.T) {
+	agent1 := &extendedMockAgent{name: "agent1", response: "done"}
+	agent2 := &extendedMockAgent{name: "agent2", response: "done"}
+
+	collab, err := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:    []agenkit.Agent{agent1, agent2},
+		MaxRounds: 5,
+		MergeFunc: DefaultMergeFunc.First,
+		RoundSelector: func(round int, last []*agenkit.Message) []agenkit.Agent {
+			if round == 0 {
+				return []agenkit.Agent{agent1, agent2}
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := collab.Process(context.Background(), agenkit.NewMessage("user", "go"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["stop_reason"] != "no_participants" {
+		t.Errorf("expected stop_reason=no_participants, got %v", result.Metadata["stop_reason"])
+	}
+	if result.Metadata["collaboration_rounds"] != 1 {
+		t.Errorf("expected 1 round, got %v", result.Metadata["collaboration_rounds"])
+	}
+
+	// Selecting nobody in the first round is an error
+	collab, _ = NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:        []agenkit.Agent{agent1, agent2},
+		MergeFunc:     DefaultMergeFunc.First,
+		RoundSelector: func(int, []*agenkit.Message) []agenkit.Agent { return nil },
+	})
+	if _, err := collab.Process(context.Background(), agenkit.NewMessage("user", "go")); err == nil {
+		t.Error("expected error when no agents are selected for round 0")
+	}
+}
+
+// TestCollaborativeAgent_PartialConsensus tests dissent reporting at max rounds
+func TestCollaborativeAgent_PartialConsensus(t *testing.T) {
+	agents := []agenkit.Agent{
+		&extendedMockAgent{name: "a", response: "approve"},
+		&extendedMockAgent{name: "b", response: "reject: missing tests"},
+		&extendedMockAgent{name: "c", response: "approve"},
+	}
+
+	collab, err := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:              agents,
+		MaxRounds:           2,
+		ConsensusFunc:       DefaultConsensusFunc.ExactMatch,
+		MergeFunc:           DefaultMergeFunc.Last,
+		BestEffortMergeFunc: DefaultMergeFunc.BestEffort,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := collab.Process(context.Background(), agenkit.NewMessage("user", "review"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Metadata["stop_reason"] != "max_rounds" {
+		t.Fatalf("expected stop_reason=max_rounds, got %v", result.Metadata["stop_reason"])
+	}
+	dissenting := result.Metadata["dissenting_agents"].([]string)
+	if len(dissenting) != 1 || dissenting[0] != "b" {
+		t.Errorf("expected dissenting [b], got %v", dissenting)
+	}
+	if fraction := result.Metadata["agreement_fraction"].(float64); fraction != 2.0/3.0 {
+		t.Errorf("expected agreement 2/3, got %v", fraction)
+	}
+
+	content := result.ContentString()
+	if !strings.HasPrefix(content, "approve") || !strings.Contains(content, "Points of disagreement") ||
+		!strings.Contains(content, "reject: missing tests") {
+		t.Errorf("expected best-effort merge with disagreement, got %q", content)
+	}
+	if result.Metadata["votes"] != 2 || result.Metadata["disagreements"] != 1 {
+		t.Errorf("unexpected merge metadata: %v", result.Metadata)
+	}
+}
+
+// TestCollaborativeAgent_ConsensusSkipsBestEffort tests that consensus uses MergeFunc
+func TestCollaborativeAgent_ConsensusSkipsBestEffort(t *testing.T) {
+	agents := []agenkit.Agent{
+		&extendedMockAgent{name: "a", response: "approve"},
+		&extendedMockAgent{name: "b", response: "approve"},
+	}
+
+	collab, _ := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:              agents,
+		ConsensusFunc:       DefaultConsensusFunc.ExactMatch,
+		MergeFunc:           DefaultMergeFunc.First,
+		BestEffortMergeFunc: DefaultMergeFunc.BestEffort,
+	})
+
+	result, err := collab.Process(context.Background(), agenkit.NewMessage("user", "review"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "approve" || result.Metadata["disagreements"] != nil {
+		t.Errorf("expected MergeFunc result on consensus, got %q", result.ContentString())
+	}
+	if len(result.Metadata["dissenting_agents"].([]string)) != 0 || result.Metadata["agreement_fraction"] != 1.0 {
+		t.Errorf("expected full agreement, got %v", result.Metadata)
+	}
+}
+
+// TestCollaborativeAgent_DissentUsesLatestResponses tests agents dropped by a selector
+func TestCollaborativeAgent_DissentUsesLatestResponses(t *testing.T) {
+	round := 0
+	approver := &extendedMockAgent{name: "approver", response: "LGTM"}
+	reviser := &extendedMockAgent{name: "reviser", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		round++
+		if round < 2 {
+			return agenkit.NewMessage("assistant", "changes requested"), nil
+		}
+		return agenkit.NewMessage("assistant", "LGTM"), nil
+	}}
+	agents := []agenkit.Agent{approver, reviser}
+
+	collab, _ := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:    agents,
+		MaxRounds: 2,
+		MergeFunc: DefaultMergeFunc.Last,
+		RoundSelector: func(r int, last []*agenkit.Message) []agenkit.Agent {
+			if r == 0 {
+				return agents
+			}
+			return []agenkit.Agent{reviser}
+		},
+	})
+
+	result, err := collab.Process(context.Background(), agenkit.NewMessage("user", "review"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The approver only spoke in round 0, but its approval still counts
+	if result.Metadata["agreement_fraction"] != 1.0 {
+		t.Errorf("expected full agreement from latest responses, got %v (dissenting %v)",
+			result.Metadata["agreement_fraction"], result.Metadata["dissenting_agents"])
+	}
+}
@@ -0,0 +1,533 @@
+package patterns
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// Built-in node types for TopologyConfig. Any other type is looked up in
+// the AgentFactory.
+const (
+	// TopologySequential runs Agents in order (SequentialPattern)
+	TopologySequential = "sequential"
+	// TopologyParallel runs Agents concurrently (ParallelPattern). Params:
+	// "aggregator" is "concatenate" (default), "first" or "majority_vote".
+	TopologyParallel = "parallel"
+	// TopologyRouter sends each message to one of Routes (RouterPattern).
+	// Params: "keywords" maps route keys to keyword lists; the first route
+	// with a keyword in the message wins, otherwise Default handles it.
+	TopologyRouter = "router"
+	// TopologySupervisor delegates subtasks to the specialists in Routes,
+	// keyed by subtask type (SupervisorAgent). Planner is required; agents
+	// that don't implement PlannerAgent are wrapped in a SimplePlanner.
+	// Params: "max_subtasks" limits subtasks per plan.
+	TopologySupervisor = "supervisor"
+)
+
+// AgentSpec declares one node of an agent topology: either a reference to
+// a named definition (Ref), or an agent of Type with its parameters and,
+// for the built-in types, its child agents.
+type AgentSpec struct {
+	// Name names the built agent (defaults to the type)
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Type is a built-in topology type or a type registered in the factory
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Ref refers to a definition by name instead of declaring an agent
+	Ref string `json:"ref,omitempty" yaml:"ref,omitempty"`
+	// Params are passed to the factory constructor, or configure a
+	// built-in type
+	Params map[string]interface{} `json:"params,omitempty" yaml:"params,omitempty"`
+	// Agents are the children of sequential and parallel nodes
+	Agents []AgentSpec `json:"agents,omitempty" yaml:"agents,omitempty"`
+	// Routes are the handlers of a router or the specialists of a
+	// supervisor, by key
+	Routes map[string]AgentSpec `json:"routes,omitempty" yaml:"routes,omitempty"`
+	// Default handles messages no router route matches
+	Default *AgentSpec `json:"default,omitempty" yaml:"default,omitempty"`
+	// Planner is a supervisor's planner
+	Planner *AgentSpec `json:"planner,omitempty" yaml:"planner,omitempty"`
+}
+
+// TopologyConfig declares an agent topology to assemble with
+// BuildFromConfig, so pipelines can be reconfigured without recompiling.
+//
+// Example (YAML):
+//
+//	definitions:
+//	  - name: writer
+//	    type: llm
+//	    params: {model: gpt-4o}
+//	root:
+//	  name: support
+//	  type: router
+//	  params:
+//	    keywords: {billing: [invoice, refund]}
+//	  routes:
+//	    billing: {type: billing-agent}
+//	  default: {ref: writer}
+type TopologyConfig struct {
+	// Definitions are named agents that specs can share by Ref. Each is
+	// built once, however many times it's referenced.
+	Definitions []AgentSpec `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+	// Root is the agent BuildFromConfig returns
+	Root AgentSpec `json:"root" yaml:"root"`
+}
+
+// ParseTopologyConfig decodes a TopologyConfig from YAML or JSON. Unknown
+// fields are errors, so typos don't silently drop configuration.
+func ParseTopologyConfig(data []byte) (*TopologyConfig, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var config TopologyConfig
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("parse topology config: %w", err)
+	}
+	return &config, nil
+}
+
+// Validate checks the configuration against factory without building any
+// agents: types must be built-in or registered, references must name a
+// definition without forming a cycle, and built-in types must have the
+// children and parameters they need. All problems are reported together,
+// each prefixed with its location in the config.
+func (c *TopologyConfig) Validate(factory *agenkit.AgentFactory) error {
+	v := &topologyValidator{
+		factory:     factory,
+		definitions: make(map[string]*AgentSpec),
+		state:       make(map[string]int),
+	}
+
+	for i := range c.Definitions {
+		def := &c.Definitions[i]
+		path := fmt.Sprintf("definitions[%d]", i)
+		switch {
+		case def.Name == "":
+			v.errorf(path, "definition requires a name")
+		case v.definitions[def.Name] != nil:
+			v.errorf(path, "duplicate definition '%s'", def.Name)
+		default:
+			v.definitions[def.Name] = def
+		}
+	}
+
+	for i := range c.Definitions {
+		if def := &c.Definitions[i]; def.Name != "" && v.definitions[def.Name] == def {
+			v.validateDefinition(def.Name)
+		}
+	}
+	v.validate("root", &c.Root)
+
+	return errors.Join(v.errs...)
+}
+
+// BuildFromConfig validates config and assembles the agent tree it
+// declares, creating leaf agents with factory.
+//
+// Example:
+//
+//	config, err := patterns.ParseTopologyConfig(data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	agent, err := patterns.BuildFromConfig(config, factory)
+func BuildFromConfig(config *TopologyConfig, factory *agenkit.AgentFactory) (agenkit.Agent, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+	if factory == nil {
+		factory = agenkit.NewAgentFactory()
+	}
+	if err := config.Validate(factory); err != nil {
+		return nil, fmt.Errorf("invalid topology config: %w", err)
+	}
+
+	b := &topologyBuilder{
+		factory:     factory,
+		definitions: make(map[string]*AgentSpec),
+		built:       make(map[string]agenkit.Agent),
+	}
+	for i := range config.Definitions {
+		b.definitions[config.Definitions[i].Name] = &config.Definitions[i]
+	}
+	return b.build("root", &config.Root)
+}
+
+// isBuiltinTopology reports whether agentType is one of the built-in
+// composition types.
+func isBuiltinTopology(agentType string) bool {
+	switch agentType {
+	case TopologySequential, TopologyParallel, TopologyRouter, TopologySupervisor:
+		return true
+	}
+	return false
+}
+
+// Definition validation states for cycle detection.
+const (
+	definitionVisiting = 1
+	definitionDone     = 2
+)
+
+// topologyValidator accumulates the problems in a TopologyConfig.
+type topologyValidator struct {
+	factory     *agenkit.AgentFactory
+	definitions map[string]*AgentSpec
+	state       map[string]int
+	errs        []error
+}
+
+func (v *topologyValidator) errorf(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+// validateDefinition validates a definition once, detecting reference
+// cycles through it.
+func (v *topologyValidator) validateDefinition(name string) {
+	switch v.state[name] {
+	case definitionVisiting:
+		v.errorf("definitions."+name, "reference cycle through '%s'", name)
+		return
+	case definitionDone:
+		return
+	}
+	v.state[name] = definitionVisiting
+	v.validate("definitions."+name, v.definitions[name])
+	v.state[name] = definitionDone
+}
+
+func (v *topologyValidator) validate(path string, spec *AgentSpec) {
+	if spec.Ref != "" {
+		if spec.Type != "" || len(spec.Params) > 0 || len(spec.Agents) > 0 || len(spec.Routes) > 0 ||
+			spec.Default != nil || spec.Planner != nil {
+			v.errorf(path, "ref '%s' cannot be combined with other fields", spec.Ref)
+		}
+		if v.definitions[spec.Ref] == nil {
+			v.errorf(path, "unknown reference '%s'", spec.Ref)
+			return
+		}
+		v.validateDefinition(spec.Ref)
+		return
+	}
+
+	if spec.Type == "" {
+		v.errorf(path, "either type or ref is required")
+		return
+	}
+
+	if !isBuiltinTopology(spec.Type) {
+		if !v.factory.Has(spec.Type) {
+			v.errorf(path, "unknown agent type '%s' (registered: %s)", spec.Type, strings.Join(v.factory.Types(), ", "))
+		}
+		if len(spec.Agents) > 0 || len(spec.Routes) > 0 || spec.Default != nil || spec.Planner != nil {
+			v.errorf(path, "agent type '%s' cannot have child agents", spec.Type)
+		}
+		return
+	}
+
+	switch spec.Type {
+	case TopologySequential, TopologyParallel:
+		if len(spec.Agents) == 0 {
+			v.errorf(path, "%s requires at least one agent", spec.Type)
+		}
+		if len(spec.Routes) > 0 || spec.Default != nil || spec.Planner != nil {
+			v.errorf(path, "%s only supports agents as children", spec.Type)
+		}
+		if spec.Type == TopologyParallel {
+			if _, err := parallelAggregator(spec.Params); err != nil {
+				v.errorf(path, "%v", err)
+			}
+		}
+	case TopologyRouter:
+		if len(spec.Routes) == 0 {
+			v.errorf(path, "router requires at least one route")
+		}
+		if len(spec.Agents) > 0 || spec.Planner != nil {
+			v.errorf(path, "router only supports routes and default as children")
+		}
+		if keywords, err := routeKeywords(spec.Params); err != nil {
+			v.errorf(path, "%v", err)
+		} else {
+			for key := range keywords {
+				if _, ok := spec.Routes[key]; !ok {
+					v.errorf(path, "keywords for unknown route '%s'", key)
+				}
+			}
+		}
+	case TopologySupervisor:
+		if spec.Planner == nil {
+			v.errorf(path, "supervisor requires a planner")
+		}
+		if len(spec.Routes) == 0 {
+			v.errorf(path, "supervisor requires at least one specialist route")
+		}
+		if len(spec.Agents) > 0 || spec.Default != nil {
+			v.errorf(path, "supervisor only supports planner and routes as children")
+		}
+		if _, err := paramInt(spec.Params, "max_subtasks"); err != nil {
+			v.errorf(path, "%v", err)
+		}
+	}
+
+	for i := range spec.Agents {
+		v.validate(fmt.Sprintf("%s.agents[%d]", path, i), &spec.Agents[i])
+	}
+	for _, key := range sortedRouteKeys(spec.Routes) {
+		route := spec.Routes[key]
+		v.validate(fmt.Sprintf("%s.routes.%s", path, key), &route)
+	}
+	if spec.Default != nil {
+		v.validate(path+".default", spec.Default)
+	}
+	if spec.Planner != nil {
+		v.validate(path+".planner", spec.Planner)
+	}
+}
+
+// topologyBuilder assembles a validated TopologyConfig.
+type topologyBuilder struct {
+	factory     *agenkit.AgentFactory
+	definitions map[string]*AgentSpec
+	built       map[string]agenkit.Agent
+}
+
+func (b *topologyBuilder) build(path string, spec *AgentSpec) (agenkit.Agent, error) {
+	if spec.Ref != "" {
+		if agent, ok := b.built[spec.Ref]; ok {
+			return agent, nil
+		}
+		agent, err := b.build("definitions."+spec.Ref, b.definitions[spec.Ref])
+		if err != nil {
+			return nil, err
+		}
+		b.built[spec.Ref] = agent
+		return agent, nil
+	}
+
+	name := spec.Name
+	if name == "" {
+		name = spec.Type
+	}
+
+	if !isBuiltinTopology(spec.Type) {
+		agent, err := b.factory.Create(spec.Type, name, spec.Params)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return agent, nil
+	}
+
+	var agent agenkit.Agent
+	var err error
+	switch spec.Type {
+	case TopologySequential:
+		agent, err = b.buildSequential(path, name, spec)
+	case TopologyParallel:
+		agent, err = b.buildParallel(path, name, spec)
+	case TopologyRouter:
+		agent, err = b.buildRouter(path, name, spec)
+	case TopologySupervisor:
+		agent, err = b.buildSupervisor(path, name, spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return agent, nil
+}
+
+func (b *topologyBuilder) buildChildren(path string, specs []AgentSpec) ([]agenkit.Agent, error) {
+	agents := make([]agenkit.Agent, len(specs))
+	for i := range specs {
+		agent, err := b.build(fmt.Sprintf("%s.agents[%d]", path, i), &specs[i])
+		if err != nil {
+			return nil, err
+		}
+		agents[i] = agent
+	}
+	return agents, nil
+}
+
+func (b *topologyBuilder) buildRoutes(path string, specs map[string]AgentSpec) (map[string]agenkit.Agent, error) {
+	agents := make(map[string]agenkit.Agent, len(specs))
+	for _, key := range sortedRouteKeys(specs) {
+		spec := specs[key]
+		agent, err := b.build(fmt.Sprintf("%s.routes.%s", path, key), &spec)
+		if err != nil {
+			return nil, err
+		}
+		agents[key] = agent
+	}
+	return agents, nil
+}
+
+func (b *topologyBuilder) buildSequential(path, name string, spec *AgentSpec) (agenkit.Agent, error) {
+	agents, err := b.buildChildren(path, spec.Agents)
+	if err != nil {
+		return nil, err
+	}
+	return NewSequentialPattern(agents, &SequentialPatternConfig{Name: name})
+}
+
+func (b *topologyBuilder) buildParallel(path, name string, spec *AgentSpec) (agenkit.Agent, error) {
+	agents, err := b.buildChildren(path, spec.Agents)
+	if err != nil {
+		return nil, err
+	}
+	aggregator, err := parallelAggregator(spec.Params)
+	if err != nil {
+		return nil, err
+	}
+	return NewParallelPattern(agents, Aggregator(aggregator), &ParallelPatternConfig{Name: name})
+}
+
+func (b *topologyBuilder) buildRouter(path, name string, spec *AgentSpec) (agenkit.Agent, error) {
+	handlers, err := b.buildRoutes(path, spec.Routes)
+	if err != nil {
+		return nil, err
+	}
+	config := &RouterPatternConfig{Name: name}
+	if spec.Default != nil {
+		if config.DefaultHandler, err = b.build(path+".default", spec.Default); err != nil {
+			return nil, err
+		}
+	}
+	keywords, err := routeKeywords(spec.Params)
+	if err != nil {
+		return nil, err
+	}
+	return NewRouterPattern(keywordRouter(keywords), handlers, config)
+}
+
+func (b *topologyBuilder) buildSupervisor(path, name string, spec *AgentSpec) (agenkit.Agent, error) {
+	specialists, err := b.buildRoutes(path, spec.Routes)
+	if err != nil {
+		return nil, err
+	}
+	plannerAgent, err := b.build(path+".planner", spec.Planner)
+	if err != nil {
+		return nil, err
+	}
+	planner, ok := plannerAgent.(PlannerAgent)
+	if !ok {
+		planner = NewSimplePlanner(plannerAgent)
+	}
+
+	supervisor, err := NewSupervisorAgent(planner, specialists)
+	if err != nil {
+		return nil, err
+	}
+	supervisor.name = name
+	maxSubtasks, err := paramInt(spec.Params, "max_subtasks")
+	if err != nil {
+		return nil, err
+	}
+	return supervisor.WithMaxSubtasks(maxSubtasks), nil
+}
+
+// parallelAggregator resolves the "aggregator" parameter of a parallel node.
+func parallelAggregator(params map[string]interface{}) (AggregatorFunc, error) {
+	name, ok := params["aggregator"]
+	if !ok {
+		return DefaultAggregators.Concatenate, nil
+	}
+	switch name {
+	case "concatenate":
+		return DefaultAggregators.Concatenate, nil
+	case "first":
+		return DefaultAggregators.First, nil
+	case "majority_vote":
+		return DefaultAggregators.MajorityVote, nil
+	default:
+		return nil, fmt.Errorf("unknown aggregator %v (want concatenate, first or majority_vote)", name)
+	}
+}
+
+// routeKeywords decodes the "keywords" parameter of a router node, a map of
+// route keys to keyword lists.
+func routeKeywords(params map[string]interface{}) (map[string][]string, error) {
+	raw, ok := params["keywords"]
+	if !ok {
+		return nil, nil
+	}
+	byRoute, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("keywords must map route keys to keyword lists, got %T", raw)
+	}
+
+	keywords := make(map[string][]string, len(byRoute))
+	for route, list := range byRoute {
+		items, ok := list.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("keywords for route '%s' must be a list, got %T", route, list)
+		}
+		for _, item := range items {
+			keyword, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("keywords for route '%s' must be strings, got %T", route, item)
+			}
+			keywords[route] = append(keywords[route], strings.ToLower(keyword))
+		}
+	}
+	return keywords, nil
+}
+
+// keywordRouter routes to the first route, in key order, with a keyword in
+// the message. Unmatched messages get an empty key, which RouterPattern
+// sends to the default handler.
+func keywordRouter(keywords map[string][]string) Router {
+	routes := make([]string, 0, len(keywords))
+	for route := range keywords {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	return func(message *agenkit.Message) string {
+		content := strings.ToLower(message.ContentString())
+		for _, route := range routes {
+			for _, keyword := range keywords[route] {
+				if strings.Contains(content, keyword) {
+					return route
+				}
+			}
+		}
+		return ""
+	}
+}
+
+// paramInt returns an integer parameter (0 if absent). JSON and YAML
+// decoders produce float64 and int respectively; both are accepted.
+func paramInt(params map[string]interface{}, key string) (int, error) {
+	raw, ok := params[key]
+	if !ok {
+		return 0, nil
+	}
+	switch value := raw.(type) {
+	case int:
+		return value, nil
+	case float64:
+		if value != float64(int(value)) {
+			return 0, fmt.Errorf("%s must be an integer, got %v", key, value)
+		}
+		return int(value), nil
+	default:
+		return 0, fmt.Errorf("%s must be an integer, got %T", key, raw)
+	}
+}
+
+// sortedRouteKeys returns the keys of routes in order, so building and
+// error reporting are deterministic.
+func sortedRouteKeys(routes map[string]AgentSpec) []string {
+	keys := make([]string, 0, len(routes))
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,199 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// newTestFactory registers an "echo" type whose agents reply with their
+// name and the "reply" param.
+func newTestFactory(t *testing.T) (*agenkit.AgentFactory, *int) {
+	t.Helper()
+	created := 0
+	factory := agenkit.NewAgentFactory()
+	err := factory.Register("echo", func(name string, params map[string]interface{}) (agenkit.Agent, error) {
+		created++
+		reply, _ := params["reply"].(string)
+		return &extendedMockAgent{name: name, response: name + ":" + reply}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return factory, &created
+}
+
+func TestBuildFromConfig_YAMLTopology(t *testing.T) {
+	data := []byte(`
+definitions:
+  - name: writer
+    type: echo
+    params: {reply: written}
+root:
+  name: support
+  type: router
+  params:
+    keywords:
+      billing: [invoice, Refund]
+  routes:
+    billing:
+      name: billing-pipeline
+      type: sequential
+      agents:
+        - {type: echo, name: lookup}
+        - {ref: writer}
+  default:
+    type: parallel
+    params: {aggregator: first}
+    agents:
+      - {ref: writer}
+      - {type: echo, name: backup}
+`)
+	config, err := ParseTopologyConfig(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	factory, created := newTestFactory(t)
+
+	agent, err := BuildFromConfig(config, factory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if agent.Name() != "support" {
+		t.Errorf("expected root named support, got %s", agent.Name())
+	}
+	// The shared definition is built once
+	if *created != 3 {
+		t.Errorf("expected 3 leaf agents, got %d", *created)
+	}
+
+	ctx := context.Background()
+	result, err := agent.Process(ctx, agenkit.NewMessage("user", "I need a REFUND"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "writer:written" {
+		t.Errorf("expected billing pipeline to end at writer, got %q", result.ContentString())
+	}
+
+	result, err = agent.Process(ctx, agenkit.NewMessage("user", "hello"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "writer:written" {
+		t.Errorf("expected default parallel to return first result, got %q", result.ContentString())
+	}
+}
+
+func TestBuildFromConfig_Supervisor(t *testing.T) {
+	factory, _ := newTestFactory(t)
+	config, err := ParseTopologyConfig([]byte(`{
+		"root": {
+			"type": "supervisor",
+			"name": "lead",
+			"params": {"max_subtasks": 3},
+			"planner": {"type": "echo"},
+			"routes": {"code": {"type": "echo", "name": "coder"}}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	agent, err := BuildFromConfig(config, factory)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	supervisor, ok := agent.(*SupervisorAgent)
+	if !ok {
+		t.Fatalf("expected *SupervisorAgent, got %T", agent)
+	}
+	if supervisor.Name() != "lead" || supervisor.maxSubtasks != 3 {
+		t.Errorf("unexpected supervisor: %s, max %d", supervisor.Name(), supervisor.maxSubtasks)
+	}
+	if _, ok := supervisor.planner.(*SimplePlanner); !ok {
+		t.Errorf("expected plain agent to be wrapped in SimplePlanner, got %T", supervisor.planner)
+	}
+}
+
+func TestTopologyConfig_Validate(t *testing.T) {
+	factory, _ := newTestFactory(t)
+
+	config := &TopologyConfig{
+		Definitions: []AgentSpec{
+			{Name: "a", Ref: "b"},
+			{Name: "b", Ref: "a"},
+			{Name: "dup", Type: "echo"},
+			{Name: "dup", Type: "echo"},
+		},
+		Root: AgentSpec{
+			Type: TopologySequential,
+			Agents: []AgentSpec{
+				{Type: "llm"},
+				{Ref: "missing"},
+				{Type: TopologyParallel, Params: map[string]interface{}{"aggregator": "best"}},
+				{Type: "echo", Agents: []AgentSpec{{Type: "echo"}}},
+				{},
+			},
+		},
+	}
+
+	err := config.Validate(factory)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		"reference cycle",
+		"definitions[3]: duplicate definition 'dup'",
+		"root.agents[0]: unknown agent type 'llm' (registered: echo)",
+		"root.agents[1]: unknown reference 'missing'",
+		"root.agents[2]: parallel requires at least one agent",
+		"root.agents[2]: unknown aggregator best",
+		"root.agents[3]: agent type 'echo' cannot have child agents",
+		"root.agents[4]: either type or ref is required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in:\n%v", want, err)
+		}
+	}
+
+	if _, err := BuildFromConfig(config, factory); err == nil || !strings.Contains(err.Error(), "invalid topology config") {
+		t.Errorf("expected BuildFromConfig to reject invalid config, got %v", err)
+	}
+}
+
+func TestTopologyConfig_RouterValidation(t *testing.T) {
+	factory, _ := newTestFactory(t)
+	config := &TopologyConfig{Root: AgentSpec{
+		Type:   TopologyRouter,
+		Params: map[string]interface{}{"keywords": map[string]interface{}{"sales": []interface{}{"buy"}}},
+		Routes: map[string]AgentSpec{"support": {Type: "echo"}},
+	}}
+
+	err := config.Validate(factory)
+	if err == nil || !strings.Contains(err.Error(), "keywords for unknown route 'sales'") {
+		t.Errorf("expected unknown route error, got %v", err)
+	}
+}
+
+func TestParseTopologyConfig_UnknownField(t *testing.T) {
+	if _, err := ParseTopologyConfig([]byte("root:\n  type: echo\n  agnets: []\n")); err == nil {
+		t.Error("expected error for misspelled field")
+	}
+}
+
+func TestBuildFromConfig_ConstructorError(t *testing.T) {
+	factory := agenkit.NewAgentFactory()
+	_ = factory.Register("broken", func(name string, params map[string]interface{}) (agenkit.Agent, error) {
+		return nil, errors.New("no credentials")
+	})
+	config := &TopologyConfig{Root: AgentSpec{Type: TopologySequential, Agents: []AgentSpec{{Type: "broken"}}}}
+
+	_, err := BuildFromConfig(config, factory)
+	if err == nil || !strings.Contains(err.Error(), "root.agents[0]") || !strings.Contains(err.Error(), "no credentials") {
+		t.Errorf("expected located constructor error, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ErrNoVotes is returned when every response abstains from a vote.
+var ErrNoVotes = errors.New("no votes cast")
+
+// ConsensusVoter decides a ConsensusAgent's result from its agents' full
+// responses, metadata included, so votes can depend on structured fields
+// or confidence rather than just the response text.
+type ConsensusVoter interface {
+	// Vote returns the consensus message for responses, in agent order.
+	Vote(ctx context.Context, responses []*agenkit.Message) (*agenkit.Message, error)
+}
+
+// ConsensusVoterFunc adapts a function to ConsensusVoter.
+type ConsensusVoterFunc func(ctx context.Context, responses []*agenkit.Message) (*agenkit.Message, error)
+
+// Vote calls f.
+func (f ConsensusVoterFunc) Vote(ctx context.Context, responses []*agenkit.Message) (*agenkit.Message, error) {
+	return f(ctx, responses)
+}
+
+// DecisionExtractor returns the option a response votes for, or "" to
+// abstain.
+type DecisionExtractor func(response *agenkit.Message) string
+
+// ContentDecision votes for the response's trimmed content.
+func ContentDecision(response *agenkit.Message) string {
+	return strings.TrimSpace(response.ContentString())
+}
+
+// MetadataDecision returns an extractor that votes for the value of a
+// metadata field, for agents that report a structured decision alongside
+// free-text reasoning. Responses without the field abstain.
+func MetadataDecision(key string) DecisionExtractor {
+	return func(response *agenkit.Message) string {
+		value, ok := response.Metadata[key]
+		if !ok || value == nil {
+			return ""
+		}
+		return strings.TrimSpace(fmt.Sprint(value))
+	}
+}
+
+// BallotVoter is a ConsensusVoter that extracts one option per response and
+// tallies them by VotingStrategy:
+//   - VotingMajority: one vote each; consensus if the winner has more than
+//     half the votes
+//   - VotingUnanimous: one vote each; consensus only if all votes agree
+//   - VotingWeighted: votes weighted by the response's confidence;
+//     consensus if the winner has more than half the total weight
+//
+// The winner is the option with the most (weighted) votes, ties going to
+// the option voted for first. The result's content is the winning option,
+// with metadata "decision", "consensus_reached", "vote_tally",
+// "voting_strategy", "votes_cast" and "abstained".
+//
+// Example:
+//
+//	voter := patterns.NewBallotVoter(patterns.VotingWeighted, patterns.MetadataDecision("verdict")).
+//	    WithMinConfidence(0.6)
+//	consensus := patterns.NewConsensusAgent(patterns.VotingWeighted).WithVoter(voter)
+type BallotVoter struct {
+	strategy      VotingStrategy
+	extract       DecisionExtractor
+	confidenceKey string
+	minConfidence float64
+}
+
+// NewBallotVoter creates a voter using strategy (default VotingMajority)
+// and extract (default ContentDecision).
+func NewBallotVoter(strategy VotingStrategy, extract DecisionExtractor) *BallotVoter {
+	if strategy == "" {
+		strategy = VotingMajority
+	}
+	if extract == nil {
+		extract = ContentDecision
+	}
+	return &BallotVoter{
+		strategy:      strategy,
+		extract:       extract,
+		confidenceKey: "confidence",
+	}
+}
+
+// WithConfidenceKey sets the metadata key holding each response's
+// confidence (default "confidence") and returns the voter for chaining.
+func (b *BallotVoter) WithConfidenceKey(key string) *BallotVoter {
+	b.confidenceKey = key
+	return b
+}
+
+// WithMinConfidence makes responses whose confidence is below min abstain
+// and returns the voter for chaining. Responses without a confidence
+// always vote.
+func (b *BallotVoter) WithMinConfidence(min float64) *BallotVoter {
+	b.minConfidence = min
+	return b
+}
+
+// Vote tallies responses.
+//
+// Returns an error wrapping ErrNoVotes if every response abstains.
+func (b *BallotVoter) Vote(ctx context.Context, responses []*agenkit.Message) (*agenkit.Message, error) {
+	tally := make(map[string]float64)
+	var order []string
+	total := 0.0
+	abstained := 0
+
+	for _, response := range responses {
+		if response == nil {
+			abstained++
+			continue
+		}
+		confidence, hasConfidence := metadataFloat(response.Metadata, b.confidenceKey)
+		if hasConfidence && confidence < b.minConfidence {
+			abstained++
+			continue
+		}
+		decision := b.extract(response)
+		if decision == "" {
+			abstained++
+			continue
+		}
+
+		weight := 1.0
+		if b.strategy == VotingWeighted && hasConfidence {
+			weight = confidence
+		}
+		if _, seen := tally[decision]; !seen {
+			order = append(order, decision)
+		}
+		tally[decision] += weight
+		total += weight
+	}
+
+	if len(order) == 0 {
+		return nil, fmt.Errorf("%w: all %d responses abstained", ErrNoVotes, len(responses))
+	}
+
+	winner := order[0]
+	for _, decision := range order[1:] {
+		if tally[decision] > tally[winner] {
+			winner = decision
+		}
+	}
+
+	reached := tally[winner] > total/2
+	if b.strategy == VotingUnanimous {
+		reached = len(order) == 1
+	}
+
+	result := agenkit.NewMessage(agenkit.RoleAssistant, winner)
+	result.WithMetadata("decision", winner).
+		WithMetadata("consensus_reached", reached).
+		WithMetadata("vote_tally", tally).
+		WithMetadata("voting_strategy", string(b.strategy)).
+		WithMetadata("votes_cast", len(responses)-abstained).
+		WithMetadata("abstained", abstained)
+	return result, nil
+}
+
+// metadataFloat reads a numeric metadata value.
+func metadataFloat(metadata map[string]interface{}, key string) (float64, bool) {
+	return numberValue(metadata[key])
+}
+
+// numberValue converts a numeric value to a float64.
+func numberValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,113 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func votes(entries ...map[string]interface{}) []*agenkit.Message {
+	responses := make([]*agenkit.Message, len(entries))
+	for i, entry := range entries {
+		content, _ := entry["content"].(string)
+		responses[i] = agenkit.NewMessage("assistant", content)
+		for key, value := range entry {
+			if key != "content" {
+				responses[i].Metadata[key] = value
+			}
+		}
+	}
+	return responses
+}
+
+func TestBallotVoter_MajorityByContent(t *testing.T) {
+	voter := NewBallotVoter("", nil)
+	result, err := voter.Vote(context.Background(), votes(
+		map[string]interface{}{"content": "yes"},
+		map[string]interface{}{"content": " no "},
+		map[string]interface{}{"content": "yes\n"},
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "yes" {
+		t.Errorf("expected 'yes', got %q", result.ContentString())
+	}
+	if result.Metadata["consensus_reached"] != true {
+		t.Error("expected consensus reached with 2 of 3 votes")
+	}
+	if tally := result.Metadata["vote_tally"].(map[string]float64); tally["no"] != 1 {
+		t.Errorf("unexpected tally %v", tally)
+	}
+}
+
+func TestBallotVoter_WeightedByConfidence(t *testing.T) {
+	voter := NewBallotVoter(VotingWeighted, MetadataDecision("verdict"))
+	result, err := voter.Vote(context.Background(), votes(
+		map[string]interface{}{"content": "Looks fine to me", "verdict": "approve", "confidence": 0.3},
+		map[string]interface{}{"content": "Probably ok", "verdict": "approve", "confidence": 0.3},
+		map[string]interface{}{"content": "Clear policy violation", "verdict": "reject", "confidence": 0.95},
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "reject" {
+		t.Errorf("expected confident 'reject' to win, got %q", result.ContentString())
+	}
+	if result.Metadata["consensus_reached"] != true {
+		t.Error("expected weighted consensus with 0.95 of 1.55")
+	}
+}
+
+func TestBallotVoter_MinConfidenceAbstains(t *testing.T) {
+	voter := NewBallotVoter(VotingUnanimous, nil).WithMinConfidence(0.5)
+	result, err := voter.Vote(context.Background(), votes(
+		map[string]interface{}{"content": "buy", "confidence": 0.9},
+		map[string]interface{}{"content": "sell", "confidence": 0.2},
+		map[string]interface{}{"content": "buy"},
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["consensus_reached"] != true {
+		t.Error("expected unanimity once the low-confidence vote abstains")
+	}
+	if result.Metadata["abstained"] != 1 || result.Metadata["votes_cast"] != 2 {
+		t.Errorf("unexpected vote counts %v", result.Metadata)
+	}
+
+	_, err = voter.Vote(context.Background(), votes(map[string]interface{}{"content": "sell", "confidence": 0.1}))
+	if !errors.Is(err, ErrNoVotes) {
+		t.Errorf("expected ErrNoVotes, got %v", err)
+	}
+}
+
+func TestBallotVoter_UnanimousNotReached(t *testing.T) {
+	result, err := NewBallotVoter(VotingUnanimous, nil).Vote(context.Background(), votes(
+		map[string]interface{}{"content": "a"},
+		map[string]interface{}{"content": "b"},
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "a" || result.Metadata["consensus_reached"] != false {
+		t.Errorf("expected first option without consensus, got %q %v", result.ContentString(), result.Metadata["consensus_reached"])
+	}
+}
+
+func TestConsensusAgent_WithVoter(t *testing.T) {
+	consensus := NewConsensusAgent(VotingWeighted).
+		WithVoter(NewBallotVoter(VotingWeighted, MetadataDecision("verdict")))
+	consensus.AddAgent(scoringAgent("a", "It is spam", map[string]interface{}{"verdict": "spam", "confidence": 0.8}))
+	consensus.AddAgent(scoringAgent("b", "Seems legitimate", map[string]interface{}{"verdict": "ham", "confidence": 0.4}))
+
+	result, err := consensus.Process(context.Background(), agenkit.NewMessage("user", "classify"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "spam" {
+		t.Errorf("expected 'spam', got %q", result.ContentString())
+	}
+}
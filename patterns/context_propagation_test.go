@@ -0,0 +1,112 @@
+package patterns
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// tenantRecordingAgent records the tenant ID seen on every call.
+func tenantRecordingAgent(name string, seen *[]string, mu *sync.Mutex) *extendedMockAgent {
+	return &extendedMockAgent{
+		name: name,
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			tenant := ""
+			if rc, ok := agenkit.RequestContextFromContext(ctx); ok {
+				tenant = rc.TenantID
+			}
+			mu.Lock()
+			*seen = append(*seen, tenant)
+			mu.Unlock()
+			return agenkit.NewMessage("assistant", name), nil
+		},
+	}
+}
+
+// processor is the subset of agent behaviour exercised by propagation tests.
+type processor interface {
+	Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error)
+}
+
+func TestRequestContext_PropagatesThroughPatterns(t *testing.T) {
+	firstMessage := func(msgs []*agenkit.Message) *agenkit.Message { return msgs[0] }
+
+	tests := []struct {
+		name  string
+		build func(agents []agenkit.Agent) (processor, error)
+	}{
+		{"SequentialAgent", func(agents []agenkit.Agent) (processor, error) {
+			return NewSequentialAgent(agents)
+		}},
+		{"ParallelAgent", func(agents []agenkit.Agent) (processor, error) {
+			return NewParallelAgent(agents, firstMessage)
+		}},
+		{"SequentialPattern", func(agents []agenkit.Agent) (processor, error) {
+			return NewSequentialPattern(agents, nil)
+		}},
+		{"ParallelPattern", func(agents []agenkit.Agent) (processor, error) {
+			return NewParallelPattern(agents, firstMessage, nil)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var seen []string
+			agents := []agenkit.Agent{
+				tenantRecordingAgent("a", &seen, &mu),
+				tenantRecordingAgent("b", &seen, &mu),
+			}
+
+			pattern, err := tt.build(agents)
+			if err != nil {
+				t.Fatalf("failed to build pattern: %v", err)
+			}
+
+			ctx := agenkit.WithRequestContext(context.Background(), agenkit.RequestContext{TenantID: "acme"})
+			if _, err := pattern.Process(ctx, agenkit.NewMessage("user", "hi")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(seen) != 2 {
+				t.Fatalf("expected 2 child calls, got %d", len(seen))
+			}
+			for _, tenant := range seen {
+				if tenant != "acme" {
+					t.Errorf("expected child to see tenant 'acme', got %q", tenant)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestContext_PropagatesThroughFallback(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	failing := &extendedMockAgent{
+		name: "failing",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			rc, _ := agenkit.RequestContextFromContext(ctx)
+			mu.Lock()
+			seen = append(seen, rc.TenantID)
+			mu.Unlock()
+			return nil, context.DeadlineExceeded
+		},
+	}
+
+	fallback, err := NewFallbackAgent([]agenkit.Agent{failing, tenantRecordingAgent("backup", &seen, &mu)})
+	if err != nil {
+		t.Fatalf("failed to create fallback agent: %v", err)
+	}
+
+	ctx := agenkit.WithRequestContext(context.Background(), agenkit.RequestContext{TenantID: "acme"})
+	if _, err := fallback.Process(ctx, agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(seen) != 2 || seen[0] != "acme" || seen[1] != "acme" {
+		t.Errorf("expected both agents to see tenant 'acme', got %v", seen)
+	}
+}
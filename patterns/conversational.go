@@ -8,6 +8,8 @@
 //   - Context window limiting
 //   - Automatic history pruning
 //   - Support for system prompts
+//   - Forking and checkpoint/restore for exploring alternative continuations
+//   - History export/import in any codec (JSON, MessagePack, Protobuf)
 //
 // Example:
 //
@@ -39,8 +41,10 @@ package patterns
 import (
 	"context"
 	"fmt"
+	"log/slog"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
+	"github.com/scttfrdmn/agenkit-go/codec"
 )
 
 // LLMClient is the interface for conversational agents.
@@ -62,6 +66,9 @@ type ConversationalAgentConfig struct {
 	SystemPrompt string
 	// IncludeSystem determines whether to include system prompt in history count (default: true)
 	IncludeSystem bool
+	// Compressor optionally shortens history before MaxHistory pruning,
+	// for example a SummarizingCompressor (default: none)
+	Compressor HistoryCompressor
 }
 
 // ConversationalAgent maintains conversation history for context-aware responses.
@@ -74,13 +81,18 @@ type ConversationalAgentConfig struct {
 //   - System messages are always preserved
 //   - Oldest user/assistant messages are removed first
 //   - Both input and response messages are added to history
+//   - If a Compressor is configured, it runs before pruning; set MaxHistory
+//     above the compressor's threshold so pruning doesn't preempt it
 type ConversationalAgent struct {
 	name          string
 	llmClient     LLMClient
 	maxHistory    int
 	systemPrompt  string
 	includeSystem bool
+	compressor    HistoryCompressor
 	history       []*agenkit.Message
+
+	lazy lazyInit
 }
 
 // NewConversationalAgent creates a new conversational agent.
@@ -109,13 +121,14 @@ func NewConversationalAgent(config *ConversationalAgentConfig) (*ConversationalA
 		maxHistory:    maxHistory,
 		systemPrompt:  config.SystemPrompt,
 		includeSystem: includeSystem,
+		compressor:    config.Compressor,
 		history:       make([]*agenkit.Message, 0),
 	}
 
 	// Add system prompt to history if provided
 	if config.SystemPrompt != "" && includeSystem {
 		agent.history = append(agent.history, &agenkit.Message{
-			Role:    "system",
+			Role:    agenkit.RoleSystem,
 			Content: config.SystemPrompt,
 		})
 	}
@@ -141,11 +154,21 @@ func (c *ConversationalAgent) Capabilities() []string {
 // Note:
 // Both the input message and the response are added to history.
 // If history exceeds maxHistory, oldest non-system messages are removed.
-func (c *ConversationalAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+func (c *ConversationalAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
 	// Add user message to history
-	c.history = append(c.history, message)
+	c.history = append(c.history, withCanonicalRole(message))
 
-	// Prune history if needed (keep system prompt if present)
+	// Compress, then prune history if needed (keep system prompt if present)
+	c.compressHistory(ctx)
 	c.pruneHistory()
 
 	// Generate response with full context
@@ -159,7 +182,7 @@ func (c *ConversationalAgent) Process(ctx context.Context, message *agenkit.Mess
 	}
 
 	// Add response to history
-	c.history = append(c.history, response)
+	c.history = append(c.history, withCanonicalRole(response))
 
 	// Prune again after adding response
 	c.pruneHistory()
@@ -167,6 +190,22 @@ func (c *ConversationalAgent) Process(ctx context.Context, message *agenkit.Mess
 	return response, nil
 }
 
+// compressHistory applies the configured compressor. On failure the history
+// is left as is, so pruning still bounds it.
+func (c *ConversationalAgent) compressHistory(ctx context.Context) {
+	if c.compressor == nil {
+		return
+	}
+
+	compressed, err := c.compressor.Compress(ctx, c.history)
+	if err != nil {
+		Logger().WarnContext(ctx, LogEventAgentError,
+			slog.String("pattern", c.name), slog.String("stage", "compress"), slog.Any("error", err))
+		return
+	}
+	c.history = compressed
+}
+
 // pruneHistory prunes history to stay within maxHistory limit.
 //
 // System messages are preserved, and oldest user/assistant messages
@@ -181,7 +220,7 @@ func (c *ConversationalAgent) pruneHistory() {
 	conversationMessages := make([]*agenkit.Message, 0)
 
 	for _, msg := range c.history {
-		if msg.Role == "system" {
+		if msg.Role == agenkit.RoleSystem {
 			systemMessages = append(systemMessages, msg)
 		} else {
 			conversationMessages = append(conversationMessages, msg)
@@ -211,7 +250,7 @@ func (c *ConversationalAgent) ClearHistory(keepSystem bool) {
 	if keepSystem && c.systemPrompt != "" && c.includeSystem {
 		c.history = []*agenkit.Message{
 			{
-				Role:    "system",
+				Role:    agenkit.RoleSystem,
 				Content: c.systemPrompt,
 			},
 		}
@@ -252,3 +291,114 @@ func (c *ConversationalAgent) SetMaxHistory(max int) {
 	c.maxHistory = max
 	c.pruneHistory()
 }
+
+// ConversationCheckpoint is a saved copy of a ConversationalAgent's
+// history, created by Checkpoint and applied with Restore.
+type ConversationCheckpoint struct {
+	history []*agenkit.Message
+}
+
+// Len returns the number of messages in the checkpoint.
+func (c *ConversationCheckpoint) Len() int {
+	return len(c.history)
+}
+
+// Fork returns an independent copy of the agent for exploring an
+// alternative continuation. The fork starts with a deep copy of the current
+// history and the same configuration and system prompt; from then on the two
+// diverge without affecting each other. The LLM client and compressor are
+// shared, so they must be safe for use by both agents.
+//
+// Example:
+//
+//	alternative := agent.Fork()
+//	formal, _ := agent.Process(ctx, agenkit.NewMessage("user", "Reply formally"))
+//	casual, _ := alternative.Process(ctx, agenkit.NewMessage("user", "Reply casually"))
+func (c *ConversationalAgent) Fork() *ConversationalAgent {
+	return &ConversationalAgent{
+		name:          c.name,
+		llmClient:     c.llmClient,
+		maxHistory:    c.maxHistory,
+		systemPrompt:  c.systemPrompt,
+		includeSystem: c.includeSystem,
+		compressor:    c.compressor,
+		history:       cloneHistory(c.history),
+	}
+}
+
+// Checkpoint saves the current history so the conversation can later be
+// rolled back with Restore, for example to undo a turn.
+func (c *ConversationalAgent) Checkpoint() *ConversationCheckpoint {
+	return &ConversationCheckpoint{history: cloneHistory(c.history)}
+}
+
+// Restore replaces the history with a checkpoint's. The checkpoint is
+// copied, so it can be restored again later. A checkpoint from a fork may be
+// restored into the agent it was forked from and vice versa.
+func (c *ConversationalAgent) Restore(checkpoint *ConversationCheckpoint) error {
+	if checkpoint == nil {
+		return fmt.Errorf("checkpoint is required")
+	}
+	c.history = cloneHistory(checkpoint.history)
+	c.pruneHistory()
+	return nil
+}
+
+// ExportHistory encodes the conversation history with c (nil uses JSON),
+// for persisting a conversation or moving it to another process.
+//
+// Example:
+//
+//	data, err := agent.ExportHistory(msgpack.Codec)
+func (c *ConversationalAgent) ExportHistory(cd codec.Codec) ([]byte, error) {
+	return codec.MarshalMessages(cd, c.history)
+}
+
+// ImportHistory replaces the history with one encoded by ExportHistory
+// using the same codec (nil uses JSON). The imported history is pruned to
+// the agent's limit.
+func (c *ConversationalAgent) ImportHistory(data []byte, cd codec.Codec) error {
+	history, err := codec.UnmarshalMessages(cd, data)
+	if err != nil {
+		return fmt.Errorf("failed to import history: %w", err)
+	}
+	for i, message := range history {
+		history[i] = withCanonicalRole(message)
+	}
+	c.history = history
+	c.pruneHistory()
+	return nil
+}
+
+// withCanonicalRole returns message, or a copy with its role normalized
+// if it uses a legacy role such as "agent", so history pruning and the LLM
+// client see one spelling of each role.
+func withCanonicalRole(message *agenkit.Message) *agenkit.Message {
+	if message == nil {
+		return nil
+	}
+	role := agenkit.NormalizeRole(message.Role)
+	if role == message.Role {
+		return message
+	}
+	normalized := *message
+	normalized.Role = role
+	return &normalized
+}
+
+// cloneHistory copies messages and their metadata so the copy can be
+// modified independently.
+func cloneHistory(history []*agenkit.Message) []*agenkit.Message {
+	cloned := make([]*agenkit.Message, len(history))
+	for i, msg := range history {
+		msgCopy := *msg
+		if msg.Metadata != nil {
+			msgCopy.Metadata = make(map[string]interface{}, len(msg.Metadata))
+			for k, v := range msg.Metadata {
+				msgCopy.Metadata[k] = v
+			}
+		}
+		cloned[i] = &msgCopy
+	}
+	return cloned
+}
@@ -7,6 +7,9 @@ import (
 	"testing"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
+	"github.com/scttfrdmn/agenkit-go/codec"
+	"github.com/scttfrdmn/agenkit-go/codec/msgpack"
+	"github.com/scttfrdmn/agenkit-go/codec/protobuf"
 )
 
 // mockLLMClient is a mock LLM client for testing.
@@ -519,3 +522,176 @@ func TestConversationalAgent_HistoryLengthProperty(t *testing.T) {
 		t.Errorf("expected 4 messages after 2 turns, got %d", agent.HistoryLength())
 	}
 }
+
+// ============================================================================
+// Fork and Checkpoint Tests
+// ============================================================================
+
+func TestConversationalAgent_ForkDivergesIndependently(t *testing.T) {
+	client := &mockLLMClient{responses: []string{"Hi Alice", "Formal reply", "Casual reply"}}
+	agent, err := NewConversationalAgent(&ConversationalAgentConfig{
+		LLMClient:     client,
+		MaxHistory:    10,
+		SystemPrompt:  "You are helpful.",
+		IncludeSystem: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := agent.Process(ctx, agenkit.NewMessage("user", "I'm Alice")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fork := agent.Fork()
+	if fork.HistoryLength() != 3 {
+		t.Fatalf("expected fork to start with 3 messages, got %d", fork.HistoryLength())
+	}
+
+	if _, err := agent.Process(ctx, agenkit.NewMessage("user", "Be formal")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := fork.Process(ctx, agenkit.NewMessage("user", "Be casual")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	original, forked := agent.GetHistory(), fork.GetHistory()
+	if len(original) != 5 || len(forked) != 5 {
+		t.Fatalf("expected 5 messages each, got %d and %d", len(original), len(forked))
+	}
+	if original[0].ContentString() != "You are helpful." || forked[0].ContentString() != "You are helpful." {
+		t.Error("expected both to keep the system prompt")
+	}
+	if original[4].ContentString() != "Formal reply" || forked[4].ContentString() != "Casual reply" {
+		t.Errorf("expected divergent replies, got %q and %q", original[4].ContentString(), forked[4].ContentString())
+	}
+
+	// Mutating a forked message's metadata must not leak back
+	fork.history[1].Metadata["edited"] = true
+	if _, ok := agent.history[1].Metadata["edited"]; ok {
+		t.Error("expected fork history to be a deep copy")
+	}
+}
+
+func TestConversationalAgent_CheckpointRestore(t *testing.T) {
+	client := &mockLLMClient{responses: []string{"one", "two", "two again"}}
+	agent, err := NewConversationalAgent(&ConversationalAgentConfig{LLMClient: client})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := agent.Process(ctx, agenkit.NewMessage("user", "first")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkpoint := agent.Checkpoint()
+	if checkpoint.Len() != 2 {
+		t.Errorf("expected checkpoint of 2 messages, got %d", checkpoint.Len())
+	}
+
+	if _, err := agent.Process(ctx, agenkit.NewMessage("user", "second")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := agent.Restore(checkpoint); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if agent.HistoryLength() != 2 {
+		t.Fatalf("expected history rolled back to 2 messages, got %d", agent.HistoryLength())
+	}
+
+	// The checkpoint is unaffected by continuing, so it can be restored again
+	if _, err := agent.Process(ctx, agenkit.NewMessage("user", "second, retried")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checkpoint.Len() != 2 {
+		t.Errorf("expected checkpoint unchanged, got %d messages", checkpoint.Len())
+	}
+
+	if err := agent.Restore(nil); err == nil {
+		t.Error("expected error restoring nil checkpoint")
+	}
+}
+
+func TestConversationalAgent_ExportImportHistory(t *testing.T) {
+	client := &mockLLMClient{responses: []string{"Hello Alice"}}
+	agent, err := NewConversationalAgent(&ConversationalAgentConfig{
+		LLMClient:     client,
+		SystemPrompt:  "Be brief.",
+		IncludeSystem: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "I'm Alice")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, c := range []codec.Codec{nil, msgpack.Codec, protobuf.Codec} {
+		data, err := agent.ExportHistory(c)
+		if err != nil {
+			t.Fatalf("ExportHistory failed: %v", err)
+		}
+
+		restored, _ := NewConversationalAgent(&ConversationalAgentConfig{LLMClient: client})
+		if err := restored.ImportHistory(data, c); err != nil {
+			t.Fatalf("ImportHistory failed: %v", err)
+		}
+		history := restored.GetHistory()
+		if len(history) != 3 {
+			t.Fatalf("expected 3 messages, got %d", len(history))
+		}
+		if history[0].Role != "system" || history[1].ContentString() != "I'm Alice" || history[2].ContentString() != "Hello Alice" {
+			t.Errorf("unexpected history %v", history)
+		}
+	}
+
+	if err := agent.ImportHistory([]byte("not json"), nil); err == nil {
+		t.Error("expected error for invalid data")
+	}
+	if agent.HistoryLength() != 3 {
+		t.Errorf("expected history unchanged after a failed import, got %d", agent.HistoryLength())
+	}
+}
+
+func TestConversationalAgent_NormalizesLegacyRoles(t *testing.T) {
+	client := &legacyRoleLLMClient{}
+	agent, err := NewConversationalAgent(&ConversationalAgentConfig{LLMClient: client, MaxHistory: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		response, err := agent.Process(context.Background(), agenkit.NewMessage(agenkit.RoleUser, fmt.Sprintf("turn %d", i)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Role != agenkit.RoleAgent {
+			t.Errorf("expected the response returned unchanged, got role %q", response.Role)
+		}
+	}
+
+	for _, msg := range agent.GetHistory() {
+		if msg.Role != agenkit.RoleUser && msg.Role != agenkit.RoleAssistant {
+			t.Errorf("expected canonical roles in history, got %q", msg.Role)
+		}
+	}
+	// The client saw the first reply as an assistant turn
+	if roles := client.lastRoles; len(roles) != 3 || roles[1] != agenkit.RoleAssistant {
+		t.Errorf("unexpected roles sent to the client: %v", roles)
+	}
+}
+
+// legacyRoleLLMClient replies with the legacy "agent" role and records
+// the roles it was sent.
+type legacyRoleLLMClient struct {
+	lastRoles []string
+}
+
+func (c *legacyRoleLLMClient) Chat(ctx context.Context, messages []*agenkit.Message) (*agenkit.Message, error) {
+	c.lastRoles = c.lastRoles[:0]
+	for _, msg := range messages {
+		c.lastRoles = append(c.lastRoles, msg.Role)
+	}
+	return agenkit.NewMessage(agenkit.RoleAgent, "ok"), nil
+}
@@ -0,0 +1,405 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Cost guard pattern tracks agent spend per session and globally, warning
+// at thresholds and rejecting calls once a limit is reached.
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ErrCostLimitExceeded is returned (wrapped in a *CostLimitError) when a
+// CostGuard rejects a call because a spending limit has been reached.
+var ErrCostLimitExceeded = errors.New("cost limit exceeded")
+
+// CostScope identifies which of a CostGuard's limits a spend figure or
+// alert refers to.
+type CostScope string
+
+const (
+	// CostScopeSession is the spend of a single session
+	CostScopeSession CostScope = "session"
+	// CostScopeGlobal is the spend of every call through the guard
+	CostScopeGlobal CostScope = "global"
+)
+
+// CostLimitError reports a call rejected by a CostGuard. It matches
+// ErrCostLimitExceeded with errors.Is.
+type CostLimitError struct {
+	// Scope is the limit that was reached
+	Scope CostScope
+	// SessionID is the session, for CostScopeSession
+	SessionID string
+	// Spent is the spend counted against the limit
+	Spent float64
+	// Limit is the configured limit
+	Limit float64
+}
+
+// Error implements the error interface.
+func (e *CostLimitError) Error() string {
+	if e.Scope == CostScopeSession {
+		return fmt.Sprintf("session %q cost limit exceeded: spent %.4f of %.4f", e.SessionID, e.Spent, e.Limit)
+	}
+	return fmt.Sprintf("global cost limit exceeded: spent %.4f of %.4f", e.Spent, e.Limit)
+}
+
+// Unwrap returns ErrCostLimitExceeded.
+func (e *CostLimitError) Unwrap() error {
+	return ErrCostLimitExceeded
+}
+
+// CostAlert describes spend crossing a warning threshold.
+type CostAlert struct {
+	// Scope is the limit the threshold belongs to
+	Scope CostScope
+	// SessionID is the session, for CostScopeSession
+	SessionID string
+	// Threshold is the crossed threshold, as a fraction of Limit
+	Threshold float64
+	// Spent is the spend after the call that crossed it
+	Spent float64
+	// Limit is the configured limit
+	Limit float64
+}
+
+// CostGuardConfig configures a CostGuard.
+type CostGuardConfig struct {
+	// SessionLimit caps the spend of each session (0 means unlimited)
+	SessionLimit float64
+	// GlobalLimit caps the spend of every call through the guard
+	// (0 means unlimited)
+	GlobalLimit float64
+	// Window makes the limits rate limits: only spend within the last
+	// Window counts (0 means spend accumulates for the guard's lifetime)
+	Window time.Duration
+	// WarningThresholds are fractions of a limit, in (0, 1], at which
+	// OnWarning fires (default: 0.8)
+	WarningThresholds []float64
+	// OnWarning is called, outside the guard's lock, each time a session's
+	// or the global spend crosses a warning threshold (optional)
+	OnWarning func(CostAlert)
+	// SessionKey is the message metadata key the session ID is read from
+	// when the request context carries none (default: "session_id")
+	SessionKey string
+}
+
+// CostGuard is a process-wide financial safety net for agent calls. It
+// tracks cumulative cost, read from each response's "cost" metadata, per
+// session and across all calls, rejects calls once a session or the whole
+// system has reached its limit, and raises alerts as spend crosses warning
+// thresholds.
+//
+// Limits are checked before a call, since its cost is only known after, so
+// the call that crosses a limit completes and the next one is rejected.
+// Share one guard across every agent that spends money by wrapping each
+// with Wrap. Safe for concurrent use.
+//
+// Example:
+//
+//	guard, _ := patterns.NewCostGuard(patterns.CostGuardConfig{
+//	    SessionLimit: 0.50,
+//	    GlobalLimit:  100,
+//	    Window:       24 * time.Hour,
+//	    OnWarning: func(alert patterns.CostAlert) {
+//	        log.Printf("%s spend at %.0f%% of limit", alert.Scope, alert.Threshold*100)
+//	    },
+//	})
+//	agent := guard.Wrap(llmAgent)
+//	ctx = agenkit.WithRequestContext(ctx, agenkit.RequestContext{SessionID: "conv-42"})
+//	result, err := agent.Process(ctx, message)
+type CostGuard struct {
+	sessionLimit float64
+	globalLimit  float64
+	window       time.Duration
+	thresholds   []float64
+	onWarning    func(CostAlert)
+	sessionKey   string
+
+	mu        sync.Mutex
+	global    costLedger
+	sessions  map[string]*costLedger
+	lastSweep time.Time
+}
+
+// costEntry is one call's cost.
+type costEntry struct {
+	at   time.Time
+	cost float64
+}
+
+// costLedger is the spend of one scope. Entries are only kept when the
+// guard has a window.
+type costLedger struct {
+	total   float64
+	entries []costEntry
+}
+
+// NewCostGuard creates a cost guard.
+//
+// Returns an error if a limit is negative or a warning threshold is
+// outside (0, 1].
+func NewCostGuard(config CostGuardConfig) (*CostGuard, error) {
+	if config.SessionLimit < 0 || config.GlobalLimit < 0 {
+		return nil, fmt.Errorf("cost limits must not be negative")
+	}
+	if config.Window < 0 {
+		return nil, fmt.Errorf("window must not be negative")
+	}
+	thresholds := config.WarningThresholds
+	if thresholds == nil {
+		thresholds = []float64{0.8}
+	}
+	for _, threshold := range thresholds {
+		if threshold <= 0 || threshold > 1 {
+			return nil, fmt.Errorf("warning threshold must be in (0, 1], got %v", threshold)
+		}
+	}
+	sessionKey := config.SessionKey
+	if sessionKey == "" {
+		sessionKey = "session_id"
+	}
+
+	return &CostGuard{
+		sessionLimit: config.SessionLimit,
+		globalLimit:  config.GlobalLimit,
+		window:       config.Window,
+		thresholds:   append([]float64(nil), thresholds...),
+		onWarning:    config.OnWarning,
+		sessionKey:   sessionKey,
+		sessions:     make(map[string]*costLedger),
+	}, nil
+}
+
+// Wrap returns agent guarded by g: each call is checked against the limits
+// and its response's cost is recorded.
+func (g *CostGuard) Wrap(agent agenkit.Agent) agenkit.Agent {
+	return &costGuardedAgent{guard: g, agent: agent}
+}
+
+// SessionOf returns the session a call is accounted to: the request
+// context's SessionID, else the message's session metadata, else "" (the
+// call only counts toward the global limit).
+func (g *CostGuard) SessionOf(ctx context.Context, message *agenkit.Message) string {
+	if rc, ok := agenkit.RequestContextFromContext(ctx); ok && rc.SessionID != "" {
+		return rc.SessionID
+	}
+	if message != nil {
+		if session, ok := message.Metadata[g.sessionKey].(string); ok {
+			return session
+		}
+	}
+	return ""
+}
+
+// GlobalSpend returns the spend across all calls (within the window, if
+// one is configured).
+func (g *CostGuard) GlobalSpend() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.global.spent(time.Now(), g.window)
+}
+
+// SessionSpend returns the spend of session (within the window, if one is
+// configured).
+func (g *CostGuard) SessionSpend(session string) float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ledger, ok := g.sessions[session]
+	if !ok {
+		return 0
+	}
+	return ledger.spent(time.Now(), g.window)
+}
+
+// ResetSession forgets session's spend, e.g. when the session ends. Its
+// spend still counts toward the global limit.
+//
+// With a window, sessions whose spend has all expired are forgotten
+// automatically. Without one, spend never expires, so call ResetSession
+// to bound the memory used by sessions that have ended.
+func (g *CostGuard) ResetSession(session string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.sessions, session)
+}
+
+// Check returns a *CostLimitError if session (may be "") or the system as
+// a whole has reached its limit.
+func (g *CostGuard) Check(session string) error {
+	now := time.Now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.sessionLimit > 0 && session != "" {
+		if ledger, ok := g.sessions[session]; ok {
+			if spent := ledger.spent(now, g.window); spent >= g.sessionLimit {
+				return &CostLimitError{Scope: CostScopeSession, SessionID: session, Spent: spent, Limit: g.sessionLimit}
+			}
+		}
+	}
+	if g.globalLimit > 0 {
+		if spent := g.global.spent(now, g.window); spent >= g.globalLimit {
+			return &CostLimitError{Scope: CostScopeGlobal, Spent: spent, Limit: g.globalLimit}
+		}
+	}
+	return nil
+}
+
+// Record charges cost to session (may be "") and the global spend, raising
+// an alert for each warning threshold crossed.
+func (g *CostGuard) Record(ctx context.Context, session string, cost float64) {
+	if cost <= 0 {
+		return
+	}
+
+	now := time.Now()
+	var alerts []CostAlert
+	g.mu.Lock()
+	alerts = g.charge(&g.global, now, cost, CostScopeGlobal, "", g.globalLimit, alerts)
+	if session != "" {
+		ledger, ok := g.sessions[session]
+		if !ok {
+			ledger = &costLedger{}
+			g.sessions[session] = ledger
+		}
+		alerts = g.charge(ledger, now, cost, CostScopeSession, session, g.sessionLimit, alerts)
+	}
+	g.sweepLocked(now)
+	g.mu.Unlock()
+
+	for _, alert := range alerts {
+		Logger().WarnContext(ctx, LogEventCostWarning,
+			slog.String("pattern", "CostGuard"),
+			slog.String("scope", string(alert.Scope)),
+			slog.String("session", alert.SessionID),
+			slog.Float64("threshold", alert.Threshold),
+			slog.Float64("spent", alert.Spent),
+			slog.Float64("limit", alert.Limit))
+		if g.onWarning != nil {
+			g.onWarning(alert)
+		}
+	}
+}
+
+// sweepLocked forgets sessions with no spend left in the window, at most
+// once per window so the sweep's cost is spread over many calls. Caller
+// must hold mu.
+func (g *CostGuard) sweepLocked(now time.Time) {
+	if g.window <= 0 || now.Sub(g.lastSweep) < g.window {
+		return
+	}
+	g.lastSweep = now
+	for session, ledger := range g.sessions {
+		if ledger.spent(now, g.window) == 0 && len(ledger.entries) == 0 {
+			delete(g.sessions, session)
+		}
+	}
+}
+
+// charge adds cost to ledger and appends an alert for each threshold of
+// limit it crosses. Caller must hold mu.
+func (g *CostGuard) charge(ledger *costLedger, now time.Time, cost float64, scope CostScope, session string, limit float64, alerts []CostAlert) []CostAlert {
+	before := ledger.spent(now, g.window)
+	ledger.total += cost
+	if g.window > 0 {
+		ledger.entries = append(ledger.entries, costEntry{at: now, cost: cost})
+	}
+	if limit <= 0 {
+		return alerts
+	}
+	for _, threshold := range g.thresholds {
+		mark := threshold * limit
+		if before < mark && ledger.total >= mark {
+			alerts = append(alerts, CostAlert{
+				Scope:     scope,
+				SessionID: session,
+				Threshold: threshold,
+				Spent:     ledger.total,
+				Limit:     limit,
+			})
+		}
+	}
+	return alerts
+}
+
+// spent returns the ledger's spend, first dropping entries older than
+// window (if set).
+func (l *costLedger) spent(now time.Time, window time.Duration) float64 {
+	if window <= 0 {
+		return l.total
+	}
+	cutoff := now.Add(-window)
+	expired := 0
+	for expired < len(l.entries) && !l.entries[expired].at.After(cutoff) {
+		expired++
+	}
+	if expired > 0 {
+		l.entries = l.entries[expired:]
+		l.total = 0
+		for _, entry := range l.entries {
+			l.total += entry.cost
+		}
+	}
+	return l.total
+}
+
+// costGuardedAgent is an agent wrapped by a CostGuard.
+type costGuardedAgent struct {
+	guard *CostGuard
+	agent agenkit.Agent
+
+	lazy lazyInit
+}
+
+// Name returns the wrapped agent's name.
+func (a *costGuardedAgent) Name() string {
+	return a.agent.Name()
+}
+
+// Capabilities returns the wrapped agent's capabilities.
+func (a *costGuardedAgent) Capabilities() []string {
+	return a.agent.Capabilities()
+}
+
+// Introspect returns the wrapped agent's introspection information.
+func (a *costGuardedAgent) Introspect() *agenkit.IntrospectionResult {
+	return a.agent.Introspect()
+}
+
+// Process rejects message with a *CostLimitError if its session or the
+// system has reached its limit, otherwise runs the wrapped agent and
+// records the response's "cost" metadata.
+func (a *costGuardedAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := a.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	session := a.guard.SessionOf(ctx, message)
+	if err := a.guard.Check(session); err != nil {
+		return nil, err
+	}
+
+	result, err := ProcessTraced(ctx, a.agent, message)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		if cost, ok := metadataFloat(result.Metadata, "cost"); ok {
+			a.guard.Record(ctx, session, cost)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,173 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// pricedAgent returns a response costing cost.
+func pricedAgent(cost float64) *extendedMockAgent {
+	return &extendedMockAgent{
+		name: "priced",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			return agenkit.NewMessage("agent", "answer").WithMetadata("cost", cost), nil
+		},
+	}
+}
+
+func sessionContext(session string) context.Context {
+	return agenkit.WithRequestContext(context.Background(), agenkit.RequestContext{SessionID: session})
+}
+
+func TestCostGuard_SessionLimit(t *testing.T) {
+	guard, err := NewCostGuard(CostGuardConfig{SessionLimit: 1.0})
+	if err != nil {
+		t.Fatalf("NewCostGuard: %v", err)
+	}
+	agent := guard.Wrap(pricedAgent(0.6))
+
+	for i := 0; i < 2; i++ {
+		if _, err := agent.Process(sessionContext("a"), agenkit.NewMessage("user", "hi")); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+	}
+
+	_, err = agent.Process(sessionContext("a"), agenkit.NewMessage("user", "hi"))
+	var limitErr *CostLimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, ErrCostLimitExceeded) {
+		t.Fatalf("expected CostLimitError, got %v", err)
+	}
+	if limitErr.Scope != CostScopeSession || limitErr.SessionID != "a" {
+		t.Errorf("got scope %q session %q, want session a", limitErr.Scope, limitErr.SessionID)
+	}
+
+	// Other sessions are unaffected
+	if _, err := agent.Process(sessionContext("b"), agenkit.NewMessage("user", "hi")); err != nil {
+		t.Errorf("session b: unexpected error: %v", err)
+	}
+	if spent := guard.SessionSpend("b"); spent != 0.6 {
+		t.Errorf("SessionSpend(b) = %v, want 0.6", spent)
+	}
+}
+
+func TestCostGuard_GlobalLimitSharedAcrossAgents(t *testing.T) {
+	guard, _ := NewCostGuard(CostGuardConfig{GlobalLimit: 1.0})
+	first := guard.Wrap(pricedAgent(0.5))
+	second := guard.Wrap(pricedAgent(0.5))
+
+	if _, err := first.Process(sessionContext("a"), agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := second.Process(sessionContext("b"), agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spent := guard.GlobalSpend(); spent != 1.0 {
+		t.Errorf("GlobalSpend() = %v, want 1.0", spent)
+	}
+
+	_, err := first.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	var limitErr *CostLimitError
+	if !errors.As(err, &limitErr) || limitErr.Scope != CostScopeGlobal {
+		t.Errorf("expected global CostLimitError, got %v", err)
+	}
+}
+
+func TestCostGuard_WarningThresholds(t *testing.T) {
+	var alerts []CostAlert
+	guard, _ := NewCostGuard(CostGuardConfig{
+		SessionLimit:      1.0,
+		WarningThresholds: []float64{0.5, 0.9},
+		OnWarning:         func(alert CostAlert) { alerts = append(alerts, alert) },
+	})
+	agent := guard.Wrap(pricedAgent(0.25))
+
+	for i := 0; i < 4; i++ {
+		if _, err := agent.Process(sessionContext("a"), agenkit.NewMessage("user", "hi")); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+	}
+
+	// 0.25, 0.5 (crosses 0.5), 0.75, 1.0 (crosses 0.9)
+	if len(alerts) != 2 {
+		t.Fatalf("got %d alerts, want 2: %+v", len(alerts), alerts)
+	}
+	if alerts[0].Threshold != 0.5 || alerts[1].Threshold != 0.9 {
+		t.Errorf("thresholds = %v, %v; want 0.5, 0.9", alerts[0].Threshold, alerts[1].Threshold)
+	}
+	if alerts[0].Scope != CostScopeSession || alerts[0].SessionID != "a" {
+		t.Errorf("alert = %+v, want session a", alerts[0])
+	}
+}
+
+func TestCostGuard_Window(t *testing.T) {
+	guard, _ := NewCostGuard(CostGuardConfig{GlobalLimit: 1.0, Window: 30 * time.Millisecond})
+	agent := guard.Wrap(pricedAgent(1.0))
+
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "hi")); !errors.Is(err, ErrCostLimitExceeded) {
+		t.Fatalf("expected ErrCostLimitExceeded within the window, got %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if spent := guard.GlobalSpend(); spent != 0 {
+		t.Errorf("GlobalSpend() = %v after the window, want 0", spent)
+	}
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "hi")); err != nil {
+		t.Errorf("unexpected error after the window: %v", err)
+	}
+}
+
+func TestCostGuard_WindowForgetsExpiredSessions(t *testing.T) {
+	guard, _ := NewCostGuard(CostGuardConfig{SessionLimit: 1.0, Window: 20 * time.Millisecond})
+
+	guard.Record(context.Background(), "a", 0.5)
+	time.Sleep(30 * time.Millisecond)
+	guard.Record(context.Background(), "b", 0.5)
+
+	guard.mu.Lock()
+	_, kept := guard.sessions["a"]
+	sessions := len(guard.sessions)
+	guard.mu.Unlock()
+	if kept || sessions != 1 {
+		t.Errorf("expected only session b to be tracked, got %d sessions (a kept: %v)", sessions, kept)
+	}
+	if spent := guard.SessionSpend("b"); spent != 0.5 {
+		t.Errorf("SessionSpend(b) = %v, want 0.5", spent)
+	}
+}
+
+func TestCostGuard_SessionFromMetadata(t *testing.T) {
+	guard, _ := NewCostGuard(CostGuardConfig{})
+	agent := guard.Wrap(pricedAgent(0.25))
+
+	message := agenkit.NewMessage("user", "hi").WithMetadata("session_id", "meta")
+	if _, err := agent.Process(context.Background(), message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spent := guard.SessionSpend("meta"); spent != 0.25 {
+		t.Errorf("SessionSpend(meta) = %v, want 0.25", spent)
+	}
+
+	guard.ResetSession("meta")
+	if spent := guard.SessionSpend("meta"); spent != 0 {
+		t.Errorf("SessionSpend(meta) = %v after reset, want 0", spent)
+	}
+	if spent := guard.GlobalSpend(); spent != 0.25 {
+		t.Errorf("GlobalSpend() = %v, want 0.25", spent)
+	}
+}
+
+func TestNewCostGuard_InvalidConfig(t *testing.T) {
+	if _, err := NewCostGuard(CostGuardConfig{GlobalLimit: -1}); err == nil {
+		t.Error("expected error for negative limit")
+	}
+	if _, err := NewCostGuard(CostGuardConfig{WarningThresholds: []float64{1.5}}); err == nil {
+		t.Error("expected error for threshold above 1")
+	}
+}
@@ -0,0 +1,33 @@
+package patterns
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// writeDeadLetter sends message to sink, if set, logging instead of
+// returning a sink error so the pattern's own error is what the caller
+// sees.
+func writeDeadLetter(ctx context.Context, sink agenkit.DeadLetterSink, source, reason string, message *agenkit.Message, err error, metadata map[string]interface{}) {
+	if sink == nil {
+		return
+	}
+
+	letter := agenkit.DeadLetter{
+		Message:   message,
+		Source:    source,
+		Reason:    reason,
+		Err:       err,
+		Timestamp: time.Now().UTC(),
+		Metadata:  metadata,
+	}
+	logger := Logger().With(slog.String("pattern", source), slog.String("reason", reason))
+	if sinkErr := sink.WriteDeadLetter(ctx, letter); sinkErr != nil {
+		logger.ErrorContext(ctx, LogEventDeadLetter, slog.Any("error", err), slog.Any("sink_error", sinkErr))
+		return
+	}
+	logger.WarnContext(ctx, LogEventDeadLetter, slog.Any("error", err))
+}
@@ -0,0 +1,170 @@
+package patterns
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// SimilarityFunc scores how similar two messages are, from 0 (unrelated)
+// to 1 (identical).
+type SimilarityFunc func(a, b *agenkit.Message) float64
+
+// WordOverlapSimilarity is the Jaccard similarity of the two messages'
+// lowercased word sets. Punctuation is ignored. Two empty messages are
+// identical.
+func WordOverlapSimilarity(a, b *agenkit.Message) float64 {
+	wordsA := wordSet(a.ContentString())
+	wordsB := wordSet(b.ContentString())
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1.0
+	}
+
+	shared := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA)+len(wordsB)-shared)
+}
+
+// EmbeddingSimilarity returns a SimilarityFunc comparing messages by the
+// cosine similarity of their embeddings, which catches paraphrases that
+// share few words. Embeddings are cached by content; messages that fail to
+// embed are treated as unrelated to everything but identical content.
+//
+// Example:
+//
+//	similarity := patterns.EmbeddingSimilarity(func(text string) ([]float64, error) {
+//	    return embedder.Embed(context.Background(), text)
+//	})
+//	aggregator := patterns.DefaultAggregators.Dedupe(similarity, 0.85)
+func EmbeddingSimilarity(embed func(text string) ([]float64, error)) SimilarityFunc {
+	var mu sync.Mutex
+	cache := make(map[string][]float64)
+
+	lookup := func(text string) []float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		if vector, ok := cache[text]; ok {
+			return vector
+		}
+		vector, err := embed(text)
+		if err != nil {
+			vector = nil
+		}
+		// Bound the cache for long-lived aggregators
+		if len(cache) >= 1024 {
+			cache = make(map[string][]float64)
+		}
+		cache[text] = vector
+		return vector
+	}
+
+	return func(a, b *agenkit.Message) float64 {
+		textA, textB := a.ContentString(), b.ContentString()
+		if textA == textB {
+			return 1.0
+		}
+		return cosineSimilarity(lookup(textA), lookup(textB))
+	}
+}
+
+// messageCluster is a group of near-duplicate messages.
+type messageCluster struct {
+	representative *agenkit.Message
+	members        []int
+}
+
+// dedupe clusters near-duplicate messages and renders one representative
+// per cluster, largest cluster first.
+func dedupe(messages []*agenkit.Message, similarity SimilarityFunc, threshold float64) *agenkit.Message {
+	messages = compactMessages(messages)
+	if len(messages) == 0 {
+		return agenkit.NewMessage(agenkit.RoleAssistant, "No results to aggregate")
+	}
+	if similarity == nil {
+		similarity = WordOverlapSimilarity
+	}
+
+	// Greedy single pass: join the most similar existing cluster, compared
+	// by its representative (first member), or start a new one
+	var clusters []*messageCluster
+	for i, msg := range messages {
+		var best *messageCluster
+		bestScore := threshold
+		for _, cluster := range clusters {
+			if score := similarity(cluster.representative, msg); score >= bestScore {
+				best = cluster
+				bestScore = score
+			}
+		}
+		if best == nil {
+			clusters = append(clusters, &messageCluster{representative: msg, members: []int{i}})
+			continue
+		}
+		best.members = append(best.members, i)
+	}
+
+	// Largest first; equal sizes keep first-appearance order
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].members) > len(clusters[j].members)
+	})
+
+	var combined strings.Builder
+	sizes := make([]int, len(clusters))
+	members := make([][]int, len(clusters))
+	for i, cluster := range clusters {
+		if i > 0 {
+			combined.WriteString("\n\n---\n\n")
+		}
+		if n := len(cluster.members); n > 1 {
+			combined.WriteString(fmt.Sprintf("[%d similar responses] ", n))
+		}
+		combined.WriteString(cluster.representative.ContentString())
+		sizes[i] = len(cluster.members)
+		members[i] = cluster.members
+	}
+
+	return agenkit.NewMessage(agenkit.RoleAssistant, combined.String()).
+		WithMetadata("clusters", len(clusters)).
+		WithMetadata("cluster_sizes", sizes).
+		WithMetadata("cluster_members", members).
+		WithMetadata("total_responses", len(messages))
+}
+
+// wordSet returns the distinct lowercased words in text.
+func wordSet(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b, or 0
+// if they are empty, zero, or of different lengths.
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0.0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0.0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,116 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestWordOverlapSimilarity(t *testing.T) {
+	a := agenkit.NewMessage("assistant", "SQL injection in login handler!")
+	b := agenkit.NewMessage("assistant", "sql injection in the login handler")
+
+	if got := WordOverlapSimilarity(a, b); got != 5.0/6.0 {
+		t.Errorf("expected 5/6, got %v", got)
+	}
+	if got := WordOverlapSimilarity(a, agenkit.NewMessage("assistant", "missing tests")); got != 0 {
+		t.Errorf("expected 0, got %v", got)
+	}
+	empty := agenkit.NewMessage("assistant", "")
+	if got := WordOverlapSimilarity(empty, empty); got != 1 {
+		t.Errorf("expected empty messages to be identical, got %v", got)
+	}
+}
+
+func TestDefaultAggregators_Dedupe(t *testing.T) {
+	reviews := []*agenkit.Message{
+		agenkit.NewMessage("assistant", "Missing unit tests for the parser"),
+		agenkit.NewMessage("assistant", "SQL injection in login handler"),
+		agenkit.NewMessage("assistant", "sql injection in the login handler"),
+		agenkit.NewMessage("assistant", "Possible SQL injection in login handler"),
+		agenkit.NewMessage("assistant", "missing unit tests for parser"),
+		agenkit.NewMessage("assistant", "Variable names are unclear"),
+	}
+
+	result := DefaultAggregators.Dedupe(nil, 0.6)(reviews)
+
+	sections := strings.Split(result.ContentString(), "\n\n---\n\n")
+	expected := []string{
+		"[3 similar responses] SQL injection in login handler",
+		"[2 similar responses] Missing unit tests for the parser",
+		"Variable names are unclear",
+	}
+	if !reflect.DeepEqual(sections, expected) {
+		t.Errorf("unexpected sections:\n%q", sections)
+	}
+
+	if result.Metadata["clusters"] != 3 || result.Metadata["total_responses"] != 6 {
+		t.Errorf("unexpected metadata: %v", result.Metadata)
+	}
+	if sizes := result.Metadata["cluster_sizes"].([]int); !reflect.DeepEqual(sizes, []int{3, 2, 1}) {
+		t.Errorf("unexpected cluster sizes: %v", sizes)
+	}
+	members := result.Metadata["cluster_members"].([][]int)
+	if !reflect.DeepEqual(members, [][]int{{1, 2, 3}, {0, 4}, {5}}) {
+		t.Errorf("unexpected cluster members: %v", members)
+	}
+}
+
+func TestDefaultAggregators_DedupeEmbedding(t *testing.T) {
+	vectors := map[string][]float64{
+		"the build is broken":   {1, 0.1},
+		"CI fails to compile":   {0.9, 0.2},
+		"docs look good":        {0, 1},
+		"unembeddable response": nil,
+	}
+	calls := 0
+	similarity := EmbeddingSimilarity(func(text string) ([]float64, error) {
+		calls++
+		if vectors[text] == nil {
+			return nil, errors.New("embedding failed")
+		}
+		return vectors[text], nil
+	})
+
+	messages := []*agenkit.Message{
+		agenkit.NewMessage("assistant", "the build is broken"),
+		agenkit.NewMessage("assistant", "docs look good"),
+		agenkit.NewMessage("assistant", "CI fails to compile"),
+		agenkit.NewMessage("assistant", "unembeddable response"),
+	}
+	result := DefaultAggregators.Dedupe(similarity, 0.9)(messages)
+
+	if sizes := result.Metadata["cluster_sizes"].([]int); !reflect.DeepEqual(sizes, []int{2, 1, 1}) {
+		t.Errorf("expected paraphrases clustered, got sizes %v", sizes)
+	}
+	if calls != 4 {
+		t.Errorf("expected one embedding call per distinct text, got %d", calls)
+	}
+}
+
+func TestParallelAgent_DedupeAggregator(t *testing.T) {
+	agents := []agenkit.Agent{
+		&extendedMockAgent{name: "r1", response: "Off-by-one error in loop"},
+		&extendedMockAgent{name: "r2", response: "off by one error in the loop"},
+		&extendedMockAgent{name: "r3", response: "Looks fine"},
+	}
+	parallel, err := NewParallelAgent(agents, DefaultAggregators.Dedupe(nil, 0.5))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := parallel.Process(context.Background(), agenkit.NewMessage("user", "review"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(result.ContentString(), "[2 similar responses] Off-by-one error in loop") {
+		t.Errorf("unexpected result: %q", result.ContentString())
+	}
+	if result.Metadata["parallel_agents"] != 3 || result.Metadata["clusters"] != 2 {
+		t.Errorf("unexpected metadata: %v", result.Metadata)
+	}
+}
@@ -0,0 +1,170 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Explain pattern attaches a plain-language account of how a composition
+// produced its response.
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ExplanationKey is the metadata key ExplainAgent stores its narrative
+// under.
+const ExplanationKey = "explanation"
+
+// ExplainAgent wraps an agent, typically a composed pattern, and attaches
+// a plain-English narrative of what it did to each result under
+// ExplanationKey, for example:
+//
+//	Router classified the request as "billing". Router delegated to
+//	BillingSpecialist. BillingSpecialist handled it in 120ms. Router
+//	completed in 121ms.
+//
+// The narrative is rendered from the execution trace (see
+// WithExecutionTrace), so it covers every sub-agent reached through
+// ProcessTraced, and decisions patterns record with AnnotateTrace. Unlike
+// the trace itself it is meant for end users: no inputs or outputs, just
+// who did what and how long it took. Tracing is enabled for the call if
+// ctx doesn't already carry a trace.
+//
+// Example:
+//
+//	explained, _ := patterns.NewExplainAgent(router)
+//	result, err := explained.Process(ctx, message)
+//	fmt.Println(result.Metadata[patterns.ExplanationKey])
+type ExplainAgent struct {
+	agent agenkit.Agent
+
+	lazy lazyInit
+}
+
+// NewExplainAgent creates an agent that explains agent's execution.
+func NewExplainAgent(agent agenkit.Agent) (*ExplainAgent, error) {
+	if agent == nil {
+		return nil, fmt.Errorf("agent is required")
+	}
+	return &ExplainAgent{agent: agent}, nil
+}
+
+// Name returns the wrapped agent's name.
+func (e *ExplainAgent) Name() string {
+	return e.agent.Name()
+}
+
+// Capabilities returns the wrapped agent's capabilities.
+func (e *ExplainAgent) Capabilities() []string {
+	return e.agent.Capabilities()
+}
+
+// Introspect returns the wrapped agent's introspection.
+func (e *ExplainAgent) Introspect() *agenkit.IntrospectionResult {
+	return e.agent.Introspect()
+}
+
+// Process runs the wrapped agent and returns a copy of its result with the
+// narrative under ExplanationKey. Errors are returned unchanged.
+func (e *ExplainAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := e.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	result, node, err := processTracedNode(ctx, e.agent, message)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("agent %s returned no message", e.agent.Name())
+	}
+
+	explained := copyMessage(result)
+	explained.Metadata[ExplanationKey] = node.Narrative()
+	return explained, nil
+}
+
+// Narrative renders the trace as plain-English prose: one narrative per
+// top-level invocation, separated by blank lines. See ExplainAgent.
+func (t *ExecutionTrace) Narrative() string {
+	roots := t.Roots()
+	narratives := make([]string, len(roots))
+	for i, root := range roots {
+		narratives[i] = root.Narrative()
+	}
+	return strings.Join(narratives, "\n\n")
+}
+
+// Narrative renders the invocation and everything beneath it as
+// plain-English prose: each agent's recorded decisions, whom it delegated
+// to, and how long it took or why it failed.
+func (n *TraceNode) Narrative() string {
+	var sentences []string
+	n.narrate(&sentences)
+	return strings.Join(sentences, " ")
+}
+
+// narrate appends n's sentences, then its children's, then n's outcome.
+func (n *TraceNode) narrate(sentences *[]string) {
+	for _, note := range n.Notes {
+		*sentences = append(*sentences, sentence(n.Agent+" "+note))
+	}
+
+	if len(n.Children) > 0 {
+		names := make([]string, 0, len(n.Children))
+		seen := make(map[string]bool)
+		for _, child := range n.Children {
+			if !seen[child.Agent] {
+				seen[child.Agent] = true
+				names = append(names, child.Agent)
+			}
+		}
+		*sentences = append(*sentences, sentence(fmt.Sprintf("%s delegated to %s", n.Agent, joinNames(names))))
+		for _, child := range n.Children {
+			child.narrate(sentences)
+		}
+	}
+
+	duration := narrativeDuration(n.Duration)
+	switch {
+	case n.Error != "":
+		*sentences = append(*sentences, sentence(fmt.Sprintf("%s failed after %s: %s", n.Agent, duration, strings.Join(strings.Fields(n.Error), " "))))
+	case len(n.Children) > 0:
+		*sentences = append(*sentences, sentence(fmt.Sprintf("%s completed in %s", n.Agent, duration)))
+	default:
+		*sentences = append(*sentences, sentence(fmt.Sprintf("%s handled it in %s", n.Agent, duration)))
+	}
+}
+
+// sentence ends text with a full stop unless it already ends a sentence.
+func sentence(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasSuffix(text, ".") || strings.HasSuffix(text, "!") || strings.HasSuffix(text, "?") {
+		return text
+	}
+	return text + "."
+}
+
+// joinNames lists names as "a", "a and b" or "a, b and c".
+func joinNames(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// narrativeDuration rounds d for reading: to the millisecond from 1ms up,
+// otherwise to the microsecond.
+func narrativeDuration(d time.Duration) string {
+	if d >= time.Millisecond {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Microsecond).String()
+}
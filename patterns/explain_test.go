@@ -0,0 +1,107 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestExplainAgent_NarratesRouting(t *testing.T) {
+	router, _ := NewRouterPattern(func(*agenkit.Message) string { return "billing" }, map[string]agenkit.Agent{
+		"billing": &extendedMockAgent{name: "BillingSpecialist", response: "refund issued"},
+	}, &RouterPatternConfig{Name: "Router"})
+	explained, err := NewExplainAgent(router)
+	if err != nil {
+		t.Fatalf("NewExplainAgent: %v", err)
+	}
+
+	input := agenkit.NewMessage("user", "I was charged twice")
+	result, err := explained.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != "refund issued" || result.ParentID != input.ID {
+		t.Errorf("result = %q (parent %q)", result.Content, result.ParentID)
+	}
+
+	explanation, _ := result.Metadata[ExplanationKey].(string)
+	for _, want := range []string{
+		`Router routed the request to "billing".`,
+		"Router delegated to BillingSpecialist.",
+		"BillingSpecialist handled it in",
+		"Router completed in",
+	} {
+		if !strings.Contains(explanation, want) {
+			t.Errorf("explanation missing %q:\n%s", want, explanation)
+		}
+	}
+	if strings.Index(explanation, "routed") > strings.Index(explanation, "handled") {
+		t.Errorf("expected the routing decision before the handler:\n%s", explanation)
+	}
+}
+
+func TestExplainAgent_NarratesFailures(t *testing.T) {
+	primary := &extendedMockAgent{name: "primary", err: errors.New("rate limited")}
+	backup := &extendedMockAgent{name: "backup", response: "ok"}
+	dispatcher := &extendedMockAgent{name: "dispatcher", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		if result, err := ProcessTraced(ctx, primary, msg); err == nil {
+			return result, nil
+		}
+		return ProcessTraced(ctx, backup, msg)
+	}}
+	explained, _ := NewExplainAgent(dispatcher)
+
+	result, err := explained.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	explanation := result.Metadata[ExplanationKey].(string)
+	if !strings.Contains(explanation, "delegated to primary and backup.") ||
+		!strings.Contains(explanation, "primary failed after") || !strings.Contains(explanation, "rate limited") {
+		t.Errorf("unexpected explanation:\n%s", explanation)
+	}
+}
+
+func TestExplainAgent_SharesExistingTrace(t *testing.T) {
+	ctx, trace := WithExecutionTrace(context.Background())
+	explained, _ := NewExplainAgent(&extendedMockAgent{name: "worker", response: "done"})
+
+	if _, err := ProcessTraced(ctx, explained, agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if trace.Len() != 2 {
+		t.Errorf("expected the explained call in the caller's trace, got %d nodes:\n%s", trace.Len(), trace)
+	}
+}
+
+func TestTraceNode_Narrative(t *testing.T) {
+	node := &TraceNode{
+		Agent:    "Supervisor",
+		Notes:    []string{"planned 2 subtasks"},
+		Duration: 1500 * time.Millisecond,
+		Children: []*TraceNode{
+			{Agent: "Researcher", Duration: 800 * time.Millisecond},
+			{Agent: "Writer", Duration: 650 * time.Microsecond, Error: "model\noverloaded"},
+		},
+	}
+
+	want := "Supervisor planned 2 subtasks. Supervisor delegated to Researcher and Writer. " +
+		"Researcher handled it in 800ms. Writer failed after 650µs: model overloaded. Supervisor completed in 1.5s."
+	if got := node.Narrative(); got != want {
+		t.Errorf("Narrative() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAnnotateTrace_WithoutTrace(t *testing.T) {
+	// Must not panic without a trace or outside a traced call
+	AnnotateTrace(context.Background(), "did something")
+	ctx, trace := WithExecutionTrace(context.Background())
+	AnnotateTrace(ctx, "did something")
+	if trace.Len() != 0 {
+		t.Error("annotating outside a traced call should not record anything")
+	}
+}
@@ -0,0 +1,96 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+)
+
+// contextFailure is a pattern-level context error. Its message is the
+// context error's, and errors.Is matches both it and the context error, so
+// existing checks against context.DeadlineExceeded and context.Canceled
+// keep working.
+type contextFailure struct {
+	cause error
+}
+
+// Error implements the error interface.
+func (e *contextFailure) Error() string {
+	return e.cause.Error()
+}
+
+// Is reports whether target is the underlying context error.
+func (e *contextFailure) Is(target error) bool {
+	return target == e.cause
+}
+
+var (
+	// ErrDeadlineExceeded is wrapped by the error a pattern returns when
+	// its own context's deadline passes, or a Task times out, as opposed
+	// to an error produced by an agent. errors.Is also matches it against
+	// context.DeadlineExceeded.
+	ErrDeadlineExceeded error = &contextFailure{cause: context.DeadlineExceeded}
+	// ErrCanceled is wrapped by the error a pattern returns when its own
+	// context is cancelled. errors.Is also matches it against
+	// context.Canceled.
+	ErrCanceled error = &contextFailure{cause: context.Canceled}
+)
+
+// FailureCauseKey is the metadata key patterns record a failure's cause
+// under (see FailureCause), alongside the error message of a failed
+// sub-agent or stage.
+const FailureCauseKey = "failure_cause"
+
+// Failure causes returned by FailureCause.
+const (
+	// FailureCauseDeadlineExceeded means time ran out: a deadline or
+	// timeout passed
+	FailureCauseDeadlineExceeded = "deadline_exceeded"
+	// FailureCauseCanceled means the work was cancelled
+	FailureCauseCanceled = "canceled"
+	// FailureCauseAgentError means an agent (or tool) failed on its own
+	FailureCauseAgentError = "agent_error"
+)
+
+// FailureCause classifies err as FailureCauseDeadlineExceeded,
+// FailureCauseCanceled or FailureCauseAgentError, so callers can tell
+// "we ran out of time" from "the agent failed". It returns "" for nil.
+//
+// Example:
+//
+//	if _, err := pipeline.Process(ctx, message); err != nil {
+//	    metrics.Inc("failures", patterns.FailureCause(err))
+//	}
+func FailureCause(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return FailureCauseDeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return FailureCauseCanceled
+	default:
+		return FailureCauseAgentError
+	}
+}
+
+// IsContextFailure reports whether err is a pattern's context running out
+// (ErrDeadlineExceeded or ErrCanceled) rather than an agent error. Retrying
+// such a failure under the same context cannot succeed.
+func IsContextFailure(err error) bool {
+	return errors.Is(err, ErrDeadlineExceeded) || errors.Is(err, ErrCanceled)
+}
+
+// contextError returns ErrDeadlineExceeded or ErrCanceled if ctx is done,
+// or nil. Patterns return it (wrapped) in place of ctx.Err().
+func contextError(ctx context.Context) error {
+	switch err := ctx.Err(); {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrDeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return ErrCanceled
+	default:
+		return err
+	}
+}
@@ -0,0 +1,116 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+	"github.com/scttfrdmn/agenkit-go/middleware"
+)
+
+func TestFailureCause(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{errors.New("rate limited"), FailureCauseAgentError},
+		{ErrDeadlineExceeded, FailureCauseDeadlineExceeded},
+		{fmt.Errorf("stage 2: %w", context.DeadlineExceeded), FailureCauseDeadlineExceeded},
+		{&TimeoutError{Duration: time.Second}, FailureCauseDeadlineExceeded},
+		{&middleware.TimeoutError{AgentName: "slow", Timeout: time.Second}, FailureCauseDeadlineExceeded},
+		{fmt.Errorf("pipeline cancelled: %w", ErrCanceled), FailureCauseCanceled},
+	}
+	for _, tt := range tests {
+		if got := FailureCause(tt.err); got != tt.want {
+			t.Errorf("FailureCause(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestContextSentinels(t *testing.T) {
+	if !errors.Is(ErrDeadlineExceeded, context.DeadlineExceeded) || !errors.Is(ErrCanceled, context.Canceled) {
+		t.Error("sentinels should match the context errors")
+	}
+	if errors.Is(ErrCanceled, context.DeadlineExceeded) || errors.Is(ErrDeadlineExceeded, context.Canceled) {
+		t.Error("sentinels should not match each other's context error")
+	}
+	if ErrCanceled.Error() != context.Canceled.Error() {
+		t.Errorf("ErrCanceled message = %q, want the context error's", ErrCanceled.Error())
+	}
+	if IsContextFailure(context.Canceled) {
+		t.Error("a bare context error from an agent is not a pattern context failure")
+	}
+}
+
+func TestSequentialAgent_CancelledReportsErrCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	pipeline, _ := NewSequentialAgent([]agenkit.Agent{&extendedMockAgent{name: "a", response: "ok"}})
+	_, err := pipeline.Process(ctx, agenkit.NewMessage("user", "hi"))
+	if !errors.Is(err, ErrCanceled) || !errors.Is(err, context.Canceled) || !IsContextFailure(err) {
+		t.Fatalf("expected ErrCanceled, got %v", err)
+	}
+	if FailureCause(err) != FailureCauseCanceled {
+		t.Errorf("FailureCause = %q, want canceled", FailureCause(err))
+	}
+}
+
+func TestFallbackAgent_RecordsFailureCause(t *testing.T) {
+	slow := &extendedMockAgent{name: "slow", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		return nil, fmt.Errorf("upstream: %w", context.DeadlineExceeded)
+	}}
+	broken := &extendedMockAgent{name: "broken", err: errors.New("bad request")}
+	fallback, _ := NewFallbackAgent([]agenkit.Agent{slow, broken, &extendedMockAgent{name: "ok", response: "done"}})
+
+	result, err := fallback.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	failed, _ := result.Metadata["fallback_failed_attempts"].([]map[string]interface{})
+	if len(failed) != 2 {
+		t.Fatalf("failed attempts = %v", result.Metadata["fallback_failed_attempts"])
+	}
+	if failed[0][FailureCauseKey] != FailureCauseDeadlineExceeded || failed[1][FailureCauseKey] != FailureCauseAgentError {
+		t.Errorf("failure causes = %v, %v", failed[0][FailureCauseKey], failed[1][FailureCauseKey])
+	}
+}
+
+func TestTask_DoesNotRetryCancellation(t *testing.T) {
+	var calls int32
+	agent := &extendedMockAgent{name: "cancelled", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, context.Canceled
+	}}
+
+	task := NewTask(agent, &TaskConfig{Retries: 3})
+	_, err := task.Execute(context.Background(), agenkit.NewMessage("user", "hi"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancellation error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("agent called %d times, want 1", calls)
+	}
+}
+
+func TestTask_TimeoutIsDeadlineExceeded(t *testing.T) {
+	agent := &extendedMockAgent{name: "slow", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+
+	task := NewTask(agent, &TaskConfig{Timeout: 10 * time.Millisecond})
+	_, err := task.Execute(context.Background(), agenkit.NewMessage("user", "hi"))
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) || !errors.Is(err, ErrDeadlineExceeded) {
+		t.Fatalf("expected a TimeoutError matching ErrDeadlineExceeded, got %v", err)
+	}
+	if err.Error() != "task timed out after 10ms" {
+		t.Errorf("error message changed: %q", err.Error())
+	}
+}
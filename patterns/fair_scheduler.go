@@ -0,0 +1,323 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Fair scheduler bounds the concurrent work of parallel patterns and
+// shares it fairly between tenants.
+package patterns
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// DefaultTenant is the tenant of work that carries no tenant ID.
+const DefaultTenant = "default"
+
+// tenantContextKey is the context key ContextWithTenant stores the tenant
+// ID under.
+type tenantContextKey struct{}
+
+// ContextWithTenant returns a context carrying tenant as the tenant ID a
+// FairScheduler schedules work under. It takes precedence over message
+// metadata.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant ID set by ContextWithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// TenantStats reports a tenant's work in a FairScheduler.
+type TenantStats struct {
+	// InFlight is the number of units running
+	InFlight int
+	// Queued is the number of units waiting for a slot
+	Queued int
+}
+
+// FairScheduler shares a worker pool fairly between tenants, so one
+// tenant's burst can't starve the others in a multi-tenant server.
+//
+// Each tenant may run at most perTenantLimit units at once; with
+// WithWorkers, the pool as a whole is also capped. Work beyond either
+// limit queues rather than runs. When a slot frees up, it goes to the
+// queued tenant that has received the least service relative to its
+// weight (start-time fair queuing), so a tenant with weight 2 gets twice
+// the slots of a tenant with weight 1 while both are busy.
+//
+// Share one scheduler across ParallelAgent, ParallelPattern and
+// ScatterGatherAgent instances with WithScheduler; each unit of work is
+// scheduled under the tenant of the message being processed (see
+// TenantOf). Work nested inside a slot, such as a ParallelAgent run by
+// another ParallelAgent sharing the scheduler, runs in its outer unit's
+// slot without queueing or counting against the limits, so nesting can't
+// deadlock.
+// Safe for concurrent use.
+//
+// Example:
+//
+//	scheduler := patterns.NewFairScheduler(4).WithWorkers(16)
+//	ensemble.WithScheduler(scheduler)
+//	ctx = patterns.ContextWithTenant(ctx, "acme")
+//	result, err := ensemble.Process(ctx, message)
+type FairScheduler struct {
+	perTenantLimit int
+	workers        int
+	tenantKey      string
+
+	mu      sync.Mutex
+	weights map[string]float64
+	tenants map[string]*tenantQueue
+	running int
+	virtual float64
+	seq     uint64
+}
+
+// tenantQueue is a tenant's scheduling state.
+type tenantQueue struct {
+	inFlight int
+	waiting  []*scheduledUnit
+	// finish is the virtual time the tenant's last dispatched unit
+	// finishes at; the next unit starts at max(finish, scheduler virtual)
+	finish float64
+}
+
+// slotContextKey marks a context as running in a slot of scheduler.
+type slotContextKey struct {
+	scheduler *FairScheduler
+}
+
+// scheduledUnit is a unit of work waiting for a slot.
+type scheduledUnit struct {
+	seq     uint64
+	ready   chan struct{}
+	granted bool
+}
+
+// NewFairScheduler creates a scheduler allowing each tenant perTenantLimit
+// concurrent units (values below 1 mean 1), with an unbounded pool.
+func NewFairScheduler(perTenantLimit int) *FairScheduler {
+	if perTenantLimit < 1 {
+		perTenantLimit = 1
+	}
+	return &FairScheduler{
+		perTenantLimit: perTenantLimit,
+		tenantKey:      "tenant_id",
+		weights:        make(map[string]float64),
+		tenants:        make(map[string]*tenantQueue),
+	}
+}
+
+// WithWorkers caps the units running across all tenants (0 means no cap)
+// and returns the scheduler for chaining.
+func (s *FairScheduler) WithWorkers(workers int) *FairScheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workers = workers
+	s.dispatchLocked()
+	return s
+}
+
+// WithTenantWeight sets tenant's share of the pool relative to other
+// tenants (default 1; values <= 0 reset it to 1) and returns the scheduler
+// for chaining.
+func (s *FairScheduler) WithTenantWeight(tenant string, weight float64) *FairScheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if weight <= 0 {
+		delete(s.weights, tenant)
+	} else {
+		s.weights[tenant] = weight
+	}
+	return s
+}
+
+// WithTenantKey sets the message metadata key TenantOf reads the tenant ID
+// from (default "tenant_id") and returns the scheduler for chaining.
+func (s *FairScheduler) WithTenantKey(key string) *FairScheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tenantKey = key
+	return s
+}
+
+// TenantOf returns the tenant work for message is scheduled under: the
+// tenant in ctx (see ContextWithTenant), else the message's tenant
+// metadata, else DefaultTenant.
+func (s *FairScheduler) TenantOf(ctx context.Context, message *agenkit.Message) string {
+	if tenant, ok := TenantFromContext(ctx); ok {
+		return tenant
+	}
+	if s != nil && message != nil {
+		s.mu.Lock()
+		key := s.tenantKey
+		s.mu.Unlock()
+		if tenant, ok := message.Metadata[key].(string); ok && tenant != "" {
+			return tenant
+		}
+	}
+	return DefaultTenant
+}
+
+// Acquire waits for a slot for tenant and returns the function that
+// releases it, which must be called exactly once when the work is done.
+// Acquire on a nil scheduler, or with a ctx already running in one of the
+// scheduler's slots (see WithSlot), returns immediately.
+//
+// Returns the context error, without a slot, if ctx is done first.
+func (s *FairScheduler) Acquire(ctx context.Context, tenant string) (release func(), _ error) {
+	if s == nil || s.holdsSlot(ctx) {
+		return func() {}, nil
+	}
+
+	s.mu.Lock()
+	queue := s.queueLocked(tenant)
+	s.seq++
+	unit := &scheduledUnit{seq: s.seq, ready: make(chan struct{})}
+	queue.waiting = append(queue.waiting, unit)
+	s.dispatchLocked()
+	s.mu.Unlock()
+
+	release = func() { s.release(tenant) }
+	select {
+	case <-unit.ready:
+		return release, nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if unit.granted {
+			// Granted as ctx was cancelled; hand the slot on
+			s.releaseLocked(tenant)
+			return nil, contextError(ctx)
+		}
+		for i, waiting := range queue.waiting {
+			if waiting == unit {
+				queue.waiting = append(queue.waiting[:i], queue.waiting[i+1:]...)
+				break
+			}
+		}
+		s.forgetIdleLocked(tenant)
+		return nil, contextError(ctx)
+	}
+}
+
+// WithSlot returns a copy of ctx marking it as running in one of the
+// scheduler's slots, so nested work that calls Acquire with it runs in
+// that slot instead of waiting for another. Patterns mark the context they
+// run a scheduled unit with.
+func (s *FairScheduler) WithSlot(ctx context.Context) context.Context {
+	if s == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, slotContextKey{scheduler: s}, true)
+}
+
+// holdsSlot reports whether ctx is running in one of the scheduler's slots.
+func (s *FairScheduler) holdsSlot(ctx context.Context) bool {
+	held, _ := ctx.Value(slotContextKey{scheduler: s}).(bool)
+	return held
+}
+
+// Stats returns the in-flight and queued units of every tenant with work.
+func (s *FairScheduler) Stats() map[string]TenantStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make(map[string]TenantStats, len(s.tenants))
+	for tenant, queue := range s.tenants {
+		stats[tenant] = TenantStats{InFlight: queue.inFlight, Queued: len(queue.waiting)}
+	}
+	return stats
+}
+
+// release frees one of tenant's slots.
+func (s *FairScheduler) release(tenant string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseLocked(tenant)
+}
+
+// releaseLocked frees one of tenant's slots and dispatches queued work.
+// Caller must hold mu.
+func (s *FairScheduler) releaseLocked(tenant string) {
+	if queue, ok := s.tenants[tenant]; ok && queue.inFlight > 0 {
+		queue.inFlight--
+		s.running--
+	}
+	s.dispatchLocked()
+	s.forgetIdleLocked(tenant)
+}
+
+// queueLocked returns tenant's queue, creating it. Caller must hold mu.
+func (s *FairScheduler) queueLocked(tenant string) *tenantQueue {
+	queue, ok := s.tenants[tenant]
+	if !ok {
+		queue = &tenantQueue{}
+		s.tenants[tenant] = queue
+	}
+	return queue
+}
+
+// forgetIdleLocked drops tenant's queue once it has no work, so Stats only
+// lists active tenants. Caller must hold mu.
+func (s *FairScheduler) forgetIdleLocked(tenant string) {
+	if queue, ok := s.tenants[tenant]; ok && queue.inFlight == 0 && len(queue.waiting) == 0 {
+		delete(s.tenants, tenant)
+	}
+}
+
+// dispatchLocked grants slots to queued units while the pool has room,
+// picking the eligible tenant with the earliest virtual start time and
+// breaking ties by arrival. Caller must hold mu.
+func (s *FairScheduler) dispatchLocked() {
+	for s.workers <= 0 || s.running < s.workers {
+		eligible := make([]string, 0, len(s.tenants))
+		for tenant, queue := range s.tenants {
+			if len(queue.waiting) > 0 && queue.inFlight < s.perTenantLimit {
+				eligible = append(eligible, tenant)
+			}
+		}
+		if len(eligible) == 0 {
+			return
+		}
+
+		sort.Slice(eligible, func(i, j int) bool {
+			a, b := s.tenants[eligible[i]], s.tenants[eligible[j]]
+			startA, startB := s.startTime(a), s.startTime(b)
+			if startA != startB {
+				return startA < startB
+			}
+			return a.waiting[0].seq < b.waiting[0].seq
+		})
+
+		tenant := eligible[0]
+		queue := s.tenants[tenant]
+		unit := queue.waiting[0]
+		queue.waiting = queue.waiting[1:]
+		queue.inFlight++
+		s.running++
+
+		weight := s.weights[tenant]
+		if weight <= 0 {
+			weight = 1
+		}
+		start := s.startTime(queue)
+		queue.finish = start + 1/weight
+		s.virtual = start
+
+		unit.granted = true
+		close(unit.ready)
+	}
+}
+
+// startTime is the virtual time queue's next unit would start at.
+func (s *FairScheduler) startTime(queue *tenantQueue) float64 {
+	if queue.finish > s.virtual {
+		return queue.finish
+	}
+	return s.virtual
+}
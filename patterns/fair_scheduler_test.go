@@ -0,0 +1,229 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// waitForQueued polls until tenant has queued units queued.
+func waitForQueued(t *testing.T, s *FairScheduler, tenant string, queued int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for s.Stats()[tenant].Queued != queued {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d queued for %s, stats %v", queued, tenant, s.Stats())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFairScheduler_PerTenantLimit(t *testing.T) {
+	s := NewFairScheduler(1)
+	ctx := context.Background()
+
+	release, err := s.Acquire(ctx, "acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		next, _ := s.Acquire(ctx, "acme")
+		acquired <- next
+	}()
+	waitForQueued(t, s, "acme", 1)
+	if stats := s.Stats()["acme"]; stats.InFlight != 1 {
+		t.Errorf("expected 1 in flight, got %+v", stats)
+	}
+
+	// Another tenant isn't held back by acme's quota
+	other, err := s.Acquire(ctx, "globex")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other()
+
+	release()
+	(<-acquired)()
+	if stats := s.Stats(); len(stats) != 0 {
+		t.Errorf("expected no active tenants, got %v", stats)
+	}
+}
+
+func TestFairScheduler_WeightedFairQueuing(t *testing.T) {
+	s := NewFairScheduler(10).WithWorkers(1).WithTenantWeight("acme", 2)
+	ctx := context.Background()
+
+	blocker, _ := s.Acquire(ctx, "blocker")
+
+	type grant struct {
+		tenant  string
+		release func()
+	}
+	grants := make(chan grant, 12)
+	queue := func(tenant string, n int) {
+		for i := 0; i < n; i++ {
+			go func() {
+				release, err := s.Acquire(ctx, tenant)
+				if err == nil {
+					grants <- grant{tenant, release}
+				}
+			}()
+			waitForQueued(t, s, tenant, i+1)
+		}
+	}
+	queue("acme", 6)
+	queue("globex", 6)
+
+	blocker()
+	counts := map[string]int{}
+	for i := 0; i < 6; i++ {
+		g := <-grants
+		counts[g.tenant]++
+		if stats := s.Stats(); stats["acme"].InFlight+stats["globex"].InFlight != 1 {
+			t.Errorf("expected one unit in flight, got %v", stats)
+		}
+		g.release()
+	}
+	if counts["acme"] != 4 || counts["globex"] != 2 {
+		t.Errorf("expected a 2:1 split, got %v", counts)
+	}
+
+	for i := 0; i < 6; i++ {
+		(<-grants).release()
+	}
+}
+
+func TestFairScheduler_CancelWhileQueued(t *testing.T) {
+	s := NewFairScheduler(1)
+	release, _ := s.Acquire(context.Background(), "acme")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		_, err := s.Acquire(ctx, "acme")
+		errCh <- err
+	}()
+	waitForQueued(t, s, "acme", 1)
+	cancel()
+
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if stats := s.Stats()["acme"]; stats.Queued != 0 || stats.InFlight != 1 {
+		t.Errorf("expected the cancelled unit dequeued, got %+v", stats)
+	}
+	release()
+}
+
+func TestFairScheduler_TenantOf(t *testing.T) {
+	s := NewFairScheduler(1)
+	msg := agenkit.NewMessage("user", "hi").WithMetadata("tenant_id", "acme")
+
+	if got := s.TenantOf(context.Background(), msg); got != "acme" {
+		t.Errorf("expected tenant from metadata, got %s", got)
+	}
+	if got := s.TenantOf(ContextWithTenant(context.Background(), "globex"), msg); got != "globex" {
+		t.Errorf("expected context to take precedence, got %s", got)
+	}
+	if got := s.TenantOf(context.Background(), agenkit.NewMessage("user", "hi")); got != DefaultTenant {
+		t.Errorf("expected default tenant, got %s", got)
+	}
+
+	s.WithTenantKey("org")
+	if got := s.TenantOf(context.Background(), agenkit.NewMessage("user", "hi").WithMetadata("org", "initech")); got != "initech" {
+		t.Errorf("expected tenant from custom key, got %s", got)
+	}
+}
+
+func TestFairScheduler_NilIsUnlimited(t *testing.T) {
+	var s *FairScheduler
+	release, err := s.Acquire(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release()
+}
+
+// concurrencyProbe returns agents that record the peak number running at
+// once.
+func concurrencyProbe(n int) ([]agenkit.Agent, *int32) {
+	var running, peak int32
+	agents := make([]agenkit.Agent, n)
+	for i := range agents {
+		agents[i] = &extendedMockAgent{
+			name: "probe",
+			processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+				now := atomic.AddInt32(&running, 1)
+				for {
+					old := atomic.LoadInt32(&peak)
+					if now <= old || atomic.CompareAndSwapInt32(&peak, old, now) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return agenkit.NewMessage("assistant", "ok"), nil
+			},
+		}
+	}
+	return agents, &peak
+}
+
+func TestParallelAgent_WithScheduler(t *testing.T) {
+	scheduler := NewFairScheduler(2)
+	agents, peak := concurrencyProbe(5)
+	parallel, _ := NewParallelAgent(agents, DefaultAggregators.Concatenate)
+	parallel.WithScheduler(scheduler)
+
+	ctx := ContextWithTenant(context.Background(), "acme")
+	if _, err := parallel.Process(ctx, agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(peak); got != 2 {
+		t.Errorf("expected at most 2 agents at once, got %d", got)
+	}
+}
+
+func TestParallelPattern_SchedulerSharedAcrossRequests(t *testing.T) {
+	scheduler := NewFairScheduler(1)
+	agents, peak := concurrencyProbe(3)
+	parallel, _ := NewParallelPattern(agents, DefaultAggregators.Concatenate, &ParallelPatternConfig{Scheduler: scheduler})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msg := agenkit.NewMessage("user", "hi").WithMetadata("tenant_id", "acme")
+			if _, err := parallel.Process(context.Background(), msg); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := atomic.LoadInt32(peak); got != 1 {
+		t.Errorf("expected the tenant's quota shared across requests, got peak %d", got)
+	}
+}
+
+func TestParallelAgent_NestedSchedulerDoesNotDeadlock(t *testing.T) {
+	scheduler := NewFairScheduler(1)
+	agents, _ := concurrencyProbe(2)
+	inner, _ := NewParallelAgent(agents, DefaultAggregators.Concatenate)
+	inner.WithScheduler(scheduler)
+	outer, _ := NewParallelAgent([]agenkit.Agent{inner, &extendedMockAgent{name: "sibling", response: "ok"}}, DefaultAggregators.Concatenate)
+	outer.WithScheduler(scheduler)
+
+	ctx, cancel := context.WithTimeout(ContextWithTenant(context.Background(), "acme"), 2*time.Second)
+	defer cancel()
+	if _, err := outer.Process(ctx, agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("expected nested parallel agents to share the outer slot, got %v", err)
+	}
+}
@@ -19,6 +19,7 @@ package patterns
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
@@ -40,9 +41,15 @@ import (
 //
 // The fallback pattern is ideal when you need resilience and have
 // multiple ways to accomplish the same task.
+//
+// WithDeadLetterSink captures messages every agent failed on, so they can
+// be triaged and reprocessed instead of only surfacing as errors.
 type FallbackAgent struct {
-	name   string
-	agents []agenkit.Agent
+	name       string
+	agents     []agenkit.Agent
+	deadLetter agenkit.DeadLetterSink
+
+	lazy lazyInit
 }
 
 // NewFallbackAgent creates a new fallback agent.
@@ -63,6 +70,13 @@ func NewFallbackAgent(agents []agenkit.Agent) (*FallbackAgent, error) {
 	}, nil
 }
 
+// WithDeadLetterSink sets the sink that receives messages every agent
+// failed on and returns the agent for chaining.
+func (f *FallbackAgent) WithDeadLetterSink(sink agenkit.DeadLetterSink) *FallbackAgent {
+	f.deadLetter = sink
+	return f
+}
+
 // Name returns the agent's identifier.
 func (f *FallbackAgent) Name() string {
 	return f.name
@@ -103,15 +117,21 @@ type attemptResult struct {
 // fails, the next agent is tried.
 //
 // If all agents fail, an error is returned that includes information
-// about all failed attempts.
+// about all failed attempts, and the message is written to the dead
+// letter sink, if set.
 //
 // The successful message includes metadata about:
 //   - Which agent succeeded
 //   - How many attempts were made
 //   - Which agents were tried
-func (f *FallbackAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	if message == nil {
-		return nil, fmt.Errorf("message cannot be nil")
+func (f *FallbackAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := f.Init(ctx); err != nil {
+		return nil, err
 	}
 
 	attempts := make([]attemptResult, 0, len(f.agents))
@@ -120,12 +140,12 @@ func (f *FallbackAgent) Process(ctx context.Context, message *agenkit.Message) (
 		// Check for context cancellation
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("fallback cancelled after %d attempts: %w", i, ctx.Err())
+			return nil, fmt.Errorf("fallback cancelled after %d attempts: %w", i, contextError(ctx))
 		default:
 		}
 
 		// Try agent
-		result, err := agent.Process(ctx, message)
+		result, err := ProcessTraced(ctx, agent, message)
 
 		// Record attempt
 		attempt := attemptResult{
@@ -144,10 +164,19 @@ func (f *FallbackAgent) Process(ctx context.Context, message *agenkit.Message) (
 
 		// Agent failed, try next (if available)
 		// Error will be included in final error if all fail
+		logger := Logger().With(slog.String("pattern", f.name), slog.String("agent", agent.Name()), slog.Int("index", i))
+		if i < len(f.agents)-1 {
+			logger.WarnContext(ctx, LogEventFallback, slog.String("next_agent", f.agents[i+1].Name()), slog.Any("error", err))
+		} else {
+			logger.ErrorContext(ctx, LogEventAgentError, slog.Any("error", err))
+		}
 	}
 
 	// All agents failed
-	return nil, f.buildFailureError(attempts)
+	err := f.buildFailureError(attempts)
+	writeDeadLetter(ctx, f.deadLetter, f.name, agenkit.DeadLetterAllFailed, message, err,
+		map[string]interface{}{"attempts": len(attempts)})
+	return nil, err
 }
 
 // buildSuccessResult adds fallback metadata to successful response.
@@ -168,9 +197,10 @@ func (f *FallbackAgent) buildSuccessResult(message *agenkit.Message, attempts []
 		failedAttempts := make([]map[string]interface{}, 0, len(attempts)-1)
 		for i := 0; i < len(attempts)-1; i++ {
 			failedAttempts = append(failedAttempts, map[string]interface{}{
-				"index": attempts[i].agentIndex,
-				"agent": attempts[i].agentName,
-				"error": attempts[i].err.Error(),
+				"index":         attempts[i].agentIndex,
+				"agent":         attempts[i].agentName,
+				"error":         attempts[i].err.Error(),
+				FailureCauseKey: FailureCause(attempts[i].err),
 			})
 		}
 		message.Metadata["fallback_failed_attempts"] = failedAttempts
@@ -208,9 +238,12 @@ type RecoveryFunc func(ctx context.Context, message *agenkit.Message, originalEr
 
 // RecoveryAgent wraps an agent with a recovery function.
 type RecoveryAgent struct {
-	name         string
-	agent        agenkit.Agent
-	recoveryFunc RecoveryFunc
+	name          string
+	agent         agenkit.Agent
+	recoveryFunc  RecoveryFunc
+	responseStore ResponseStore
+
+	lazy lazyInit
 }
 
 // WithRecovery creates a fallback agent with custom recovery logic.
@@ -234,9 +267,21 @@ func (r *RecoveryAgent) Capabilities() []string {
 }
 
 // Process executes the agent with recovery on failure.
-func (r *RecoveryAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	result, err := r.agent.Process(ctx, message)
+func (r *RecoveryAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := r.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := ProcessTraced(ctx, r.agent, message)
 	if err == nil {
+		if r.responseStore != nil && result != nil {
+			r.responseStore.Put(responseKey(message), result)
+		}
 		return result, nil
 	}
 
@@ -245,6 +290,9 @@ func (r *RecoveryAgent) Process(ctx context.Context, message *agenkit.Message) (
 	if recoveryErr != nil {
 		return nil, fmt.Errorf("primary agent failed: %w; recovery failed: %v", err, recoveryErr)
 	}
+	if recovered == nil {
+		return nil, fmt.Errorf("primary agent failed: %w; recovery returned no response", err)
+	}
 
 	// Add recovery metadata
 	if recovered.Metadata == nil {
@@ -257,6 +305,8 @@ func (r *RecoveryAgent) Process(ctx context.Context, message *agenkit.Message) (
 }
 
 // DefaultRecovery provides common recovery strategies.
+//
+// Combine them with ChainRecovery, ErrorClassifyingRecovery and RecoverIf.
 var DefaultRecovery = struct {
 	// StaticMessage returns a fixed fallback message
 	StaticMessage func(message string) RecoveryFunc
@@ -266,10 +316,19 @@ var DefaultRecovery = struct {
 
 	// EmptyResponse returns an empty but valid response
 	EmptyResponse RecoveryFunc
+
+	// CachedResponse returns the last successful response for the same
+	// input, as recorded by RecoveryAgent.WithResponseStore. The copy is
+	// marked with metadata "cached_response".
+	CachedResponse func(store ResponseStore) RecoveryFunc
+
+	// FromMetadata returns the input message's metadata value for key as
+	// the response, letting callers supply their own fallback per request
+	FromMetadata func(key string) RecoveryFunc
 }{
 	StaticMessage: func(message string) RecoveryFunc {
 		return func(ctx context.Context, msg *agenkit.Message, originalError error) (*agenkit.Message, error) {
-			return agenkit.NewMessage("assistant", message), nil
+			return agenkit.NewMessage(agenkit.RoleAssistant, message), nil
 		}
 	},
 
@@ -279,6 +338,18 @@ var DefaultRecovery = struct {
 	},
 
 	EmptyResponse: func(ctx context.Context, msg *agenkit.Message, originalError error) (*agenkit.Message, error) {
-		return agenkit.NewMessage("assistant", ""), nil
+		return agenkit.NewMessage(agenkit.RoleAssistant, ""), nil
+	},
+
+	CachedResponse: func(store ResponseStore) RecoveryFunc {
+		return func(ctx context.Context, msg *agenkit.Message, originalError error) (*agenkit.Message, error) {
+			return cachedResponse(store, msg, originalError)
+		}
+	},
+
+	FromMetadata: func(key string) RecoveryFunc {
+		return func(ctx context.Context, msg *agenkit.Message, originalError error) (*agenkit.Message, error) {
+			return metadataResponse(key, msg, originalError)
+		}
 	},
 }
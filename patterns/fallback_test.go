@@ -180,6 +180,28 @@ func TestFallbackAgent_AllAgentsFail(t *testing.T) {
 	}
 }
 
+// TestFallbackAgent_DeadLetters tests capturing messages every agent failed on
+func TestFallbackAgent_DeadLetters(t *testing.T) {
+	sink := agenkit.NewMemoryDeadLetterSink(0)
+	fallback, _ := NewFallbackAgent([]agenkit.Agent{
+		&extendedMockAgent{name: "primary", err: errors.New("down")},
+		&extendedMockAgent{name: "backup", err: errors.New("down too")},
+	})
+	fallback.WithDeadLetterSink(sink)
+
+	msg := agenkit.NewMessage("user", "test")
+	_, err := fallback.Process(context.Background(), msg)
+	if err == nil {
+		t.Fatal("expected error when all agents fail")
+	}
+
+	letters := sink.Drain()
+	if len(letters) != 1 || letters[0].Message != msg || letters[0].Reason != agenkit.DeadLetterAllFailed ||
+		letters[0].Err != err || letters[0].Metadata["attempts"] != 2 {
+		t.Errorf("unexpected dead letters %+v", letters)
+	}
+}
+
 // TestFallbackAgent_ContextCancellation tests context cancellation
 func TestFallbackAgent_ContextCancellation(t *testing.T) {
 	agent1 := &extendedMockAgent{name: "agent1", err: errors.New("error1")}
@@ -0,0 +1,307 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Resource governors bound the invocations, depth and concurrency of one
+// top-level request across arbitrarily nested compositions.
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ErrResourceLimit is returned (wrapped) when a ResourceGovernor's limit
+// is exceeded.
+var ErrResourceLimit = errors.New("resource limit exceeded")
+
+// Limits checked by a ResourceGovernor, as reported by ResourceLimitError.
+const (
+	ResourceInvocations = "invocations"
+	ResourceDepth       = "depth"
+	ResourceConcurrency = "concurrency"
+)
+
+// ResourceLimits configures a ResourceGovernor. A zero limit is unlimited.
+type ResourceLimits struct {
+	// MaxInvocations caps the total sub-agent calls of the request
+	MaxInvocations int
+	// MaxDepth caps how deeply sub-agent calls nest; a call made by the
+	// top-level agent is at depth 1
+	MaxDepth int
+	// MaxConcurrency caps the goroutines patterns run at once for fan-out
+	MaxConcurrency int
+}
+
+// ResourceLimitError reports which limit a request exceeded, and where.
+// It unwraps to ErrResourceLimit.
+type ResourceLimitError struct {
+	// Resource is the exceeded limit, e.g. ResourceDepth
+	Resource string
+	// Limit is the configured maximum
+	Limit int
+	// Agent is the agent being called when the limit was exceeded
+	Agent string
+}
+
+func (e *ResourceLimitError) Error() string {
+	return fmt.Sprintf("%v: %s limit of %d reached calling agent '%s'", ErrResourceLimit, e.Resource, e.Limit, e.Agent)
+}
+
+// Unwrap returns ErrResourceLimit.
+func (e *ResourceLimitError) Unwrap() error {
+	return ErrResourceLimit
+}
+
+// ResourceUsage is a snapshot of a ResourceGovernor's counters.
+type ResourceUsage struct {
+	// Invocations is the number of sub-agent calls made
+	Invocations int
+	// MaxDepth is the deepest nesting reached
+	MaxDepth int
+	// Concurrency is the number of pattern goroutines running now
+	Concurrency int
+	// PeakConcurrency is the most pattern goroutines running at once
+	PeakConcurrency int
+}
+
+// ResourceGovernor tracks the resources used by one top-level request and
+// enforces ResourceLimits on them. It is safe for concurrent use.
+//
+// Once a limit is exceeded the governor trips: every later sub-agent call
+// and goroutine of the request fails with the same *ResourceLimitError, so
+// the whole composition unwinds instead of retrying or falling back into
+// more work.
+type ResourceGovernor struct {
+	mu     sync.Mutex
+	limits ResourceLimits
+	usage  ResourceUsage
+	err    *ResourceLimitError
+}
+
+// NewResourceGovernor creates a governor enforcing limits.
+func NewResourceGovernor(limits ResourceLimits) *ResourceGovernor {
+	return &ResourceGovernor{limits: limits}
+}
+
+// Usage returns a snapshot of the resources used so far.
+func (g *ResourceGovernor) Usage() ResourceUsage {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.usage
+}
+
+// Err returns the *ResourceLimitError that tripped the governor, or nil.
+func (g *ResourceGovernor) Err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err == nil {
+		return nil
+	}
+	return g.err
+}
+
+// enter counts a call of agent at depth. Caller must not hold the lock.
+func (g *ResourceGovernor) enter(agent string, depth int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.err != nil {
+		return g.err
+	}
+	if g.limits.MaxInvocations > 0 && g.usage.Invocations >= g.limits.MaxInvocations {
+		return g.tripLocked(ResourceInvocations, g.limits.MaxInvocations, agent)
+	}
+	if g.limits.MaxDepth > 0 && depth > g.limits.MaxDepth {
+		return g.tripLocked(ResourceDepth, g.limits.MaxDepth, agent)
+	}
+
+	g.usage.Invocations++
+	if depth > g.usage.MaxDepth {
+		g.usage.MaxDepth = depth
+	}
+	return nil
+}
+
+// acquire counts a goroutine started on behalf of agent, returning the
+// function that releases it.
+func (g *ResourceGovernor) acquire(agent string) (func(), error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.err != nil {
+		return nil, g.err
+	}
+	if g.limits.MaxConcurrency > 0 && g.usage.Concurrency >= g.limits.MaxConcurrency {
+		return nil, g.tripLocked(ResourceConcurrency, g.limits.MaxConcurrency, agent)
+	}
+
+	g.usage.Concurrency++
+	if g.usage.Concurrency > g.usage.PeakConcurrency {
+		g.usage.PeakConcurrency = g.usage.Concurrency
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			g.usage.Concurrency--
+		})
+	}, nil
+}
+
+// tripLocked records the first exceeded limit. Caller must hold the lock.
+func (g *ResourceGovernor) tripLocked(resource string, limit int, agent string) error {
+	g.err = &ResourceLimitError{Resource: resource, Limit: limit, Agent: agent}
+	return g.err
+}
+
+// governorKey is the private context key for governorScope values.
+type governorKey struct{}
+
+// governorScope is a governor and the depth of the agent holding ctx.
+type governorScope struct {
+	governor *ResourceGovernor
+	depth    int
+}
+
+// WithResourceGovernor returns a copy of ctx carrying governor, so that
+// every sub-agent call and pattern goroutine beneath it is counted against
+// its limits. Agents called with the returned ctx are at depth 0.
+func WithResourceGovernor(ctx context.Context, governor *ResourceGovernor) context.Context {
+	return context.WithValue(ctx, governorKey{}, governorScope{governor: governor})
+}
+
+// ResourceGovernorFromContext retrieves the ResourceGovernor stored in ctx.
+func ResourceGovernorFromContext(ctx context.Context) (*ResourceGovernor, bool) {
+	scope, ok := ctx.Value(governorKey{}).(governorScope)
+	return scope.governor, ok && scope.governor != nil
+}
+
+// enterGovernor counts a call of agent against ctx's governor, returning
+// the context to call it with, one level deeper. Without a governor in
+// ctx it returns ctx unchanged.
+func enterGovernor(ctx context.Context, agent agenkit.Agent) (context.Context, error) {
+	scope, ok := ctx.Value(governorKey{}).(governorScope)
+	if !ok || scope.governor == nil {
+		return ctx, nil
+	}
+	depth := scope.depth + 1
+	if err := scope.governor.enter(agent.Name(), depth); err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, governorKey{}, governorScope{governor: scope.governor, depth: depth}), nil
+}
+
+// acquireGoroutine counts a goroutine a pattern is about to start against
+// ctx's governor. The returned release function must be called when the
+// goroutine finishes; it is safe to call more than once.
+func acquireGoroutine(ctx context.Context, agent string) (func(), error) {
+	governor, ok := ResourceGovernorFromContext(ctx)
+	if !ok {
+		return func() {}, nil
+	}
+	return governor.acquire(agent)
+}
+
+// GovernedAgent runs each request under a fresh ResourceGovernor, the
+// safety net for deeply composed agents.
+//
+// If ctx already carries a governor (see WithResourceGovernor), it is
+// shared instead, so nested GovernedAgents don't reset the count. When a
+// limit trips, Process returns the *ResourceLimitError even if a pattern
+// beneath recovered from it, e.g. by falling back.
+//
+// Example:
+//
+//	governed, _ := patterns.NewGovernedAgent(supervisor, patterns.ResourceLimits{
+//	    MaxInvocations: 200,
+//	    MaxDepth:       8,
+//	    MaxConcurrency: 32,
+//	})
+//	result, err := governed.Process(ctx, message)
+//	if errors.Is(err, patterns.ErrResourceLimit) {
+//	    // The topology ran away
+//	}
+type GovernedAgent struct {
+	name   string
+	agent  agenkit.Agent
+	limits ResourceLimits
+
+	lazy lazyInit
+}
+
+// NewGovernedAgent creates an agent enforcing limits on each request to
+// agent.
+func NewGovernedAgent(agent agenkit.Agent, limits ResourceLimits) (*GovernedAgent, error) {
+	if agent == nil {
+		return nil, fmt.Errorf("agent is required")
+	}
+	if limits.MaxInvocations < 0 || limits.MaxDepth < 0 || limits.MaxConcurrency < 0 {
+		return nil, fmt.Errorf("resource limits cannot be negative")
+	}
+
+	return &GovernedAgent{
+		name:   "GovernedAgent",
+		agent:  agent,
+		limits: limits,
+	}, nil
+}
+
+// Name returns the agent's identifier.
+func (g *GovernedAgent) Name() string {
+	return g.name
+}
+
+// Capabilities returns the wrapped agent's capabilities.
+func (g *GovernedAgent) Capabilities() []string {
+	return g.agent.Capabilities()
+}
+
+// Introspect returns introspection information for the agent.
+func (g *GovernedAgent) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    g.Name(),
+		Capabilities: g.Capabilities(),
+		InternalState: map[string]interface{}{
+			"agent":           g.agent.Name(),
+			"max_invocations": g.limits.MaxInvocations,
+			"max_depth":       g.limits.MaxDepth,
+			"max_concurrency": g.limits.MaxConcurrency,
+		},
+	}
+}
+
+// Process runs the wrapped agent under the request's governor.
+func (g *GovernedAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := g.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	governor, shared := ResourceGovernorFromContext(ctx)
+	if !shared {
+		governor = NewResourceGovernor(g.limits)
+		ctx = WithResourceGovernor(ctx, governor)
+	}
+
+	result, err := ProcessTraced(ctx, g.agent, message)
+	if limitErr := governor.Err(); limitErr != nil {
+		usage := governor.Usage()
+		Logger().WarnContext(ctx, LogEventResourceLimit, slog.String("pattern", g.name), slog.String("agent", g.agent.Name()),
+			slog.Int("invocations", usage.Invocations), slog.Int("max_depth", usage.MaxDepth),
+			slog.Int("peak_concurrency", usage.PeakConcurrency), slog.Any("error", limitErr))
+		return nil, limitErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,137 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// recursiveAgent delegates to itself forever, like an agent tool that
+// calls its own caller.
+func recursiveAgent() *extendedMockAgent {
+	agent := &extendedMockAgent{name: "recursive"}
+	agent.processFunc = func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		return ProcessTraced(ctx, agent, msg)
+	}
+	return agent
+}
+
+func TestGovernedAgent_MaxDepth(t *testing.T) {
+	governed, err := NewGovernedAgent(recursiveAgent(), ResourceLimits{MaxDepth: 5})
+	if err != nil {
+		t.Fatalf("NewGovernedAgent failed: %v", err)
+	}
+
+	_, err = governed.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	var limitErr *ResourceLimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, ErrResourceLimit) {
+		t.Fatalf("expected a resource limit error, got %v", err)
+	}
+	if limitErr.Resource != ResourceDepth || limitErr.Limit != 5 || limitErr.Agent != "recursive" {
+		t.Errorf("unexpected error %+v", limitErr)
+	}
+}
+
+func TestGovernedAgent_MaxInvocations(t *testing.T) {
+	agents := make([]agenkit.Agent, 10)
+	for i := range agents {
+		agents[i] = &extendedMockAgent{name: fmt.Sprintf("stage%d", i), response: "ok"}
+	}
+	sequential, _ := NewSequentialAgent(agents)
+
+	governor := NewResourceGovernor(ResourceLimits{MaxInvocations: 4})
+	ctx := WithResourceGovernor(context.Background(), governor)
+	_, err := ProcessTraced(ctx, sequential, agenkit.NewMessage("user", "hi"))
+
+	var limitErr *ResourceLimitError
+	if !errors.As(err, &limitErr) || limitErr.Resource != ResourceInvocations || limitErr.Agent != "stage3" {
+		t.Fatalf("expected the invocation limit to trip at stage3, got %v", err)
+	}
+	if usage := governor.Usage(); usage.Invocations != 4 || usage.MaxDepth != 2 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+}
+
+func TestGovernedAgent_TripIsNotRecoverable(t *testing.T) {
+	backup := &extendedMockAgent{name: "backup", response: "ok"}
+	fallback, _ := NewBudgetedFallbackAgent(&BudgetedFallbackConfig{Agents: []agenkit.Agent{recursiveAgent(), backup}})
+	governed, _ := NewGovernedAgent(fallback, ResourceLimits{MaxDepth: 3})
+
+	// The fallback is refused too, and the trip surfaces even though
+	// the fallback pattern handles errors
+	_, err := governed.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	if !errors.Is(err, ErrResourceLimit) {
+		t.Fatalf("expected a resource limit error, got %v", err)
+	}
+}
+
+func TestGovernedAgent_MaxConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	agents := make([]agenkit.Agent, 4)
+	for i := range agents {
+		agents[i] = &extendedMockAgent{
+			name: fmt.Sprintf("worker%d", i),
+			processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				return agenkit.NewMessage("agent", "done"), nil
+			},
+		}
+	}
+	parallel, _ := NewParallelAgent(agents, DefaultAggregators.First)
+
+	governed, _ := NewGovernedAgent(parallel, ResourceLimits{MaxConcurrency: 2})
+	_, err := governed.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	var limitErr *ResourceLimitError
+	if !errors.As(err, &limitErr) || limitErr.Resource != ResourceConcurrency || limitErr.Agent != "worker2" {
+		t.Fatalf("expected the concurrency limit to trip at worker2, got %v", err)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent workers, got %d", peak.Load())
+	}
+}
+
+func TestGovernedAgent_WithinLimits(t *testing.T) {
+	agents := []agenkit.Agent{
+		&extendedMockAgent{name: "a", response: "A"},
+		&extendedMockAgent{name: "b", response: "B"},
+	}
+	parallel, _ := NewParallelAgent(agents, DefaultAggregators.First)
+	governed, _ := NewGovernedAgent(parallel, ResourceLimits{MaxInvocations: 10, MaxDepth: 3, MaxConcurrency: 2})
+
+	// A governor in ctx is shared rather than replaced
+	governor := NewResourceGovernor(ResourceLimits{})
+	ctx := WithResourceGovernor(context.Background(), governor)
+	result, err := governed.Process(ctx, agenkit.NewMessage("user", "hi"))
+	if err != nil || result == nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	usage := governor.Usage()
+	if usage.Invocations != 3 || usage.MaxDepth != 2 || usage.Concurrency != 0 || usage.PeakConcurrency < 1 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	if governor.Err() != nil {
+		t.Errorf("expected no trip, got %v", governor.Err())
+	}
+}
+
+func TestNewGovernedAgent_Validation(t *testing.T) {
+	if _, err := NewGovernedAgent(nil, ResourceLimits{}); err == nil {
+		t.Error("expected error for nil agent")
+	}
+	if _, err := NewGovernedAgent(&extendedMockAgent{name: "a"}, ResourceLimits{MaxDepth: -1}); err == nil {
+		t.Error("expected error for negative limit")
+	}
+}
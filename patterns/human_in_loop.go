@@ -44,6 +44,63 @@ type ApprovalResponse struct {
 	Feedback string
 	// ModifiedMessage is an optional modified version (if approved with changes)
 	ModifiedMessage *agenkit.Message
+	// Decision optionally records how an automated policy decided. If nil,
+	// the agent records a decision in ApprovalTierHuman.
+	Decision *ApprovalDecision
+}
+
+// Approval tiers recorded in ApprovalDecision.Tier.
+const (
+	// ApprovalTierBypass means confidence met the agent's threshold, so no
+	// approval was requested
+	ApprovalTierBypass = "bypass"
+	// ApprovalTierAutoReject means confidence fell below the policy's
+	// reject threshold
+	ApprovalTierAutoReject = "auto_reject"
+	// ApprovalTierManual means confidence fell between the policy's
+	// thresholds, so the case needs human review
+	ApprovalTierManual = "manual"
+	// ApprovalTierAutoApprove means confidence met the policy's
+	// auto-approve threshold
+	ApprovalTierAutoApprove = "auto_approve"
+	// ApprovalTierHuman means the approval function decided without
+	// reporting a tier, typically a human reviewer
+	ApprovalTierHuman = "human"
+)
+
+// Approval actions recorded in ApprovalDecision.Action.
+const (
+	// ApprovalActionApprove means the response was returned as is
+	ApprovalActionApprove = "approve"
+	// ApprovalActionModify means a modified response was returned
+	ApprovalActionModify = "modify"
+	// ApprovalActionReject means a rejection message was returned
+	ApprovalActionReject = "reject"
+)
+
+// ApprovalDecision describes how a HumanInLoopAgent response was decided,
+// so downstream systems can branch on the tier (for example, queue only
+// ApprovalTierManual cases for review) instead of parsing status strings.
+// It is stored in the response metadata under "approval_decision".
+type ApprovalDecision struct {
+	// Tier is the decision tier that fired (an ApprovalTier* constant)
+	Tier string `json:"tier"`
+	// Threshold is the boundary the tier was decided against
+	Threshold float64 `json:"threshold"`
+	// Confidence is the agent's confidence in its response
+	Confidence float64 `json:"confidence"`
+	// Action is what was done with the response (an ApprovalAction* constant)
+	Action string `json:"action"`
+}
+
+// GetApprovalDecision returns the ApprovalDecision recorded on a
+// HumanInLoopAgent response, if any.
+func GetApprovalDecision(message *agenkit.Message) (ApprovalDecision, bool) {
+	if message == nil || message.Metadata == nil {
+		return ApprovalDecision{}, false
+	}
+	decision, ok := message.Metadata["approval_decision"].(ApprovalDecision)
+	return decision, ok
 }
 
 // ApprovalFunc is called when human approval is needed.
@@ -53,6 +110,10 @@ type ApprovalResponse struct {
 // (using a queue/callback system).
 //
 // If the context is cancelled, the function should return immediately.
+//
+// An ApprovalFunc is also an ApprovalBackend; external interfaces such as
+// Slack or a web dashboard implement ApprovalBackend directly (see
+// WebhookApprovalBackend).
 type ApprovalFunc func(ctx context.Context, request *ApprovalRequest) (*ApprovalResponse, error)
 
 // HumanInLoopAgent wraps an agent with human approval gates.
@@ -75,8 +136,10 @@ type HumanInLoopAgent struct {
 	name              string
 	agent             agenkit.Agent
 	approvalThreshold float64
-	approvalFunc      ApprovalFunc
+	approvalBackend   ApprovalBackend
 	confidenceKey     string
+
+	lazy lazyInit
 }
 
 // HumanInLoopConfig configures a HumanInLoopAgent.
@@ -88,6 +151,9 @@ type HumanInLoopConfig struct {
 	ApprovalThreshold float64
 	// ApprovalFunc is called when approval is needed
 	ApprovalFunc ApprovalFunc
+	// ApprovalBackend is asked when approval is needed, instead of
+	// ApprovalFunc; set exactly one of the two
+	ApprovalBackend ApprovalBackend
 	// ConfidenceKey specifies metadata key for confidence (default: "confidence")
 	ConfidenceKey string
 }
@@ -107,9 +173,16 @@ func NewHumanInLoopAgent(config *HumanInLoopConfig) (*HumanInLoopAgent, error) {
 	if config.Agent == nil {
 		return nil, fmt.Errorf("agent is required")
 	}
-	if config.ApprovalFunc == nil {
+	if config.ApprovalFunc == nil && config.ApprovalBackend == nil {
 		return nil, fmt.Errorf("approval function is required")
 	}
+	if config.ApprovalFunc != nil && config.ApprovalBackend != nil {
+		return nil, fmt.Errorf("set either an approval function or an approval backend, not both")
+	}
+	var backend ApprovalBackend = config.ApprovalFunc
+	if config.ApprovalBackend != nil {
+		backend = config.ApprovalBackend
+	}
 
 	threshold := config.ApprovalThreshold
 	if threshold == 0 {
@@ -128,7 +201,7 @@ func NewHumanInLoopAgent(config *HumanInLoopConfig) (*HumanInLoopAgent, error) {
 		name:              "HumanInLoopAgent",
 		agent:             config.Agent,
 		approvalThreshold: threshold,
-		approvalFunc:      config.ApprovalFunc,
+		approvalBackend:   backend,
 		confidenceKey:     confidenceKey,
 	}, nil
 }
@@ -181,13 +254,18 @@ func (h *HumanInLoopAgent) Introspect() *agenkit.IntrospectionResult {
 // If approval includes modifications, the modified message is returned.
 //
 // The final message includes metadata about the approval process.
-func (h *HumanInLoopAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	if message == nil {
-		return nil, fmt.Errorf("message cannot be nil")
+func (h *HumanInLoopAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := h.Init(ctx); err != nil {
+		return nil, err
 	}
 
 	// Execute underlying agent
-	response, err := h.agent.Process(ctx, message)
+	response, err := ProcessTraced(ctx, h.agent, message)
 	if err != nil {
 		return nil, fmt.Errorf("agent execution failed: %w", err)
 	}
@@ -209,6 +287,12 @@ func (h *HumanInLoopAgent) Process(ctx context.Context, message *agenkit.Message
 	// If high confidence, return without approval
 	if !needsApproval {
 		response.Metadata["approval_status"] = "bypassed"
+		response.Metadata["approval_decision"] = ApprovalDecision{
+			Tier:       ApprovalTierBypass,
+			Threshold:  h.approvalThreshold,
+			Confidence: confidence,
+			Action:     ApprovalActionApprove,
+		}
 		return response, nil
 	}
 
@@ -225,15 +309,36 @@ func (h *HumanInLoopAgent) Process(ctx context.Context, message *agenkit.Message
 		Timestamp: time.Now().UTC(),
 	}
 
-	approval, err := h.approvalFunc(ctx, request)
+	approval, err := h.approvalBackend.RequestApproval(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf("approval request failed: %w", err)
 	}
+	if approval == nil {
+		return nil, fmt.Errorf("approval backend returned no decision")
+	}
+
+	// Record the decision, defaulting to a human decision
+	decision := ApprovalDecision{
+		Tier:       ApprovalTierHuman,
+		Threshold:  h.approvalThreshold,
+		Confidence: confidence,
+	}
+	if approval.Decision != nil {
+		decision = *approval.Decision
+	}
+	switch {
+	case !approval.Approved:
+		decision.Action = ApprovalActionReject
+	case approval.ModifiedMessage != nil:
+		decision.Action = ApprovalActionModify
+	default:
+		decision.Action = ApprovalActionApprove
+	}
 
 	// Handle approval decision
 	if !approval.Approved {
 		// Request denied
-		rejectionMsg := agenkit.NewMessage("agent",
+		rejectionMsg := agenkit.NewMessage(agenkit.RoleAssistant,
 			"Action rejected by human reviewer")
 
 		if approval.Feedback != "" {
@@ -243,6 +348,7 @@ func (h *HumanInLoopAgent) Process(ctx context.Context, message *agenkit.Message
 		rejectionMsg.Metadata["approval_status"] = "rejected"
 		rejectionMsg.Metadata["original_response"] = response.ContentString()
 		rejectionMsg.Metadata["confidence"] = confidence
+		rejectionMsg.Metadata["approval_decision"] = decision
 
 		return rejectionMsg, nil
 	}
@@ -252,6 +358,9 @@ func (h *HumanInLoopAgent) Process(ctx context.Context, message *agenkit.Message
 	if approval.ModifiedMessage != nil {
 		// Use modified version
 		finalResponse = approval.ModifiedMessage
+		if finalResponse.Metadata == nil {
+			finalResponse.Metadata = make(map[string]interface{})
+		}
 		finalResponse.Metadata["approval_status"] = "approved_with_modifications"
 		finalResponse.Metadata["original_response"] = response.ContentString()
 	} else {
@@ -261,6 +370,7 @@ func (h *HumanInLoopAgent) Process(ctx context.Context, message *agenkit.Message
 	if approval.Feedback != "" {
 		finalResponse.Metadata["approval_feedback"] = approval.Feedback
 	}
+	finalResponse.Metadata["approval_decision"] = decision
 
 	return finalResponse, nil
 }
@@ -337,6 +447,11 @@ func SimpleApprovalFunc(autoApprove bool) ApprovalFunc {
 //   - Low confidence (0.5-0.7): require approval
 //   - Medium confidence (0.7-0.8): require approval
 //   - High confidence (>= 0.8): auto-approve
+//
+// Each response carries an ApprovalDecision naming the tier that fired
+// (ApprovalTierAutoReject, ApprovalTierManual or ApprovalTierAutoApprove)
+// and the threshold it was decided against; manual cases are measured
+// against autoApproveAbove.
 func ConfidenceBasedApprovalFunc(rejectBelow float64, autoApproveAbove float64) ApprovalFunc {
 	return func(ctx context.Context, request *ApprovalRequest) (*ApprovalResponse, error) {
 		if request.Confidence < rejectBelow {
@@ -344,6 +459,11 @@ func ConfidenceBasedApprovalFunc(rejectBelow float64, autoApproveAbove float64)
 				Approved: false,
 				Feedback: fmt.Sprintf("Confidence too low (%.2f < %.2f)",
 					request.Confidence, rejectBelow),
+				Decision: &ApprovalDecision{
+					Tier:       ApprovalTierAutoReject,
+					Threshold:  rejectBelow,
+					Confidence: request.Confidence,
+				},
 			}, nil
 		}
 
@@ -352,6 +472,11 @@ func ConfidenceBasedApprovalFunc(rejectBelow float64, autoApproveAbove float64)
 				Approved: true,
 				Feedback: fmt.Sprintf("Auto-approved (%.2f >= %.2f)",
 					request.Confidence, autoApproveAbove),
+				Decision: &ApprovalDecision{
+					Tier:       ApprovalTierAutoApprove,
+					Threshold:  autoApproveAbove,
+					Confidence: request.Confidence,
+				},
 			}, nil
 		}
 
@@ -361,6 +486,11 @@ func ConfidenceBasedApprovalFunc(rejectBelow float64, autoApproveAbove float64)
 			Approved: false,
 			Feedback: fmt.Sprintf("Manual approval required (%.2f in threshold range)",
 				request.Confidence),
+			Decision: &ApprovalDecision{
+				Tier:       ApprovalTierManual,
+				Threshold:  autoApproveAbove,
+				Confidence: request.Confidence,
+			},
 		}, nil
 	}
 }
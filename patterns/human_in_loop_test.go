@@ -589,3 +589,74 @@ func TestHumanInLoopAgent_ApprovalRequestContext(t *testing.T) {
 		t.Errorf("expected original_message context")
 	}
 }
+
+// TestHumanInLoopAgent_TieredApprovalDecision tests the typed decision for each tier
+func TestHumanInLoopAgent_TieredApprovalDecision(t *testing.T) {
+	tests := []struct {
+		confidence float64
+		want       ApprovalDecision
+	}{
+		{0.3, ApprovalDecision{Tier: ApprovalTierAutoReject, Threshold: 0.5, Confidence: 0.3, Action: ApprovalActionReject}},
+		{0.7, ApprovalDecision{Tier: ApprovalTierManual, Threshold: 0.9, Confidence: 0.7, Action: ApprovalActionReject}},
+		{0.85, ApprovalDecision{Tier: ApprovalTierManual, Threshold: 0.9, Confidence: 0.85, Action: ApprovalActionReject}},
+		{0.95, ApprovalDecision{Tier: ApprovalTierBypass, Threshold: 0.9, Confidence: 0.95, Action: ApprovalActionApprove}},
+	}
+
+	for _, tt := range tests {
+		agent := &extendedMockAgent{
+			name: "moderator",
+			processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+				return agenkit.NewMessage("assistant", "verdict").WithMetadata("confidence", tt.confidence), nil
+			},
+		}
+		hil, err := NewHumanInLoopAgent(&HumanInLoopConfig{
+			Agent:             agent,
+			ApprovalThreshold: 0.9,
+			ApprovalFunc:      ConfidenceBasedApprovalFunc(0.5, 0.9),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result, err := hil.Process(context.Background(), agenkit.NewMessage("user", "content"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		decision, ok := GetApprovalDecision(result)
+		if !ok {
+			t.Fatalf("confidence %.2f: expected an approval decision in %v", tt.confidence, result.Metadata)
+		}
+		if decision != tt.want {
+			t.Errorf("confidence %.2f: expected %+v, got %+v", tt.confidence, tt.want, decision)
+		}
+	}
+}
+
+// TestHumanInLoopAgent_HumanApprovalDecision tests the default decision for custom approval functions
+func TestHumanInLoopAgent_HumanApprovalDecision(t *testing.T) {
+	agent := &extendedMockAgent{
+		name: "agent",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			return agenkit.NewMessage("assistant", "draft").WithMetadata("confidence", 0.6), nil
+		},
+	}
+	hil, err := NewHumanInLoopAgent(&HumanInLoopConfig{
+		Agent: agent,
+		ApprovalFunc: func(ctx context.Context, request *ApprovalRequest) (*ApprovalResponse, error) {
+			return &ApprovalResponse{Approved: true, ModifiedMessage: &agenkit.Message{Role: "assistant", Content: "edited"}}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := hil.Process(context.Background(), agenkit.NewMessage("user", "test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decision, _ := GetApprovalDecision(result)
+	want := ApprovalDecision{Tier: ApprovalTierHuman, Threshold: 0.8, Confidence: 0.6, Action: ApprovalActionModify}
+	if decision != want {
+		t.Errorf("expected %+v, got %+v", want, decision)
+	}
+}
@@ -0,0 +1,44 @@
+package patterns
+
+import (
+	"errors"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ErrNilMessage is returned by a pattern's Process when given a nil
+// message.
+//
+// Empty content is valid input: it flows through patterns like any other
+// content, so a pipeline stage that produces an empty response doesn't
+// fail the stages after it.
+var ErrNilMessage = errors.New("message cannot be nil")
+
+// validateInput checks a message passed to Process, returning ErrNilMessage
+// if it is nil.
+func validateInput(message *agenkit.Message) error {
+	if message == nil {
+		return ErrNilMessage
+	}
+	return nil
+}
+
+// compactMessages returns messages without nil entries, so aggregators and
+// merge functions can index and read the results they are given. It
+// returns messages itself if there are none.
+func compactMessages(messages []*agenkit.Message) []*agenkit.Message {
+	for i, msg := range messages {
+		if msg != nil {
+			continue
+		}
+		compacted := make([]*agenkit.Message, 0, len(messages)-1)
+		compacted = append(compacted, messages[:i]...)
+		for _, rest := range messages[i+1:] {
+			if rest != nil {
+				compacted = append(compacted, rest)
+			}
+		}
+		return compacted
+	}
+	return messages
+}
@@ -0,0 +1,99 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestProcess_NilMessageReturnsErrNilMessage(t *testing.T) {
+	worker := &extendedMockAgent{name: "worker", response: "ok"}
+	sequential, _ := NewSequentialAgent([]agenkit.Agent{worker})
+	parallel, _ := NewParallelAgent([]agenkit.Agent{worker}, DefaultAggregators.First)
+	fallback, _ := NewFallbackAgent([]agenkit.Agent{worker})
+	pipeline, _ := NewSequentialPattern([]agenkit.Agent{worker}, nil)
+	transform := NewUppercaseTransform("upper")
+	reflection, _ := NewReflectionAgent(ReflectionConfig{Generator: worker, Critic: worker})
+
+	agents := []interface {
+		Name() string
+		Process(context.Context, *agenkit.Message) (*agenkit.Message, error)
+	}{
+		sequential, parallel, fallback, pipeline, transform, reflection,
+		NewAutonomousAgent("objective", 1),
+		NewConsensusAgent(VotingMajority),
+	}
+	for _, agent := range agents {
+		t.Run(agent.Name(), func(t *testing.T) {
+			_, err := agent.Process(context.Background(), nil)
+			if !errors.Is(err, ErrNilMessage) {
+				t.Errorf("expected ErrNilMessage, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSequentialAgent_EmptyContentFlowsThrough(t *testing.T) {
+	empty := &extendedMockAgent{name: "empty", response: ""}
+	upper := NewUppercaseTransform("upper")
+	sequential, _ := NewSequentialAgent([]agenkit.Agent{empty, upper})
+
+	result, err := sequential.Process(context.Background(), agenkit.NewMessage("user", ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "" {
+		t.Errorf("expected empty content, got %q", result.ContentString())
+	}
+}
+
+func TestSequentialAgent_NilStageResult(t *testing.T) {
+	silent := &extendedMockAgent{
+		name: "silent",
+		processFunc: func(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+			return nil, nil
+		},
+	}
+	sequential, _ := NewSequentialAgent([]agenkit.Agent{silent, &extendedMockAgent{name: "next", response: "ok"}})
+
+	if _, err := sequential.Process(context.Background(), agenkit.NewMessage("user", "hi")); err == nil {
+		t.Fatal("expected an error for a stage returning no message")
+	}
+}
+
+func TestDefaultAggregators_SkipNilMessages(t *testing.T) {
+	messages := []*agenkit.Message{nil, agenkit.NewMessage("assistant", "a"), nil, agenkit.NewMessage("assistant", "a")}
+
+	if got := DefaultAggregators.First(messages).ContentString(); got != "a" {
+		t.Errorf("First: got %q", got)
+	}
+	if got := DefaultAggregators.Concatenate(messages).ContentString(); got != "a\n\n---\n\na" {
+		t.Errorf("Concatenate: got %q", got)
+	}
+	if got := DefaultAggregators.MajorityVote(messages).Metadata["votes"]; got != 2 {
+		t.Errorf("MajorityVote: got %v votes", got)
+	}
+	if got := DefaultAggregators.Dedupe(nil, 0.8)(messages).Metadata["total_responses"]; got != 2 {
+		t.Errorf("Dedupe: got %v responses", got)
+	}
+	if got := DefaultAggregators.First([]*agenkit.Message{nil}); got == nil {
+		t.Error("First: expected a placeholder for only nil messages")
+	}
+}
+
+func TestDefaultMergeFunc_SkipNilMessages(t *testing.T) {
+	noMetadata := &agenkit.Message{Role: "assistant", Content: "b"}
+	messages := []*agenkit.Message{nil, noMetadata, nil}
+
+	if got := DefaultMergeFunc.Vote(messages); got.ContentString() != "b" || got.Metadata["votes"] != 1 {
+		t.Errorf("Vote: got %q with %v", got.ContentString(), got.Metadata)
+	}
+	if got := DefaultMergeFunc.Last(messages).ContentString(); got != "b" {
+		t.Errorf("Last: got %q", got)
+	}
+	if !DefaultConsensusFunc.ExactMatch([]*agenkit.Message{nil, noMetadata}) {
+		t.Error("ExactMatch: expected consensus ignoring nil messages")
+	}
+}
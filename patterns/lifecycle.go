@@ -0,0 +1,617 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// Lifecycle forwarding for composite patterns.
+//
+// Every pattern that owns sub-agents implements agenkit.Initializable and
+// agenkit.Closer by forwarding to them, so initializing or closing the
+// outermost pattern reaches every heavyweight agent in the tree:
+//
+//	supervisor, _ := patterns.NewSupervisorAgent(planner, specialists)
+//	if err := agenkit.InitAll(ctx, supervisor); err != nil {
+//	    return err
+//	}
+//	defer agenkit.CloseAll(supervisor)
+//
+// Init is optional: a pattern initializes its sub-agents before its first
+// Process call if nobody did, and again after Close. Patterns also forward
+// to the tools, LLM clients and history compressors they own when those
+// implement the interfaces.
+//
+// Sub-agents are initialized in declaration order and closed in reverse.
+// Agents held in maps are ordered by key for determinism.
+//
+// Exceptions: ScatterGatherAgent picks its agents per request through its
+// ScatterRouter, so callers initialize and close those agents themselves,
+// and AutonomousAgent's GoalWorker is a function with no lifecycle.
+
+// lazyInit initializes a composite's sub-agents once, on its first Init or
+// Process call. A failed initialization is retried on the next call, and
+// reset lets Close make the next call initialize again.
+type lazyInit struct {
+	done atomic.Bool
+	mu   sync.Mutex
+}
+
+// initializingKey is the private context key for the chain of lazyInits
+// running on a context.
+type initializingKey struct{}
+
+// initializing links a running lazyInit to the one that started it.
+type initializing struct {
+	lazy   *lazyInit
+	parent *initializing
+}
+
+// do runs initAll unless it already succeeded. A composite reached again
+// while it is initializing, as in a topology where an agent tool calls its
+// own caller, returns nil instead of deadlocking.
+func (l *lazyInit) do(ctx context.Context, initAll func(context.Context) error) error {
+	if l.done.Load() {
+		return nil
+	}
+	chain, _ := ctx.Value(initializingKey{}).(*initializing)
+	for running := chain; running != nil; running = running.parent {
+		if running.lazy == l {
+			return nil
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.done.Load() {
+		return nil
+	}
+	ctx = context.WithValue(ctx, initializingKey{}, &initializing{lazy: l, parent: chain})
+	if err := initAll(ctx); err != nil {
+		return err
+	}
+	l.done.Store(true)
+	return nil
+}
+
+// initAgents initializes agents unless they already were.
+func (l *lazyInit) initAgents(ctx context.Context, agents ...agenkit.Agent) error {
+	return l.do(ctx, func(ctx context.Context) error {
+		return agenkit.InitAll(ctx, agents...)
+	})
+}
+
+// reset makes the next call initialize again.
+func (l *lazyInit) reset() {
+	l.done.Store(false)
+}
+
+// initComponents initializes every component that implements
+// agenkit.Initializable, in order, like agenkit.InitAll for values that
+// need not be agents: tools, LLM clients, history compressors.
+func initComponents(ctx context.Context, components ...interface{}) error {
+	for i, component := range components {
+		initializable, ok := component.(agenkit.Initializable)
+		if !ok {
+			continue
+		}
+		if err := initializable.Init(ctx); err != nil {
+			initErr := fmt.Errorf("init %s: %w", componentName(component), err)
+			if closeErr := closeComponents(components[:i]...); closeErr != nil {
+				return errors.Join(initErr, closeErr)
+			}
+			return initErr
+		}
+	}
+	return nil
+}
+
+// closeComponents closes every component that implements agenkit.Closer,
+// in reverse order, like agenkit.CloseAll.
+func closeComponents(components ...interface{}) error {
+	var errs []error
+	for i := len(components) - 1; i >= 0; i-- {
+		closer, ok := components[i].(agenkit.Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", componentName(components[i]), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// componentName names a component in lifecycle errors.
+func componentName(component interface{}) string {
+	if named, ok := component.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", component)
+}
+
+// sortedTools returns the tools in m ordered by key, as components.
+func sortedTools(m map[string]agenkit.Tool) []interface{} {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tools := make([]interface{}, len(keys))
+	for i, key := range keys {
+		tools[i] = m[key]
+	}
+	return tools
+}
+
+// sortedAgents returns the agents in m ordered by key.
+func sortedAgents(m map[string]agenkit.Agent) []agenkit.Agent {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	agents := make([]agenkit.Agent, len(keys))
+	for i, key := range keys {
+		agents[i] = m[key]
+	}
+	return agents
+}
+
+// Init initializes all pipeline agents.
+func (s *SequentialAgent) Init(ctx context.Context) error {
+	return s.lazy.initAgents(ctx, s.agents...)
+}
+
+// Close closes all pipeline agents.
+func (s *SequentialAgent) Close() error {
+	s.lazy.reset()
+	return agenkit.CloseAll(s.agents...)
+}
+
+// Init initializes all parallel agents.
+func (p *ParallelAgent) Init(ctx context.Context) error {
+	return p.lazy.initAgents(ctx, p.agents...)
+}
+
+// Close closes all parallel agents.
+func (p *ParallelAgent) Close() error {
+	p.lazy.reset()
+	return agenkit.CloseAll(p.agents...)
+}
+
+// Init initializes all fallback agents.
+func (f *FallbackAgent) Init(ctx context.Context) error {
+	return f.lazy.initAgents(ctx, f.agents...)
+}
+
+// Close closes all fallback agents.
+func (f *FallbackAgent) Close() error {
+	f.lazy.reset()
+	return agenkit.CloseAll(f.agents...)
+}
+
+// Init initializes all fallback agents.
+func (b *BudgetedFallbackAgent) Init(ctx context.Context) error {
+	return b.lazy.initAgents(ctx, b.agents...)
+}
+
+// Close closes all fallback agents.
+func (b *BudgetedFallbackAgent) Close() error {
+	b.lazy.reset()
+	return agenkit.CloseAll(b.agents...)
+}
+
+// Init initializes all collaborating agents.
+func (c *CollaborativeAgent) Init(ctx context.Context) error {
+	return c.lazy.initAgents(ctx, c.agents...)
+}
+
+// Close closes all collaborating agents.
+func (c *CollaborativeAgent) Close() error {
+	c.lazy.reset()
+	return agenkit.CloseAll(c.agents...)
+}
+
+// routerAgents returns the classifier followed by the routed agents.
+func (r *RouterAgent) routerAgents() []agenkit.Agent {
+	return append([]agenkit.Agent{r.classifier}, sortedAgents(r.agents)...)
+}
+
+// Init initializes the classifier and all routed agents.
+func (r *RouterAgent) Init(ctx context.Context) error {
+	return r.lazy.initAgents(ctx, r.routerAgents()...)
+}
+
+// Close closes the classifier and all routed agents.
+func (r *RouterAgent) Close() error {
+	r.lazy.reset()
+	return agenkit.CloseAll(r.routerAgents()...)
+}
+
+// Init initializes all candidate agents.
+func (r *CapabilityRouter) Init(ctx context.Context) error {
+	return r.lazy.initAgents(ctx, r.agents...)
+}
+
+// Close closes all candidate agents.
+func (r *CapabilityRouter) Close() error {
+	r.lazy.reset()
+	return agenkit.CloseAll(r.agents...)
+}
+
+// supervisorAgents returns the planner followed by the specialists.
+func (s *SupervisorAgent) supervisorAgents() []agenkit.Agent {
+	return append([]agenkit.Agent{s.planner}, sortedAgents(s.specialists)...)
+}
+
+// Init initializes the planner and all specialists.
+func (s *SupervisorAgent) Init(ctx context.Context) error {
+	return s.lazy.initAgents(ctx, s.supervisorAgents()...)
+}
+
+// Close closes the planner and all specialists.
+func (s *SupervisorAgent) Close() error {
+	s.lazy.reset()
+	return agenkit.CloseAll(s.supervisorAgents()...)
+}
+
+// Init initializes all pipeline agents.
+func (p *SequentialPattern) Init(ctx context.Context) error {
+	return p.lazy.initAgents(ctx, p.agents...)
+}
+
+// Close closes all pipeline agents.
+func (p *SequentialPattern) Close() error {
+	p.lazy.reset()
+	return agenkit.CloseAll(p.agents...)
+}
+
+// Init initializes all parallel agents.
+func (p *ParallelPattern) Init(ctx context.Context) error {
+	return p.lazy.initAgents(ctx, p.agents...)
+}
+
+// Close closes all parallel agents.
+func (p *ParallelPattern) Close() error {
+	p.lazy.reset()
+	return agenkit.CloseAll(p.agents...)
+}
+
+// routerPatternAgents returns the handlers followed by the default handler.
+func (p *RouterPattern) routerPatternAgents() []agenkit.Agent {
+	agents := sortedAgents(p.handlers)
+	if p.defaultHandler != nil {
+		agents = append(agents, p.defaultHandler)
+	}
+	return agents
+}
+
+// Init initializes all handlers.
+func (p *RouterPattern) Init(ctx context.Context) error {
+	return p.lazy.initAgents(ctx, p.routerPatternAgents()...)
+}
+
+// Close closes all handlers.
+func (p *RouterPattern) Close() error {
+	p.lazy.reset()
+	return agenkit.CloseAll(p.routerPatternAgents()...)
+}
+
+// Init initializes all registered agents and the synthesizer.
+func (m *MultiAgentOrchestrator) Init(ctx context.Context) error {
+	return m.lazy.initAgents(ctx, m.orchestratorAgents()...)
+}
+
+// Close closes all registered agents and the synthesizer.
+func (m *MultiAgentOrchestrator) Close() error {
+	m.lazy.reset()
+	return agenkit.CloseAll(m.orchestratorAgents()...)
+}
+
+// orchestratorAgents returns the registered agents followed by the
+// synthesizer.
+func (m *MultiAgentOrchestrator) orchestratorAgents() []agenkit.Agent {
+	agents := sortedAgents(m.agents)
+	if m.synthesizer != nil {
+		agents = append(agents, m.synthesizer)
+	}
+	return agents
+}
+
+// Init initializes all voting agents.
+func (c *ConsensusAgent) Init(ctx context.Context) error {
+	return c.lazy.initAgents(ctx, c.agents...)
+}
+
+// Close closes all voting agents.
+func (c *ConsensusAgent) Close() error {
+	c.lazy.reset()
+	return agenkit.CloseAll(c.agents...)
+}
+
+// qualityGateAgents returns the remediation agent, if any.
+func (g *QualityGate) qualityGateAgents() []agenkit.Agent {
+	if g.onFail == nil {
+		return nil
+	}
+	return []agenkit.Agent{g.onFail}
+}
+
+// Init initializes the remediation agent.
+func (g *QualityGate) Init(ctx context.Context) error {
+	return g.lazy.initAgents(ctx, g.qualityGateAgents()...)
+}
+
+// Close closes the remediation agent.
+func (g *QualityGate) Close() error {
+	g.lazy.reset()
+	return agenkit.CloseAll(g.qualityGateAgents()...)
+}
+
+// Init initializes the wrapped agent.
+func (s *SelfCorrectingAgent) Init(ctx context.Context) error {
+	return s.lazy.initAgents(ctx, s.agent)
+}
+
+// Close closes the wrapped agent.
+func (s *SelfCorrectingAgent) Close() error {
+	s.lazy.reset()
+	return agenkit.CloseAll(s.agent)
+}
+
+// Init initializes the governed agent.
+func (g *GovernedAgent) Init(ctx context.Context) error {
+	return g.lazy.initAgents(ctx, g.agent)
+}
+
+// Close closes the governed agent.
+func (g *GovernedAgent) Close() error {
+	g.lazy.reset()
+	return agenkit.CloseAll(g.agent)
+}
+
+// Init initializes the generator and the critic.
+func (r *ReflectionAgent) Init(ctx context.Context) error {
+	return r.lazy.initAgents(ctx, r.generator, r.critic)
+}
+
+// Close closes the generator and the critic.
+func (r *ReflectionAgent) Close() error {
+	r.lazy.reset()
+	return agenkit.CloseAll(r.generator, r.critic)
+}
+
+// reactComponents returns the reasoning agent followed by the tools.
+func (r *ReActAgent) reactComponents() []interface{} {
+	return append([]interface{}{r.agent}, sortedTools(r.tools)...)
+}
+
+// Init initializes the reasoning agent and the tools.
+func (r *ReActAgent) Init(ctx context.Context) error {
+	return r.lazy.do(ctx, func(ctx context.Context) error {
+		return initComponents(ctx, r.reactComponents()...)
+	})
+}
+
+// Close closes the reasoning agent and the tools.
+func (r *ReActAgent) Close() error {
+	r.lazy.reset()
+	return closeComponents(r.reactComponents()...)
+}
+
+// reasoningComponents returns the LLM followed by the tools.
+func (r *ReasoningWithToolsAgent) reasoningComponents() []interface{} {
+	return append([]interface{}{r.llm}, sortedTools(r.tools)...)
+}
+
+// Init initializes the LLM and the tools.
+func (r *ReasoningWithToolsAgent) Init(ctx context.Context) error {
+	return r.lazy.do(ctx, func(ctx context.Context) error {
+		return initComponents(ctx, r.reasoningComponents()...)
+	})
+}
+
+// Close closes the LLM and the tools.
+func (r *ReasoningWithToolsAgent) Close() error {
+	r.lazy.reset()
+	return closeComponents(r.reasoningComponents()...)
+}
+
+// Init initializes the gated agent.
+func (h *HumanInLoopAgent) Init(ctx context.Context) error {
+	return h.lazy.initAgents(ctx, h.agent)
+}
+
+// Close closes the gated agent.
+func (h *HumanInLoopAgent) Close() error {
+	h.lazy.reset()
+	return agenkit.CloseAll(h.agent)
+}
+
+// Init initializes the wrapped agent.
+func (t *AgentTool) Init(ctx context.Context) error {
+	return t.lazy.initAgents(ctx, t.agent)
+}
+
+// Close closes the wrapped agent.
+func (t *AgentTool) Close() error {
+	t.lazy.reset()
+	return agenkit.CloseAll(t.agent)
+}
+
+// Init initializes the task's agent.
+func (t *Task) Init(ctx context.Context) error {
+	return t.lazy.initAgents(ctx, t.agent)
+}
+
+// Close closes the task's agent.
+func (t *Task) Close() error {
+	t.lazy.reset()
+	return agenkit.CloseAll(t.agent)
+}
+
+// Init initializes the LLM client and the history compressor.
+func (c *ConversationalAgent) Init(ctx context.Context) error {
+	return c.lazy.do(ctx, func(ctx context.Context) error {
+		return initComponents(ctx, c.llmClient, c.compressor)
+	})
+}
+
+// Close closes the LLM client and the history compressor.
+func (c *ConversationalAgent) Close() error {
+	c.lazy.reset()
+	return closeComponents(c.llmClient, c.compressor)
+}
+
+// Init initializes the summarizer.
+func (s *SummarizingCompressor) Init(ctx context.Context) error {
+	return s.lazy.initAgents(ctx, s.summarizer)
+}
+
+// Close closes the summarizer.
+func (s *SummarizingCompressor) Close() error {
+	s.lazy.reset()
+	return agenkit.CloseAll(s.summarizer)
+}
+
+// Init initializes the cached agent.
+func (c *CachingAgent) Init(ctx context.Context) error {
+	return c.lazy.initAgents(ctx, c.agent)
+}
+
+// Close closes the cached agent.
+func (c *CachingAgent) Close() error {
+	c.lazy.reset()
+	return agenkit.CloseAll(c.agent)
+}
+
+// Init initializes the underlying router.
+func (a *AdaptiveRouter) Init(ctx context.Context) error {
+	return a.router.Init(ctx)
+}
+
+// Close closes the underlying router.
+func (a *AdaptiveRouter) Close() error {
+	return a.router.Close()
+}
+
+// Init initializes all balanced agents.
+func (lb *LoadBalancer) Init(ctx context.Context) error {
+	return lb.lazy.initAgents(ctx, lb.agents...)
+}
+
+// Close closes all balanced agents.
+func (lb *LoadBalancer) Close() error {
+	lb.lazy.reset()
+	return agenkit.CloseAll(lb.agents...)
+}
+
+// Init initializes the wrapped agent.
+func (r *ResilientAgent) Init(ctx context.Context) error {
+	return r.lazy.initAgents(ctx, r.agent)
+}
+
+// Close closes the wrapped agent.
+func (r *ResilientAgent) Close() error {
+	r.lazy.reset()
+	return agenkit.CloseAll(r.agent)
+}
+
+// Init initializes the guarded agent.
+func (a *costGuardedAgent) Init(ctx context.Context) error {
+	return a.lazy.initAgents(ctx, a.agent)
+}
+
+// Close closes the guarded agent.
+func (a *costGuardedAgent) Close() error {
+	a.lazy.reset()
+	return agenkit.CloseAll(a.agent)
+}
+
+// Init initializes the sampled agent.
+func (b *BestOfNAgent) Init(ctx context.Context) error {
+	return b.lazy.initAgents(ctx, b.agent)
+}
+
+// Close closes the sampled agent.
+func (b *BestOfNAgent) Close() error {
+	b.lazy.reset()
+	return agenkit.CloseAll(b.agent)
+}
+
+// Init initializes the wrapped agent.
+func (s *StructuredAgent) Init(ctx context.Context) error {
+	return s.lazy.initAgents(ctx, s.agent)
+}
+
+// Close closes the wrapped agent.
+func (s *StructuredAgent) Close() error {
+	s.lazy.reset()
+	return agenkit.CloseAll(s.agent)
+}
+
+// Init initializes the primary and fallback agents.
+func (t *TimeoutFallbackAgent) Init(ctx context.Context) error {
+	return t.lazy.initAgents(ctx, t.primary, t.fallback)
+}
+
+// Close closes the primary and fallback agents.
+func (t *TimeoutFallbackAgent) Close() error {
+	t.lazy.reset()
+	return agenkit.CloseAll(t.primary, t.fallback)
+}
+
+// Init initializes the explained agent.
+func (e *ExplainAgent) Init(ctx context.Context) error {
+	return e.lazy.initAgents(ctx, e.agent)
+}
+
+// Close closes the explained agent.
+func (e *ExplainAgent) Close() error {
+	e.lazy.reset()
+	return agenkit.CloseAll(e.agent)
+}
+
+// Init initializes the wrapped agent.
+func (r *RecoveryAgent) Init(ctx context.Context) error {
+	return r.lazy.initAgents(ctx, r.agent)
+}
+
+// Close closes the wrapped agent.
+func (r *RecoveryAgent) Close() error {
+	r.lazy.reset()
+	return agenkit.CloseAll(r.agent)
+}
+
+// Init initializes the classifying agent.
+func (c *LLMClassifier) Init(ctx context.Context) error {
+	return c.lazy.initAgents(ctx, c.agent)
+}
+
+// Close closes the classifying agent.
+func (c *LLMClassifier) Close() error {
+	c.lazy.reset()
+	return agenkit.CloseAll(c.agent)
+}
+
+// Init initializes the LLM client and the step executor.
+func (p *PlanningAgent) Init(ctx context.Context) error {
+	return p.lazy.do(ctx, func(ctx context.Context) error {
+		return initComponents(ctx, p.llm, p.executor)
+	})
+}
+
+// Close closes the LLM client and the step executor.
+func (p *PlanningAgent) Close() error {
+	p.lazy.reset()
+	return closeComponents(p.llm, p.executor)
+}
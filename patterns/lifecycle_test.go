@@ -0,0 +1,198 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// lifecycleAgent records Init and Close calls into a shared log.
+type lifecycleAgent struct {
+	extendedMockAgent
+	log *[]string
+}
+
+func newLifecycleAgent(name string, log *[]string) *lifecycleAgent {
+	return &lifecycleAgent{extendedMockAgent: extendedMockAgent{name: name}, log: log}
+}
+
+func (a *lifecycleAgent) Init(ctx context.Context) error {
+	*a.log = append(*a.log, "init "+a.name)
+	return nil
+}
+
+func (a *lifecycleAgent) Close() error {
+	*a.log = append(*a.log, "close "+a.name)
+	return nil
+}
+
+func TestLifecycle_NestedPatternsForward(t *testing.T) {
+	var log []string
+	inner, err := NewParallelPattern([]agenkit.Agent{
+		newLifecycleAgent("b", &log),
+		newLifecycleAgent("c", &log),
+	}, func(messages []*agenkit.Message) *agenkit.Message { return messages[0] }, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	outer, err := NewSequentialPattern([]agenkit.Agent{newLifecycleAgent("a", &log), inner}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := agenkit.InitAll(context.Background(), outer); err != nil {
+		t.Fatalf("InitAll failed: %v", err)
+	}
+	if err := agenkit.CloseAll(outer); err != nil {
+		t.Fatalf("CloseAll failed: %v", err)
+	}
+
+	want := []string{"init a", "init b", "init c", "close c", "close b", "close a"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("expected %v, got %v", want, log)
+	}
+}
+
+func TestLifecycle_SupervisorInitsPlannerAndSpecialists(t *testing.T) {
+	var log []string
+	planner := &lifecyclePlanner{SimplePlanner: NewSimplePlanner(&extendedMockAgent{name: "planner"}), log: &log}
+	supervisor, err := NewSupervisorAgent(planner, map[string]agenkit.Agent{
+		"writer": newLifecycleAgent("writer", &log),
+		"coder":  newLifecycleAgent("coder", &log),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := supervisor.Init(context.Background()); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	want := []string{"init planner", "init coder", "init writer"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("expected %v, got %v", want, log)
+	}
+}
+
+// lifecyclePlanner adds Init to a SimplePlanner.
+type lifecyclePlanner struct {
+	*SimplePlanner
+	log *[]string
+}
+
+func (p *lifecyclePlanner) Init(ctx context.Context) error {
+	*p.log = append(*p.log, "init planner")
+	return nil
+}
+
+func TestLifecycle_ProcessInitializesOnce(t *testing.T) {
+	var log []string
+	pipeline, err := NewSequentialPattern([]agenkit.Agent{newLifecycleAgent("a", &log)}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := pipeline.Process(context.Background(), agenkit.NewMessage("user", "hi")); err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+	}
+	if err := pipeline.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := pipeline.Process(context.Background(), agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	want := []string{"init a", "close a", "init a"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("expected %v, got %v", want, log)
+	}
+}
+
+// failingInitAgent fails its first Init call.
+type failingInitAgent struct {
+	extendedMockAgent
+	calls int
+}
+
+func (a *failingInitAgent) Init(ctx context.Context) error {
+	a.calls++
+	if a.calls == 1 {
+		return errors.New("model not loaded")
+	}
+	return nil
+}
+
+func TestLifecycle_FailedInitIsRetried(t *testing.T) {
+	agent := &failingInitAgent{extendedMockAgent: extendedMockAgent{name: "model", response: "ok"}}
+	wrapped := WithTimeoutFallback(agent, &extendedMockAgent{name: "backup", response: "backup"}, 0)
+
+	if _, err := wrapped.Process(context.Background(), agenkit.NewMessage("user", "hi")); err == nil {
+		t.Fatal("expected the failed Init to fail Process")
+	}
+	result, err := wrapped.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if result.ContentString() != "ok" || agent.calls != 2 {
+		t.Errorf("expected the primary to serve after a second Init, got %q after %d calls", result.ContentString(), agent.calls)
+	}
+}
+
+func TestLifecycle_ReActForwardsToAgentTools(t *testing.T) {
+	var log []string
+	tool, err := NewAgentTool(AgentToolConfig{Agent: newLifecycleAgent("researcher", &log), Name: "research", Description: "Researches"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	react, err := NewReActAgent(&ReActConfig{Agent: newLifecycleAgent("reasoner", &log), Tools: []agenkit.Tool{tool}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := react.Init(context.Background()); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if err := react.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	want := []string{"init reasoner", "init researcher", "close researcher", "close reasoner"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("expected %v, got %v", want, log)
+	}
+}
+
+func TestLifecycle_CyclicTopologyDoesNotDeadlock(t *testing.T) {
+	var log []string
+	inner, err := NewSequentialPattern([]agenkit.Agent{newLifecycleAgent("b", &log)}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	outer, err := NewSequentialPattern([]agenkit.Agent{newLifecycleAgent("a", &log), inner}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inner.agents = append(inner.agents, outer)
+
+	done := make(chan error, 1)
+	go func() { done <- outer.Init(context.Background()) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Init failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Init deadlocked on a cyclic topology")
+	}
+
+	want := []string{"init a", "init b"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("expected %v, got %v", want, log)
+	}
+}
@@ -0,0 +1,16 @@
+package patterns
+
+import "github.com/scttfrdmn/agenkit-go/agenkit"
+
+// linkToInput returns output linked to input, so callers can trace a
+// pattern's result back to the request that produced it: a copy of output
+// with ParentID set to input's ID. Output itself is not modified, since a
+// sub-agent may return a shared or cached message. An output that already
+// has a parent (for example a sub-agent response linked to the message it
+// was given) is returned as is, preserving the deeper causal chain.
+func linkToInput(input, output *agenkit.Message) *agenkit.Message {
+	if input == nil || output == nil || output == input || output.ParentID != "" {
+		return output
+	}
+	return copyMessage(output).WithParent(input)
+}
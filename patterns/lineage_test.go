@@ -0,0 +1,135 @@
+package patterns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestLinkToInput(t *testing.T) {
+	input := agenkit.NewMessage("user", "in")
+	output := agenkit.NewMessage("assistant", "out")
+
+	linked := linkToInput(input, output)
+	if linked.ParentID != input.ID || linked.ID != output.ID {
+		t.Errorf("expected a copy of the output with parent %q, got %+v", input.ID, linked)
+	}
+	if output.ParentID != "" {
+		t.Error("expected the sub-agent's message not to be modified")
+	}
+
+	// An existing parent is kept
+	other := agenkit.NewMessage("user", "other")
+	if relinked := linkToInput(other, linked); relinked != linked || relinked.ParentID != input.ID {
+		t.Error("expected existing parent to be kept")
+	}
+
+	// A passthrough output is not linked to itself
+	if self := linkToInput(input, input); self != input || input.ParentID != "" {
+		t.Error("expected no self-link")
+	}
+}
+
+func TestSequentialAgent_LinksStages(t *testing.T) {
+	first := &extendedMockAgent{name: "first", response: "a"}
+	second := &extendedMockAgent{name: "second", response: "b"}
+
+	var stageOne *agenkit.Message
+	second.processFunc = func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		stageOne = msg
+		return agenkit.NewMessage("assistant", "b"), nil
+	}
+
+	pipeline, err := NewSequentialAgent([]agenkit.Agent{first, second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	input := agenkit.NewMessage("user", "start")
+	result, err := pipeline.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stageOne.ParentID != input.ID {
+		t.Errorf("expected stage 1 output linked to input, got %q", stageOne.ParentID)
+	}
+	if result.ParentID != stageOne.ID {
+		t.Errorf("expected final output linked to stage 1 output, got %q", result.ParentID)
+	}
+}
+
+func TestSupervisorAgent_LinksSubtasks(t *testing.T) {
+	subtask := agenkit.NewMessage("user", "write code")
+	planner := &mockPlanner{
+		name:        "planner",
+		subtasks:    []Subtask{{Type: "coder", Message: subtask}},
+		synthesized: "done",
+	}
+
+	var received, specialistOutput *agenkit.Message
+	coder := &extendedMockAgent{name: "coder", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		received = msg
+		specialistOutput = agenkit.NewMessage("assistant", "code")
+		return specialistOutput, nil
+	}}
+
+	supervisor, _ := NewSupervisorAgent(planner, map[string]agenkit.Agent{"coder": coder})
+
+	input := agenkit.NewMessage("user", "build it")
+	result, err := supervisor.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.ParentID != input.ID {
+		t.Error("expected subtask linked to the request")
+	}
+	if subtask.ParentID != "" || specialistOutput.ParentID != "" {
+		t.Error("expected the planner's and specialist's messages not to be modified")
+	}
+	if synthesized := planner.results["coder_0"]; synthesized == nil || synthesized.ParentID != received.ID {
+		t.Error("expected specialist output linked to its subtask")
+	}
+	if result.ParentID != input.ID {
+		t.Error("expected synthesized result linked to the request")
+	}
+}
+
+func TestCollaborativeAgent_LinksRounds(t *testing.T) {
+	var contexts []*agenkit.Message
+	record := func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		contexts = append(contexts, msg)
+		return agenkit.NewMessage("assistant", "ok"), nil
+	}
+	agents := []agenkit.Agent{
+		&extendedMockAgent{name: "a", processFunc: record},
+		&extendedMockAgent{name: "b", processFunc: record},
+	}
+
+	collab, _ := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:    agents,
+		MaxRounds: 1,
+		MergeFunc: DefaultMergeFunc.Last,
+	})
+
+	// Struct-literal messages get an ID on demand
+	input := &agenkit.Message{Role: "user", Content: "review"}
+	result, err := collab.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if input.ID == "" {
+		t.Fatal("expected input to be assigned an ID")
+	}
+	for _, msg := range contexts {
+		if msg.ParentID != input.ID {
+			t.Errorf("expected round context linked to input, got %q", msg.ParentID)
+		}
+	}
+	if result.ParentID != contexts[len(contexts)-1].ID {
+		t.Error("expected merged response linked to the context it answered")
+	}
+}
@@ -0,0 +1,300 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Load balancer pattern spreads messages across interchangeable backend
+// agents, failing over to the others when one fails.
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// LBStrategy selects which backend a LoadBalancer sends a message to.
+type LBStrategy string
+
+const (
+	// LBRoundRobin cycles through the backends in order
+	LBRoundRobin LBStrategy = "round_robin"
+	// LBWeighted cycles through the backends in proportion to their
+	// weights (see WithWeights), interleaving them smoothly
+	LBWeighted LBStrategy = "weighted"
+	// LBLeastOutstanding picks the backend with the fewest in-flight
+	// requests, the first in order on ties
+	LBLeastOutstanding LBStrategy = "least_outstanding"
+	// LBSticky sends every message of a session to the same backend,
+	// hashing the session ID read from message metadata (see
+	// WithSessionKey). Messages without one are sent round-robin.
+	LBSticky LBStrategy = "sticky"
+)
+
+// LoadBalancer distributes messages across interchangeable backends, such
+// as several instances of the same agent behind different LLM connections,
+// to scale horizontally.
+//
+// If the chosen backend fails, the message fails over to the other
+// backends in order, as FallbackAgent does, until one succeeds; disable
+// this with WithFailover(false). If every backend fails, Process returns an
+// *AllAgentsFailedError.
+//
+// Example:
+//
+//	lb, _ := patterns.NewLoadBalancer([]agenkit.Agent{gpt1, gpt2, gpt3}, patterns.LBLeastOutstanding)
+//	result, err := lb.Process(ctx, message)
+//	fmt.Println(result.Metadata["lb_backend"])
+type LoadBalancer struct {
+	name       string
+	agents     []agenkit.Agent
+	strategy   LBStrategy
+	sessionKey string
+	failover   bool
+
+	mu       sync.Mutex
+	next     int
+	weights  []int
+	current  []int
+	inFlight []int
+
+	lazy lazyInit
+}
+
+// NewLoadBalancer creates a load balancer over agents.
+//
+// Parameters:
+//   - agents: The interchangeable backends (must have at least one)
+//   - strategy: How backends are selected (default: LBRoundRobin)
+func NewLoadBalancer(agents []agenkit.Agent, strategy LBStrategy) (*LoadBalancer, error) {
+	if len(agents) == 0 {
+		return nil, fmt.Errorf("at least one agent is required")
+	}
+	for i, agent := range agents {
+		if agent == nil {
+			return nil, fmt.Errorf("agent %d is nil", i)
+		}
+	}
+	switch strategy {
+	case "":
+		strategy = LBRoundRobin
+	case LBRoundRobin, LBWeighted, LBLeastOutstanding, LBSticky:
+	default:
+		return nil, fmt.Errorf("unknown load balancing strategy: %s", strategy)
+	}
+
+	weights := make([]int, len(agents))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return &LoadBalancer{
+		name:       "LoadBalancer",
+		agents:     agents,
+		strategy:   strategy,
+		sessionKey: "session_id",
+		failover:   true,
+		weights:    weights,
+		current:    make([]int, len(agents)),
+		inFlight:   make([]int, len(agents)),
+	}, nil
+}
+
+// WithWeights sets the LBWeighted weight of each backend, in agent order,
+// and returns the balancer for chaining. Missing weights and weights <= 0
+// count as 1; extra weights are ignored.
+func (lb *LoadBalancer) WithWeights(weights ...int) *LoadBalancer {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	for i := range lb.weights {
+		lb.weights[i] = 1
+		if i < len(weights) && weights[i] > 0 {
+			lb.weights[i] = weights[i]
+		}
+		lb.current[i] = 0
+	}
+	return lb
+}
+
+// WithSessionKey sets the metadata key LBSticky reads the session ID from
+// (default "session_id") and returns the balancer for chaining.
+func (lb *LoadBalancer) WithSessionKey(key string) *LoadBalancer {
+	lb.sessionKey = key
+	return lb
+}
+
+// WithFailover sets whether a failed message is retried on the other
+// backends (default true) and returns the balancer for chaining.
+func (lb *LoadBalancer) WithFailover(enabled bool) *LoadBalancer {
+	lb.failover = enabled
+	return lb
+}
+
+// Name returns the agent's identifier.
+func (lb *LoadBalancer) Name() string {
+	return lb.name
+}
+
+// Capabilities returns the combined capabilities of the backends.
+func (lb *LoadBalancer) Capabilities() []string {
+	capMap := make(map[string]bool)
+	for _, agent := range lb.agents {
+		for _, cap := range agent.Capabilities() {
+			capMap[cap] = true
+		}
+	}
+
+	capabilities := make([]string, 0, len(capMap)+1)
+	for cap := range capMap {
+		capabilities = append(capabilities, cap)
+	}
+	return append(capabilities, "load_balancing")
+}
+
+// Introspect returns introspection information for the agent.
+func (lb *LoadBalancer) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    lb.Name(),
+		Capabilities: lb.Capabilities(),
+	}
+}
+
+// InFlight returns the number of in-flight requests per backend, in agent
+// order.
+func (lb *LoadBalancer) InFlight() []int {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	counts := make([]int, len(lb.inFlight))
+	copy(counts, lb.inFlight)
+	return counts
+}
+
+// Process sends message to the backend the strategy selects, failing over
+// to the others in order if it fails.
+//
+// The result includes metadata "lb_backend" (the backend's name),
+// "lb_backend_index", "lb_strategy", "lb_attempts" and, after failover,
+// "lb_failed_backends".
+//
+// Returns an *AllAgentsFailedError if every attempted backend fails, or the
+// context error if ctx is done before a backend succeeds.
+func (lb *LoadBalancer) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := lb.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	primary := lb.choose(message)
+	attempts := 1
+	if lb.failover {
+		attempts = len(lb.agents)
+	}
+
+	logger := Logger().With(slog.String("pattern", lb.name), slog.String("strategy", string(lb.strategy)))
+	failed := &AllAgentsFailedError{}
+	for n := 0; n < attempts; n++ {
+		// The primary is already reserved in choose, so it always runs
+		if err := contextError(ctx); err != nil && n > 0 {
+			return nil, fmt.Errorf("load balancer cancelled after %d attempts: %w", n, err)
+		}
+
+		index := (primary + n) % len(lb.agents)
+		agent := lb.agents[index]
+		result, err := lb.run(ctx, index, message, n == 0)
+		if err == nil {
+			if result == nil {
+				return nil, fmt.Errorf("backend %s returned no message", agent.Name())
+			}
+			if result.Metadata == nil {
+				result.Metadata = make(map[string]interface{})
+			}
+			result.Metadata["lb_backend"] = agent.Name()
+			result.Metadata["lb_backend_index"] = index
+			result.Metadata["lb_strategy"] = string(lb.strategy)
+			result.Metadata["lb_attempts"] = n + 1
+			if len(failed.Agents) > 0 {
+				result.Metadata["lb_failed_backends"] = failed.Agents
+			}
+			return result, nil
+		}
+
+		failed.Agents = append(failed.Agents, agent.Name())
+		failed.Errors = append(failed.Errors, err)
+		if n+1 < attempts {
+			logger.WarnContext(ctx, LogEventFallback, slog.String("agent", agent.Name()),
+				slog.String("next_agent", lb.agents[(index+1)%len(lb.agents)].Name()), slog.Any("error", err))
+		} else {
+			logger.WarnContext(ctx, LogEventAgentError, slog.String("agent", agent.Name()), slog.Any("error", err))
+		}
+	}
+	return nil, failed
+}
+
+// run processes message on backend index, tracking it as in flight.
+// reserved means choose already counted the request.
+func (lb *LoadBalancer) run(ctx context.Context, index int, message *agenkit.Message, reserved bool) (*agenkit.Message, error) {
+	if !reserved {
+		lb.mu.Lock()
+		lb.inFlight[index]++
+		lb.mu.Unlock()
+	}
+	defer func() {
+		lb.mu.Lock()
+		lb.inFlight[index]--
+		lb.mu.Unlock()
+	}()
+	return ProcessTraced(ctx, lb.agents[index], message)
+}
+
+// choose returns the index of the backend the strategy selects, counting
+// the request as in flight on it so concurrent calls see it.
+func (lb *LoadBalancer) choose(message *agenkit.Message) int {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	index := lb.selectLocked(message)
+	lb.inFlight[index]++
+	return index
+}
+
+// selectLocked applies the strategy. Caller must hold mu.
+func (lb *LoadBalancer) selectLocked(message *agenkit.Message) int {
+	switch lb.strategy {
+	case LBWeighted:
+		// Smooth weighted round-robin: spreads heavy backends' turns out
+		// instead of sending them in bursts
+		best, total := 0, 0
+		for i, weight := range lb.weights {
+			lb.current[i] += weight
+			total += weight
+			if lb.current[i] > lb.current[best] {
+				best = i
+			}
+		}
+		lb.current[best] -= total
+		return best
+
+	case LBLeastOutstanding:
+		best := 0
+		for i, count := range lb.inFlight {
+			if count < lb.inFlight[best] {
+				best = i
+			}
+		}
+		return best
+
+	case LBSticky:
+		if session, ok := message.Metadata[lb.sessionKey]; ok && session != nil {
+			hash := fnv.New32a()
+			fmt.Fprint(hash, session)
+			return int(hash.Sum32() % uint32(len(lb.agents)))
+		}
+	}
+
+	index := lb.next
+	lb.next = (lb.next + 1) % len(lb.agents)
+	return index
+}
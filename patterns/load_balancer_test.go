@@ -0,0 +1,206 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// lbBackends creates n backends that respond with their names.
+func lbBackends(n int) []agenkit.Agent {
+	agents := make([]agenkit.Agent, n)
+	for i := range agents {
+		name := fmt.Sprintf("b%d", i)
+		agents[i] = &extendedMockAgent{name: name, response: name}
+	}
+	return agents
+}
+
+// lbSequence processes n messages and returns the backends that served them.
+func lbSequence(t *testing.T, lb *LoadBalancer, messages ...*agenkit.Message) []string {
+	t.Helper()
+	served := make([]string, len(messages))
+	for i, msg := range messages {
+		result, err := lb.Process(context.Background(), msg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		served[i] = result.Metadata["lb_backend"].(string)
+	}
+	return served
+}
+
+func lbMessages(n int) []*agenkit.Message {
+	messages := make([]*agenkit.Message, n)
+	for i := range messages {
+		messages[i] = agenkit.NewMessage("user", "hi")
+	}
+	return messages
+}
+
+func TestLoadBalancer_RoundRobin(t *testing.T) {
+	lb, err := NewLoadBalancer(lbBackends(3), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := fmt.Sprint(lbSequence(t, lb, lbMessages(4)...))
+	if got != "[b0 b1 b2 b0]" {
+		t.Errorf("unexpected order: %s", got)
+	}
+}
+
+func TestLoadBalancer_Weighted(t *testing.T) {
+	lb, _ := NewLoadBalancer(lbBackends(2), LBWeighted)
+	lb.WithWeights(3, 1)
+
+	counts := map[string]int{}
+	for _, name := range lbSequence(t, lb, lbMessages(8)...) {
+		counts[name]++
+	}
+	if counts["b0"] != 6 || counts["b1"] != 2 {
+		t.Errorf("expected 3:1 split, got %v", counts)
+	}
+}
+
+func TestLoadBalancer_Sticky(t *testing.T) {
+	lb, _ := NewLoadBalancer(lbBackends(4), LBSticky)
+
+	var messages []*agenkit.Message
+	for i := 0; i < 3; i++ {
+		messages = append(messages, agenkit.NewMessage("user", "hi").WithMetadata("session_id", "user-42"))
+	}
+	served := lbSequence(t, lb, messages...)
+	if served[0] != served[1] || served[1] != served[2] {
+		t.Errorf("expected one backend per session, got %v", served)
+	}
+
+	lb.WithSessionKey("conversation")
+	tagged := agenkit.NewMessage("user", "hi").WithMetadata("conversation", "user-42")
+	if got := lbSequence(t, lb, tagged)[0]; got != served[0] {
+		t.Errorf("expected the same session hash under a custom key, got %s want %s", got, served[0])
+	}
+}
+
+func TestLoadBalancer_LeastOutstanding(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	slow := &extendedMockAgent{
+		name: "slow",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			close(started)
+			<-release
+			return agenkit.NewMessage("assistant", "slow"), nil
+		},
+	}
+	fast := &extendedMockAgent{name: "fast", response: "fast"}
+	lb, _ := NewLoadBalancer([]agenkit.Agent{slow, fast}, LBLeastOutstanding)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = lb.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	}()
+	<-started
+
+	if inFlight := lb.InFlight(); inFlight[0] != 1 || inFlight[1] != 0 {
+		t.Errorf("expected slow backend in flight, got %v", inFlight)
+	}
+	if got := lbSequence(t, lb, lbMessages(2)...); got[0] != "fast" || got[1] != "fast" {
+		t.Errorf("expected requests to avoid the busy backend, got %v", got)
+	}
+
+	close(release)
+	wg.Wait()
+	if inFlight := lb.InFlight(); inFlight[0] != 0 || inFlight[1] != 0 {
+		t.Errorf("expected nothing in flight, got %v", inFlight)
+	}
+}
+
+func TestLoadBalancer_Failover(t *testing.T) {
+	broken := &extendedMockAgent{name: "broken", err: errors.New("connection refused")}
+	healthy := &extendedMockAgent{name: "healthy", response: "ok"}
+	lb, _ := NewLoadBalancer([]agenkit.Agent{broken, healthy}, LBRoundRobin)
+
+	result, err := lb.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["lb_backend"] != "healthy" || result.Metadata["lb_attempts"] != 2 {
+		t.Errorf("unexpected failover metadata: %v", result.Metadata)
+	}
+	if failed := result.Metadata["lb_failed_backends"].([]string); len(failed) != 1 || failed[0] != "broken" {
+		t.Errorf("expected broken backend recorded, got %v", failed)
+	}
+
+	lb.WithFailover(false)
+	lb.next = 0
+	_, err = lb.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	var allFailed *AllAgentsFailedError
+	if !errors.As(err, &allFailed) || len(allFailed.Agents) != 1 {
+		t.Errorf("expected a single failed attempt without failover, got %v", err)
+	}
+}
+
+func TestLoadBalancer_AllFail(t *testing.T) {
+	lb, _ := NewLoadBalancer([]agenkit.Agent{
+		&extendedMockAgent{name: "a", err: errors.New("down")},
+		&extendedMockAgent{name: "b", err: errors.New("down")},
+	}, LBRoundRobin)
+
+	_, err := lb.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	if !errors.Is(err, ErrAllAgentsFailed) {
+		t.Fatalf("expected ErrAllAgentsFailed, got %v", err)
+	}
+}
+
+func TestLoadBalancer_CancelledStopsFailover(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelling := &extendedMockAgent{
+		name: "cancelling",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			cancel()
+			return nil, errors.New("interrupted")
+		},
+	}
+	next := &extendedMockAgent{name: "next", response: "ok"}
+	lb, _ := NewLoadBalancer([]agenkit.Agent{cancelling, next}, LBRoundRobin)
+
+	if _, err := lb.Process(ctx, agenkit.NewMessage("user", "hi")); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if inFlight := lb.InFlight(); inFlight[0] != 0 || inFlight[1] != 0 {
+		t.Errorf("expected nothing in flight, got %v", inFlight)
+	}
+}
+
+func TestNewLoadBalancer_Validation(t *testing.T) {
+	if _, err := NewLoadBalancer(nil, LBRoundRobin); err == nil {
+		t.Error("expected error for no agents")
+	}
+	if _, err := NewLoadBalancer(lbBackends(1), "random"); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
+func TestLoadBalancer_ConcurrentUse(t *testing.T) {
+	lb, _ := NewLoadBalancer(lbBackends(3), LBLeastOutstanding)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = lb.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+		}()
+	}
+	wg.Wait()
+	for _, count := range lb.InFlight() {
+		if count != 0 {
+			t.Errorf("expected nothing in flight, got %v", lb.InFlight())
+		}
+	}
+}
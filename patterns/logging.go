@@ -0,0 +1,225 @@
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Structured log event messages emitted by patterns.
+//
+// Every event carries a "pattern" attribute naming the emitting pattern,
+// plus event-specific attributes such as "agent", "round", "tool",
+// "attempt", "duration" and "error". Handlers can filter on these
+// messages to select the events they care about.
+const (
+	// LogEventAgentStart is logged at Debug before a sub-agent runs
+	LogEventAgentStart = "agent start"
+	// LogEventAgentEnd is logged at Debug after a sub-agent succeeds
+	LogEventAgentEnd = "agent end"
+	// LogEventAgentError is logged at Warn when a sub-agent fails
+	LogEventAgentError = "agent error"
+	// LogEventToolCall is logged at Debug when a pattern executes a tool
+	LogEventToolCall = "tool call"
+	// LogEventRound is logged at Debug when an iterative pattern completes a round
+	LogEventRound = "round"
+	// LogEventRoute is logged at Debug when a router selects an agent
+	LogEventRoute = "route"
+	// LogEventRetry is logged at Warn before an agent or tool is retried
+	LogEventRetry = "retry"
+	// LogEventFallback is logged at Warn when falling back to the next agent
+	LogEventFallback = "fallback"
+	// LogEventObservationInjected is logged at Debug when an external
+	// observation is injected into a reasoning loop
+	LogEventObservationInjected = "observation injected"
+	// LogEventLoopDetected is logged at Warn when a reasoning loop stops
+	// because it keeps repeating the same action
+	LogEventLoopDetected = "loop detected"
+	// LogEventCostWarning is logged at Warn when spend crosses a CostGuard
+	// warning threshold
+	LogEventCostWarning = "cost warning"
+	// LogEventQualityGate is logged at Debug when a QualityGate scores a
+	// message, and at Warn when the score is below its threshold
+	LogEventQualityGate = "quality gate"
+	// LogEventDeadLetter is logged at Warn when a pattern writes a message
+	// to its DeadLetterSink, and at Error if the sink rejects it
+	LogEventDeadLetter = "dead letter"
+	// LogEventResourceLimit is logged at Warn when a GovernedAgent aborts a
+	// request that exceeded its resource limits
+	LogEventResourceLimit = "resource limit"
+)
+
+// discardLogger drops every record. It is the package default so the
+// library is silent unless a caller opts in.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// packageLogger holds the package-wide *slog.Logger.
+var packageLogger atomic.Pointer[slog.Logger]
+
+// SetLogger sets the package-wide logger used by every pattern that has no
+// logger of its own. Passing nil restores the default no-op logger.
+//
+// Example:
+//
+//	patterns.SetLogger(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+func SetLogger(logger *slog.Logger) {
+	packageLogger.Store(logger)
+}
+
+// Logger returns the package-wide logger.
+func Logger() *slog.Logger {
+	if logger := packageLogger.Load(); logger != nil {
+		return logger
+	}
+	return discardLogger
+}
+
+// resolveLogger returns logger if set, otherwise the package-wide logger.
+// It is resolved on each call so SetLogger takes effect for patterns
+// constructed earlier.
+func resolveLogger(logger *slog.Logger) *slog.Logger {
+	if logger != nil {
+		return logger
+	}
+	return Logger()
+}
+
+// ConsoleHandler is a human-friendly slog.Handler for examples and local
+// development. It writes one line per record with a level icon followed by
+// the message and key=value attributes:
+//
+//	🔍 agent start pattern=SequentialAgent agent=writer stage=0
+//	⚠️  fallback pattern=FallbackAgent agent=primary error="timeout"
+//
+// Production code should prefer slog.NewJSONHandler or another structured
+// handler.
+type ConsoleHandler struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	level  slog.Leveler
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewConsoleHandler creates a ConsoleHandler writing to w. If opts is nil
+// or opts.Level is unset, records at Info and above are written.
+//
+// Example:
+//
+//	patterns.SetLogger(slog.New(patterns.NewConsoleHandler(os.Stdout, nil)))
+func NewConsoleHandler(w io.Writer, opts *slog.HandlerOptions) *ConsoleHandler {
+	var level slog.Leveler = slog.LevelInfo
+	if opts != nil && opts.Level != nil {
+		level = opts.Level
+	}
+
+	return &ConsoleHandler{
+		mu:    &sync.Mutex{},
+		w:     w,
+		level: level,
+	}
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle writes the record as a single human-readable line.
+func (h *ConsoleHandler) Handle(_ context.Context, record slog.Record) error {
+	var line strings.Builder
+
+	line.WriteString(consoleLevelIcon(record.Level))
+	line.WriteString(record.Message)
+
+	prefix := ""
+	if len(h.groups) > 0 {
+		prefix = strings.Join(h.groups, ".") + "."
+	}
+	for _, attr := range h.attrs {
+		writeConsoleAttr(&line, "", attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		writeConsoleAttr(&line, prefix, attr)
+		return true
+	})
+	line.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, line.String())
+	return err
+}
+
+// WithAttrs returns a new handler with additional attributes.
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	prefix := ""
+	if len(h.groups) > 0 {
+		prefix = strings.Join(h.groups, ".") + "."
+	}
+
+	combined := make([]slog.Attr, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(combined, h.attrs)
+	for _, attr := range attrs {
+		combined = append(combined, slog.Attr{Key: prefix + attr.Key, Value: attr.Value})
+	}
+
+	clone := *h
+	clone.attrs = combined
+	return &clone
+}
+
+// WithGroup returns a new handler that qualifies subsequent attribute keys
+// with name.
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.groups = append(append([]string{}, h.groups...), name)
+	return &clone
+}
+
+// consoleLevelIcon returns the line prefix for a level.
+func consoleLevelIcon(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "❌ "
+	case level >= slog.LevelWarn:
+		return "⚠️  "
+	case level >= slog.LevelInfo:
+		return "ℹ️  "
+	default:
+		return "🔍 "
+	}
+}
+
+// writeConsoleAttr appends " key=value" to line, flattening groups.
+func writeConsoleAttr(line *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix = prefix + attr.Key + "."
+		}
+		for _, child := range attr.Value.Group() {
+			writeConsoleAttr(line, groupPrefix, child)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if attr.Value.Kind() == slog.KindString && strings.ContainsAny(value, " \t\n\"=") {
+		value = fmt.Sprintf("%q", value)
+	}
+	fmt.Fprintf(line, " %s%s=%s", prefix, attr.Key, value)
+}
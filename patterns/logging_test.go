@@ -0,0 +1,146 @@
+package patterns
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// recordingHandler captures records for assertions.
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, record.Clone())
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler      { return h }
+
+// messages returns the recorded messages at or above level.
+func (h *recordingHandler) messages(level slog.Level) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var messages []string
+	for _, record := range h.records {
+		if record.Level >= level {
+			messages = append(messages, record.Message)
+		}
+	}
+	return messages
+}
+
+func TestLogger_DefaultsToDiscard(t *testing.T) {
+	if Logger().Enabled(context.Background(), slog.LevelError) {
+		t.Error("expected default logger to discard all records")
+	}
+
+	handler := &recordingHandler{}
+	SetLogger(slog.New(handler))
+	defer SetLogger(nil)
+
+	if !Logger().Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("expected package logger to be replaced")
+	}
+}
+
+func TestLogger_PackageWideFallbackEvents(t *testing.T) {
+	handler := &recordingHandler{}
+	SetLogger(slog.New(handler))
+	defer SetLogger(nil)
+
+	agent, err := NewFallbackAgent([]agenkit.Agent{
+		&extendedMockAgent{name: "primary", err: errors.New("down")},
+		&extendedMockAgent{name: "backup", response: "ok"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	warnings := handler.messages(slog.LevelWarn)
+	if len(warnings) != 1 || warnings[0] != LogEventFallback {
+		t.Errorf("expected a single fallback warning, got %v", warnings)
+	}
+}
+
+func TestLogger_PerPatternOverridesPackage(t *testing.T) {
+	packageHandler := &recordingHandler{}
+	SetLogger(slog.New(packageHandler))
+	defer SetLogger(nil)
+
+	patternHandler := &recordingHandler{}
+	agent, err := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:    []agenkit.Agent{&extendedMockAgent{name: "a"}, &extendedMockAgent{name: "b"}},
+		MaxRounds: 2,
+		MergeFunc: DefaultMergeFunc.First,
+		Logger:    slog.New(patternHandler),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "hi")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rounds := 0
+	for _, msg := range patternHandler.messages(slog.LevelDebug) {
+		if msg == LogEventRound {
+			rounds++
+		}
+	}
+	if rounds != 2 {
+		t.Errorf("expected 2 round events on the pattern logger, got %d", rounds)
+	}
+	if got := packageHandler.messages(slog.LevelDebug); len(got) != 0 {
+		t.Errorf("expected no events on the package logger, got %v", got)
+	}
+}
+
+func TestConsoleHandler_Format(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewConsoleHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	logger.With(slog.String("pattern", "SequentialAgent")).
+		WithGroup("stage").
+		Debug(LogEventAgentStart, slog.String("agent", "writer"), slog.Int("index", 0))
+	logger.Warn(LogEventRetry, slog.String("error", "upstream timed out"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+	if lines[0] != "🔍 agent start pattern=SequentialAgent stage.agent=writer stage.index=0" {
+		t.Errorf("unexpected debug line: %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "⚠️") || !strings.Contains(lines[1], `error="upstream timed out"`) {
+		t.Errorf("unexpected warn line: %q", lines[1])
+	}
+}
+
+func TestConsoleHandler_DefaultLevelIsInfo(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewConsoleHandler(&buf, nil))
+
+	logger.Debug("hidden")
+	logger.Info("shown")
+
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Errorf("expected only info output, got %q", buf.String())
+	}
+}
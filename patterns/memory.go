@@ -11,7 +11,8 @@
 //   - Short-Term Memory: Recent sessions (medium, TTL-based, recency retrieval)
 //   - Long-Term Memory: Persistent facts (large, semantic retrieval, importance-based)
 //   - Automatic Promotion: Important memories move from short-term to long-term
-//   - Intelligent Retrieval: Search across tiers with relevance ranking
+//   - Intelligent Retrieval: Search across tiers, ranked by a configurable
+//     RankingFunc combining relevance, importance, recency and tier
 //
 // Use cases:
 //   - Long-running conversational agents
@@ -36,12 +37,16 @@ package patterns
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
 
 // MemoryEntry represents a single memory entry across all tiers.
@@ -62,6 +67,12 @@ type MemoryEntry struct {
 	Importance float64
 	// SessionID optional session identifier
 	SessionID string
+	// Tier is the highest-priority tier a MemoryHierarchy.Retrieve result
+	// was found in (MemoryTierWorking, MemoryTierShortTerm or
+	// MemoryTierLongTerm)
+	Tier string
+	// RankScore is the score MemoryHierarchy.Retrieve ranked the entry by
+	RankScore float64
 }
 
 // CreateMemoryEntry creates a new memory entry.
@@ -97,10 +108,13 @@ type MemoryStore interface {
 //   - Fast: O(1) append, O(n) retrieval
 //   - Small capacity: 10-20 messages typically
 //   - FIFO eviction: Oldest messages removed first
+//   - Optional byte budget: see WithMaxBytes
 //   - No persistence: Exists only in memory
 //   - Use for: Current conversation context
 type WorkingMemory struct {
 	maxMessages int
+	maxBytes    int
+	sizeBytes   int
 	messages    []*MemoryEntry
 	mu          sync.RWMutex
 }
@@ -117,19 +131,50 @@ func NewWorkingMemory(maxMessages int) (*WorkingMemory, error) {
 	}, nil
 }
 
+// WithMaxBytes caps the total content size of stored entries (0 means no
+// byte limit) and returns the memory for chaining. Oldest entries are
+// evicted until both the byte budget and the message limit hold, whichever
+// binds first, except that the most recent entry is always kept.
+func (w *WorkingMemory) WithMaxBytes(maxBytes int) *WorkingMemory {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	w.maxBytes = maxBytes
+	w.evict()
+	return w
+}
+
 // Store stores a memory entry in working memory.
 func (w *WorkingMemory) Store(ctx context.Context, entry *MemoryEntry) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
 	w.messages = append(w.messages, entry)
+	w.sizeBytes += len(entry.Content)
+	w.evict()
+
+	return nil
+}
 
-	// Evict oldest if over capacity
-	if len(w.messages) > w.maxMessages {
+// evict removes the oldest entries while over the message or byte limit.
+// Callers hold w.mu.
+func (w *WorkingMemory) evict() {
+	for len(w.messages) > w.maxMessages ||
+		(w.maxBytes > 0 && w.sizeBytes > w.maxBytes && len(w.messages) > 1) {
+		w.sizeBytes -= len(w.messages[0].Content)
 		w.messages = w.messages[1:]
 	}
+}
 
-	return nil
+// resize recomputes the stored content size. Callers hold w.mu.
+func (w *WorkingMemory) resize() {
+	w.sizeBytes = 0
+	for _, entry := range w.messages {
+		w.sizeBytes += len(entry.Content)
+	}
 }
 
 // Retrieve retrieves recent messages from working memory.
@@ -161,6 +206,7 @@ func (w *WorkingMemory) Delete(ctx context.Context, entryID string) error {
 		}
 	}
 	w.messages = filtered
+	w.resize()
 
 	return nil
 }
@@ -181,16 +227,97 @@ func (w *WorkingMemory) Clear() {
 	defer w.mu.Unlock()
 
 	w.messages = make([]*MemoryEntry, 0)
+	w.sizeBytes = 0
+}
+
+// Compress shortens working memory with compressor, for example a
+// SummarizingCompressor.
+//
+// Entries are presented to the compressor as messages whose role comes
+// from metadata["role"] (default "user"). Entries the compressor keeps
+// retain their identity; new messages (such as a summary) become new
+// entries with their metadata and the highest importance of the entries
+// they replace.
+func (w *WorkingMemory) Compress(ctx context.Context, compressor HistoryCompressor) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	messages := make([]*agenkit.Message, len(w.messages))
+	entries := make(map[*agenkit.Message]*MemoryEntry, len(w.messages))
+	for i, entry := range w.messages {
+		role, _ := entry.Metadata["role"].(string)
+		if role == "" {
+			role = agenkit.RoleUser
+		}
+		messages[i] = &agenkit.Message{Role: role, Content: entry.Content, Metadata: entry.Metadata, Timestamp: entry.Timestamp}
+		entries[messages[i]] = entry
+	}
+
+	compressed, err := compressor.Compress(ctx, messages)
+	if err != nil {
+		return fmt.Errorf("failed to compress working memory: %w", err)
+	}
+
+	kept := make(map[*MemoryEntry]bool, len(compressed))
+	for _, msg := range compressed {
+		if entry, ok := entries[msg]; ok {
+			kept[entry] = true
+		}
+	}
+
+	importance := 0.0
+	sessionID := ""
+	for _, entry := range w.messages {
+		if !kept[entry] {
+			importance = max(importance, entry.Importance)
+			if sessionID == "" {
+				sessionID = entry.SessionID
+			}
+		}
+	}
+
+	result := make([]*MemoryEntry, 0, len(compressed))
+	for _, msg := range compressed {
+		if entry, ok := entries[msg]; ok {
+			result = append(result, entry)
+			continue
+		}
+		metadata := make(map[string]interface{}, len(msg.Metadata)+1)
+		for k, v := range msg.Metadata {
+			metadata[k] = v
+		}
+		metadata["role"] = msg.Role
+		result = append(result, CreateMemoryEntry(msg.ContentString(), metadata, importance, sessionID))
+	}
+	w.messages = result
+	w.resize()
+	w.evict()
+
+	return nil
 }
 
 // Length returns the number of entries in working memory.
 func (w *WorkingMemory) Length() int {
+	return w.Len()
+}
+
+// Len returns the number of entries in working memory.
+func (w *WorkingMemory) Len() int {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
 	return len(w.messages)
 }
 
+// SizeBytes returns the total content size of the entries in working
+// memory.
+func (w *WorkingMemory) SizeBytes() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.sizeBytes
+}
+
 // ShortTermMemory is recent session memory with TTL-based expiration.
 //
 // Characteristics:
@@ -453,6 +580,126 @@ func max(a, b float64) float64 {
 	return b
 }
 
+// Memory tiers, as searched by MemoryHierarchy.Retrieve and reported in
+// MemoryEntry.Tier.
+const (
+	// MemoryTierWorking is the working memory tier
+	MemoryTierWorking = "working"
+	// MemoryTierShortTerm is the short-term memory tier
+	MemoryTierShortTerm = "short_term"
+	// MemoryTierLongTerm is the long-term memory tier
+	MemoryTierLongTerm = "long_term"
+)
+
+// RankingFunc scores a memory entry for query at time now; higher ranks
+// first. The entry's Tier is set when it is called.
+type RankingFunc func(entry *MemoryEntry, query string, now time.Time) float64
+
+// RankingWeights configures NewWeightedRanking. Each component scores from
+// 0.0 to 1.0 and the rank is their weighted sum.
+type RankingWeights struct {
+	// Relevance weights how well the content matches the query: 1.0 if it
+	// contains the whole query, otherwise the fraction of query words it
+	// contains
+	Relevance float64
+	// Importance weights the entry's importance
+	Importance float64
+	// Recency weights how recently the entry was created, decaying
+	// exponentially with RecencyHalfLife
+	Recency float64
+	// Tier weights the priority of the tier the entry came from
+	Tier float64
+	// RecencyHalfLife is the age at which the recency score halves
+	// (default: 24 hours)
+	RecencyHalfLife time.Duration
+	// TierPriority scores each tier (default: working 1.0, short-term 0.6,
+	// long-term 0.3)
+	TierPriority map[string]float64
+}
+
+// DefaultRankingWeights returns the weights of DefaultRanking: relevance
+// 0.4, importance 0.3, recency 0.2 and tier 0.1, with a 24 hour recency
+// half-life.
+func DefaultRankingWeights() RankingWeights {
+	return RankingWeights{
+		Relevance:       0.4,
+		Importance:      0.3,
+		Recency:         0.2,
+		Tier:            0.1,
+		RecencyHalfLife: 24 * time.Hour,
+		TierPriority: map[string]float64{
+			MemoryTierWorking:   1.0,
+			MemoryTierShortTerm: 0.6,
+			MemoryTierLongTerm:  0.3,
+		},
+	}
+}
+
+// NewWeightedRanking returns a RankingFunc that sums relevance, importance,
+// recency and tier priority scores with weights.
+//
+// Example:
+//
+//	// Favor recent entries: recency halves every hour
+//	weights := patterns.DefaultRankingWeights()
+//	weights.Recency = 0.5
+//	weights.RecencyHalfLife = time.Hour
+//	memory.WithRanking(patterns.NewWeightedRanking(weights))
+func NewWeightedRanking(weights RankingWeights) RankingFunc {
+	defaults := DefaultRankingWeights()
+	if weights.RecencyHalfLife <= 0 {
+		weights.RecencyHalfLife = defaults.RecencyHalfLife
+	}
+	if weights.TierPriority == nil {
+		weights.TierPriority = defaults.TierPriority
+	}
+
+	return func(entry *MemoryEntry, query string, now time.Time) float64 {
+		age := now.Sub(entry.Timestamp)
+		if age < 0 {
+			age = 0
+		}
+		recency := math.Pow(0.5, float64(age)/float64(weights.RecencyHalfLife))
+
+		return weights.Relevance*memoryRelevance(entry.Content, query) +
+			weights.Importance*entry.Importance +
+			weights.Recency*recency +
+			weights.Tier*weights.TierPriority[entry.Tier]
+	}
+}
+
+// DefaultRanking is the RankingFunc MemoryHierarchy uses unless configured
+// with WithRanking: NewWeightedRanking with DefaultRankingWeights.
+var DefaultRanking = NewWeightedRanking(DefaultRankingWeights())
+
+// memoryRelevance scores how well content matches query: 1.0 if it
+// contains the whole query, otherwise the fraction of query words it
+// contains (0 for an empty query).
+func memoryRelevance(content, query string) float64 {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return 0
+	}
+	content = strings.ToLower(content)
+	if strings.Contains(content, query) {
+		return 1
+	}
+
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return 0
+	}
+	matched := 0
+	for _, word := range words {
+		if strings.Contains(content, word) {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(words))
+}
+
 // MemoryHierarchy is a multi-tier memory system for agents.
 //
 // Manages working, short-term, and long-term memory with automatic
@@ -461,6 +708,7 @@ type MemoryHierarchy struct {
 	working   *WorkingMemory
 	shortTerm *ShortTermMemory
 	longTerm  *LongTermMemory
+	ranking   RankingFunc
 }
 
 // NewMemoryHierarchy creates a new memory hierarchy.
@@ -473,9 +721,20 @@ func NewMemoryHierarchy(
 		working:   workingMemory,
 		shortTerm: shortTermMemory,
 		longTerm:  longTermMemory,
+		ranking:   DefaultRanking,
 	}
 }
 
+// WithRanking sets how Retrieve ranks entries across tiers (nil restores
+// DefaultRanking) and returns the hierarchy for chaining.
+func (m *MemoryHierarchy) WithRanking(ranking RankingFunc) *MemoryHierarchy {
+	if ranking == nil {
+		ranking = DefaultRanking
+	}
+	m.ranking = ranking
+	return m
+}
+
 // Store stores memory across appropriate tiers.
 func (m *MemoryHierarchy) Store(
 	ctx context.Context,
@@ -515,7 +774,11 @@ func (m *MemoryHierarchy) Store(
 
 // Retrieve retrieves memories from hierarchy.
 //
-// Searches across all enabled tiers and returns deduplicated, ranked results.
+// Searches across all enabled tiers (all if searchTiers is nil) and returns
+// deduplicated results ranked by the hierarchy's RankingFunc. Each result
+// is a copy of the stored entry with Tier set to the highest-priority tier
+// it was found in and RankScore to its rank. Ties go to the more important,
+// then the more recent, entry.
 func (m *MemoryHierarchy) Retrieve(
 	ctx context.Context,
 	query string,
@@ -527,54 +790,74 @@ func (m *MemoryHierarchy) Retrieve(
 	// Determine which tiers to search
 	tiersToSearch := searchTiers
 	if tiersToSearch == nil {
-		tiersToSearch = []string{"working", "short_term", "long_term"}
+		tiersToSearch = []string{MemoryTierWorking, MemoryTierShortTerm, MemoryTierLongTerm}
+	}
+
+	// Search tiers in priority order, so an entry's first occurrence is
+	// its highest-priority tier
+	tiers := make(map[*MemoryEntry]string)
+	collect := func(tier string, entries []*MemoryEntry) {
+		for _, entry := range entries {
+			if _, ok := tiers[entry]; !ok {
+				tiers[entry] = tier
+			}
+		}
+		results = append(results, entries...)
 	}
 
 	// Search working memory
-	if contains(tiersToSearch, "working") {
+	if contains(tiersToSearch, MemoryTierWorking) {
 		workingResults, err := m.working.Retrieve(ctx, query, limit)
 		if err != nil {
 			return nil, fmt.Errorf("failed to retrieve from working memory: %w", err)
 		}
-		results = append(results, workingResults...)
+		collect(MemoryTierWorking, workingResults)
 	}
 
 	// Search short-term memory
-	if m.shortTerm != nil && contains(tiersToSearch, "short_term") {
+	if m.shortTerm != nil && contains(tiersToSearch, MemoryTierShortTerm) {
 		shortResults, err := m.shortTerm.Retrieve(ctx, query, limit)
 		if err != nil {
 			return nil, fmt.Errorf("failed to retrieve from short-term memory: %w", err)
 		}
-		results = append(results, shortResults...)
+		collect(MemoryTierShortTerm, shortResults)
 	}
 
 	// Search long-term memory
-	if m.longTerm != nil && contains(tiersToSearch, "long_term") {
+	if m.longTerm != nil && contains(tiersToSearch, MemoryTierLongTerm) {
 		longResults, err := m.longTerm.Retrieve(ctx, query, limit)
 		if err != nil {
 			return nil, fmt.Errorf("failed to retrieve from long-term memory: %w", err)
 		}
-		results = append(results, longResults...)
+		collect(MemoryTierLongTerm, longResults)
 	}
 
-	// Deduplicate by ID
+	// Deduplicate by ID and rank copies, leaving stored entries untouched
+	ranking := m.ranking
+	if ranking == nil {
+		ranking = DefaultRanking
+	}
+	now := time.Now()
 	seen := make(map[string]bool)
 	unique := make([]*MemoryEntry, 0)
 
 	for _, entry := range results {
 		if !seen[entry.ID] {
 			seen[entry.ID] = true
-			unique = append(unique, entry)
+			ranked := *entry
+			ranked.Tier = tiers[entry]
+			ranked.RankScore = ranking(&ranked, query, now)
+			unique = append(unique, &ranked)
 		}
 	}
 
-	// Sort by importance and recency
-	sort.Slice(unique, func(i, j int) bool {
-		// Primary: importance
+	sort.SliceStable(unique, func(i, j int) bool {
+		if unique[i].RankScore != unique[j].RankScore {
+			return unique[i].RankScore > unique[j].RankScore
+		}
 		if unique[i].Importance != unique[j].Importance {
 			return unique[i].Importance > unique[j].Importance
 		}
-		// Secondary: recency
 		return unique[i].Timestamp.After(unique[j].Timestamp)
 	})
 
@@ -92,6 +92,59 @@ func TestWorkingMemory_FIFOEviction(t *testing.T) {
 	}
 }
 
+func TestWorkingMemory_MaxBytesEviction(t *testing.T) {
+	wm, _ := NewWorkingMemory(10)
+	wm.WithMaxBytes(10)
+
+	for _, content := range []string{"aaaa", "bbbb", "cccc"} {
+		_ = wm.Store(context.Background(), CreateMemoryEntry(content, nil, 0.5, ""))
+	}
+
+	// 12 bytes exceeds the 10-byte budget before the 10-message limit
+	if wm.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", wm.Len())
+	}
+	if wm.SizeBytes() != 8 {
+		t.Errorf("expected 8 bytes, got %d", wm.SizeBytes())
+	}
+	if all := wm.GetAll(); all[0].Content != "bbbb" {
+		t.Errorf("expected oldest entry evicted, got %s", all[0].Content)
+	}
+
+	// An entry larger than the budget is kept alone
+	_ = wm.Store(context.Background(), CreateMemoryEntry(strings.Repeat("d", 25), nil, 0.5, ""))
+	if wm.Len() != 1 || wm.SizeBytes() != 25 {
+		t.Errorf("expected only the oversized entry, got %d entries, %d bytes", wm.Len(), wm.SizeBytes())
+	}
+}
+
+func TestWorkingMemory_SizeBytesTracksChanges(t *testing.T) {
+	wm, _ := NewWorkingMemory(10)
+	first := CreateMemoryEntry("hello", nil, 0.5, "")
+	_ = wm.Store(context.Background(), first)
+	_ = wm.Store(context.Background(), CreateMemoryEntry("world!", nil, 0.5, ""))
+	if wm.SizeBytes() != 11 {
+		t.Errorf("expected 11 bytes, got %d", wm.SizeBytes())
+	}
+
+	_ = wm.Delete(context.Background(), first.ID)
+	if wm.SizeBytes() != 6 {
+		t.Errorf("expected 6 bytes after delete, got %d", wm.SizeBytes())
+	}
+
+	// Tightening the budget evicts immediately, keeping the newest entry
+	_ = wm.Store(context.Background(), CreateMemoryEntry("again", nil, 0.5, ""))
+	wm.WithMaxBytes(5)
+	if wm.Len() != 1 || wm.SizeBytes() != 5 {
+		t.Errorf("expected 1 entry of 5 bytes, got %d entries, %d bytes", wm.Len(), wm.SizeBytes())
+	}
+
+	wm.Clear()
+	if wm.SizeBytes() != 0 {
+		t.Errorf("expected 0 bytes after clear, got %d", wm.SizeBytes())
+	}
+}
+
 func TestWorkingMemory_Retrieve(t *testing.T) {
 	wm, _ := NewWorkingMemory(10)
 
@@ -427,6 +480,75 @@ func TestMemoryHierarchy_Retrieve_SpecificTiers(t *testing.T) {
 	}
 }
 
+func TestMemoryHierarchy_Retrieve_RecentRelevantOutranksOldFact(t *testing.T) {
+	wm, _ := NewWorkingMemory(10)
+	ltm, _ := NewLongTermMemory(nil, nil, 0.5)
+	hierarchy := NewMemoryHierarchy(wm, nil, ltm)
+
+	old := CreateMemoryEntry("User's favorite color is blue", nil, 0.9, "")
+	old.Timestamp = time.Now().Add(-90 * 24 * time.Hour)
+	_ = ltm.Store(context.Background(), old)
+	_, _ = hierarchy.Store(context.Background(), "Deploy target for this task is staging", nil, 0.4, "")
+
+	results, err := hierarchy.Retrieve(context.Background(), "deploy target", 10, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if !strings.Contains(results[0].Content, "staging") {
+		t.Errorf("expected the recent relevant entry first, got %q", results[0].Content)
+	}
+	if results[0].Tier != MemoryTierWorking || results[1].Tier != MemoryTierLongTerm {
+		t.Errorf("tiers = %q, %q", results[0].Tier, results[1].Tier)
+	}
+	if results[0].RankScore <= results[1].RankScore {
+		t.Errorf("rank scores %v, %v should be descending", results[0].RankScore, results[1].RankScore)
+	}
+	if old.Tier != "" || old.RankScore != 0 {
+		t.Error("ranking should not modify stored entries")
+	}
+}
+
+func TestMemoryHierarchy_WithRanking(t *testing.T) {
+	wm, _ := NewWorkingMemory(10)
+	hierarchy := NewMemoryHierarchy(wm, nil, nil).WithRanking(func(entry *MemoryEntry, query string, now time.Time) float64 {
+		return float64(len(entry.Content))
+	})
+
+	_, _ = hierarchy.Store(context.Background(), "a much longer entry", nil, 0.1, "")
+	_, _ = hierarchy.Store(context.Background(), "short", nil, 0.9, "")
+
+	results, _ := hierarchy.Retrieve(context.Background(), "", 10, nil)
+	if results[0].Content != "a much longer entry" || results[0].RankScore != 19 {
+		t.Errorf("expected the custom ranking to order results, got %q (%v)", results[0].Content, results[0].RankScore)
+	}
+}
+
+func TestNewWeightedRanking(t *testing.T) {
+	now := time.Now()
+	entry := &MemoryEntry{Content: "Python is preferred", Importance: 0.5, Timestamp: now.Add(-time.Hour), Tier: MemoryTierShortTerm}
+
+	recency := NewWeightedRanking(RankingWeights{Recency: 1, RecencyHalfLife: time.Hour})
+	if score := recency(entry, "", now); score < 0.49 || score > 0.51 {
+		t.Errorf("recency after one half-life = %v, want 0.5", score)
+	}
+
+	relevance := NewWeightedRanking(RankingWeights{Relevance: 1})
+	if score := relevance(entry, "is Python preferred?", now); score < 0.99 {
+		t.Errorf("relevance with every word matching = %v, want 1", score)
+	}
+	if score := relevance(entry, "python or go", now); score < 0.33 || score > 0.34 {
+		t.Errorf("relevance with one of three words = %v, want 1/3", score)
+	}
+
+	tier := NewWeightedRanking(RankingWeights{Tier: 1})
+	if score := tier(entry, "", now); score != 0.6 {
+		t.Errorf("short-term tier priority = %v, want 0.6", score)
+	}
+}
+
 func TestMemoryHierarchy_Delete_AllTiers(t *testing.T) {
 	wm, _ := NewWorkingMemory(10)
 	stm, _ := NewShortTermMemory(100, 3600)
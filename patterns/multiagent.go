@@ -108,11 +108,18 @@ const (
 //	    Content: "Create a comprehensive report on AI",
 //	})
 //	// Each agent processes the message in sequence
+//
+// By default the stage outputs are concatenated into the result. With
+// WithSynthesizer, a synthesizer agent condenses them into a final message
+// instead.
 type MultiAgentOrchestrator struct {
-	name     string
-	agents   map[string]agenkit.Agent
-	strategy OrchestrationStrategy
-	tasks    []AgentTask
+	name        string
+	agents      map[string]agenkit.Agent
+	strategy    OrchestrationStrategy
+	tasks       []AgentTask
+	synthesizer agenkit.Agent
+
+	lazy lazyInit
 }
 
 // NewMultiAgentOrchestrator creates a new multi-agent orchestrator.
@@ -166,6 +173,24 @@ func (m *MultiAgentOrchestrator) UnregisterAgent(name string) {
 	delete(m.agents, name)
 }
 
+// WithSynthesizer sets an agent that receives all stage outputs and
+// produces the final message, like the Supervisor's synthesis step, and
+// returns the orchestrator for chaining. Pass nil to restore the default
+// concatenation.
+//
+// The synthesizer's input lists the original request and each stage's
+// output, and carries the stage outputs in metadata "stage_outputs" (see
+// Process). The synthesizer's response is the result.
+//
+// Example:
+//
+//	orchestrator := patterns.NewMultiAgentOrchestrator(patterns.StrategySequential).
+//	    WithSynthesizer(summaryAgent)
+func (m *MultiAgentOrchestrator) WithSynthesizer(synthesizer agenkit.Agent) *MultiAgentOrchestrator {
+	m.synthesizer = synthesizer
+	return m
+}
+
 // ListAgents returns list of registered agent names.
 func (m *MultiAgentOrchestrator) ListAgents() []string {
 	names := make([]string, 0, len(m.agents))
@@ -180,8 +205,23 @@ func (m *MultiAgentOrchestrator) ListAgents() []string {
 // Currently implements sequential strategy where all agents process
 // the message one after another. Results are combined into a single
 // response.
-func (m *MultiAgentOrchestrator) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+//
+// With a synthesizer (see WithSynthesizer), the synthesizer's response is
+// returned instead, with metadata "stage_outputs": one map per stage with
+// "agent", "status" and either "output" or "error". A synthesizer failure
+// fails Process.
+func (m *MultiAgentOrchestrator) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := m.Init(ctx); err != nil {
+		return nil, err
+	}
+
 	results := make([]string, 0, len(m.agents))
+	stageOutputs := make([]map[string]interface{}, 0, len(m.agents))
 
 	for agentName, agent := range m.agents {
 		task := AgentTask{
@@ -192,25 +232,62 @@ func (m *MultiAgentOrchestrator) Process(ctx context.Context, message *agenkit.M
 		m.tasks = append(m.tasks, task)
 		taskIdx := len(m.tasks) - 1
 
-		response, err := agent.Process(ctx, message)
+		response, err := ProcessTraced(ctx, agent, message)
 		if err != nil {
 			m.tasks[taskIdx].Error = err.Error()
 			m.tasks[taskIdx].Status = TaskStatusFailed
 			results = append(results, fmt.Sprintf("%s: Failed - %s", agentName, err.Error()))
+			stageOutputs = append(stageOutputs, map[string]interface{}{
+				"agent":  agentName,
+				"status": string(TaskStatusFailed),
+				"error":  err.Error(),
+			})
 		} else {
 			m.tasks[taskIdx].Result = response.ContentString()
 			m.tasks[taskIdx].Status = TaskStatusCompleted
 			results = append(results, fmt.Sprintf("%s: %s", agentName, response.ContentString()))
+			stageOutputs = append(stageOutputs, map[string]interface{}{
+				"agent":  agentName,
+				"status": string(TaskStatusCompleted),
+				"output": response.ContentString(),
+			})
 		}
 	}
 
 	combinedResult := strings.Join(results, "\n\n")
+	if m.synthesizer != nil {
+		return m.synthesize(ctx, message, combinedResult, stageOutputs)
+	}
 	return &agenkit.Message{
-		Role:    "assistant",
+		Role:    agenkit.RoleAssistant,
 		Content: combinedResult,
 	}, nil
 }
 
+// synthesize asks the synthesizer to condense the stage outputs into the
+// final message.
+func (m *MultiAgentOrchestrator) synthesize(ctx context.Context, original *agenkit.Message, combined string, stageOutputs []map[string]interface{}) (*agenkit.Message, error) {
+	prompt := fmt.Sprintf("Original request: %s\n\nStage outputs:\n\n%s\n\nSynthesize these into a single, concise response.",
+		original.ContentString(), combined)
+	input := agenkit.NewMessage(original.Role, prompt)
+	for k, v := range original.Metadata {
+		input.Metadata[k] = v
+	}
+	input.Metadata["stage_outputs"] = stageOutputs
+
+	response, err := ProcessTraced(ctx, m.synthesizer, input)
+	if err != nil {
+		return nil, fmt.Errorf("synthesis failed: %w", err)
+	}
+	if response == nil {
+		return nil, fmt.Errorf("synthesizer %s returned no message", m.synthesizer.Name())
+	}
+
+	result := copyMessage(response)
+	result.Metadata["stage_outputs"] = stageOutputs
+	return result, nil
+}
+
 // GetTasks returns all tasks that have been executed.
 func (m *MultiAgentOrchestrator) GetTasks() []AgentTask {
 	// Return a copy
@@ -244,6 +321,9 @@ type ConsensusAgent struct {
 	name           string
 	agents         []agenkit.Agent
 	votingStrategy VotingStrategy
+	voter          ConsensusVoter
+
+	lazy lazyInit
 }
 
 // NewConsensusAgent creates a new consensus agent.
@@ -293,6 +373,15 @@ func (c *ConsensusAgent) Agents() []agenkit.Agent {
 	return agentsCopy
 }
 
+// WithVoter sets the voter that decides the consensus from the agents'
+// full responses, for example a BallotVoter, and returns the agent for
+// chaining. Without a voter, Process combines all responses into a
+// summary.
+func (c *ConsensusAgent) WithVoter(voter ConsensusVoter) *ConsensusAgent {
+	c.voter = voter
+	return c
+}
+
 // AddAgent adds an agent to the consensus group.
 func (c *ConsensusAgent) AddAgent(agent agenkit.Agent) {
 	c.agents = append(c.agents, agent)
@@ -300,19 +389,39 @@ func (c *ConsensusAgent) AddAgent(agent agenkit.Agent) {
 
 // Process gets responses from all agents and forms consensus.
 //
-// Currently implements a simple consensus approach where all responses
-// are combined into a formatted summary showing each agent's perspective.
-func (c *ConsensusAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+// With a voter (see WithVoter), the voter's message is the result. Otherwise
+// all responses are combined into a formatted summary showing each agent's
+// perspective.
+func (c *ConsensusAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	messages := make([]*agenkit.Message, 0, len(c.agents))
 	responses := make([]string, 0, len(c.agents))
 
 	for _, agent := range c.agents {
-		response, err := agent.Process(ctx, message)
+		response, err := ProcessTraced(ctx, agent, message)
 		if err != nil {
 			return nil, fmt.Errorf("agent %s failed: %w", agent.Name(), err)
 		}
+		messages = append(messages, response)
 		responses = append(responses, response.ContentString())
 	}
 
+	if c.voter != nil {
+		result, err := c.voter.Vote(ctx, messages)
+		if err != nil {
+			return nil, fmt.Errorf("consensus vote failed: %w", err)
+		}
+		return result, nil
+	}
+
 	// Simple consensus: combine all responses
 	var consensus strings.Builder
 	consensus.WriteString(fmt.Sprintf("Consensus from %d agents:\n\n", len(responses)))
@@ -325,7 +434,7 @@ func (c *ConsensusAgent) Process(ctx context.Context, message *agenkit.Message)
 	}
 
 	return &agenkit.Message{
-		Role:    "assistant",
+		Role:    agenkit.RoleAssistant,
 		Content: consensus.String(),
 	}, nil
 }
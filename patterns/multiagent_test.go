@@ -544,3 +544,55 @@ func TestMultiAgentOrchestrator_ContextCancellation(t *testing.T) {
 		t.Fatal("expected non-nil result")
 	}
 }
+
+func TestMultiAgentOrchestrator_WithSynthesizer(t *testing.T) {
+	var synthesisInput *agenkit.Message
+	synthesizer := &extendedMockAgent{name: "summarizer", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		synthesisInput = msg
+		return agenkit.NewMessage("assistant", "Summary"), nil
+	}}
+
+	orchestrator := NewMultiAgentOrchestrator(StrategySequential).WithSynthesizer(synthesizer)
+	orchestrator.RegisterAgent("researcher", &mockMultiAgent{name: "researcher", response: "Findings"})
+	orchestrator.RegisterAgent("editor", &mockMultiAgent{name: "editor", err: errors.New("unavailable")})
+
+	input := agenkit.NewMessage("user", "Write a report")
+	result, err := orchestrator.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "Summary" {
+		t.Errorf("expected the synthesizer's response, got %q", result.ContentString())
+	}
+	if result.ParentID != input.ID {
+		t.Errorf("ParentID = %q, want the input's ID", result.ParentID)
+	}
+
+	content := synthesisInput.ContentString()
+	if !strings.Contains(content, "Write a report") || !strings.Contains(content, "researcher: Findings") {
+		t.Errorf("synthesis input missing request or stage output: %q", content)
+	}
+
+	stages, _ := result.Metadata["stage_outputs"].([]map[string]interface{})
+	if len(stages) != 2 {
+		t.Fatalf("stage_outputs = %v, want 2 stages", result.Metadata["stage_outputs"])
+	}
+	byAgent := make(map[string]map[string]interface{})
+	for _, stage := range stages {
+		byAgent[stage["agent"].(string)] = stage
+	}
+	if byAgent["researcher"]["output"] != "Findings" || byAgent["editor"]["status"] != "failed" {
+		t.Errorf("stage_outputs = %v", stages)
+	}
+}
+
+func TestMultiAgentOrchestrator_SynthesizerFailure(t *testing.T) {
+	orchestrator := NewMultiAgentOrchestrator(StrategySequential).
+		WithSynthesizer(&extendedMockAgent{name: "summarizer", err: errors.New("model overloaded")})
+	orchestrator.RegisterAgent("agent1", &mockMultiAgent{name: "agent1", response: "Response"})
+
+	_, err := orchestrator.Process(context.Background(), agenkit.NewMessage("user", "Test"))
+	if err == nil || !strings.Contains(err.Error(), "synthesis failed") {
+		t.Errorf("expected synthesis failure, got %v", err)
+	}
+}
@@ -15,7 +15,6 @@ package patterns
 import (
 	"context"
 	"fmt"
-	"sync"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
@@ -23,8 +22,10 @@ import (
 // AgentHook is called before/after agent execution
 type AgentHook func(agent agenkit.Agent, message *agenkit.Message)
 
-// Aggregator combines parallel results into a single message
-type Aggregator func(messages []*agenkit.Message) *agenkit.Message
+// Aggregator combines parallel results into a single message. It is the
+// same type as AggregatorFunc, so DefaultAggregators work with
+// ParallelPattern too.
+type Aggregator = AggregatorFunc
 
 // Router returns a handler key for routing a message
 type Router func(message *agenkit.Message) string
@@ -47,6 +48,8 @@ type SequentialPattern struct {
 	name        string
 	beforeAgent AgentHook
 	afterAgent  AgentHook
+
+	lazy lazyInit
 }
 
 // SequentialPatternConfig configures a sequential pattern
@@ -102,29 +105,44 @@ func (s *SequentialPattern) Capabilities() []string {
 	return caps
 }
 
-// Introspect returns introspection information about the pattern
+// Introspect returns introspection information about the pattern,
+// aggregating its agents' capabilities and listing them as stages under
+// TopologyKey
 func (s *SequentialPattern) Introspect() *agenkit.IntrospectionResult {
-	return &agenkit.IntrospectionResult{
-		AgentName:    s.Name(),
-		Capabilities: s.Capabilities(),
-	}
+	return introspectComposition(s.Name(), TopologySequential, s.Capabilities(), stageComponents(s.agents))
 }
 
-// Process executes agents sequentially
-func (s *SequentialPattern) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+// Process executes agents sequentially. CarryThroughKeys set by an agent
+// are carried forward through later agents that don't set them.
+func (s *SequentialPattern) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := s.Init(ctx); err != nil {
+		return nil, err
+	}
+
 	current := message
 
-	for _, agent := range s.agents {
+	for i, agent := range s.agents {
 		// Hook: before agent
 		if s.beforeAgent != nil {
 			s.beforeAgent(agent, current)
 		}
 
 		// Process
-		result, err := agent.Process(ctx, current)
+		result, err := ProcessTraced(ctx, agent, current)
 		if err != nil {
 			return nil, err
 		}
+		if result == nil {
+			return nil, fmt.Errorf("agent %d (%s) returned no message", i, agent.Name())
+		}
+		if i > 0 {
+			result = propagateStage(current, result)
+		}
 		current = result
 
 		// Hook: after agent
@@ -147,9 +165,14 @@ func (s *SequentialPattern) Unwrap() []agenkit.Agent {
 //
 // All agents receive the same input, execute concurrently, results are combined.
 //
+// Execution is fail-fast: the first agent to fail cancels the others, and
+// Process returns that error, wrapped with the failing agent's index,
+// without waiting for them. Use ParallelAgent to aggregate whichever agents
+// succeed instead.
+//
 // Performance characteristics:
 //   - True parallelism (uses goroutines)
-//   - Bounded by slowest agent
+//   - Bounded by slowest agent, or by the first failure
 //   - Memory: O(n) where n = number of agents
 //
 // Example:
@@ -166,6 +189,10 @@ type ParallelPattern struct {
 	name        string
 	beforeAgent AgentHook
 	afterAgent  AgentHook
+	group       *WorkGroup
+	scheduler   *FairScheduler
+
+	lazy lazyInit
 }
 
 // ParallelPatternConfig configures a parallel pattern
@@ -173,6 +200,12 @@ type ParallelPatternConfig struct {
 	Name        string
 	BeforeAgent AgentHook
 	AfterAgent  AgentHook
+	// WorkGroup runs the agents as units of a shared group so they can be
+	// drained on shutdown (optional)
+	WorkGroup *WorkGroup
+	// Scheduler runs each agent in a slot from a shared FairScheduler,
+	// under the message's tenant (optional)
+	Scheduler *FairScheduler
 }
 
 // NewParallelPattern creates a new parallel execution pattern
@@ -187,6 +220,8 @@ func NewParallelPattern(agents []agenkit.Agent, aggregator Aggregator, config *P
 
 	name := "parallel"
 	var beforeAgent, afterAgent AgentHook
+	var group *WorkGroup
+	var scheduler *FairScheduler
 
 	if config != nil {
 		if config.Name != "" {
@@ -194,6 +229,8 @@ func NewParallelPattern(agents []agenkit.Agent, aggregator Aggregator, config *P
 		}
 		beforeAgent = config.BeforeAgent
 		afterAgent = config.AfterAgent
+		group = config.WorkGroup
+		scheduler = config.Scheduler
 	}
 
 	return &ParallelPattern{
@@ -202,6 +239,8 @@ func NewParallelPattern(agents []agenkit.Agent, aggregator Aggregator, config *P
 		name:        name,
 		beforeAgent: beforeAgent,
 		afterAgent:  afterAgent,
+		group:       group,
+		scheduler:   scheduler,
 	}, nil
 }
 
@@ -226,26 +265,56 @@ func (p *ParallelPattern) Capabilities() []string {
 	return caps
 }
 
-// Introspect returns introspection information about the pattern
+// Introspect returns introspection information about the pattern,
+// aggregating its agents' capabilities and listing them under TopologyKey
 func (p *ParallelPattern) Introspect() *agenkit.IntrospectionResult {
-	return &agenkit.IntrospectionResult{
-		AgentName:    p.Name(),
-		Capabilities: p.Capabilities(),
-	}
+	return introspectComposition(p.Name(), TopologyParallel, p.Capabilities(), branchComponents(p.agents))
 }
 
-// Process executes agents in parallel and aggregates results
-func (p *ParallelPattern) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	// Create channels for results and errors
-	results := make([]*agenkit.Message, len(p.agents))
-	errors := make([]error, len(p.agents))
-	var wg sync.WaitGroup
+// Process executes agents in parallel and aggregates results in agent
+// order, each recording its completion order under CompletionRankKey.
+// CarryThroughKeys the aggregate lacks are taken from the first agent
+// result, in agent order, that has them.
+func (p *ParallelPattern) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := p.Init(ctx); err != nil {
+		return nil, err
+	}
 
-	// Execute all agents in parallel
+	// Cancel in-flight agents if we return early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type indexedResult struct {
+		index  int
+		result *agenkit.Message
+		err    error
+	}
+
+	// Buffered so workers never block after Process returns
+	resultsCh := make(chan indexedResult, len(p.agents))
+
+	// Execute agents in parallel, stopping if the context is cancelled
+	tenant := p.scheduler.TenantOf(ctx, message)
+	launched := 0
 	for i, agent := range p.agents {
-		wg.Add(1)
-		go func(index int, ag agenkit.Agent) {
-			defer wg.Done()
+		if ctx.Err() != nil {
+			break
+		}
+		launched++
+		index, ag := i, agent
+		err := startWork(ctx, p.group, ag.Name(), func(ctx context.Context) error {
+			release, err := p.scheduler.Acquire(ctx, tenant)
+			if err != nil {
+				resultsCh <- indexedResult{index: index, err: err}
+				return err
+			}
+			defer release()
+			ctx = p.scheduler.WithSlot(ctx)
 
 			// Hook: before agent
 			if p.beforeAgent != nil {
@@ -253,32 +322,41 @@ func (p *ParallelPattern) Process(ctx context.Context, message *agenkit.Message)
 			}
 
 			// Process
-			result, err := ag.Process(ctx, message)
-			if err != nil {
-				errors[index] = err
-				return
-			}
-			results[index] = result
-
-			// Hook: after agent
-			if p.afterAgent != nil {
+			result, err := ProcessTraced(ctx, ag, message)
+			if err == nil && p.afterAgent != nil {
+				// Hook: after agent
 				p.afterAgent(ag, result)
 			}
-		}(i, agent)
-	}
-
-	// Wait for all agents to complete
-	wg.Wait()
 
-	// Check for errors
-	for i, err := range errors {
+			resultsCh <- indexedResult{index: index, result: result, err: err}
+			return err
+		})
 		if err != nil {
-			return nil, fmt.Errorf("agent %d failed: %w", i, err)
+			resultsCh <- indexedResult{index: index, err: err}
+		}
+	}
+
+	// Wait for all launched agents, the first error, or cancellation
+	results := make([]*agenkit.Message, len(p.agents))
+	for received := 0; received < launched; received++ {
+		select {
+		case r := <-resultsCh:
+			if r.err != nil {
+				return nil, fmt.Errorf("agent %d failed: %w", r.index, r.err)
+			}
+			results[r.index] = r.result
+			setCompletionRank(r.result, received)
+		case <-ctx.Done():
+			return nil, fmt.Errorf("parallel pattern cancelled: %w", contextError(ctx))
 		}
 	}
 
-	// Aggregate results
-	return p.aggregator(results), nil
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("parallel pattern cancelled: %w", contextError(ctx))
+	}
+
+	// Aggregate results, keeping carry-through keys
+	return propagateBranches(results, p.aggregator(results)), nil
 }
 
 // Unwrap returns the underlying agents list
@@ -316,12 +394,18 @@ type RouterPattern struct {
 	handlers       map[string]agenkit.Agent
 	defaultHandler agenkit.Agent
 	name           string
+	deadLetter     agenkit.DeadLetterSink
+
+	lazy lazyInit
 }
 
 // RouterPatternConfig configures a router pattern
 type RouterPatternConfig struct {
 	Name           string
 	DefaultHandler agenkit.Agent
+	// DeadLetters receives messages routed to an unknown key when there is
+	// no DefaultHandler (optional)
+	DeadLetters agenkit.DeadLetterSink
 }
 
 // NewRouterPattern creates a new router pattern
@@ -336,12 +420,14 @@ func NewRouterPattern(router Router, handlers map[string]agenkit.Agent, config *
 
 	name := "router"
 	var defaultHandler agenkit.Agent
+	var deadLetter agenkit.DeadLetterSink
 
 	if config != nil {
 		if config.Name != "" {
 			name = config.Name
 		}
 		defaultHandler = config.DefaultHandler
+		deadLetter = config.DeadLetters
 	}
 
 	return &RouterPattern{
@@ -349,6 +435,7 @@ func NewRouterPattern(router Router, handlers map[string]agenkit.Agent, config *
 		handlers:       handlers,
 		defaultHandler: defaultHandler,
 		name:           name,
+		deadLetter:     deadLetter,
 	}, nil
 }
 
@@ -378,16 +465,26 @@ func (r *RouterPattern) Capabilities() []string {
 	return caps
 }
 
-// Introspect returns introspection information about the pattern
+// Introspect returns introspection information about the pattern,
+// aggregating its handlers' capabilities and listing them by route under
+// TopologyKey
 func (r *RouterPattern) Introspect() *agenkit.IntrospectionResult {
-	return &agenkit.IntrospectionResult{
-		AgentName:    r.Name(),
-		Capabilities: r.Capabilities(),
-	}
+	return introspectComposition(r.Name(), TopologyRouter, r.Capabilities(), routeComponents(r.handlers, r.defaultHandler, ""))
 }
 
-// Process routes the message to the appropriate handler
-func (r *RouterPattern) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+// Process routes the message to the appropriate handler. A message routed
+// to an unknown key without a default handler is written to the
+// DeadLetters sink, if configured, before the error is returned
+func (r *RouterPattern) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := r.Init(ctx); err != nil {
+		return nil, err
+	}
+
 	// Get handler key from router
 	key := r.router(message)
 
@@ -396,13 +493,18 @@ func (r *RouterPattern) Process(ctx context.Context, message *agenkit.Message) (
 	if !ok {
 		// Try default handler
 		if r.defaultHandler != nil {
-			return r.defaultHandler.Process(ctx, message)
+			AnnotateTrace(ctx, fmt.Sprintf("found no route for %q and used the default handler", key))
+			return ProcessTraced(ctx, r.defaultHandler, message)
 		}
-		return nil, fmt.Errorf("router returned unknown key '%s' and no default handler is configured", key)
+		err := fmt.Errorf("router returned unknown key '%s' and no default handler is configured", key)
+		writeDeadLetter(ctx, r.deadLetter, r.name, agenkit.DeadLetterUnroutable, message, err,
+			map[string]interface{}{"key": key})
+		return nil, err
 	}
+	AnnotateTrace(ctx, fmt.Sprintf("routed the request to %q", key))
 
 	// Process with selected handler
-	return handler.Process(ctx, message)
+	return ProcessTraced(ctx, handler, message)
 }
 
 // Unwrap returns the handlers map
@@ -486,3 +486,18 @@ func TestComposition_SequentialOfParallel(t *testing.T) {
 	assertError(t, err, false)
 	assertEqual(t, result.ContentString(), "C:parallel_result")
 }
+
+func TestRouterPattern_DeadLettersUnknownKey(t *testing.T) {
+	sink := agenkit.NewMemoryDeadLetterSink(0)
+	router, _ := NewRouterPattern(func(*agenkit.Message) string { return "unknown" }, map[string]agenkit.Agent{
+		"known": &extendedMockAgent{name: "known"},
+	}, &RouterPatternConfig{DeadLetters: sink})
+
+	msg := agenkit.NewMessage("user", "test")
+	if _, err := router.Process(context.Background(), msg); err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+	if letters := sink.Letters(); len(letters) != 1 || letters[0].Metadata["key"] != "unknown" || letters[0].Source != "router" {
+		t.Errorf("unexpected dead letters %+v", letters)
+	}
+}
@@ -18,8 +18,11 @@ package patterns
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"sync"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
@@ -35,6 +38,77 @@ import (
 //   - Consensus: Require agreement threshold
 type AggregatorFunc func([]*agenkit.Message) *agenkit.Message
 
+// CompletionRankKey is the metadata key ParallelAgent, ParallelPattern and
+// ScatterGatherAgent set on each result: its 0-based position in the order
+// the agents finished (0 finished first, failures included).
+// DefaultAggregators.FastestSuccessful selects by it.
+const CompletionRankKey = "completion_rank"
+
+// ParallelResultsKey is the metadata key the selecting aggregators
+// (DefaultAggregators.First, FastestSuccessful and Select) set on the
+// selected message: a []*agenkit.Message holding copies of every result,
+// in agent order.
+const ParallelResultsKey = "parallel_results"
+
+// ErrAllAgentsFailed is returned (wrapped) when every agent in a
+// ParallelAgent fails.
+var ErrAllAgentsFailed = errors.New("all agents failed")
+
+// AllAgentsFailedError reports that no agent in a ParallelAgent succeeded.
+// It wraps ErrAllAgentsFailed and each agent's error, so errors.Is works
+// for the sentinel or any individual cause.
+type AllAgentsFailedError struct {
+	// Agents are the failed agents' names, in agent order
+	Agents []string
+	// Errors are the corresponding agent errors
+	Errors []error
+}
+
+func (e *AllAgentsFailedError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		parts[i] = fmt.Sprintf("%s: %v", e.Agents[i], err)
+	}
+	return fmt.Sprintf("%v: %s", ErrAllAgentsFailed, strings.Join(parts, "; "))
+}
+
+// Unwrap returns the sentinel followed by every agent error.
+func (e *AllAgentsFailedError) Unwrap() []error {
+	return append([]error{ErrAllAgentsFailed}, e.Errors...)
+}
+
+// PartialFailuresKey is the metadata key under which ParallelAgent and
+// ScatterGatherAgent record a []PartialFailure when agents fail but a
+// result is still produced. Read it with GetPartialFailures.
+const PartialFailuresKey = "partial_failures"
+
+// PartialFailure records an agent that failed while others succeeded.
+type PartialFailure struct {
+	// AgentName is the failed agent's name
+	AgentName string
+	// Err is the agent's error
+	Err error
+	// Key identifies the failed item for a ScatterGatherAgent ("" otherwise)
+	Key string
+}
+
+// GetPartialFailures returns the failures recorded in msg's metadata, in
+// agent (or item) order, or nil if there were none.
+//
+// Example:
+//
+//	failures := patterns.GetPartialFailures(result)
+//	if len(failures) > len(agents)/2 {
+//	    alert("most of the ensemble failed")
+//	}
+func GetPartialFailures(msg *agenkit.Message) []PartialFailure {
+	if msg == nil {
+		return nil
+	}
+	failures, _ := msg.Metadata[PartialFailuresKey].([]PartialFailure)
+	return failures
+}
+
 // ParallelAgent executes multiple agents concurrently and aggregates results.
 //
 // All agents receive the same input message and execute concurrently.
@@ -48,11 +122,19 @@ type AggregatorFunc func([]*agenkit.Message) *agenkit.Message
 //   - Redundant processing for reliability
 //
 // If any agent fails, the error is collected but other agents continue.
-// The aggregator receives all successful results.
+// The aggregator receives all successful results, and the failures are
+// recorded for GetPartialFailures. If no agent succeeds,
+// Process returns an *AllAgentsFailedError without calling the aggregator
+// unless WithAggregateOnAllFailed is enabled.
 type ParallelAgent struct {
-	name       string
-	agents     []agenkit.Agent
-	aggregator AggregatorFunc
+	name               string
+	agents             []agenkit.Agent
+	aggregator         AggregatorFunc
+	aggregateAllFailed bool
+	group              *WorkGroup
+	scheduler          *FairScheduler
+
+	lazy lazyInit
 }
 
 // NewParallelAgent creates a new parallel execution agent.
@@ -78,6 +160,34 @@ func NewParallelAgent(agents []agenkit.Agent, aggregator AggregatorFunc) (*Paral
 	}, nil
 }
 
+// WithAggregateOnAllFailed sets whether the aggregator is called with an
+// empty slice when every agent fails, and returns the agent for chaining.
+//
+// Enable this to produce a custom fallback message from the aggregator;
+// the per-agent errors are still recorded (see GetPartialFailures). If the
+// aggregator returns nil, Process returns an *AllAgentsFailedError.
+func (p *ParallelAgent) WithAggregateOnAllFailed(enabled bool) *ParallelAgent {
+	p.aggregateAllFailed = enabled
+	return p
+}
+
+// WithWorkGroup runs the agents as units of group, so an in-flight
+// Process can be drained by group.Shutdown, and returns the agent for
+// chaining. Once the group is shutting down, agents fail with
+// ErrWorkGroupClosed instead of starting.
+func (p *ParallelAgent) WithWorkGroup(group *WorkGroup) *ParallelAgent {
+	p.group = group
+	return p
+}
+
+// WithScheduler runs each agent in a slot from scheduler, under the
+// message's tenant, so agents queue once the tenant reaches its quota, and
+// returns the agent for chaining.
+func (p *ParallelAgent) WithScheduler(scheduler *FairScheduler) *ParallelAgent {
+	p.scheduler = scheduler
+	return p
+}
+
 // Name returns the agent's identifier.
 func (p *ParallelAgent) Name() string {
 	return p.name
@@ -101,8 +211,15 @@ func (p *ParallelAgent) Capabilities() []string {
 	return capabilities
 }
 
+// Introspect reports the ensemble as a whole: the union of its agents'
+// capabilities, with the agents under TopologyKey.
+func (p *ParallelAgent) Introspect() *agenkit.IntrospectionResult {
+	return introspectComposition(p.Name(), TopologyParallel, p.Capabilities(), branchComponents(p.agents))
+}
+
 // agentResult holds the result or error from an agent execution.
 type agentResult struct {
+	index     int
 	agentName string
 	message   *agenkit.Message
 	err       error
@@ -112,107 +229,209 @@ type agentResult struct {
 //
 // All agents receive the same input message and execute in parallel using
 // goroutines. Results are collected as they complete. Once all agents finish
-// (or fail), successful results are passed to the aggregator function.
+// (or fail), successful results are passed to the aggregator function in
+// agent order (not completion order), so aggregation is deterministic.
+// Each result records its completion order under CompletionRankKey.
+//
+// If all agents fail, an *AllAgentsFailedError (wrapping ErrAllAgentsFailed)
+// is returned, unless WithAggregateOnAllFailed is enabled. If some agents
+// succeed, their results are aggregated and any errors are recorded in
+// metadata.
+//
+// If ctx is cancelled, no further agents are launched, in-flight agents are
+// cancelled, and Process returns the context error promptly.
 //
-// If all agents fail, an error is returned. If some agents succeed, their
-// results are aggregated and any errors are recorded in metadata.
+// The final message includes metadata:
+//   - "parallel_agents": total agents
+//   - "successful_agents" and "failed_agents": counts
+//   - PartialFailuresKey: a []PartialFailure, if any agent failed
+//   - "errors": the same failures as maps with "agent" and "error"
+//     (kept for compatibility; prefer GetPartialFailures)
 //
-// The final message includes metadata about:
-//   - Total agents executed
-//   - Successful agent results
-//   - Any errors that occurred
-func (p *ParallelAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	if message == nil {
-		return nil, fmt.Errorf("message cannot be nil")
+// CarryThroughKeys the aggregate lacks are taken from the first successful
+// result, in agent order, that has them.
+func (p *ParallelAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := p.Init(ctx); err != nil {
+		return nil, err
 	}
 
-	// Channel for collecting results
+	// Cancel in-flight agents if we return early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Channel for collecting results (buffered so workers never block)
 	resultsCh := make(chan agentResult, len(p.agents))
 
-	// WaitGroup to track completion
-	var wg sync.WaitGroup
+	// Launch agents concurrently, stopping if the context is cancelled
+	tenant := p.scheduler.TenantOf(ctx, message)
+	launched := 0
+	for i, agent := range p.agents {
+		if ctx.Err() != nil {
+			break
+		}
+		launched++
+		index, a := i, agent
+		err := startWork(ctx, p.group, a.Name(), func(ctx context.Context) error {
+			release, err := p.scheduler.Acquire(ctx, tenant)
+			if err != nil {
+				resultsCh <- agentResult{index: index, agentName: a.Name(), err: err}
+				return err
+			}
+			defer release()
+			ctx = p.scheduler.WithSlot(ctx)
 
-	// Launch all agents concurrently
-	for _, agent := range p.agents {
-		wg.Add(1)
-		go func(a agenkit.Agent) {
-			defer wg.Done()
+			logger := Logger().With(slog.String("pattern", p.name), slog.String("agent", a.Name()))
+			logger.DebugContext(ctx, LogEventAgentStart)
+			start := time.Now()
 
 			// Process with agent
-			result, err := a.Process(ctx, message)
+			result, err := ProcessTraced(ctx, a, message)
+			if err != nil {
+				logger.WarnContext(ctx, LogEventAgentError, slog.Any("error", err))
+			} else {
+				logger.DebugContext(ctx, LogEventAgentEnd, slog.Duration("duration", time.Since(start)))
+				result = linkToInput(message, result)
+			}
 
 			// Send result to channel
 			resultsCh <- agentResult{
+				index:     index,
 				agentName: a.Name(),
 				message:   result,
 				err:       err,
 			}
-		}(agent)
+			return err
+		})
+		if err != nil {
+			resultsCh <- agentResult{index: index, agentName: a.Name(), err: err}
+		}
+	}
+
+	// Collect results until all launched agents report or ctx is cancelled
+	ordered := make([]*agenkit.Message, len(p.agents))
+	failed := make([]*agentResult, len(p.agents))
+
+	for received := 0; received < launched; received++ {
+		select {
+		case result := <-resultsCh:
+			if result.err != nil {
+				failed[result.index] = &result
+			} else {
+				ordered[result.index] = result.message
+				setCompletionRank(result.message, received)
+			}
+		case <-ctx.Done():
+			return nil, fmt.Errorf("parallel execution cancelled: %w", contextError(ctx))
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("parallel execution cancelled: %w", contextError(ctx))
 	}
 
-	// Close results channel when all agents complete
-	go func() {
-		wg.Wait()
-		close(resultsCh)
-	}()
-
-	// Collect all results
-	var successes []*agenkit.Message
-	var errors []map[string]interface{}
-
-	for result := range resultsCh {
-		if result.err != nil {
-			errors = append(errors, map[string]interface{}{
-				"agent": result.agentName,
-				"error": result.err.Error(),
-			})
-		} else {
-			successes = append(successes, result.message)
+	// Keep successful results in agent order
+	successes := make([]*agenkit.Message, 0, len(ordered))
+	for _, msg := range ordered {
+		if msg != nil {
+			successes = append(successes, msg)
 		}
 	}
 
-	// Check if all agents failed
-	if len(successes) == 0 {
-		return nil, fmt.Errorf("all agents failed: %v", errors)
+	// Keep failures in agent order too
+	var errorDetails []map[string]interface{}
+	var failures []PartialFailure
+	allFailed := &AllAgentsFailedError{}
+	for _, result := range failed {
+		if result == nil {
+			continue
+		}
+		errorDetails = append(errorDetails, map[string]interface{}{
+			"agent": result.agentName,
+			"error": result.err.Error(),
+
+			FailureCauseKey: FailureCause(result.err),
+		})
+		failures = append(failures, PartialFailure{AgentName: result.agentName, Err: result.err})
+		allFailed.Agents = append(allFailed.Agents, result.agentName)
+		allFailed.Errors = append(allFailed.Errors, result.err)
+	}
+
+	// Don't hand an empty slice to the aggregator unless asked to
+	if len(successes) == 0 && !p.aggregateAllFailed {
+		return nil, allFailed
 	}
 
 	// Aggregate successful results
 	aggregated := p.aggregator(successes)
-
-	// Add parallel execution metadata
-	if aggregated.Metadata == nil {
-		aggregated.Metadata = make(map[string]interface{})
+	if aggregated == nil && len(successes) == 0 {
+		return nil, allFailed
 	}
+
+	// Add parallel execution metadata, on a copy since the aggregator may
+	// return one of the agents' messages
+	aggregated = propagateBranches(successes, aggregated)
 	aggregated.Metadata["parallel_agents"] = len(p.agents)
 	aggregated.Metadata["successful_agents"] = len(successes)
-	if len(errors) > 0 {
-		aggregated.Metadata["errors"] = errors
+	aggregated.Metadata["failed_agents"] = len(failures)
+	if len(failures) > 0 {
+		aggregated.Metadata[PartialFailuresKey] = failures
+		aggregated.Metadata["errors"] = errorDetails
 	}
 
 	return aggregated, nil
 }
 
-// DefaultAggregators provides common aggregation strategies.
+// DefaultAggregators provides common aggregation strategies. They skip nil
+// messages and return a placeholder message for an empty slice.
 var DefaultAggregators = struct {
-	// First returns the first successful result
+	// First returns the first result in agent order, with every result
+	// under ParallelResultsKey
 	First AggregatorFunc
 
+	// FastestSuccessful returns the result that finished first (lowest
+	// CompletionRankKey; results without a rank come last, in agent order),
+	// with every result under ParallelResultsKey
+	FastestSuccessful AggregatorFunc
+
+	// Select returns the result at the index selector picks from the
+	// non-nil results, with every result under ParallelResultsKey. An index
+	// out of range selects the first result.
+	Select func(selector func([]*agenkit.Message) int) AggregatorFunc
+
 	// Concatenate combines all results with separator
 	Concatenate AggregatorFunc
 
-	// MajorityVote returns the most common response
+	// MajorityVote returns the most common response.
+	//
+	// Ties are broken in favour of the response that appears first in
+	// messages (ParallelAgent passes results in agent order). The winner's
+	// metadata includes "votes" (its count), "total_agents", and
+	// "vote_tally" (count per distinct response).
 	MajorityVote AggregatorFunc
+
+	// Dedupe clusters near-duplicate responses and emits one representative
+	// per cluster, largest first, prefixed with the cluster size. Messages
+	// join a cluster when similarity with its first member is at least
+	// threshold; a nil similarity uses WordOverlapSimilarity. Metadata
+	// includes "clusters", "cluster_sizes" and "cluster_members" (message
+	// indexes per cluster, in output order) and "total_responses".
+	Dedupe func(similarity SimilarityFunc, threshold float64) AggregatorFunc
 }{
-	First: func(messages []*agenkit.Message) *agenkit.Message {
-		if len(messages) == 0 {
-			return agenkit.NewMessage("assistant", "No results to aggregate")
-		}
-		return messages[0]
-	},
+	First: selectAggregator(func(messages []*agenkit.Message) int { return 0 }),
+
+	FastestSuccessful: selectAggregator(fastestResult),
+
+	Select: selectAggregator,
 
 	Concatenate: func(messages []*agenkit.Message) *agenkit.Message {
+		messages = compactMessages(messages)
 		if len(messages) == 0 {
-			return agenkit.NewMessage("assistant", "No results to aggregate")
+			return agenkit.NewMessage(agenkit.RoleAssistant, "No results to aggregate")
 		}
 
 		var combined string
@@ -223,37 +442,112 @@ var DefaultAggregators = struct {
 			combined += msg.ContentString()
 		}
 
-		return agenkit.NewMessage("assistant", combined)
+		return agenkit.NewMessage(agenkit.RoleAssistant, combined)
 	},
 
 	MajorityVote: func(messages []*agenkit.Message) *agenkit.Message {
+		messages = compactMessages(messages)
 		if len(messages) == 0 {
-			return agenkit.NewMessage("assistant", "No results to aggregate")
+			return agenkit.NewMessage(agenkit.RoleAssistant, "No results to aggregate")
 		}
 
-		// Count occurrences of each response
+		// Count occurrences of each response, remembering first appearance
 		votes := make(map[string]int)
-		msgByContent := make(map[string]*agenkit.Message)
+		firstByContent := make(map[string]*agenkit.Message)
+		order := make([]string, 0, len(messages))
 
 		for _, msg := range messages {
-			votes[msg.ContentString()]++
-			msgByContent[msg.ContentString()] = msg
+			content := msg.ContentString()
+			if _, seen := firstByContent[content]; !seen {
+				firstByContent[content] = msg
+				order = append(order, content)
+			}
+			votes[content]++
 		}
 
-		// Find most common response
+		// Find most common response; strict > keeps the earliest on ties
 		var maxVotes int
 		var winner string
-		for content, count := range votes {
-			if count > maxVotes {
-				maxVotes = count
+		for _, content := range order {
+			if votes[content] > maxVotes {
+				maxVotes = votes[content]
 				winner = content
 			}
 		}
 
-		result := msgByContent[winner]
+		result := firstByContent[winner]
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]interface{})
+		}
 		result.WithMetadata("votes", maxVotes).
-			WithMetadata("total_agents", len(messages))
+			WithMetadata("total_agents", len(messages)).
+			WithMetadata("vote_tally", votes)
 
 		return result
 	},
+
+	Dedupe: func(similarity SimilarityFunc, threshold float64) AggregatorFunc {
+		return func(messages []*agenkit.Message) *agenkit.Message {
+			return dedupe(messages, similarity, threshold)
+		}
+	},
+}
+
+// selectAggregator returns an aggregator that picks one result by selector
+// and attaches copies of all results under ParallelResultsKey. The copies
+// have their own metadata maps, so the selected message doesn't contain
+// itself.
+func selectAggregator(selector func([]*agenkit.Message) int) AggregatorFunc {
+	return func(messages []*agenkit.Message) *agenkit.Message {
+		messages = compactMessages(messages)
+		if len(messages) == 0 {
+			return agenkit.NewMessage(agenkit.RoleAssistant, "No results to aggregate")
+		}
+
+		index := selector(messages)
+		if index < 0 || index >= len(messages) {
+			index = 0
+		}
+
+		all := make([]*agenkit.Message, len(messages))
+		for i, msg := range messages {
+			clone := *msg
+			clone.Metadata = make(map[string]interface{}, len(msg.Metadata))
+			for key, value := range msg.Metadata {
+				clone.Metadata[key] = value
+			}
+			all[i] = &clone
+		}
+
+		selected := messages[index]
+		if selected.Metadata == nil {
+			selected.Metadata = make(map[string]interface{})
+		}
+		selected.Metadata[ParallelResultsKey] = all
+		return selected
+	}
+}
+
+// fastestResult returns the index of the result with the lowest
+// CompletionRankKey, or 0 if none has one.
+func fastestResult(messages []*agenkit.Message) int {
+	best, bestRank := 0, -1
+	for i, msg := range messages {
+		rank, ok := msg.Metadata[CompletionRankKey].(int)
+		if ok && (bestRank < 0 || rank < bestRank) {
+			best, bestRank = i, rank
+		}
+	}
+	return best
+}
+
+// setCompletionRank records rank under CompletionRankKey on result.
+func setCompletionRank(result *agenkit.Message, rank int) {
+	if result == nil {
+		return
+	}
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	result.Metadata[CompletionRankKey] = rank
 }
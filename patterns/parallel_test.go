@@ -2,8 +2,10 @@ package patterns
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -203,6 +205,37 @@ func TestParallelAgent_PartialFailure(t *testing.T) {
 	if errors[0]["agent"] != "agent2" {
 		t.Errorf("expected error from agent2, got %v", errors[0]["agent"])
 	}
+
+	// Typed failures
+	failures := GetPartialFailures(result)
+	if len(failures) != 1 || failures[0].AgentName != "agent2" || failures[0].Err != agent2.err {
+		t.Errorf("unexpected partial failures %+v", failures)
+	}
+	if result.Metadata["successful_agents"] != 2 || result.Metadata["failed_agents"] != 1 {
+		t.Errorf("expected 2 successful and 1 failed agent, got %v and %v",
+			result.Metadata["successful_agents"], result.Metadata["failed_agents"])
+	}
+}
+
+func TestGetPartialFailures_None(t *testing.T) {
+	if failures := GetPartialFailures(nil); failures != nil {
+		t.Errorf("expected nil for nil message, got %v", failures)
+	}
+
+	parallel, err := NewParallelAgent([]agenkit.Agent{&extendedMockAgent{name: "a", response: "ok"}}, DefaultAggregators.First)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := parallel.Process(context.Background(), agenkit.NewMessage("user", "test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if failures := GetPartialFailures(result); failures != nil {
+		t.Errorf("expected no failures, got %v", failures)
+	}
+	if result.Metadata["failed_agents"] != 0 {
+		t.Errorf("expected 0 failed agents, got %v", result.Metadata["failed_agents"])
+	}
 }
 
 // TestParallelAgent_AllAgentsFail tests when all agents fail
@@ -231,6 +264,90 @@ func TestParallelAgent_AllAgentsFail(t *testing.T) {
 	}
 }
 
+// TestParallelAgent_AllAgentsFailError tests the typed all-fail error
+func TestParallelAgent_AllAgentsFailError(t *testing.T) {
+	cause := errors.New("error2")
+	agent1 := &extendedMockAgent{name: "agent1", err: errors.New("error1")}
+	agent2 := &extendedMockAgent{name: "agent2", err: cause}
+
+	called := false
+	aggregator := func(messages []*agenkit.Message) *agenkit.Message {
+		called = true
+		return messages[0]
+	}
+
+	parallel, err := NewParallelAgent([]agenkit.Agent{agent1, agent2}, aggregator)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = parallel.Process(context.Background(), agenkit.NewMessage("user", "test"))
+	if !errors.Is(err, ErrAllAgentsFailed) {
+		t.Fatalf("expected ErrAllAgentsFailed, got %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected per-agent error to be wrapped")
+	}
+	if called {
+		t.Error("aggregator should not be called when all agents fail")
+	}
+
+	var allFailed *AllAgentsFailedError
+	if !errors.As(err, &allFailed) {
+		t.Fatalf("expected *AllAgentsFailedError, got %T", err)
+	}
+	if len(allFailed.Agents) != 2 || allFailed.Agents[0] != "agent1" || allFailed.Agents[1] != "agent2" {
+		t.Errorf("expected failures in agent order, got %v", allFailed.Agents)
+	}
+	if err.Error() != "all agents failed: agent1: error1; agent2: error2" {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+// TestParallelAgent_AggregateOnAllFailed tests opting in to empty aggregation
+func TestParallelAgent_AggregateOnAllFailed(t *testing.T) {
+	agent1 := &extendedMockAgent{name: "agent1", err: errors.New("error1")}
+	agent2 := &extendedMockAgent{name: "agent2", err: errors.New("error2")}
+
+	aggregator := func(messages []*agenkit.Message) *agenkit.Message {
+		if len(messages) == 0 {
+			return agenkit.NewMessage("assistant", "Sorry, no analysis is available right now")
+		}
+		return messages[0]
+	}
+
+	parallel, err := NewParallelAgent([]agenkit.Agent{agent1, agent2}, aggregator)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parallel.WithAggregateOnAllFailed(true)
+
+	result, err := parallel.Process(context.Background(), agenkit.NewMessage("user", "test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "Sorry, no analysis is available right now" {
+		t.Errorf("unexpected content: %s", result.ContentString())
+	}
+	if result.Metadata["successful_agents"] != 0 {
+		t.Errorf("expected 0 successful agents, got %v", result.Metadata["successful_agents"])
+	}
+	if failures := GetPartialFailures(result); len(failures) != 2 {
+		t.Errorf("expected 2 recorded failures, got %v", failures)
+	}
+
+	// A nil result from the aggregator still reports the failure
+	nilAggregator, err := NewParallelAgent([]agenkit.Agent{agent1},
+		func(messages []*agenkit.Message) *agenkit.Message { return nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nilAggregator.WithAggregateOnAllFailed(true)
+	if _, err := nilAggregator.Process(context.Background(), agenkit.NewMessage("user", "test")); !errors.Is(err, ErrAllAgentsFailed) {
+		t.Errorf("expected ErrAllAgentsFailed, got %v", err)
+	}
+}
+
 // TestParallelAgent_NilMessage tests nil message handling
 func TestParallelAgent_NilMessage(t *testing.T) {
 	agent := &extendedMockAgent{name: "agent1", response: "test"}
@@ -352,6 +469,55 @@ func TestParallelAgent_DefaultAggregatorMajorityVote(t *testing.T) {
 	}
 }
 
+// TestParallelAgent_MajorityVoteTieBreak locks tie-breaking to agent order
+func TestParallelAgent_MajorityVoteTieBreak(t *testing.T) {
+	// delayed returns response after d, so completion order differs from agent order
+	delayed := func(name, response string, d time.Duration) *extendedMockAgent {
+		return &extendedMockAgent{name: name, processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			time.Sleep(d)
+			return agenkit.NewMessage("assistant", response), nil
+		}}
+	}
+
+	// 2 ham / 2 spam / 1 neutral: "ham" appears first by agent index but
+	// its agents finish last
+	agents := []agenkit.Agent{
+		delayed("agent1", "ham", 40*time.Millisecond),
+		delayed("agent2", "spam", 0),
+		delayed("agent3", "neutral", 0),
+		delayed("agent4", "spam", 10*time.Millisecond),
+		delayed("agent5", "ham", 30*time.Millisecond),
+	}
+
+	parallel, err := NewParallelAgent(agents, DefaultAggregators.MajorityVote)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for run := 0; run < 5; run++ {
+		result, err := parallel.Process(context.Background(), agenkit.NewMessage("user", "classify"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if result.ContentString() != "ham" {
+			t.Errorf("run %d: expected tie to go to 'ham' (first by agent index), got '%s'", run, result.ContentString())
+		}
+		if result.Metadata["votes"] != 2 {
+			t.Errorf("run %d: expected votes=2, got %v", run, result.Metadata["votes"])
+		}
+
+		tally, ok := result.Metadata["vote_tally"].(map[string]int)
+		if !ok {
+			t.Fatalf("run %d: expected vote_tally map, got %T", run, result.Metadata["vote_tally"])
+		}
+		want := map[string]int{"ham": 2, "spam": 2, "neutral": 1}
+		if !reflect.DeepEqual(tally, want) {
+			t.Errorf("run %d: expected tally %v, got %v", run, want, tally)
+		}
+	}
+}
+
 // TestParallelAgent_CustomAggregator tests custom aggregator function
 func TestParallelAgent_CustomAggregator(t *testing.T) {
 	agent1 := &extendedMockAgent{name: "agent1", response: "10"}
@@ -385,3 +551,93 @@ func TestParallelAgent_CustomAggregator(t *testing.T) {
 		t.Errorf("expected '60', got '%s'", result.ContentString())
 	}
 }
+
+// delayedAgent responds with its name after delay
+func delayedAgent(name string, delay time.Duration) *extendedMockAgent {
+	return &extendedMockAgent{
+		name: name,
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			time.Sleep(delay)
+			return agenkit.NewMessage("assistant", name), nil
+		},
+	}
+}
+
+func TestDefaultAggregators_FirstAttachesParallelResults(t *testing.T) {
+	parallel, _ := NewParallelAgent([]agenkit.Agent{
+		&extendedMockAgent{name: "a", response: "ra"},
+		&extendedMockAgent{name: "b", response: "rb"},
+	}, DefaultAggregators.First)
+
+	result, err := parallel.Process(context.Background(), agenkit.NewMessage("user", "go"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "ra" {
+		t.Errorf("expected first result in agent order, got %q", result.ContentString())
+	}
+	all, ok := result.Metadata[ParallelResultsKey].([]*agenkit.Message)
+	if !ok || len(all) != 2 || all[1].ContentString() != "rb" {
+		t.Fatalf("expected both results under %s, got %v", ParallelResultsKey, result.Metadata[ParallelResultsKey])
+	}
+	if _, nested := all[0].Metadata[ParallelResultsKey]; nested {
+		t.Error("copies should not contain the parallel results themselves")
+	}
+	if _, err := json.Marshal(result); err != nil {
+		t.Errorf("selected result should serialize: %v", err)
+	}
+}
+
+func TestDefaultAggregators_FastestSuccessful(t *testing.T) {
+	parallel, _ := NewParallelAgent([]agenkit.Agent{
+		delayedAgent("slow", 50*time.Millisecond),
+		delayedAgent("fast", 0),
+		&extendedMockAgent{name: "broken", err: errors.New("down")},
+	}, DefaultAggregators.FastestSuccessful)
+
+	result, err := parallel.Process(context.Background(), agenkit.NewMessage("user", "go"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "fast" {
+		t.Errorf("expected fastest successful result, got %q", result.ContentString())
+	}
+	if all := result.Metadata[ParallelResultsKey].([]*agenkit.Message); len(all) != 2 {
+		t.Errorf("expected 2 successful results attached, got %d", len(all))
+	}
+}
+
+func TestDefaultAggregators_Select(t *testing.T) {
+	longest := DefaultAggregators.Select(func(messages []*agenkit.Message) int {
+		best := 0
+		for i, msg := range messages {
+			if len(msg.ContentString()) > len(messages[best].ContentString()) {
+				best = i
+			}
+		}
+		return best
+	})
+	pattern, err := NewParallelPattern([]agenkit.Agent{
+		&extendedMockAgent{name: "a", response: "short"},
+		&extendedMockAgent{name: "b", response: "much longer"},
+	}, longest, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := pattern.Process(context.Background(), agenkit.NewMessage("user", "go"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "much longer" {
+		t.Errorf("expected selected result, got %q", result.ContentString())
+	}
+	if _, ok := result.Metadata[CompletionRankKey].(int); !ok {
+		t.Error("expected completion rank on the result")
+	}
+
+	outOfRange := DefaultAggregators.Select(func([]*agenkit.Message) int { return 5 })
+	if got := outOfRange([]*agenkit.Message{agenkit.NewMessage("assistant", "only")}); got.ContentString() != "only" {
+		t.Errorf("expected out-of-range index to select the first result, got %q", got.ContentString())
+	}
+}
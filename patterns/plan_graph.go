@@ -0,0 +1,135 @@
+package patterns
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GraphNode is a node in a dependency graph rendered by RenderDOT and
+// RenderMermaid.
+//
+// Patterns that execute a DAG of work (such as Planning) convert their steps
+// into GraphNodes so that they all share the same renderer and colour scheme.
+type GraphNode struct {
+	// ID is the node's index within the graph
+	ID int
+	// Label is the human-readable node text
+	Label string
+	// Status controls the node colour
+	Status StepStatus
+	// Dependencies are IDs of nodes that must complete before this one
+	Dependencies []int
+}
+
+// graphNodeColors maps step status to fill colours shared by both renderers.
+var graphNodeColors = map[StepStatus]string{
+	StepStatusPending:    "#e0e0e0",
+	StepStatusInProgress: "#fff3b0",
+	StepStatusCompleted:  "#b7e4c7",
+	StepStatusFailed:     "#f4a5a5",
+	StepStatusSkipped:    "#cfd8dc",
+}
+
+// graphNodeColor returns the fill colour for a status.
+func graphNodeColor(status StepStatus) string {
+	if color, ok := graphNodeColors[status]; ok {
+		return color
+	}
+	return graphNodeColors[StepStatusPending]
+}
+
+// RenderDOT renders nodes as a Graphviz DOT digraph.
+func RenderDOT(title string, nodes []GraphNode) string {
+	var b strings.Builder
+
+	b.WriteString("digraph plan {\n")
+	b.WriteString("  rankdir=TB;\n")
+	if title != "" {
+		fmt.Fprintf(&b, "  label=%q;\n", title)
+		b.WriteString("  labelloc=t;\n")
+	}
+	b.WriteString("  node [shape=box, style=\"rounded,filled\"];\n")
+
+	for _, node := range nodes {
+		label := fmt.Sprintf("%d. %s\n[%s]", node.ID+1, node.Label, node.Status)
+		fmt.Fprintf(&b, "  step%d [label=%q, fillcolor=%q];\n", node.ID, label, graphNodeColor(node.Status))
+	}
+
+	for _, node := range nodes {
+		for _, dep := range node.Dependencies {
+			fmt.Fprintf(&b, "  step%d -> step%d;\n", dep, node.ID)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// RenderMermaid renders nodes as a Mermaid flowchart.
+func RenderMermaid(title string, nodes []GraphNode) string {
+	var b strings.Builder
+
+	if title != "" {
+		fmt.Fprintf(&b, "---\ntitle: %s\n---\n", mermaidEscape(title))
+	}
+	b.WriteString("flowchart TD\n")
+
+	for _, node := range nodes {
+		fmt.Fprintf(&b, "  step%d[\"%d. %s<br/>[%s]\"]:::%s\n",
+			node.ID, node.ID+1, mermaidEscape(node.Label), node.Status, mermaidClass(node.Status))
+	}
+
+	for _, node := range nodes {
+		for _, dep := range node.Dependencies {
+			fmt.Fprintf(&b, "  step%d --> step%d\n", dep, node.ID)
+		}
+	}
+
+	for _, status := range []StepStatus{StepStatusPending, StepStatusInProgress, StepStatusCompleted, StepStatusFailed, StepStatusSkipped} {
+		fmt.Fprintf(&b, "  classDef %s fill:%s\n", mermaidClass(status), graphNodeColor(status))
+	}
+
+	return b.String()
+}
+
+// mermaidClass returns the Mermaid class name for a status.
+func mermaidClass(status StepStatus) string {
+	if _, ok := graphNodeColors[status]; !ok {
+		status = StepStatusPending
+	}
+	return strings.ReplaceAll(string(status), "_", "")
+}
+
+// mermaidEscape makes text safe for use inside a quoted Mermaid label.
+func mermaidEscape(text string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(text)
+}
+
+// GraphNodes converts the plan's steps into dependency graph nodes.
+func (p Plan) GraphNodes() []GraphNode {
+	nodes := make([]GraphNode, 0, len(p.Steps))
+	for i, step := range p.Steps {
+		nodes = append(nodes, GraphNode{
+			ID:           i,
+			Label:        step.Description,
+			Status:       step.Status,
+			Dependencies: step.Dependencies,
+		})
+	}
+	return nodes
+}
+
+// ToDOT renders the plan as a Graphviz DOT digraph coloured by step status.
+//
+// Example:
+//
+//	os.WriteFile("plan.dot", []byte(plan.ToDOT()), 0644)
+//	// dot -Tpng plan.dot -o plan.png
+func (p Plan) ToDOT() string {
+	return RenderDOT(p.Goal, p.GraphNodes())
+}
+
+// ToMermaid renders the plan as a Mermaid flowchart coloured by step status.
+func (p Plan) ToMermaid() string {
+	return RenderMermaid(p.Goal, p.GraphNodes())
+}
@@ -0,0 +1,70 @@
+package patterns
+
+import (
+	"strings"
+	"testing"
+)
+
+func graphTestPlan() Plan {
+	steps := []PlanStep{
+		CreatePlanStep("Choose venue", 0, nil),
+		CreatePlanStep("Send \"save the date\"", 1, []int{0}),
+		CreatePlanStep("Arrange catering", 2, []int{0}),
+	}
+	steps[0].Status = StepStatusCompleted
+	steps[1].Status = StepStatusFailed
+	steps[2].Status = StepStatusInProgress
+	return CreatePlan("Organize event", steps)
+}
+
+func TestPlan_ToDOT(t *testing.T) {
+	dot := graphTestPlan().ToDOT()
+
+	if !strings.HasPrefix(dot, "digraph plan {") {
+		t.Errorf("expected DOT digraph header, got: %s", dot)
+	}
+	for _, want := range []string{
+		`label="Organize event"`,
+		"step0 -> step1;",
+		"step0 -> step2;",
+		`fillcolor="#b7e4c7"`,
+		`fillcolor="#f4a5a5"`,
+		`fillcolor="#fff3b0"`,
+		`\"save the date\"`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("expected DOT output to contain %q, got:\n%s", want, dot)
+		}
+	}
+}
+
+func TestPlan_ToMermaid(t *testing.T) {
+	mermaid := graphTestPlan().ToMermaid()
+
+	for _, want := range []string{
+		"title: Organize event",
+		"flowchart TD",
+		"step0 --> step1",
+		"step0 --> step2",
+		":::completed",
+		":::failed",
+		":::inprogress",
+		"classDef skipped fill:#cfd8dc",
+		"#quot;save the date#quot;",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("expected Mermaid output to contain %q, got:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestRenderDOT_EmptyGraph(t *testing.T) {
+	dot := RenderDOT("", nil)
+
+	if strings.Contains(dot, "label=") {
+		t.Errorf("expected no graph label for empty title, got: %s", dot)
+	}
+	if !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("expected closed digraph, got: %s", dot)
+	}
+}
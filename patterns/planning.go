@@ -35,6 +35,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
@@ -70,6 +71,10 @@ type PlanStep struct {
 	Error string
 	// StepNumber is the position in the plan (0-indexed)
 	StepNumber int
+	// Partial indicates the step completed with caveats
+	Partial bool
+	// Warnings reported by the executor for a partially successful step
+	Warnings []string
 	// Metadata contains additional step metadata
 	Metadata map[string]interface{}
 	// Timestamp when the step was created
@@ -118,6 +123,27 @@ type Plan struct {
 	CreatedAt time.Time
 	// Metadata contains additional plan metadata
 	Metadata map[string]interface{}
+
+	// mu guards step status while a PlanningAgent executes the plan. It
+	// is a pointer so copies of the plan share it.
+	mu *sync.RWMutex
+}
+
+// Progress returns the plan's completion percentage. It is safe to call
+// from a monitoring goroutine while a PlanningAgent executes the plan.
+func (p *Plan) Progress() float64 {
+	if p.mu != nil {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+	}
+	return GetPlanProgress(*p)
+}
+
+// ensureLock gives a plan built without CreatePlan its lock.
+func (p *Plan) ensureLock() {
+	if p.mu == nil {
+		p.mu = &sync.RWMutex{}
+	}
 }
 
 // CreatePlan creates a new plan.
@@ -130,6 +156,7 @@ func CreatePlan(goal string, steps []PlanStep) Plan {
 		Goal:      goal,
 		Steps:     steps,
 		CreatedAt: time.Now(),
+		mu:        &sync.RWMutex{},
 	}
 }
 
@@ -191,11 +218,47 @@ func GetPlanProgress(plan Plan) float64 {
 }
 
 // StepExecutor is the protocol for executing individual plan steps.
+//
+// Execute may return a StepResult (or *StepResult) to report partial
+// success; any other value is treated as the output of a fully successful step.
 type StepExecutor interface {
 	// Execute executes a plan step
 	Execute(ctx context.Context, step PlanStep, context map[string]interface{}) (interface{}, error)
 }
 
+// StepResult is the structured outcome of executing a plan step.
+//
+// A step that "mostly worked" should return a StepResult with Partial set and
+// the caveats listed in Warnings rather than an error. The PlanningAgent marks
+// such steps as completed, lets dependent steps run, and surfaces the warnings
+// in the plan metadata and final summary.
+type StepResult struct {
+	// Output is the step's result value
+	Output interface{}
+	// Warnings describes anything that did not fully succeed
+	Warnings []string
+	// Partial indicates the step completed with caveats
+	Partial bool
+}
+
+// NormalizeStepResult adapts a raw executor output into a StepResult.
+//
+// StepResult and *StepResult values are returned as-is; any other value
+// becomes the Output of a fully successful result.
+func NormalizeStepResult(output interface{}) StepResult {
+	switch v := output.(type) {
+	case StepResult:
+		return v
+	case *StepResult:
+		if v == nil {
+			return StepResult{}
+		}
+		return *v
+	default:
+		return StepResult{Output: output}
+	}
+}
+
 // DefaultStepExecutor is a default step executor that returns mock results.
 type DefaultStepExecutor struct{}
 
@@ -205,6 +268,10 @@ func (d *DefaultStepExecutor) Execute(ctx context.Context, step PlanStep, contex
 	return fmt.Sprintf("Completed: %s", step.Description), nil
 }
 
+// StepCompleteFunc is called after each plan step finishes, successfully
+// or not, with a snapshot of the step and the plan's progress percentage.
+type StepCompleteFunc func(step *PlanStep, progress float64)
+
 // PlanningAgentConfig configures a PlanningAgent.
 type PlanningAgentConfig struct {
 	// MaxSteps is the maximum steps in a plan
@@ -213,6 +280,9 @@ type PlanningAgentConfig struct {
 	AllowReplanning bool
 	// SystemPrompt is an optional system prompt
 	SystemPrompt string
+	// SystemPromptTemplate renders the system prompt when SystemPrompt is
+	// empty (default: DefaultPlanningPrompt). Data: PlanningPromptData.
+	SystemPromptTemplate *agenkit.PromptTemplate
 }
 
 // PlanningAgent creates and executes plans for complex tasks.
@@ -244,7 +314,13 @@ type PlanningAgent struct {
 	maxSteps        int
 	allowReplanning bool
 	systemPrompt    string
-	currentPlan     *Plan
+	systemTemplate  *agenkit.PromptTemplate
+	onStepComplete  StepCompleteFunc
+
+	mu          sync.Mutex
+	currentPlan *Plan
+
+	lazy lazyInit
 }
 
 // NewPlanningAgent creates a new planning agent.
@@ -267,8 +343,10 @@ func NewPlanningAgent(llmClient LLMClient, stepExecutor StepExecutor, config *Pl
 
 	if config.SystemPrompt != "" {
 		agent.systemPrompt = config.SystemPrompt
+	} else if config.SystemPromptTemplate != nil {
+		agent.systemTemplate = config.SystemPromptTemplate
 	} else {
-		agent.systemPrompt = agent.defaultSystemPrompt()
+		agent.systemTemplate = DefaultPlanningPrompt
 	}
 
 	if agent.executor == nil {
@@ -278,25 +356,13 @@ func NewPlanningAgent(llmClient LLMClient, stepExecutor StepExecutor, config *Pl
 	return agent
 }
 
-func (p *PlanningAgent) defaultSystemPrompt() string {
-	return fmt.Sprintf(`You are a planning agent that breaks down complex tasks into steps.
-
-For each task, create a plan with specific, actionable steps.
-
-Format your plan as:
-Goal: [overall goal]
-Steps:
-1. [first step]
-2. [second step]
-...
-
-Maximum %d steps.
-
-Guidelines:
-- Make steps concrete and actionable
-- Consider dependencies between steps
-- Keep steps focused and achievable
-- Include verification steps when appropriate`, p.maxSteps)
+// resolveSystemPrompt returns the fixed system prompt, or renders the
+// system prompt template.
+func (p *PlanningAgent) resolveSystemPrompt() (string, error) {
+	if p.systemTemplate == nil {
+		return p.systemPrompt, nil
+	}
+	return p.systemTemplate.Render(PlanningPromptData{MaxSteps: p.maxSteps})
 }
 
 // Name returns the agent name.
@@ -309,15 +375,28 @@ func (p *PlanningAgent) Capabilities() []string {
 	return []string{"planning", "task_decomposition", "step_execution"}
 }
 
-// Process processes a task by creating and executing a plan.
-func (p *PlanningAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+// Process processes a task by creating and executing a plan. The shared
+// StopReason is recorded under StopReasonKey.
+func (p *PlanningAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := p.Init(ctx); err != nil {
+		return nil, err
+	}
+
 	// Create plan
 	plan, err := p.createPlan(ctx, message.ContentString())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create plan: %w", err)
 	}
 
+	plan.ensureLock()
+	p.mu.Lock()
 	p.currentPlan = &plan
+	p.mu.Unlock()
 
 	// Execute plan
 	result, err := p.executePlan(ctx, &plan)
@@ -332,17 +411,42 @@ func (p *PlanningAgent) Process(ctx context.Context, message *agenkit.Message) (
 		}
 	}
 
-	return &agenkit.Message{
-		Role:    "assistant",
-		Content: fmt.Sprintf("Task completed.\n\nGoal: %s\n\nSteps completed: %d/%d\n\nResult: %s", plan.Goal, completed, len(plan.Steps), result),
-	}, nil
+	response := &agenkit.Message{
+		Role:     agenkit.RoleAssistant,
+		Content:  fmt.Sprintf("Task completed.\n\nGoal: %s\n\nSteps completed: %d/%d\n\nResult: %s", plan.Goal, completed, len(plan.Steps), result),
+		Metadata: map[string]interface{}{},
+	}
+	if warnings, ok := plan.Metadata["warnings"].([]string); ok && len(warnings) > 0 {
+		response.Metadata["warnings"] = warnings
+	}
+	response.Metadata[StopReasonKey] = planStopReason(plan)
+
+	return response, nil
+}
+
+// planStopReason classifies why plan execution stopped: every step done,
+// a failed step that could not be replanned, or steps left blocked.
+func planStopReason(plan Plan) StopReason {
+	switch {
+	case IsPlanComplete(plan):
+		return StopCompleted
+	case HasPlanFailures(plan):
+		return StopError
+	default:
+		return StopStopped
+	}
 }
 
 func (p *PlanningAgent) createPlan(ctx context.Context, task string) (Plan, error) {
+	systemPrompt, err := p.resolveSystemPrompt()
+	if err != nil {
+		return Plan{}, err
+	}
+
 	// Ask LLM to create a plan
 	messages := []*agenkit.Message{
-		{Role: "system", Content: p.systemPrompt},
-		{Role: "user", Content: fmt.Sprintf("Create a plan for: %s", task)},
+		{Role: agenkit.RoleSystem, Content: systemPrompt},
+		{Role: agenkit.RoleUser, Content: fmt.Sprintf("Create a plan for: %s", task)},
 	}
 
 	response, err := p.llm.Chat(ctx, messages)
@@ -413,6 +517,7 @@ func (p *PlanningAgent) parsePlan(planText string, goal string) Plan {
 func (p *PlanningAgent) executePlan(ctx context.Context, plan *Plan) (string, error) {
 	context := make(map[string]interface{})
 	results := []string{}
+	warnings := []string{}
 
 	for !IsPlanComplete(*plan) {
 		// Get next executable steps
@@ -435,20 +540,49 @@ func (p *PlanningAgent) executePlan(ctx context.Context, plan *Plan) (string, er
 			// Find the step in the plan and update its status
 			for i := range plan.Steps {
 				if plan.Steps[i].StepNumber == step.StepNumber {
+					plan.mu.Lock()
 					plan.Steps[i].Status = StepStatusInProgress
+					plan.mu.Unlock()
+
+					output, err := p.executor.Execute(ctx, step, context)
+					var result StepResult
+					if err == nil {
+						result = NormalizeStepResult(output)
+					}
 
-					result, err := p.executor.Execute(ctx, step, context)
+					plan.mu.Lock()
 					if err != nil {
 						plan.Steps[i].Error = err.Error()
 						plan.Steps[i].Status = StepStatusFailed
-						results = append(results, fmt.Sprintf("Step %d: %s ✗ (%s)", step.StepNumber+1, step.Description, err.Error()))
 					} else {
-						plan.Steps[i].Result = result
+						plan.Steps[i].Result = result.Output
 						plan.Steps[i].Status = StepStatusCompleted
+						plan.Steps[i].Partial = result.Partial
+						plan.Steps[i].Warnings = result.Warnings
+					}
+					snapshot := plan.Steps[i]
+					progress := GetPlanProgress(*plan)
+					plan.mu.Unlock()
+
+					// Report outside the lock so the callback may read progress
+					if p.onStepComplete != nil {
+						p.onStepComplete(&snapshot, progress)
+					}
 
+					if err != nil {
+						results = append(results, fmt.Sprintf("Step %d: %s ✗ (%s)", step.StepNumber+1, step.Description, err.Error()))
+					} else {
 						// Add result to context for future steps
-						context[fmt.Sprintf("step_%d_result", step.StepNumber)] = result
-						results = append(results, fmt.Sprintf("Step %d: %s ✓", step.StepNumber+1, step.Description))
+						context[fmt.Sprintf("step_%d_result", step.StepNumber)] = result.Output
+
+						if result.Partial {
+							results = append(results, fmt.Sprintf("Step %d: %s ✓ (partial)", step.StepNumber+1, step.Description))
+						} else {
+							results = append(results, fmt.Sprintf("Step %d: %s ✓", step.StepNumber+1, step.Description))
+						}
+						for _, warning := range result.Warnings {
+							warnings = append(warnings, fmt.Sprintf("Step %d: %s", step.StepNumber+1, warning))
+						}
 					}
 					break
 				}
@@ -456,9 +590,20 @@ func (p *PlanningAgent) executePlan(ctx context.Context, plan *Plan) (string, er
 		}
 	}
 
+	if len(warnings) > 0 {
+		if plan.Metadata == nil {
+			plan.Metadata = make(map[string]interface{})
+		}
+		plan.Metadata["warnings"] = warnings
+	}
+
 	// Generate summary
 	summary := strings.Join(results, "\n")
 
+	if len(warnings) > 0 {
+		summary += "\n\nWarnings:\n- " + strings.Join(warnings, "\n- ")
+	}
+
 	if IsPlanComplete(*plan) {
 		summary += fmt.Sprintf("\n\nPlan completed successfully (%.0f%%)", GetPlanProgress(*plan))
 	} else if HasPlanFailures(*plan) {
@@ -489,17 +634,24 @@ func (p *PlanningAgent) replan(ctx context.Context, failedPlan *Plan) error {
 		failedDescriptions = append(failedDescriptions, fmt.Sprintf("- %s (Error: %s)", step.Description, step.Error))
 	}
 
+	systemPrompt, err := p.resolveSystemPrompt()
+	if err != nil {
+		return err
+	}
+
 	messages := []*agenkit.Message{
-		{Role: "system", Content: p.systemPrompt},
-		{Role: "user", Content: fmt.Sprintf("The following steps failed:\n%s\n\nCreate alternative steps to accomplish the goal: %s", strings.Join(failedDescriptions, "\n"), failedPlan.Goal)},
+		{Role: agenkit.RoleSystem, Content: systemPrompt},
+		{Role: agenkit.RoleUser, Content: fmt.Sprintf("The following steps failed:\n%s\n\nCreate alternative steps to accomplish the goal: %s", strings.Join(failedDescriptions, "\n"), failedPlan.Goal)},
 	}
 
-	_, err := p.llm.Chat(ctx, messages)
+	_, err = p.llm.Chat(ctx, messages)
 	if err != nil {
 		return fmt.Errorf("replanning LLM call failed: %w", err)
 	}
 
 	// For simplicity, mark failed steps as skipped
+	failedPlan.mu.Lock()
+	defer failedPlan.mu.Unlock()
 	for i := range failedPlan.Steps {
 		if failedPlan.Steps[i].Status == StepStatusFailed {
 			failedPlan.Steps[i].Status = StepStatusSkipped
@@ -509,15 +661,27 @@ func (p *PlanningAgent) replan(ctx context.Context, failedPlan *Plan) error {
 	return nil
 }
 
-// GetPlan returns the current plan.
+// OnStepComplete sets a callback invoked after each plan step finishes,
+// for live progress reporting. It is called on the executing goroutine,
+// outside any lock, so it may call GetProgress or Plan.Progress. Set it
+// before calling Process.
+func (p *PlanningAgent) OnStepComplete(callback StepCompleteFunc) {
+	p.onStepComplete = callback
+}
+
+// GetPlan returns the current plan. While the plan is executing, read its
+// progress with Plan.Progress rather than its steps.
 func (p *PlanningAgent) GetPlan() *Plan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	return p.currentPlan
 }
 
-// GetProgress returns current plan progress as a percentage.
+// GetProgress returns current plan progress as a percentage. It is safe
+// to call while Process is executing the plan.
 func (p *PlanningAgent) GetProgress() float64 {
-	if p.currentPlan != nil {
-		return GetPlanProgress(*p.currentPlan)
+	if plan := p.GetPlan(); plan != nil {
+		return plan.Progress()
 	}
 	return 0
 }
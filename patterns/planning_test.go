@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
@@ -533,6 +534,86 @@ Steps:
 	}
 }
 
+// partialStepExecutor reports the configured step as partially successful.
+type partialStepExecutor struct {
+	partialOnStep int
+}
+
+func (m *partialStepExecutor) Execute(ctx context.Context, step PlanStep, context map[string]interface{}) (interface{}, error) {
+	if step.StepNumber == m.partialOnStep {
+		return StepResult{
+			Output:   "half done",
+			Warnings: []string{"2 of 5 records skipped"},
+			Partial:  true,
+		}, nil
+	}
+	return fmt.Sprintf("Completed: %s", step.Description), nil
+}
+
+func TestNormalizeStepResult(t *testing.T) {
+	plain := NormalizeStepResult("output")
+	if plain.Output != "output" || plain.Partial || len(plain.Warnings) != 0 {
+		t.Errorf("expected plain output to be wrapped as full success, got %+v", plain)
+	}
+
+	ptr := NormalizeStepResult(&StepResult{Output: 1, Partial: true})
+	if ptr.Output != 1 || !ptr.Partial {
+		t.Errorf("expected *StepResult to be unwrapped, got %+v", ptr)
+	}
+
+	var nilResult *StepResult
+	if empty := NormalizeStepResult(nilResult); empty.Output != nil || empty.Partial {
+		t.Errorf("expected nil *StepResult to yield empty result, got %+v", empty)
+	}
+}
+
+func TestPlanningAgent_Process_WithPartialStep(t *testing.T) {
+	llm := &planningMockLLMClient{
+		response: `Goal: Test
+Steps:
+1. Step 1
+2. Step 2
+3. Step 3`,
+	}
+
+	agent := NewPlanningAgent(llm, &partialStepExecutor{partialOnStep: 0}, nil)
+
+	result, err := agent.Process(context.Background(), &agenkit.Message{
+		Role:    "user",
+		Content: "Do task",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Partial steps count as completed, so dependents still run
+	if !strings.Contains(result.ContentString(), "Steps completed: 3/3") {
+		t.Errorf("expected 3/3 steps completed, got: %s", result.ContentString())
+	}
+	if !strings.Contains(result.ContentString(), "Step 1: 2 of 5 records skipped") {
+		t.Errorf("expected summary to surface warnings, got: %s", result.ContentString())
+	}
+
+	warnings, ok := result.Metadata["warnings"].([]string)
+	if !ok || len(warnings) != 1 {
+		t.Fatalf("expected 1 warning in response metadata, got %v", result.Metadata["warnings"])
+	}
+
+	plan := agent.GetPlan()
+	if !plan.Steps[0].Partial {
+		t.Error("expected step 0 to be marked partial")
+	}
+	if plan.Steps[0].Result != "half done" {
+		t.Errorf("expected step 0 result 'half done', got %v", plan.Steps[0].Result)
+	}
+	if plan.Steps[1].Partial {
+		t.Error("expected step 1 not to be marked partial")
+	}
+	if _, ok := plan.Metadata["warnings"].([]string); !ok {
+		t.Error("expected warnings in plan metadata")
+	}
+}
+
 func TestPlanningAgent_GetPlan(t *testing.T) {
 	llm := &planningMockLLMClient{
 		response: `Goal: Test
@@ -668,3 +749,78 @@ Steps:
 		}
 	}
 }
+
+// ============================================================================
+// Progress Reporting Tests
+// ============================================================================
+
+// slowStepExecutor sleeps briefly per step so progress can be observed.
+type slowStepExecutor struct{}
+
+func (s *slowStepExecutor) Execute(ctx context.Context, step PlanStep, context map[string]interface{}) (interface{}, error) {
+	time.Sleep(2 * time.Millisecond)
+	return "done", nil
+}
+
+func TestPlanningAgent_OnStepComplete(t *testing.T) {
+	llm := &planningMockLLMClient{response: "Goal: Test\nSteps:\n1. First\n2. Second\n3. Third\n4. Fourth"}
+	agent := NewPlanningAgent(llm, &mockStepExecutor{failOnStep: 2}, nil)
+
+	var steps []int
+	var progress []float64
+	agent.OnStepComplete(func(step *PlanStep, p float64) {
+		steps = append(steps, step.StepNumber)
+		progress = append(progress, p)
+		// The callback runs outside the plan lock
+		if agent.GetProgress() != p {
+			t.Errorf("expected GetProgress %v inside callback, got %v", p, agent.GetProgress())
+		}
+	})
+
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "task")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(steps) != 4 || steps[2] != 2 {
+		t.Fatalf("expected a callback per step, got %v", steps)
+	}
+	expected := []float64{25, 50, 50, 75}
+	for i, p := range progress {
+		if p != expected[i] {
+			t.Errorf("callback %d: expected progress %v, got %v", i, expected[i], p)
+		}
+	}
+}
+
+func TestPlanningAgent_ConcurrentProgressReads(t *testing.T) {
+	llm := &planningMockLLMClient{response: "Goal: Test\nSteps:\n1. A\n2. B\n3. C\n4. D\n5. E"}
+	agent := NewPlanningAgent(llm, &slowStepExecutor{}, nil)
+
+	done := make(chan struct{})
+	monitored := make(chan float64, 1)
+	go func() {
+		last := 0.0
+		for {
+			select {
+			case <-done:
+				monitored <- last
+				return
+			default:
+				if plan := agent.GetPlan(); plan != nil {
+					last = plan.Progress()
+				}
+			}
+		}
+	}()
+
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "task")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(done)
+	if last := <-monitored; last < 0 || last > 100 {
+		t.Errorf("unexpected monitored progress %v", last)
+	}
+	if agent.GetProgress() != 100 {
+		t.Errorf("expected 100%% progress, got %v", agent.GetProgress())
+	}
+}
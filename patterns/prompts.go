@@ -0,0 +1,122 @@
+package patterns
+
+import (
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// Default prompt templates for LLM-driven patterns.
+//
+// Each template is rendered with the data type documented next to it.
+// To tune a prompt for a specific model, start from the default's Text(),
+// parse a variant with agenkit.NewPromptTemplate and pass it through the
+// pattern's config:
+//
+//	tmpl, _ := agenkit.NewPromptTemplate("react-strict",
+//	    patterns.DefaultReActPrompt.Text()+"\nAlways cite the tool you used.")
+//	agent, _ := patterns.NewReActAgent(&patterns.ReActConfig{
+//	    Agent:    llm,
+//	    Tools:    tools,
+//	    Template: tmpl,
+//	})
+
+// ClassifierPromptData is the data passed to LLMClassifier templates.
+type ClassifierPromptData struct {
+	// Categories are the valid category names
+	Categories []string
+	// Message is the content to classify
+	Message string
+}
+
+// ReActPromptData is the data passed to ReAct templates.
+type ReActPromptData struct {
+	// Tools describes the available tools, in config order
+	Tools []PromptTool
+}
+
+// PromptTool describes a tool for prompt rendering.
+type PromptTool struct {
+	Name        string
+	Description string
+}
+
+// PlanningPromptData is the data passed to PlanningAgent system prompt templates.
+type PlanningPromptData struct {
+	// MaxSteps is the maximum number of steps in a plan
+	MaxSteps int
+}
+
+// SummaryPromptData is the data passed to SummarizingCompressor templates.
+type SummaryPromptData struct {
+	// PreviousSummary is the existing summary, empty on first compression
+	PreviousSummary string
+	// Transcript is the turns to summarize as "role: content" lines
+	Transcript string
+}
+
+// DefaultClassifierPrompt is the LLMClassifier prompt. Data: ClassifierPromptData.
+var DefaultClassifierPrompt = agenkit.MustPromptTemplate("classifier", `Classify the following message into one of these categories: {{join .Categories ", "}}
+
+Reply with ONLY the category name, nothing else.
+
+Message: {{.Message}}`)
+
+// DefaultReActPrompt is the ReActAgent instruction prompt. Data: ReActPromptData.
+var DefaultReActPrompt = agenkit.MustPromptTemplate("react", `You are a helpful assistant that can use tools to answer questions.
+
+Available tools:
+{{range $i, $tool := .Tools}}{{if $i}}
+{{end}}- {{$tool.Name}}: {{$tool.Description}}{{end}}
+
+Use the following format:
+
+Thought: Think about what to do next
+Action: [tool name]
+Action Input: [input for the tool]
+Observation: [result will be provided]
+
+... (repeat Thought/Action/Observation as needed)
+
+Thought: I now know the final answer
+Final Answer: [your final answer here]
+
+Begin!`)
+
+// DefaultPlanningPrompt is the PlanningAgent system prompt. Data: PlanningPromptData.
+var DefaultPlanningPrompt = agenkit.MustPromptTemplate("planning", `You are a planning agent that breaks down complex tasks into steps.
+
+For each task, create a plan with specific, actionable steps.
+
+Format your plan as:
+Goal: [overall goal]
+Steps:
+1. [first step]
+2. [second step]
+...
+
+Maximum {{.MaxSteps}} steps.
+
+Guidelines:
+- Make steps concrete and actionable
+- Consider dependencies between steps
+- Keep steps focused and achievable
+- Include verification steps when appropriate`)
+
+// DefaultSummaryPrompt is the SummarizingCompressor prompt. Data: SummaryPromptData.
+var DefaultSummaryPrompt = agenkit.MustPromptTemplate("summary", `Summarize the conversation below so it can replace the original messages.
+
+Preserve every salient fact: names, preferences, decisions, numbers, open questions and commitments. Write in the third person and be concise. Reply with ONLY the summary.
+{{if .PreviousSummary}}
+Summary of earlier conversation:
+{{.PreviousSummary}}
+{{end}}
+Conversation:
+{{.Transcript}}`)
+
+// promptTools converts tools to their prompt descriptions.
+func promptTools(tools []agenkit.Tool) []PromptTool {
+	described := make([]PromptTool, len(tools))
+	for i, tool := range tools {
+		described[i] = PromptTool{Name: tool.Name(), Description: tool.Description()}
+	}
+	return described
+}
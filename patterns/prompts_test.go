@@ -0,0 +1,149 @@
+package patterns
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestDefaultPrompts_Render(t *testing.T) {
+	classifier, err := DefaultClassifierPrompt.Render(ClassifierPromptData{
+		Categories: []string{"billing", "technical"},
+		Message:    "my card was declined",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(classifier, "categories: billing, technical") ||
+		!strings.HasSuffix(classifier, "Message: my card was declined") {
+		t.Errorf("unexpected classifier prompt: %q", classifier)
+	}
+
+	react, err := DefaultReActPrompt.Render(ReActPromptData{Tools: []PromptTool{
+		{Name: "search", Description: "Search the web"},
+		{Name: "calc", Description: "Do math"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(react, "Available tools:\n- search: Search the web\n- calc: Do math\n\nUse the following format:") {
+		t.Errorf("unexpected react prompt: %q", react)
+	}
+
+	planning, err := DefaultPlanningPrompt.Render(PlanningPromptData{MaxSteps: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(planning, "Maximum 4 steps.") {
+		t.Errorf("unexpected planning prompt: %q", planning)
+	}
+}
+
+func TestLLMClassifier_WithPromptTemplate(t *testing.T) {
+	var seen string
+	llm := &extendedMockAgent{
+		name: "llm",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			seen = msg.ContentString()
+			return agenkit.NewMessage("assistant", "sales"), nil
+		},
+	}
+
+	tmpl := agenkit.MustPromptTemplate("terse", `{{join .Categories "|"}} :: {{.Message}}`)
+	classifier := NewLLMClassifier(llm, []string{"billing", "sales"}).WithPromptTemplate(tmpl)
+
+	category, err := classifier.Classify(context.Background(), agenkit.NewMessage("user", "quote please"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if category != "sales" {
+		t.Errorf("expected sales, got %s", category)
+	}
+	if seen != "billing|sales :: quote please" {
+		t.Errorf("unexpected prompt: %q", seen)
+	}
+}
+
+func TestLLMClassifier_PromptTemplateRenderError(t *testing.T) {
+	llm := &extendedMockAgent{name: "llm", response: "sales"}
+	tmpl := agenkit.MustPromptTemplate("broken", `{{.Unknown}}`)
+	classifier := NewLLMClassifier(llm, []string{"sales"}).WithPromptTemplate(tmpl)
+
+	if _, err := classifier.Classify(context.Background(), agenkit.NewMessage("user", "x")); err == nil {
+		t.Fatal("expected render error")
+	}
+}
+
+func TestReActAgent_Template(t *testing.T) {
+	tmpl := agenkit.MustPromptTemplate("react-custom",
+		`Tools:{{range .Tools}} {{.Name}}{{end}}. Answer tersely.`)
+
+	agent, err := NewReActAgent(&ReActConfig{
+		Agent:    &mockReActAgent{name: "llm"},
+		Tools:    []agenkit.Tool{&mockTool{name: "search"}, &mockTool{name: "calc"}},
+		Template: tmpl,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if agent.promptTemplate != "Tools: search calc. Answer tersely." {
+		t.Errorf("unexpected prompt: %q", agent.promptTemplate)
+	}
+
+	// A fixed PromptTemplate string takes precedence
+	agent, err = NewReActAgent(&ReActConfig{
+		Agent:          &mockReActAgent{name: "llm"},
+		Tools:          []agenkit.Tool{&mockTool{name: "search"}},
+		PromptTemplate: "fixed",
+		Template:       tmpl,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if agent.promptTemplate != "fixed" {
+		t.Errorf("expected fixed prompt, got %q", agent.promptTemplate)
+	}
+
+	_, err = NewReActAgent(&ReActConfig{
+		Agent:    &mockReActAgent{name: "llm"},
+		Tools:    []agenkit.Tool{&mockTool{name: "search"}},
+		Template: agenkit.MustPromptTemplate("broken", `{{.Missing}}`),
+	})
+	if err == nil {
+		t.Fatal("expected render error")
+	}
+}
+
+// promptCapturingLLMClient records the system prompt of each chat.
+type promptCapturingLLMClient struct {
+	system []string
+}
+
+func (c *promptCapturingLLMClient) Chat(ctx context.Context, messages []*agenkit.Message) (*agenkit.Message, error) {
+	c.system = append(c.system, messages[0].ContentString())
+	return &agenkit.Message{Role: "assistant", Content: "Goal: Test\nSteps:\n1. Do something"}, nil
+}
+
+func TestPlanningAgent_SystemPromptTemplate(t *testing.T) {
+	llm := &promptCapturingLLMClient{}
+	agent := NewPlanningAgent(llm, nil, &PlanningAgentConfig{
+		MaxSteps:             3,
+		SystemPromptTemplate: agenkit.MustPromptTemplate("plan-short", `Plan in at most {{.MaxSteps}} steps.`),
+	})
+
+	if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", "ship it")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(llm.system) == 0 || llm.system[0] != "Plan in at most 3 steps." {
+		t.Errorf("unexpected system prompt: %v", llm.system)
+	}
+
+	broken := NewPlanningAgent(llm, nil, &PlanningAgentConfig{
+		SystemPromptTemplate: agenkit.MustPromptTemplate("broken", `{{.Missing}}`),
+	})
+	if _, err := broken.Process(context.Background(), agenkit.NewMessage("user", "ship it")); err == nil {
+		t.Fatal("expected render error")
+	}
+}
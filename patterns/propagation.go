@@ -0,0 +1,119 @@
+package patterns
+
+import "github.com/scttfrdmn/agenkit-go/agenkit"
+
+// CarryThroughKeys are the metadata keys composition patterns carry from
+// their sub-agents' results onto their own output, so scalars set by a leaf
+// agent survive wrapping. For example, a HumanInLoopAgent gating a
+// SequentialAgent sees the confidence set by any stage, not just the last.
+//
+// Append to it during initialization to carry custom keys.
+var CarryThroughKeys = []string{"confidence", "cost", "tokens"}
+
+// AdditiveKeys are the carry-through keys that measure spend. They are
+// summed rather than overridden, so a pipeline or fan-out reports the
+// total cost and tokens of all its sub-agents. Each agent should set them
+// to its own spend (plus that of agents it called), not copy them from its
+// input, or the input's spend is counted twice.
+//
+// Append to it during initialization to sum custom keys.
+var AdditiveKeys = []string{"cost", "tokens"}
+
+// PropagateMetadata copies the given metadata keys (default:
+// CarryThroughKeys) from one message to another, modifying to in place.
+// Additive keys (see AdditiveKeys) are summed with the value already on to;
+// for other keys the value already on to is left alone, so the value
+// nearest the output wins.
+//
+// Only call it on a message you own: patterns copy a sub-agent's result
+// before propagating onto it, since the agent may still hold it, e.g. in a
+// cache.
+//
+// Example:
+//
+//	summary := agenkit.NewMessage("assistant", summarize(result))
+//	patterns.PropagateMetadata(result, summary)
+func PropagateMetadata(from, to *agenkit.Message, keys ...string) {
+	if from == nil || to == nil || from == to {
+		return
+	}
+	if len(keys) == 0 {
+		keys = CarryThroughKeys
+	}
+	for _, key := range keys {
+		value, ok := from.Metadata[key]
+		if !ok {
+			continue
+		}
+		if to.Metadata == nil {
+			to.Metadata = make(map[string]interface{})
+		}
+		existing, set := to.Metadata[key]
+		if !set {
+			to.Metadata[key] = value
+			continue
+		}
+		if isAdditiveKey(key) {
+			if sum, ok := addNumbers(existing, value); ok {
+				to.Metadata[key] = sum
+			}
+		}
+	}
+}
+
+// propagateStage returns a copy of result carrying the metadata of input,
+// the previous stage's output, for sequential compositions.
+func propagateStage(input, result *agenkit.Message) *agenkit.Message {
+	result = copyMessage(result)
+	PropagateMetadata(input, result)
+	return result
+}
+
+// propagateBranches returns a copy of out carrying the metadata of the
+// branches it was aggregated from, for fan-out compositions. Additive keys
+// are set to the sum over the branches, replacing any value out took from
+// one of them, so no branch is counted twice.
+func propagateBranches(branches []*agenkit.Message, out *agenkit.Message) *agenkit.Message {
+	out = copyMessage(out)
+	for _, key := range AdditiveKeys {
+		for _, branch := range branches {
+			if _, ok := branch.Metadata[key]; ok {
+				delete(out.Metadata, key)
+				break
+			}
+		}
+	}
+	for _, branch := range branches {
+		PropagateMetadata(branch, out)
+	}
+	return out
+}
+
+// isAdditiveKey reports whether key is one of AdditiveKeys.
+func isAdditiveKey(key string) bool {
+	for _, additive := range AdditiveKeys {
+		if key == additive {
+			return true
+		}
+	}
+	return false
+}
+
+// addNumbers sums two numeric metadata values, keeping an int when both
+// are integers. Returns false if either isn't a number.
+func addNumbers(a, b interface{}) (interface{}, bool) {
+	ai, aInt := a.(int)
+	bi, bInt := b.(int)
+	if aInt && bInt {
+		return ai + bi, true
+	}
+	af, ok := numberValue(a)
+	if !ok {
+		return nil, false
+	}
+	bf, ok := numberValue(b)
+	if !ok {
+		return nil, false
+	}
+	return af + bf, true
+}
@@ -0,0 +1,177 @@
+package patterns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// scoringAgent responds with content and the given metadata.
+func scoringAgent(name, content string, metadata map[string]interface{}) *extendedMockAgent {
+	return &extendedMockAgent{name: name, processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		result := agenkit.NewMessage("assistant", content)
+		for key, value := range metadata {
+			result.Metadata[key] = value
+		}
+		return result, nil
+	}}
+}
+
+func TestPropagateMetadata(t *testing.T) {
+	from := agenkit.NewMessage("assistant", "a").
+		WithMetadata("confidence", 0.9).
+		WithMetadata("cost", 0.02).
+		WithMetadata("risk", "low")
+	to := &agenkit.Message{Role: "assistant", Content: "b"}
+
+	PropagateMetadata(from, to)
+	if to.Metadata["confidence"] != 0.9 || to.Metadata["cost"] != 0.02 {
+		t.Errorf("expected carry-through keys copied, got %v", to.Metadata)
+	}
+	if _, ok := to.Metadata["risk"]; ok {
+		t.Error("expected non carry-through key not copied")
+	}
+
+	to.Metadata["confidence"] = 0.4
+	PropagateMetadata(from, to, "confidence", "risk")
+	if to.Metadata["confidence"] != 0.4 {
+		t.Errorf("expected existing value kept, got %v", to.Metadata["confidence"])
+	}
+	if to.Metadata["risk"] != "low" {
+		t.Errorf("expected explicit key copied, got %v", to.Metadata["risk"])
+	}
+
+	PropagateMetadata(nil, to)
+	PropagateMetadata(from, nil)
+}
+
+func TestSequentialAgent_CarriesConfidenceThroughLaterStages(t *testing.T) {
+	pipeline, err := NewSequentialAgent([]agenkit.Agent{
+		scoringAgent("classifier", "fraud", map[string]interface{}{"confidence": 0.72}),
+		scoringAgent("formatter", "Verdict: fraud", nil),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := pipeline.Process(context.Background(), agenkit.NewMessage("user", "txn 42"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["confidence"] != 0.72 {
+		t.Errorf("expected confidence 0.72 on pipeline output, got %v", result.Metadata["confidence"])
+	}
+}
+
+func TestSequentialPattern_LaterStageConfidenceWins(t *testing.T) {
+	pipeline, err := NewSequentialPattern([]agenkit.Agent{
+		scoringAgent("draft", "draft", map[string]interface{}{"confidence": 0.5, "tokens": 100}),
+		scoringAgent("review", "final", map[string]interface{}{"confidence": 0.9}),
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := pipeline.Process(context.Background(), agenkit.NewMessage("user", "write"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["confidence"] != 0.9 {
+		t.Errorf("expected last stage's confidence, got %v", result.Metadata["confidence"])
+	}
+	if result.Metadata["tokens"] != 100 {
+		t.Errorf("expected tokens carried from first stage, got %v", result.Metadata["tokens"])
+	}
+}
+
+func TestParallelAgent_CarriesConfidenceOntoAggregate(t *testing.T) {
+	parallel, err := NewParallelAgent([]agenkit.Agent{
+		scoringAgent("a", "one", nil),
+		scoringAgent("b", "two", map[string]interface{}{"confidence": 0.8}),
+	}, DefaultAggregators.Concatenate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := parallel.Process(context.Background(), agenkit.NewMessage("user", "go"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["confidence"] != 0.8 {
+		t.Errorf("expected confidence 0.8 on aggregate, got %v", result.Metadata["confidence"])
+	}
+}
+
+func TestPropagateMetadata_SumsAdditiveKeys(t *testing.T) {
+	from := agenkit.NewMessage("assistant", "a").
+		WithMetadata("confidence", 0.9).
+		WithMetadata("cost", 0.25).
+		WithMetadata("tokens", 100)
+	to := agenkit.NewMessage("assistant", "b").
+		WithMetadata("confidence", 0.4).
+		WithMetadata("cost", 0.5).
+		WithMetadata("tokens", 20)
+
+	PropagateMetadata(from, to)
+	if to.Metadata["confidence"] != 0.4 {
+		t.Errorf("expected nearest confidence kept, got %v", to.Metadata["confidence"])
+	}
+	if to.Metadata["cost"] != 0.75 || to.Metadata["tokens"] != 120 {
+		t.Errorf("expected cost and tokens summed, got %v", to.Metadata)
+	}
+}
+
+func TestSequentialAgent_SumsCostAcrossStages(t *testing.T) {
+	shared := agenkit.NewMessage("assistant", "cached").WithMetadata("cost", 0.5).WithMetadata("tokens", 7)
+	pipeline, err := NewSequentialAgent([]agenkit.Agent{
+		scoringAgent("draft", "draft", map[string]interface{}{"cost": 0.25, "tokens": 100, "confidence": 0.5}),
+		&extendedMockAgent{name: "cache", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			return shared, nil
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := pipeline.Process(context.Background(), agenkit.NewMessage("user", "write"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["cost"] != 0.75 || result.Metadata["tokens"] != 107 || result.Metadata["confidence"] != 0.5 {
+		t.Errorf("unexpected pipeline metadata %v", result.Metadata)
+	}
+	// The stage's own message is not written to
+	if shared.Metadata["cost"] != 0.5 || shared.Metadata["tokens"] != 7 {
+		t.Errorf("expected the stage's message unchanged, got %v", shared.Metadata)
+	}
+}
+
+func TestParallelAgent_SumsCostAcrossBranches(t *testing.T) {
+	first := agenkit.NewMessage("assistant", "one").WithMetadata("cost", 0.5).WithMetadata("confidence", 0.6)
+	parallel, err := NewParallelAgent([]agenkit.Agent{
+		&extendedMockAgent{name: "a", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			return first, nil
+		}},
+		scoringAgent("b", "two", map[string]interface{}{"cost": 0.25, "confidence": 0.9}),
+	}, func(messages []*agenkit.Message) *agenkit.Message {
+		return messages[0] // returns a branch's own message
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := parallel.Process(context.Background(), agenkit.NewMessage("user", "go"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["cost"] != 0.75 {
+		t.Errorf("expected summed cost 0.75, got %v", result.Metadata["cost"])
+	}
+	if result.Metadata["confidence"] != 0.6 {
+		t.Errorf("expected the aggregate's confidence kept, got %v", result.Metadata["confidence"])
+	}
+	if first.Metadata["cost"] != 0.5 || first.Metadata["parallel_agents"] != nil {
+		t.Errorf("expected the branch's message unchanged, got %v", first.Metadata)
+	}
+}
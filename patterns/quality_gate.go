@@ -0,0 +1,149 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Quality gate pattern scores a message with an evaluation metric and
+// passes it through or hands it to a remediation agent.
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+	"github.com/scttfrdmn/agenkit-go/evaluation"
+)
+
+// QualityScoreKey is the metadata key the quality gate stores its score
+// under, and QualityPassedKey whether the score met the threshold.
+const (
+	QualityScoreKey  = "quality_score"
+	QualityPassedKey = "quality_passed"
+)
+
+// QualityGateError is returned by a QualityGate without a remediation
+// agent when a message scores below the threshold.
+type QualityGateError struct {
+	Metric    string
+	Score     float64
+	Threshold float64
+}
+
+func (e *QualityGateError) Error() string {
+	return fmt.Sprintf("quality gate failed: %s scored %.2f, below threshold %.2f", e.Metric, e.Score, e.Threshold)
+}
+
+// QualityGate passes messages that score at least threshold on a metric
+// and sends the rest to a remediation agent.
+//
+// The incoming message is measured as the output of an interaction (it is
+// also passed as the input, since the gate never sees the original
+// request). Passing messages are returned as a copy with the score in
+// metadata; failing messages, with the score attached, go to onFail and
+// its response is returned instead. Without onFail a failing message
+// returns a *QualityGateError, stopping any enclosing pipeline.
+//
+// Example:
+//
+//	gate, _ := patterns.NewQualityGate(evaluation.NewQualityMetrics(false, "", nil), 0.7, rewriter)
+//	pipeline, _ := patterns.NewSequentialAgent([]agenkit.Agent{drafter, gate, publisher})
+type QualityGate struct {
+	name      string
+	metric    evaluation.Metric
+	threshold float64
+	onFail    agenkit.Agent
+
+	lazy lazyInit
+}
+
+// NewQualityGate creates a quality gate.
+//
+// Parameters:
+//   - metric: Metric to score messages with
+//   - threshold: Minimum passing score
+//   - onFail: Agent that handles failing messages (nil returns an error)
+func NewQualityGate(metric evaluation.Metric, threshold float64, onFail agenkit.Agent) (*QualityGate, error) {
+	if metric == nil {
+		return nil, fmt.Errorf("metric is required")
+	}
+
+	return &QualityGate{
+		name:      "QualityGate",
+		metric:    metric,
+		threshold: threshold,
+		onFail:    onFail,
+	}, nil
+}
+
+// Name returns the agent's identifier.
+func (g *QualityGate) Name() string {
+	return g.name
+}
+
+// Capabilities returns the gate's capabilities.
+func (g *QualityGate) Capabilities() []string {
+	return []string{"quality_gate", "evaluation"}
+}
+
+// Introspect returns introspection information for the gate.
+func (g *QualityGate) Introspect() *agenkit.IntrospectionResult {
+	state := map[string]interface{}{
+		"metric":    g.metric.Name(),
+		"threshold": g.threshold,
+	}
+	if g.onFail != nil {
+		state["on_fail"] = g.onFail.Name()
+	}
+
+	return &agenkit.IntrospectionResult{
+		AgentName:     g.Name(),
+		Capabilities:  g.Capabilities(),
+		InternalState: state,
+	}
+}
+
+// Process scores the message and passes it through or remediates it.
+func (g *QualityGate) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := g.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	score, err := g.metric.Measure(g, message, message, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("quality gate metric %s failed: %w", g.metric.Name(), err)
+	}
+	passed := score >= g.threshold
+
+	scored := copyMessage(message)
+	scored.Metadata[QualityScoreKey] = score
+	scored.Metadata[QualityPassedKey] = passed
+
+	logger := Logger().With(slog.String("pattern", g.name), slog.String("metric", g.metric.Name()))
+	if passed {
+		logger.DebugContext(ctx, LogEventQualityGate, slog.Float64("score", score))
+		return scored, nil
+	}
+	logger.WarnContext(ctx, LogEventQualityGate, slog.Float64("score", score), slog.Float64("threshold", g.threshold))
+
+	if g.onFail == nil {
+		return nil, &QualityGateError{Metric: g.metric.Name(), Score: score, Threshold: g.threshold}
+	}
+	AnnotateTrace(ctx, fmt.Sprintf("scored the output %.2f on %s, below %.2f, and sent it to %s", score, g.metric.Name(), g.threshold, g.onFail.Name()))
+
+	remediated, err := ProcessTraced(ctx, g.onFail, scored)
+	if err != nil {
+		return nil, fmt.Errorf("quality gate remediation %s failed: %w", g.onFail.Name(), err)
+	}
+	if remediated == nil {
+		return nil, fmt.Errorf("agent %s returned no message", g.onFail.Name())
+	}
+
+	result := copyMessage(remediated)
+	result.Metadata[QualityScoreKey] = score
+	result.Metadata[QualityPassedKey] = false
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// lengthScore scores a message by its length, capped at 1 for 10 characters.
+type lengthScore struct{}
+
+func (lengthScore) Name() string { return "length" }
+func (lengthScore) Measure(agent agenkit.Agent, input, output *agenkit.Message, ctx map[string]interface{}) (float64, error) {
+	if output.ContentString() == "broken" {
+		return 0, errors.New("cannot score")
+	}
+	return math.Min(float64(len(output.ContentString()))/10, 1), nil
+}
+
+func TestQualityGate_PassesGoodOutput(t *testing.T) {
+	gate, err := NewQualityGate(lengthScore{}, 0.5, nil)
+	if err != nil {
+		t.Fatalf("NewQualityGate: %v", err)
+	}
+
+	input := agenkit.NewMessage("assistant", "a long enough answer")
+	result, err := gate.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != input.Content || result.Metadata[QualityScoreKey] != 1.0 || result.Metadata[QualityPassedKey] != true {
+		t.Errorf("unexpected result %q %v", result.Content, result.Metadata)
+	}
+	if _, ok := input.Metadata[QualityScoreKey]; ok {
+		t.Error("gate mutated the input message")
+	}
+}
+
+func TestQualityGate_RemediatesPoorOutput(t *testing.T) {
+	var received *agenkit.Message
+	rewriter := &extendedMockAgent{name: "rewriter", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		received = msg
+		return agenkit.NewMessage("assistant", "a rewritten answer"), nil
+	}}
+	gate, _ := NewQualityGate(lengthScore{}, 0.5, rewriter)
+
+	result, err := gate.Process(context.Background(), agenkit.NewMessage("assistant", "meh"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != "a rewritten answer" || result.Metadata[QualityPassedKey] != false {
+		t.Errorf("unexpected result %q %v", result.Content, result.Metadata)
+	}
+	if received == nil || received.Content != "meh" || received.Metadata[QualityScoreKey] == nil {
+		t.Errorf("expected the scored message to reach the rewriter, got %+v", received)
+	}
+}
+
+func TestQualityGate_Errors(t *testing.T) {
+	gate, _ := NewQualityGate(lengthScore{}, 0.5, nil)
+
+	_, err := gate.Process(context.Background(), agenkit.NewMessage("assistant", "meh"))
+	var gateErr *QualityGateError
+	if !errors.As(err, &gateErr) || gateErr.Score != 0.3 || gateErr.Metric != "length" {
+		t.Errorf("expected QualityGateError, got %v", err)
+	}
+
+	if _, err := gate.Process(context.Background(), agenkit.NewMessage("assistant", "broken")); err == nil {
+		t.Error("expected the metric error to be returned")
+	}
+
+	if _, err := NewQualityGate(nil, 0.5, nil); err == nil {
+		t.Error("expected an error without a metric")
+	}
+}
@@ -24,8 +24,11 @@ package patterns
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
@@ -36,12 +39,58 @@ type ReActStep struct {
 	Thought string
 	// Action is the tool to use (if any)
 	Action string
-	// ActionInput is the input to the tool (if any)
+	// ActionInput is the input to the tool (if any); for a structured
+	// tool call, its arguments as JSON
 	ActionInput string
+	// ToolCallID is the ID of the structured tool call the action came
+	// from (if any)
+	ToolCallID string
 	// Observation is the result of the action (if any)
 	Observation string
 	// IsFinal indicates whether this is the final answer
 	IsFinal bool
+	// Injected marks an observation supplied by an ObservationSource
+	// rather than produced by the agent's own action
+	Injected bool
+	// ToolRetries is how many times the action's tool was retried
+	ToolRetries int
+}
+
+// ObservationSource supplies external observations (a human correction,
+// another system's update) to a running ReActAgent. It is called before
+// each reasoning step with the zero-based step number and returns the
+// observations to append to the conversation, or none to proceed normally.
+// It must not block; see ChannelObservations for a channel-backed source.
+type ObservationSource func(ctx context.Context, step int) []string
+
+// ChannelObservations returns an ObservationSource that drains whatever
+// observations are waiting on ch without blocking.
+//
+// Example:
+//
+//	feedback := make(chan string, 10)
+//	agent, _ := patterns.NewReActAgent(&patterns.ReActConfig{
+//	    Agent:        llm,
+//	    Tools:        tools,
+//	    Observations: patterns.ChannelObservations(feedback),
+//	})
+//	// From another goroutine, mid-run:
+//	feedback <- "That search result is stale, ignore it"
+func ChannelObservations(ch <-chan string) ObservationSource {
+	return func(ctx context.Context, step int) []string {
+		var observations []string
+		for {
+			select {
+			case observation, ok := <-ch:
+				if !ok {
+					return observations
+				}
+				observations = append(observations, observation)
+			default:
+				return observations
+			}
+		}
+	}
 }
 
 // ReActStopReason indicates why the ReAct loop terminated.
@@ -56,8 +105,30 @@ const (
 	StopReasonInvalidAction ReActStopReason = "invalid_action"
 	// StopReasonToolError indicates tool execution failed
 	StopReasonToolError ReActStopReason = "tool_error"
+	// StopReasonTimeout indicates MaxDuration elapsed before a final answer
+	StopReasonTimeout ReActStopReason = "timeout"
+	// StopReasonLoopDetected indicates the agent repeated an identical
+	// action too often (see AdaptiveSteps)
+	StopReasonLoopDetected ReActStopReason = "loop_detected"
 )
 
+// shared maps a ReAct stop reason to the StopReason shared by all loop
+// patterns.
+func (s ReActStopReason) shared() StopReason {
+	switch s {
+	case StopReasonFinalAnswer:
+		return StopCompleted
+	case StopReasonMaxSteps:
+		return StopMaxIterations
+	case StopReasonTimeout:
+		return StopTimeout
+	case StopReasonLoopDetected:
+		return StopLoopDetected
+	default:
+		return StopError
+	}
+}
+
 // ReActConfig configures a ReActAgent.
 type ReActConfig struct {
 	// Agent to use for reasoning
@@ -66,10 +137,41 @@ type ReActConfig struct {
 	Tools []agenkit.Tool
 	// MaxSteps is the maximum number of reasoning-acting steps (default: 10)
 	MaxSteps int
+	// AdaptiveSteps turns MaxSteps into a soft budget that extends while
+	// steps make progress, and stops with StopReasonLoopDetected on
+	// repeated identical actions (optional; nil = fixed MaxSteps). The
+	// final limit is recorded in metadata "step_budget".
+	AdaptiveSteps *AdaptiveSteps
 	// Verbose includes step-by-step reasoning in final output (default: false)
 	Verbose bool
-	// PromptTemplate is a custom prompt template for the agent
+	// PromptTemplate is a fixed custom prompt for the agent; it takes
+	// precedence over Template
 	PromptTemplate string
+	// Template renders the instruction prompt (default: DefaultReActPrompt).
+	// Data: ReActPromptData.
+	Template *agenkit.PromptTemplate
+	// MaxDuration caps total wall-clock time for the loop (0 = unlimited).
+	// On expiry the in-flight call is cancelled and the last step is
+	// returned with stop_reason and terminated_reason "timeout".
+	MaxDuration time.Duration
+	// ToolBudgets caps how many times each named tool may be called per
+	// Process call (optional). Once a tool's budget is spent, further calls
+	// are not executed and the observation says the budget is exhausted, so
+	// the agent can reason around it. Tools without an entry are unlimited;
+	// a budget of 0 disables the tool.
+	ToolBudgets map[string]int
+	// ToolRetry retries failed tool executions before they become an
+	// observation (optional; nil = no retries). Retries are recorded in
+	// each step's ToolRetries and per tool in metadata "tool_retries".
+	ToolRetry *ToolRetryPolicy
+	// ToolRetryPolicies overrides ToolRetry for the named tools (optional)
+	ToolRetryPolicies map[string]ToolRetryPolicy
+	// Observations supplies externally injected observations before each
+	// reasoning step (optional). Injected observations are recorded in the
+	// trace as steps with Injected set.
+	Observations ObservationSource
+	// Logger receives structured tool-call events (default: package logger)
+	Logger *slog.Logger
 }
 
 // ReActAgent combines reasoning with tool use.
@@ -95,9 +197,21 @@ type ReActAgent struct {
 	agent          agenkit.Agent
 	tools          map[string]agenkit.Tool
 	maxSteps       int
+	adaptiveSteps  *AdaptiveSteps
+	stepBudget     *stepBudget
 	verbose        bool
 	promptTemplate string
 	steps          []ReActStep
+	maxDuration    time.Duration
+	toolBudgets    map[string]int
+	toolCalls      *toolCallBudget
+	toolRetry      *ToolRetryPolicy
+	toolPolicies   map[string]ToolRetryPolicy
+	toolRetries    *toolRetrier
+	observations   ObservationSource
+	logger         *slog.Logger
+
+	lazy lazyInit
 }
 
 // NewReActAgent creates a new ReAct agent.
@@ -127,7 +241,21 @@ func NewReActAgent(config *ReActConfig) (*ReActAgent, error) {
 
 	promptTemplate := config.PromptTemplate
 	if promptTemplate == "" {
-		promptTemplate = buildDefaultPrompt(config.Tools)
+		tmpl := config.Template
+		if tmpl == nil {
+			tmpl = DefaultReActPrompt
+		}
+		rendered, err := tmpl.Render(ReActPromptData{Tools: promptTools(config.Tools)})
+		if err != nil {
+			return nil, err
+		}
+		promptTemplate = rendered
+	}
+
+	var toolRetry *ToolRetryPolicy
+	if config.ToolRetry != nil {
+		policy := *config.ToolRetry
+		toolRetry = &policy
 	}
 
 	return &ReActAgent{
@@ -135,42 +263,22 @@ func NewReActAgent(config *ReActConfig) (*ReActAgent, error) {
 		agent:          config.Agent,
 		tools:          toolsMap,
 		maxSteps:       maxSteps,
+		adaptiveSteps:  config.AdaptiveSteps,
+		stepBudget:     newStepBudget(maxSteps, nil),
 		verbose:        verbose,
 		promptTemplate: promptTemplate,
 		steps:          []ReActStep{},
+		maxDuration:    config.MaxDuration,
+		toolBudgets:    copyToolBudgets(config.ToolBudgets),
+		toolCalls:      newToolCallBudget(nil),
+		toolRetry:      toolRetry,
+		toolPolicies:   copyToolRetryPolicies(config.ToolRetryPolicies),
+		toolRetries:    newToolRetrier(nil, nil, nil),
+		observations:   config.Observations,
+		logger:         config.Logger,
 	}, nil
 }
 
-// buildDefaultPrompt creates the default prompt template with tool descriptions.
-func buildDefaultPrompt(tools []agenkit.Tool) string {
-	var toolDescriptions strings.Builder
-	for i, tool := range tools {
-		if i > 0 {
-			toolDescriptions.WriteString("\n")
-		}
-		toolDescriptions.WriteString(fmt.Sprintf("- %s: %s", tool.Name(), tool.Description()))
-	}
-
-	return fmt.Sprintf(`You are a helpful assistant that can use tools to answer questions.
-
-Available tools:
-%s
-
-Use the following format:
-
-Thought: Think about what to do next
-Action: [tool name]
-Action Input: [input for the tool]
-Observation: [result will be provided]
-
-... (repeat Thought/Action/Observation as needed)
-
-Thought: I now know the final answer
-Final Answer: [your final answer here]
-
-Begin!`, toolDescriptions.String())
-}
-
 // Name returns the agent name.
 func (r *ReActAgent) Name() string {
 	return r.name
@@ -182,25 +290,71 @@ func (r *ReActAgent) Capabilities() []string {
 }
 
 // Process executes the ReAct reasoning-acting loop.
-func (r *ReActAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+func (r *ReActAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := r.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	logger := resolveLogger(r.logger).With(slog.String("pattern", r.name))
 	r.steps = []ReActStep{}
+	r.toolCalls = newToolCallBudget(r.toolBudgets)
+	r.toolRetries = newToolRetrier(r.toolRetry, r.toolPolicies, logger)
+	r.stepBudget = newStepBudget(r.maxSteps, r.adaptiveSteps)
 	conversationHistory := []string{r.promptTemplate, fmt.Sprintf("\nQuestion: %s", message.ContentString())}
 
-	for step := 0; step < r.maxSteps; step++ {
+	// Bound the whole loop, cancelling in-flight calls at the deadline
+	loopCtx := ctx
+	if r.maxDuration > 0 {
+		var cancel context.CancelFunc
+		loopCtx, cancel = context.WithTimeout(ctx, r.maxDuration)
+		defer cancel()
+	}
+
+	for step := 0; r.stepBudget.allows(step); step++ {
+		if loopTimedOut(ctx, loopCtx) {
+			return r.timeoutAnswer(), nil
+		}
+
+		// Append any externally injected observations
+		if r.observations != nil {
+			for _, observation := range r.observations(loopCtx, step) {
+				logger.DebugContext(ctx, LogEventObservationInjected, slog.Int("step", step))
+				injected := ReActStep{Observation: observation, Injected: true}
+				r.steps = append(r.steps, injected)
+				conversationHistory = append(conversationHistory, r.formatStep(injected))
+			}
+		}
+
 		// Get agent's reasoning
 		prompt := strings.Join(conversationHistory, "\n")
-		response, err := r.agent.Process(ctx, &agenkit.Message{
-			Role:    "user",
+		response, err := ProcessTraced(loopCtx, r.agent, &agenkit.Message{
+			Role:    agenkit.RoleUser,
 			Content: prompt,
 		})
 		if err != nil {
+			if loopTimedOut(ctx, loopCtx) {
+				return r.timeoutAnswer(), nil
+			}
+			logger.WarnContext(ctx, LogEventAgentError, slog.String("agent", r.agent.Name()),
+				slog.Int("step", step), slog.Any("error", err))
 			return nil, fmt.Errorf("agent process failed: %w", err)
 		}
 
 		responseText := response.ContentString()
 
-		// Parse the response
-		parsed := r.parseResponse(responseText)
+		// Prefer a structured tool call, falling back to parsing the text
+		var parsed ReActStep
+		var arguments map[string]interface{}
+		if response.HasToolCalls() {
+			parsed, arguments = r.structuredStep(response)
+		} else {
+			parsed = r.parseResponse(responseText)
+		}
 
 		// Check for final answer
 		if parsed.IsFinal {
@@ -224,20 +378,48 @@ func (r *ReActAgent) Process(ctx context.Context, message *agenkit.Message) (*ag
 			parsed.Observation = fmt.Sprintf("Error: Tool '%s' not found. Available tools: %s",
 				parsed.Action, strings.Join(toolNames, ", "))
 			r.steps = append(r.steps, parsed)
+			if r.stepBudget.observe(step, parsed.Action, parsed.ActionInput, parsed.Observation) {
+				return r.loopDetected(ctx, logger, step, parsed), nil
+			}
+			conversationHistory = append(conversationHistory, r.formatStep(parsed))
+			continue
+		}
+
+		if !r.toolCalls.acquire(parsed.Action) {
+			logger.DebugContext(ctx, LogEventToolCall, slog.Int("step", step),
+				slog.String("tool", parsed.Action), slog.Bool("budget_exhausted", true))
+			parsed.Observation = "Error: " + r.toolCalls.exhaustedMessage(parsed.Action)
+			r.steps = append(r.steps, parsed)
+			if r.stepBudget.observe(step, parsed.Action, parsed.ActionInput, parsed.Observation) {
+				return r.loopDetected(ctx, logger, step, parsed), nil
+			}
 			conversationHistory = append(conversationHistory, r.formatStep(parsed))
 			continue
 		}
 
 		// Execute tool
-		toolResult, err := tool.Execute(ctx, map[string]interface{}{"input": parsed.ActionInput})
+		logger.DebugContext(ctx, LogEventToolCall, slog.Int("step", step),
+			slog.String("tool", parsed.Action), slog.String("input", parsed.ActionInput))
+		if arguments == nil {
+			arguments = map[string]interface{}{"input": parsed.ActionInput}
+		}
+		toolResult, retries, err := r.toolRetries.execute(loopCtx, tool, arguments)
+		parsed.ToolRetries = retries
 		if err != nil {
+			if loopTimedOut(ctx, loopCtx) {
+				parsed.Observation = fmt.Sprintf("Error: %v", err)
+				r.steps = append(r.steps, parsed)
+				return r.timeoutAnswer(), nil
+			}
+			logger.WarnContext(ctx, LogEventToolCall, slog.Int("step", step),
+				slog.String("tool", parsed.Action), slog.Any("error", err))
 			parsed.Observation = fmt.Sprintf("Error: %v", err)
 			r.steps = append(r.steps, parsed)
 			return r.formatFinalAnswer(parsed, StopReasonToolError), nil
 		}
 
 		if toolResult.Success {
-			parsed.Observation = fmt.Sprintf("%v", toolResult.Data)
+			parsed.Observation = toolResult.DataString()
 		} else {
 			errorMsg := "Tool execution failed"
 			if toolResult.Error != "" {
@@ -248,6 +430,9 @@ func (r *ReActAgent) Process(ctx context.Context, message *agenkit.Message) (*ag
 
 		// Record step and add to conversation
 		r.steps = append(r.steps, parsed)
+		if r.stepBudget.observe(step, parsed.Action, parsed.ActionInput, parsed.Observation) {
+			return r.loopDetected(ctx, logger, step, parsed), nil
+		}
 		conversationHistory = append(conversationHistory, r.formatStep(parsed))
 	}
 
@@ -256,13 +441,51 @@ func (r *ReActAgent) Process(ctx context.Context, message *agenkit.Message) (*ag
 		Thought: "Reached maximum steps without finding answer",
 		IsFinal: false,
 	}
-	if len(r.steps) > 0 {
-		lastStep = r.steps[len(r.steps)-1]
+	if last, ok := r.lastReasoningStep(); ok {
+		lastStep = last
 	}
 
 	return r.formatFinalAnswer(lastStep, StopReasonMaxSteps), nil
 }
 
+// loopDetected returns the answer when step repeated an identical action
+// too often.
+func (r *ReActAgent) loopDetected(ctx context.Context, logger *slog.Logger, step int, parsed ReActStep) *agenkit.Message {
+	logger.WarnContext(ctx, LogEventLoopDetected, slog.Int("step", step),
+		slog.String("tool", parsed.Action), slog.String("input", parsed.ActionInput))
+	return r.formatFinalAnswer(parsed, StopReasonLoopDetected)
+}
+
+// timeoutAnswer returns the best partial conclusion after MaxDuration expires.
+func (r *ReActAgent) timeoutAnswer() *agenkit.Message {
+	lastStep := ReActStep{Thought: "Ran out of time before finding answer"}
+	if last, ok := r.lastReasoningStep(); ok {
+		lastStep = last
+	}
+
+	result := r.formatFinalAnswer(lastStep, StopReasonTimeout)
+	result.Metadata["terminated_reason"] = string(StopReasonTimeout)
+	return result
+}
+
+// lastReasoningStep returns the agent's most recent step, skipping
+// injected observations.
+func (r *ReActAgent) lastReasoningStep() (ReActStep, bool) {
+	for i := len(r.steps) - 1; i >= 0; i-- {
+		if !r.steps[i].Injected {
+			return r.steps[i], true
+		}
+	}
+	return ReActStep{}, false
+}
+
+// loopTimedOut reports whether loopCtx hit its own deadline while the
+// caller's ctx is still live, i.e. a MaxDuration timeout rather than
+// caller cancellation.
+func loopTimedOut(ctx, loopCtx context.Context) bool {
+	return ctx.Err() == nil && errors.Is(loopCtx.Err(), context.DeadlineExceeded)
+}
+
 // parseResponse parses agent response into structured step.
 func (r *ReActAgent) parseResponse(response string) ReActStep {
 	lines := strings.Split(response, "\n")
@@ -290,8 +513,39 @@ func (r *ReActAgent) parseResponse(response string) ReActStep {
 	return step
 }
 
+// structuredStep builds a step from the response's first structured tool
+// call, returning the call's arguments. Further calls in the same response
+// are ignored; the agent can repeat them on a later step.
+func (r *ReActAgent) structuredStep(response *agenkit.Message) (ReActStep, map[string]interface{}) {
+	call := response.ToolCalls[0]
+	arguments := call.Arguments
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+
+	text := response.ContentString()
+	thought := r.parseResponse(text).Thought
+	if thought == "" {
+		thought = strings.TrimSpace(text)
+	}
+	if thought == "" {
+		thought = fmt.Sprintf("Calling %s", call.Name)
+	}
+
+	return ReActStep{
+		Thought:     thought,
+		Action:      call.Name,
+		ActionInput: toolCallKey(arguments),
+		ToolCallID:  call.ID,
+	}, arguments
+}
+
 // formatStep formats a step for conversation history.
 func (r *ReActAgent) formatStep(step ReActStep) string {
+	if step.Injected {
+		return fmt.Sprintf("Observation (external): %s", step.Observation)
+	}
+
 	var formatted strings.Builder
 	formatted.WriteString(fmt.Sprintf("Thought: %s", step.Thought))
 
@@ -336,15 +590,24 @@ func (r *ReActAgent) formatFinalAnswer(step ReActStep, stopReason ReActStopReaso
 		}
 	}
 
-	return &agenkit.Message{
-		Role:    "assistant",
+	result := &agenkit.Message{
+		Role:    agenkit.RoleAssistant,
 		Content: content.String(),
 		Metadata: map[string]interface{}{
 			"stop_reason": string(stopReason),
+			StopReasonKey: stopReason.shared(),
 			"steps":       len(r.steps),
 			"reasoning":   r.steps,
+			"tool_calls":  r.toolCalls.counts(),
 		},
 	}
+	if r.toolRetries.enabled() {
+		result.Metadata["tool_retries"] = r.toolRetries.counts()
+	}
+	if r.adaptiveSteps != nil {
+		result.Metadata["step_budget"] = r.stepBudget.limit
+	}
+	return result
 }
 
 // GetSteps returns the reasoning history (useful for debugging/analysis).
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
@@ -651,3 +652,331 @@ func TestParseResponse_FinalAnswer(t *testing.T) {
 		t.Error("expected IsFinal true")
 	}
 }
+
+// ============================================================================
+// Timeout Tests
+// ============================================================================
+
+// ctxBlockingTool blocks until its context is cancelled.
+type ctxBlockingTool struct {
+	cancelled chan struct{}
+}
+
+func (b *ctxBlockingTool) Name() string        { return "slow" }
+func (b *ctxBlockingTool) Description() string { return "Never finishes" }
+func (b *ctxBlockingTool) Execute(ctx context.Context, params map[string]any) (*agenkit.ToolResult, error) {
+	<-ctx.Done()
+	close(b.cancelled)
+	return nil, ctx.Err()
+}
+
+func TestReActAgent_MaxDurationCancelsTool(t *testing.T) {
+	tool := &ctxBlockingTool{cancelled: make(chan struct{})}
+	agent := &mockReActAgent{
+		name:      "test",
+		responses: []string{"Thought: I should look this up\nAction: slow\nAction Input: query"},
+	}
+
+	react, err := NewReActAgent(&ReActConfig{
+		Agent:       agent,
+		Tools:       []agenkit.Tool{tool},
+		MaxDuration: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	result, err := react.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	if err != nil {
+		t.Fatalf("expected partial result, got error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected prompt return after timeout, took %v", elapsed)
+	}
+
+	select {
+	case <-tool.cancelled:
+	default:
+		t.Error("expected in-progress tool call to be cancelled")
+	}
+
+	if result.Metadata["stop_reason"] != "timeout" || result.Metadata["terminated_reason"] != "timeout" {
+		t.Errorf("expected timeout metadata, got %v", result.Metadata)
+	}
+	if !strings.Contains(result.ContentString(), "I should look this up") {
+		t.Errorf("expected last thought in partial answer, got %q", result.ContentString())
+	}
+}
+
+func TestReActAgent_MaxDurationCancelsLLM(t *testing.T) {
+	llm := &extendedMockAgent{name: "llm", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+
+	react, err := NewReActAgent(&ReActConfig{
+		Agent:       llm,
+		Tools:       []agenkit.Tool{&mockTool{name: "search"}},
+		MaxDuration: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := react.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	if err != nil {
+		t.Fatalf("expected partial result, got error: %v", err)
+	}
+	if result.Metadata["terminated_reason"] != "timeout" {
+		t.Errorf("expected timeout, got %v", result.Metadata)
+	}
+
+	// Caller cancellation is still an error, not a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := react.Process(ctx, agenkit.NewMessage("user", "question")); err == nil {
+		t.Error("expected error when caller cancels")
+	}
+}
+
+func TestReActAgent_ToolBudgets(t *testing.T) {
+	search := &mockTool{name: "search", description: "Paid search", response: "result"}
+	calc := &mockTool{name: "calc", description: "Calculator", response: "4"}
+	call := func(tool string) string {
+		return fmt.Sprintf("Thought: use %s\nAction: %s\nAction Input: x", tool, tool)
+	}
+	agent := &mockReActAgent{name: "llm", responses: []string{
+		call("search"), call("search"), call("calc"), call("calc"),
+		"Thought: done\nFinal Answer: 4",
+	}}
+
+	react, err := NewReActAgent(&ReActConfig{
+		Agent:       agent,
+		Tools:       []agenkit.Tool{search, calc},
+		ToolBudgets: map[string]int{"search": 1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := react.Process(context.Background(), agenkit.NewMessage("user", "question"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if search.callCount != 1 || calc.callCount != 2 {
+		t.Errorf("expected search=1 calc=2 executions, got search=%d calc=%d", search.callCount, calc.callCount)
+	}
+	steps := result.Metadata["reasoning"].([]ReActStep)
+	if !strings.Contains(steps[1].Observation, "'search' budget exhausted") {
+		t.Errorf("expected budget exhausted observation, got %q", steps[1].Observation)
+	}
+	calls := result.Metadata["tool_calls"].(map[string]int)
+	if calls["search"] != 1 || calls["calc"] != 2 {
+		t.Errorf("unexpected tool call counts: %v", calls)
+	}
+	if result.ContentString() != "4" {
+		t.Errorf("expected final answer after exhausted budget, got %q", result.ContentString())
+	}
+}
+
+func TestReActAgent_ToolBudgetsResetPerProcess(t *testing.T) {
+	search := &mockTool{name: "search", description: "Paid search", response: "result"}
+	agent := &mockReActAgent{name: "llm", responses: []string{
+		"Thought: look\nAction: search\nAction Input: x", "Final Answer: a",
+		"Thought: look\nAction: search\nAction Input: y", "Final Answer: b",
+	}}
+
+	react, _ := NewReActAgent(&ReActConfig{
+		Agent:       agent,
+		Tools:       []agenkit.Tool{search},
+		ToolBudgets: map[string]int{"search": 1},
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := react.Process(context.Background(), agenkit.NewMessage("user", "question")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if search.callCount != 2 {
+		t.Errorf("expected budget to reset between sessions, got %d calls", search.callCount)
+	}
+}
+
+// tableTool returns structured rows.
+type tableTool struct{}
+
+func (t *tableTool) Name() string        { return "query" }
+func (t *tableTool) Description() string { return "Query sales" }
+func (t *tableTool) Execute(ctx context.Context, params map[string]any) (*agenkit.ToolResult, error) {
+	return agenkit.NewToolResult([]map[string]interface{}{
+		{"region": "EU", "total": 10},
+		{"region": "US", "total": 12},
+	}), nil
+}
+
+func TestReActAgent_StructuredToolResult(t *testing.T) {
+	agent := &mockReActAgent{
+		name: "test",
+		responses: []string{
+			"Thought: I need the sales table\nAction: query\nAction Input: sales",
+			"Thought: US is higher\nFinal Answer: US",
+		},
+	}
+	reactAgent, err := NewReActAgent(&ReActConfig{
+		Agent: agent,
+		Tools: []agenkit.Tool{&tableTool{}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := reactAgent.Process(context.Background(), agenkit.NewMessage("user", "Top region?")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	observation := reactAgent.GetSteps()[0].Observation
+	expected := `[{"region":"EU","total":10},{"region":"US","total":12}]`
+	if observation != expected {
+		t.Errorf("expected JSON observation %s, got %s", expected, observation)
+	}
+}
+
+// promptRecordingAgent wraps mockReActAgent, recording each prompt.
+type promptRecordingAgent struct {
+	mockReActAgent
+	prompts []string
+}
+
+func (p *promptRecordingAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	p.prompts = append(p.prompts, message.ContentString())
+	return p.mockReActAgent.Process(ctx, message)
+}
+
+func TestReActAgent_InjectedObservations(t *testing.T) {
+	agent := &promptRecordingAgent{mockReActAgent: mockReActAgent{
+		name: "test",
+		responses: []string{
+			"Thought: Search for it\nAction: search\nAction Input: weather",
+			"Thought: Correction noted\nFinal Answer: Rainy",
+		},
+	}}
+	feedback := make(chan string, 2)
+	reactAgent, err := NewReActAgent(&ReActConfig{
+		Agent: agent,
+		Tools: []agenkit.Tool{&mockTool{name: "search", response: "Sunny"}},
+		Observations: func(ctx context.Context, step int) []string {
+			if step == 1 {
+				feedback <- "That search result is stale, ignore it"
+			}
+			return ChannelObservations(feedback)(ctx, step)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := reactAgent.Process(context.Background(), agenkit.NewMessage("user", "Weather?"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "Rainy" {
+		t.Errorf("expected 'Rainy', got %s", result.ContentString())
+	}
+
+	if strings.Contains(agent.prompts[0], "stale") {
+		t.Error("expected no injected observation before the first step")
+	}
+	if !strings.Contains(agent.prompts[1], "Observation (external): That search result is stale, ignore it") {
+		t.Errorf("expected injected observation in prompt, got:\n%s", agent.prompts[1])
+	}
+
+	steps := reactAgent.GetSteps()
+	if len(steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(steps))
+	}
+	if steps[0].Injected || !steps[1].Injected || steps[2].Injected {
+		t.Errorf("expected only the second step to be injected, got %+v", steps)
+	}
+	if steps[1].Observation != "That search result is stale, ignore it" {
+		t.Errorf("unexpected injected observation %q", steps[1].Observation)
+	}
+}
+
+func TestChannelObservations_NonBlocking(t *testing.T) {
+	ch := make(chan string, 3)
+	source := ChannelObservations(ch)
+
+	if got := source(context.Background(), 0); len(got) != 0 {
+		t.Errorf("expected no observations from empty channel, got %v", got)
+	}
+
+	ch <- "a"
+	ch <- "b"
+	if got := source(context.Background(), 1); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("expected [a b], got %v", got)
+	}
+
+	close(ch)
+	if got := source(context.Background(), 2); len(got) != 0 {
+		t.Errorf("expected no observations from closed channel, got %v", got)
+	}
+}
+
+// scriptedMessages returns the next of its messages on each call.
+func scriptedMessages(messages ...*agenkit.Message) *extendedMockAgent {
+	calls := 0
+	return &extendedMockAgent{
+		name: "llm",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			if calls >= len(messages) {
+				return nil, fmt.Errorf("no more scripted messages")
+			}
+			calls++
+			return messages[calls-1], nil
+		},
+	}
+}
+
+// paramsTool records the parameters it is called with.
+type paramsTool struct{ params map[string]any }
+
+func (p *paramsTool) Name() string        { return "weather" }
+func (p *paramsTool) Description() string { return "Weather for a city" }
+func (p *paramsTool) Execute(ctx context.Context, params map[string]any) (*agenkit.ToolResult, error) {
+	p.params = params
+	return agenkit.NewToolResult(fmt.Sprintf("sunny in %v", params["city"])), nil
+}
+
+func TestReActAgent_StructuredToolCall(t *testing.T) {
+	tool := &paramsTool{}
+	llm := scriptedMessages(
+		agenkit.NewMessage("assistant", "I should check the forecast.").WithToolCalls(agenkit.ToolCall{
+			ID:        "call_1",
+			Name:      "weather",
+			Arguments: map[string]interface{}{"city": "Paris"},
+		}),
+		agenkit.NewMessage("assistant", "Thought: Done\nFinal Answer: Sunny"),
+	)
+	agent, _ := NewReActAgent(&ReActConfig{Agent: llm, Tools: []agenkit.Tool{tool}})
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "Weather in Paris?"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "Sunny" {
+		t.Errorf("unexpected answer: %s", result.ContentString())
+	}
+	if tool.params["city"] != "Paris" {
+		t.Errorf("expected the structured arguments passed through, got %v", tool.params)
+	}
+
+	step := agent.GetSteps()[0]
+	if step.Action != "weather" || step.ToolCallID != "call_1" || step.Thought != "I should check the forecast." {
+		t.Errorf("unexpected step: %+v", step)
+	}
+	if step.ActionInput != `{"city":"Paris"}` || step.Observation != "sunny in Paris" {
+		t.Errorf("unexpected step input or observation: %+v", step)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -63,8 +64,12 @@ type ReasoningStep struct {
 	ToolName string
 	// ToolParameters for tool calls
 	ToolParameters map[string]interface{}
+	// ToolCallID is the ID of a structured tool call (if any)
+	ToolCallID string
 	// ToolResult for tool results
 	ToolResult interface{}
+	// Retries is how many times a tool call was retried
+	Retries int
 	// Confidence score
 	Confidence float64
 	// Timestamp in milliseconds
@@ -79,6 +84,10 @@ type ReasoningTrace struct {
 	TotalToolsUsed int
 	// TotalThinkingSteps count
 	TotalThinkingSteps int
+	// ToolCalls counts executed calls per tool
+	ToolCalls map[string]int
+	// ToolRetries counts retries per tool
+	ToolRetries map[string]int
 	// StartTime in milliseconds
 	StartTime int64
 	// EndTime in milliseconds
@@ -89,12 +98,34 @@ type ReasoningTrace struct {
 type ReasoningWithToolsConfig struct {
 	// MaxReasoningSteps is the maximum reasoning steps
 	MaxReasoningSteps int
+	// AdaptiveSteps turns MaxReasoningSteps into a soft budget that extends
+	// while steps make progress, and stops with StopLoopDetected on
+	// repeated identical tool calls (optional; nil = fixed limit). The
+	// final limit is recorded in metadata "step_budget".
+	AdaptiveSteps *AdaptiveSteps
 	// ToolUsePrompt is a custom tool use prompt
 	ToolUsePrompt string
 	// EnableTrace enables reasoning trace
 	EnableTrace bool
 	// ConfidenceThreshold is the confidence threshold
 	ConfidenceThreshold float64
+	// MaxDuration caps total wall-clock time for the loop (0 = unlimited).
+	// On expiry the in-flight call is cancelled and the latest reasoning is
+	// returned with terminated_reason "timeout".
+	MaxDuration time.Duration
+	// ToolBudgets caps how many times each named tool may be called per
+	// Process call (optional). Once a tool's budget is spent, further calls
+	// are not executed and the model is told the budget is exhausted, so it
+	// can reason around it. Tools without an entry are unlimited; a budget
+	// of 0 disables the tool.
+	ToolBudgets map[string]int
+	// ToolRetry retries failed tool executions before the error is added
+	// to the reasoning context (optional; nil = no retries). Retries are
+	// recorded on tool call trace steps and per tool in metadata
+	// "tool_retries".
+	ToolRetry *ToolRetryPolicy
+	// ToolRetryPolicies overrides ToolRetry for the named tools (optional)
+	ToolRetryPolicies map[string]ToolRetryPolicy
 }
 
 // ReasoningWithToolsAgent can use tools during reasoning (not just after).
@@ -127,9 +158,16 @@ type ReasoningWithToolsAgent struct {
 	llm                 agenkit.Agent
 	tools               map[string]agenkit.Tool
 	maxReasoningSteps   int
+	adaptiveSteps       *AdaptiveSteps
 	toolUsePrompt       string
 	enableTrace         bool
 	confidenceThreshold float64
+	maxDuration         time.Duration
+	toolBudgets         map[string]int
+	toolRetry           *ToolRetryPolicy
+	toolPolicies        map[string]ToolRetryPolicy
+
+	lazy lazyInit
 }
 
 // NewReasoningWithToolsAgent creates a new reasoning with tools agent.
@@ -166,8 +204,16 @@ func NewReasoningWithToolsAgent(
 		llm:                 llm,
 		tools:               toolsMap,
 		maxReasoningSteps:   maxSteps,
+		adaptiveSteps:       config.AdaptiveSteps,
 		enableTrace:         enableTrace,
 		confidenceThreshold: confidenceThreshold,
+		maxDuration:         config.MaxDuration,
+		toolBudgets:         copyToolBudgets(config.ToolBudgets),
+		toolPolicies:        copyToolRetryPolicies(config.ToolRetryPolicies),
+	}
+	if config.ToolRetry != nil {
+		policy := *config.ToolRetry
+		agent.toolRetry = &policy
 	}
 
 	if config.ToolUsePrompt != "" {
@@ -211,7 +257,22 @@ func (r *ReasoningWithToolsAgent) Capabilities() []string {
 }
 
 // Process processes message with reasoning and tool use.
-func (r *ReasoningWithToolsAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+//
+// If MaxDuration elapses, the latest reasoning is returned instead of an
+// error, with metadata "terminated_reason": "timeout". The shared
+// StopReason is recorded under StopReasonKey. Metadata
+// "tool_calls" counts executed calls per tool when tracing is enabled or
+// ToolBudgets is set.
+func (r *ReasoningWithToolsAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := r.Init(ctx); err != nil {
+		return nil, err
+	}
+
 	var trace *ReasoningTrace
 	if r.enableTrace {
 		trace = &ReasoningTrace{
@@ -229,32 +290,73 @@ USER QUESTION:
 
 Begin reasoning. Use tools as needed while thinking.`, r.toolUsePrompt, message.ContentString())
 
+	// Bound the whole loop, cancelling in-flight calls at the deadline
+	loopCtx := ctx
+	if r.maxDuration > 0 {
+		var cancel context.CancelFunc
+		loopCtx, cancel = context.WithTimeout(ctx, r.maxDuration)
+		defer cancel()
+	}
+
 	// Reasoning loop
+	budget := newToolCallBudget(r.toolBudgets)
+	logger := Logger().With(slog.String("pattern", r.name))
+	retrier := newToolRetrier(r.toolRetry, r.toolPolicies, logger)
+	steps := newStepBudget(r.maxReasoningSteps, r.adaptiveSteps)
 	currentContext := enhancedContent
 	var finalAnswer string
+	var lastResponse string
+	timedOut := false
+	stopReason := StopMaxIterations
+
+	for stepNum := 0; steps.allows(stepNum); stepNum++ {
+		if loopTimedOut(ctx, loopCtx) {
+			timedOut = true
+			break
+		}
 
-	for stepNum := 0; stepNum < r.maxReasoningSteps; stepNum++ {
 		// Check context cancellation
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return nil, contextError(ctx)
 		default:
 		}
 
 		// Get next reasoning step from LLM
-		response, err := r.llm.Process(ctx, &agenkit.Message{
-			Role:    "user",
+		response, err := ProcessTraced(loopCtx, r.llm, &agenkit.Message{
+			Role:    agenkit.RoleUser,
 			Content: currentContext,
 		})
 		if err != nil {
+			if loopTimedOut(ctx, loopCtx) {
+				timedOut = true
+				break
+			}
 			return nil, fmt.Errorf("LLM process failed: %w", err)
 		}
 
 		responseText := response.ContentString()
+		lastResponse = responseText
+
+		// Check if this is a tool call, preferring a structured call
+		// (only the first is used) over parsing the text
+		var toolNamePtr *string
+		var parameters map[string]interface{}
+		var remainingText, toolCallID string
+		isToolCall := true
+		if response.HasToolCalls() {
+			call := response.ToolCalls[0]
+			toolNamePtr, parameters, remainingText, toolCallID = &call.Name, call.Arguments, responseText, call.ID
+			if parameters == nil {
+				parameters = map[string]interface{}{}
+			}
+		} else if strings.Contains(responseText, "TOOL_CALL:") {
+			toolNamePtr, parameters, remainingText = r.parseToolCall(responseText)
+		} else {
+			isToolCall = false
+		}
 
-		// Check if this is a tool call
-		if strings.Contains(responseText, "TOOL_CALL:") {
-			toolNamePtr, parameters, remainingText := r.parseToolCall(responseText)
+		if isToolCall {
 
 			if toolNamePtr != nil && r.tools[*toolNamePtr] != nil {
 				toolName := *toolNamePtr
@@ -269,9 +371,35 @@ Begin reasoning. Use tools as needed while thinking.`, r.toolUsePrompt, message.
 					trace.TotalThinkingSteps++
 				}
 
+				if !budget.acquire(toolName) {
+					errorMsg := budget.exhaustedMessage(toolName)
+					if trace != nil {
+						trace.Steps = append(trace.Steps, ReasoningStep{
+							StepNumber: stepNum,
+							StepType:   ReasoningStepToolResult,
+							Content:    errorMsg,
+							ToolName:   toolName,
+							Timestamp:  currentTimeMillis(),
+						})
+					}
+
+					currentContext = fmt.Sprintf(`%s
+
+ERROR: %s
+
+Continue reasoning without this tool.`, currentContext, errorMsg)
+					if steps.observe(stepNum, toolName, toolCallKey(parameters), errorMsg) {
+						stopReason = StopLoopDetected
+						logger.WarnContext(ctx, LogEventLoopDetected, slog.Int("step", stepNum), slog.String("tool", toolName))
+						break
+					}
+					continue
+				}
+
 				// Execute tool
 				tool := r.tools[toolName]
-				toolResult, err := tool.Execute(ctx, parameters)
+				toolResult, retries, err := retrier.execute(loopCtx, tool, parameters)
+				observation := ""
 
 				if err == nil {
 					// Record tool call and result
@@ -282,13 +410,15 @@ Begin reasoning. Use tools as needed while thinking.`, r.toolUsePrompt, message.
 							Content:        fmt.Sprintf("Called %s", toolName),
 							ToolName:       toolName,
 							ToolParameters: parameters,
+							ToolCallID:     toolCallID,
+							Retries:        retries,
 							Timestamp:      currentTimeMillis(),
 						})
 
 						trace.Steps = append(trace.Steps, ReasoningStep{
 							StepNumber: stepNum,
 							StepType:   ReasoningStepToolResult,
-							Content:    fmt.Sprintf("%v", toolResult.Data),
+							Content:    toolResult.DataString(),
 							ToolName:   toolName,
 							ToolResult: toolResult.Data,
 							Timestamp:  currentTimeMillis(),
@@ -296,22 +426,26 @@ Begin reasoning. Use tools as needed while thinking.`, r.toolUsePrompt, message.
 						trace.TotalToolsUsed++
 					}
 
+					observation = toolResult.DataString()
+
 					// Update context with tool result
 					currentContext = fmt.Sprintf(`Previous reasoning: %s
 
 TOOL RESULT from %s:
 %v
 
-Continue reasoning with this information.`, currentContext, toolName, toolResult.Data)
+Continue reasoning with this information.`, currentContext, toolName, toolResult.DataString())
 				} else {
 					// Tool execution failed
 					errorMsg := fmt.Sprintf("Tool %s failed: %v", toolName, err)
+					observation = errorMsg
 					if trace != nil {
 						trace.Steps = append(trace.Steps, ReasoningStep{
 							StepNumber: stepNum,
 							StepType:   ReasoningStepToolResult,
 							Content:    errorMsg,
 							ToolName:   toolName,
+							Retries:    retries,
 							Timestamp:  currentTimeMillis(),
 						})
 					}
@@ -322,6 +456,12 @@ ERROR: %s
 
 Continue reasoning without this tool.`, currentContext, errorMsg)
 				}
+
+				if steps.observe(stepNum, toolName, toolCallKey(parameters), observation) {
+					stopReason = StopLoopDetected
+					logger.WarnContext(ctx, LogEventLoopDetected, slog.Int("step", stepNum), slog.String("tool", toolName))
+					break
+				}
 			} else {
 				// Unknown tool, continue with regular thinking
 				if trace != nil {
@@ -339,11 +479,13 @@ Continue reasoning without this tool.`, currentContext, errorMsg)
 %s
 
 Continue.`, currentContext, responseText)
+				steps.observe(stepNum, "", "", responseText)
 			}
 		} else {
 			// Check if we have a final answer
 			if r.isConclusion(responseText) {
 				finalAnswer = r.extractAnswer(responseText)
+				stopReason = StopCompleted
 				if trace != nil {
 					trace.Steps = append(trace.Steps, ReasoningStep{
 						StepNumber: stepNum,
@@ -372,34 +514,66 @@ Continue.`, currentContext, responseText)
 %s
 
 Continue reasoning or provide final answer.`, currentContext, responseText)
+			steps.observe(stepNum, "", "", responseText)
 		}
 	}
 
 	// Finalize trace
 	if trace != nil {
 		trace.EndTime = currentTimeMillis()
+		trace.ToolCalls = budget.counts()
+		trace.ToolRetries = retrier.counts()
 	}
 
-	// If no answer found, use last response
+	// If no answer found, use the latest reasoning on timeout, otherwise
+	// the accumulated context
 	if finalAnswer == "" {
-		finalAnswer = currentContext
+		if timedOut && lastResponse != "" {
+			finalAnswer = lastResponse
+		} else {
+			finalAnswer = currentContext
+		}
 	}
 
 	// Create response with trace
 	metadata := make(map[string]interface{})
+	if timedOut {
+		metadata["terminated_reason"] = "timeout"
+		stopReason = StopTimeout
+	}
+	metadata[StopReasonKey] = stopReason
 	if trace != nil {
 		metadata["reasoning_trace"] = traceToDict(trace)
 		metadata["reasoning_steps"] = len(trace.Steps)
 		metadata["tools_used"] = trace.TotalToolsUsed
 	}
+	if trace != nil || r.toolBudgets != nil {
+		metadata["tool_calls"] = budget.counts()
+	}
+	if retrier.enabled() {
+		metadata["tool_retries"] = retrier.counts()
+	}
+	if r.adaptiveSteps != nil {
+		metadata["step_budget"] = steps.limit
+	}
 
 	return &agenkit.Message{
-		Role:     "assistant",
+		Role:     agenkit.RoleAssistant,
 		Content:  finalAnswer,
 		Metadata: metadata,
 	}, nil
 }
 
+// toolCallKey encodes tool call parameters canonically (map keys sorted),
+// so identical calls compare equal.
+func toolCallKey(parameters map[string]interface{}) string {
+	encoded, err := json.Marshal(parameters)
+	if err != nil {
+		return fmt.Sprint(parameters)
+	}
+	return string(encoded)
+}
+
 // parseToolCall parses tool call from text.
 // Returns a pointer to the tool name (nil if no tool call found), parameters, and remaining text.
 func (r *ReasoningWithToolsAgent) parseToolCall(text string) (*string, map[string]interface{}, string) {
@@ -517,9 +691,13 @@ func traceToDict(trace *ReasoningTrace) map[string]interface{} {
 			"tool_name":       step.ToolName,
 			"tool_parameters": step.ToolParameters,
 			"tool_result":     step.ToolResult,
+			"retries":         step.Retries,
 			"confidence":      step.Confidence,
 			"timestamp":       step.Timestamp,
 		}
+		if step.ToolCallID != "" {
+			steps[i]["tool_call_id"] = step.ToolCallID
+		}
 	}
 
 	durationSeconds := float64(trace.EndTime-trace.StartTime) / 1000.0
@@ -528,6 +706,8 @@ func traceToDict(trace *ReasoningTrace) map[string]interface{} {
 		"steps":                steps,
 		"total_tools_used":     trace.TotalToolsUsed,
 		"total_thinking_steps": trace.TotalThinkingSteps,
+		"tool_calls":           trace.ToolCalls,
+		"tool_retries":         trace.ToolRetries,
 		"duration_seconds":     durationSeconds,
 	}
 }
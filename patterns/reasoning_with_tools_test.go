@@ -2,9 +2,11 @@ package patterns
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
@@ -645,7 +647,7 @@ func TestProcess_ContextCancellation(t *testing.T) {
 		Content: "Test",
 	})
 
-	if err != context.Canceled {
+	if !errors.Is(err, ErrCanceled) {
 		t.Errorf("expected context.Canceled error, got %v", err)
 	}
 }
@@ -671,9 +673,9 @@ func TestProcess_TraceDisabled(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	// Metadata should be empty when trace disabled
-	if len(result.Metadata) > 0 {
-		t.Error("expected empty metadata when trace disabled")
+	// Only the stop reason is recorded when trace disabled
+	if len(result.Metadata) != 1 || GetStopReason(result) == "" {
+		t.Errorf("expected only the stop reason when trace disabled, got %v", result.Metadata)
 	}
 }
 
@@ -751,3 +753,132 @@ func TestProcess_ReasoningTrace(t *testing.T) {
 		t.Error("expected conclusion step in trace")
 	}
 }
+
+// ============================================================================
+// Timeout Tests
+// ============================================================================
+
+func TestProcess_MaxDurationReturnsPartial(t *testing.T) {
+	calls := 0
+	llm := &extendedMockAgent{name: "llm", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		calls++
+		if calls == 1 {
+			return agenkit.NewMessage("assistant", "The interest is roughly 5% per year"), nil
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+
+	agent := NewReasoningWithToolsAgent(llm, nil, &ReasoningWithToolsConfig{
+		MaxDuration: 50 * time.Millisecond,
+	})
+
+	start := time.Now()
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "Calculate interest"))
+	if err != nil {
+		t.Fatalf("expected partial result, got error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected prompt return after timeout, took %v", elapsed)
+	}
+	if result.Metadata["terminated_reason"] != "timeout" {
+		t.Errorf("expected terminated_reason timeout, got %v", result.Metadata["terminated_reason"])
+	}
+	if result.ContentString() != "The interest is roughly 5% per year" {
+		t.Errorf("expected latest reasoning as partial answer, got %q", result.ContentString())
+	}
+}
+
+func TestProcess_ToolBudgets(t *testing.T) {
+	llm := &mockReasoningAgent{
+		name: "test",
+		responses: []string{
+			"TOOL_CALL: search\nPARAMETERS: {}",
+			"TOOL_CALL: search\nPARAMETERS: {}",
+			"FINAL ANSWER: Done",
+		},
+	}
+	search := &mockReasoningTool{name: "search", description: "Paid search", response: "hit"}
+
+	agent := NewReasoningWithToolsAgent(llm, []agenkit.Tool{search}, &ReasoningWithToolsConfig{
+		ToolBudgets: map[string]int{"search": 1},
+	})
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "Test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if search.callCount != 1 {
+		t.Errorf("expected 1 search execution, got %d", search.callCount)
+	}
+	if calls := result.Metadata["tool_calls"].(map[string]int); calls["search"] != 1 {
+		t.Errorf("unexpected tool call counts: %v", calls)
+	}
+	if result.ContentString() != "Done" {
+		t.Errorf("expected final answer, got %q", result.ContentString())
+	}
+}
+
+func TestProcess_ToolBudgetsInTrace(t *testing.T) {
+	llm := &mockReasoningAgent{
+		name: "test",
+		responses: []string{
+			"TOOL_CALL: search\nPARAMETERS: {}",
+			"FINAL ANSWER: Done",
+		},
+	}
+	search := &mockReasoningTool{name: "search", description: "Paid search", response: "hit"}
+
+	agent := NewReasoningWithToolsAgent(llm, []agenkit.Tool{search}, &ReasoningWithToolsConfig{
+		EnableTrace: true,
+		ToolBudgets: map[string]int{"search": 0},
+	})
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "Test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if search.callCount != 0 {
+		t.Errorf("expected zero budget to disable the tool, got %d calls", search.callCount)
+	}
+	trace := result.Metadata["reasoning_trace"].(map[string]interface{})
+	steps := trace["steps"].([]map[string]interface{})
+	if !strings.Contains(steps[0]["content"].(string), "budget exhausted") {
+		t.Errorf("expected budget exhausted step, got %v", steps[0]["content"])
+	}
+	if calls := trace["tool_calls"].(map[string]int); len(calls) != 0 {
+		t.Errorf("expected no recorded calls, got %v", calls)
+	}
+}
+
+func TestReasoningWithTools_StructuredToolCall(t *testing.T) {
+	tool := &paramsTool{}
+	llm := scriptedMessages(
+		agenkit.NewMessage("assistant", "").WithToolCalls(agenkit.ToolCall{
+			ID:        "call_1",
+			Name:      "weather",
+			Arguments: map[string]interface{}{"city": "Paris"},
+		}),
+		agenkit.NewMessage("assistant", "FINAL ANSWER: Sunny"),
+	)
+	agent := NewReasoningWithToolsAgent(llm, []agenkit.Tool{tool}, &ReasoningWithToolsConfig{EnableTrace: true})
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "Weather in Paris?"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tool.params["city"] != "Paris" {
+		t.Errorf("expected the structured arguments passed through, got %v", tool.params)
+	}
+	if result.Metadata["tools_used"] != 1 {
+		t.Errorf("expected one tool used, got %v", result.Metadata["tools_used"])
+	}
+
+	trace := result.Metadata["reasoning_trace"].(map[string]interface{})
+	steps := trace["steps"].([]map[string]interface{})
+	if steps[0]["step_type"] != string(ReasoningStepToolCall) || steps[0]["tool_call_id"] != "call_1" {
+		t.Errorf("expected the tool call traced with its ID, got %v", steps[0])
+	}
+}
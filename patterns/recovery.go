@@ -0,0 +1,212 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ResponseStore keeps the last successful response per input for
+// DefaultRecovery.CachedResponse.
+//
+// Implement this to share cached responses across processes (Redis, etc.).
+type ResponseStore interface {
+	// Get returns the response stored for key, if any.
+	Get(key string) (*agenkit.Message, bool)
+
+	// Put stores response for key, replacing any previous response.
+	Put(key string, response *agenkit.Message)
+}
+
+// MemoryResponseStore is an in-memory ResponseStore that keeps the most
+// recently stored maxEntries responses. Safe for concurrent use.
+type MemoryResponseStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	responses  map[string]*agenkit.Message
+	order      []string
+}
+
+// NewMemoryResponseStore creates an in-memory response store holding at
+// most maxEntries responses (default: 1000).
+func NewMemoryResponseStore(maxEntries int) *MemoryResponseStore {
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	return &MemoryResponseStore{
+		maxEntries: maxEntries,
+		responses:  make(map[string]*agenkit.Message),
+	}
+}
+
+// Get returns the response stored for key.
+func (s *MemoryResponseStore) Get(key string) (*agenkit.Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response, ok := s.responses[key]
+	return response, ok
+}
+
+// Put stores response for key, evicting the oldest entry when full.
+func (s *MemoryResponseStore) Put(key string, response *agenkit.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.responses[key]; ok {
+		for i, k := range s.order {
+			if k == key {
+				s.order = append(s.order[:i], s.order[i+1:]...)
+				break
+			}
+		}
+	} else if len(s.order) >= s.maxEntries {
+		delete(s.responses, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.responses[key] = response
+	s.order = append(s.order, key)
+}
+
+// Len returns the number of stored responses.
+func (s *MemoryResponseStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.responses)
+}
+
+// WithResponseStore records every successful response of the wrapped agent
+// in store, keyed by input content, and returns the agent for chaining.
+// Pair it with DefaultRecovery.CachedResponse(store) to serve the last good
+// answer when the agent fails.
+func (r *RecoveryAgent) WithResponseStore(store ResponseStore) *RecoveryAgent {
+	r.responseStore = store
+	return r
+}
+
+// ChainRecovery returns a RecoveryFunc that tries each recovery in order
+// and returns the first message produced. If every recovery fails, the
+// errors are joined.
+//
+// Example:
+//
+//	recovery := patterns.ChainRecovery(
+//	    patterns.DefaultRecovery.CachedResponse(store),
+//	    patterns.DefaultRecovery.FromMetadata("fallback_response"),
+//	    patterns.DefaultRecovery.StaticMessage("Please try again later."),
+//	)
+func ChainRecovery(recoveries ...RecoveryFunc) RecoveryFunc {
+	return func(ctx context.Context, msg *agenkit.Message, originalError error) (*agenkit.Message, error) {
+		var errs []error
+		for i, recovery := range recoveries {
+			recovered, err := recovery(ctx, msg, originalError)
+			if err == nil && recovered != nil {
+				return recovered, nil
+			}
+			if err == nil {
+				err = errors.New("no response")
+			}
+			errs = append(errs, fmt.Errorf("recovery %d: %w", i, err))
+		}
+		return nil, fmt.Errorf("all %d recoveries failed: %w", len(recoveries), errors.Join(errs...))
+	}
+}
+
+// ErrorClassifyingRecovery returns a RecoveryFunc that picks a recovery by
+// the kind of error, so different failures get different fallbacks.
+//
+// Keys are matched against the original error with errors.Is. If several
+// keys match, the one whose message sorts first wins. Errors matching no
+// key are returned unrecovered; wrap the result in ChainRecovery to add a
+// catch-all.
+//
+// Example:
+//
+//	recovery := patterns.ErrorClassifyingRecovery(map[error]patterns.RecoveryFunc{
+//	    ErrRateLimited: patterns.DefaultRecovery.StaticMessage("We're busy, retrying shortly."),
+//	    ErrUnauthorized: escalateToHuman,
+//	})
+func ErrorClassifyingRecovery(routes map[error]RecoveryFunc) RecoveryFunc {
+	keys := make([]error, 0, len(routes))
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Error() < keys[j].Error()
+	})
+
+	return func(ctx context.Context, msg *agenkit.Message, originalError error) (*agenkit.Message, error) {
+		for _, key := range keys {
+			if errors.Is(originalError, key) {
+				return routes[key](ctx, msg, originalError)
+			}
+		}
+		return nil, fmt.Errorf("no recovery for error: %w", originalError)
+	}
+}
+
+// RecoverIf returns a RecoveryFunc that applies recovery only to errors
+// for which match returns true. Use it for error types that errors.Is
+// can't match, such as middleware.CircuitBreakerError.
+//
+// Example:
+//
+//	circuitOpen := func(err error) bool {
+//	    var cbErr *middleware.CircuitBreakerError
+//	    return errors.As(err, &cbErr)
+//	}
+//	recovery := patterns.ChainRecovery(
+//	    patterns.RecoverIf(circuitOpen, patterns.DefaultRecovery.CachedResponse(store)),
+//	    patterns.DefaultRecovery.StaticMessage("Service temporarily unavailable"),
+//	)
+func RecoverIf(match func(error) bool, recovery RecoveryFunc) RecoveryFunc {
+	return func(ctx context.Context, msg *agenkit.Message, originalError error) (*agenkit.Message, error) {
+		if !match(originalError) {
+			return nil, fmt.Errorf("no recovery for error: %w", originalError)
+		}
+		return recovery(ctx, msg, originalError)
+	}
+}
+
+// cachedResponse returns the response stored for msg as a new message.
+func cachedResponse(store ResponseStore, msg *agenkit.Message, originalError error) (*agenkit.Message, error) {
+	cached, ok := store.Get(responseKey(msg))
+	if !ok || cached == nil {
+		return nil, fmt.Errorf("no cached response: %w", originalError)
+	}
+
+	// Copy so per-request metadata and lineage don't leak into the cache
+	response := agenkit.NewMessage(cached.Role, "")
+	response.Content = cached.Content
+	for key, value := range cached.Metadata {
+		response.Metadata[key] = value
+	}
+	response.Metadata["cached_response"] = true
+	return response, nil
+}
+
+// metadataResponse returns msg.Metadata[key] as an assistant message.
+func metadataResponse(key string, msg *agenkit.Message, originalError error) (*agenkit.Message, error) {
+	if msg == nil || msg.Metadata == nil {
+		return nil, fmt.Errorf("no %q in message metadata: %w", key, originalError)
+	}
+	value, ok := msg.Metadata[key]
+	if !ok || value == nil {
+		return nil, fmt.Errorf("no %q in message metadata: %w", key, originalError)
+	}
+	if content, ok := value.(string); ok {
+		return agenkit.NewMessage(agenkit.RoleAssistant, content), nil
+	}
+	return agenkit.NewMessage(agenkit.RoleAssistant, fmt.Sprint(value)), nil
+}
+
+// responseKey is the ResponseStore key for an input message.
+func responseKey(msg *agenkit.Message) string {
+	if msg == nil {
+		return ""
+	}
+	return msg.Role + "\x00" + msg.ContentString()
+}
@@ -0,0 +1,157 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+var (
+	errRateLimited  = errors.New("rate limited")
+	errUnauthorized = errors.New("unauthorized")
+)
+
+// toggleAgent fails while failing is set.
+type toggleAgent struct {
+	extendedMockAgent
+	failing bool
+}
+
+func (a *toggleAgent) Process(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+	if a.failing {
+		return nil, errors.New("provider down")
+	}
+	return agenkit.NewMessage("assistant", "answer to "+msg.ContentString()), nil
+}
+
+func TestDefaultRecovery_CachedResponse(t *testing.T) {
+	store := NewMemoryResponseStore(10)
+	agent := &toggleAgent{extendedMockAgent: extendedMockAgent{name: "llm"}}
+	recovery := WithRecovery(agent, DefaultRecovery.CachedResponse(store)).WithResponseStore(store)
+	ctx := context.Background()
+
+	if _, err := recovery.Process(ctx, agenkit.NewMessage("user", "q1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	agent.failing = true
+	result, err := recovery.Process(ctx, agenkit.NewMessage("user", "q1"))
+	if err != nil {
+		t.Fatalf("expected cached response, got %v", err)
+	}
+	if result.ContentString() != "answer to q1" || result.Metadata["cached_response"] != true {
+		t.Errorf("unexpected cached response: %q %v", result.ContentString(), result.Metadata)
+	}
+
+	// The cached copy doesn't carry per-request metadata back into the store
+	cached, _ := store.Get(responseKey(agenkit.NewMessage("user", "q1")))
+	if _, ok := cached.Metadata["recovery_used"]; ok {
+		t.Error("expected stored response to be unchanged by recovery")
+	}
+
+	if _, err := recovery.Process(ctx, agenkit.NewMessage("user", "q2")); err == nil {
+		t.Error("expected error for input without a cached response")
+	}
+}
+
+func TestMemoryResponseStore_Evicts(t *testing.T) {
+	store := NewMemoryResponseStore(2)
+	store.Put("a", agenkit.NewMessage("assistant", "1"))
+	store.Put("b", agenkit.NewMessage("assistant", "2"))
+	store.Put("a", agenkit.NewMessage("assistant", "3"))
+	store.Put("c", agenkit.NewMessage("assistant", "4"))
+
+	if _, ok := store.Get("b"); ok {
+		t.Error("expected least recently stored entry to be evicted")
+	}
+	if a, ok := store.Get("a"); !ok || a.ContentString() != "3" {
+		t.Error("expected updated entry to be kept")
+	}
+	if store.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", store.Len())
+	}
+}
+
+func TestDefaultRecovery_FromMetadata(t *testing.T) {
+	recovery := DefaultRecovery.FromMetadata("fallback_response")
+	msg := agenkit.NewMessage("user", "hi").WithMetadata("fallback_response", "Sorry, try later.")
+
+	result, err := recovery(context.Background(), msg, errors.New("boom"))
+	if err != nil || result.ContentString() != "Sorry, try later." {
+		t.Errorf("expected metadata response, got %v, %v", result, err)
+	}
+
+	if _, err := recovery(context.Background(), agenkit.NewMessage("user", "hi"), errors.New("boom")); err == nil {
+		t.Error("expected error when metadata key is missing")
+	}
+}
+
+func TestChainRecovery(t *testing.T) {
+	calls := 0
+	failing := func(ctx context.Context, msg *agenkit.Message, err error) (*agenkit.Message, error) {
+		calls++
+		return nil, errors.New("unavailable")
+	}
+	empty := func(ctx context.Context, msg *agenkit.Message, err error) (*agenkit.Message, error) {
+		calls++
+		return nil, nil
+	}
+
+	recovery := ChainRecovery(failing, empty, DefaultRecovery.StaticMessage("fallback"))
+	result, err := recovery(context.Background(), agenkit.NewMessage("user", "x"), errors.New("boom"))
+	if err != nil || result.ContentString() != "fallback" {
+		t.Errorf("expected static fallback, got %v, %v", result, err)
+	}
+	if calls != 2 {
+		t.Errorf("expected both earlier recoveries tried, got %d calls", calls)
+	}
+
+	_, err = ChainRecovery(failing, empty)(context.Background(), agenkit.NewMessage("user", "x"), errors.New("boom"))
+	if err == nil || !strings.Contains(err.Error(), "all 2 recoveries failed") {
+		t.Errorf("expected joined error, got %v", err)
+	}
+}
+
+func TestErrorClassifyingRecovery(t *testing.T) {
+	recovery := ErrorClassifyingRecovery(map[error]RecoveryFunc{
+		errRateLimited:  DefaultRecovery.StaticMessage("busy, retrying shortly"),
+		errUnauthorized: DefaultRecovery.StaticMessage("escalated to an operator"),
+	})
+	ctx := context.Background()
+	msg := agenkit.NewMessage("user", "x")
+
+	result, err := recovery(ctx, msg, errors.Join(errors.New("call failed"), errRateLimited))
+	if err != nil || result.ContentString() != "busy, retrying shortly" {
+		t.Errorf("expected rate limit fallback, got %v, %v", result, err)
+	}
+
+	agent := WithRecovery(&extendedMockAgent{name: "llm", err: errUnauthorized}, recovery)
+	result, err = agent.Process(ctx, msg)
+	if err != nil || result.ContentString() != "escalated to an operator" {
+		t.Errorf("expected auth fallback, got %v, %v", result, err)
+	}
+
+	if _, err := recovery(ctx, msg, errors.New("other")); err == nil {
+		t.Error("expected unmatched error to be returned")
+	}
+}
+
+func TestRecoverIf(t *testing.T) {
+	isTimeout := func(err error) bool { return errors.Is(err, context.DeadlineExceeded) }
+	recovery := ChainRecovery(
+		RecoverIf(isTimeout, DefaultRecovery.StaticMessage("timed out")),
+		DefaultRecovery.StaticMessage("generic"),
+	)
+
+	result, _ := recovery(context.Background(), nil, context.DeadlineExceeded)
+	if result.ContentString() != "timed out" {
+		t.Errorf("expected timeout fallback, got %q", result.ContentString())
+	}
+	result, _ = recovery(context.Background(), nil, errors.New("other"))
+	if result.ContentString() != "generic" {
+		t.Errorf("expected generic fallback, got %q", result.ContentString())
+	}
+}
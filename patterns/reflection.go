@@ -31,16 +31,13 @@ var (
 	}
 )
 
-// StopReason indicates why the reflection loop stopped.
-type StopReason string
-
+// Reflection-specific stop reasons, reported under "stop_reason". The
+// shared StopReasonKey reports them as StopCompleted or StopMaxIterations.
 const (
 	// StopQualityThreshold indicates quality threshold was met
 	StopQualityThreshold StopReason = "quality_threshold_met"
 	// StopMinimalImprovement indicates improvements became minimal
 	StopMinimalImprovement StopReason = "minimal_improvement"
-	// StopMaxIterations indicates maximum iterations reached
-	StopMaxIterations StopReason = "max_iterations"
 	// StopPerfectScore indicates perfect score (1.0) achieved
 	StopPerfectScore StopReason = "perfect_score"
 )
@@ -95,6 +92,8 @@ type ReflectionAgent struct {
 	critiqueFormat       CritiqueFormat
 	verbose              bool
 	history              []ReflectionStep
+
+	lazy lazyInit
 }
 
 // ReflectionConfig contains configuration for a ReflectionAgent.
@@ -200,15 +199,25 @@ func (r *ReflectionAgent) Capabilities() []string {
 //   - reflection_iterations: Number of iterations performed
 //   - final_quality_score: Final quality score achieved
 //   - stop_reason: Why the loop stopped
+//   - loop_stop_reason: The shared StopReason (see StopReasonKey)
 //   - reflection_history: List of ReflectionStep (if verbose=true)
 //   - initial_quality_score: Quality score of first output
 //   - total_improvement: Improvement from first to final
-func (r *ReflectionAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+func (r *ReflectionAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := r.Init(ctx); err != nil {
+		return nil, err
+	}
+
 	// Reset history for new task (pre-allocate with capacity to avoid reallocations)
 	r.history = make([]ReflectionStep, 0, r.maxIterations)
 
 	// Initial generation
-	output, err := r.generator.Process(ctx, message)
+	output, err := ProcessTraced(ctx, r.generator, message)
 	if err != nil {
 		return nil, fmt.Errorf("initial generation failed: %w", err)
 	}
@@ -220,13 +229,13 @@ func (r *ReflectionAgent) Process(ctx context.Context, message *agenkit.Message)
 		// Check context cancellation
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return nil, contextError(ctx)
 		default:
 		}
 
 		// Critique current output
 		critiqueMsg := r.buildCritiquePrompt(message.ContentString(), output.ContentString())
-		critiqueResponse, err := r.critic.Process(ctx, critiqueMsg)
+		critiqueResponse, err := ProcessTraced(ctx, r.critic, critiqueMsg)
 		if err != nil {
 			return nil, fmt.Errorf("critique failed at iteration %d: %w", iteration, err)
 		}
@@ -261,7 +270,7 @@ func (r *ReflectionAgent) Process(ctx context.Context, message *agenkit.Message)
 
 		// Refine based on critique
 		refineMsg := r.buildRefinementPrompt(message.ContentString(), output.ContentString(), feedback, iteration)
-		output, err = r.generator.Process(ctx, refineMsg)
+		output, err = ProcessTraced(ctx, r.generator, refineMsg)
 		if err != nil {
 			return nil, fmt.Errorf("refinement failed at iteration %d: %w", iteration, err)
 		}
@@ -293,7 +302,7 @@ func (r *ReflectionAgent) buildCritiquePrompt(originalQuery, currentOutput strin
 		b.WriteString("\n\nProvide:\n1. A score (0.0-1.0) indicating quality\n2. Specific feedback on what could be improved\n\nYour evaluation:")
 	}
 
-	return agenkit.NewMessage("user", b.String())
+	return agenkit.NewMessage(agenkit.RoleUser, b.String())
 }
 
 // buildRefinementPrompt creates a prompt for the generator to refine output.
@@ -312,7 +321,7 @@ func (r *ReflectionAgent) buildRefinementPrompt(originalQuery, currentOutput, cr
 	b.WriteString(critique)
 	b.WriteString("\n\nPlease provide an improved version that addresses the critique while maintaining what was already good.\n\nRefined Output:")
 
-	return agenkit.NewMessage("user", b.String())
+	return agenkit.NewMessage(agenkit.RoleUser, b.String())
 }
 
 // parseCritique parses the critic's response into score and feedback.
@@ -434,6 +443,11 @@ func (r *ReflectionAgent) formatResult(output *agenkit.Message, stopReason StopR
 
 	metadata["reflection_iterations"] = len(r.history)
 	metadata["stop_reason"] = string(stopReason)
+	if stopReason == StopMaxIterations {
+		metadata[StopReasonKey] = StopMaxIterations
+	} else {
+		metadata[StopReasonKey] = StopCompleted
+	}
 
 	if len(r.history) > 0 {
 		metadata["final_quality_score"] = r.history[len(r.history)-1].QualityScore
@@ -3,6 +3,7 @@ package patterns
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -630,7 +631,7 @@ func TestReflectionContextCancellation(t *testing.T) {
 	msg := agenkit.NewMessage("user", "Write a test")
 
 	_, err := agent.Process(ctx, msg)
-	if err != context.Canceled {
+	if !errors.Is(err, ErrCanceled) {
 		t.Errorf("expected context.Canceled error, got %v", err)
 	}
 }
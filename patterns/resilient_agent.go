@@ -0,0 +1,187 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Resilient agent pattern wraps an agent with timeout, retries, circuit
+// breaker and cost budget in one configuration.
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+	"github.com/scttfrdmn/agenkit-go/middleware"
+)
+
+// ErrCostBudgetExceeded is returned (wrapped) when a ResilientAgent's cost
+// budget is spent.
+var ErrCostBudgetExceeded = errors.New("cost budget exceeded")
+
+// ResilienceConfig configures a ResilientAgent. The zero value applies no
+// protection.
+type ResilienceConfig struct {
+	// Timeout bounds each attempt (0 means no timeout)
+	Timeout time.Duration
+	// Retries is the number of retries after a failed first attempt
+	// (0 means no retries)
+	Retries int
+	// Backoff is the delay before the first retry, doubling on each
+	// further retry (default: 100ms)
+	Backoff time.Duration
+	// CircuitBreaker, if set, fails calls fast once the agent keeps
+	// failing. Every attempt counts toward it, and retries stop once the
+	// circuit opens. Its Timeout defaults to the Timeout above, if set.
+	CircuitBreaker *middleware.CircuitBreakerConfig
+	// CostBudget caps the total cost, summed from the "cost" metadata of
+	// the agent's responses, across every call (0 means unlimited). Once
+	// spent, calls fail with ErrCostBudgetExceeded.
+	CostBudget float64
+}
+
+// ResilientAgent hardens an agent with timeouts, retries, a circuit breaker
+// and a cost budget. Unlike Task, which applies timeout and retry to a
+// single execution, a ResilientAgent is an ordinary reusable Agent, so it
+// can be embedded in any composition (Sequential, Router, Fallback and so
+// on). The circuit breaker and cost budget persist across calls. Safe for
+// concurrent use.
+//
+// Example:
+//
+//	hardened := patterns.NewResilientAgent(llmAgent, patterns.ResilienceConfig{
+//	    Timeout:        10 * time.Second,
+//	    Retries:        2,
+//	    CircuitBreaker: &middleware.CircuitBreakerConfig{FailureThreshold: 5},
+//	    CostBudget:     1.50,
+//	})
+//	pipeline, _ := patterns.NewSequentialAgent([]agenkit.Agent{hardened, formatter})
+type ResilientAgent struct {
+	agent   agenkit.Agent
+	chain   agenkit.Agent
+	breaker *middleware.CircuitBreakerDecorator
+	budget  float64
+
+	mu    sync.Mutex
+	spent float64
+
+	lazy lazyInit
+}
+
+// NewResilientAgent wraps agent with the protections in config.
+//
+// Attempts are layered as: timeout innermost, then the circuit breaker,
+// then retries, so each attempt is timed and counted by the breaker
+// individually, and a call is refused up front once the cost budget is
+// spent.
+func NewResilientAgent(agent agenkit.Agent, config ResilienceConfig) *ResilientAgent {
+	chain := agent
+	if config.Timeout > 0 {
+		chain = middleware.NewTimeoutDecorator(chain, middleware.TimeoutConfig{Timeout: config.Timeout})
+	}
+
+	var breaker *middleware.CircuitBreakerDecorator
+	if config.CircuitBreaker != nil {
+		breakerConfig := *config.CircuitBreaker
+		if breakerConfig.Timeout <= 0 {
+			breakerConfig.Timeout = config.Timeout
+		}
+		breaker = middleware.NewCircuitBreakerDecorator(chain, breakerConfig)
+		chain = breaker
+	}
+
+	if config.Retries > 0 {
+		chain = middleware.NewRetryDecorator(chain, middleware.RetryConfig{
+			MaxRetries:        config.Retries + 1, // counts the first attempt
+			InitialRetryDelay: config.Backoff,
+			ShouldRetry:       shouldRetryResilient,
+		})
+	}
+
+	return &ResilientAgent{
+		agent:   agent,
+		chain:   chain,
+		breaker: breaker,
+		budget:  config.CostBudget,
+	}
+}
+
+// shouldRetryResilient reports whether a failed attempt is worth retrying:
+// not once the circuit is open or the caller has given up.
+func shouldRetryResilient(err error) bool {
+	var open *middleware.CircuitBreakerError
+	if errors.As(err, &open) {
+		return false
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+// Name returns the wrapped agent's name.
+func (r *ResilientAgent) Name() string {
+	return r.agent.Name()
+}
+
+// Capabilities returns the wrapped agent's capabilities.
+func (r *ResilientAgent) Capabilities() []string {
+	return r.agent.Capabilities()
+}
+
+// Introspect returns introspection information for the agent.
+func (r *ResilientAgent) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    r.Name(),
+		Capabilities: r.Capabilities(),
+	}
+}
+
+// Spent returns the cost charged against the budget so far.
+func (r *ResilientAgent) Spent() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.spent
+}
+
+// CircuitState returns the circuit breaker's state, or StateClosed if no
+// circuit breaker is configured.
+func (r *ResilientAgent) CircuitState() middleware.CircuitState {
+	if r.breaker == nil {
+		return middleware.StateClosed
+	}
+	return r.breaker.State()
+}
+
+// Process runs message through the wrapped agent with the configured
+// protections, charging the response's "cost" metadata to the budget.
+//
+// Returns an error wrapping ErrCostBudgetExceeded if the budget is already
+// spent, a *middleware.CircuitBreakerError if the circuit is open, or the
+// last attempt's error (wrapped) once retries run out.
+func (r *ResilientAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := r.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	if r.budget > 0 {
+		if spent := r.Spent(); spent >= r.budget {
+			return nil, fmt.Errorf("%w: spent %.4f of %.4f", ErrCostBudgetExceeded, spent, r.budget)
+		}
+	}
+
+	result, err := ProcessTraced(ctx, r.chain, message)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		if cost, ok := metadataFloat(result.Metadata, "cost"); ok {
+			r.mu.Lock()
+			r.spent += cost
+			r.mu.Unlock()
+		}
+	}
+	return result, nil
+}
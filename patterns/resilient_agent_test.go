@@ -0,0 +1,148 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+	"github.com/scttfrdmn/agenkit-go/middleware"
+)
+
+func TestResilientAgent_RetriesUntilSuccess(t *testing.T) {
+	var calls int32
+	agent := &extendedMockAgent{
+		name: "flaky",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return nil, errors.New("transient")
+			}
+			return agenkit.NewMessage("agent", "ok"), nil
+		},
+	}
+
+	resilient := NewResilientAgent(agent, ResilienceConfig{Retries: 2, Backoff: time.Millisecond})
+	result, err := resilient.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != "ok" || calls != 3 {
+		t.Errorf("got %q after %d calls, want ok after 3", result.Content, calls)
+	}
+	if resilient.Name() != "flaky" {
+		t.Errorf("Name() = %q, want flaky", resilient.Name())
+	}
+}
+
+func TestResilientAgent_IsReusable(t *testing.T) {
+	resilient := NewResilientAgent(&extendedMockAgent{name: "a", response: "done"}, ResilienceConfig{Retries: 1})
+	for i := 0; i < 3; i++ {
+		if _, err := resilient.Process(context.Background(), agenkit.NewMessage("user", "hi")); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+func TestResilientAgent_TimeoutPerAttempt(t *testing.T) {
+	var calls int32
+	agent := &extendedMockAgent{
+		name: "slow",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return agenkit.NewMessage("agent", "fast"), nil
+		},
+	}
+
+	resilient := NewResilientAgent(agent, ResilienceConfig{
+		Timeout: 20 * time.Millisecond,
+		Retries: 1,
+		Backoff: time.Millisecond,
+	})
+	result, err := resilient.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != "fast" {
+		t.Errorf("content = %q, want fast", result.Content)
+	}
+}
+
+func TestResilientAgent_CircuitOpensAndStopsRetries(t *testing.T) {
+	var calls int32
+	agent := &extendedMockAgent{
+		name: "down",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, errors.New("unavailable")
+		},
+	}
+
+	resilient := NewResilientAgent(agent, ResilienceConfig{
+		Retries:        5,
+		Backoff:        time.Millisecond,
+		CircuitBreaker: &middleware.CircuitBreakerConfig{FailureThreshold: 2, RecoveryTimeout: time.Hour},
+	})
+	_, err := resilient.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	var open *middleware.CircuitBreakerError
+	if !errors.As(err, &open) {
+		t.Fatalf("expected CircuitBreakerError, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("agent called %d times, want 2 before the circuit opened", calls)
+	}
+	if resilient.CircuitState() != middleware.StateOpen {
+		t.Errorf("CircuitState() = %v, want open", resilient.CircuitState())
+	}
+
+	// The open circuit persists across calls
+	if _, err := resilient.Process(context.Background(), agenkit.NewMessage("user", "again")); !errors.As(err, &open) {
+		t.Errorf("expected CircuitBreakerError on the next call, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("agent called %d times, want no calls while open", calls)
+	}
+}
+
+func TestResilientAgent_CostBudget(t *testing.T) {
+	agent := &extendedMockAgent{
+		name: "priced",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			return agenkit.NewMessage("agent", "answer").WithMetadata("cost", 0.4), nil
+		},
+	}
+
+	resilient := NewResilientAgent(agent, ResilienceConfig{CostBudget: 1.0})
+	for i := 0; i < 3; i++ {
+		if _, err := resilient.Process(context.Background(), agenkit.NewMessage("user", "hi")); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+	}
+	if spent := resilient.Spent(); spent < 1.19 || spent > 1.21 {
+		t.Errorf("Spent() = %v, want 1.2", spent)
+	}
+
+	_, err := resilient.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	if !errors.Is(err, ErrCostBudgetExceeded) {
+		t.Errorf("expected ErrCostBudgetExceeded, got %v", err)
+	}
+}
+
+func TestResilientAgent_InSequential(t *testing.T) {
+	resilient := NewResilientAgent(&extendedMockAgent{name: "first", response: "step one"}, ResilienceConfig{Retries: 1})
+	pipeline, err := NewSequentialAgent([]agenkit.Agent{resilient, &extendedMockAgent{name: "second", response: "step two"}})
+	if err != nil {
+		t.Fatalf("NewSequentialAgent: %v", err)
+	}
+	result, err := pipeline.Process(context.Background(), agenkit.NewMessage("user", "hi"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != "step two" {
+		t.Errorf("content = %q, want step two", result.Content)
+	}
+}
@@ -9,6 +9,8 @@
 //   - Conditional routing to specialists
 //   - Single agent execution per request
 //   - Dynamic agent selection based on input
+//   - Nested routers share a depth limit and record their decisions in a
+//     "routing_path" metadata list
 //
 // Performance characteristics:
 //   - Time: O(classification + selected agent)
@@ -18,12 +20,42 @@ package patterns
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
 
+// DefaultMaxRoutingDepth is the default maximum number of nested routers a
+// message may pass through.
+const DefaultMaxRoutingDepth = 10
+
+// ErrMaxRoutingDepth is returned (wrapped) when a message passes through
+// more nested routers than allowed, usually because routers form a cycle.
+var ErrMaxRoutingDepth = errors.New("max routing depth exceeded")
+
+// routingPathKey is the private context key for the decisions made by
+// enclosing routers.
+type routingPathKey struct{}
+
+// routingPathFromContext returns the decisions made by enclosing routers,
+// outermost first.
+func routingPathFromContext(ctx context.Context) []map[string]interface{} {
+	path, _ := ctx.Value(routingPathKey{}).([]map[string]interface{})
+	return path
+}
+
+// formatRoutingPath renders decisions as "router:category -> ...".
+func formatRoutingPath(path []map[string]interface{}) string {
+	steps := make([]string, len(path))
+	for i, decision := range path {
+		steps[i] = fmt.Sprintf("%v:%v", decision["router"], decision["category"])
+	}
+	return strings.Join(steps, " -> ")
+}
+
 // ClassifierAgent is responsible for determining routing decisions.
 //
 // The classifier analyzes the input message and returns a category/intent
@@ -55,6 +87,11 @@ type RouterAgent struct {
 	classifier ClassifierAgent
 	agents     map[string]agenkit.Agent
 	defaultKey string
+	maxDepth   int
+	logger     *slog.Logger
+	deadLetter agenkit.DeadLetterSink
+
+	lazy lazyInit
 }
 
 // RouterConfig configures a RouterAgent.
@@ -65,6 +102,16 @@ type RouterConfig struct {
 	Agents map[string]agenkit.Agent
 	// DefaultKey specifies fallback agent when classification doesn't match (optional)
 	DefaultKey string
+	// Name identifies the router in logs and routing paths (default: "RouterAgent")
+	Name string
+	// MaxDepth is the maximum number of nested routers, including this one,
+	// a message may pass through (default: DefaultMaxRoutingDepth)
+	MaxDepth int
+	// Logger receives structured routing events (default: package logger)
+	Logger *slog.Logger
+	// DeadLetters receives messages whose category has no agent when there
+	// is no DefaultKey (optional)
+	DeadLetters agenkit.DeadLetterSink
 }
 
 // NewRouterAgent creates a new router agent.
@@ -93,11 +140,26 @@ func NewRouterAgent(config *RouterConfig) (*RouterAgent, error) {
 		}
 	}
 
+	name := config.Name
+	if name == "" {
+		name = "RouterAgent"
+	}
+	maxDepth := config.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxRoutingDepth
+	}
+	if maxDepth < 1 {
+		return nil, fmt.Errorf("max depth must be positive")
+	}
+
 	return &RouterAgent{
-		name:       "RouterAgent",
+		name:       name,
 		classifier: config.Classifier,
 		agents:     config.Agents,
 		defaultKey: config.DefaultKey,
+		maxDepth:   maxDepth,
+		logger:     config.Logger,
+		deadLetter: config.DeadLetters,
 	}, nil
 }
 
@@ -131,12 +193,13 @@ func (r *RouterAgent) Capabilities() []string {
 	return capabilities
 }
 
-// Introspect returns introspection information for the router agent.
+// Introspect reports the router as a whole: the union of its agents'
+// capabilities, with the classifier and the agents by category under
+// TopologyKey.
 func (r *RouterAgent) Introspect() *agenkit.IntrospectionResult {
-	return &agenkit.IntrospectionResult{
-		AgentName:    r.Name(),
-		Capabilities: r.Capabilities(),
-	}
+	components := []topologyComponent{{agent: r.classifier, position: map[string]interface{}{"role": "classifier"}}}
+	components = append(components, routeComponents(r.agents, nil, r.defaultKey)...)
+	return introspectComposition(r.Name(), TopologyRouter, r.Capabilities(), components)
 }
 
 // Process classifies the message and routes to appropriate agent.
@@ -147,12 +210,29 @@ func (r *RouterAgent) Introspect() *agenkit.IntrospectionResult {
 //  3. Execution: Delegate to selected agent
 //
 // If classification fails, an error is returned. If the classified category
-// doesn't match any agent and no default is configured, an error is returned.
+// doesn't match any agent and no default is configured, an error is returned
+// and the message is written to the DeadLetters sink, if configured.
+// If the message is already inside MaxDepth nested routers (for example
+// because routers route to each other in a cycle), ErrMaxRoutingDepth is
+// returned before classifying.
 //
-// The final message includes metadata about the routing decision.
-func (r *RouterAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	if message == nil {
-		return nil, fmt.Errorf("message cannot be nil")
+// The final message includes metadata about the routing decision, and
+// "routing_path" lists the decision of every router the message passed
+// through, outermost first, as maps with "router", "category" and "agent".
+func (r *RouterAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := r.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	path := routingPathFromContext(ctx)
+	if len(path) >= r.maxDepth {
+		return nil, fmt.Errorf("%w: %s reached at depth %d (path: %s)",
+			ErrMaxRoutingDepth, r.name, len(path)+1, formatRoutingPath(path))
 	}
 
 	// Step 1: Classify the message
@@ -163,9 +243,12 @@ func (r *RouterAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 
 	// Step 2: Select agent based on category
 	agent, ok := r.agents[category]
-	if !ok {
+	if ok {
+		AnnotateTrace(ctx, fmt.Sprintf("classified the request as %q", category))
+	} else {
 		// Try default agent if configured
 		if r.defaultKey != "" {
+			AnnotateTrace(ctx, fmt.Sprintf("classified the request as %q, which has no route, and used the default %q", category, r.defaultKey))
 			agent = r.agents[r.defaultKey]
 			category = r.defaultKey // Update category to reflect actual routing
 		} else {
@@ -173,14 +256,27 @@ func (r *RouterAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 			for cat := range r.agents {
 				availableCategories = append(availableCategories, cat)
 			}
-			return nil, fmt.Errorf("no agent found for category '%s' (available: %s)",
+			err := fmt.Errorf("no agent found for category '%s' (available: %s)",
 				category, strings.Join(availableCategories, ", "))
+			writeDeadLetter(ctx, r.deadLetter, r.name, agenkit.DeadLetterUnroutable, message, err,
+				map[string]interface{}{"category": category})
+			return nil, err
 		}
 	}
 
-	// Step 3: Execute selected agent
-	result, err := agent.Process(ctx, message)
+	// Step 3: Execute selected agent, telling nested routers how we got here
+	decision := map[string]interface{}{
+		"router":   r.name,
+		"category": category,
+		"agent":    agent.Name(),
+	}
+	childPath := append(append(make([]map[string]interface{}, 0, len(path)+1), path...), decision)
+
+	logger := resolveLogger(r.logger).With(slog.String("pattern", r.name), slog.String("agent", agent.Name()))
+	logger.DebugContext(ctx, LogEventRoute, slog.String("category", category), slog.Int("depth", len(childPath)))
+	result, err := ProcessTraced(context.WithValue(ctx, routingPathKey{}, childPath), agent, message)
 	if err != nil {
+		logger.WarnContext(ctx, LogEventAgentError, slog.Any("error", err))
 		return nil, fmt.Errorf("agent '%s' (category: %s) failed: %w",
 			agent.Name(), category, err)
 	}
@@ -193,6 +289,10 @@ func (r *RouterAgent) Process(ctx context.Context, message *agenkit.Message) (*a
 	result.Metadata["routed_agent"] = agent.Name()
 	result.Metadata["available_routes"] = len(r.agents)
 
+	// Prepend our decision to those of any nested routers
+	nested, _ := result.Metadata["routing_path"].([]map[string]interface{})
+	result.Metadata["routing_path"] = append([]map[string]interface{}{decision}, nested...)
+
 	return result, nil
 }
 
@@ -229,8 +329,14 @@ func (c *SimpleClassifier) Capabilities() []string {
 }
 
 // Process handles direct message processing (delegates to underlying agent).
-func (c *SimpleClassifier) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	return c.agent.Process(ctx, message)
+func (c *SimpleClassifier) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+
+	return ProcessTraced(ctx, c.agent, message)
 }
 
 // Introspect returns introspection information for the classifier.
@@ -244,7 +350,7 @@ func (c *SimpleClassifier) Introspect() *agenkit.IntrospectionResult {
 // Classify determines category using keyword matching.
 func (c *SimpleClassifier) Classify(ctx context.Context, message *agenkit.Message) (string, error) {
 	if message == nil {
-		return "", fmt.Errorf("message cannot be nil")
+		return "", ErrNilMessage
 	}
 
 	content := strings.ToLower(message.ContentString())
@@ -281,7 +387,9 @@ func (c *SimpleClassifier) Classify(ctx context.Context, message *agenkit.Messag
 type LLMClassifier struct {
 	agent      agenkit.Agent
 	categories []string
-	prompt     string
+	template   *agenkit.PromptTemplate
+
+	lazy lazyInit
 }
 
 // NewLLMClassifier creates an LLM-based classifier.
@@ -289,25 +397,32 @@ type LLMClassifier struct {
 // Parameters:
 //   - agent: LLM agent for classification
 //   - categories: List of valid category names
-//   - prompt: Optional custom prompt template
+//
+// The prompt defaults to DefaultClassifierPrompt; use WithPromptTemplate
+// to override it.
 func NewLLMClassifier(agent agenkit.Agent, categories []string) *LLMClassifier {
 	if len(categories) == 0 {
 		categories = []string{"general"}
 	}
 
-	prompt := fmt.Sprintf(`Classify the following message into one of these categories: %s
-
-Reply with ONLY the category name, nothing else.
-
-Message: `, strings.Join(categories, ", "))
-
 	return &LLMClassifier{
 		agent:      agent,
 		categories: categories,
-		prompt:     prompt,
+		template:   DefaultClassifierPrompt,
 	}
 }
 
+// WithPromptTemplate sets the classification prompt template and returns
+// the classifier for chaining. Data: ClassifierPromptData. Passing nil
+// restores DefaultClassifierPrompt.
+func (c *LLMClassifier) WithPromptTemplate(template *agenkit.PromptTemplate) *LLMClassifier {
+	if template == nil {
+		template = DefaultClassifierPrompt
+	}
+	c.template = template
+	return c
+}
+
 // Name returns the classifier's identifier.
 func (c *LLMClassifier) Name() string {
 	return "LLMClassifier"
@@ -319,8 +434,17 @@ func (c *LLMClassifier) Capabilities() []string {
 }
 
 // Process handles direct message processing (delegates to underlying agent).
-func (c *LLMClassifier) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	return c.agent.Process(ctx, message)
+func (c *LLMClassifier) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	return ProcessTraced(ctx, c.agent, message)
 }
 
 // Introspect returns introspection information for the classifier.
@@ -334,14 +458,21 @@ func (c *LLMClassifier) Introspect() *agenkit.IntrospectionResult {
 // Classify uses LLM to determine category.
 func (c *LLMClassifier) Classify(ctx context.Context, message *agenkit.Message) (string, error) {
 	if message == nil {
-		return "", fmt.Errorf("message cannot be nil")
+		return "", ErrNilMessage
 	}
 
 	// Build classification prompt
-	classificationMsg := agenkit.NewMessage("user", c.prompt+message.ContentString())
+	prompt, err := c.template.Render(ClassifierPromptData{
+		Categories: c.categories,
+		Message:    message.ContentString(),
+	})
+	if err != nil {
+		return "", err
+	}
+	classificationMsg := agenkit.NewMessage(agenkit.RoleUser, prompt)
 
 	// Get LLM classification
-	result, err := c.agent.Process(ctx, classificationMsg)
+	result, err := ProcessTraced(ctx, c.agent, classificationMsg)
 	if err != nil {
 		return "", fmt.Errorf("llm classification failed: %w", err)
 	}
@@ -276,6 +276,44 @@ func TestRouterAgent_UnknownCategoryNoDefault(t *testing.T) {
 	}
 }
 
+// TestRouterAgent_DeadLettersUnroutable tests capturing unroutable messages
+func TestRouterAgent_DeadLettersUnroutable(t *testing.T) {
+	sink := agenkit.NewMemoryDeadLetterSink(0)
+	router, err := NewRouterAgent(&RouterConfig{
+		Classifier:  &mockClassifier{name: "classifier", category: "refunds"},
+		Agents:      map[string]agenkit.Agent{"billing": &extendedMockAgent{name: "billing"}},
+		DeadLetters: sink,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := agenkit.NewMessage("user", "where is my refund?")
+	_, err = router.Process(context.Background(), msg)
+	if err == nil {
+		t.Fatal("expected error for unknown category")
+	}
+
+	letters := sink.Letters()
+	if len(letters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(letters))
+	}
+	letter := letters[0]
+	if letter.Message != msg || letter.Reason != agenkit.DeadLetterUnroutable || letter.Source != "RouterAgent" ||
+		letter.Err != err || letter.Metadata["category"] != "refunds" {
+		t.Errorf("unexpected dead letter %+v", letter)
+	}
+
+	// Routable messages are not dead-lettered
+	router.classifier = &mockClassifier{name: "classifier", category: "billing"}
+	if _, err := router.Process(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sink.Letters()) != 1 {
+		t.Error("expected a routed message not to be dead-lettered")
+	}
+}
+
 // TestRouterAgent_UnknownCategoryWithDefault tests fallback to default agent
 func TestRouterAgent_UnknownCategoryWithDefault(t *testing.T) {
 	classifier := &mockClassifier{
@@ -546,3 +584,79 @@ func TestLLMClassifier_LLMError(t *testing.T) {
 		t.Errorf("expected LLM error, got: %v", err)
 	}
 }
+
+// TestRouterAgent_NestedRoutingPath tests routing_path across nested routers
+func TestRouterAgent_NestedRoutingPath(t *testing.T) {
+	inner, err := NewRouterAgent(&RouterConfig{
+		Name:       "tech",
+		Classifier: &mockClassifier{name: "c2", category: "hardware"},
+		Agents: map[string]agenkit.Agent{
+			"hardware": &extendedMockAgent{name: "hw", response: "fixed"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	outer, err := NewRouterAgent(&RouterConfig{
+		Name:       "main",
+		Classifier: &mockClassifier{name: "c1", category: "technical"},
+		Agents:     map[string]agenkit.Agent{"technical": inner},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := outer.Process(context.Background(), agenkit.NewMessage("user", "keyboard broken"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path, ok := result.Metadata["routing_path"].([]map[string]interface{})
+	if !ok || len(path) != 2 {
+		t.Fatalf("expected 2-step routing path, got %v", result.Metadata["routing_path"])
+	}
+	if path[0]["router"] != "main" || path[0]["category"] != "technical" || path[0]["agent"] != "tech" {
+		t.Errorf("unexpected outer decision: %v", path[0])
+	}
+	if path[1]["router"] != "tech" || path[1]["category"] != "hardware" || path[1]["agent"] != "hw" {
+		t.Errorf("unexpected inner decision: %v", path[1])
+	}
+	// The outermost router's metadata wins for the flat keys
+	if result.Metadata["routed_category"] != "technical" {
+		t.Errorf("expected outer routed_category, got %v", result.Metadata["routed_category"])
+	}
+}
+
+// TestRouterAgent_CycleHitsMaxDepth tests that routers routing to each other stop
+func TestRouterAgent_CycleHitsMaxDepth(t *testing.T) {
+	routerA, _ := NewRouterAgent(&RouterConfig{
+		Name:       "A",
+		Classifier: &mockClassifier{name: "ca", category: "b"},
+		Agents:     map[string]agenkit.Agent{"placeholder": &extendedMockAgent{name: "p"}},
+		MaxDepth:   5,
+	})
+	routerB, _ := NewRouterAgent(&RouterConfig{
+		Name:       "B",
+		Classifier: &mockClassifier{name: "cb", category: "a"},
+		Agents:     map[string]agenkit.Agent{"placeholder": &extendedMockAgent{name: "p"}},
+	})
+	// Wire the cycle after construction: A routes to B, B back to A
+	routerA.agents = map[string]agenkit.Agent{"b": routerB}
+	routerB.agents = map[string]agenkit.Agent{"a": routerA}
+
+	_, err := routerA.Process(context.Background(), agenkit.NewMessage("user", "loop"))
+	if !errors.Is(err, ErrMaxRoutingDepth) {
+		t.Fatalf("expected ErrMaxRoutingDepth, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "A:b -> B:a -> A:b -> B:a -> A:b -> B:a") {
+		t.Errorf("expected routing path in error, got %v", err)
+	}
+
+	if _, err := NewRouterAgent(&RouterConfig{
+		Classifier: &mockClassifier{name: "c"},
+		Agents:     map[string]agenkit.Agent{"x": &extendedMockAgent{name: "x"}},
+		MaxDepth:   -1,
+	}); err == nil {
+		t.Error("expected error for negative max depth")
+	}
+}
@@ -0,0 +1,281 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Scatter-gather pattern splits a message into items, processes each with
+// an agent concurrently and aggregates the results.
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ErrNoScatterItems is returned when a ScatterGatherAgent's splitter
+// produces no items for the input.
+var ErrNoScatterItems = errors.New("splitter produced no items")
+
+// ScatterItem is one piece of a scattered input.
+type ScatterItem struct {
+	// Key identifies the piece (e.g. "section-2") and is recorded on its
+	// result as metadata "scatter_key"
+	Key string
+	// Message is the sub-message sent to the piece's agent
+	Message *agenkit.Message
+}
+
+// ScatterSplitter splits an input message into the pieces to scatter.
+type ScatterSplitter func(message *agenkit.Message) []ScatterItem
+
+// ScatterRouter picks the agent that processes a piece.
+type ScatterRouter func(item ScatterItem) agenkit.Agent
+
+// ScatterGatherAgent splits its input into sub-messages, processes each
+// with its own agent concurrently, and gathers the results.
+//
+// Where ParallelAgent sends the same input to every agent, scatter-gather
+// sends each agent a tailored piece, such as one document section per
+// summarizer. It is the map-reduce shape of the Parallel pattern.
+//
+// Failures follow ParallelAgent: a failed piece is recorded for
+// GetPartialFailures, with its item key, and the gatherer receives the
+// successful results in item order. If every piece fails, Process returns an
+// *AllAgentsFailedError.
+//
+// Example:
+//
+//	sg, err := patterns.NewScatterGather(
+//	    func(msg *agenkit.Message) []patterns.ScatterItem {
+//	        var items []patterns.ScatterItem
+//	        for i, section := range strings.Split(msg.ContentString(), "\n\n") {
+//	            items = append(items, patterns.ScatterItem{
+//	                Key:     fmt.Sprintf("section-%d", i),
+//	                Message: agenkit.NewMessage("user", section),
+//	            })
+//	        }
+//	        return items
+//	    },
+//	    func(item patterns.ScatterItem) agenkit.Agent { return summarizer },
+//	    patterns.DefaultAggregators.Concatenate,
+//	)
+type ScatterGatherAgent struct {
+	name      string
+	splitter  ScatterSplitter
+	agentFor  ScatterRouter
+	gatherer  AggregatorFunc
+	group     *WorkGroup
+	scheduler *FairScheduler
+}
+
+// NewScatterGather creates a scatter-gather agent.
+//
+// Parameters:
+//   - splitter: Splits the input into pieces
+//   - agentFor: Picks the agent for each piece
+//   - gatherer: Combines the pieces' results into the final output
+func NewScatterGather(splitter ScatterSplitter, agentFor ScatterRouter, gatherer AggregatorFunc) (*ScatterGatherAgent, error) {
+	if splitter == nil {
+		return nil, fmt.Errorf("splitter function is required")
+	}
+	if agentFor == nil {
+		return nil, fmt.Errorf("agent selection function is required")
+	}
+	if gatherer == nil {
+		return nil, fmt.Errorf("gatherer function is required")
+	}
+
+	return &ScatterGatherAgent{
+		name:     "ScatterGatherAgent",
+		splitter: splitter,
+		agentFor: agentFor,
+		gatherer: gatherer,
+	}, nil
+}
+
+// WithWorkGroup runs the pieces as units of group, so an in-flight
+// Process can be drained by group.Shutdown, and returns the agent for
+// chaining.
+func (s *ScatterGatherAgent) WithWorkGroup(group *WorkGroup) *ScatterGatherAgent {
+	s.group = group
+	return s
+}
+
+// WithScheduler runs each piece in a slot from scheduler, under the input
+// message's tenant, and returns the agent for chaining.
+func (s *ScatterGatherAgent) WithScheduler(scheduler *FairScheduler) *ScatterGatherAgent {
+	s.scheduler = scheduler
+	return s
+}
+
+// Name returns the agent's identifier.
+func (s *ScatterGatherAgent) Name() string {
+	return s.name
+}
+
+// Capabilities returns the pattern's capabilities.
+func (s *ScatterGatherAgent) Capabilities() []string {
+	return []string{"scatter_gather", "parallel"}
+}
+
+// Introspect returns introspection information for the agent.
+func (s *ScatterGatherAgent) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    s.Name(),
+		Capabilities: s.Capabilities(),
+	}
+}
+
+// Process splits message, processes every piece concurrently and gathers
+// the results.
+//
+// Each piece's message without a parent is linked to the input, and each
+// result carries metadata "scatter_key". The final message includes
+// metadata "scatter_items", "successful_items", "failed_items" and, if
+// any piece failed, PartialFailuresKey and "errors" (key, agent and error
+// per failure). CarryThroughKeys the
+// gathered message lacks are taken from the results in item order.
+//
+// Returns ErrNoScatterItems if the splitter produces no items, and an
+// error before any agent runs if an item has no message or no agent.
+func (s *ScatterGatherAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+
+	items := s.splitter(message)
+	if len(items) == 0 {
+		return nil, ErrNoScatterItems
+	}
+	agents := make([]agenkit.Agent, len(items))
+	for i, item := range items {
+		if item.Message == nil {
+			return nil, fmt.Errorf("scatter item %d (%q) has no message", i, item.Key)
+		}
+		agents[i] = s.agentFor(item)
+		if agents[i] == nil {
+			return nil, fmt.Errorf("no agent for scatter item %d (%q)", i, item.Key)
+		}
+		items[i].Message = linkToInput(message, item.Message)
+	}
+
+	// Cancel in-flight pieces if we return early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resultsCh := make(chan agentResult, len(items))
+
+	tenant := s.scheduler.TenantOf(ctx, message)
+	launched := 0
+	for i := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		launched++
+		index, item, a := i, items[i], agents[i]
+		err := startWork(ctx, s.group, a.Name(), func(ctx context.Context) error {
+			release, err := s.scheduler.Acquire(ctx, tenant)
+			if err != nil {
+				resultsCh <- agentResult{index: index, agentName: a.Name(), err: err}
+				return err
+			}
+			defer release()
+			ctx = s.scheduler.WithSlot(ctx)
+
+			logger := Logger().With(slog.String("pattern", s.name), slog.String("agent", a.Name()),
+				slog.String("scatter_key", item.Key))
+			logger.DebugContext(ctx, LogEventAgentStart)
+			start := time.Now()
+
+			result, err := ProcessTraced(ctx, a, item.Message)
+			if err != nil {
+				logger.WarnContext(ctx, LogEventAgentError, slog.Any("error", err))
+			} else if result != nil {
+				logger.DebugContext(ctx, LogEventAgentEnd, slog.Duration("duration", time.Since(start)))
+				result = linkToInput(item.Message, result)
+				result.WithMetadata("scatter_key", item.Key)
+			}
+
+			resultsCh <- agentResult{index: index, agentName: a.Name(), message: result, err: err}
+			return err
+		})
+		if err != nil {
+			resultsCh <- agentResult{index: index, agentName: a.Name(), err: err}
+		}
+	}
+
+	ordered := make([]*agenkit.Message, len(items))
+	failed := make([]*agentResult, len(items))
+
+	for received := 0; received < launched; received++ {
+		select {
+		case result := <-resultsCh:
+			if result.err != nil {
+				failed[result.index] = &result
+			} else {
+				ordered[result.index] = result.message
+				setCompletionRank(result.message, received)
+			}
+		case <-ctx.Done():
+			return nil, fmt.Errorf("scatter-gather cancelled: %w", contextError(ctx))
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("scatter-gather cancelled: %w", contextError(ctx))
+	}
+
+	successes := make([]*agenkit.Message, 0, len(ordered))
+	for _, msg := range ordered {
+		if msg != nil {
+			successes = append(successes, msg)
+		}
+	}
+
+	var errorDetails []map[string]interface{}
+	var failures []PartialFailure
+	allFailed := &AllAgentsFailedError{}
+	for _, result := range failed {
+		if result == nil {
+			continue
+		}
+		errorDetails = append(errorDetails, map[string]interface{}{
+			"key":   items[result.index].Key,
+			"agent": result.agentName,
+			"error": result.err.Error(),
+
+			FailureCauseKey: FailureCause(result.err),
+		})
+		failures = append(failures, PartialFailure{
+			AgentName: result.agentName,
+			Err:       result.err,
+			Key:       items[result.index].Key,
+		})
+		allFailed.Agents = append(allFailed.Agents, result.agentName)
+		allFailed.Errors = append(allFailed.Errors, result.err)
+	}
+
+	if len(successes) == 0 {
+		return nil, allFailed
+	}
+
+	gathered := s.gatherer(successes)
+	if gathered == nil {
+		return nil, fmt.Errorf("gatherer returned no message")
+	}
+
+	gathered = propagateBranches(successes, gathered)
+	gathered.Metadata["scatter_items"] = len(items)
+	gathered.Metadata["successful_items"] = len(successes)
+	gathered.Metadata["failed_items"] = len(failures)
+	if len(failures) > 0 {
+		gathered.Metadata[PartialFailuresKey] = failures
+		gathered.Metadata["errors"] = errorDetails
+	}
+
+	return gathered, nil
+}
@@ -0,0 +1,121 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// splitLines scatters each line of the input to its own item.
+func splitLines(msg *agenkit.Message) []ScatterItem {
+	var items []ScatterItem
+	for i, line := range strings.Split(msg.ContentString(), "\n") {
+		items = append(items, ScatterItem{
+			Key:     string(rune('a' + i)),
+			Message: agenkit.NewMessage("user", line),
+		})
+	}
+	return items
+}
+
+func upperAgent(name string) agenkit.Agent {
+	return &extendedMockAgent{name: name, processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		return agenkit.NewMessage("assistant", strings.ToUpper(msg.ContentString())), nil
+	}}
+}
+
+func TestNewScatterGather_Validation(t *testing.T) {
+	route := func(item ScatterItem) agenkit.Agent { return upperAgent("a") }
+	if _, err := NewScatterGather(nil, route, DefaultAggregators.Concatenate); err == nil {
+		t.Error("expected error for nil splitter")
+	}
+	if _, err := NewScatterGather(splitLines, nil, DefaultAggregators.Concatenate); err == nil {
+		t.Error("expected error for nil agent selection")
+	}
+	if _, err := NewScatterGather(splitLines, route, nil); err == nil {
+		t.Error("expected error for nil gatherer")
+	}
+}
+
+func TestScatterGather_RoutesPiecesAndGathersInOrder(t *testing.T) {
+	var received []string
+	gather := func(messages []*agenkit.Message) *agenkit.Message {
+		parts := make([]string, len(messages))
+		for i, msg := range messages {
+			parts[i] = msg.Metadata["scatter_key"].(string) + "=" + msg.ContentString()
+			received = append(received, msg.ContentString())
+		}
+		return agenkit.NewMessage("assistant", strings.Join(parts, ","))
+	}
+	sg, err := NewScatterGather(splitLines, func(item ScatterItem) agenkit.Agent {
+		return upperAgent("agent-" + item.Key)
+	}, gather)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	input := agenkit.NewMessage("user", "one\ntwo\nthree")
+	result, err := sg.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "a=ONE,b=TWO,c=THREE" {
+		t.Errorf("unexpected gathered content %q", result.ContentString())
+	}
+	if result.Metadata["scatter_items"] != 3 || result.Metadata["successful_items"] != 3 {
+		t.Errorf("unexpected metadata %v", result.Metadata)
+	}
+	if result.ParentID != input.ID {
+		t.Errorf("expected result linked to input")
+	}
+}
+
+func TestScatterGather_PartialFailure(t *testing.T) {
+	failing := &extendedMockAgent{name: "failing", err: errors.New("boom")}
+	sg, err := NewScatterGather(splitLines, func(item ScatterItem) agenkit.Agent {
+		if item.Key == "b" {
+			return failing
+		}
+		return upperAgent("upper")
+	}, DefaultAggregators.Concatenate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := sg.Process(context.Background(), agenkit.NewMessage("user", "one\ntwo"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "ONE" {
+		t.Errorf("expected only successful piece, got %q", result.ContentString())
+	}
+	failures := GetPartialFailures(result)
+	if len(failures) != 1 || failures[0].Key != "b" || failures[0].AgentName != "failing" {
+		t.Errorf("expected failure for piece b, got %+v", failures)
+	}
+	if result.Metadata["failed_items"] != 1 {
+		t.Errorf("expected 1 failed item, got %v", result.Metadata["failed_items"])
+	}
+}
+
+func TestScatterGather_Errors(t *testing.T) {
+	none := func(msg *agenkit.Message) []ScatterItem { return nil }
+	sg, _ := NewScatterGather(none, func(item ScatterItem) agenkit.Agent { return upperAgent("a") }, DefaultAggregators.First)
+	if _, err := sg.Process(context.Background(), agenkit.NewMessage("user", "x")); !errors.Is(err, ErrNoScatterItems) {
+		t.Errorf("expected ErrNoScatterItems, got %v", err)
+	}
+
+	sg, _ = NewScatterGather(splitLines, func(item ScatterItem) agenkit.Agent { return nil }, DefaultAggregators.First)
+	if _, err := sg.Process(context.Background(), agenkit.NewMessage("user", "x")); err == nil {
+		t.Error("expected error for piece without an agent")
+	}
+
+	failing := &extendedMockAgent{name: "failing", err: errors.New("boom")}
+	sg, _ = NewScatterGather(splitLines, func(item ScatterItem) agenkit.Agent { return failing }, DefaultAggregators.First)
+	if _, err := sg.Process(context.Background(), agenkit.NewMessage("user", "x\ny")); !errors.Is(err, ErrAllAgentsFailed) {
+		t.Errorf("expected ErrAllAgentsFailed, got %v", err)
+	}
+}
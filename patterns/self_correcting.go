@@ -0,0 +1,189 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Self-correcting pattern validates an agent's response and re-prompts it
+// with the validation error until the response is valid.
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// Metadata keys set by SelfCorrectingAgent.
+const (
+	// SelfCorrectionAttemptsKey holds the number of agent calls made
+	SelfCorrectionAttemptsKey = "self_correction_attempts"
+	// SelfCorrectionValidKey holds whether the final response passed validation
+	SelfCorrectionValidKey = "self_correction_valid"
+	// SelfCorrectionErrorsKey holds the validation errors of rejected
+	// responses, in attempt order
+	SelfCorrectionErrorsKey = "self_correction_errors"
+)
+
+// RePromptFunc builds the request for the next attempt from the original
+// request and the validation error of the previous response.
+type RePromptFunc func(original *agenkit.Message, err error) *agenkit.Message
+
+// DefaultRePrompt repeats the original request with the validation error
+// appended, keeping its role and metadata.
+func DefaultRePrompt(original *agenkit.Message, err error) *agenkit.Message {
+	request := copyMessage(original)
+	request.Content = fmt.Sprintf("%s\n\nYour previous response was invalid: %v\nRespond again, correcting the problem.",
+		original.ContentString(), err)
+	return request
+}
+
+// SelfCorrectionError is returned when no response passes validation
+// within the allowed attempts. It unwraps to the last validation error.
+type SelfCorrectionError struct {
+	// Attempts is the number of agent calls made
+	Attempts int
+	// LastResponse is the final rejected response, with the attempt
+	// metadata set
+	LastResponse *agenkit.Message
+	// Err is the final validation error
+	Err error
+}
+
+func (e *SelfCorrectionError) Error() string {
+	return fmt.Sprintf("response invalid after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the final validation error.
+func (e *SelfCorrectionError) Unwrap() error {
+	return e.Err
+}
+
+// SelfCorrectingAgent re-prompts an agent until its response passes a
+// validator.
+//
+// A valid response is returned as a copy with the number of attempts,
+// the validation status and the errors of earlier rejected responses in
+// metadata. If every attempt is rejected, a *SelfCorrectionError carrying
+// the last response is returned. Agent errors are returned immediately:
+// the loop corrects output, it doesn't retry failures.
+//
+// Example:
+//
+//	agent, _ := patterns.NewSelfCorrectingAgent(llmAgent, func(msg *agenkit.Message) error {
+//	    if !json.Valid([]byte(msg.ContentString())) {
+//	        return errors.New("response is not valid JSON")
+//	    }
+//	    return nil
+//	}, 3, nil)
+//	result, err := agent.Process(ctx, message)
+type SelfCorrectingAgent struct {
+	name        string
+	agent       agenkit.Agent
+	validate    func(*agenkit.Message) error
+	maxAttempts int
+	rePrompt    RePromptFunc
+
+	lazy lazyInit
+}
+
+// NewSelfCorrectingAgent creates a self-correcting agent.
+//
+// Parameters:
+//   - agent: The agent whose responses are validated
+//   - validate: Returns an error describing why a response is invalid
+//   - maxAttempts: Maximum agent calls, including the first (at least 1)
+//   - rePromptFn: Builds each corrective request (nil uses DefaultRePrompt)
+func NewSelfCorrectingAgent(agent agenkit.Agent, validate func(*agenkit.Message) error, maxAttempts int, rePromptFn RePromptFunc) (*SelfCorrectingAgent, error) {
+	if agent == nil {
+		return nil, fmt.Errorf("agent is required")
+	}
+	if validate == nil {
+		return nil, fmt.Errorf("validate function is required")
+	}
+	if maxAttempts < 1 {
+		return nil, fmt.Errorf("max attempts must be at least 1, got %d", maxAttempts)
+	}
+	if rePromptFn == nil {
+		rePromptFn = DefaultRePrompt
+	}
+
+	return &SelfCorrectingAgent{
+		name:        "SelfCorrectingAgent",
+		agent:       agent,
+		validate:    validate,
+		maxAttempts: maxAttempts,
+		rePrompt:    rePromptFn,
+	}, nil
+}
+
+// Name returns the agent's identifier.
+func (s *SelfCorrectingAgent) Name() string {
+	return s.name
+}
+
+// Capabilities returns the wrapped agent's capabilities plus self-correction.
+func (s *SelfCorrectingAgent) Capabilities() []string {
+	return append(append([]string{}, s.agent.Capabilities()...), "self-correction")
+}
+
+// Introspect returns introspection information for the agent.
+func (s *SelfCorrectingAgent) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    s.Name(),
+		Capabilities: s.Capabilities(),
+		InternalState: map[string]interface{}{
+			"agent":        s.agent.Name(),
+			"max_attempts": s.maxAttempts,
+		},
+	}
+}
+
+// Process runs the agent, validating each response and re-prompting with
+// the validation error until a response passes or attempts run out.
+func (s *SelfCorrectingAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := s.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	request := message
+	rejected := make([]string, 0)
+	for attempt := 1; ; attempt++ {
+		if err := contextError(ctx); err != nil {
+			return nil, fmt.Errorf("self-correction cancelled after %d attempts: %w", attempt-1, err)
+		}
+
+		response, err := ProcessTraced(ctx, s.agent, request)
+		if err != nil {
+			return nil, fmt.Errorf("agent '%s' failed on attempt %d: %w", s.agent.Name(), attempt, err)
+		}
+		if response == nil {
+			return nil, fmt.Errorf("agent %s returned no message", s.agent.Name())
+		}
+
+		validationErr := s.validate(response)
+		result := copyMessage(response)
+		result.Metadata[SelfCorrectionAttemptsKey] = attempt
+		result.Metadata[SelfCorrectionValidKey] = validationErr == nil
+		result.Metadata[SelfCorrectionErrorsKey] = append([]string{}, rejected...)
+		if validationErr == nil {
+			return result, nil
+		}
+
+		rejected = append(rejected, validationErr.Error())
+		if attempt >= s.maxAttempts {
+			return nil, &SelfCorrectionError{Attempts: attempt, LastResponse: result, Err: validationErr}
+		}
+
+		AnnotateTrace(ctx, fmt.Sprintf("rejected attempt %d (%v) and asked again", attempt, validationErr))
+		Logger().WarnContext(ctx, LogEventRetry, slog.String("pattern", s.name), slog.String("agent", s.agent.Name()),
+			slog.Int("attempt", attempt), slog.Any("error", validationErr))
+		request = s.rePrompt(message, validationErr)
+		if request == nil {
+			return nil, fmt.Errorf("re-prompt function returned no message after attempt %d", attempt)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package patterns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// validJSON rejects responses that aren't JSON.
+func validJSON(msg *agenkit.Message) error {
+	if !json.Valid([]byte(msg.ContentString())) {
+		return errors.New("response is not valid JSON")
+	}
+	return nil
+}
+
+func TestSelfCorrectingAgent_CorrectsInvalidOutput(t *testing.T) {
+	var requests []string
+	responses := []string{"sure! here you go", `{"ok": true}`}
+	agent := &extendedMockAgent{name: "llm", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		requests = append(requests, msg.ContentString())
+		return agenkit.NewMessage("assistant", responses[len(requests)-1]), nil
+	}}
+	corrector, err := NewSelfCorrectingAgent(agent, validJSON, 3, nil)
+	if err != nil {
+		t.Fatalf("NewSelfCorrectingAgent: %v", err)
+	}
+
+	input := agenkit.NewMessage("user", "give me JSON")
+	result, err := corrector.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Content != `{"ok": true}` || result.ParentID != input.ID {
+		t.Errorf("result = %q (parent %q)", result.Content, result.ParentID)
+	}
+	if result.Metadata[SelfCorrectionAttemptsKey] != 2 || result.Metadata[SelfCorrectionValidKey] != true {
+		t.Errorf("unexpected metadata %v", result.Metadata)
+	}
+	if errs := result.Metadata[SelfCorrectionErrorsKey].([]string); len(errs) != 1 || errs[0] != "response is not valid JSON" {
+		t.Errorf("unexpected rejected errors %v", errs)
+	}
+	if len(requests) != 2 || !strings.HasPrefix(requests[1], "give me JSON") || !strings.Contains(requests[1], "not valid JSON") {
+		t.Errorf("expected a corrective prompt with the error, got %q", requests)
+	}
+}
+
+func TestSelfCorrectingAgent_GivesUp(t *testing.T) {
+	agent := &extendedMockAgent{name: "llm", response: "never JSON"}
+	var rePrompts int
+	corrector, _ := NewSelfCorrectingAgent(agent, validJSON, 3, func(original *agenkit.Message, err error) *agenkit.Message {
+		rePrompts++
+		return agenkit.NewMessage("user", original.ContentString()+" (JSON only!)")
+	})
+
+	_, err := corrector.Process(context.Background(), agenkit.NewMessage("user", "give me JSON"))
+	var correctionErr *SelfCorrectionError
+	if !errors.As(err, &correctionErr) {
+		t.Fatalf("expected SelfCorrectionError, got %v", err)
+	}
+	if correctionErr.Attempts != 3 || rePrompts != 2 || correctionErr.LastResponse.Metadata[SelfCorrectionValidKey] != false {
+		t.Errorf("attempts %d, re-prompts %d, last %v", correctionErr.Attempts, rePrompts, correctionErr.LastResponse.Metadata)
+	}
+	if !strings.Contains(err.Error(), "not valid JSON") {
+		t.Errorf("expected the validation error in %q", err)
+	}
+}
+
+func TestSelfCorrectingAgent_AgentErrorStopsLoop(t *testing.T) {
+	calls := 0
+	agent := &extendedMockAgent{name: "llm", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		calls++
+		return nil, errors.New("rate limited")
+	}}
+	corrector, _ := NewSelfCorrectingAgent(agent, validJSON, 3, nil)
+
+	if _, err := corrector.Process(context.Background(), agenkit.NewMessage("user", "hi")); err == nil || calls != 1 {
+		t.Errorf("expected the agent error after 1 call, got %v after %d", err, calls)
+	}
+	if _, err := NewSelfCorrectingAgent(agent, validJSON, 0, nil); err == nil {
+		t.Error("expected an error for zero attempts")
+	}
+}
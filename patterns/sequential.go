@@ -19,10 +19,21 @@ package patterns
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
 
+// StageRecorder receives the input and output of every stage of a
+// recording pipeline (see NewRecordingSequential), one session per run.
+// *evaluation.SessionRecorder satisfies it.
+type StageRecorder interface {
+	StartSession(sessionID, agentName string, metadata map[string]interface{})
+	RecordInteraction(sessionID string, input, output *agenkit.Message, latencyMs float64, metadata map[string]interface{})
+}
+
 // SequentialAgent executes a pipeline of agents in order.
 //
 // Each agent receives the output of the previous agent as input.
@@ -35,8 +46,11 @@ import (
 //
 // The pipeline stops immediately if any agent returns an error.
 type SequentialAgent struct {
-	name   string
-	agents []agenkit.Agent
+	name     string
+	agents   []agenkit.Agent
+	recorder StageRecorder
+
+	lazy lazyInit
 }
 
 // NewSequentialAgent creates a new sequential pipeline agent.
@@ -57,6 +71,46 @@ func NewSequentialAgent(agents []agenkit.Agent) (*SequentialAgent, error) {
 	}, nil
 }
 
+// NewRecordingSequential creates a sequential pipeline that records every
+// stage's input and output to recorder, so a wrong final answer can be
+// traced to the stage that produced it without instrumenting each agent.
+//
+// Each run is recorded as one session with an interaction per stage, in
+// order, whose metadata has "stage" (the stage index) and "agent". A
+// failing stage is recorded with no output and an "error" entry, and the
+// stages after it are not run. The session ID is the input's "session_id"
+// metadata, or a new UUID; the result carries it as
+// "pipeline_recording_id". Recorded messages are snapshots, unaffected by
+// later stages.
+//
+// Example:
+//
+//	recorder := evaluation.NewSessionRecorder(evaluation.NewMemoryRecordingStorage())
+//	pipeline, _ := patterns.NewRecordingSequential([]agenkit.Agent{extract, translate, summarize}, recorder)
+//	result, err := pipeline.Process(ctx, message)
+//	recording, _ := recorder.FinalizeSession(result.Metadata["pipeline_recording_id"].(string))
+//	for _, stage := range recording.Interactions {
+//	    fmt.Println(stage.Metadata["agent"], stage.OutputMessage["content"])
+//	}
+func NewRecordingSequential(agents []agenkit.Agent, recorder StageRecorder) (*SequentialAgent, error) {
+	if recorder == nil {
+		return nil, fmt.Errorf("recorder is required")
+	}
+	pipeline, err := NewSequentialAgent(agents)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.WithRecorder(recorder), nil
+}
+
+// WithRecorder records every stage of each run to recorder, as
+// NewRecordingSequential does, and returns the agent for chaining. Pass nil
+// to stop recording.
+func (s *SequentialAgent) WithRecorder(recorder StageRecorder) *SequentialAgent {
+	s.recorder = recorder
+	return s
+}
+
 // Name returns the agent's identifier.
 func (s *SequentialAgent) Name() string {
 	return s.name
@@ -80,6 +134,12 @@ func (s *SequentialAgent) Capabilities() []string {
 	return capabilities
 }
 
+// Introspect reports the pipeline as a whole: the union of its stages'
+// capabilities, with the stages in order under TopologyKey.
+func (s *SequentialAgent) Introspect() *agenkit.IntrospectionResult {
+	return introspectComposition(s.Name(), TopologySequential, s.Capabilities(), stageComponents(s.agents))
+}
+
 // Process executes the agent pipeline sequentially.
 //
 // The message is passed through each agent in order. Each agent's output
@@ -88,30 +148,64 @@ func (s *SequentialAgent) Capabilities() []string {
 //
 // Metadata from each agent is preserved in the final message under the
 // "pipeline_stages" key, allowing inspection of intermediate results.
-func (s *SequentialAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	if message == nil {
-		return nil, fmt.Errorf("message cannot be nil")
+// CarryThroughKeys set by a stage are carried forward through later stages
+// that don't set them.
+func (s *SequentialAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := s.Init(ctx); err != nil {
+		return nil, err
 	}
 
 	// Track pipeline stages for observability
 	stages := make([]map[string]interface{}, 0, len(s.agents))
 	executionOrder := make([]string, 0, len(s.agents))
 
+	// Record each stage if a recorder is set
+	recordingID := ""
+	if s.recorder != nil {
+		recordingID, _ = message.Metadata["session_id"].(string)
+		if recordingID == "" {
+			recordingID = uuid.NewString()
+		}
+		s.recorder.StartSession(recordingID, s.name, map[string]interface{}{"pipeline_length": len(s.agents)})
+	}
+
 	// Pass message through each agent
 	current := message
 	for i, agent := range s.agents {
 		// Check for context cancellation
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("pipeline cancelled at stage %d: %w", i, ctx.Err())
+			return nil, fmt.Errorf("pipeline cancelled at stage %d: %w", i, contextError(ctx))
 		default:
 		}
 
 		// Process with current agent
-		result, err := agent.Process(ctx, current)
+		logger := Logger().With(slog.String("pattern", s.name), slog.String("agent", agent.Name()), slog.Int("stage", i))
+		logger.DebugContext(ctx, LogEventAgentStart)
+		start := time.Now()
+		result, err := ProcessTraced(ctx, agent, current)
+		if err == nil && result != nil {
+			result = linkToInput(current, result)
+			if i > 0 {
+				result = propagateStage(current, result)
+			}
+		}
+		if s.recorder != nil {
+			s.recordStage(recordingID, i, agent, current, result, err, time.Since(start))
+		}
 		if err != nil {
+			logger.WarnContext(ctx, LogEventAgentError, slog.Any("error", err))
 			return nil, fmt.Errorf("agent %d (%s) failed: %w", i, agent.Name(), err)
 		}
+		if result == nil {
+			return nil, fmt.Errorf("agent %d (%s) returned no message", i, agent.Name())
+		}
+		logger.DebugContext(ctx, LogEventAgentEnd, slog.Duration("duration", time.Since(start)))
 
 		// Record stage metadata (without circular references)
 		stageInfo := map[string]interface{}{
@@ -141,6 +235,28 @@ func (s *SequentialAgent) Process(ctx context.Context, message *agenkit.Message)
 	current.Metadata["execution_order"] = executionOrder
 	current.Metadata["agent_count"] = len(s.agents)
 	current.Metadata["sub_agents"] = executionOrder // For test harness compatibility
+	if recordingID != "" {
+		current.Metadata["pipeline_recording_id"] = recordingID
+	}
 
 	return current, nil
 }
+
+// recordStage records stage i's input and output, copying both so later
+// stages can't change the recorded messages.
+func (s *SequentialAgent) recordStage(recordingID string, i int, agent agenkit.Agent, input, output *agenkit.Message, err error, latency time.Duration) {
+	metadata := map[string]interface{}{
+		"stage": i,
+		"agent": agent.Name(),
+	}
+	if err != nil {
+		metadata["error"] = err.Error()
+		metadata[FailureCauseKey] = FailureCause(err)
+	} else if output == nil {
+		metadata["error"] = "no message returned"
+	}
+	if output != nil {
+		output = copyMessage(output)
+	}
+	s.recorder.RecordInteraction(recordingID, copyMessage(input), output, float64(latency.Milliseconds()), metadata)
+}
@@ -362,3 +362,88 @@ func TestSequentialAgent_StageMetadata(t *testing.T) {
 		}
 	}
 }
+
+// stageRecord is one interaction captured by fakeStageRecorder.
+type stageRecord struct {
+	session  string
+	input    *agenkit.Message
+	output   *agenkit.Message
+	metadata map[string]interface{}
+}
+
+// fakeStageRecorder captures pipeline stages in memory.
+type fakeStageRecorder struct {
+	sessions []string
+	stages   []stageRecord
+}
+
+func (r *fakeStageRecorder) StartSession(sessionID, agentName string, metadata map[string]interface{}) {
+	r.sessions = append(r.sessions, sessionID)
+}
+
+func (r *fakeStageRecorder) RecordInteraction(sessionID string, input, output *agenkit.Message, latencyMs float64, metadata map[string]interface{}) {
+	r.stages = append(r.stages, stageRecord{sessionID, input, output, metadata})
+}
+
+func TestRecordingSequential_RecordsEveryStage(t *testing.T) {
+	recorder := &fakeStageRecorder{}
+	pipeline, err := NewRecordingSequential([]agenkit.Agent{
+		&extendedMockAgent{name: "extract", response: "facts"},
+		&extendedMockAgent{name: "summarize", response: "summary"},
+	}, recorder)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	input := agenkit.NewMessage("user", "document").WithMetadata("session_id", "run-1")
+	result, err := pipeline.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(recorder.sessions) != 1 || recorder.sessions[0] != "run-1" || result.Metadata["pipeline_recording_id"] != "run-1" {
+		t.Errorf("expected one session run-1, got %v (result %v)", recorder.sessions, result.Metadata["pipeline_recording_id"])
+	}
+	if len(recorder.stages) != 2 {
+		t.Fatalf("expected 2 recorded stages, got %d", len(recorder.stages))
+	}
+	first, second := recorder.stages[0], recorder.stages[1]
+	if first.input.ContentString() != "document" || first.output.ContentString() != "facts" || first.metadata["agent"] != "extract" {
+		t.Errorf("unexpected first stage: %+v", first)
+	}
+	if second.input.ContentString() != "facts" || second.output.ContentString() != "summary" || second.metadata["stage"] != 1 {
+		t.Errorf("unexpected second stage: %+v", second)
+	}
+	if _, ok := second.output.Metadata["pipeline_stages"]; ok {
+		t.Error("expected the recorded output to be a snapshot without pipeline metadata")
+	}
+}
+
+func TestRecordingSequential_RecordsFailingStage(t *testing.T) {
+	recorder := &fakeStageRecorder{}
+	pipeline, _ := NewRecordingSequential([]agenkit.Agent{
+		&extendedMockAgent{name: "extract", response: "facts"},
+		&extendedMockAgent{name: "translate", err: errors.New("unsupported language")},
+		&extendedMockAgent{name: "summarize", response: "summary"},
+	}, recorder)
+
+	if _, err := pipeline.Process(context.Background(), agenkit.NewMessage("user", "document")); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(recorder.stages) != 2 {
+		t.Fatalf("expected stages up to the failure recorded, got %d", len(recorder.stages))
+	}
+	failed := recorder.stages[1]
+	if failed.output != nil || failed.metadata["error"] != "unsupported language" || failed.input.ContentString() != "facts" {
+		t.Errorf("unexpected failed stage: %+v", failed)
+	}
+	if recorder.sessions[0] == "" || failed.session != recorder.sessions[0] {
+		t.Errorf("expected a generated session ID, got %v", recorder.sessions)
+	}
+}
+
+func TestNewRecordingSequential_RequiresRecorder(t *testing.T) {
+	if _, err := NewRecordingSequential([]agenkit.Agent{&extendedMockAgent{name: "a"}}, nil); err == nil {
+		t.Error("expected error for nil recorder")
+	}
+}
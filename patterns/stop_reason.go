@@ -0,0 +1,53 @@
+package patterns
+
+import "github.com/scttfrdmn/agenkit-go/agenkit"
+
+// StopReason indicates why a loop pattern stopped.
+//
+// Every loop pattern (ReAct, ReasoningWithTools, Collaborative, Planning,
+// Reflection) records one of the shared values below in its result's
+// metadata under StopReasonKey, so monitoring code can classify any
+// termination without parsing pattern-specific strings. AutonomousAgent
+// reports it in AutonomousResult.StopReason. Pattern-specific detail,
+// where a pattern has it, stays under "stop_reason".
+type StopReason string
+
+const (
+	// StopCompleted indicates the loop finished its task
+	StopCompleted StopReason = "completed"
+	// StopMaxIterations indicates the iteration, step or round limit was reached
+	StopMaxIterations StopReason = "max_iterations"
+	// StopTimeout indicates the loop's time limit elapsed
+	StopTimeout StopReason = "timeout"
+	// StopBudgetExceeded indicates a work budget ran out before completion
+	StopBudgetExceeded StopReason = "budget_exceeded"
+	// StopConsensus indicates the participating agents reached consensus
+	StopConsensus StopReason = "consensus"
+	// StopStopped indicates the loop was stopped early by a stop condition,
+	// a caller or a lack of further work
+	StopStopped StopReason = "stopped"
+	// StopError indicates the loop gave up after an error
+	StopError StopReason = "error"
+	// StopLoopDetected indicates the loop stopped because it kept
+	// repeating the same action (see AdaptiveSteps)
+	StopLoopDetected StopReason = "loop_detected"
+)
+
+// StopReasonKey is the metadata key under which loop patterns record
+// their StopReason.
+const StopReasonKey = "loop_stop_reason"
+
+// GetStopReason returns the StopReason recorded in msg's metadata, or ""
+// if msg is nil or was not produced by a loop pattern.
+func GetStopReason(msg *agenkit.Message) StopReason {
+	if msg == nil {
+		return ""
+	}
+	switch v := msg.Metadata[StopReasonKey].(type) {
+	case StopReason:
+		return v
+	case string:
+		return StopReason(v)
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package patterns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestGetStopReason(t *testing.T) {
+	if got := GetStopReason(nil); got != "" {
+		t.Errorf("expected empty reason for nil message, got %q", got)
+	}
+	if got := GetStopReason(agenkit.NewMessage("assistant", "x")); got != "" {
+		t.Errorf("expected empty reason without metadata, got %q", got)
+	}
+	msg := agenkit.NewMessage("assistant", "x").WithMetadata(StopReasonKey, "timeout")
+	if got := GetStopReason(msg); got != StopTimeout {
+		t.Errorf("expected timeout from string value, got %q", got)
+	}
+}
+
+func TestReasoningWithTools_StopReason(t *testing.T) {
+	llm := &mockReasoningAgent{name: "llm", responses: []string{"FINAL ANSWER: 42"}}
+	agent := NewReasoningWithToolsAgent(llm, nil, nil)
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "q"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := GetStopReason(result); got != StopCompleted {
+		t.Errorf("expected completed, got %q", got)
+	}
+
+	llm = &mockReasoningAgent{name: "llm", responses: []string{"thinking", "still thinking"}}
+	agent = NewReasoningWithToolsAgent(llm, nil, &ReasoningWithToolsConfig{MaxReasoningSteps: 2})
+	result, err = agent.Process(context.Background(), agenkit.NewMessage("user", "q"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := GetStopReason(result); got != StopMaxIterations {
+		t.Errorf("expected max_iterations, got %q", got)
+	}
+}
+
+func TestCollaborativeAgent_StopReason(t *testing.T) {
+	agents := []agenkit.Agent{
+		&extendedMockAgent{name: "a", response: "agreed"},
+		&extendedMockAgent{name: "b", response: "agreed"},
+	}
+	collab, err := NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:        agents,
+		MaxRounds:     3,
+		MergeFunc:     DefaultMergeFunc.First,
+		ConsensusFunc: DefaultConsensusFunc.ExactMatch,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := collab.Process(context.Background(), agenkit.NewMessage("user", "q"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := GetStopReason(result); got != StopConsensus {
+		t.Errorf("expected consensus, got %q", got)
+	}
+
+	collab, err = NewCollaborativeAgent(&CollaborativeConfig{
+		Agents:    agents,
+		MaxRounds: 2,
+		MergeFunc: DefaultMergeFunc.First,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err = collab.Process(context.Background(), agenkit.NewMessage("user", "q"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := GetStopReason(result); got != StopMaxIterations {
+		t.Errorf("expected max_iterations, got %q", got)
+	}
+}
+
+func TestPlanningAgent_StopReason(t *testing.T) {
+	llm := &planningMockLLMClient{response: "Goal: Test\nSteps:\n1. First\n2. Second"}
+
+	agent := NewPlanningAgent(llm, &mockStepExecutor{failOnStep: -1}, nil)
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "task"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := GetStopReason(result); got != StopCompleted {
+		t.Errorf("expected completed, got %q", got)
+	}
+
+	agent = NewPlanningAgent(llm, &mockStepExecutor{failOnStep: 0}, nil)
+	result, err = agent.Process(context.Background(), agenkit.NewMessage("user", "task"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := GetStopReason(result); got != StopError {
+		t.Errorf("expected error, got %q", got)
+	}
+}
+
+func TestAutonomousAgent_StopReason(t *testing.T) {
+	agent := NewAutonomousAgent("objective", 10)
+	agent.AddGoal("goal", 1)
+	result, err := agent.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.StopReason != StopCompleted {
+		t.Errorf("expected completed, got %q", result.StopReason)
+	}
+
+	agent = NewAutonomousAgent("objective", 2)
+	agent.AddGoal("goal", 1)
+	agent.SetCompletionCheck(func(goal *Goal, output string) bool { return false })
+	result, err = agent.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.StopReason != StopMaxIterations {
+		t.Errorf("expected max_iterations, got %q", result.StopReason)
+	}
+
+	agent = NewAutonomousAgent("objective", 10)
+	agent.AddGoal("goal", 1)
+	agent.SetStopCondition(func() bool { return true })
+	result, err = agent.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.StopReason != StopStopped {
+		t.Errorf("expected stopped, got %q", result.StopReason)
+	}
+}
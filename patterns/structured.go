@@ -0,0 +1,261 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Structured output pattern makes an agent return JSON that conforms to a
+// JSON Schema, re-prompting it with validation errors.
+package patterns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// StructuredOutputError is returned when the agent fails to produce a
+// schema-conforming response within the allowed attempts.
+type StructuredOutputError struct {
+	// Attempts is the number of agent calls made
+	Attempts int
+	// LastResponse is the final raw response text
+	LastResponse string
+	// Errors are the validation errors for the final response
+	Errors []string
+}
+
+func (e *StructuredOutputError) Error() string {
+	return fmt.Sprintf("structured output invalid after %d attempts: %s",
+		e.Attempts, strings.Join(e.Errors, "; "))
+}
+
+// StructuredAgent wraps an agent so that its responses are JSON validated
+// against a schema.
+//
+// The parsed value is attached to result.Metadata["structured"] and the
+// number of attempts to result.Metadata["structured_attempts"].
+//
+// Example:
+//
+//	router, _ := patterns.NewStructuredAgent(llmAgent, map[string]interface{}{
+//	    "type": "object",
+//	    "properties": map[string]interface{}{
+//	        "category": map[string]interface{}{
+//	            "type": "string",
+//	            "enum": []string{"billing", "technical", "sales"},
+//	        },
+//	    },
+//	    "required": []string{"category"},
+//	})
+//	result, _ := router.Process(ctx, message)
+//	category := result.Metadata["structured"].(map[string]interface{})["category"]
+type StructuredAgent struct {
+	name       string
+	agent      agenkit.Agent
+	schema     *gojsonschema.Schema
+	schemaText string
+	maxRetries int
+
+	lazy lazyInit
+}
+
+// NewStructuredAgent creates a new structured output agent.
+//
+// Parameters:
+//   - agent: The agent to wrap (typically LLM-backed)
+//   - schema: JSON Schema describing the expected response
+//
+// Returns an error if the schema is invalid. Defaults to 2 retries.
+func NewStructuredAgent(agent agenkit.Agent, schema map[string]interface{}) (*StructuredAgent, error) {
+	if agent == nil {
+		return nil, fmt.Errorf("agent is required")
+	}
+	if len(schema) == 0 {
+		return nil, fmt.Errorf("schema is required")
+	}
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	schemaText, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	return &StructuredAgent{
+		name:       "StructuredAgent",
+		agent:      agent,
+		schema:     compiled,
+		schemaText: string(schemaText),
+		maxRetries: 2,
+	}, nil
+}
+
+// WithMaxRetries sets how many times the agent is re-prompted after an
+// invalid response and returns the agent for chaining.
+func (s *StructuredAgent) WithMaxRetries(maxRetries int) *StructuredAgent {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	s.maxRetries = maxRetries
+	return s
+}
+
+// Name returns the agent's identifier.
+func (s *StructuredAgent) Name() string {
+	return s.name
+}
+
+// Capabilities returns the wrapped agent's capabilities plus structured output.
+func (s *StructuredAgent) Capabilities() []string {
+	return append(append([]string{}, s.agent.Capabilities()...), "structured-output", "json")
+}
+
+// Introspect returns introspection information for the agent.
+func (s *StructuredAgent) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    s.Name(),
+		Capabilities: s.Capabilities(),
+		InternalState: map[string]interface{}{
+			"agent":       s.agent.Name(),
+			"max_retries": s.maxRetries,
+		},
+	}
+}
+
+// Process asks the agent for a JSON response and validates it, re-prompting
+// with validation errors until the response conforms or retries run out.
+func (s *StructuredAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := s.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	prompt := s.buildPrompt(message.ContentString())
+	var lastText string
+	var lastErrors []string
+
+	for attempt := 1; attempt <= s.maxRetries+1; attempt++ {
+		if err := contextError(ctx); err != nil {
+			return nil, fmt.Errorf("structured output cancelled after %d attempts: %w", attempt-1, err)
+		}
+
+		request := agenkit.NewMessage(message.Role, prompt)
+		for k, v := range message.Metadata {
+			request.Metadata[k] = v
+		}
+
+		response, err := ProcessTraced(ctx, s.agent, request)
+		if err != nil {
+			return nil, fmt.Errorf("agent '%s' failed on attempt %d: %w", s.agent.Name(), attempt, err)
+		}
+
+		lastText = response.ContentString()
+		value, errs := s.validate(lastText)
+		if len(errs) == 0 {
+			if response.Metadata == nil {
+				response.Metadata = make(map[string]interface{})
+			}
+			response.Metadata["structured"] = value
+			response.Metadata["structured_attempts"] = attempt
+			return response, nil
+		}
+
+		lastErrors = errs
+		prompt = s.buildRepairPrompt(message.ContentString(), lastText, errs)
+	}
+
+	return nil, &StructuredOutputError{
+		Attempts:     s.maxRetries + 1,
+		LastResponse: lastText,
+		Errors:       lastErrors,
+	}
+}
+
+// validate parses text as JSON and checks it against the schema.
+func (s *StructuredAgent) validate(text string) (interface{}, []string) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(extractJSON(text)), &value); err != nil {
+		return nil, []string{fmt.Sprintf("response is not valid JSON: %v", err)}
+	}
+
+	result, err := s.schema.Validate(gojsonschema.NewGoLoader(value))
+	if err != nil {
+		return nil, []string{fmt.Sprintf("schema validation failed: %v", err)}
+	}
+	if !result.Valid() {
+		errs := make([]string, len(result.Errors()))
+		for i, e := range result.Errors() {
+			errs[i] = e.String()
+		}
+		return nil, errs
+	}
+
+	return value, nil
+}
+
+// buildPrompt instructs the agent to answer in schema-conforming JSON.
+func (s *StructuredAgent) buildPrompt(request string) string {
+	return fmt.Sprintf(`%s
+
+Respond with ONLY a JSON value that conforms to this JSON Schema. Do not include any other text.
+
+Schema:
+%s`, request, s.schemaText)
+}
+
+// buildRepairPrompt asks the agent to fix its previous response.
+func (s *StructuredAgent) buildRepairPrompt(request, previous string, errs []string) string {
+	var b strings.Builder
+	b.WriteString(s.buildPrompt(request))
+	b.WriteString("\n\nYour previous response was invalid:\n")
+	b.WriteString(previous)
+	b.WriteString("\n\nValidation errors:\n")
+	for _, e := range errs {
+		b.WriteString("- ")
+		b.WriteString(e)
+		b.WriteString("\n")
+	}
+	b.WriteString("\nRespond again with corrected JSON only.")
+	return b.String()
+}
+
+// extractJSON returns the JSON portion of text, stripping Markdown code
+// fences and any prose before the first '{' or '[' and after the matching
+// last '}' or ']'.
+func extractJSON(text string) string {
+	text = strings.TrimSpace(text)
+
+	if start := strings.Index(text, "```"); start >= 0 {
+		body := text[start+3:]
+		if newline := strings.Index(body, "\n"); newline >= 0 {
+			body = body[newline+1:]
+		}
+		if end := strings.Index(body, "```"); end >= 0 {
+			body = body[:end]
+		}
+		text = strings.TrimSpace(body)
+	}
+
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return text
+	}
+	closing := "}"
+	if text[start] == '[' {
+		closing = "]"
+	}
+	end := strings.LastIndex(text, closing)
+	if end < start {
+		return text
+	}
+	return text[start : end+1]
+}
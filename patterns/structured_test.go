@@ -0,0 +1,136 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+var categorySchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"category": map[string]interface{}{
+			"type": "string",
+			"enum": []interface{}{"billing", "technical"},
+		},
+	},
+	"required": []interface{}{"category"},
+}
+
+// scriptedAgent returns responses in order and records prompts.
+func scriptedAgent(prompts *[]string, responses ...string) *extendedMockAgent {
+	return &extendedMockAgent{name: "llm", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		*prompts = append(*prompts, msg.ContentString())
+		i := len(*prompts) - 1
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		return agenkit.NewMessage("assistant", responses[i]), nil
+	}}
+}
+
+func TestNewStructuredAgent_Validation(t *testing.T) {
+	agent := &extendedMockAgent{name: "llm"}
+
+	if _, err := NewStructuredAgent(nil, categorySchema); err == nil {
+		t.Error("expected error for nil agent")
+	}
+	if _, err := NewStructuredAgent(agent, nil); err == nil {
+		t.Error("expected error for empty schema")
+	}
+	if _, err := NewStructuredAgent(agent, map[string]interface{}{"type": 42}); err == nil {
+		t.Error("expected error for invalid schema")
+	}
+}
+
+func TestStructuredAgent_ValidFirstAttempt(t *testing.T) {
+	var prompts []string
+	agent, err := NewStructuredAgent(scriptedAgent(&prompts, "```json\n{\"category\": \"billing\"}\n```"), categorySchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "I was charged twice"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	structured := result.Metadata["structured"].(map[string]interface{})
+	if structured["category"] != "billing" {
+		t.Errorf("expected category billing, got %v", structured["category"])
+	}
+	if result.Metadata["structured_attempts"] != 1 {
+		t.Errorf("expected 1 attempt, got %v", result.Metadata["structured_attempts"])
+	}
+	if !strings.Contains(prompts[0], "I was charged twice") || !strings.Contains(prompts[0], `"enum"`) {
+		t.Errorf("expected prompt to include request and schema, got %q", prompts[0])
+	}
+}
+
+func TestStructuredAgent_RepromptsWithErrors(t *testing.T) {
+	var prompts []string
+	llm := scriptedAgent(&prompts,
+		"The category is billing.",
+		`{"category": "sales"}`,
+		`Sure! {"category": "technical"}`,
+	)
+	agent, err := NewStructuredAgent(llm, categorySchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "My app crashes"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Metadata["structured_attempts"] != 3 {
+		t.Errorf("expected 3 attempts, got %v", result.Metadata["structured_attempts"])
+	}
+	if !strings.Contains(prompts[1], "not valid JSON") {
+		t.Errorf("expected parse error in repair prompt, got %q", prompts[1])
+	}
+	if !strings.Contains(prompts[2], `{"category": "sales"}`) || !strings.Contains(prompts[2], "category") {
+		t.Errorf("expected previous response and schema error in repair prompt, got %q", prompts[2])
+	}
+}
+
+func TestStructuredAgent_GivesUp(t *testing.T) {
+	var prompts []string
+	agent, err := NewStructuredAgent(scriptedAgent(&prompts, `{"category": 1}`), categorySchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	agent.WithMaxRetries(1)
+
+	_, err = agent.Process(context.Background(), agenkit.NewMessage("user", "hello"))
+	var structuredErr *StructuredOutputError
+	if !errors.As(err, &structuredErr) {
+		t.Fatalf("expected StructuredOutputError, got %v", err)
+	}
+	if structuredErr.Attempts != 2 || len(prompts) != 2 {
+		t.Errorf("expected 2 attempts, got %d (prompts %d)", structuredErr.Attempts, len(prompts))
+	}
+	if structuredErr.LastResponse != `{"category": 1}` || len(structuredErr.Errors) == 0 {
+		t.Errorf("unexpected error details: %+v", structuredErr)
+	}
+}
+
+func TestExtractJSON(t *testing.T) {
+	tests := map[string]string{
+		`{"a": 1}`:                       `{"a": 1}`,
+		"```json\n{\"a\": 1}\n```":       `{"a": 1}`,
+		"Here you go: [1, 2] thanks":     `[1, 2]`,
+		"prefix {\"a\": {\"b\": 2}} end": `{"a": {"b": 2}}`,
+		"no json here":                   "no json here",
+	}
+
+	for input, want := range tests {
+		if got := extractJSON(input); got != want {
+			t.Errorf("extractJSON(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
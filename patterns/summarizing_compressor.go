@@ -0,0 +1,187 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Summarizing compressor replaces the oldest turns of a long conversation
+// with an LLM-generated summary, keeping recent turns verbatim.
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// SummaryMetadataKey tags summary messages produced by a SummarizingCompressor.
+const SummaryMetadataKey = "summary"
+
+// HistoryCompressor shortens a conversation history.
+//
+// Implementations return the history unchanged when no compression is
+// needed. The input slice must not be modified.
+type HistoryCompressor interface {
+	Compress(ctx context.Context, history []*agenkit.Message) ([]*agenkit.Message, error)
+}
+
+// SummarizingCompressorConfig configures a SummarizingCompressor.
+type SummarizingCompressorConfig struct {
+	// Summarizer produces the summary text (required)
+	Summarizer agenkit.Agent
+	// Threshold is the number of conversation turns (non-system messages)
+	// above which compression runs (default: 20)
+	Threshold int
+	// KeepRecent is the number of newest turns kept verbatim (default: 6).
+	// Must be less than Threshold.
+	KeepRecent int
+	// Template renders the summarization request (default: DefaultSummaryPrompt).
+	// Data: SummaryPromptData.
+	Template *agenkit.PromptTemplate
+}
+
+// SummarizingCompressor collapses old conversation turns into a summary.
+//
+// When the number of turns exceeds Threshold, every turn except the newest
+// KeepRecent is sent to the summarizer, and replaced by a single "system"
+// message tagged with metadata[SummaryMetadataKey] = true. On later
+// compressions the existing summary is passed to the summarizer as context
+// and replaced by the updated summary, so it is never treated as a turn.
+// Other system messages are kept unchanged at the front.
+//
+// Example:
+//
+//	compressor, _ := patterns.NewSummarizingCompressor(&patterns.SummarizingCompressorConfig{
+//	    Summarizer: summarizerAgent,
+//	    Threshold:  30,
+//	    KeepRecent: 10,
+//	})
+//	agent, _ := patterns.NewConversationalAgent(&patterns.ConversationalAgentConfig{
+//	    LLMClient:  llm,
+//	    MaxHistory: 40,
+//	    Compressor: compressor,
+//	})
+type SummarizingCompressor struct {
+	summarizer agenkit.Agent
+	threshold  int
+	keepRecent int
+	template   *agenkit.PromptTemplate
+
+	lazy lazyInit
+}
+
+// NewSummarizingCompressor creates a new summarizing compressor.
+func NewSummarizingCompressor(config *SummarizingCompressorConfig) (*SummarizingCompressor, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+	if config.Summarizer == nil {
+		return nil, fmt.Errorf("summarizer is required")
+	}
+
+	threshold := config.Threshold
+	if threshold == 0 {
+		threshold = 20
+	}
+	keepRecent := config.KeepRecent
+	if keepRecent == 0 {
+		keepRecent = 6
+	}
+	if threshold < 1 || keepRecent < 0 {
+		return nil, fmt.Errorf("threshold and keepRecent must be positive")
+	}
+	if keepRecent >= threshold {
+		return nil, fmt.Errorf("keepRecent (%d) must be less than threshold (%d)", keepRecent, threshold)
+	}
+
+	template := config.Template
+	if template == nil {
+		template = DefaultSummaryPrompt
+	}
+
+	return &SummarizingCompressor{
+		summarizer: config.Summarizer,
+		threshold:  threshold,
+		keepRecent: keepRecent,
+		template:   template,
+	}, nil
+}
+
+// IsSummary reports whether message is a summary produced by a
+// SummarizingCompressor.
+func IsSummary(message *agenkit.Message) bool {
+	if message == nil || message.Metadata == nil {
+		return false
+	}
+	tagged, _ := message.Metadata[SummaryMetadataKey].(bool)
+	return tagged
+}
+
+// Compress summarizes the oldest turns if history exceeds the threshold.
+func (s *SummarizingCompressor) Compress(ctx context.Context, history []*agenkit.Message) ([]*agenkit.Message, error) {
+	var system []*agenkit.Message
+	var turns []*agenkit.Message
+	var previous *agenkit.Message
+
+	for _, msg := range history {
+		switch {
+		case IsSummary(msg):
+			previous = msg
+		case msg.Role == agenkit.RoleSystem:
+			system = append(system, msg)
+		default:
+			turns = append(turns, msg)
+		}
+	}
+
+	if len(turns) <= s.threshold {
+		return history, nil
+	}
+
+	old := turns[:len(turns)-s.keepRecent]
+	recent := turns[len(turns)-s.keepRecent:]
+
+	data := SummaryPromptData{Transcript: formatTranscript(old)}
+	summarized := len(old)
+	if previous != nil {
+		data.PreviousSummary = previous.ContentString()
+		if count, ok := previous.Metadata["summarized_messages"].(int); ok {
+			summarized += count
+		}
+	}
+
+	prompt, err := s.template.Render(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Init(ctx); err != nil {
+		return nil, err
+	}
+	response, err := ProcessTraced(ctx, s.summarizer, agenkit.NewMessage(agenkit.RoleUser, prompt))
+	if err != nil {
+		return nil, fmt.Errorf("summarizer '%s' failed: %w", s.summarizer.Name(), err)
+	}
+
+	summary := agenkit.NewMessage(agenkit.RoleSystem, strings.TrimSpace(response.ContentString())).
+		WithMetadata(SummaryMetadataKey, true).
+		WithMetadata("summarized_messages", summarized)
+
+	compressed := make([]*agenkit.Message, 0, len(system)+1+len(recent))
+	compressed = append(compressed, system...)
+	compressed = append(compressed, summary)
+	compressed = append(compressed, recent...)
+	return compressed, nil
+}
+
+// formatTranscript renders messages as "role: content" lines.
+func formatTranscript(messages []*agenkit.Message) string {
+	var b strings.Builder
+	for i, msg := range messages {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(msg.Role)
+		b.WriteString(": ")
+		b.WriteString(msg.ContentString())
+	}
+	return b.String()
+}
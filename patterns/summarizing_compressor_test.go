@@ -0,0 +1,245 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// recordingSummarizer returns numbered summaries and records its prompts.
+func recordingSummarizer(prompts *[]string) *extendedMockAgent {
+	return &extendedMockAgent{
+		name: "summarizer",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			*prompts = append(*prompts, msg.ContentString())
+			return agenkit.NewMessage("assistant", fmt.Sprintf("summary %d", len(*prompts))), nil
+		},
+	}
+}
+
+// turns builds alternating user/assistant messages.
+func turns(n int) []*agenkit.Message {
+	messages := make([]*agenkit.Message, n)
+	for i := range messages {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		messages[i] = agenkit.NewMessage(role, fmt.Sprintf("turn %d", i))
+	}
+	return messages
+}
+
+func TestNewSummarizingCompressor_Validation(t *testing.T) {
+	summarizer := &extendedMockAgent{name: "s", response: "x"}
+
+	if _, err := NewSummarizingCompressor(nil); err == nil {
+		t.Error("expected error for nil config")
+	}
+	if _, err := NewSummarizingCompressor(&SummarizingCompressorConfig{}); err == nil {
+		t.Error("expected error for missing summarizer")
+	}
+	if _, err := NewSummarizingCompressor(&SummarizingCompressorConfig{
+		Summarizer: summarizer, Threshold: 4, KeepRecent: 4,
+	}); err == nil {
+		t.Error("expected error when keepRecent >= threshold")
+	}
+
+	compressor, err := NewSummarizingCompressor(&SummarizingCompressorConfig{Summarizer: summarizer})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if compressor.threshold != 20 || compressor.keepRecent != 6 {
+		t.Errorf("unexpected defaults: threshold=%d keepRecent=%d", compressor.threshold, compressor.keepRecent)
+	}
+}
+
+func TestSummarizingCompressor_UnderThreshold(t *testing.T) {
+	var prompts []string
+	compressor, _ := NewSummarizingCompressor(&SummarizingCompressorConfig{
+		Summarizer: recordingSummarizer(&prompts), Threshold: 6, KeepRecent: 2,
+	})
+
+	history := turns(6)
+	compressed, err := compressor.Compress(context.Background(), history)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(compressed) != 6 || len(prompts) != 0 {
+		t.Errorf("expected no compression, got %d messages and %d summarizer calls", len(compressed), len(prompts))
+	}
+}
+
+func TestSummarizingCompressor_Compresses(t *testing.T) {
+	var prompts []string
+	compressor, _ := NewSummarizingCompressor(&SummarizingCompressorConfig{
+		Summarizer: recordingSummarizer(&prompts), Threshold: 6, KeepRecent: 2,
+	})
+
+	system := agenkit.NewMessage("system", "You are helpful.")
+	history := append([]*agenkit.Message{system}, turns(7)...)
+
+	compressed, err := compressor.Compress(context.Background(), history)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// system prompt, summary, 2 recent turns
+	if len(compressed) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(compressed))
+	}
+	if compressed[0] != system {
+		t.Error("expected system prompt to be kept first")
+	}
+	summary := compressed[1]
+	if !IsSummary(summary) || summary.Role != "system" || summary.ContentString() != "summary 1" {
+		t.Errorf("unexpected summary message: %+v", summary)
+	}
+	if summary.Metadata["summarized_messages"] != 5 {
+		t.Errorf("expected 5 summarized messages, got %v", summary.Metadata["summarized_messages"])
+	}
+	if compressed[2].ContentString() != "turn 5" || compressed[3].ContentString() != "turn 6" {
+		t.Errorf("expected recent turns verbatim, got %q %q", compressed[2].ContentString(), compressed[3].ContentString())
+	}
+
+	if !strings.Contains(prompts[0], "user: turn 0") || strings.Contains(prompts[0], "turn 5") {
+		t.Errorf("expected only old turns in prompt, got %q", prompts[0])
+	}
+	if strings.Contains(prompts[0], "You are helpful.") {
+		t.Error("system prompt should not be summarized")
+	}
+}
+
+func TestSummarizingCompressor_UpdatesExistingSummary(t *testing.T) {
+	var prompts []string
+	compressor, _ := NewSummarizingCompressor(&SummarizingCompressorConfig{
+		Summarizer: recordingSummarizer(&prompts), Threshold: 4, KeepRecent: 2,
+	})
+
+	history, err := compressor.Compress(context.Background(), turns(5))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The summary doesn't count as a turn, so 3 more turns are needed
+	history = append(history, turns(2)...)
+	if again, _ := compressor.Compress(context.Background(), history); len(prompts) != 1 || len(again) != len(history) {
+		t.Fatalf("expected no second compression yet, got %d calls", len(prompts))
+	}
+
+	history = append(history, turns(1)...)
+	history, err = compressor.Compress(context.Background(), history)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	summaries := 0
+	for _, msg := range history {
+		if IsSummary(msg) {
+			summaries++
+			if msg.ContentString() != "summary 2" {
+				t.Errorf("expected updated summary, got %q", msg.ContentString())
+			}
+			if msg.Metadata["summarized_messages"] != 6 {
+				t.Errorf("expected cumulative count 6, got %v", msg.Metadata["summarized_messages"])
+			}
+		}
+	}
+	if summaries != 1 {
+		t.Errorf("expected exactly one summary, got %d", summaries)
+	}
+	if !strings.Contains(prompts[1], "Summary of earlier conversation:\nsummary 1") {
+		t.Errorf("expected previous summary as context, got %q", prompts[1])
+	}
+	if strings.Contains(prompts[1], "system: summary 1") {
+		t.Error("previous summary should not be re-summarized as a turn")
+	}
+}
+
+func TestSummarizingCompressor_SummarizerError(t *testing.T) {
+	compressor, _ := NewSummarizingCompressor(&SummarizingCompressorConfig{
+		Summarizer: &extendedMockAgent{name: "s", err: errors.New("unavailable")},
+		Threshold:  2,
+		KeepRecent: 1,
+	})
+
+	if _, err := compressor.Compress(context.Background(), turns(3)); err == nil {
+		t.Fatal("expected summarizer error")
+	}
+}
+
+func TestConversationalAgent_WithCompressor(t *testing.T) {
+	var prompts []string
+	compressor, _ := NewSummarizingCompressor(&SummarizingCompressorConfig{
+		Summarizer: recordingSummarizer(&prompts), Threshold: 4, KeepRecent: 2,
+	})
+
+	client := &mockLLMClient{responses: []string{"r1", "r2", "r3"}}
+	agent, err := NewConversationalAgent(&ConversationalAgentConfig{
+		LLMClient:     client,
+		MaxHistory:    20,
+		SystemPrompt:  "Be brief.",
+		IncludeSystem: true,
+		Compressor:    compressor,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, input := range []string{"My name is Alice", "I like Go", "What's my name?"} {
+		if _, err := agent.Process(context.Background(), agenkit.NewMessage("user", input)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(prompts) != 1 {
+		t.Fatalf("expected one compression, got %d", len(prompts))
+	}
+	// LLM saw: system prompt, summary, 2 recent turns
+	if len(client.lastInput) != 4 || !IsSummary(client.lastInput[1]) {
+		t.Errorf("expected compressed context, got %d messages", len(client.lastInput))
+	}
+	if client.lastInput[len(client.lastInput)-1].ContentString() != "What's my name?" {
+		t.Error("expected latest user message last")
+	}
+}
+
+func TestWorkingMemory_Compress(t *testing.T) {
+	var prompts []string
+	compressor, _ := NewSummarizingCompressor(&SummarizingCompressorConfig{
+		Summarizer: recordingSummarizer(&prompts), Threshold: 3, KeepRecent: 1,
+	})
+
+	memory, _ := NewWorkingMemory(10)
+	ctx := context.Background()
+	for i, importance := range []float64{0.2, 0.9, 0.4, 0.1} {
+		entry := CreateMemoryEntry(fmt.Sprintf("fact %d", i), map[string]interface{}{"role": "user"}, importance, "s1")
+		if err := memory.Store(ctx, entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	last := memory.GetAll()[3]
+
+	if err := memory.Compress(ctx, compressor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := memory.GetAll()
+	if len(entries) != 2 {
+		t.Fatalf("expected summary plus 1 recent entry, got %d", len(entries))
+	}
+	summary := entries[0]
+	if summary.Metadata[SummaryMetadataKey] != true || summary.Metadata["role"] != "system" {
+		t.Errorf("unexpected summary metadata: %v", summary.Metadata)
+	}
+	if summary.Importance != 0.9 || summary.SessionID != "s1" {
+		t.Errorf("expected summary to inherit importance 0.9 and session, got %v %q", summary.Importance, summary.SessionID)
+	}
+	if entries[1] != last {
+		t.Error("expected recent entry to keep its identity")
+	}
+}
@@ -9,6 +9,8 @@
 //   - Specialist agents for domain-specific tasks
 //   - Task decomposition and delegation
 //   - Result synthesis from specialist outputs
+//   - Optional incremental synthesis and progress callbacks as each
+//     subtask completes
 //
 // Performance characteristics:
 //   - Time: O(planning + max(specialist) + synthesis)
@@ -18,22 +20,54 @@ package patterns
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
 )
 
+// ErrTooManySubtasks is returned (wrapped) when a planner's plan exceeds
+// the supervisor's subtask limit and truncation is disabled.
+var ErrTooManySubtasks = errors.New("too many subtasks")
+
 // Subtask represents a decomposed task for a specialist agent.
 type Subtask struct {
 	// Type identifies which specialist should handle this subtask
 	Type string
+	// Pool, if set, sends the subtask to every agent of the named pool
+	// (see WithPool) instead of the Type specialist; Type then only names
+	// the result key and defaults to Pool
+	Pool string
+	// Reduce combines the pool's results, overriding the pool's reducer
+	// (optional)
+	Reduce ReduceFunc
 	// Message is the input for the specialist
 	Message *agenkit.Message
 	// Metadata contains additional task information
 	Metadata map[string]interface{}
 }
 
+// ReduceFunc combines the results of a pool of interchangeable
+// specialists into one result, e.g. by averaging independent estimates.
+// Results are in pool order and exclude failed members.
+type ReduceFunc func(ctx context.Context, results []*agenkit.Message) (*agenkit.Message, error)
+
+// ReduceWith adapts an AggregatorFunc, such as DefaultAggregators.MajorityVote,
+// to a ReduceFunc.
+func ReduceWith(aggregator AggregatorFunc) ReduceFunc {
+	return func(ctx context.Context, results []*agenkit.Message) (*agenkit.Message, error) {
+		return aggregator(results), nil
+	}
+}
+
+// specialistPool is a named group of interchangeable specialists.
+type specialistPool struct {
+	agents []agenkit.Agent
+	reduce ReduceFunc
+}
+
 // PlannerAgent is responsible for task decomposition and result synthesis.
 //
 // The planner receives the initial message and breaks it down into subtasks
@@ -49,6 +83,51 @@ type PlannerAgent interface {
 	Synthesize(ctx context.Context, original *agenkit.Message, results map[string]*agenkit.Message) (*agenkit.Message, error)
 }
 
+// IncrementalSynthesizer builds the final response as specialist results
+// arrive instead of all at once.
+//
+// A synthesizer is created per request by a SynthesizerFactory, so it may
+// keep state between calls. AddResult is called once per subtask in
+// completion order, never concurrently; Finalize is called after the last
+// subtask completes.
+type IncrementalSynthesizer interface {
+	// AddResult incorporates the result of one subtask. The key is the
+	// same "<type>_<index>" key Synthesize receives.
+	AddResult(ctx context.Context, key string, result *agenkit.Message) error
+
+	// Finalize returns the final response
+	Finalize(ctx context.Context) (*agenkit.Message, error)
+}
+
+// SynthesizerFactory creates an IncrementalSynthesizer for one request.
+type SynthesizerFactory func(ctx context.Context, original *agenkit.Message) (IncrementalSynthesizer, error)
+
+// SubtaskEvent reports progress of a supervisor subtask.
+type SubtaskEvent struct {
+	// Index is the subtask's position in the plan
+	Index int
+	// Type is the specialist type handling the subtask
+	Type string
+	// Specialist is the name of the specialist agent
+	Specialist string
+	// Key is the result key passed to synthesis ("<type>_<index>")
+	Key string
+	// Status is StepStatusInProgress, StepStatusCompleted or StepStatusFailed
+	Status StepStatus
+	// Result is the specialist's response (completed subtasks only)
+	Result *agenkit.Message
+	// Err is the specialist's error (failed subtasks only)
+	Err error
+	// Completed is the number of subtasks completed so far, including this one
+	Completed int
+	// Total is the number of subtasks in the plan being executed
+	Total int
+}
+
+// SubtaskCallback receives subtask progress events. It is called
+// synchronously on the supervisor's goroutine, so it should return quickly.
+type SubtaskCallback func(ctx context.Context, event SubtaskEvent)
+
 // SupervisorAgent coordinates specialist agents through hierarchical planning.
 //
 // The supervisor uses a planner agent to decompose complex tasks into subtasks,
@@ -63,10 +142,29 @@ type PlannerAgent interface {
 //
 // The supervisor pattern is ideal when tasks have clear domain boundaries
 // and benefit from specialized expertise.
+//
+// Use WithMaxSubtasks to bound how many specialist calls a single plan can
+// trigger, guarding against a misbehaving or adversarial planner.
+//
+// For long workflows, WithSubtaskCallback reports each subtask as it starts
+// and finishes (e.g. to show "coder done, tester in progress" in a UI), and
+// WithIncrementalSynthesis folds results into the response as they arrive
+// instead of calling the planner's Synthesize at the end.
+//
+// WithPool registers a pool of interchangeable specialists, so one subtask
+// can fan out to all of them and reduce their results before synthesis,
+// e.g. "get three independent estimates and average them".
 type SupervisorAgent struct {
-	name        string
-	planner     PlannerAgent
-	specialists map[string]agenkit.Agent
+	name               string
+	planner            PlannerAgent
+	specialists        map[string]agenkit.Agent
+	maxSubtasks        int
+	truncatePlans      bool
+	synthesizerFactory SynthesizerFactory
+	onSubtask          SubtaskCallback
+	pools              map[string]*specialistPool
+
+	lazy lazyInit
 }
 
 // NewSupervisorAgent creates a new supervisor agent.
@@ -92,6 +190,69 @@ func NewSupervisorAgent(planner PlannerAgent, specialists map[string]agenkit.Age
 	}, nil
 }
 
+// WithMaxSubtasks limits the number of subtasks executed per plan (0 means
+// unlimited) and returns the supervisor for chaining.
+//
+// Oversized plans are rejected with ErrTooManySubtasks before any
+// specialist runs, unless WithTruncatePlans is enabled.
+func (s *SupervisorAgent) WithMaxSubtasks(maxSubtasks int) *SupervisorAgent {
+	if maxSubtasks < 0 {
+		maxSubtasks = 0
+	}
+	s.maxSubtasks = maxSubtasks
+	return s
+}
+
+// WithTruncatePlans sets whether oversized plans are truncated to the first
+// MaxSubtasks subtasks instead of rejected, and returns the supervisor for
+// chaining. Truncation is recorded in metadata as "supervisor_truncated"
+// and "supervisor_planned_subtasks".
+func (s *SupervisorAgent) WithTruncatePlans(truncate bool) *SupervisorAgent {
+	s.truncatePlans = truncate
+	return s
+}
+
+// WithIncrementalSynthesis replaces the planner's batch Synthesize with an
+// IncrementalSynthesizer created per request by factory, and returns the
+// supervisor for chaining. Pass nil to restore batch synthesis.
+//
+// Example:
+//
+//	supervisor.WithIncrementalSynthesis(func(ctx context.Context, original *agenkit.Message) (patterns.IncrementalSynthesizer, error) {
+//	    return newReportBuilder(original), nil
+//	})
+func (s *SupervisorAgent) WithIncrementalSynthesis(factory SynthesizerFactory) *SupervisorAgent {
+	s.synthesizerFactory = factory
+	return s
+}
+
+// WithSubtaskCallback sets a callback notified when each subtask starts,
+// completes or fails, and returns the supervisor for chaining.
+func (s *SupervisorAgent) WithSubtaskCallback(callback SubtaskCallback) *SupervisorAgent {
+	s.onSubtask = callback
+	return s
+}
+
+// WithPool registers a pool of interchangeable specialists under name, and
+// returns the supervisor for chaining. A subtask with Pool set to name is
+// sent to every agent concurrently and their results are combined by
+// reduce (or the subtask's own Reduce) into the subtask's result.
+//
+// Members that fail are skipped and recorded on the reduced result (see
+// GetPartialFailures); the subtask fails only if every member fails.
+//
+// Example:
+//
+//	supervisor.WithPool("estimator", []agenkit.Agent{a, b, c}, averageEstimates)
+//	// Plan: patterns.Subtask{Pool: "estimator", Message: msg}
+func (s *SupervisorAgent) WithPool(name string, agents []agenkit.Agent, reduce ReduceFunc) *SupervisorAgent {
+	if s.pools == nil {
+		s.pools = make(map[string]*specialistPool)
+	}
+	s.pools[name] = &specialistPool{agents: agents, reduce: reduce}
+	return s
+}
+
 // Name returns the agent's identifier.
 func (s *SupervisorAgent) Name() string {
 	return s.name
@@ -112,6 +273,13 @@ func (s *SupervisorAgent) Capabilities() []string {
 			capMap[cap] = true
 		}
 	}
+	for _, pool := range s.pools {
+		for _, agent := range pool.agents {
+			for _, cap := range agent.Capabilities() {
+				capMap[cap] = true
+			}
+		}
+	}
 
 	capabilities := make([]string, 0, len(capMap))
 	for cap := range capMap {
@@ -122,6 +290,14 @@ func (s *SupervisorAgent) Capabilities() []string {
 	return capabilities
 }
 
+// Introspect returns introspection information for the supervisor.
+func (s *SupervisorAgent) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    s.Name(),
+		Capabilities: s.Capabilities(),
+	}
+}
+
 // Process executes the supervisor pattern: plan, delegate, synthesize.
 //
 // The process follows these steps:
@@ -130,13 +306,25 @@ func (s *SupervisorAgent) Capabilities() []string {
 //  3. Execution: Specialists process their assigned subtasks
 //  4. Synthesis: Planner combines specialist results into final response
 //
-// If any subtask references an unknown specialist type, an error is returned.
-// If any specialist fails, the error is returned immediately.
+// With incremental synthesis, each result is passed to the synthesizer as
+// soon as its subtask completes and the synthesizer's Finalize produces the
+// response in step 4.
+//
+// If the plan exceeds MaxSubtasks it is rejected (or truncated) before any
+// specialist runs. If any subtask references an unknown specialist type or
+// pool, or a pool subtask has no reducer, an error is returned. If any
+// specialist (or every member of a pool) fails, the error is returned
+// immediately.
 //
 // The final message includes metadata about the planning and delegation process.
-func (s *SupervisorAgent) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	if message == nil {
-		return nil, fmt.Errorf("message cannot be nil")
+func (s *SupervisorAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := s.Init(ctx); err != nil {
+		return nil, err
 	}
 
 	// Step 1: Plan - decompose task into subtasks
@@ -147,11 +335,29 @@ func (s *SupervisorAgent) Process(ctx context.Context, message *agenkit.Message)
 
 	if len(subtasks) == 0 {
 		// No subtasks - let planner handle directly
-		return s.planner.Process(ctx, message)
+		return ProcessTraced(ctx, s.planner, message)
+	}
+
+	// Step 2: Enforce the fan-out limit before executing anything
+	plannedSubtasks := len(subtasks)
+	truncated := false
+	if s.maxSubtasks > 0 && plannedSubtasks > s.maxSubtasks {
+		if !s.truncatePlans {
+			return nil, fmt.Errorf("%w: planner returned %d subtasks (max %d)",
+				ErrTooManySubtasks, plannedSubtasks, s.maxSubtasks)
+		}
+		subtasks = subtasks[:s.maxSubtasks]
+		truncated = true
 	}
 
-	// Step 2: Validate specialist availability
+	// Step 3: Validate specialist availability
 	for i, subtask := range subtasks {
+		if subtask.Pool != "" {
+			if err := s.validatePoolSubtask(i, subtask); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		if _, ok := s.specialists[subtask.Type]; !ok {
 			availableTypes := make([]string, 0, len(s.specialists))
 			for t := range s.specialists {
@@ -162,7 +368,15 @@ func (s *SupervisorAgent) Process(ctx context.Context, message *agenkit.Message)
 		}
 	}
 
-	// Step 3: Execute subtasks with specialists
+	// Step 4: Execute subtasks with specialists
+	var synthesizer IncrementalSynthesizer
+	if s.synthesizerFactory != nil {
+		synthesizer, err = s.synthesizerFactory(ctx, message)
+		if err != nil {
+			return nil, fmt.Errorf("synthesis failed: %w", err)
+		}
+	}
+
 	results := make(map[string]*agenkit.Message)
 	executionOrder := make([]map[string]interface{}, 0, len(subtasks))
 
@@ -170,33 +384,82 @@ func (s *SupervisorAgent) Process(ctx context.Context, message *agenkit.Message)
 		// Check for context cancellation
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("supervisor cancelled at subtask %d: %w", i, ctx.Err())
+			return nil, fmt.Errorf("supervisor cancelled at subtask %d: %w", i, contextError(ctx))
 		default:
 		}
 
-		specialist := s.specialists[subtask.Type]
-
-		// Execute subtask
-		result, err := specialist.Process(ctx, subtask.Message)
+		subtaskType, specialistName := subtask.Type, ""
+		if subtask.Pool != "" {
+			if subtaskType == "" {
+				subtaskType = subtask.Pool
+			}
+			specialistName = subtask.Pool
+		} else {
+			specialistName = s.specialists[subtask.Type].Name()
+		}
+		resultKey := fmt.Sprintf("%s_%d", subtaskType, i)
+		event := SubtaskEvent{
+			Index:      i,
+			Type:       subtaskType,
+			Specialist: specialistName,
+			Key:        resultKey,
+			Status:     StepStatusInProgress,
+			Completed:  len(results),
+			Total:      len(subtasks),
+		}
+		s.notifySubtask(ctx, event)
+
+		// Execute subtask, linking it to the request and its result to it
+		subtask.Message = linkToInput(message, subtask.Message)
+		var result *agenkit.Message
+		if subtask.Pool != "" {
+			result, err = s.runPool(ctx, subtask)
+		} else {
+			result, err = ProcessTraced(ctx, s.specialists[subtask.Type], subtask.Message)
+		}
 		if err != nil {
+			event.Status = StepStatusFailed
+			event.Err = err
+			s.notifySubtask(ctx, event)
 			return nil, fmt.Errorf("specialist '%s' failed on subtask %d: %w",
-				subtask.Type, i, err)
+				subtaskType, i, err)
 		}
+		result = linkToInput(subtask.Message, result)
 
 		// Store result keyed by specialist type and index for synthesis
-		resultKey := fmt.Sprintf("%s_%d", subtask.Type, i)
 		results[resultKey] = result
 
+		event.Status = StepStatusCompleted
+		event.Result = result
+		event.Completed = len(results)
+		s.notifySubtask(ctx, event)
+
+		if synthesizer != nil {
+			if err := synthesizer.AddResult(ctx, resultKey, result); err != nil {
+				return nil, fmt.Errorf("synthesis failed on subtask %d: %w", i, err)
+			}
+		}
+
 		// Track execution order
-		executionOrder = append(executionOrder, map[string]interface{}{
+		order := map[string]interface{}{
 			"index":      i,
-			"type":       subtask.Type,
-			"specialist": specialist.Name(),
-		})
+			"type":       subtaskType,
+			"specialist": specialistName,
+		}
+		if subtask.Pool != "" {
+			order["pool"] = subtask.Pool
+			order["pool_size"] = len(s.pools[subtask.Pool].agents)
+		}
+		executionOrder = append(executionOrder, order)
 	}
 
-	// Step 4: Synthesize - combine specialist results
-	final, err := s.planner.Synthesize(ctx, message, results)
+	// Step 5: Synthesize - combine specialist results
+	var final *agenkit.Message
+	if synthesizer != nil {
+		final, err = synthesizer.Finalize(ctx)
+	} else {
+		final, err = s.planner.Synthesize(ctx, message, results)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("synthesis failed: %w", err)
 	}
@@ -208,10 +471,111 @@ func (s *SupervisorAgent) Process(ctx context.Context, message *agenkit.Message)
 	final.Metadata["supervisor_subtasks"] = len(subtasks)
 	final.Metadata["supervisor_specialists"] = len(s.specialists)
 	final.Metadata["execution_order"] = executionOrder
+	if synthesizer != nil {
+		final.Metadata["supervisor_incremental"] = true
+	}
+	if truncated {
+		final.Metadata["supervisor_truncated"] = true
+		final.Metadata["supervisor_planned_subtasks"] = plannedSubtasks
+	}
 
 	return final, nil
 }
 
+// validatePoolSubtask checks that subtask's pool exists, has members and
+// has a reducer.
+func (s *SupervisorAgent) validatePoolSubtask(index int, subtask Subtask) error {
+	pool, ok := s.pools[subtask.Pool]
+	if !ok {
+		available := make([]string, 0, len(s.pools))
+		for name := range s.pools {
+			available = append(available, name)
+		}
+		return fmt.Errorf("subtask %d references unknown specialist pool '%s' (available: %s)",
+			index, subtask.Pool, strings.Join(available, ", "))
+	}
+	if len(pool.agents) == 0 {
+		return fmt.Errorf("subtask %d references empty specialist pool '%s'", index, subtask.Pool)
+	}
+	if subtask.Reduce == nil && pool.reduce == nil {
+		return fmt.Errorf("subtask %d uses specialist pool '%s' without a reduce function", index, subtask.Pool)
+	}
+	return nil
+}
+
+// runPool sends subtask to every member of its pool concurrently and
+// reduces the successful results in pool order.
+func (s *SupervisorAgent) runPool(ctx context.Context, subtask Subtask) (*agenkit.Message, error) {
+	pool := s.pools[subtask.Pool]
+	reduce := subtask.Reduce
+	if reduce == nil {
+		reduce = pool.reduce
+	}
+
+	results := make([]*agenkit.Message, len(pool.agents))
+	errs := make([]error, len(pool.agents))
+	var wg sync.WaitGroup
+	for i, agent := range pool.agents {
+		release, err := acquireGoroutine(ctx, agent.Name())
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		wg.Add(1)
+		go func(i int, agent agenkit.Agent) {
+			defer wg.Done()
+			defer release()
+			results[i], errs[i] = ProcessTraced(ctx, agent, subtask.Message)
+			if errs[i] == nil {
+				results[i] = linkToInput(subtask.Message, results[i])
+			}
+		}(i, agent)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, contextError(ctx)
+	}
+
+	successes := make([]*agenkit.Message, 0, len(results))
+	var failures []PartialFailure
+	allFailed := &AllAgentsFailedError{}
+	for i, agent := range pool.agents {
+		if errs[i] != nil {
+			failures = append(failures, PartialFailure{AgentName: agent.Name(), Err: errs[i]})
+			allFailed.Agents = append(allFailed.Agents, agent.Name())
+			allFailed.Errors = append(allFailed.Errors, errs[i])
+			continue
+		}
+		successes = append(successes, results[i])
+	}
+	if len(successes) == 0 {
+		return nil, allFailed
+	}
+
+	reduced, err := reduce(ctx, successes)
+	if err != nil {
+		return nil, fmt.Errorf("reducing pool '%s' failed: %w", subtask.Pool, err)
+	}
+	if reduced == nil {
+		return nil, fmt.Errorf("reducing pool '%s' returned no message", subtask.Pool)
+	}
+	reduced = propagateBranches(successes, reduced)
+	reduced.Metadata["pool"] = subtask.Pool
+	reduced.Metadata["pool_results"] = len(successes)
+	if len(failures) > 0 {
+		reduced.Metadata[PartialFailuresKey] = failures
+	}
+	return reduced, nil
+}
+
+// notifySubtask reports a subtask event to the callback, if any.
+func (s *SupervisorAgent) notifySubtask(ctx context.Context, event SubtaskEvent) {
+	if s.onSubtask != nil {
+		s.onSubtask(ctx, event)
+	}
+}
+
 // SimplePlanner provides a basic planner implementation for simple use cases.
 //
 // This planner uses an LLM agent to handle both planning and synthesis.
@@ -241,7 +605,7 @@ func (p *SimplePlanner) Capabilities() []string {
 
 // Process handles direct message processing (delegates to underlying agent).
 func (p *SimplePlanner) Process(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
-	return p.agent.Process(ctx, message)
+	return ProcessTraced(ctx, p.agent, message)
 }
 
 // Plan uses the LLM to decompose tasks (simplified implementation).
@@ -265,7 +629,7 @@ func (p *SimplePlanner) Synthesize(ctx context.Context, original *agenkit.Messag
 		combined.WriteString(fmt.Sprintf("Result from %s:\n%s\n\n", key, result.ContentString()))
 	}
 
-	return agenkit.NewMessage("assistant", combined.String()), nil
+	return agenkit.NewMessage(agenkit.RoleAssistant, combined.String()), nil
 }
 
 // Introspect examines the planner's internal state.
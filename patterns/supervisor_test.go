@@ -16,6 +16,8 @@ type mockPlanner struct {
 	planErr      error
 	synthesized  string
 	synthesisErr error
+	// results are the specialist results passed to Synthesize
+	results map[string]*agenkit.Message
 }
 
 func (m *mockPlanner) Name() string {
@@ -45,6 +47,7 @@ func (m *mockPlanner) Plan(ctx context.Context, message *agenkit.Message) ([]Sub
 }
 
 func (m *mockPlanner) Synthesize(ctx context.Context, original *agenkit.Message, results map[string]*agenkit.Message) (*agenkit.Message, error) {
+	m.results = results
 	if m.synthesisErr != nil {
 		return nil, m.synthesisErr
 	}
@@ -501,3 +504,415 @@ func TestSupervisorAgent_ExecutionOrder(t *testing.T) {
 		}
 	}
 }
+
+// TestSupervisorAgent_MaxSubtasksRejects tests that oversized plans are rejected
+func TestSupervisorAgent_MaxSubtasksRejects(t *testing.T) {
+	subtasks := make([]Subtask, 5)
+	for i := range subtasks {
+		subtasks[i] = Subtask{Type: "worker", Message: agenkit.NewMessage("user", "task")}
+	}
+	planner := &mockPlanner{name: "planner", subtasks: subtasks, synthesized: "done"}
+
+	calls := 0
+	worker := &extendedMockAgent{
+		name: "worker",
+		processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			calls++
+			return agenkit.NewMessage("assistant", "ok"), nil
+		},
+	}
+
+	supervisor, err := NewSupervisorAgent(planner, map[string]agenkit.Agent{"worker": worker})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	supervisor.WithMaxSubtasks(3)
+
+	_, err = supervisor.Process(context.Background(), agenkit.NewMessage("user", "test"))
+	if !errors.Is(err, ErrTooManySubtasks) {
+		t.Fatalf("expected ErrTooManySubtasks, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "5 subtasks (max 3)") {
+		t.Errorf("expected counts in error, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no specialist calls, got %d", calls)
+	}
+}
+
+// TestSupervisorAgent_MaxSubtasksTruncates tests truncation of oversized plans
+func TestSupervisorAgent_MaxSubtasksTruncates(t *testing.T) {
+	planner := &mockPlanner{
+		name: "planner",
+		subtasks: []Subtask{
+			{Type: "first", Message: agenkit.NewMessage("user", "task1")},
+			{Type: "second", Message: agenkit.NewMessage("user", "task2")},
+			{Type: "unknown", Message: agenkit.NewMessage("user", "task3")},
+		},
+		synthesized: "done",
+	}
+
+	specialists := map[string]agenkit.Agent{
+		"first":  &extendedMockAgent{name: "f", response: "r1"},
+		"second": &extendedMockAgent{name: "s", response: "r2"},
+	}
+
+	supervisor, err := NewSupervisorAgent(planner, specialists)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	supervisor.WithMaxSubtasks(2).WithTruncatePlans(true)
+
+	// The dropped subtask's unknown type is never validated
+	result, err := supervisor.Process(context.Background(), agenkit.NewMessage("user", "test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Metadata["supervisor_subtasks"] != 2 {
+		t.Errorf("expected 2 executed subtasks, got %v", result.Metadata["supervisor_subtasks"])
+	}
+	if result.Metadata["supervisor_planned_subtasks"] != 3 {
+		t.Errorf("expected 3 planned subtasks, got %v", result.Metadata["supervisor_planned_subtasks"])
+	}
+	if result.Metadata["supervisor_truncated"] != true {
+		t.Error("expected supervisor_truncated=true")
+	}
+}
+
+// TestSupervisorAgent_MaxSubtasksWithinLimit tests that plans within the limit are untouched
+func TestSupervisorAgent_MaxSubtasksWithinLimit(t *testing.T) {
+	planner := &mockPlanner{
+		name:        "planner",
+		subtasks:    []Subtask{{Type: "worker", Message: agenkit.NewMessage("user", "task")}},
+		synthesized: "done",
+	}
+
+	supervisor, err := NewSupervisorAgent(planner, map[string]agenkit.Agent{
+		"worker": &extendedMockAgent{name: "w", response: "r"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	supervisor.WithMaxSubtasks(1)
+
+	result, err := supervisor.Process(context.Background(), agenkit.NewMessage("user", "test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := result.Metadata["supervisor_truncated"]; ok {
+		t.Error("expected no truncation metadata")
+	}
+}
+
+// runningSynthesizer appends each result as it arrives.
+type runningSynthesizer struct {
+	keys   []string
+	report strings.Builder
+	addErr error
+}
+
+func (r *runningSynthesizer) AddResult(ctx context.Context, key string, result *agenkit.Message) error {
+	if r.addErr != nil {
+		return r.addErr
+	}
+	r.keys = append(r.keys, key)
+	r.report.WriteString(key + "=" + result.ContentString() + ";")
+	return nil
+}
+
+func (r *runningSynthesizer) Finalize(ctx context.Context) (*agenkit.Message, error) {
+	return agenkit.NewMessage("assistant", r.report.String()), nil
+}
+
+// TestSupervisorAgent_IncrementalSynthesis tests results fed to the synthesizer as they complete
+func TestSupervisorAgent_IncrementalSynthesis(t *testing.T) {
+	planner := &mockPlanner{
+		name: "planner",
+		subtasks: []Subtask{
+			{Type: "coder", Message: agenkit.NewMessage("user", "write code")},
+			{Type: "tester", Message: agenkit.NewMessage("user", "write tests")},
+		},
+		synthesized: "batch result",
+	}
+	specialists := map[string]agenkit.Agent{
+		"coder":  &extendedMockAgent{name: "coder", response: "code"},
+		"tester": &extendedMockAgent{name: "tester", response: "tests"},
+	}
+
+	synthesizer := &runningSynthesizer{}
+	var original *agenkit.Message
+	supervisor, _ := NewSupervisorAgent(planner, specialists)
+	supervisor.WithIncrementalSynthesis(func(ctx context.Context, msg *agenkit.Message) (IncrementalSynthesizer, error) {
+		original = msg
+		return synthesizer, nil
+	})
+
+	msg := agenkit.NewMessage("user", "build a feature")
+	result, err := supervisor.Process(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if original != msg {
+		t.Error("expected factory to receive the original message")
+	}
+	if result.ContentString() != "coder_0=code;tester_1=tests;" {
+		t.Errorf("expected incremental result, got %q", result.ContentString())
+	}
+	if result.Metadata["supervisor_incremental"] != true {
+		t.Error("expected supervisor_incremental metadata")
+	}
+
+	// Batch synthesis is restored with nil
+	supervisor.WithIncrementalSynthesis(nil)
+	result, _ = supervisor.Process(context.Background(), msg)
+	if result.ContentString() != "batch result" {
+		t.Errorf("expected batch result, got %q", result.ContentString())
+	}
+}
+
+// TestSupervisorAgent_IncrementalSynthesisError tests AddResult errors abort the run
+func TestSupervisorAgent_IncrementalSynthesisError(t *testing.T) {
+	planner := &mockPlanner{
+		name:     "planner",
+		subtasks: []Subtask{{Type: "coder", Message: agenkit.NewMessage("user", "code")}},
+	}
+	supervisor, _ := NewSupervisorAgent(planner, map[string]agenkit.Agent{
+		"coder": &extendedMockAgent{name: "coder", response: "code"},
+	})
+	supervisor.WithIncrementalSynthesis(func(ctx context.Context, msg *agenkit.Message) (IncrementalSynthesizer, error) {
+		return &runningSynthesizer{addErr: errors.New("bad result")}, nil
+	})
+
+	_, err := supervisor.Process(context.Background(), agenkit.NewMessage("user", "test"))
+	if err == nil || !strings.Contains(err.Error(), "bad result") {
+		t.Errorf("expected synthesis error, got %v", err)
+	}
+}
+
+// TestSupervisorAgent_SubtaskCallback tests progress events for each subtask
+func TestSupervisorAgent_SubtaskCallback(t *testing.T) {
+	planner := &mockPlanner{
+		name: "planner",
+		subtasks: []Subtask{
+			{Type: "coder", Message: agenkit.NewMessage("user", "code")},
+			{Type: "tester", Message: agenkit.NewMessage("user", "tests")},
+		},
+	}
+	supervisor, _ := NewSupervisorAgent(planner, map[string]agenkit.Agent{
+		"coder":  &extendedMockAgent{name: "coder", response: "code"},
+		"tester": &extendedMockAgent{name: "tester", err: errors.New("flaky")},
+	})
+
+	var events []SubtaskEvent
+	supervisor.WithSubtaskCallback(func(ctx context.Context, event SubtaskEvent) {
+		events = append(events, event)
+	})
+
+	if _, err := supervisor.Process(context.Background(), agenkit.NewMessage("user", "test")); err == nil {
+		t.Fatal("expected specialist error")
+	}
+
+	expected := []struct {
+		key       string
+		status    StepStatus
+		completed int
+	}{
+		{"coder_0", StepStatusInProgress, 0},
+		{"coder_0", StepStatusCompleted, 1},
+		{"tester_1", StepStatusInProgress, 1},
+		{"tester_1", StepStatusFailed, 1},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(events))
+	}
+	for i, want := range expected {
+		got := events[i]
+		if got.Key != want.key || got.Status != want.status || got.Completed != want.completed || got.Total != 2 {
+			t.Errorf("event %d: got %+v, want %+v", i, got, want)
+		}
+	}
+	if events[1].Result == nil || events[1].Result.ContentString() != "code" {
+		t.Error("expected completed event to carry the result")
+	}
+	if events[3].Err == nil || events[3].Specialist != "tester" {
+		t.Errorf("expected failed event to carry the error, got %+v", events[3])
+	}
+}
+
+// joinReduce joins pool results with "+"
+func joinReduce(ctx context.Context, results []*agenkit.Message) (*agenkit.Message, error) {
+	parts := make([]string, len(results))
+	for i, r := range results {
+		parts[i] = r.ContentString()
+	}
+	return agenkit.NewMessage("assistant", strings.Join(parts, "+")), nil
+}
+
+func TestSupervisorAgent_PoolReduces(t *testing.T) {
+	planner := &mockPlanner{
+		name: "planner",
+		subtasks: []Subtask{
+			{Pool: "estimator", Message: agenkit.NewMessage("user", "estimate")},
+			{Type: "coder", Message: agenkit.NewMessage("user", "code")},
+		},
+		synthesized: "done",
+	}
+	supervisor, _ := NewSupervisorAgent(planner, map[string]agenkit.Agent{
+		"coder": &extendedMockAgent{name: "coder", response: "code"},
+	})
+	supervisor.WithPool("estimator", []agenkit.Agent{
+		&extendedMockAgent{name: "e1", response: "1"},
+		&extendedMockAgent{name: "e2", response: "2"},
+		&extendedMockAgent{name: "e3", response: "3"},
+	}, joinReduce)
+
+	var events []SubtaskEvent
+	supervisor.WithSubtaskCallback(func(ctx context.Context, event SubtaskEvent) {
+		events = append(events, event)
+	})
+
+	result, err := supervisor.Process(context.Background(), agenkit.NewMessage("user", "test"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	completed := events[1]
+	if completed.Key != "estimator_0" || completed.Specialist != "estimator" {
+		t.Errorf("unexpected pool event: %+v", completed)
+	}
+	if completed.Result.ContentString() != "1+2+3" {
+		t.Errorf("expected reduced result in pool order, got %q", completed.Result.ContentString())
+	}
+	if completed.Result.Metadata["pool"] != "estimator" || completed.Result.Metadata["pool_results"] != 3 {
+		t.Errorf("unexpected pool metadata: %v", completed.Result.Metadata)
+	}
+
+	order := result.Metadata["execution_order"].([]map[string]interface{})
+	if order[0]["pool"] != "estimator" || order[0]["pool_size"] != 3 {
+		t.Errorf("unexpected execution order entry: %v", order[0])
+	}
+	if _, ok := order[1]["pool"]; ok {
+		t.Errorf("specialist entry should not have a pool: %v", order[1])
+	}
+}
+
+func TestSupervisorAgent_PoolPartialFailure(t *testing.T) {
+	planner := &mockPlanner{
+		name: "planner",
+		subtasks: []Subtask{
+			{Pool: "estimator", Message: agenkit.NewMessage("user", "estimate")},
+		},
+	}
+	supervisor, _ := NewSupervisorAgent(planner, map[string]agenkit.Agent{
+		"coder": &extendedMockAgent{name: "coder", response: "code"},
+	})
+	supervisor.WithPool("estimator", []agenkit.Agent{
+		&extendedMockAgent{name: "e1", response: "1"},
+		&extendedMockAgent{name: "e2", err: errors.New("down")},
+	}, joinReduce)
+
+	var result *agenkit.Message
+	supervisor.WithSubtaskCallback(func(ctx context.Context, event SubtaskEvent) {
+		if event.Status == StepStatusCompleted {
+			result = event.Result
+		}
+	})
+
+	if _, err := supervisor.Process(context.Background(), agenkit.NewMessage("user", "test")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "1" {
+		t.Errorf("expected only successful results reduced, got %q", result.ContentString())
+	}
+	failures := GetPartialFailures(result)
+	if len(failures) != 1 || failures[0].AgentName != "e2" {
+		t.Errorf("expected e2 recorded as a partial failure, got %+v", failures)
+	}
+}
+
+func TestSupervisorAgent_PoolAllFail(t *testing.T) {
+	planner := &mockPlanner{
+		name:     "planner",
+		subtasks: []Subtask{{Pool: "estimator", Message: agenkit.NewMessage("user", "estimate")}},
+	}
+	supervisor, _ := NewSupervisorAgent(planner, map[string]agenkit.Agent{
+		"coder": &extendedMockAgent{name: "coder", response: "code"},
+	})
+	supervisor.WithPool("estimator", []agenkit.Agent{
+		&extendedMockAgent{name: "e1", err: errors.New("down")},
+		&extendedMockAgent{name: "e2", err: errors.New("down")},
+	}, joinReduce)
+
+	_, err := supervisor.Process(context.Background(), agenkit.NewMessage("user", "test"))
+	var allFailed *AllAgentsFailedError
+	if !errors.As(err, &allFailed) {
+		t.Fatalf("expected AllAgentsFailedError, got %v", err)
+	}
+}
+
+func TestSupervisorAgent_PoolSubtaskReduceOverrides(t *testing.T) {
+	planner := &mockPlanner{
+		name: "planner",
+		subtasks: []Subtask{{
+			Type:    "vote",
+			Pool:    "estimator",
+			Reduce:  ReduceWith(DefaultAggregators.MajorityVote),
+			Message: agenkit.NewMessage("user", "estimate"),
+		}},
+	}
+	supervisor, _ := NewSupervisorAgent(planner, map[string]agenkit.Agent{
+		"coder": &extendedMockAgent{name: "coder", response: "code"},
+	})
+	supervisor.WithPool("estimator", []agenkit.Agent{
+		&extendedMockAgent{name: "e1", response: "yes"},
+		&extendedMockAgent{name: "e2", response: "no"},
+		&extendedMockAgent{name: "e3", response: "yes"},
+	}, nil)
+
+	var event SubtaskEvent
+	supervisor.WithSubtaskCallback(func(ctx context.Context, e SubtaskEvent) {
+		if e.Status == StepStatusCompleted {
+			event = e
+		}
+	})
+
+	if _, err := supervisor.Process(context.Background(), agenkit.NewMessage("user", "test")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Key != "vote_0" {
+		t.Errorf("expected key from Type, got %q", event.Key)
+	}
+	if event.Result.ContentString() != "yes" {
+		t.Errorf("expected majority vote, got %q", event.Result.ContentString())
+	}
+}
+
+func TestSupervisorAgent_PoolValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		subtask Subtask
+		reduce  ReduceFunc
+		want    string
+	}{
+		{"unknown pool", Subtask{Pool: "missing"}, joinReduce, "unknown specialist pool"},
+		{"no reducer", Subtask{Pool: "estimator"}, nil, "without a reduce function"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pooled := &extendedMockAgent{name: "e1", response: "1"}
+			tt.subtask.Message = agenkit.NewMessage("user", "estimate")
+			planner := &mockPlanner{name: "planner", subtasks: []Subtask{tt.subtask}}
+			supervisor, _ := NewSupervisorAgent(planner, map[string]agenkit.Agent{
+				"coder": &extendedMockAgent{name: "coder", response: "code"},
+			})
+			supervisor.WithPool("estimator", []agenkit.Agent{pooled}, tt.reduce)
+
+			_, err := supervisor.Process(context.Background(), agenkit.NewMessage("user", "test"))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
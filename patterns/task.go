@@ -9,6 +9,7 @@
 //   - Timeout support
 //   - Retry logic with exponential backoff
 //   - Prevention of reuse after completion
+//   - Idempotency keys to dedupe retried work
 //
 // Example:
 //
@@ -37,7 +38,9 @@ package patterns
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/scttfrdmn/agenkit-go/agenkit"
@@ -47,8 +50,94 @@ import (
 type TaskConfig struct {
 	// Timeout for task execution (0 means no timeout)
 	Timeout time.Duration
-	// Retries is the number of retry attempts on failure (default: 0)
+	// Retries is the number of retry attempts on failure (default: 0).
+	// Timeouts and cancellations are not retried.
 	Retries int
+	// IdempotencyKey identifies the logical unit of work (optional).
+	// When set together with IdempotencyStore, a cached successful result
+	// under this key is returned instead of re-running the agent.
+	IdempotencyKey string
+	// IdempotencyStore records successful results by idempotency key.
+	// Share a store across Task instances to dedupe work between them.
+	IdempotencyStore IdempotencyStore
+}
+
+// IdempotencyStore caches successful task results by idempotency key.
+//
+// Implementations must be safe for concurrent use.
+type IdempotencyStore interface {
+	// Get returns the cached result for key, if present.
+	Get(ctx context.Context, key string) (*agenkit.Message, bool, error)
+	// Put records a successful result under key.
+	Put(ctx context.Context, key string, result *agenkit.Message) error
+}
+
+// idempotencyEntry is a cached result with its expiry time.
+type idempotencyEntry struct {
+	result    *agenkit.Message
+	expiresAt time.Time
+}
+
+// InMemoryIdempotencyStore is an in-process IdempotencyStore with TTL expiry.
+type InMemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]idempotencyEntry
+}
+
+// NewInMemoryIdempotencyStore creates an in-memory store whose entries
+// expire after ttl (0 means entries never expire).
+func NewInMemoryIdempotencyStore(ttl time.Duration) *InMemoryIdempotencyStore {
+	return &InMemoryIdempotencyStore{
+		ttl:     ttl,
+		entries: make(map[string]idempotencyEntry),
+	}
+}
+
+// Get implements IdempotencyStore.
+func (s *InMemoryIdempotencyStore) Get(ctx context.Context, key string) (*agenkit.Message, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return entry.result, true, nil
+}
+
+// Put implements IdempotencyStore.
+func (s *InMemoryIdempotencyStore) Put(ctx context.Context, key string, result *agenkit.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := idempotencyEntry{result: result}
+	if s.ttl > 0 {
+		entry.expiresAt = time.Now().Add(s.ttl)
+	}
+	s.entries[key] = entry
+	return nil
+}
+
+// Len returns the number of unexpired entries in the store.
+func (s *InMemoryIdempotencyStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	count := 0
+	for key, entry := range s.entries {
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			delete(s.entries, key)
+			continue
+		}
+		count++
+	}
+	return count
 }
 
 // Task provides one-shot agent execution with lifecycle management.
@@ -70,11 +159,16 @@ type TaskConfig struct {
 //
 // Examples: summarize_document, classify_text, extract_entities
 type Task struct {
-	agent     agenkit.Agent
-	timeout   time.Duration
-	retries   int
-	completed bool
-	result    *agenkit.Message
+	agent          agenkit.Agent
+	timeout        time.Duration
+	retries        int
+	idempotencyKey string
+	store          IdempotencyStore
+	completed      bool
+	replayed       bool
+	result         *agenkit.Message
+
+	lazy lazyInit
 }
 
 // TaskError wraps errors from task execution.
@@ -103,6 +197,12 @@ func (e *TimeoutError) Error() string {
 	return fmt.Sprintf("task timed out after %v", e.Duration)
 }
 
+// Unwrap returns ErrDeadlineExceeded, so errors.Is matches a task timeout
+// against both ErrDeadlineExceeded and context.DeadlineExceeded.
+func (e *TimeoutError) Unwrap() error {
+	return ErrDeadlineExceeded
+}
+
 // NewTask creates a new Task.
 func NewTask(agent agenkit.Agent, config *TaskConfig) *Task {
 	if config == nil {
@@ -110,11 +210,13 @@ func NewTask(agent agenkit.Agent, config *TaskConfig) *Task {
 	}
 
 	return &Task{
-		agent:     agent,
-		timeout:   config.Timeout,
-		retries:   config.Retries,
-		completed: false,
-		result:    nil,
+		agent:          agent,
+		timeout:        config.Timeout,
+		retries:        config.Retries,
+		idempotencyKey: config.IdempotencyKey,
+		store:          config.IdempotencyStore,
+		completed:      false,
+		result:         nil,
 	}
 }
 
@@ -124,6 +226,10 @@ func NewTask(agent agenkit.Agent, config *TaskConfig) *Task {
 // completes (successfully or with error), the Task is marked as completed
 // and cannot be reused.
 //
+// If an idempotency key and store are configured and the store holds a
+// successful result for the key, that result is returned without running
+// the agent.
+//
 // Returns an error if task already completed or execution fails.
 func (t *Task) Execute(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
 	if t.completed {
@@ -132,25 +238,62 @@ func (t *Task) Execute(ctx context.Context, message *agenkit.Message) (*agenkit.
 		}
 	}
 
+	useIdempotency := t.idempotencyKey != "" && t.store != nil
+	if useIdempotency {
+		cached, ok, err := t.store.Get(ctx, t.idempotencyKey)
+		if err != nil {
+			t.completed = true
+			t.Cleanup()
+			return nil, &TaskError{
+				Message: "idempotency store lookup failed",
+				Cause:   err,
+			}
+		}
+		if ok {
+			t.completed = true
+			t.replayed = true
+			t.result = cached
+			return cached, nil
+		}
+	}
+
+	if err := t.Init(ctx); err != nil {
+		t.completed = true
+		t.Cleanup()
+		return nil, &TaskError{
+			Message: "task agent initialization failed",
+			Cause:   err,
+		}
+	}
+
 	attempts := t.retries + 1 // retries=0 means 1 attempt
 	var lastError error
 
 	for attempt := 0; attempt < attempts; attempt++ {
 		// Create context with timeout if specified
-		execCtx := ctx
+		execCtx := agenkit.WithAttempt(ctx, attempt+1)
 		var cancel context.CancelFunc
 		if t.timeout > 0 {
-			execCtx, cancel = context.WithTimeout(ctx, t.timeout)
+			execCtx, cancel = context.WithTimeout(execCtx, t.timeout)
 			defer cancel()
 		}
 
 		// Execute the agent
-		result, err := t.agent.Process(execCtx, message)
+		start := time.Now()
+		result, err := ProcessTraced(execCtx, t.agent, message)
 
 		if err == nil {
 			// Success - mark completed and return
 			t.completed = true
 			t.result = result
+			if useIdempotency {
+				if err := t.store.Put(ctx, t.idempotencyKey, result); err != nil {
+					return result, &TaskError{
+						Message: "task succeeded but idempotency store write failed",
+						Cause:   err,
+					}
+				}
+			}
 			return result, nil
 		}
 
@@ -163,6 +306,21 @@ func (t *Task) Execute(ctx context.Context, message *agenkit.Message) (*agenkit.
 			return nil, &TimeoutError{Duration: t.timeout}
 		}
 
+		// A cancellation is not retried: the next attempt would be
+		// cancelled too
+		if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+			t.completed = true
+			t.Cleanup()
+			cause := contextError(ctx)
+			if cause == nil {
+				cause = err
+			}
+			return nil, &TaskError{
+				Message: fmt.Sprintf("task cancelled after %d attempts", attempt+1),
+				Cause:   cause,
+			}
+		}
+
 		// If this was the last attempt, fail
 		if attempt == attempts-1 {
 			t.completed = true
@@ -174,6 +332,7 @@ func (t *Task) Execute(ctx context.Context, message *agenkit.Message) (*agenkit.
 		}
 
 		// Otherwise, retry after exponential backoff
+		agenkit.NotifyRetry(ctx, attempt+1, err, time.Since(start))
 		backoff := time.Duration(100*(attempt+1)) * time.Millisecond
 		select {
 		case <-time.After(backoff):
@@ -184,7 +343,7 @@ func (t *Task) Execute(ctx context.Context, message *agenkit.Message) (*agenkit.
 			t.Cleanup()
 			return nil, &TaskError{
 				Message: "task cancelled during retry backoff",
-				Cause:   ctx.Err(),
+				Cause:   contextError(ctx),
 			}
 		}
 	}
@@ -221,6 +380,12 @@ func (t *Task) Completed() bool {
 	return t.completed
 }
 
+// Replayed returns whether the result was served from the idempotency store
+// instead of executing the agent.
+func (t *Task) Replayed() bool {
+	return t.replayed
+}
+
 // Result returns the result of the task (if completed successfully).
 func (t *Task) Result() *agenkit.Message {
 	return t.result
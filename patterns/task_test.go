@@ -573,3 +573,118 @@ func TestTask_ErrorTypes(t *testing.T) {
 		t.Errorf("expected TimeoutError, got %T", err2)
 	}
 }
+
+// failingIdempotencyStore always fails lookups.
+type failingIdempotencyStore struct{}
+
+func (s *failingIdempotencyStore) Get(ctx context.Context, key string) (*agenkit.Message, bool, error) {
+	return nil, false, errors.New("store unavailable")
+}
+
+func (s *failingIdempotencyStore) Put(ctx context.Context, key string, result *agenkit.Message) error {
+	return nil
+}
+
+func TestTask_IdempotencyKeyDedupesAcrossTasks(t *testing.T) {
+	agent := &mockTaskAgent{name: "email", response: "sent"}
+	store := NewInMemoryIdempotencyStore(time.Minute)
+	config := &TaskConfig{IdempotencyKey: "email-42", IdempotencyStore: store}
+
+	first := NewTask(agent, config)
+	result, err := first.Execute(context.Background(), agenkit.NewMessage("user", "send"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Replayed() {
+		t.Error("expected first execution not to be a replay")
+	}
+
+	second := NewTask(agent, config)
+	replay, err := second.Execute(context.Background(), agenkit.NewMessage("user", "send"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if agent.callCount != 1 {
+		t.Errorf("expected agent to run once, ran %d times", agent.callCount)
+	}
+	if !second.Replayed() {
+		t.Error("expected second execution to be served from the store")
+	}
+	if replay.ContentString() != result.ContentString() {
+		t.Errorf("expected cached result %q, got %q", result.ContentString(), replay.ContentString())
+	}
+}
+
+func TestTask_IdempotencyDoesNotCacheFailures(t *testing.T) {
+	store := NewInMemoryIdempotencyStore(0)
+	failing := &mockTaskAgent{name: "flaky", err: errors.New("boom")}
+
+	task := NewTask(failing, &TaskConfig{IdempotencyKey: "job", IdempotencyStore: store})
+	if _, err := task.Execute(context.Background(), agenkit.NewMessage("user", "go")); err == nil {
+		t.Fatal("expected error")
+	}
+	if store.Len() != 0 {
+		t.Errorf("expected failed result not to be cached, got %d entries", store.Len())
+	}
+
+	healthy := &mockTaskAgent{name: "healthy", response: "ok"}
+	task = NewTask(healthy, &TaskConfig{IdempotencyKey: "job", IdempotencyStore: store})
+	if _, err := task.Execute(context.Background(), agenkit.NewMessage("user", "go")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if healthy.callCount != 1 {
+		t.Errorf("expected agent to run after earlier failure, ran %d times", healthy.callCount)
+	}
+}
+
+func TestInMemoryIdempotencyStore_TTLExpiry(t *testing.T) {
+	store := NewInMemoryIdempotencyStore(10 * time.Millisecond)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "key", agenkit.NewMessage("assistant", "done")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok, _ := store.Get(ctx, "key"); !ok {
+		t.Fatal("expected entry before expiry")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok, _ := store.Get(ctx, "key"); ok {
+		t.Error("expected entry to expire after TTL")
+	}
+	if store.Len() != 0 {
+		t.Errorf("expected empty store after expiry, got %d", store.Len())
+	}
+}
+
+func TestTask_IdempotencyStoreError(t *testing.T) {
+	agent := &mockTaskAgent{name: "agent", response: "ok"}
+	task := NewTask(agent, &TaskConfig{IdempotencyKey: "key", IdempotencyStore: &failingIdempotencyStore{}})
+
+	_, err := task.Execute(context.Background(), agenkit.NewMessage("user", "go"))
+
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) {
+		t.Fatalf("expected TaskError, got %v", err)
+	}
+	if agent.callCount != 0 {
+		t.Errorf("expected agent not to run when store lookup fails, ran %d times", agent.callCount)
+	}
+}
+
+func TestTask_IdempotencyKeyWithoutStore(t *testing.T) {
+	agent := &mockTaskAgent{name: "agent", response: "ok"}
+
+	for i := 0; i < 2; i++ {
+		task := NewTask(agent, &TaskConfig{IdempotencyKey: "key"})
+		if _, err := task.Execute(context.Background(), agenkit.NewMessage("user", "go")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if agent.callCount != 2 {
+		t.Errorf("expected key without store to be ignored, agent ran %d times", agent.callCount)
+	}
+}
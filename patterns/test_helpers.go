@@ -40,5 +40,5 @@ func (m *extendedMockAgent) Process(ctx context.Context, msg *agenkit.Message) (
 	if m.err != nil {
 		return nil, m.err
 	}
-	return agenkit.NewMessage("assistant", m.response), nil
+	return agenkit.NewMessage(agenkit.RoleAssistant, m.response), nil
 }
@@ -0,0 +1,183 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Timeout fallback pattern runs a primary agent under a deadline and
+// answers with a fallback agent if it times out or fails.
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ErrPrimaryTimeout is reported as the primary error when a
+// TimeoutFallbackAgent's primary doesn't respond within the timeout.
+var ErrPrimaryTimeout = errors.New("primary agent timed out")
+
+// TimeoutFallbackAgent runs a primary agent under a deadline and, if it
+// times out or fails, answers with a fallback agent instead, typically a
+// cheaper model or a cached/static response.
+//
+// The response's metadata records the path taken: "served_by" is
+// "primary" or "fallback", and fallback responses also carry
+// "fallback_reason" ("timeout" or "error"), "primary_error" and the
+// primary's FailureCauseKey.
+//
+// The fallback receives the original message and the caller's context, so
+// it isn't limited by the primary's timeout. A primary that ignores context
+// cancellation is abandoned at the deadline rather than waited for.
+type TimeoutFallbackAgent struct {
+	name     string
+	primary  agenkit.Agent
+	fallback agenkit.Agent
+	timeout  time.Duration
+
+	lazy lazyInit
+}
+
+// WithTimeoutFallback creates an agent that tries primary for at most
+// timeout (no limit if timeout <= 0) and falls back to fallback on timeout
+// or error.
+//
+// Example:
+//
+//	agent := patterns.WithTimeoutFallback(largeModel, smallModel, 2*time.Second)
+//	result, err := agent.Process(ctx, message)
+//	if result.Metadata["served_by"] == "fallback" {
+//	    log.Printf("degraded response: %v", result.Metadata["primary_error"])
+//	}
+func WithTimeoutFallback(primary agenkit.Agent, fallback agenkit.Agent, timeout time.Duration) *TimeoutFallbackAgent {
+	return &TimeoutFallbackAgent{
+		name:     fmt.Sprintf("%s+TimeoutFallback", primary.Name()),
+		primary:  primary,
+		fallback: fallback,
+		timeout:  timeout,
+	}
+}
+
+// Name returns the agent's identifier.
+func (t *TimeoutFallbackAgent) Name() string {
+	return t.name
+}
+
+// Capabilities returns the primary agent's capabilities plus fallback.
+func (t *TimeoutFallbackAgent) Capabilities() []string {
+	caps := append([]string{}, t.primary.Capabilities()...)
+	return append(caps, "fallback", "timeout")
+}
+
+// Introspect returns introspection information for the agent.
+func (t *TimeoutFallbackAgent) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    t.Name(),
+		Capabilities: t.Capabilities(),
+	}
+}
+
+// Process runs the primary within the timeout, falling back on timeout or
+// error. If the caller's context is cancelled, its error is returned
+// without trying the fallback.
+func (t *TimeoutFallbackAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+	if err := t.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := t.runPrimary(ctx, message)
+	if err == nil {
+		return tagServedBy(result, "primary"), nil
+	}
+	if ctx.Err() != nil {
+		return nil, contextError(ctx)
+	}
+
+	reason := "error"
+	if errors.Is(err, ErrPrimaryTimeout) {
+		reason = "timeout"
+	}
+	Logger().WarnContext(ctx, LogEventFallback,
+		slog.String("pattern", t.name), slog.String("agent", t.primary.Name()),
+		slog.String("next_agent", t.fallback.Name()), slog.String("reason", reason), slog.Any("error", err))
+
+	fallbackResult, fallbackErr := ProcessTraced(ctx, t.fallback, message)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("primary agent failed: %w; fallback failed: %v", err, fallbackErr)
+	}
+	if fallbackResult == nil {
+		return nil, fmt.Errorf("primary agent failed: %w; fallback returned no response", err)
+	}
+
+	tagServedBy(fallbackResult, "fallback")
+	fallbackResult.Metadata["fallback_reason"] = reason
+	fallbackResult.Metadata["primary_error"] = err.Error()
+	if reason == "timeout" {
+		fallbackResult.Metadata[FailureCauseKey] = FailureCauseDeadlineExceeded
+	} else {
+		fallbackResult.Metadata[FailureCauseKey] = FailureCause(err)
+	}
+	return fallbackResult, nil
+}
+
+// runPrimary calls the primary under the timeout, returning
+// ErrPrimaryTimeout if the deadline passes first.
+func (t *TimeoutFallbackAgent) runPrimary(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+	if t.timeout <= 0 {
+		return t.checked(ProcessTraced(ctx, t.primary, message))
+	}
+
+	primaryCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	type outcome struct {
+		result *agenkit.Message
+		err    error
+	}
+	release, err := acquireGoroutine(ctx, t.primary.Name())
+	if err != nil {
+		return nil, err
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer release()
+		result, err := ProcessTraced(primaryCtx, t.primary, message)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		if o.err != nil && errors.Is(primaryCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			return nil, fmt.Errorf("%w after %v", ErrPrimaryTimeout, t.timeout)
+		}
+		return t.checked(o.result, o.err)
+	case <-primaryCtx.Done():
+		if ctx.Err() != nil {
+			return nil, contextError(ctx)
+		}
+		return nil, fmt.Errorf("%w after %v", ErrPrimaryTimeout, t.timeout)
+	}
+}
+
+// checked treats a nil primary response as an error.
+func (t *TimeoutFallbackAgent) checked(result *agenkit.Message, err error) (*agenkit.Message, error) {
+	if err == nil && result == nil {
+		return nil, fmt.Errorf("primary agent returned no response")
+	}
+	return result, err
+}
+
+// tagServedBy records which path produced message.
+func tagServedBy(message *agenkit.Message, path string) *agenkit.Message {
+	if message.Metadata == nil {
+		message.Metadata = make(map[string]interface{})
+	}
+	message.Metadata["served_by"] = path
+	return message
+}
@@ -0,0 +1,103 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestTimeoutFallback_PrimaryServes(t *testing.T) {
+	primary := &extendedMockAgent{name: "primary", response: "full answer"}
+	fallback := &extendedMockAgent{name: "fallback", response: "cheap answer"}
+
+	agent := WithTimeoutFallback(primary, fallback, time.Second)
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "q"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "full answer" || result.Metadata["served_by"] != "primary" {
+		t.Errorf("expected primary response, got %q %v", result.ContentString(), result.Metadata)
+	}
+	if agent.Name() != "primary+TimeoutFallback" {
+		t.Errorf("unexpected name %s", agent.Name())
+	}
+}
+
+func TestTimeoutFallback_Timeout(t *testing.T) {
+	// The primary ignores cancellation; the fallback must still answer on time
+	release := make(chan struct{})
+	defer close(release)
+	primary := &extendedMockAgent{name: "slow", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		<-release
+		return agenkit.NewMessage("assistant", "too late"), nil
+	}}
+	var fallbackInput string
+	fallback := &extendedMockAgent{name: "fallback", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		fallbackInput = msg.ContentString()
+		return agenkit.NewMessage("assistant", "cheap answer"), nil
+	}}
+
+	agent := WithTimeoutFallback(primary, fallback, 20*time.Millisecond)
+	start := time.Now()
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "original"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected fallback at the deadline, took %v", elapsed)
+	}
+	if fallbackInput != "original" {
+		t.Errorf("expected fallback to get the original message, got %q", fallbackInput)
+	}
+	if result.Metadata["served_by"] != "fallback" || result.Metadata["fallback_reason"] != "timeout" {
+		t.Errorf("unexpected metadata: %v", result.Metadata)
+	}
+	if !strings.Contains(result.Metadata["primary_error"].(string), "timed out") {
+		t.Errorf("expected timeout error recorded, got %v", result.Metadata["primary_error"])
+	}
+}
+
+func TestTimeoutFallback_Error(t *testing.T) {
+	primary := &extendedMockAgent{name: "primary", err: errors.New("rate limited")}
+	fallback := &extendedMockAgent{name: "fallback", response: "cheap answer"}
+
+	result, err := WithTimeoutFallback(primary, fallback, time.Second).Process(context.Background(), agenkit.NewMessage("user", "q"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Metadata["fallback_reason"] != "error" || result.Metadata["primary_error"] != "rate limited" {
+		t.Errorf("unexpected metadata: %v", result.Metadata)
+	}
+
+	failing := &extendedMockAgent{name: "fallback", err: errors.New("also down")}
+	_, err = WithTimeoutFallback(primary, failing, time.Second).Process(context.Background(), agenkit.NewMessage("user", "q"))
+	if err == nil || !strings.Contains(err.Error(), "rate limited") || !strings.Contains(err.Error(), "also down") {
+		t.Errorf("expected both errors, got %v", err)
+	}
+}
+
+func TestTimeoutFallback_CallerCancellation(t *testing.T) {
+	primary := &extendedMockAgent{name: "primary", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+	fallbackCalled := false
+	fallback := &extendedMockAgent{name: "fallback", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		fallbackCalled = true
+		return agenkit.NewMessage("assistant", "x"), nil
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := WithTimeoutFallback(primary, fallback, time.Second).Process(ctx, agenkit.NewMessage("user", "q"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected caller's deadline error, got %v", err)
+	}
+	if fallbackCalled {
+		t.Error("expected no fallback after caller cancellation")
+	}
+}
@@ -0,0 +1,61 @@
+package patterns
+
+import "fmt"
+
+// toolCallBudget counts tool invocations during one reasoning session and
+// enforces optional per-tool caps (ToolBudgets in ReActConfig and
+// ReasoningWithToolsConfig). Tools without an entry are unlimited.
+type toolCallBudget struct {
+	limits map[string]int
+	calls  map[string]int
+}
+
+// newToolCallBudget creates an empty budget enforcing limits.
+func newToolCallBudget(limits map[string]int) *toolCallBudget {
+	return &toolCallBudget{
+		limits: limits,
+		calls:  make(map[string]int),
+	}
+}
+
+// acquire records a call to tool and returns true, or returns false without
+// recording it if the tool's budget is exhausted.
+func (b *toolCallBudget) acquire(tool string) bool {
+	if limit, ok := b.limits[tool]; ok && b.calls[tool] >= limit {
+		return false
+	}
+	b.calls[tool]++
+	return true
+}
+
+// exhaustedMessage describes an exhausted budget for the model, so it can
+// reason around the tool instead of retrying it.
+func (b *toolCallBudget) exhaustedMessage(tool string) string {
+	limit := b.limits[tool]
+	if limit < 0 {
+		limit = 0
+	}
+	return fmt.Sprintf("Tool '%s' budget exhausted (%d calls allowed). Do not call it again.", tool, limit)
+}
+
+// counts returns a copy of the per-tool call counts.
+func (b *toolCallBudget) counts() map[string]int {
+	counts := make(map[string]int, len(b.calls))
+	for tool, n := range b.calls {
+		counts[tool] = n
+	}
+	return counts
+}
+
+// copyToolBudgets copies config budgets so later changes to the caller's
+// map don't affect the agent.
+func copyToolBudgets(budgets map[string]int) map[string]int {
+	if len(budgets) == 0 {
+		return nil
+	}
+	copied := make(map[string]int, len(budgets))
+	for tool, limit := range budgets {
+		copied[tool] = limit
+	}
+	return copied
+}
@@ -0,0 +1,193 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ToolRetryPolicy configures how a tool-using agent (ToolRetry and
+// ToolRetryPolicies in ReActConfig and ReasoningWithToolsConfig) retries a
+// failed tool execution before reporting the failure to its reasoning
+// loop.
+//
+// Errors returned by Execute are retried if ShouldRetry allows. A ToolResult
+// with Success false is the tool's own verdict on the call and is reported
+// as is, unless the tool marks it transient (agenkit.ToolResult.Retryable,
+// as HTTPTool does for network errors and 5xx responses).
+type ToolRetryPolicy struct {
+	// MaxAttempts is the total number of executions, including the first
+	// (default: 3). 1 disables retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry (default: 100ms)
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries (default: 5s)
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after each retry (default: 2.0)
+	Multiplier float64
+	// ShouldRetry decides whether an error is retried
+	// (default: IsRetryableToolError)
+	ShouldRetry func(error) bool
+}
+
+// withDefaults returns a copy of p with unset fields defaulted.
+func (p ToolRetryPolicy) withDefaults() ToolRetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 5 * time.Second
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = 2.0
+	}
+	if p.ShouldRetry == nil {
+		p.ShouldRetry = IsRetryableToolError
+	}
+	return p
+}
+
+// PermanentToolError marks a tool error that retrying cannot fix, such as
+// invalid parameters. Tools return it (see NewPermanentToolError) so
+// retry policies report the failure immediately.
+type PermanentToolError struct {
+	// Err is the underlying error
+	Err error
+}
+
+func (e *PermanentToolError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *PermanentToolError) Unwrap() error {
+	return e.Err
+}
+
+// NewPermanentToolError wraps err as a *PermanentToolError.
+func NewPermanentToolError(err error) error {
+	return &PermanentToolError{Err: err}
+}
+
+// IsRetryableToolError reports whether a tool error is transient. All
+// errors are, except a *PermanentToolError and context cancellation or
+// deadline errors.
+func IsRetryableToolError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var permanent *PermanentToolError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// toolRetrier executes tools under their retry policies during one
+// reasoning session and counts retries per tool.
+type toolRetrier struct {
+	global  *ToolRetryPolicy
+	perTool map[string]ToolRetryPolicy
+	logger  *slog.Logger
+	retries map[string]int
+}
+
+// newToolRetrier creates a retrier using perTool policies, falling back to
+// global (nil = no retries) for other tools.
+func newToolRetrier(global *ToolRetryPolicy, perTool map[string]ToolRetryPolicy, logger *slog.Logger) *toolRetrier {
+	return &toolRetrier{
+		global:  global,
+		perTool: perTool,
+		logger:  logger,
+		retries: make(map[string]int),
+	}
+}
+
+// enabled reports whether any retry policy is configured.
+func (t *toolRetrier) enabled() bool {
+	return t.global != nil || len(t.perTool) > 0
+}
+
+// policy returns the policy for tool, if any.
+func (t *toolRetrier) policy(tool string) (ToolRetryPolicy, bool) {
+	if policy, ok := t.perTool[tool]; ok {
+		return policy.withDefaults(), true
+	}
+	if t.global != nil {
+		return t.global.withDefaults(), true
+	}
+	return ToolRetryPolicy{}, false
+}
+
+// execute runs tool with params, retrying errors its policy allows and
+// failed results marked retryable. It
+// returns the last result and error, and the number of retries made.
+// Each attempt's context carries its attempt number (agenkit.WithAttempt)
+// and every retry is reported through agenkit.NotifyRetry. If ctx is done
+// during a backoff, the last error is returned.
+func (t *toolRetrier) execute(ctx context.Context, tool agenkit.Tool, params map[string]interface{}) (*agenkit.ToolResult, int, error) {
+	policy, ok := t.policy(tool.Name())
+	if !ok {
+		result, err := tool.Execute(ctx, params)
+		return result, 0, err
+	}
+
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		result, err := tool.Execute(agenkit.WithAttempt(ctx, attempt), params)
+		cause := err
+		if err == nil && result.Retryable() {
+			cause = errors.New(result.Error)
+		} else if err == nil || !policy.ShouldRetry(err) {
+			return result, attempt - 1, err
+		}
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return result, attempt - 1, err
+		}
+
+		agenkit.NotifyRetry(ctx, attempt, cause, time.Since(start))
+		resolveLogger(t.logger).WarnContext(ctx, LogEventRetry, slog.String("tool", tool.Name()),
+			slog.Int("attempt", attempt), slog.Duration("backoff", backoff), slog.Any("error", cause))
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return result, attempt - 1, err
+		}
+		t.retries[tool.Name()]++
+
+		backoff = time.Duration(float64(backoff) * policy.Multiplier)
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// counts returns a copy of the per-tool retry counts.
+func (t *toolRetrier) counts() map[string]int {
+	counts := make(map[string]int, len(t.retries))
+	for tool, n := range t.retries {
+		counts[tool] = n
+	}
+	return counts
+}
+
+// copyToolRetryPolicies copies config policies so later changes to the
+// caller's map don't affect the agent.
+func copyToolRetryPolicies(policies map[string]ToolRetryPolicy) map[string]ToolRetryPolicy {
+	if len(policies) == 0 {
+		return nil
+	}
+	copied := make(map[string]ToolRetryPolicy, len(policies))
+	for tool, policy := range policies {
+		copied[tool] = policy
+	}
+	return copied
+}
@@ -0,0 +1,196 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// flakyTool fails its first failures calls with err, then succeeds.
+type flakyTool struct {
+	name     string
+	failures int
+	err      error
+	calls    int
+	attempts []int
+}
+
+func (f *flakyTool) Name() string        { return f.name }
+func (f *flakyTool) Description() string { return "flaky tool" }
+
+func (f *flakyTool) Execute(ctx context.Context, params map[string]any) (*agenkit.ToolResult, error) {
+	f.calls++
+	attempt, _ := agenkit.AttemptFromContext(ctx)
+	f.attempts = append(f.attempts, attempt)
+	if f.calls <= f.failures {
+		return nil, f.err
+	}
+	return agenkit.NewToolResult("ok"), nil
+}
+
+func fastRetry(attempts int) *ToolRetryPolicy {
+	return &ToolRetryPolicy{MaxAttempts: attempts, InitialBackoff: time.Millisecond}
+}
+
+func TestIsRetryableToolError(t *testing.T) {
+	if !IsRetryableToolError(errors.New("connection reset")) {
+		t.Error("expected plain error to be retryable")
+	}
+	if IsRetryableToolError(NewPermanentToolError(errors.New("bad parameters"))) {
+		t.Error("expected permanent error not to be retryable")
+	}
+	if IsRetryableToolError(context.Canceled) {
+		t.Error("expected cancellation not to be retryable")
+	}
+}
+
+func TestToolRetrier_RetriesTransientErrors(t *testing.T) {
+	tool := &flakyTool{name: "search", failures: 2, err: errors.New("timeout")}
+	var observed []int
+	ctx := agenkit.WithRetryObserver(context.Background(), func(attempt int, err error, latency time.Duration) {
+		observed = append(observed, attempt)
+	})
+
+	retrier := newToolRetrier(fastRetry(3), nil, nil)
+	result, retries, err := retrier.execute(ctx, tool, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success || retries != 2 || tool.calls != 3 {
+		t.Errorf("expected success after 2 retries, got retries=%d calls=%d", retries, tool.calls)
+	}
+	if len(tool.attempts) != 3 || tool.attempts[2] != 3 {
+		t.Errorf("expected attempts 1..3 in context, got %v", tool.attempts)
+	}
+	if len(observed) != 2 {
+		t.Errorf("expected 2 retry notifications, got %v", observed)
+	}
+	if retrier.counts()["search"] != 2 {
+		t.Errorf("expected 2 retries counted, got %v", retrier.counts())
+	}
+}
+
+func TestToolRetrier_PermanentErrorsAndPerToolPolicy(t *testing.T) {
+	permanent := &flakyTool{name: "calc", failures: 5, err: NewPermanentToolError(errors.New("bad input"))}
+	retrier := newToolRetrier(fastRetry(3), nil, nil)
+	if _, retries, err := retrier.execute(context.Background(), permanent, nil); err == nil || retries != 0 {
+		t.Errorf("expected permanent error without retries, got retries=%d err=%v", retries, err)
+	}
+	if permanent.calls != 1 {
+		t.Errorf("expected 1 call, got %d", permanent.calls)
+	}
+
+	flaky := &flakyTool{name: "search", failures: 5, err: errors.New("timeout")}
+	retrier = newToolRetrier(fastRetry(3), map[string]ToolRetryPolicy{"search": {MaxAttempts: 1}}, nil)
+	if _, _, err := retrier.execute(context.Background(), flaky, nil); err == nil {
+		t.Error("expected error")
+	}
+	if flaky.calls != 1 {
+		t.Errorf("expected per-tool policy to disable retries, got %d calls", flaky.calls)
+	}
+
+	flaky = &flakyTool{name: "search", failures: 1, err: errors.New("timeout")}
+	retrier = newToolRetrier(nil, nil, nil)
+	if _, _, err := retrier.execute(context.Background(), flaky, nil); err == nil {
+		t.Error("expected no retries without a policy")
+	}
+}
+
+func TestReActAgent_ToolRetry(t *testing.T) {
+	tool := &flakyTool{name: "search", failures: 1, err: errors.New("network blip")}
+	llm := &mockReActAgent{name: "llm", responses: []string{
+		"Thought: search\nAction: search\nAction Input: go",
+		"Thought: done\nFinal Answer: found it",
+	}}
+	agent, err := NewReActAgent(&ReActConfig{
+		Agent:     llm,
+		Tools:     []agenkit.Tool{tool},
+		ToolRetry: fastRetry(2),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "find"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if GetStopReason(result) != StopCompleted {
+		t.Errorf("expected completion after retry, got %v", result.Metadata["stop_reason"])
+	}
+	if steps := agent.GetSteps(); steps[0].ToolRetries != 1 || steps[0].Observation != "ok" {
+		t.Errorf("unexpected first step %+v", steps[0])
+	}
+	if retries := result.Metadata["tool_retries"].(map[string]int); retries["search"] != 1 {
+		t.Errorf("expected 1 search retry, got %v", retries)
+	}
+}
+
+func TestReasoningWithTools_ToolRetry(t *testing.T) {
+	tool := &flakyTool{name: "search", failures: 2, err: errors.New("network blip")}
+	llm := &mockReasoningAgent{name: "llm", responses: []string{
+		"TOOL_CALL: search\nPARAMETERS: {}",
+		"FINAL ANSWER: done",
+	}}
+	agent := NewReasoningWithToolsAgent(llm, []agenkit.Tool{tool}, &ReasoningWithToolsConfig{
+		EnableTrace: true,
+		ToolRetry:   fastRetry(3),
+	})
+
+	result, err := agent.Process(context.Background(), agenkit.NewMessage("user", "find"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retries := result.Metadata["tool_retries"].(map[string]int); retries["search"] != 2 {
+		t.Errorf("expected 2 search retries, got %v", retries)
+	}
+	trace := result.Metadata["reasoning_trace"].(map[string]interface{})
+	found := false
+	for _, step := range trace["steps"].([]map[string]interface{}) {
+		if step["step_type"] == string(ReasoningStepToolCall) {
+			found = true
+			if step["retries"] != 2 {
+				t.Errorf("expected tool call step to record 2 retries, got %v", step["retries"])
+			}
+		}
+	}
+	if !found {
+		t.Error("expected a tool call step in the trace")
+	}
+}
+
+// retryableResultTool reports failures failed results marked retryable,
+// then succeeds.
+type retryableResultTool struct {
+	failures int
+	calls    int
+}
+
+func (r *retryableResultTool) Name() string        { return "api" }
+func (r *retryableResultTool) Description() string { return "api tool" }
+
+func (r *retryableResultTool) Execute(ctx context.Context, params map[string]any) (*agenkit.ToolResult, error) {
+	r.calls++
+	if r.calls <= r.failures {
+		return agenkit.NewToolError("HTTP 503").WithMetadata(agenkit.ToolResultRetryableKey, true), nil
+	}
+	return agenkit.NewToolResult("ok"), nil
+}
+
+func TestToolRetrier_RetriesRetryableResults(t *testing.T) {
+	tool := &retryableResultTool{failures: 1}
+	retrier := newToolRetrier(fastRetry(3), nil, nil)
+	result, retries, err := retrier.execute(context.Background(), tool, nil)
+	if err != nil || !result.Success || retries != 1 {
+		t.Errorf("expected success after 1 retry, got result=%+v retries=%d err=%v", result, retries, err)
+	}
+
+	exhausted := &retryableResultTool{failures: 5}
+	result, retries, err = retrier.execute(context.Background(), exhausted, nil)
+	if err != nil || result.Success || retries != 2 {
+		t.Errorf("expected the last failed result after 2 retries, got result=%+v retries=%d err=%v", result, retries, err)
+	}
+}
@@ -0,0 +1,142 @@
+package patterns
+
+import (
+	"sort"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// TopologyKey is the IntrospectionResult.InternalState key under which
+// composition patterns (sequential, parallel and router) describe their
+// structure.
+//
+// The topology is a map with "type" (TopologySequential, TopologyParallel
+// or TopologyRouter, as in a TopologyConfig) and "components", one map per
+// child in execution order (handlers in route order for routers). Each component holds the child's "name" and
+// "capabilities", its position ("stage" for sequential, "index" for
+// parallel, "route" for routers, whose default handler also has "default"
+// and whose classifier has "role" "classifier"), and the child's own
+// topology under TopologyKey if it is itself a composition. A caller can
+// walk it to see the whole composed sub-system.
+//
+// Example:
+//
+//	info := pipeline.Introspect()
+//	fmt.Println(info.Capabilities) // everything the pipeline can do
+//	topology := info.InternalState[patterns.TopologyKey].(map[string]interface{})
+//	for _, stage := range topology["components"].([]map[string]interface{}) {
+//	    fmt.Println(stage["stage"], stage["name"])
+//	}
+const TopologyKey = "topology"
+
+// topologyComponent is one child of a composition, with the position
+// fields recorded alongside its name and capabilities.
+type topologyComponent struct {
+	agent    agenkit.Agent
+	position map[string]interface{}
+}
+
+// introspectComposition builds the IntrospectionResult of a composition:
+// capabilities are the union of own (the pattern's Capabilities) and every
+// component's introspected capabilities, sorted, and the topology is
+// recorded under TopologyKey.
+func introspectComposition(name, kind string, own []string, components []topologyComponent) *agenkit.IntrospectionResult {
+	capabilitySet := make(map[string]struct{})
+	for _, capability := range own {
+		capabilitySet[capability] = struct{}{}
+	}
+
+	described := make([]map[string]interface{}, 0, len(components))
+	for _, component := range components {
+		info := componentIntrospection(component.agent)
+		for _, capability := range info.Capabilities {
+			capabilitySet[capability] = struct{}{}
+		}
+
+		entry := map[string]interface{}{
+			"name":         component.agent.Name(),
+			"capabilities": info.Capabilities,
+		}
+		for key, value := range component.position {
+			entry[key] = value
+		}
+		if topology, ok := info.InternalState[TopologyKey]; ok {
+			entry[TopologyKey] = topology
+		}
+		described = append(described, entry)
+	}
+
+	capabilities := make([]string, 0, len(capabilitySet))
+	for capability := range capabilitySet {
+		capabilities = append(capabilities, capability)
+	}
+	sort.Strings(capabilities)
+
+	return &agenkit.IntrospectionResult{
+		Timestamp:    time.Now().UTC(),
+		AgentName:    name,
+		Capabilities: capabilities,
+		InternalState: map[string]interface{}{
+			TopologyKey: map[string]interface{}{
+				"type":       kind,
+				"components": described,
+			},
+		},
+		Metadata: make(map[string]interface{}),
+	}
+}
+
+// componentIntrospection introspects agent, falling back to its
+// Capabilities if it returns nothing.
+func componentIntrospection(agent agenkit.Agent) *agenkit.IntrospectionResult {
+	info := agent.Introspect()
+	if info == nil {
+		info = agenkit.DefaultIntrospectionResult(agent)
+	}
+	if info.Capabilities == nil {
+		info.Capabilities = agent.Capabilities()
+	}
+	return info
+}
+
+// stageComponents describes agents as pipeline stages.
+func stageComponents(agents []agenkit.Agent) []topologyComponent {
+	components := make([]topologyComponent, len(agents))
+	for i, agent := range agents {
+		components[i] = topologyComponent{agent: agent, position: map[string]interface{}{"stage": i}}
+	}
+	return components
+}
+
+// branchComponents describes agents as parallel branches.
+func branchComponents(agents []agenkit.Agent) []topologyComponent {
+	components := make([]topologyComponent, len(agents))
+	for i, agent := range agents {
+		components[i] = topologyComponent{agent: agent, position: map[string]interface{}{"index": i}}
+	}
+	return components
+}
+
+// routeComponents describes handlers by route, in route order, followed by
+// the default handler if it is not also a route.
+func routeComponents(handlers map[string]agenkit.Agent, defaultHandler agenkit.Agent, defaultRoute string) []topologyComponent {
+	routes := make([]string, 0, len(handlers))
+	for route := range handlers {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	components := make([]topologyComponent, 0, len(routes)+1)
+	for _, route := range routes {
+		position := map[string]interface{}{"route": route}
+		if defaultRoute != "" && route == defaultRoute {
+			position["default"] = true
+		}
+		components = append(components, topologyComponent{agent: handlers[route], position: position})
+	}
+	if defaultHandler != nil {
+		components = append(components, topologyComponent{agent: defaultHandler, position: map[string]interface{}{"default": true}})
+	}
+	return components
+}
@@ -0,0 +1,114 @@
+package patterns
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func topologyOf(t *testing.T, info *agenkit.IntrospectionResult) map[string]interface{} {
+	t.Helper()
+	if err := info.Validate(); err != nil {
+		t.Fatalf("invalid introspection result: %v", err)
+	}
+	topology, ok := info.InternalState[TopologyKey].(map[string]interface{})
+	if !ok {
+		t.Fatalf("no topology in %v", info.InternalState)
+	}
+	return topology
+}
+
+func TestSequentialAgent_IntrospectAggregatesStages(t *testing.T) {
+	researcher := &extendedMockAgent{name: "researcher", capabilities: []string{"research"}}
+	writer := &extendedMockAgent{name: "writer", capabilities: []string{"writing"}}
+	pipeline, _ := NewSequentialAgent([]agenkit.Agent{researcher, writer})
+
+	info := pipeline.Introspect()
+	want := []string{"pipeline", "research", "sequential", "writing"}
+	if !reflect.DeepEqual(info.Capabilities, want) {
+		t.Errorf("capabilities = %v, want %v", info.Capabilities, want)
+	}
+
+	topology := topologyOf(t, info)
+	stages := topology["components"].([]map[string]interface{})
+	if topology["type"] != TopologySequential || len(stages) != 2 {
+		t.Fatalf("topology = %v", topology)
+	}
+	if stages[0]["name"] != "researcher" || stages[0]["stage"] != 0 || stages[1]["name"] != "writer" || stages[1]["stage"] != 1 {
+		t.Errorf("stages = %v", stages)
+	}
+}
+
+func TestParallelPattern_IntrospectNestsCompositions(t *testing.T) {
+	inner, _ := NewSequentialAgent([]agenkit.Agent{
+		&extendedMockAgent{name: "translate", capabilities: []string{"translation"}},
+		&extendedMockAgent{name: "review", capabilities: []string{"review"}},
+	})
+	parallel, _ := NewParallelPattern([]agenkit.Agent{
+		inner,
+		&extendedMockAgent{name: "summarize", capabilities: []string{"summarization"}},
+	}, DefaultAggregators.Concatenate, nil)
+
+	info := parallel.Introspect()
+	for _, capability := range []string{"translation", "review", "summarization"} {
+		found := false
+		for _, c := range info.Capabilities {
+			found = found || c == capability
+		}
+		if !found {
+			t.Errorf("capabilities %v missing %q", info.Capabilities, capability)
+		}
+	}
+
+	branches := topologyOf(t, info)["components"].([]map[string]interface{})
+	nested, ok := branches[0][TopologyKey].(map[string]interface{})
+	if !ok || nested["type"] != TopologySequential {
+		t.Fatalf("first branch should carry its pipeline topology, got %v", branches[0])
+	}
+	if _, ok := branches[1][TopologyKey]; ok {
+		t.Error("a plain agent should not report a topology")
+	}
+}
+
+func TestRouterPattern_IntrospectListsRoutes(t *testing.T) {
+	router, _ := NewRouterPattern(func(*agenkit.Message) string { return "code" }, map[string]agenkit.Agent{
+		"math": &extendedMockAgent{name: "math", capabilities: []string{"arithmetic"}},
+		"code": &extendedMockAgent{name: "code", capabilities: []string{"coding"}},
+	}, &RouterPatternConfig{DefaultHandler: &extendedMockAgent{name: "general", capabilities: []string{"chat"}}})
+
+	info := router.Introspect()
+	if !reflect.DeepEqual(info.Capabilities, []string{"arithmetic", "chat", "coding"}) {
+		t.Errorf("capabilities = %v", info.Capabilities)
+	}
+
+	topology := topologyOf(t, info)
+	routes := topology["components"].([]map[string]interface{})
+	if topology["type"] != TopologyRouter || len(routes) != 3 {
+		t.Fatalf("topology = %v", topology)
+	}
+	if routes[0]["route"] != "code" || routes[1]["route"] != "math" || routes[2]["default"] != true {
+		t.Errorf("routes = %v", routes)
+	}
+}
+
+func TestRouterAgent_IntrospectIncludesClassifier(t *testing.T) {
+	router, err := NewRouterAgent(&RouterConfig{
+		Classifier: &mockClassifier{name: "classifier"},
+		Agents: map[string]agenkit.Agent{
+			"billing": &extendedMockAgent{name: "billing", capabilities: []string{"invoices"}},
+		},
+		DefaultKey: "billing",
+	})
+	if err != nil {
+		t.Fatalf("NewRouterAgent: %v", err)
+	}
+
+	components := topologyOf(t, router.Introspect())["components"].([]map[string]interface{})
+	if len(components) != 2 || components[0]["role"] != "classifier" {
+		t.Fatalf("components = %v", components)
+	}
+	if components[1]["route"] != "billing" || components[1]["default"] != true {
+		t.Errorf("billing route = %v", components[1])
+	}
+}
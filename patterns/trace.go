@@ -0,0 +1,272 @@
+package patterns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// maxTraceContent caps the input and output text kept per trace node.
+const maxTraceContent = 4096
+
+// traceKey is the context key for the active trace scope.
+type traceKey struct{}
+
+// traceScope is the trace and the node whose children new invocations
+// become.
+type traceScope struct {
+	trace *ExecutionTrace
+	node  *TraceNode
+}
+
+// ExecutionTrace is an in-process record of every agent invocation made
+// while handling a request: which agents ran, in what order and nesting,
+// with their inputs, outputs and durations. It needs no tracing backend.
+//
+// Patterns record each sub-agent call as a node whose children are the
+// calls that sub-agent made, so nested compositions (sequential of
+// parallel, routers of routers, agents as tools) produce a full tree.
+// Safe for concurrent use by parallel patterns.
+//
+// Example:
+//
+//	ctx, trace := patterns.WithExecutionTrace(ctx)
+//	result, err := patterns.ProcessTraced(ctx, pipeline, message)
+//	fmt.Println(trace)
+//	// pipeline (12ms)
+//	// ├── researcher (8ms)
+//	// └── writer (4ms)
+type ExecutionTrace struct {
+	mu    sync.Mutex
+	roots []*TraceNode
+}
+
+// TraceNode is one agent invocation in an ExecutionTrace. Input and
+// Output hold the message text, truncated to 4KB. Notes are the decisions
+// the agent recorded with AnnotateTrace, such as a router's choice.
+type TraceNode struct {
+	Agent    string        `json:"agent"`
+	Input    string        `json:"input"`
+	Output   string        `json:"output,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Notes    []string      `json:"notes,omitempty"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration_ns"`
+	Children []*TraceNode  `json:"children,omitempty"`
+}
+
+// WithExecutionTrace returns a context that records agent invocations into
+// a new ExecutionTrace, and the trace. If ctx already carries a trace, the
+// existing trace is reused so nested calls stay in one tree.
+func WithExecutionTrace(ctx context.Context) (context.Context, *ExecutionTrace) {
+	if trace := TraceFromContext(ctx); trace != nil {
+		return ctx, trace
+	}
+	trace := &ExecutionTrace{}
+	return context.WithValue(ctx, traceKey{}, traceScope{trace: trace}), trace
+}
+
+// TraceFromContext returns the ExecutionTrace carried by ctx, or nil if
+// tracing wasn't enabled with WithExecutionTrace.
+func TraceFromContext(ctx context.Context) *ExecutionTrace {
+	scope, _ := ctx.Value(traceKey{}).(traceScope)
+	return scope.trace
+}
+
+// ProcessTraced calls agent.Process, recording the call as a node in the
+// execution trace carried by ctx. Calls the agent makes with the context
+// it receives are recorded as children of that node. Without a trace in
+// ctx it is a plain Process call. The call is also counted against the
+// ResourceGovernor carried by ctx, if any.
+//
+// Patterns use it for every sub-agent call; use it for the top-level call
+// so the root agent appears in the trace, and in custom agents that
+// delegate to others.
+func ProcessTraced(ctx context.Context, agent agenkit.Agent, message *agenkit.Message) (*agenkit.Message, error) {
+	ctx, err := enterGovernor(ctx, agent)
+	if err != nil {
+		return nil, err
+	}
+	scope, ok := ctx.Value(traceKey{}).(traceScope)
+	if !ok || scope.trace == nil {
+		return agent.Process(ctx, message)
+	}
+	result, _, err := scope.process(ctx, agent, message)
+	return result, err
+}
+
+// processTracedNode is ProcessTraced that also returns a snapshot of the
+// call's node, recording into a new trace if ctx carries none.
+func processTracedNode(ctx context.Context, agent agenkit.Agent, message *agenkit.Message) (*agenkit.Message, *TraceNode, error) {
+	ctx, err := enterGovernor(ctx, agent)
+	if err != nil {
+		return nil, nil, err
+	}
+	scope, ok := ctx.Value(traceKey{}).(traceScope)
+	if !ok || scope.trace == nil {
+		scope = traceScope{trace: &ExecutionTrace{}}
+	}
+	return scope.process(ctx, agent, message)
+}
+
+// process calls agent under a new node beneath s.node.
+func (s traceScope) process(ctx context.Context, agent agenkit.Agent, message *agenkit.Message) (*agenkit.Message, *TraceNode, error) {
+	node := s.trace.begin(s.node, agent.Name(), message)
+	start := time.Now()
+	result, err := agent.Process(context.WithValue(ctx, traceKey{}, traceScope{trace: s.trace, node: node}), message)
+	s.trace.end(node, time.Since(start), result, err)
+	return result, s.trace.snapshot(node), err
+}
+
+// AnnotateTrace records a decision, such as "classified the request as
+// billing", on the trace node of the agent whose Process received ctx.
+// Explanations (see ExplainAgent) render notes as sentences about that
+// agent, so write them as a verb phrase. Without a trace in ctx it does
+// nothing.
+//
+// Example:
+//
+//	patterns.AnnotateTrace(ctx, fmt.Sprintf("approved the refund of $%.2f", amount))
+func AnnotateTrace(ctx context.Context, note string) {
+	scope, ok := ctx.Value(traceKey{}).(traceScope)
+	if !ok || scope.trace == nil || scope.node == nil {
+		return
+	}
+	scope.trace.mu.Lock()
+	defer scope.trace.mu.Unlock()
+	scope.node.Notes = append(scope.node.Notes, note)
+}
+
+// begin adds a node for an invocation under parent (nil for a root).
+func (t *ExecutionTrace) begin(parent *TraceNode, agent string, input *agenkit.Message) *TraceNode {
+	node := &TraceNode{
+		Agent: agent,
+		Input: traceContent(input),
+		Start: time.Now(),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if parent == nil {
+		t.roots = append(t.roots, node)
+	} else {
+		parent.Children = append(parent.Children, node)
+	}
+	return node
+}
+
+// end records the outcome of node's invocation.
+func (t *ExecutionTrace) end(node *TraceNode, duration time.Duration, output *agenkit.Message, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	node.Duration = duration
+	node.Output = traceContent(output)
+	if err != nil {
+		node.Error = err.Error()
+	}
+}
+
+// Roots returns a snapshot of the top-level invocations, in start order.
+func (t *ExecutionTrace) Roots() []*TraceNode {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	roots := make([]*TraceNode, len(t.roots))
+	for i, root := range t.roots {
+		roots[i] = root.clone()
+	}
+	return roots
+}
+
+// snapshot returns a deep copy of node, taken under the trace's lock.
+func (t *ExecutionTrace) snapshot(node *TraceNode) *TraceNode {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return node.clone()
+}
+
+// Len returns the total number of recorded invocations.
+func (t *ExecutionTrace) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	count := 0
+	var walk func(nodes []*TraceNode)
+	walk = func(nodes []*TraceNode) {
+		for _, node := range nodes {
+			count++
+			walk(node.Children)
+		}
+	}
+	walk(t.roots)
+	return count
+}
+
+// MarshalJSON encodes the trace as its list of root nodes.
+func (t *ExecutionTrace) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Roots())
+}
+
+// String renders the trace as an indented tree, one invocation per line
+// with its duration and any error.
+func (t *ExecutionTrace) String() string {
+	var sb strings.Builder
+	for _, root := range t.Roots() {
+		writeTraceNode(&sb, root, "", "")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// writeTraceNode renders node and its children; prefix is this line's
+// branch and indent the continuation for its children.
+func writeTraceNode(sb *strings.Builder, node *TraceNode, prefix, indent string) {
+	sb.WriteString(prefix)
+	sb.WriteString(fmt.Sprintf("%s (%v)", node.Agent, node.Duration.Round(time.Microsecond)))
+	if node.Error != "" {
+		sb.WriteString(" error: " + node.Error)
+	}
+	sb.WriteString("\n")
+
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			writeTraceNode(sb, child, indent+"└── ", indent+"    ")
+		} else {
+			writeTraceNode(sb, child, indent+"├── ", indent+"│   ")
+		}
+	}
+}
+
+// clone deep-copies the node so snapshots don't race with recording.
+func (n *TraceNode) clone() *TraceNode {
+	c := *n
+	c.Notes = append([]string(nil), n.Notes...)
+	c.Children = make([]*TraceNode, len(n.Children))
+	for i, child := range n.Children {
+		c.Children[i] = child.clone()
+	}
+	if len(c.Children) == 0 {
+		c.Children = nil
+	}
+	return &c
+}
+
+// traceContent returns the message text for a trace node, truncated on a
+// rune boundary.
+func traceContent(message *agenkit.Message) string {
+	if message == nil {
+		return ""
+	}
+	content := message.ContentString()
+	if len(content) <= maxTraceContent {
+		return content
+	}
+	cut := maxTraceContent
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return content[:cut] + "..."
+}
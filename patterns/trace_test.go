@@ -0,0 +1,127 @@
+package patterns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func tracedPipeline(t *testing.T) agenkit.Agent {
+	t.Helper()
+	parallel, err := NewParallelPattern([]agenkit.Agent{
+		&extendedMockAgent{name: "searcher", response: "results"},
+		&extendedMockAgent{name: "retriever", response: "documents"},
+	}, func(messages []*agenkit.Message) *agenkit.Message {
+		return messages[0]
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A custom agent that delegates with ProcessTraced
+	primary := &extendedMockAgent{name: "primary", err: errors.New("unavailable")}
+	backup := &extendedMockAgent{name: "backup", response: "summary"}
+	writer := &extendedMockAgent{name: "writer", processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		if result, err := ProcessTraced(ctx, primary, msg); err == nil {
+			return result, nil
+		}
+		return ProcessTraced(ctx, backup, msg)
+	}}
+
+	sequential, err := NewSequentialPattern([]agenkit.Agent{parallel, writer}, &SequentialPatternConfig{Name: "pipeline"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return sequential
+}
+
+func TestExecutionTrace_RecordsTree(t *testing.T) {
+	ctx, trace := WithExecutionTrace(context.Background())
+	pipeline := tracedPipeline(t)
+
+	if _, err := ProcessTraced(ctx, pipeline, agenkit.NewMessage("user", "research")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if trace.Len() != 7 {
+		t.Fatalf("expected 7 invocations, got %d:\n%s", trace.Len(), trace)
+	}
+	roots := trace.Roots()
+	if len(roots) != 1 || roots[0].Agent != pipeline.Name() || roots[0].Input != "research" {
+		t.Fatalf("unexpected root: %+v", roots)
+	}
+
+	steps := roots[0].Children
+	if len(steps) != 2 || len(steps[0].Children) != 2 || len(steps[1].Children) != 2 {
+		t.Fatalf("unexpected nesting:\n%s", trace)
+	}
+	failed := steps[1].Children[0]
+	if failed.Agent != "primary" || failed.Error != "unavailable" || failed.Output != "" {
+		t.Errorf("expected failed primary call, got %+v", failed)
+	}
+	if backup := steps[1].Children[1]; backup.Agent != "backup" || backup.Output != "summary" {
+		t.Errorf("expected backup output, got %+v", backup)
+	}
+
+	lines := strings.Split(trace.String(), "\n")
+	if len(lines) != 7 || !strings.HasPrefix(lines[0], pipeline.Name()+" (") {
+		t.Fatalf("unexpected tree:\n%s", trace)
+	}
+	if !strings.HasPrefix(lines[5], "    ├── primary (") || !strings.HasSuffix(lines[5], "error: unavailable") {
+		t.Errorf("unexpected failed line %q in tree:\n%s", lines[5], trace)
+	}
+	if !strings.HasPrefix(lines[6], "    └── backup (") {
+		t.Errorf("unexpected last line %q", lines[6])
+	}
+}
+
+func TestExecutionTrace_JSON(t *testing.T) {
+	ctx, trace := WithExecutionTrace(context.Background())
+	if _, err := ProcessTraced(ctx, tracedPipeline(t), agenkit.NewMessage("user", "research")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var roots []TraceNode
+	if err := json.Unmarshal(data, &roots); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(roots) != 1 || len(roots[0].Children) != 2 || roots[0].Children[1].Children[1].Output != "summary" {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
+
+func TestExecutionTrace_Disabled(t *testing.T) {
+	ctx := context.Background()
+	if TraceFromContext(ctx) != nil {
+		t.Error("expected no trace without WithExecutionTrace")
+	}
+
+	result, err := ProcessTraced(ctx, tracedPipeline(t), agenkit.NewMessage("user", "research"))
+	if err != nil || result == nil {
+		t.Fatalf("expected plain Process call, got %v, %v", result, err)
+	}
+
+	tracedCtx, trace := WithExecutionTrace(ctx)
+	if again, reused := WithExecutionTrace(tracedCtx); again != tracedCtx || reused != trace {
+		t.Error("expected nested WithExecutionTrace to reuse the trace")
+	}
+}
+
+func TestTraceContent_Truncates(t *testing.T) {
+	long := strings.Repeat("é", maxTraceContent)
+	content := traceContent(agenkit.NewMessage("user", long))
+	if len(content) > maxTraceContent+3 || !strings.HasSuffix(content, "...") {
+		t.Errorf("expected truncated content, got %d bytes", len(content))
+	}
+	if !strings.HasPrefix(long, strings.TrimSuffix(content, "...")) {
+		t.Error("expected truncation on a rune boundary")
+	}
+}
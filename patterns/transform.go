@@ -0,0 +1,176 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Transform pattern wraps a plain function as an agent, for the
+// deterministic glue stages of a pipeline.
+package patterns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ErrNoMatch is returned by a regex-extract transform when its input
+// doesn't match the pattern.
+var ErrNoMatch = errors.New("no match")
+
+// TransformFunc maps an input message to an output message.
+type TransformFunc func(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error)
+
+// TransformAgent wraps a plain function as an agent, for the deterministic
+// glue stages of a pipeline (parsing, extraction, reformatting) that don't
+// need an LLM or a hand-written agent struct. Drop it into any Sequential,
+// Parallel or graph composition.
+//
+// Example:
+//
+//	trim, _ := patterns.NewTransformAgent("trim", func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+//	    return patterns.TransformedMessage(msg, strings.TrimSpace(msg.ContentString())), nil
+//	})
+//	pipeline, _ := patterns.NewSequentialPattern([]agenkit.Agent{trim, writer}, nil)
+type TransformAgent struct {
+	name         string
+	fn           TransformFunc
+	capabilities []string
+}
+
+// NewTransformAgent creates an agent that processes messages with fn.
+//
+// Returns an error if name is empty or fn is nil.
+func NewTransformAgent(name string, fn TransformFunc) (*TransformAgent, error) {
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("transform function is required")
+	}
+	return &TransformAgent{
+		name:         name,
+		fn:           fn,
+		capabilities: []string{"transform"},
+	}, nil
+}
+
+// WithCapabilities replaces the capabilities the agent advertises (default
+// "transform") and returns the agent for chaining.
+func (t *TransformAgent) WithCapabilities(capabilities ...string) *TransformAgent {
+	t.capabilities = capabilities
+	return t
+}
+
+// Name returns the agent name.
+func (t *TransformAgent) Name() string {
+	return t.name
+}
+
+// Capabilities returns the agent capabilities.
+func (t *TransformAgent) Capabilities() []string {
+	return t.capabilities
+}
+
+// Introspect returns introspection information.
+func (t *TransformAgent) Introspect() *agenkit.IntrospectionResult {
+	return &agenkit.IntrospectionResult{
+		AgentName:    t.Name(),
+		Capabilities: t.Capabilities(),
+	}
+}
+
+// Process applies the transform. A nil result with no error is an error,
+// so a buggy transform can't silently end a pipeline.
+func (t *TransformAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+	result, err := t.fn(ctx, message)
+	if err != nil {
+		return nil, fmt.Errorf("transform %s: %w", t.name, err)
+	}
+	if result == nil {
+		return nil, fmt.Errorf("transform %s returned no message", t.name)
+	}
+	return result, nil
+}
+
+// TransformedMessage returns a new message with the input's role and a copy
+// of its metadata, carrying content. Transforms use it so context set by
+// earlier stages flows through.
+func TransformedMessage(input *agenkit.Message, content interface{}) *agenkit.Message {
+	result := agenkit.NewMessage(input.Role, "")
+	result.Content = content
+	for key, value := range input.Metadata {
+		result.Metadata[key] = value
+	}
+	return result
+}
+
+// NewUppercaseTransform creates a transform that uppercases the message
+// content.
+func NewUppercaseTransform(name string) *TransformAgent {
+	return &TransformAgent{
+		name: name,
+		fn: func(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+			return TransformedMessage(message, strings.ToUpper(message.ContentString())), nil
+		},
+		capabilities: []string{"transform"},
+	}
+}
+
+// NewJSONParseTransform creates a transform that parses the message content
+// as JSON, replacing it with the decoded value (a map, slice, string,
+// float64, bool or nil). Content that isn't valid JSON is an error.
+func NewJSONParseTransform(name string) *TransformAgent {
+	return &TransformAgent{
+		name: name,
+		fn: func(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+			var parsed interface{}
+			if err := json.Unmarshal([]byte(message.ContentString()), &parsed); err != nil {
+				return nil, fmt.Errorf("parse JSON: %w", err)
+			}
+			return TransformedMessage(message, parsed), nil
+		},
+		capabilities: []string{"transform", "parsing"},
+	}
+}
+
+// NewRegexExtractTransform creates a transform that replaces the message
+// content with the first match of pattern, or with the first capture group
+// if the pattern has one. Input that doesn't match fails with ErrNoMatch.
+//
+// Returns an error if pattern doesn't compile.
+//
+// Example:
+//
+//	orderID, err := patterns.NewRegexExtractTransform("order-id", `order #(\d+)`)
+func NewRegexExtractTransform(name string, pattern string) (*TransformAgent, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return &TransformAgent{
+		name: name,
+		fn: func(ctx context.Context, message *agenkit.Message) (*agenkit.Message, error) {
+			match := re.FindStringSubmatch(message.ContentString())
+			if match == nil {
+				return nil, fmt.Errorf("%w for %s", ErrNoMatch, re)
+			}
+			extracted := match[0]
+			if len(match) > 1 {
+				extracted = match[1]
+			}
+			return TransformedMessage(message, extracted), nil
+		},
+		capabilities: []string{"transform", "extraction"},
+	}, nil
+}
@@ -0,0 +1,104 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestNewTransformAgent_Validation(t *testing.T) {
+	identity := func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) { return msg, nil }
+
+	if _, err := NewTransformAgent("", identity); err == nil {
+		t.Error("expected error for empty name")
+	}
+	if _, err := NewTransformAgent("identity", nil); err == nil {
+		t.Error("expected error for nil function")
+	}
+}
+
+func TestTransformAgent_InPipeline(t *testing.T) {
+	trim, err := NewTransformAgent("trim", func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		return TransformedMessage(msg, strings.TrimSpace(msg.ContentString())), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipeline, err := NewSequentialPattern([]agenkit.Agent{trim, NewUppercaseTransform("upper")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := agenkit.NewMessage("user", "  hello world  ").WithMetadata("request_id", "r1")
+	result, err := pipeline.Process(context.Background(), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ContentString() != "HELLO WORLD" {
+		t.Errorf("expected HELLO WORLD, got %q", result.ContentString())
+	}
+	if result.Role != "user" || result.Metadata["request_id"] != "r1" {
+		t.Errorf("expected role and metadata to flow through, got %s %v", result.Role, result.Metadata)
+	}
+}
+
+func TestTransformAgent_Errors(t *testing.T) {
+	failing, _ := NewTransformAgent("failing", func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		return nil, errors.New("boom")
+	})
+	if _, err := failing.Process(context.Background(), agenkit.NewMessage("user", "x")); err == nil || !strings.Contains(err.Error(), "transform failing: boom") {
+		t.Errorf("expected wrapped error, got %v", err)
+	}
+
+	empty, _ := NewTransformAgent("empty", func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+		return nil, nil
+	})
+	if _, err := empty.Process(context.Background(), agenkit.NewMessage("user", "x")); err == nil {
+		t.Error("expected error for nil result")
+	}
+}
+
+func TestJSONParseTransform(t *testing.T) {
+	parse := NewJSONParseTransform("parse")
+
+	result, err := parse.Process(context.Background(), agenkit.NewMessage("agent", `{"name": "widget", "count": 3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, ok := result.Content.(map[string]interface{})
+	if !ok || parsed["name"] != "widget" || parsed["count"] != 3.0 {
+		t.Errorf("unexpected parsed content: %#v", result.Content)
+	}
+
+	if _, err := parse.Process(context.Background(), agenkit.NewMessage("agent", "not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestRegexExtractTransform(t *testing.T) {
+	if _, err := NewRegexExtractTransform("bad", "("); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+
+	group, err := NewRegexExtractTransform("order-id", `order #(\d+)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := group.Process(context.Background(), agenkit.NewMessage("user", "Where is order #4521?"))
+	if err != nil || result.ContentString() != "4521" {
+		t.Errorf("expected capture group 4521, got %v, %v", result, err)
+	}
+
+	whole, _ := NewRegexExtractTransform("email", `\S+@\S+\.com`)
+	result, err = whole.Process(context.Background(), agenkit.NewMessage("user", "mail bob@example.com today"))
+	if err != nil || result.ContentString() != "bob@example.com" {
+		t.Errorf("expected whole match, got %v, %v", result, err)
+	}
+
+	if _, err := group.Process(context.Background(), agenkit.NewMessage("user", "no order here")); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("expected ErrNoMatch, got %v", err)
+	}
+}
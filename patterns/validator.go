@@ -0,0 +1,259 @@
+// Package patterns provides reusable agent composition patterns.
+//
+// Validator pattern checks a message against a list of rules and passes
+// it through with a validation report.
+package patterns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+// ValidationRule is a named check applied to a message.
+type ValidationRule struct {
+	// Name identifies the rule in validation reports
+	Name string
+	// Check returns a non-nil error describing why the message is invalid
+	Check func(message *agenkit.Message) error
+}
+
+// ValidationFailure records a single failed rule.
+type ValidationFailure struct {
+	// Rule is the name of the failed rule
+	Rule string
+	// Error describes the violation
+	Error string
+}
+
+// ValidationResult is the outcome of running all rules against a message.
+type ValidationResult struct {
+	// Passed is true if every rule passed
+	Passed bool
+	// Failures lists the rules that failed, in rule order
+	Failures []ValidationFailure
+	// RulesChecked is the number of rules run
+	RulesChecked int
+}
+
+// ToDict converts the result to a map for message metadata.
+func (r *ValidationResult) ToDict() map[string]interface{} {
+	failures := make([]map[string]interface{}, len(r.Failures))
+	for i, f := range r.Failures {
+		failures[i] = map[string]interface{}{
+			"rule":  f.Rule,
+			"error": f.Error,
+		}
+	}
+
+	return map[string]interface{}{
+		"passed":        r.Passed,
+		"failures":      failures,
+		"rules_checked": r.RulesChecked,
+	}
+}
+
+// ValidationError is returned by a halting ValidatorAgent when any rule fails.
+type ValidationError struct {
+	Result *ValidationResult
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Result.Failures))
+	for i, f := range e.Result.Failures {
+		parts[i] = fmt.Sprintf("%s: %s", f.Rule, f.Error)
+	}
+	return fmt.Sprintf("validation failed (%d of %d rules): %s",
+		len(e.Result.Failures), e.Result.RulesChecked, strings.Join(parts, "; "))
+}
+
+// ValidatorAgent runs validation rules against each message.
+//
+// The message is passed through unchanged, with the validation report in
+// metadata["validation"]. If halting is enabled, a failed validation
+// returns a *ValidationError instead, stopping any enclosing pipeline.
+//
+// Example:
+//
+//	validator, _ := patterns.NewValidatorAgent([]patterns.ValidationRule{
+//	    patterns.NonEmptyContentRule(),
+//	    patterns.MaxLengthRule(4000),
+//	    patterns.JSONContentRule(),
+//	})
+//	validator.WithHaltOnFailure(true)
+//
+//	pipeline, _ := patterns.NewSequentialAgent([]agenkit.Agent{validator, processor})
+type ValidatorAgent struct {
+	name          string
+	rules         []ValidationRule
+	haltOnFailure bool
+}
+
+// NewValidatorAgent creates a new validator agent.
+//
+// Parameters:
+//   - rules: Validation rules to apply in order (must have at least one)
+func NewValidatorAgent(rules []ValidationRule) (*ValidatorAgent, error) {
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("at least one validation rule is required")
+	}
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("validation rule %d has no name", i)
+		}
+		if rule.Check == nil {
+			return nil, fmt.Errorf("validation rule '%s' has no check function", rule.Name)
+		}
+	}
+
+	return &ValidatorAgent{
+		name:  "ValidatorAgent",
+		rules: rules,
+	}, nil
+}
+
+// WithHaltOnFailure sets whether a failed validation returns an error and
+// returns the agent for chaining.
+func (v *ValidatorAgent) WithHaltOnFailure(halt bool) *ValidatorAgent {
+	v.haltOnFailure = halt
+	return v
+}
+
+// Name returns the agent's identifier.
+func (v *ValidatorAgent) Name() string {
+	return v.name
+}
+
+// Capabilities returns the validator's capabilities.
+func (v *ValidatorAgent) Capabilities() []string {
+	return []string{"validation"}
+}
+
+// Introspect returns introspection information for the validator.
+func (v *ValidatorAgent) Introspect() *agenkit.IntrospectionResult {
+	names := make([]string, len(v.rules))
+	for i, rule := range v.rules {
+		names[i] = rule.Name
+	}
+
+	return &agenkit.IntrospectionResult{
+		AgentName:    v.Name(),
+		Capabilities: v.Capabilities(),
+		InternalState: map[string]interface{}{
+			"rules":           names,
+			"halt_on_failure": v.haltOnFailure,
+		},
+	}
+}
+
+// Validate runs every rule against message and returns the result.
+func (v *ValidatorAgent) Validate(message *agenkit.Message) *ValidationResult {
+	result := &ValidationResult{
+		Passed:       true,
+		Failures:     []ValidationFailure{},
+		RulesChecked: len(v.rules),
+	}
+
+	for _, rule := range v.rules {
+		if err := rule.Check(message); err != nil {
+			result.Passed = false
+			result.Failures = append(result.Failures, ValidationFailure{
+				Rule:  rule.Name,
+				Error: err.Error(),
+			})
+		}
+	}
+
+	return result
+}
+
+// Process validates the message and passes it through with the report in
+// metadata["validation"].
+func (v *ValidatorAgent) Process(ctx context.Context, message *agenkit.Message) (out *agenkit.Message, _ error) {
+	defer func() { out = linkToInput(message, out) }()
+
+	if err := validateInput(message); err != nil {
+		return nil, err
+	}
+
+	result := v.Validate(message)
+	if !result.Passed && v.haltOnFailure {
+		return nil, &ValidationError{Result: result}
+	}
+
+	// Copy so the caller's message is not mutated
+	output := &agenkit.Message{
+		Role:      message.Role,
+		Content:   message.Content,
+		Metadata:  make(map[string]interface{}, len(message.Metadata)+1),
+		Timestamp: message.Timestamp,
+	}
+	for k, val := range message.Metadata {
+		output.Metadata[k] = val
+	}
+	output.Metadata["validation"] = result.ToDict()
+
+	return output, nil
+}
+
+// NonEmptyContentRule fails if the message content is empty or whitespace.
+func NonEmptyContentRule() ValidationRule {
+	return ValidationRule{
+		Name: "non_empty_content",
+		Check: func(message *agenkit.Message) error {
+			if strings.TrimSpace(message.ContentString()) == "" {
+				return fmt.Errorf("content is empty")
+			}
+			return nil
+		},
+	}
+}
+
+// MaxLengthRule fails if the content is longer than maxChars characters.
+func MaxLengthRule(maxChars int) ValidationRule {
+	return ValidationRule{
+		Name: "max_length",
+		Check: func(message *agenkit.Message) error {
+			if length := utf8.RuneCountInString(message.ContentString()); length > maxChars {
+				return fmt.Errorf("content length %d exceeds maximum %d", length, maxChars)
+			}
+			return nil
+		},
+	}
+}
+
+// RequiredMetadataRule fails if any of keys is missing from the metadata.
+func RequiredMetadataRule(keys ...string) ValidationRule {
+	return ValidationRule{
+		Name: "required_metadata",
+		Check: func(message *agenkit.Message) error {
+			missing := make([]string, 0)
+			for _, key := range keys {
+				if _, ok := message.Metadata[key]; !ok {
+					missing = append(missing, key)
+				}
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("missing metadata keys: %s", strings.Join(missing, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// JSONContentRule fails if the content is not valid JSON.
+func JSONContentRule() ValidationRule {
+	return ValidationRule{
+		Name: "json_content",
+		Check: func(message *agenkit.Message) error {
+			if !json.Valid([]byte(message.ContentString())) {
+				return fmt.Errorf("content is not valid JSON")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,125 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestNewValidatorAgent_Validation(t *testing.T) {
+	if _, err := NewValidatorAgent(nil); err == nil {
+		t.Error("expected error for no rules")
+	}
+	if _, err := NewValidatorAgent([]ValidationRule{{Check: func(*agenkit.Message) error { return nil }}}); err == nil {
+		t.Error("expected error for unnamed rule")
+	}
+	if _, err := NewValidatorAgent([]ValidationRule{{Name: "nil"}}); err == nil {
+		t.Error("expected error for rule without check")
+	}
+}
+
+func TestValidatorAgent_PassesThroughWithReport(t *testing.T) {
+	validator, err := NewValidatorAgent([]ValidationRule{
+		NonEmptyContentRule(),
+		MaxLengthRule(5),
+		RequiredMetadataRule("user_id", "tenant"),
+		JSONContentRule(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	input := agenkit.NewMessage("user", "not json").WithMetadata("user_id", "u1")
+	result, err := validator.Process(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.ContentString() != "not json" {
+		t.Errorf("expected content to pass through, got %q", result.ContentString())
+	}
+	if _, ok := input.Metadata["validation"]; ok {
+		t.Error("expected input message not to be mutated")
+	}
+
+	report := result.Metadata["validation"].(map[string]interface{})
+	if report["passed"] != false {
+		t.Error("expected validation to fail")
+	}
+	failures := report["failures"].([]map[string]interface{})
+	if len(failures) != 3 {
+		t.Fatalf("expected 3 failures, got %d: %v", len(failures), failures)
+	}
+	for i, want := range []string{"max_length", "required_metadata", "json_content"} {
+		if failures[i]["rule"] != want {
+			t.Errorf("failure %d: expected rule %s, got %v", i, want, failures[i]["rule"])
+		}
+	}
+	if !strings.Contains(failures[1]["error"].(string), "tenant") {
+		t.Errorf("expected missing key to be named, got %v", failures[1]["error"])
+	}
+	if result.Metadata["user_id"] != "u1" {
+		t.Error("expected existing metadata to be preserved")
+	}
+}
+
+func TestValidatorAgent_HaltOnFailure(t *testing.T) {
+	validator, err := NewValidatorAgent([]ValidationRule{NonEmptyContentRule()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	validator.WithHaltOnFailure(true)
+
+	next := &extendedMockAgent{name: "next", response: "processed"}
+	pipeline, err := NewSequentialAgent([]agenkit.Agent{validator, next})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = pipeline.Process(context.Background(), agenkit.NewMessage("user", "   "))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if validationErr.Result.Failures[0].Rule != "non_empty_content" {
+		t.Errorf("unexpected failure: %+v", validationErr.Result.Failures)
+	}
+
+	result, err := pipeline.Process(context.Background(), agenkit.NewMessage("user", "hello"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ContentString() != "processed" {
+		t.Errorf("expected pipeline to continue, got %q", result.ContentString())
+	}
+}
+
+func TestValidationRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    ValidationRule
+		message *agenkit.Message
+		wantErr bool
+	}{
+		{"non-empty passes", NonEmptyContentRule(), agenkit.NewMessage("user", "x"), false},
+		{"non-empty fails", NonEmptyContentRule(), agenkit.NewMessage("user", ""), true},
+		{"max length counts runes", MaxLengthRule(3), agenkit.NewMessage("user", "日本語"), false},
+		{"max length fails", MaxLengthRule(3), agenkit.NewMessage("user", "abcd"), true},
+		{"metadata present", RequiredMetadataRule("k"), agenkit.NewMessage("user", "").WithMetadata("k", 1), false},
+		{"metadata nil map", RequiredMetadataRule("k"), &agenkit.Message{Role: "user"}, true},
+		{"json object", JSONContentRule(), agenkit.NewMessage("user", `{"a": 1}`), false},
+		{"json invalid", JSONContentRule(), agenkit.NewMessage("user", `{"a":`), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Check(tt.message)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrWorkGroupClosed is returned when work is submitted to a WorkGroup that
+// is shutting down.
+var ErrWorkGroupClosed = errors.New("work group is shutting down")
+
+// WorkReport summarizes the units of work a WorkGroup ran.
+type WorkReport struct {
+	// Completed units returned without error
+	Completed int
+	// Failed units returned an error other than cancellation
+	Failed int
+	// Canceled units were cancelled by Shutdown or their caller, or were
+	// still running when Shutdown gave up on them
+	Canceled int
+	// Rejected units were submitted after Shutdown began and never ran
+	Rejected int
+}
+
+// WorkGroup coordinates concurrent units of work so they can be drained on
+// shutdown, e.g. when a server receives SIGTERM mid-request. Share one group
+// across ParallelAgent and ParallelPattern instances with WithWorkGroup and
+// batches with checkpointing.BatchConfig.WorkGroup, then call Shutdown from
+// the signal handler.
+//
+// Like errgroup, units are started with Go; unlike errgroup, one unit's
+// error doesn't cancel the others. Safe for concurrent use.
+//
+// Example:
+//
+//	group := patterns.NewWorkGroup()
+//	ensemble.WithWorkGroup(group)
+//	// On SIGTERM:
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	report, err := group.Shutdown(ctx)
+//	log.Printf("completed %d, canceled %d", report.Completed, report.Canceled)
+type WorkGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	closed  bool
+	running int
+	drained chan struct{}
+	report  WorkReport
+	// abandoned is set once Shutdown gave up on the running units and
+	// counted them as Canceled, so they aren't counted again on return
+	abandoned bool
+}
+
+// NewWorkGroup creates an empty work group accepting work.
+func NewWorkGroup() *WorkGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WorkGroup{ctx: ctx, cancel: cancel}
+}
+
+// Go runs fn in a new goroutine. fn's context is cancelled when ctx is, or
+// when Shutdown's grace period expires.
+//
+// Returns ErrWorkGroupClosed, without running fn, once Shutdown has begun.
+func (g *WorkGroup) Go(ctx context.Context, fn func(ctx context.Context) error) error {
+	g.mu.Lock()
+	if g.closed {
+		g.report.Rejected++
+		g.mu.Unlock()
+		return ErrWorkGroupClosed
+	}
+	g.running++
+	g.mu.Unlock()
+
+	go func() {
+		workCtx, cancel := context.WithCancel(ctx)
+		stop := context.AfterFunc(g.ctx, cancel)
+		defer func() {
+			stop()
+			cancel()
+		}()
+
+		err := fn(workCtx)
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.running--
+		switch {
+		case g.abandoned:
+		case err == nil:
+			g.report.Completed++
+		case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+			g.report.Canceled++
+		default:
+			g.report.Failed++
+		}
+		if g.running == 0 && g.drained != nil {
+			close(g.drained)
+			g.drained = nil
+		}
+	}()
+	return nil
+}
+
+// Running returns the number of units currently running.
+func (g *WorkGroup) Running() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.running
+}
+
+// Shutdown stops accepting new work and waits for running units to finish
+// until ctx is done (the grace period). It then cancels the remaining
+// units and returns without waiting for them, counting them as Canceled.
+//
+// Returns the report of all units the group has seen, and ctx's error if
+// the grace period expired before the group drained.
+func (g *WorkGroup) Shutdown(ctx context.Context) (WorkReport, error) {
+	g.mu.Lock()
+	g.closed = true
+	if g.running == 0 {
+		report := g.report
+		g.mu.Unlock()
+		g.cancel()
+		return report, nil
+	}
+	if g.drained == nil {
+		g.drained = make(chan struct{})
+	}
+	drained := g.drained
+	g.mu.Unlock()
+
+	select {
+	case <-drained:
+		g.cancel()
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.report, nil
+	case <-ctx.Done():
+		g.cancel()
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if !g.abandoned {
+			g.report.Canceled += g.running
+			g.abandoned = true
+		}
+		return g.report, ctx.Err()
+	}
+}
+
+// startWork runs fn in group, or in a plain goroutine if group is nil.
+// The goroutine is counted against ctx's ResourceGovernor on behalf of
+// agent while it runs.
+func startWork(ctx context.Context, group *WorkGroup, agent string, fn func(ctx context.Context) error) error {
+	release, err := acquireGoroutine(ctx, agent)
+	if err != nil {
+		return err
+	}
+	run := func(ctx context.Context) error {
+		defer release()
+		return fn(ctx)
+	}
+
+	if group == nil {
+		go func() { _ = run(ctx) }()
+		return nil
+	}
+	if err := group.Go(ctx, run); err != nil {
+		release()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/agenkit-go/agenkit"
+)
+
+func TestWorkGroup_ShutdownDrainsRunningWork(t *testing.T) {
+	group := NewWorkGroup()
+	release := make(chan struct{})
+
+	for i := 0; i < 2; i++ {
+		if err := group.Go(context.Background(), func(ctx context.Context) error {
+			<-release
+			return nil
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := group.Go(context.Background(), func(ctx context.Context) error {
+		return errors.New("boom")
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.AfterFunc(20*time.Millisecond, func() { close(release) })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	report, err := group.Shutdown(ctx)
+	if err != nil {
+		t.Fatalf("expected clean drain, got %v", err)
+	}
+	if report.Completed != 2 || report.Failed != 1 || report.Canceled != 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if group.Running() != 0 {
+		t.Errorf("expected no running units, got %d", group.Running())
+	}
+}
+
+func TestWorkGroup_ShutdownCancelsAfterGracePeriod(t *testing.T) {
+	group := NewWorkGroup()
+	cancelled := make(chan struct{})
+	if err := group.Go(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	report, err := group.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if report.Canceled != 1 || report.Completed != 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected running unit to be cancelled")
+	}
+}
+
+func TestWorkGroup_AbandonedUnitsCountedOnce(t *testing.T) {
+	group := NewWorkGroup()
+	finish := make(chan struct{})
+	if err := group.Go(context.Background(), func(ctx context.Context) error {
+		<-finish // ignores cancellation
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := group.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	close(finish)
+	report, err := group.Shutdown(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Canceled != 1 || report.Completed != 0 {
+		t.Errorf("expected the abandoned unit counted once as canceled, got %+v", report)
+	}
+}
+
+func TestWorkGroup_RejectsWorkAfterShutdown(t *testing.T) {
+	group := NewWorkGroup()
+	if _, err := group.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ran := false
+	err := group.Go(context.Background(), func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	if !errors.Is(err, ErrWorkGroupClosed) {
+		t.Errorf("expected ErrWorkGroupClosed, got %v", err)
+	}
+	if ran {
+		t.Error("expected rejected unit not to run")
+	}
+
+	report, _ := group.Shutdown(context.Background())
+	if report.Rejected != 1 {
+		t.Errorf("expected 1 rejected unit, got %+v", report)
+	}
+}
+
+func TestParallelAgent_WorkGroupDrainsInFlightProcess(t *testing.T) {
+	group := NewWorkGroup()
+	started := make(chan struct{}, 2)
+	slow := func(name string) agenkit.Agent {
+		return &extendedMockAgent{name: name, processFunc: func(ctx context.Context, msg *agenkit.Message) (*agenkit.Message, error) {
+			started <- struct{}{}
+			time.Sleep(30 * time.Millisecond)
+			return agenkit.NewMessage("assistant", name), nil
+		}}
+	}
+	parallel, err := NewParallelAgent([]agenkit.Agent{slow("a"), slow("b")}, DefaultAggregators.Concatenate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parallel.WithWorkGroup(group)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := parallel.Process(context.Background(), agenkit.NewMessage("user", "go"))
+		done <- err
+	}()
+	<-started
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	report, err := group.Shutdown(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Completed != 2 {
+		t.Errorf("expected 2 completed units, got %+v", report)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("expected in-flight Process to finish, got %v", err)
+	}
+
+	_, err = parallel.Process(context.Background(), agenkit.NewMessage("user", "again"))
+	if !errors.Is(err, ErrWorkGroupClosed) || !errors.Is(err, ErrAllAgentsFailed) {
+		t.Errorf("expected ErrWorkGroupClosed after shutdown, got %v", err)
+	}
+}
+
+func TestParallelPattern_WorkGroupRejectsAfterShutdown(t *testing.T) {
+	group := NewWorkGroup()
+	if _, err := group.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pattern, err := NewParallelPattern(
+		[]agenkit.Agent{&extendedMockAgent{name: "a", response: "ok"}},
+		func(messages []*agenkit.Message) *agenkit.Message { return messages[0] },
+		&ParallelPatternConfig{WorkGroup: group},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = pattern.Process(context.Background(), agenkit.NewMessage("user", "go"))
+	if !errors.Is(err, ErrWorkGroupClosed) {
+		t.Errorf("expected ErrWorkGroupClosed, got %v", err)
+	}
+}
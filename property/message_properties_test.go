@@ -14,7 +14,7 @@ import (
 )
 
 // validRoles are the allowed message roles.
-var validRoles = []string{"user", "assistant", "system", "tool", "agent"}
+var validRoles = []string{"user", "assistant", "system", "tool", "developer", "agent"}
 
 // ============================================
 // Property: JSON Round-Trip Serialization
@@ -171,7 +171,7 @@ func (s *MCPServer) handleToolsCall(ctx context.Context, req jsonrpcRequest) jso
 	}
 
 	isError := !toolResult.Success
-	text := fmt.Sprintf("%v", toolResult.Data)
+	text := toolResult.DataString()
 	if isError && toolResult.Error != "" {
 		text = toolResult.Error
 	}
@@ -77,7 +77,7 @@ func (m *MockAgent) Process(_ context.Context, _ *agenkit.Message) (*agenkit.Mes
 		return nil, fmt.Errorf("mock agent %q: configured to fail", m.name)
 	}
 	resp := m.responses[idx%int64(len(m.responses))]
-	return agenkit.NewMessage("agent", resp), nil
+	return agenkit.NewMessage(agenkit.RoleAssistant, resp), nil
 }
 
 // CallCount returns the number of times Process has been called.
@@ -135,7 +135,7 @@ func (t *ToolAgent) Process(ctx context.Context, message *agenkit.Message) (*age
 	}
 
 	// Create response with tool results
-	response := agenkit.NewMessage("agent", t.formatToolResults(results))
+	response := agenkit.NewMessage(agenkit.RoleAssistant, t.formatToolResults(results))
 	response.Metadata["tool_results"] = results
 	return response, nil
 }
@@ -172,7 +172,7 @@ func (t *ToolAgent) formatToolResults(results []*agenkit.ToolResult) string {
 	sb.WriteString("Tool execution results:\n")
 	for i, result := range results {
 		if result.Success {
-			sb.WriteString(fmt.Sprintf("%d. Success: %v\n", i+1, result.Data))
+			sb.WriteString(fmt.Sprintf("%d. Success: %s\n", i+1, result.DataString()))
 		} else {
 			sb.WriteString(fmt.Sprintf("%d. Error: %s\n", i+1, result.Error))
 		}